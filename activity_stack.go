@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// resumedActivityRegex finds the resumed activity in dumpsys activity activities, e.g.
+// "mResumedActivity: ActivityRecord{5c1e2a u0 com.android.chrome/.Main t42}" or
+// "topResumedActivity=ActivityRecord{...}" on Android 10+
+var resumedActivityRegex = regexp.MustCompile(`(?:mResumedActivity|topResumedActivity|ResumedActivity)[:=]\s*ActivityRecord\{\S+ u\d+ ([\w.$]+/[\w.$]+)`)
+
+// focusedComponentRegex pulls the window name out of dumpsys window's mCurrentFocus line:
+// "mCurrentFocus=Window{1a2b3c u0 com.android.settings/com.android.settings.Settings}"
+var focusedComponentRegex = regexp.MustCompile(`mCurrentFocus=Window\{\S+ (?:u\d+ )?([^\s}]+)`)
+
+var (
+	// taskHeaderRegex matches a task in dumpsys activity activities: "* Task{8e1f8b6 #42 type=standard
+	// A=10123:com.android.settings ...}" on Android 10+, "* TaskRecord{... #42 A=com.android.settings ...}" before
+	taskHeaderRegex   = regexp.MustCompile(`^\* (?:Task|TaskRecord)\{\w+ #(\d+)(.*)`)
+	taskAffinityRegex = regexp.MustCompile(`\bA=(?:\d+:)?([\w.$]+)`)
+	taskTypeRegex     = regexp.MustCompile(`\btype=(\w+)`)
+	// taskActivityRegex matches an activity listed in a task, "* Hist #1: ActivityRecord{3a5c9f1 u0
+	// com.android.settings/.SubSettings t42}" or, on Android 12+, "* ActivityRecord{...}"
+	taskActivityRegex = regexp.MustCompile(`^\* (?:Hist\s*#\d+: )?ActivityRecord\{\w+ u\d+ ([\w.$]+/[\w.$]+) t(-?\d+)`)
+)
+
+// Activity watcher state
+var (
+	activityWatchers   = make(map[string]context.CancelFunc)
+	activityWatchersMu sync.Mutex
+)
+
+// resumedActivity returns the component of the activity in the foreground
+func (a *App) resumedActivity(deviceId string) (string, error) {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "dumpsys activity activities | grep -E 'ResumedActivity'").CombinedOutput()
+	if err != nil && len(out) == 0 {
+		return "", fmt.Errorf("failed to read activities: %w", err)
+	}
+	m := resumedActivityRegex.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", fmt.Errorf("no resumed activity")
+	}
+	return m[1], nil
+}
+
+// focusedComponent is the cheap way to follow the foreground: the focused window is usually the
+// resumed activity, but can be a dialog or system window such as "StatusBar"
+func (a *App) focusedComponent(ctx context.Context, deviceId string) (string, error) {
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "dumpsys window | grep -m1 mCurrentFocus").CombinedOutput()
+	if err != nil && len(out) == 0 {
+		return "", fmt.Errorf("failed to read focused window: %w", err)
+	}
+	m := focusedComponentRegex.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", fmt.Errorf("no focused window")
+	}
+	return m[1], nil
+}
+
+// splitComponent expands "pkg/.Class" into the package and the fully qualified class
+func splitComponent(component string) CurrentActivity {
+	pkg, class, _ := strings.Cut(component, "/")
+	if strings.HasPrefix(class, ".") {
+		class = pkg + class
+	}
+	return CurrentActivity{Component: component, Package: pkg, Activity: class}
+}
+
+// GetCurrentActivity returns the package and activity in the foreground
+func (a *App) GetCurrentActivity(deviceId string) (*CurrentActivity, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	component, err := a.resumedActivity(deviceId)
+	if err != nil {
+		// Some OEM builds trim dumpsys activity; the focused window is close enough
+		if component, err = a.focusedComponent(nil, deviceId); err != nil || !strings.Contains(component, "/") {
+			return nil, fmt.Errorf("could not determine the current activity")
+		}
+	}
+	current := splitComponent(component)
+	return &current, nil
+}
+
+// activityMatches reports whether a component is the one expected: a full component, a package,
+// or an activity class name with or without its package
+func activityMatches(current CurrentActivity, expected string) bool {
+	expected = strings.TrimSpace(expected)
+	if expected == "" {
+		return false
+	}
+	if current.Component == expected || current.Package == expected || current.Activity == expected {
+		return true
+	}
+	if full := splitComponent(expected); strings.Contains(expected, "/") {
+		return full.Activity == current.Activity
+	}
+	return strings.HasSuffix(current.Activity, "."+strings.TrimPrefix(expected, "."))
+}
+
+// parseActivityStack groups the activities of dumpsys activity activities by task, top first
+func parseActivityStack(output, resumed string) []ActivityTask {
+	var tasks []*ActivityTask
+	byID := make(map[int]*ActivityTask)
+	seen := make(map[string]bool)
+	taskFor := func(id int) *ActivityTask {
+		if t, ok := byID[id]; ok {
+			return t
+		}
+		t := &ActivityTask{ID: id, Activities: []string{}}
+		byID[id] = t
+		tasks = append(tasks, t)
+		return t
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if m := taskHeaderRegex.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			t := taskFor(id)
+			if am := taskAffinityRegex.FindStringSubmatch(m[2]); am != nil {
+				t.Affinity = am[1]
+			}
+			if tm := taskTypeRegex.FindStringSubmatch(m[2]); tm != nil {
+				t.Type = tm[1]
+			}
+			continue
+		}
+		if m := taskActivityRegex.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[2])
+			key := m[2] + " " + m[1]
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			t := taskFor(id)
+			t.Activities = append(t.Activities, m[1])
+			if m[1] == resumed {
+				t.Focused = true
+			}
+		}
+	}
+
+	result := make([]ActivityTask, 0, len(tasks))
+	for _, t := range tasks {
+		if len(t.Activities) > 0 {
+			result = append(result, *t)
+		}
+	}
+	return result
+}
+
+// GetActivityStack returns the tasks with their activities, each list top activity first
+func (a *App) GetActivityStack(deviceId string) ([]ActivityTask, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "dumpsys activity activities").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read activities: %w, %s", err, strings.TrimSpace(lastLines(string(out), 3)))
+	}
+	resumed := ""
+	if m := resumedActivityRegex.FindStringSubmatch(string(out)); m != nil {
+		resumed = m[1]
+	}
+	return parseActivityStack(string(out), resumed), nil
+}
+
+// watchFocusedComponent polls the focused window every second until ctx ends, calling onChange
+// with each new value
+func (a *App) watchFocusedComponent(ctx context.Context, deviceId string, onChange func(component string)) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	last := ""
+	for {
+		if component, err := a.focusedComponent(ctx, deviceId); err == nil && component != last {
+			last = component
+			onChange(component)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartActivityWatcher emits current-activity-changed whenever the focused activity changes
+func (a *App) StartActivityWatcher(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	activityWatchersMu.Lock()
+	defer activityWatchersMu.Unlock()
+	if _, ok := activityWatchers[deviceId]; ok {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	activityWatchers[deviceId] = cancel
+
+	go func() {
+		previous := ""
+		a.watchFocusedComponent(ctx, deviceId, func(component string) {
+			current := splitComponent(component)
+			wailsRuntime.EventsEmit(a.ctx, "current-activity-changed", map[string]interface{}{
+				"deviceId":  deviceId,
+				"component": current.Component,
+				"package":   current.Package,
+				"activity":  current.Activity,
+				"previous":  previous,
+			})
+			previous = component
+		})
+	}()
+	return nil
+}
+
+// StopActivityWatcher stops the watcher for a device
+func (a *App) StopActivityWatcher(deviceId string) {
+	activityWatchersMu.Lock()
+	defer activityWatchersMu.Unlock()
+	if cancel, ok := activityWatchers[deviceId]; ok {
+		cancel()
+		delete(activityWatchers, deviceId)
+	}
+}
+
+// stopAllActivityWatchers stops every watcher on shutdown
+func (a *App) stopAllActivityWatchers() {
+	activityWatchersMu.Lock()
+	defer activityWatchersMu.Unlock()
+	for id, cancel := range activityWatchers {
+		cancel()
+		delete(activityWatchers, id)
+	}
+}
+
+// waitForActivity polls until the current activity matches expected or timeout passes
+func (a *App) waitForActivity(ctx context.Context, deviceId, expected string, timeout time.Duration) (*CurrentActivity, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := a.GetCurrentActivity(deviceId)
+		if err == nil && activityMatches(*current, expected) {
+			return current, true
+		}
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			return current, false
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Advanced query syntax, e.g. `clickable=true AND (class$Button OR desc~close) AND NOT text=""`
+//
+//	attr=value   equals            attr!=value  does not equal
+//	attr:value   contains          attr~value   contains
+//	attr^value   starts with       attr$value   ends with
+//	attr*=regex  matches regex     attr=/re/i   same
+//
+// Comparisons ignore case except for regexes (add the i flag). A term without an operator
+// searches text, content-desc and resource-id. Values run to the next AND, OR or closing
+// parenthesis; quote them to include those. A value only counts as /re/ when the closing
+// slash ends it, so paths like text:/sdcard/x are plain values. NOT is only a keyword in
+// capitals, leaving a term like "Not now" alone. AND binds tighter than OR, NOT tighter
+// than both.
+
+// Compiled Advanced Query Cache
+var (
+	advancedQueryCache   = make(map[string]advancedQuery)
+	advancedQueryCacheMu sync.Mutex
+)
+
+const maxAdvancedQueryCache = 256
+
+type advancedQuery interface {
+	match(a *App, node *UINode) bool
+}
+
+type aqOr []advancedQuery
+
+func (q aqOr) match(a *App, node *UINode) bool {
+	for _, sub := range q {
+		if sub.match(a, node) {
+			return true
+		}
+	}
+	return false
+}
+
+type aqAnd []advancedQuery
+
+func (q aqAnd) match(a *App, node *UINode) bool {
+	for _, sub := range q {
+		if !sub.match(a, node) {
+			return false
+		}
+	}
+	return true
+}
+
+type aqNot struct{ q advancedQuery }
+
+func (q aqNot) match(a *App, node *UINode) bool { return !q.q.match(a, node) }
+
+type aqCondition struct {
+	attr  string // "" searches text, content-desc and resource-id
+	op    string
+	value string // lower-cased
+	re    *regexp.Regexp
+}
+
+func (c aqCondition) match(a *App, node *UINode) bool {
+	if c.attr == "" {
+		return strings.Contains(strings.ToLower(node.Text), c.value) ||
+			strings.Contains(strings.ToLower(node.ContentDesc), c.value) ||
+			strings.Contains(strings.ToLower(node.ResourceID), c.value)
+	}
+	attrValue := a.getNodeAttribute(node, c.attr)
+	if c.re != nil {
+		return c.re.MatchString(attrValue)
+	}
+	attrValue = strings.ToLower(attrValue)
+	switch c.op {
+	case "=":
+		return attrValue == c.value
+	case "!=":
+		return attrValue != c.value
+	case ":", "~":
+		return strings.Contains(attrValue, c.value)
+	case "^":
+		return strings.HasPrefix(attrValue, c.value)
+	case "$":
+		return strings.HasSuffix(attrValue, c.value)
+	}
+	return false
+}
+
+// compileAdvancedQuery parses query, reusing earlier compilations
+func compileAdvancedQuery(query string) (advancedQuery, error) {
+	query = strings.TrimSpace(query)
+	advancedQueryCacheMu.Lock()
+	compiled, ok := advancedQueryCache[query]
+	advancedQueryCacheMu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+
+	if query == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &aqParser{s: []rune(query)}
+	compiled, err := p.parseOr()
+	if err == nil && p.skipSpace() < len(p.s) {
+		err = fmt.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	advancedQueryCacheMu.Lock()
+	if len(advancedQueryCache) >= maxAdvancedQueryCache {
+		advancedQueryCache = make(map[string]advancedQuery)
+	}
+	advancedQueryCache[query] = compiled
+	advancedQueryCacheMu.Unlock()
+	return compiled, nil
+}
+
+type aqParser struct {
+	s     []rune
+	pos   int
+	depth int // open parentheses
+}
+
+func (p *aqParser) skipSpace() int {
+	for p.pos < len(p.s) && unicode.IsSpace(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.pos
+}
+
+// keywordAt reports whether word (case-insensitive) starts at i and is followed by a space,
+// a parenthesis or the end
+func (p *aqParser) keywordAt(i int, word string) bool {
+	end := i + len(word)
+	if end > len(p.s) || !strings.EqualFold(string(p.s[i:end]), word) {
+		return false
+	}
+	return end == len(p.s) || unicode.IsSpace(p.s[end]) || p.s[end] == '(' || p.s[end] == ')'
+}
+
+func (p *aqParser) keyword(word string) bool {
+	if p.keywordAt(p.skipSpace(), word) {
+		p.pos += len(word)
+		return true
+	}
+	return false
+}
+
+// notKeyword consumes a NOT written in capitals and followed by a term
+func (p *aqParser) notKeyword() bool {
+	i := p.skipSpace()
+	if !p.keywordAt(i, "NOT") || string(p.s[i:i+3]) != "NOT" || p.atBoundary(i+3) {
+		return false
+	}
+	p.pos += 3
+	return true
+}
+
+func (p *aqParser) parseOr() (advancedQuery, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := aqOr{first}
+	for p.keyword("OR") {
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, next)
+	}
+	if len(or) == 1 {
+		return first, nil
+	}
+	return or, nil
+}
+
+func (p *aqParser) parseAnd() (advancedQuery, error) {
+	first, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	and := aqAnd{first}
+	for p.keyword("AND") {
+		next, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, next)
+	}
+	if len(and) == 1 {
+		return first, nil
+	}
+	return and, nil
+}
+
+func (p *aqParser) parseNot() (advancedQuery, error) {
+	if p.notKeyword() {
+		q, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return aqNot{q}, nil
+	}
+	if p.skipSpace() < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		p.depth++
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace() >= len(p.s) || p.s[p.pos] != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		p.depth--
+		return q, nil
+	}
+	return p.parseCondition()
+}
+
+// atBoundary reports whether a term ends at i: the end, a closing parenthesis inside a group,
+// or whitespace before AND/OR
+func (p *aqParser) atBoundary(i int) bool {
+	if i >= len(p.s) {
+		return true
+	}
+	if p.s[i] == ')' && p.depth > 0 {
+		return true
+	}
+	if !unicode.IsSpace(p.s[i]) {
+		return false
+	}
+	j := i
+	for j < len(p.s) && unicode.IsSpace(p.s[j]) {
+		j++
+	}
+	return j >= len(p.s) || (p.s[j] == ')' && p.depth > 0) || p.keywordAt(j, "AND") || p.keywordAt(j, "OR")
+}
+
+var aqOperators = []string{"*=", "!=", "~", "^", "$", "=", ":"}
+
+func (p *aqParser) parseCondition() (advancedQuery, error) {
+	start := p.skipSpace()
+	if start >= len(p.s) {
+		return nil, fmt.Errorf("expected a condition at end of query")
+	}
+
+	// attr followed directly by an operator
+	i := start
+	for i < len(p.s) && (unicode.IsLetter(p.s[i]) || unicode.IsDigit(p.s[i]) || p.s[i] == '-' || p.s[i] == '_') {
+		i++
+	}
+	op := ""
+	if i > start {
+		for _, candidate := range aqOperators {
+			if strings.HasPrefix(string(p.s[i:min(i+2, len(p.s))]), candidate) {
+				op = candidate
+				break
+			}
+		}
+	}
+
+	if op == "" {
+		// Bare search term
+		end := start
+		for !p.atBoundary(end) {
+			end++
+		}
+		p.pos = end
+		term := strings.TrimSpace(string(p.s[start:end]))
+		if term == "" {
+			return nil, fmt.Errorf("expected a condition at %d", start)
+		}
+		return aqCondition{value: strings.ToLower(unquote(term))}, nil
+	}
+
+	cond := aqCondition{attr: string(p.s[start:i]), op: op}
+	p.pos = i + len(op)
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+
+	var value string
+	isRegex := op == "*="
+	flags := ""
+	switch {
+	case p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\''):
+		quote := p.s[p.pos]
+		end := p.pos + 1
+		for end < len(p.s) && p.s[end] != quote {
+			end++
+		}
+		if end >= len(p.s) {
+			return nil, fmt.Errorf("unterminated quote in %s condition", cond.attr)
+		}
+		value = string(p.s[p.pos+1 : end])
+		p.pos = end + 1
+	case p.pos < len(p.s) && p.s[p.pos] == '/' && (op == "=" || op == "*=") && p.regexLiteralEnd(p.pos) > 0:
+		end := p.regexLiteralEnd(p.pos)
+		value = string(p.s[p.pos+1 : end])
+		p.pos = end + 1
+		if p.pos < len(p.s) && p.s[p.pos] == 'i' {
+			flags = "(?i)"
+			p.pos++
+		}
+		isRegex = true
+	default:
+		end := p.pos
+		for !p.atBoundary(end) {
+			end++
+		}
+		value = strings.TrimSpace(string(p.s[p.pos:end]))
+		p.pos = end
+	}
+	if !p.atBoundary(p.pos) {
+		return nil, fmt.Errorf("unexpected %q after %s condition", p.s[p.pos], cond.attr)
+	}
+
+	if isRegex {
+		re, err := regexp.Compile(flags + value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for %s: %w", cond.attr, err)
+		}
+		cond.re = re
+		return cond, nil
+	}
+	cond.value = strings.ToLower(value)
+	return cond, nil
+}
+
+// regexLiteralEnd returns the index of the slash closing a /re/ or /re/i value starting at
+// start, or -1 when the value goes on past it, as a path does
+func (p *aqParser) regexLiteralEnd(start int) int {
+	end := start + 1
+	for end < len(p.s) && p.s[end] != '/' {
+		if p.s[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(p.s) || end == start+1 {
+		return -1
+	}
+	after := end + 1
+	if after < len(p.s) && p.s[after] == 'i' {
+		after++
+	}
+	if !p.atBoundary(after) {
+		return -1
+	}
+	return end
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
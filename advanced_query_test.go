@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdvancedQuery(t *testing.T) {
+	nodes := map[string]*UINode{
+		"notNow":  {Text: "Not now", Class: "android.widget.Button", Clickable: "true"},
+		"allow":   {Text: "Allow", Class: "android.widget.Button", Clickable: "true"},
+		"path":    {Text: "/sdcard/x/photo.jpg", Class: "android.widget.TextView"},
+		"sdcard":  {Text: "sdcard", Class: "android.widget.TextView"},
+		"version": {Text: "Version 12", Class: "android.widget.TextView"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`text:Not now`, []string{"notNow"}},
+		{`Not now`, []string{"notNow"}},
+		{`class$Button AND NOT text=allow`, []string{"notNow"}},
+		{`NOT (clickable=true)`, []string{"path", "sdcard", "version"}},
+		{`text:/sdcard/x`, []string{"path"}},
+		{`text^/sdcard/`, []string{"path"}},
+		{`text=/^sdcard$/`, []string{"sdcard"}},
+		{`text=/^version \d+$/i`, []string{"version"}},
+		{`text*=^Not`, []string{"notNow"}},
+		{`text=/card$/ OR text=allow`, []string{"allow", "sdcard"}},
+	}
+	for _, tt := range tests {
+		q, err := compileAdvancedQuery(tt.query)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, name := range []string{"allow", "notNow", "path", "sdcard", "version"} {
+			if q.match(&App{}, nodes[name]) {
+				got = append(got, name)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// animationScaleKeys are the global settings behind the three developer option scales, in
+// AnimationScales field order
+var animationScaleKeys = []string{"window_animation_scale", "transition_animation_scale", "animator_duration_scale"}
+
+var (
+	// savedAnimationScales holds what DisableAnimations replaced, keyed by device serial
+	savedAnimationScales   = make(map[string]AnimationScales)
+	savedAnimationScalesMu sync.Mutex
+)
+
+// GetAnimationScales reads the window, transition and animator scales. Unset scales are 1.
+func (a *App) GetAnimationScales(deviceId string) (*AnimationScales, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	reads := make([]string, len(animationScaleKeys))
+	for i, k := range animationScaleKeys {
+		reads[i] = "settings get global " + k
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(reads, "; ")).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read animation scales: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	values := [3]float64{1, 1, 1}
+	for i, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if i >= len(values) {
+			break
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(line), 64); err == nil {
+			values[i] = v
+		}
+	}
+	return &AnimationScales{Window: values[0], Transition: values[1], Animator: values[2]}, nil
+}
+
+// SetAnimationScales sets the three scales (0 turns an animation off, 1 is normal speed) and
+// returns them as read back
+func (a *App) SetAnimationScales(deviceId string, window, transition, animator float64) (*AnimationScales, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	values := []float64{window, transition, animator}
+	puts := make([]string, len(animationScaleKeys))
+	for i, k := range animationScaleKeys {
+		if values[i] < 0 || values[i] > 10 {
+			return nil, fmt.Errorf("%s must be between 0 and 10", k)
+		}
+		puts[i] = "settings put global " + k + " " + strconv.FormatFloat(values[i], 'f', -1, 64)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(puts, " && ")).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return nil, fmt.Errorf("failed to set animation scales: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	scales, err := a.GetAnimationScales(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if scales.Window != window || scales.Transition != transition || scales.Animator != animator {
+		return scales, fmt.Errorf("animation scales read back as %g/%g/%g", scales.Window, scales.Transition, scales.Animator)
+	}
+	return scales, nil
+}
+
+// DisableAnimations sets all scales to 0, remembering the current ones for RestoreAnimations.
+// Calling it again before a restore keeps the first saved values.
+func (a *App) DisableAnimations(deviceId string) (*AnimationScales, error) {
+	serial := a.serialFor(deviceId)
+	savedAnimationScalesMu.Lock()
+	_, saved := savedAnimationScales[serial]
+	savedAnimationScalesMu.Unlock()
+
+	if !saved {
+		current, err := a.GetAnimationScales(deviceId)
+		if err != nil {
+			return nil, err
+		}
+		savedAnimationScalesMu.Lock()
+		savedAnimationScales[serial] = *current
+		savedAnimationScalesMu.Unlock()
+	}
+	return a.SetAnimationScales(deviceId, 0, 0, 0)
+}
+
+// RestoreAnimations puts back the scales DisableAnimations replaced. Without saved values it
+// leaves the device alone and returns the current scales.
+func (a *App) RestoreAnimations(deviceId string) (*AnimationScales, error) {
+	serial := a.serialFor(deviceId)
+	savedAnimationScalesMu.Lock()
+	prev, ok := savedAnimationScales[serial]
+	savedAnimationScalesMu.Unlock()
+	if !ok {
+		return a.GetAnimationScales(deviceId)
+	}
+	scales, err := a.SetAnimationScales(deviceId, prev.Window, prev.Transition, prev.Animator)
+	if err != nil {
+		return nil, err
+	}
+	savedAnimationScalesMu.Lock()
+	delete(savedAnimationScales, serial)
+	savedAnimationScalesMu.Unlock()
+	return scales, nil
+}
+
+// withAnimationsDisabled turns animations off for a playback or workflow run and returns the
+// function that restores them. If they were already disabled by someone else, restoring is
+// left to whoever disabled them.
+func (a *App) withAnimationsDisabled(deviceId string) func() {
+	savedAnimationScalesMu.Lock()
+	_, already := savedAnimationScales[a.serialFor(deviceId)]
+	savedAnimationScalesMu.Unlock()
+
+	if _, err := a.DisableAnimations(deviceId); err != nil {
+		a.Log("Could not disable animations on %s: %v", deviceId, err)
+		return func() {}
+	}
+	if already {
+		return func() {}
+	}
+	return func() {
+		if _, err := a.RestoreAnimations(deviceId); err != nil {
+			a.Log("Could not restore animations on %s: %v", deviceId, err)
+		}
+	}
+}
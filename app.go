@@ -12,19 +12,25 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // Binaries are embedded in platform-specific files (bin_*.go) and bin_common.go
 
 // App struct
 type App struct {
-	ctx          context.Context
-	adbPath      string
-	scrcpyPath   string
-	serverPath   string
-	aaptPath     string
-	logcatCmd    *exec.Cmd
-	logcatCancel context.CancelFunc
+	ctx        context.Context
+	adbPath    string
+	scrcpyPath string
+	serverPath string
+	aaptPath   string
+	// fastbootPath is empty when no fastboot binary was found
+	fastbootPath string
+
+	// Logcat sessions keyed by session ID
+	logcatSessions map[string]*logcatSession
+	logcatMu       sync.Mutex
 
 	// Generic mutex for shared state
 	mu sync.Mutex
@@ -35,14 +41,33 @@ type App struct {
 	cachePath   string
 
 	// Scrcpy process management
-	scrcpyCmds      map[string]*exec.Cmd
+	scrcpySessions  map[string][]*scrcpyProcess
 	scrcpyRecordCmd map[string]*exec.Cmd
 	scrcpyMu        sync.Mutex
 
-	// File open process management
+	// File open process management. openStaged holds the shared-storage copies
+	// OpenRemoteFile made on each device, removed on its next open and on shutdown.
 	openFileCmds map[string]*exec.Cmd
+	openStaged   map[string][]string
 	openFileMu   sync.Mutex
 
+	// File preview size cap in bytes
+	previewSizeCap int64
+	previewMu      sync.RWMutex
+
+	// Long-running child processes, terminated on shutdown
+	managedProcs   map[*exec.Cmd]*managedProcess
+	managedProcsMu sync.Mutex
+
+	// Screen recordings in progress, keyed by device
+	recordings    map[string]*screenRecording
+	recordingsDir string
+	recordingMu   sync.Mutex
+
+	// Default thresholds for classifying recorded touches
+	classifierConfig ClassifierConfig
+	classifierMu     sync.Mutex
+
 	// Wireless Server
 	httpServer *http.Server
 	localAddr  string
@@ -78,19 +103,55 @@ type App struct {
 	// Device monitor
 	deviceMonitorCancel context.CancelFunc
 	deviceMonitorMu     sync.Mutex
+
+	// UI dumps keyed by device, reused for uiDumpTTL
+	uiDumps   map[string]*uiDumpState
+	uiDumpTTL time.Duration
+	uiDumpMu  sync.Mutex
+
+	// UI dump cache hooks for running without a device; nil runs uiautomator and dumpsys
+	uiDumpRun    func(deviceId string) (*UIHierarchyResult, error)
+	uiFocusProbe func(deviceId string) (string, error)
+
+	// Workflow run reports kept before the oldest are pruned
+	workflowReportLimit int
+	workflowReportMu    sync.Mutex
+
+	// Workflow engine hooks for running without a device or frontend; nil dumps the
+	// device's UI and emits Wails events
+	workflowUIDump func(deviceId string) (*UIHierarchyResult, error)
+	workflowEmit   func(event string, data map[string]interface{})
+
+	// Interactive shell sessions, keyed by session ID
+	shellSessions map[string]*shellSession
+	shellMu       sync.Mutex
+
+	// Dangerous-command policy; dangerousCustom holds the compiled user rules
+	dangerousSettings dangerousCommandSettings
+	dangerousCustom   []compiledDangerousRule
+	dangerousMu       sync.RWMutex
 }
 
 // NewApp creates a new App instance
 func NewApp(version string) *App {
 	app := &App{
-		aaptCache:         make(map[string]AppPackage),
-		scrcpyCmds:        make(map[string]*exec.Cmd),
-		scrcpyRecordCmd:   make(map[string]*exec.Cmd),
-		openFileCmds:      make(map[string]*exec.Cmd),
-		lastActive:        make(map[string]int64),
-		idToSerial:        make(map[string]string),
-		reconnectCooldown: make(map[string]time.Time),
-		version:           version,
+		aaptCache:           make(map[string]AppPackage),
+		logcatSessions:      make(map[string]*logcatSession),
+		shellSessions:       make(map[string]*shellSession),
+		scrcpySessions:      make(map[string][]*scrcpyProcess),
+		scrcpyRecordCmd:     make(map[string]*exec.Cmd),
+		openFileCmds:        make(map[string]*exec.Cmd),
+		openStaged:          make(map[string][]string),
+		lastActive:          make(map[string]int64),
+		idToSerial:          make(map[string]string),
+		reconnectCooldown:   make(map[string]time.Time),
+		previewSizeCap:      defaultPreviewSizeCap,
+		recordings:          make(map[string]*screenRecording),
+		managedProcs:        make(map[*exec.Cmd]*managedProcess),
+		uiDumps:             make(map[string]*uiDumpState),
+		uiDumpTTL:           defaultUIDumpTTL,
+		workflowReportLimit: defaultWorkflowReportLimit,
+		version:             version,
 	}
 	app.initPersistentCache()
 	return app
@@ -102,15 +163,25 @@ func (a *App) startup(ctx context.Context) {
 	a.setupBinaries()
 	a.initPersistentCache()
 	a.StartDeviceMonitor()
+	a.startPlaybackScheduler()
+	a.startWorkflowTriggers()
+
+	wailsRuntime.OnFileDrop(ctx, func(x, y int, paths []string) {
+		wailsRuntime.EventsEmit(a.ctx, "files-dropped", map[string]interface{}{
+			"x":     x,
+			"y":     y,
+			"paths": paths,
+		})
+	})
 }
 
 // Shutdown is called when the application is closing
 func (a *App) Shutdown(ctx context.Context) {
 	a.scrcpyMu.Lock()
-	for id, cmd := range a.scrcpyCmds {
-		if cmd.Process != nil {
+	for id, sessions := range a.scrcpySessions {
+		for _, s := range sessions {
 			os.Stderr.WriteString(fmt.Sprintf("\n[SHUTDOWN] Killing mirroring for %s\n", id))
-			_ = cmd.Process.Kill()
+			_ = s.cmd.Process.Kill()
 		}
 	}
 	for id, cmd := range a.scrcpyRecordCmd {
@@ -120,8 +191,25 @@ func (a *App) Shutdown(ctx context.Context) {
 		}
 	}
 	a.scrcpyMu.Unlock()
-	a.StopLogcat()
+	a.stopAllScreenRecordings()
+	a.stopAllSystemTraces()
+	a.stopAllLocationRoutes()
+	a.stopAllActivityWatchers()
+	a.stopAllNotificationWatchers()
+	a.stopAllMonkeyTests()
+	a.stopAllMethodTraces()
+	a.stopAllPacketCaptures()
+	a.StopAllLogcat()
+	a.closeAllShellSessions()
+	a.stopAllResourceMonitors()
+	a.stopAppMemoryTracesForOffline(nil)
+	a.stopJankMonitorsForOffline(nil)
+	a.stopAppNetworkMonitorsForOffline(nil)
 	a.StopDeviceMonitor()
+	a.stopPlaybackScheduler()
+	a.stopWorkflowTriggers()
+	a.removeAllStagedOpenFiles()
+	a.stopManagedProcesses(3 * time.Second)
 }
 
 // GetAppVersion returns the application version
@@ -155,18 +243,23 @@ func (a *App) GetBackendLogs() []string {
 	return logs
 }
 
+// serialFor maps a transport ID (e.g. an IP:port) to the device's hardware serial when known
+func (a *App) serialFor(deviceId string) string {
+	a.idToSerialMu.RLock()
+	defer a.idToSerialMu.RUnlock()
+	if s, ok := a.idToSerial[deviceId]; ok {
+		return s
+	}
+	return deviceId
+}
+
 // updateLastActive updates the last active timestamp for a device
 func (a *App) updateLastActive(deviceId string) {
 	if deviceId == "" {
 		return
 	}
 
-	serial := deviceId
-	a.idToSerialMu.RLock()
-	if s, ok := a.idToSerial[deviceId]; ok {
-		serial = s
-	}
-	a.idToSerialMu.RUnlock()
+	serial := a.serialFor(deviceId)
 
 	a.lastActiveMu.Lock()
 	a.lastActive[serial] = time.Now().Unix()
@@ -214,6 +307,34 @@ func (a *App) loadSettings() {
 	a.pinnedMu.Lock()
 	a.pinnedSerial = settings.PinnedSerial
 	a.pinnedMu.Unlock()
+
+	if settings.PreviewSizeCap > 0 {
+		a.previewMu.Lock()
+		a.previewSizeCap = settings.PreviewSizeCap
+		a.previewMu.Unlock()
+	}
+
+	if settings.RecordingsDir != "" {
+		a.recordingMu.Lock()
+		a.recordingsDir = settings.RecordingsDir
+		a.recordingMu.Unlock()
+	}
+
+	if settings.TouchClassifier != nil {
+		a.classifierMu.Lock()
+		a.classifierConfig = *settings.TouchClassifier
+		a.classifierMu.Unlock()
+	}
+
+	if settings.WorkflowReports > 0 {
+		a.workflowReportMu.Lock()
+		a.workflowReportLimit = settings.WorkflowReports
+		a.workflowReportMu.Unlock()
+	}
+
+	if settings.DangerousCommands != nil {
+		a.setDangerousCommandSettings(*settings.DangerousCommands)
+	}
 }
 
 func (a *App) saveSettings() {
@@ -232,9 +353,43 @@ func (a *App) saveSettings() {
 	pinnedSerial := a.pinnedSerial
 	a.pinnedMu.RUnlock()
 
+	a.previewMu.RLock()
+	previewSizeCap := a.previewSizeCap
+	a.previewMu.RUnlock()
+
+	a.recordingMu.Lock()
+	recordingsDir := a.recordingsDir
+	a.recordingMu.Unlock()
+
+	a.classifierMu.Lock()
+	var classifier *ClassifierConfig
+	if a.classifierConfig != (ClassifierConfig{}) {
+		c := a.classifierConfig
+		classifier = &c
+	}
+	a.classifierMu.Unlock()
+
+	a.workflowReportMu.Lock()
+	workflowReports := a.workflowReportLimit
+	a.workflowReportMu.Unlock()
+
+	a.dangerousMu.RLock()
+	var dangerous *dangerousCommandSettings
+	if a.dangerousSettings.Disabled || len(a.dangerousSettings.DisabledBuiltins) > 0 || len(a.dangerousSettings.Custom) > 0 {
+		d := a.dangerousSettings
+		dangerous = &d
+	}
+	a.dangerousMu.RUnlock()
+
 	settings := AppSettings{
-		LastActive:   lastActive,
-		PinnedSerial: pinnedSerial,
+		LastActive:      lastActive,
+		PinnedSerial:    pinnedSerial,
+		PreviewSizeCap:  previewSizeCap,
+		RecordingsDir:   recordingsDir,
+		TouchClassifier: classifier,
+		WorkflowReports: workflowReports,
+
+		DangerousCommands: dangerous,
 	}
 
 	data, err := json.Marshal(settings)
@@ -319,6 +474,12 @@ func (a *App) setupBinaries() {
 		}
 	}
 
+	// fastboot isn't bundled; the SDK's usually sits next to adb in platform-tools
+	a.fastbootPath = a.findPlatformTool("fastboot")
+	if a.fastbootPath != "" {
+		fmt.Printf("Using fastboot at: %s\n", a.fastbootPath)
+	}
+
 	a.scrcpyPath = extract("scrcpy", scrcpyBinary)
 	a.serverPath = extract("scrcpy-server", scrcpyServerBinary)
 
@@ -331,6 +492,32 @@ func (a *App) setupBinaries() {
 	a.Log("Final ADB path: %s", a.adbPath)
 }
 
+// findPlatformTool looks for an SDK platform-tools binary on PATH, next to adb and under
+// ANDROID_HOME / ANDROID_SDK_ROOT
+func (a *App) findPlatformTool(name string) string {
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	var candidates []string
+	if a.adbPath != "" {
+		candidates = append(candidates, filepath.Join(filepath.Dir(a.adbPath), name))
+	}
+	for _, env := range []string{"ANDROID_HOME", "ANDROID_SDK_ROOT"} {
+		if dir := os.Getenv(env); dir != "" {
+			candidates = append(candidates, filepath.Join(dir, "platform-tools", name))
+		}
+	}
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c
+		}
+	}
+	return ""
+}
+
 // Command helper functions
 
 // newAdbCommand creates an exec.Cmd with a clean environment to avoid proxy issues
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	// meminfoProcessHeader starts a process block: "** MEMINFO in pid 1234 [com.example] **"
+	meminfoProcessHeader = regexp.MustCompile(`^\*\* MEMINFO in pid (\d+) \[([^\]]+)\] \*\*`)
+	// meminfoPair matches "Name: 123" pairs; Objects and SQL print two per line
+	meminfoPair = regexp.MustCompile(`([A-Za-z][A-Za-z0-9 _()./-]*?):\s+(-?\d+)`)
+
+	appMemoryTraces   = make(map[string]context.CancelFunc)
+	appMemoryTracesMu sync.Mutex
+)
+
+// GetAppMemoryInfo runs `dumpsys meminfo <package>` and parses the first (main) process block
+func (a *App) GetAppMemoryInfo(deviceId, packageName string) (*AppMemoryInfo, error) {
+	return a.readAppMemoryInfo(context.Background(), deviceId, packageName)
+}
+
+func (a *App) readAppMemoryInfo(ctx context.Context, deviceId, packageName string) (*AppMemoryInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return nil, fmt.Errorf("no package specified")
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", "dumpsys", "meminfo", packageName).Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to read meminfo: %w", err)
+	}
+	info, err := parseAppMeminfo(string(out))
+	if err != nil {
+		return nil, err
+	}
+	info.Package = packageName
+	info.Timestamp = time.Now().UnixMilli()
+	return info, nil
+}
+
+// parseAppMeminfo reads the per-process table, App Summary, Objects and SQL sections; any
+// other section is kept verbatim in Extras. Android 9 has no Rss columns and prints "TOTAL:"
+// where later releases print "TOTAL PSS:".
+func parseAppMeminfo(text string) (*AppMemoryInfo, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	start := -1
+	info := &AppMemoryInfo{
+		Summary:    make(map[string]int64),
+		SummaryRss: make(map[string]int64),
+		Objects:    make(map[string]int64),
+		SQL:        make(map[string]int64),
+		Extras:     make(map[string]string),
+	}
+	for i, line := range lines {
+		if m := meminfoProcessHeader.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			info.PID, _ = strconv.Atoi(m[1])
+			info.Process = m[2]
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		if msg := strings.TrimSpace(text); strings.HasPrefix(msg, "No process found") {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, fmt.Errorf("unrecognised meminfo output")
+	}
+
+	section := ""
+	var extra []string
+	var header1, header2 []string
+	rssCol := -1
+	flushExtra := func() {
+		if section != "" && len(extra) > 0 {
+			info.Extras[section] = strings.Join(extra, "\n")
+		}
+		extra = nil
+	}
+
+	for _, line := range lines[start:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "** MEMINFO") {
+			// Further processes of the same package
+			break
+		}
+		if trimmed == "" {
+			continue
+		}
+		if isMeminfoSectionTitle(line, trimmed) {
+			flushExtra()
+			section = trimmed
+			continue
+		}
+
+		switch section {
+		case "":
+			// The main table: two header rows, a dashed rule, then "Label  n  n  n ..."
+			if strings.HasPrefix(trimmed, "---") {
+				continue
+			}
+			label, values := splitMeminfoRow(trimmed)
+			if len(values) == 0 {
+				if header1 == nil {
+					header1 = strings.Fields(trimmed)
+				} else if header2 == nil {
+					header2 = strings.Fields(trimmed)
+				}
+				continue
+			}
+			columns := meminfoColumns(header1, header2)
+			row := MeminfoRow{Name: label, Values: make(map[string]int64, len(values))}
+			for i, v := range values {
+				if i < len(columns) {
+					row.Values[columns[i]] = v
+				}
+			}
+			info.Rows = append(info.Rows, row)
+			if label == "TOTAL" {
+				info.TotalPrivateDirty = row.Values["Private Dirty"]
+				info.TotalPrivateClean = row.Values["Private Clean"]
+				if info.TotalPss == 0 {
+					info.TotalPss = row.Values["Pss Total"]
+				}
+				if info.TotalRss == 0 {
+					info.TotalRss = row.Values["Rss Total"]
+				}
+			}
+
+		case "App Summary":
+			if idx := strings.Index(line, "Rss(KB)"); idx >= 0 {
+				rssCol = idx
+				continue
+			}
+			if strings.Contains(line, "Pss(KB)") || strings.HasPrefix(trimmed, "---") {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "TOTAL") {
+				for _, m := range meminfoPair.FindAllStringSubmatch(trimmed, -1) {
+					v, _ := strconv.ParseInt(m[2], 10, 64)
+					switch strings.TrimSpace(m[1]) {
+					case "TOTAL", "TOTAL PSS":
+						info.TotalPss = v
+					case "TOTAL RSS":
+						info.TotalRss = v
+					case "TOTAL SWAP PSS", "TOTAL SWAP (KB)":
+						info.TotalSwapPss = v
+					}
+				}
+				continue
+			}
+			name, rest, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			name = strings.TrimSpace(name)
+			// A value lives in the Rss column when it ends past the midpoint of the two headers
+			offset := len(name) + 1
+			for _, field := range strings.Fields(rest) {
+				pos := strings.Index(line[offset:], field) + offset
+				offset = pos + len(field)
+				v, err := strconv.ParseInt(field, 10, 64)
+				if err != nil {
+					continue
+				}
+				if rssCol >= 0 && offset > rssCol-8 {
+					info.SummaryRss[name] = v
+				} else {
+					info.Summary[name] = v
+				}
+			}
+
+		case "Objects", "SQL":
+			target := info.Objects
+			if section == "SQL" {
+				target = info.SQL
+			}
+			for _, m := range meminfoPair.FindAllStringSubmatch(trimmed, -1) {
+				v, _ := strconv.ParseInt(m[2], 10, 64)
+				target[strings.TrimSpace(m[1])] = v
+			}
+
+		default:
+			extra = append(extra, strings.TrimRight(line, " "))
+		}
+	}
+	flushExtra()
+
+	info.JavaHeap = info.Summary["Java Heap"]
+	info.NativeHeap = info.Summary["Native Heap"]
+	info.Code = info.Summary["Code"]
+	info.Stack = info.Summary["Stack"]
+	info.Graphics = info.Summary["Graphics"]
+	info.PrivateOther = info.Summary["Private Other"]
+	info.System = info.Summary["System"]
+	info.Views = info.Objects["Views"]
+	info.ViewRootImpl = info.Objects["ViewRootImpl"]
+	info.Activities = info.Objects["Activities"]
+	info.AppContexts = info.Objects["AppContexts"]
+	return info, nil
+}
+
+// isMeminfoSectionTitle recognises " App Summary", " Objects", " SQL", " DATABASES" and the
+// like: a short, lightly indented line without digits or colons
+func isMeminfoSectionTitle(line, trimmed string) bool {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 2 || strings.ContainsAny(trimmed, ":0123456789-") {
+		return false
+	}
+	return len(strings.Fields(trimmed)) <= 3
+}
+
+// splitMeminfoRow splits "Native Heap  12345  12300  0" into its label and numbers
+func splitMeminfoRow(line string) (string, []int64) {
+	fields := strings.Fields(line)
+	i := 0
+	for i < len(fields) {
+		if _, err := strconv.ParseInt(fields[i], 10, 64); err == nil {
+			break
+		}
+		i++
+	}
+	var values []int64
+	for _, f := range fields[i:] {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			break
+		}
+		values = append(values, v)
+	}
+	return strings.Join(fields[:i], " "), values
+}
+
+// meminfoColumns joins the two header rows into names such as "Pss Total" and "Private Dirty"
+func meminfoColumns(header1, header2 []string) []string {
+	if len(header1) != len(header2) {
+		return header2
+	}
+	columns := make([]string, len(header1))
+	for i := range header1 {
+		columns[i] = header1[i] + " " + header2[i]
+	}
+	return columns
+}
+
+// StartAppMemoryTrace samples the app's meminfo every intervalSec seconds and emits
+// app-memory-sample events until StopAppMemoryTrace or the device goes away. Samples taken
+// while the app isn't running carry an error instead of data.
+func (a *App) StartAppMemoryTrace(deviceId, packageName string, intervalSec int) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return fmt.Errorf("no package specified")
+	}
+	if intervalSec <= 0 {
+		intervalSec = 5
+	}
+
+	a.StopAppMemoryTrace(deviceId, packageName)
+	ctx, cancel := context.WithCancel(context.Background())
+	key := deviceId + "|" + packageName
+	appMemoryTracesMu.Lock()
+	appMemoryTraces[key] = cancel
+	appMemoryTracesMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+		defer ticker.Stop()
+		for {
+			info, err := a.readAppMemoryInfo(ctx, deviceId, packageName)
+			if ctx.Err() != nil {
+				return
+			}
+			payload := map[string]interface{}{
+				"deviceId":    deviceId,
+				"packageName": packageName,
+				"timestamp":   time.Now().UnixMilli(),
+			}
+			if err != nil {
+				payload["error"] = err.Error()
+			} else {
+				payload["info"] = info
+			}
+			wailsRuntime.EventsEmit(a.ctx, "app-memory-sample", payload)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// StopAppMemoryTrace stops sampling the app
+func (a *App) StopAppMemoryTrace(deviceId, packageName string) {
+	key := deviceId + "|" + packageName
+	appMemoryTracesMu.Lock()
+	cancel := appMemoryTraces[key]
+	delete(appMemoryTraces, key)
+	appMemoryTracesMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// stopAppMemoryTracesForOffline stops traces whose device is no longer online; with nil it
+// stops them all
+func (a *App) stopAppMemoryTracesForOffline(devices []Device) {
+	online := onlineDeviceIDs(devices)
+	appMemoryTracesMu.Lock()
+	for key, cancel := range appMemoryTraces {
+		deviceId, _, _ := strings.Cut(key, "|")
+		if !online[deviceId] {
+			cancel()
+			delete(appMemoryTraces, key)
+		}
+	}
+	appMemoryTracesMu.Unlock()
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// uidPackagesTTL bounds how long a UID-to-package map is trusted without a refresh
+	uidPackagesTTL = 5 * time.Minute
+	// uidPackagesRetry is the minimum gap between refreshes triggered by unknown UIDs
+	uidPackagesRetry = 30 * time.Second
+)
+
+// sharedUIDNames labels the well-known shared UIDs, whose packages are reported together
+var sharedUIDNames = map[int]string{
+	0:    "root",
+	1000: "android.uid.system",
+	1001: "android.uid.phone",
+	1002: "android.uid.bluetooth",
+	1013: "android.media",
+	1021: "android.uid.gps",
+	1027: "android.uid.nfc",
+	1073: "android.uid.networkstack",
+	2000: "android.uid.shell",
+	-4:   "removed apps",
+	-5:   "tethering",
+}
+
+var (
+	// netstatsIdent starts a UID entry: "ident=[{type=WIFI, ...}] uid=10123 set=DEFAULT tag=0x0"
+	netstatsIdent = regexp.MustCompile(`ident=\[(.*)\]\s+uid=(-?\d+)\s+set=\S+\s+tag=(0x[0-9a-fA-F]+)`)
+	// netstatsBucket is one history bucket: "st=1690000000 rb=12345 rp=10 tb=2345 tp=5 op=0"
+	netstatsBucket = regexp.MustCompile(`\brb=(\d+)\b.*\btb=(\d+)\b`)
+)
+
+// uidPackages is a device's UID-to-package map
+type uidPackages struct {
+	packages  map[int][]string
+	fetched   time.Time
+	retriedAt time.Time
+}
+
+var (
+	uidPackageCache   = make(map[string]*uidPackages)
+	uidPackageCacheMu sync.Mutex
+
+	appNetworkMonitors   = make(map[string]context.CancelFunc)
+	appNetworkMonitorsMu sync.Mutex
+)
+
+// GetNetworkStats returns the bytes each app has received and sent over mobile and Wi-Fi,
+// from /proc/net/xt_qtaguid/stats on Android 9 and earlier and dumpsys netstats after that
+func (a *App) GetNetworkStats(deviceId string) (*AppNetworkStats, error) {
+	return a.readAppNetworkStats(context.Background(), deviceId)
+}
+
+func (a *App) readAppNetworkStats(ctx context.Context, deviceId string) (*AppNetworkStats, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	stats := &AppNetworkStats{DeviceID: deviceId, Timestamp: time.Now().UnixMilli()}
+	var usage map[int]*AppNetworkUsage
+	out, err := a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", "cat /proc/net/xt_qtaguid/stats 2>/dev/null").Output()
+	if err == nil && strings.Contains(string(out), "uid_tag_int") {
+		stats.Source = "qtaguid"
+		usage = parseQtaguidStats(string(out))
+	} else {
+		// --poll makes the service fold pending traffic into its history first
+		out, err = a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", "dumpsys netstats --poll >/dev/null; dumpsys netstats --uid").Output()
+		if err != nil && len(out) == 0 {
+			return nil, fmt.Errorf("failed to read network stats: %w", err)
+		}
+		stats.Source = "netstats"
+		usage = parseNetstatsUIDs(string(out))
+	}
+
+	packages := a.packagesForUIDs(ctx, deviceId, usage)
+	stats.Apps = make([]AppNetworkUsage, 0, len(usage))
+	for uid, u := range usage {
+		u.UID = uid
+		u.Label, u.Packages = uidLabel(uid, packages)
+		stats.Apps = append(stats.Apps, *u)
+		stats.RxMobile += u.RxMobile
+		stats.TxMobile += u.TxMobile
+		stats.RxWifi += u.RxWifi
+		stats.TxWifi += u.TxWifi
+	}
+	sort.Slice(stats.Apps, func(i, j int) bool {
+		return stats.Apps[i].total() > stats.Apps[j].total()
+	})
+	return stats, nil
+}
+
+func (u *AppNetworkUsage) total() int64 {
+	return u.RxMobile + u.TxMobile + u.RxWifi + u.TxWifi
+}
+
+// networkKind classifies an interface name as "wifi", "mobile" or "" for everything else
+// (loopback, VPN tunnels and the like, which would count traffic twice)
+func networkKind(iface string) string {
+	switch {
+	case strings.HasPrefix(iface, "wlan"), strings.HasPrefix(iface, "swlan"), strings.HasPrefix(iface, "wifi"):
+		return "wifi"
+	case strings.HasPrefix(iface, "rmnet"), strings.HasPrefix(iface, "r_rmnet"), strings.HasPrefix(iface, "rev_rmnet"),
+		strings.HasPrefix(iface, "ccmni"), strings.HasPrefix(iface, "seth"), strings.HasPrefix(iface, "pdp"),
+		strings.HasPrefix(iface, "v4-rmnet"), strings.HasPrefix(iface, "clat"):
+		return "mobile"
+	}
+	return ""
+}
+
+// parseQtaguidStats sums the untagged rows of xt_qtaguid/stats per UID
+func parseQtaguidStats(text string) map[int]*AppNetworkUsage {
+	usage := make(map[int]*AppNetworkUsage)
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		// idx iface acct_tag_hex uid_tag_int cnt_set rx_bytes rx_packets tx_bytes ...
+		if len(fields) < 8 || fields[2] != "0x0" {
+			continue
+		}
+		kind := networkKind(fields[1])
+		uid, err := strconv.Atoi(fields[3])
+		if kind == "" || err != nil {
+			continue
+		}
+		rx, _ := strconv.ParseInt(fields[5], 10, 64)
+		tx, _ := strconv.ParseInt(fields[7], 10, 64)
+		addNetworkUsage(usage, uid, kind, rx, tx)
+	}
+	return usage
+}
+
+// parseNetstatsUIDs sums the untagged history buckets of `dumpsys netstats --uid` per UID
+func parseNetstatsUIDs(text string) map[int]*AppNetworkUsage {
+	usage := make(map[int]*AppNetworkUsage)
+	uid, kind := 0, ""
+	inEntry := false
+	for _, line := range strings.Split(text, "\n") {
+		if m := netstatsIdent.FindStringSubmatch(line); m != nil {
+			uid, _ = strconv.Atoi(m[2])
+			kind = netstatsIdentKind(m[1])
+			inEntry = m[3] == "0x0" && kind != ""
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+		if m := netstatsBucket.FindStringSubmatch(line); m != nil {
+			rx, _ := strconv.ParseInt(m[1], 10, 64)
+			tx, _ := strconv.ParseInt(m[2], 10, 64)
+			addNetworkUsage(usage, uid, kind, rx, tx)
+		}
+	}
+	return usage
+}
+
+// netstatsIdentKind reads the network type out of an ident; releases print it by name
+// ("type=WIFI"), by number ("type=1") or as transports ("transports={1}")
+func netstatsIdentKind(ident string) string {
+	lower := strings.ToLower(ident)
+	switch {
+	case strings.Contains(lower, "type=wifi"), strings.Contains(lower, "type=1,"), strings.Contains(lower, "type=1}"),
+		strings.Contains(lower, "transports={1}"):
+		return "wifi"
+	case strings.Contains(lower, "type=mobile"), strings.Contains(lower, "type=0,"), strings.Contains(lower, "type=0}"),
+		strings.Contains(lower, "transports={0}"):
+		return "mobile"
+	}
+	return ""
+}
+
+func addNetworkUsage(usage map[int]*AppNetworkUsage, uid int, kind string, rx, tx int64) {
+	u := usage[uid]
+	if u == nil {
+		u = &AppNetworkUsage{}
+		usage[uid] = u
+	}
+	if kind == "wifi" {
+		u.RxWifi += rx
+		u.TxWifi += tx
+	} else {
+		u.RxMobile += rx
+		u.TxMobile += tx
+	}
+}
+
+// uidLabel names a UID: its package, the shared UID's name, or the first package plus a count
+func uidLabel(uid int, packages map[int][]string) (string, []string) {
+	appID := uid
+	if uid >= 0 {
+		// Secondary users get UIDs offset by 100000 per user
+		appID = uid % 100000
+	}
+	pkgs := packages[appID]
+	if name, ok := sharedUIDNames[appID]; ok {
+		return name, pkgs
+	}
+	switch len(pkgs) {
+	case 0:
+		return fmt.Sprintf("uid %d", uid), nil
+	case 1:
+		return pkgs[0], pkgs
+	}
+	return fmt.Sprintf("%s (+%d shared)", pkgs[0], len(pkgs)-1), pkgs
+}
+
+// packagesForUIDs returns the device's UID-to-package map, refreshing it when it has expired
+// or when an app UID in usage isn't in it
+func (a *App) packagesForUIDs(ctx context.Context, deviceId string, usage map[int]*AppNetworkUsage) map[int][]string {
+	serial := a.serialFor(deviceId)
+	uidPackageCacheMu.Lock()
+	cached := uidPackageCache[serial]
+	refresh := cached == nil || time.Since(cached.fetched) > uidPackagesTTL
+	if !refresh && time.Since(cached.retriedAt) > uidPackagesRetry {
+		for uid := range usage {
+			if appID := uid % 100000; appID >= 10000 && cached.packages[appID] == nil {
+				refresh = true
+				cached.retriedAt = time.Now()
+				break
+			}
+		}
+	}
+	uidPackageCacheMu.Unlock()
+	if !refresh {
+		return cached.packages
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", "pm", "list", "packages", "-U").Output()
+	if err != nil {
+		if cached != nil {
+			return cached.packages
+		}
+		return nil
+	}
+	packages := make(map[int][]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		// "package:com.example uid:10123", or "uid:10123,10124" with several users
+		fields := strings.Fields(strings.TrimSpace(line))
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "package:") || !strings.HasPrefix(fields[1], "uid:") {
+			continue
+		}
+		pkg := strings.TrimPrefix(fields[0], "package:")
+		first, _, _ := strings.Cut(strings.TrimPrefix(fields[1], "uid:"), ",")
+		uid, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+		appID := uid % 100000
+		packages[appID] = append(packages[appID], pkg)
+	}
+	for _, pkgs := range packages {
+		sort.Strings(pkgs)
+	}
+
+	uidPackageCacheMu.Lock()
+	uidPackageCache[serial] = &uidPackages{packages: packages, fetched: time.Now(), retriedAt: time.Now()}
+	uidPackageCacheMu.Unlock()
+	return packages
+}
+
+// invalidateUIDPackages drops the cached UID map after an install or uninstall
+func (a *App) invalidateUIDPackages(deviceId string) {
+	uidPackageCacheMu.Lock()
+	delete(uidPackageCache, a.serialFor(deviceId))
+	uidPackageCacheMu.Unlock()
+}
+
+// StartAppNetworkMonitor polls GetNetworkStats every intervalSec seconds and emits the
+// per-app and total throughput since the previous poll as network-sample events
+func (a *App) StartAppNetworkMonitor(deviceId string, intervalSec int) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if intervalSec <= 0 {
+		intervalSec = 2
+	}
+
+	a.StopAppNetworkMonitor(deviceId)
+	ctx, cancel := context.WithCancel(context.Background())
+	appNetworkMonitorsMu.Lock()
+	appNetworkMonitors[deviceId] = cancel
+	appNetworkMonitorsMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+		defer ticker.Stop()
+		var prev *AppNetworkStats
+		for {
+			cur, err := a.readAppNetworkStats(ctx, deviceId)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				if prev != nil && prev.Source == cur.Source {
+					wailsRuntime.EventsEmit(a.ctx, "network-sample", networkRates(prev, cur))
+				}
+				prev = cur
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// StopAppNetworkMonitor stops the device's per-app network monitor
+func (a *App) StopAppNetworkMonitor(deviceId string) {
+	appNetworkMonitorsMu.Lock()
+	cancel := appNetworkMonitors[deviceId]
+	delete(appNetworkMonitors, deviceId)
+	appNetworkMonitorsMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// stopAppNetworkMonitorsForOffline stops monitors whose device is no longer online; with nil
+// it stops them all
+func (a *App) stopAppNetworkMonitorsForOffline(devices []Device) {
+	online := onlineDeviceIDs(devices)
+	appNetworkMonitorsMu.Lock()
+	for deviceId, cancel := range appNetworkMonitors {
+		if !online[deviceId] {
+			cancel()
+			delete(appNetworkMonitors, deviceId)
+		}
+	}
+	appNetworkMonitorsMu.Unlock()
+}
+
+// networkRates turns two snapshots into bytes per second. Counters that went backwards (a
+// reboot, an uninstalled app, history buckets ageing out) count as zero.
+func networkRates(prev, cur *AppNetworkStats) NetworkSample {
+	seconds := float64(cur.Timestamp-prev.Timestamp) / 1000
+	sample := NetworkSample{DeviceID: cur.DeviceID, Timestamp: cur.Timestamp, Apps: []AppNetworkRate{}}
+	if seconds <= 0 {
+		return sample
+	}
+	rate := func(now, before int64) float64 {
+		if now <= before {
+			return 0
+		}
+		return roundTenth(float64(now-before) / seconds)
+	}
+
+	before := make(map[int]AppNetworkUsage, len(prev.Apps))
+	for _, u := range prev.Apps {
+		before[u.UID] = u
+	}
+	for _, u := range cur.Apps {
+		p := before[u.UID]
+		r := AppNetworkRate{
+			UID:      u.UID,
+			Label:    u.Label,
+			RxMobile: rate(u.RxMobile, p.RxMobile),
+			TxMobile: rate(u.TxMobile, p.TxMobile),
+			RxWifi:   rate(u.RxWifi, p.RxWifi),
+			TxWifi:   rate(u.TxWifi, p.TxWifi),
+		}
+		if r.RxMobile+r.TxMobile+r.RxWifi+r.TxWifi == 0 {
+			continue
+		}
+		sample.RxTotal += r.RxMobile + r.RxWifi
+		sample.TxTotal += r.TxMobile + r.TxWifi
+		sample.Apps = append(sample.Apps, r)
+	}
+	sort.Slice(sample.Apps, func(i, j int) bool {
+		ri, rj := sample.Apps[i], sample.Apps[j]
+		return ri.RxMobile+ri.TxMobile+ri.RxWifi+ri.TxWifi > rj.RxMobile+rj.TxMobile+rj.RxWifi+rj.TxWifi
+	})
+	return sample
+}
@@ -735,6 +735,7 @@ func (a *App) UninstallApp(deviceId, packageName string) (string, error) {
 		return "", fmt.Errorf("no device specified")
 	}
 
+	defer a.invalidateUIDPackages(deviceId)
 	a.Log("Uninstalling %s from %s", packageName, deviceId)
 
 	cmd := exec.Command(a.adbPath, "-s", deviceId, "uninstall", packageName)
@@ -898,6 +899,7 @@ func (a *App) InstallAPK(deviceId string, path string) (string, error) {
 		return "", fmt.Errorf("no device selected")
 	}
 
+	defer a.invalidateUIDPackages(deviceId)
 	a.Log("Installing APK %s to device %s", path, deviceId)
 
 	cmd := exec.Command(a.adbPath, "-s", deviceId, "install", "-r", path)
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// auditLogMu serialises appends to audit.log
+var auditLogMu sync.Mutex
+
+func (a *App) getAuditLogPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "audit.log")
+}
+
+// appendAuditLog adds one JSON line to audit.log. The log is append-only; failures are
+// reported to the runtime log rather than to the caller.
+func (a *App) appendAuditLog(entry AuditEntry) {
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	auditLogMu.Lock()
+	defer auditLogMu.Unlock()
+	f, err := os.OpenFile(a.getAuditLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		a.Log("Failed to write audit log: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		a.Log("Failed to write audit log: %v", err)
+	}
+}
+
+// GetAuditLog returns the most recent audit entries, newest first; limit <= 0 returns all
+func (a *App) GetAuditLog(limit int) []AuditEntry {
+	auditLogMu.Lock()
+	defer auditLogMu.Unlock()
+
+	result := []AuditEntry{}
+	f, err := os.Open(a.getAuditLogPath())
+	if err != nil {
+		return result
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			result = append(result, entry)
+		}
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,12 +21,19 @@ import (
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+const (
+	// maxPlaybackSpeed bounds the playback speed multiplier
+	maxPlaybackSpeed = 10.0
+	// minSwipeDurationMs keeps sped-up swipes from degenerating into taps
+	minSwipeDurationMs = 50
+)
+
 // Touch recording state management
 var (
-	touchRecordCmd    = make(map[string]*exec.Cmd)
-	touchRecordCancel = make(map[string]context.CancelFunc)
-	touchRecordData   = make(map[string]*TouchRecordingSession)
-	touchRecordMu     sync.Mutex
+	touchRecordStreams = make(map[string]*geteventStream)
+	touchRecordCancel  = make(map[string]context.CancelFunc)
+	touchRecordData    = make(map[string]*TouchRecordingSession)
+	touchRecordMu      sync.Mutex
 
 	touchPlaybackCancel = make(map[string]context.CancelFunc)
 	touchPlaybackMu     sync.Mutex
@@ -31,6 +41,8 @@ var (
 	taskPauseSignal = make(map[string]chan struct{})
 	taskIsPaused    = make(map[string]bool)
 	taskPauseMu     sync.Mutex
+	// Devices whose playback pauses before every event (step-through mode)
+	touchPlaybackStepping = make(map[string]bool)
 
 	// UI hierarchy cache for recording (to avoid excessive dumps)
 	uiHierarchyCache       = make(map[string]*cachedUIHierarchy)
@@ -146,6 +158,36 @@ func (a *App) GetTouchInputDevice(deviceId string) (string, error) {
 	return "", fmt.Errorf("no touch input device found")
 }
 
+// getTouchAxisRange reads the raw ABS_MT_POSITION_X/Y ranges of a touch input device.
+// Zeros are returned when the ranges cannot be read.
+func (a *App) getTouchAxisRange(deviceId, inputDevice string) (minX, maxX, minY, maxY int) {
+	propsCmd := fmt.Sprintf("shell getevent -p %s", inputDevice)
+	propsOutput, err := a.RunAdbCommand(deviceId, propsCmd)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(propsOutput, "\n")
+	// Regex to match "min 0, max 1079"
+	re := regexp.MustCompile(`min\s+(-?\d+),\s+max\s+(-?\d+)`)
+
+	for _, line := range lines {
+		if strings.Contains(line, "ABS_MT_POSITION_X") || strings.Contains(line, "0035") {
+			if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
+				minX, _ = strconv.Atoi(matches[1])
+				maxX, _ = strconv.Atoi(matches[2])
+			}
+		}
+		if strings.Contains(line, "ABS_MT_POSITION_Y") || strings.Contains(line, "0036") {
+			if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
+				minY, _ = strconv.Atoi(matches[1])
+				maxY, _ = strconv.Atoi(matches[2])
+			}
+		}
+	}
+	return
+}
+
 // GetDeviceResolution gets the screen resolution of the device
 func (a *App) GetDeviceResolution(deviceId string) (string, error) {
 	output, err := a.RunAdbCommand(deviceId, "shell wm size")
@@ -163,13 +205,14 @@ func (a *App) GetDeviceResolution(deviceId string) (string, error) {
 	return "1080x1920", nil // Default fallback
 }
 
-// StartTouchRecording starts recording touch events from the device
-func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
+// StartTouchRecording starts recording touch events from the device. opts filters out
+// accidental touches such as edge grazes when the recording is parsed.
+func (a *App) StartTouchRecording(deviceId string, recordingMode string, opts RecordingOptions) error {
 	touchRecordMu.Lock()
 	defer touchRecordMu.Unlock()
 
 	// Check if already recording
-	if _, exists := touchRecordCmd[deviceId]; exists {
+	if _, exists := touchRecordStreams[deviceId]; exists {
 		return fmt.Errorf("already recording on this device")
 	}
 
@@ -183,73 +226,28 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 	// Get resolution for coordinate scaling later
 	resolution, _ := a.GetDeviceResolution(deviceId)
 	fmt.Printf("[Automation] Device resolution: %s\n", resolution)
+	var orientation *int
+	if rotation, err := a.getDisplayRotation(deviceId); err == nil {
+		orientation = &rotation
+	}
 
 	// Create context for cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Start getevent command for specific device
-	// Run getevent -lt /dev/input/eventX
-	cmd := exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "shell", "getevent", "-lt", inputDevice)
-
-	// Create a pipe to read output
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		cancel()
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
-
-	// Also capture stderr
-	stderr, err := cmd.StderrPipe()
+	// Start getevent for the specific device, checking it's alive before reporting success
+	stream, err := a.startGetevent(ctx, deviceId, inputDevice, "touch-recording", cancel)
 	if err != nil {
 		cancel()
-		return fmt.Errorf("failed to create stderr pipe: %w", err)
+		return fmt.Errorf("touch recording could not start: %w", err)
 	}
-
-	if err := cmd.Start(); err != nil {
-		cancel()
-		return fmt.Errorf("failed to start getevent: %w", err)
-	}
-
-	fmt.Printf("[Automation] getevent process started, PID: %d, listening on %s\n", cmd.Process.Pid, inputDevice)
-
-	// Log stderr in background
-	go func() {
-		stderrScanner := bufio.NewScanner(stderr)
-		for stderrScanner.Scan() {
-			fmt.Printf("[Automation] stderr: %s\n", stderrScanner.Text())
-		}
-	}()
+	fmt.Printf("[Automation] getevent process started, PID: %d, listening on %s\n", stream.cmd.Process.Pid, inputDevice)
 
 	// Get device min/max coordinates
-	maxX, maxY := 0, 0
-	minX, minY := 0, 0
-
-	propsCmd := fmt.Sprintf("shell getevent -p %s", inputDevice)
-	propsOutput, err := a.RunAdbCommand(deviceId, propsCmd)
-	if err == nil {
-		lines := strings.Split(propsOutput, "\n")
-		// Regex to match "min 0, max 1079"
-		re := regexp.MustCompile(`min\s+(-?\d+),\s+max\s+(-?\d+)`)
-
-		for _, line := range lines {
-			if strings.Contains(line, "ABS_MT_POSITION_X") || strings.Contains(line, "0035") {
-				if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
-					minX, _ = strconv.Atoi(matches[1])
-					maxX, _ = strconv.Atoi(matches[2])
-				}
-			}
-			if strings.Contains(line, "ABS_MT_POSITION_Y") || strings.Contains(line, "0036") {
-				if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
-					minY, _ = strconv.Atoi(matches[1])
-					maxY, _ = strconv.Atoi(matches[2])
-				}
-			}
-		}
-	}
+	minX, maxX, minY, maxY := a.getTouchAxisRange(deviceId, inputDevice)
 	fmt.Printf("[Automation] Touch device coords detected: X[%d, %d], Y[%d, %d]\n", minX, maxX, minY, maxY)
 
 	// Store recording state
-	touchRecordCmd[deviceId] = cmd
+	touchRecordStreams[deviceId] = stream
 	touchRecordCancel[deviceId] = cancel
 
 	// Default to fast mode if not specified
@@ -268,9 +266,32 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 		MinX:          minX,
 		MinY:          minY,
 		RecordingMode: recordingMode,
+		Orientation:   orientation,
 		IsPaused:      false,
+		Options:       opts,
+	}
+	if opts.ShowTouches {
+		touchRecordData[deviceId].restoreShowTouches = a.enableShowTouches(deviceId)
+	}
+	var snapshot *recordingSnapshot
+	if recordingMode != "precise" && !opts.DisableSelectorCapture {
+		snapshot = &recordingSnapshot{}
+		touchRecordData[deviceId].snapshot = snapshot
+		a.refreshRecordingSnapshot(deviceId, snapshot)
 	}
 
+	// Hardware keys arrive on their own input devices
+	a.startKeyRecording(ctx, deviceId, inputDevice, stream.numeric)
+
+	// Note which activity each touch lands on
+	go a.watchFocusedComponent(ctx, deviceId, func(component string) {
+		touchRecordMu.Lock()
+		defer touchRecordMu.Unlock()
+		if session, ok := touchRecordData[deviceId]; ok {
+			session.activityMarks = append(session.activityMarks, activityMark{RawIndex: len(session.RawEvents), Component: component})
+		}
+	})
+
 	// Pre-capture UI hierarchy in precise mode so the first action has a snapshot
 	if recordingMode == "precise" {
 		go func() {
@@ -297,18 +318,17 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 
 	// Start goroutine to read events
 	go func() {
-		scanner := bufio.NewScanner(stdout)
 		lineCount := 0
 		capturedCount := 0
 
 		// Track current touch position for element capture
 		var currentTouchX, currentTouchY int = -1, -1
 		var touchActive bool = false
+		var touchDownAt time.Time
 
 		fmt.Printf("[Automation] Listening for events from: %s\n", inputDevice)
 
-		for scanner.Scan() {
-			line := scanner.Text()
+		for line := range stream.lines {
 			lineCount++
 
 			// Debug: print first few lines to see what we're getting
@@ -354,6 +374,9 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 				// Detect touch down
 				if (strings.Contains(line, "BTN_TOUCH") && (strings.Contains(line, "DOWN") || strings.HasSuffix(strings.TrimSpace(line), "00000001"))) ||
 					(strings.Contains(line, "ABS_MT_TRACKING_ID") && !strings.Contains(strings.ToLower(line), "ffffffff")) {
+					if !touchActive {
+						touchDownAt = time.Now()
+					}
 					touchActive = true
 				}
 
@@ -452,9 +475,11 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 								})
 								fmt.Printf("[Automation] Recording paused for selector choice\n")
 							}(scaledX, scaledY, len(sess.ElementInfos), time.Now())
+						} else if snapshot != nil {
+							// Fast mode: never blocks; the selector comes from a background dump
+							// taken before the touch, when there is one
+							a.recordTapElement(deviceId, snapshot, scaledX, scaledY, touchDownAt)
 						} else {
-							// Fast mode: strictly coordinates only.
-							// Do NOT capture element info to ensure zero latency and pure coordinate playback.
 							fmt.Printf("[Automation] Fast mode: recording coordinate (%d,%d) only\n", scaledX, scaledY)
 						}
 					}
@@ -466,8 +491,12 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 			}
 		}
 		fmt.Printf("[Automation] Scanner finished: %d lines read, %d events captured\n", lineCount, capturedCount)
-		if err := scanner.Err(); err != nil {
-			fmt.Printf("[Automation] Scanner error: %v\n", err)
+		if stream.err != nil {
+			a.Log("Touch recording on %s lost its event stream: %v", deviceId, stream.err)
+			wailsRuntime.EventsEmit(a.ctx, "touch-record-error", map[string]interface{}{
+				"deviceId": deviceId,
+				"error":    stream.err.Error(),
+			})
 		}
 	}()
 
@@ -481,12 +510,13 @@ func (a *App) StartTouchRecording(deviceId string, recordingMode string) error {
 	return nil
 }
 
-// StopTouchRecording stops recording and returns the parsed touch script
-func (a *App) StopTouchRecording(deviceId string) (*TouchScript, error) {
+// StopTouchRecording stops recording and returns the parsed touch script. Zero fields in
+// classifier fall back to the saved default thresholds.
+func (a *App) StopTouchRecording(deviceId string, classifier ClassifierConfig) (*TouchScript, error) {
 	// First, get the cancel function and command without holding the lock
 	touchRecordMu.Lock()
 	cancel, exists := touchRecordCancel[deviceId]
-	cmd := touchRecordCmd[deviceId]
+	stream := touchRecordStreams[deviceId]
 	touchRecordMu.Unlock()
 
 	if !exists {
@@ -498,9 +528,10 @@ func (a *App) StopTouchRecording(deviceId string) (*TouchScript, error) {
 
 	// Wait for process to finish - don't hold the lock here!
 	// This allows the reading goroutine to finish processing remaining events
-	if cmd != nil {
-		_ = cmd.Wait()
+	if stream != nil {
+		stream.wait()
 	}
+	a.stopKeyRecording(deviceId)
 
 	// Give the reading goroutine a moment to finish processing
 	time.Sleep(100 * time.Millisecond)
@@ -517,8 +548,11 @@ func (a *App) StopTouchRecording(deviceId string) (*TouchScript, error) {
 
 	fmt.Printf("[Automation] StopRecording: got %d raw events\n", len(session.RawEvents))
 
+	// Interleave the touch and key streams before parsing
+	session.RawEvents = mergeEventStreams(session.RawEvents)
+
 	// Parse raw events into TouchScript
-	script := a.parseRawEvents(session)
+	script := a.parseRawEvents(session, classifier.withDefaults(a.GetClassifierConfig()))
 
 	// Enrich with device model info
 	info, err := a.GetDeviceInfo(deviceId)
@@ -527,9 +561,16 @@ func (a *App) StopTouchRecording(deviceId string) (*TouchScript, error) {
 	}
 
 	// Cleanup
-	delete(touchRecordCmd, deviceId)
+	delete(touchRecordStreams, deviceId)
 	delete(touchRecordCancel, deviceId)
 	delete(touchRecordData, deviceId)
+	if session.restoreShowTouches {
+		go func() {
+			if _, err := a.SetDeveloperToggle(deviceId, "show_touches", false); err != nil {
+				a.Log("Could not restore show taps on %s: %v", deviceId, err)
+			}
+		}()
+	}
 
 	// Clear UI hierarchy cache for this device
 	uiHierarchyCacheMu.Lock()
@@ -545,11 +586,50 @@ func (a *App) StopTouchRecording(deviceId string) (*TouchScript, error) {
 	return script, nil
 }
 
+// defaultClassifierConfig matches the thresholds recordings have always been classified with
+var defaultClassifierConfig = ClassifierConfig{LongPressMs: 500, TapMaxDistance: 50, DoubleTapMs: 300, DoubleTapDistance: 50}
+
+// withDefaults fills zero fields from base
+func (c ClassifierConfig) withDefaults(base ClassifierConfig) ClassifierConfig {
+	if c.LongPressMs <= 0 {
+		c.LongPressMs = base.LongPressMs
+	}
+	if c.TapMaxDistance <= 0 {
+		c.TapMaxDistance = base.TapMaxDistance
+	}
+	if c.DoubleTapMs == 0 {
+		c.DoubleTapMs = base.DoubleTapMs
+	}
+	if c.DoubleTapDistance <= 0 {
+		c.DoubleTapDistance = base.DoubleTapDistance
+	}
+	return c
+}
+
+// GetClassifierConfig returns the default touch classification thresholds
+func (a *App) GetClassifierConfig() ClassifierConfig {
+	a.classifierMu.Lock()
+	defer a.classifierMu.Unlock()
+	return a.classifierConfig.withDefaults(defaultClassifierConfig)
+}
+
+// SetClassifierConfig saves new default touch classification thresholds
+func (a *App) SetClassifierConfig(config ClassifierConfig) error {
+	if config.LongPressMs < 0 || config.TapMaxDistance < 0 || config.DoubleTapDistance < 0 {
+		return fmt.Errorf("thresholds must not be negative")
+	}
+	a.classifierMu.Lock()
+	a.classifierConfig = config
+	a.classifierMu.Unlock()
+	go a.saveSettings()
+	return nil
+}
+
 // IsRecordingTouch returns whether touch recording is active for a device
 func (a *App) IsRecordingTouch(deviceId string) bool {
 	touchRecordMu.Lock()
 	defer touchRecordMu.Unlock()
-	_, exists := touchRecordCmd[deviceId]
+	_, exists := touchRecordStreams[deviceId]
 	return exists
 }
 
@@ -572,30 +652,7 @@ func (a *App) PickPointOnScreen(deviceId string, timeoutSeconds int) (map[string
 	}
 
 	// Get min/max coordinates for the touch device
-	maxX, maxY := 0, 0
-	minX, minY := 0, 0
-
-	propsCmd := fmt.Sprintf("shell getevent -p %s", inputDevice)
-	propsOutput, err := a.RunAdbCommand(deviceId, propsCmd)
-	if err == nil {
-		lines := strings.Split(propsOutput, "\n")
-		re := regexp.MustCompile(`min\s+(-?\d+),\s+max\s+(-?\d+)`)
-
-		for _, line := range lines {
-			if strings.Contains(line, "ABS_MT_POSITION_X") || strings.Contains(line, "0035") {
-				if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
-					minX, _ = strconv.Atoi(matches[1])
-					maxX, _ = strconv.Atoi(matches[2])
-				}
-			}
-			if strings.Contains(line, "ABS_MT_POSITION_Y") || strings.Contains(line, "0036") {
-				if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
-					minY, _ = strconv.Atoi(matches[1])
-					maxY, _ = strconv.Atoi(matches[2])
-				}
-			}
-		}
-	}
+	minX, maxX, minY, maxY := a.getTouchAxisRange(deviceId, inputDevice)
 
 	// Default timeout 30 seconds
 	if timeoutSeconds <= 0 {
@@ -616,6 +673,8 @@ func (a *App) PickPointOnScreen(deviceId string, timeoutSeconds int) (map[string
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start getevent: %w", err)
 	}
+	a.trackProcess("point-picker", cmd, cancel)
+	defer a.untrackProcess(cmd)
 
 	// Emit event to notify frontend that we're waiting
 	wailsRuntime.EventsEmit(a.ctx, "point-picker-started", map[string]interface{}{
@@ -753,12 +812,13 @@ func (a *App) GetRecordingEventCount(deviceId string) int {
 }
 
 // parseRawEvents converts raw getevent output into TouchScript
-func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
+func (a *App) parseRawEvents(session *TouchRecordingSession, cfg ClassifierConfig) *TouchScript {
 	script := &TouchScript{
-		DeviceID:   session.DeviceID,
-		Resolution: session.Resolution,
-		CreatedAt:  session.StartTime.Format(time.RFC3339),
-		Events:     make([]TouchEvent, 0),
+		DeviceID:    session.DeviceID,
+		Resolution:  session.Resolution,
+		Orientation: session.Orientation,
+		CreatedAt:   session.StartTime.Format(time.RFC3339),
+		Events:      make([]TouchEvent, 0),
 	}
 
 	fmt.Printf("[Automation] Parsing %d raw events, %d element infos captured\n", len(session.RawEvents), len(session.ElementInfos))
@@ -767,23 +827,32 @@ func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
 		return script
 	}
 
-	// Helper function to find element info by coordinates (with tolerance)
+	// Helper function to find element info by coordinates (with tolerance). Each info is used
+	// once, so repeated taps on the same spot across screens each get their own element.
+	usedInfos := make(map[int]bool)
 	findElementInfo := func(x, y int) *ElementInfo {
 		tolerance := 50 // pixels tolerance for matching
-		var bestMatch *ElementInfo
+		best := -1
 		bestDist := tolerance * tolerance * 2 // max distance squared
 
 		for i := range session.ElementInfos {
+			if usedInfos[i] {
+				continue
+			}
 			info := &session.ElementInfos[i]
 			dx := info.X - x
 			dy := info.Y - y
 			dist := dx*dx + dy*dy
 			if dist < bestDist {
 				bestDist = dist
-				bestMatch = info
+				best = i
 			}
 		}
-		return bestMatch
+		if best < 0 {
+			return nil
+		}
+		usedInfos[best] = true
+		return &session.ElementInfos[best]
 	}
 
 	// Parse resolution for coordinate scaling
@@ -800,7 +869,7 @@ func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
 	// Regular expression to parse getevent lines
 	// Format: [ 1234.567890] EV_ABS       ABS_MT_POSITION_X    00000500
 	// We need to be flexible with whitespace
-	re := regexp.MustCompile(`\[\s*([\d.]+)\].*?(EV_\w+)\s+(\w+)\s+([0-9a-fA-F]+|DOWN|UP)`)
+	re := regexp.MustCompile(`\[\s*([\d.]+)\].*?(EV_\w+)\s+(\w+)\s+(DOWN|UP|[0-9a-fA-F]+)\b`)
 
 	// Use stored max coordinates, default to screen parsing if missing (though they shouldn't be)
 	var maxX, maxY int = session.MaxX, session.MaxY
@@ -822,12 +891,220 @@ func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
 	var firstTimestamp float64 = -1
 	var lastEventTimestamp float64 = -1
 	var totalAdjustment float64 = 0
-	var currentX, currentY int = -1, -1
-	var touchStartTime float64 = -1
-	var touchStartX, touchStartY int = -1, -1
-	var tracking bool = false
 
-	for _, line := range session.RawEvents {
+	// Per-slot state for multi-touch (Input Protocol Type B). Devices that never report
+	// ABS_MT_SLOT put everything in slot 0, which keeps single-touch behaviour unchanged.
+	type touchSlot struct {
+		active             bool
+		startTime          float64
+		startX, startY     int
+		currentX, currentY int
+	}
+	slots := make(map[int]*touchSlot)
+	slotFor := func(id int) *touchSlot {
+		s, ok := slots[id]
+		if !ok {
+			s = &touchSlot{startX: -1, startY: -1, currentX: -1, currentY: -1}
+			slots[id] = s
+		}
+		return s
+	}
+	currentSlot := 0
+
+	// The activity in front when the current line was recorded, and when the gesture began
+	currentActivity, gestureActivity := "", ""
+	nextMark := 0
+
+	// A gesture runs from the first finger down until every finger is lifted
+	var gestureStart float64 = -1
+	var gestureFingers []TouchPointer
+	activeCount := 0
+	discarded := 0
+
+	// Helper for proper rounding: int(val + 0.5)
+	round := func(val float64) int {
+		return int(val + 0.5)
+	}
+
+	// getevent timestamps are float seconds; truncating would turn 0.2s into 199ms
+	toMs := func(seconds float64) int64 {
+		return int64(math.Round(seconds * 1000))
+	}
+
+	// Scale coordinates using floating point arithmetic to avoid precision loss
+	// Formula: screen_x = (raw_x - min_raw_x) * screen_width / (max_raw_x - min_raw_x)
+	scalePoint := func(rawX, rawY int) (int, int) {
+		x, y := rawX, rawY
+		if maxX > minX {
+			x = round(float64(rawX-minX) * float64(screenW) / float64(maxX-minX+1))
+		}
+		if maxY > minY {
+			y = round(float64(rawY-minY) * float64(screenH) / float64(maxY-minY+1))
+		}
+		return x, y
+	}
+
+	fingerDown := func(s *touchSlot, timestamp float64) {
+		s.active = true
+		s.startTime = timestamp
+		// Reset start coords to detect if they change in this stroke
+		s.startX = -1
+		s.startY = -1
+		if activeCount == 0 {
+			gestureStart = timestamp
+			gestureFingers = gestureFingers[:0]
+			gestureActivity = currentActivity
+		}
+		activeCount++
+	}
+
+	fingerUp := func(s *touchSlot, timestamp float64, relativeMs int64) {
+		s.active = false
+		activeCount--
+
+		// If start coords were never updated in this stroke, it means
+		// they didn't change from the previous state (Input Protocol Type B)
+		// So use the current state as the start.
+		if s.startX == -1 {
+			s.startX = s.currentX
+		}
+		if s.startY == -1 {
+			s.startY = s.currentY
+		}
+
+		// Ensure we have valid coordinates before using the stroke
+		if s.startX == -1 || s.startY == -1 || s.currentX == -1 || s.currentY == -1 {
+			fmt.Printf("[Automation] Warning: Skipping stroke with invalid coords: Start(%d,%d) End(%d,%d)\n",
+				s.startX, s.startY, s.currentX, s.currentY)
+		} else {
+			finger := TouchPointer{
+				Delay:    int(toMs(s.startTime - gestureStart)),
+				Duration: int(toMs(timestamp - s.startTime)),
+			}
+			finger.X, finger.Y = scalePoint(s.startX, s.startY)
+			finger.X2, finger.Y2 = scalePoint(s.currentX, s.currentY)
+			if reason := session.Options.rejectStroke(finger); reason != "" {
+				discarded++
+				fmt.Printf("[Automation] Discarding stroke at (%d,%d): %s\n", finger.X, finger.Y, reason)
+			} else {
+				gestureFingers = append(gestureFingers, finger)
+			}
+		}
+
+		if activeCount > 0 || len(gestureFingers) == 0 {
+			return
+		}
+
+		event := TouchEvent{
+			Timestamp: relativeMs,
+			Activity:  gestureActivity,
+		}
+
+		if len(gestureFingers) >= 2 {
+			// Several fingers were down at once: keep every finger's path so the
+			// gesture can be replayed instead of collapsing into a bogus swipe
+			event.Type = "pinch"
+			event.X, event.Y = gestureFingers[0].X, gestureFingers[0].Y
+			event.X2, event.Y2 = gestureFingers[0].X2, gestureFingers[0].Y2
+			event.Duration = int(toMs(timestamp - gestureStart))
+			event.Fingers = append([]TouchPointer(nil), gestureFingers...)
+			script.Events = append(script.Events, event)
+			return
+		}
+
+		finger := gestureFingers[0]
+		dx := finger.X2 - finger.X
+		dy := finger.Y2 - finger.Y
+		distance := dx*dx + dy*dy
+
+		// A press that stays within TapMaxDistance is a tap, or a long press once held for
+		// LongPressMs; anything that travels further is a swipe, however slow
+		tapMax := cfg.TapMaxDistance * cfg.TapMaxDistance
+		if distance < tapMax && finger.Duration >= cfg.LongPressMs {
+			// Long press: held for significant time (even with minor drift)
+			event.Type = "long_press"
+			event.X = finger.X
+			event.Y = finger.Y
+			event.Duration = finger.Duration
+		} else if distance < tapMax {
+			// Tap: quick touch with minimal movement
+			event.Type = "tap"
+			event.X = finger.X
+			event.Y = finger.Y
+		} else {
+			// Swipe: significant movement
+			event.Type = "swipe"
+			event.X = finger.X
+			event.Y = finger.Y
+			event.X2 = finger.X2
+			event.Y2 = finger.Y2
+			event.Duration = finger.Duration
+		}
+
+		// Look up element info for this touch event
+		if elemInfo := findElementInfo(event.X, event.Y); elemInfo != nil {
+			event.Selector = elemInfo.Selector
+			event.ElementText = elemInfo.Text
+			event.ElementID = elemInfo.ResourceID
+		}
+
+		// A second quick tap close to the previous one turns it into a double tap
+		if event.Type == "tap" && cfg.DoubleTapMs > 0 && len(script.Events) > 0 {
+			prev := &script.Events[len(script.Events)-1]
+			gap := relativeMs - int64(finger.Duration) - prev.Timestamp
+			ddx, ddy := event.X-prev.X, event.Y-prev.Y
+			if prev.Type == "tap" && gap <= int64(cfg.DoubleTapMs) &&
+				ddx*ddx+ddy*ddy <= cfg.DoubleTapDistance*cfg.DoubleTapDistance {
+				prev.Type = "double_tap"
+				return
+			}
+		}
+
+		script.Events = append(script.Events, event)
+	}
+
+	var rawEvents []RawInputEvent
+
+	// Key presses are emitted on release so a held key can be replayed as a long press
+	type keyPress struct {
+		timestamp  float64
+		relativeMs int64
+	}
+	keysDown := make(map[string]keyPress)
+	keyChanged := func(label string, value int32, timestamp float64, relativeMs int64) {
+		switch value {
+		case 1:
+			if _, held := keysDown[label]; !held {
+				keysDown[label] = keyPress{timestamp: timestamp, relativeMs: relativeMs}
+			}
+		case 0:
+			press, held := keysDown[label]
+			if !held {
+				return
+			}
+			delete(keysDown, label)
+			keyCode, known := linuxKeyToAndroid[label]
+			if !known {
+				fmt.Printf("[Automation] Skipping unmapped key %s\n", label)
+				return
+			}
+			event := TouchEvent{
+				Timestamp: press.relativeMs,
+				Type:      "key",
+				KeyCode:   keyCode,
+			}
+			if held := int(toMs(timestamp - press.timestamp)); held >= 500 {
+				event.Duration = held
+			}
+			script.Events = append(script.Events, event)
+		}
+	}
+
+	for lineIndex, line := range session.RawEvents {
+		for nextMark < len(session.activityMarks) && session.activityMarks[nextMark].RawIndex <= lineIndex {
+			currentActivity = session.activityMarks[nextMark].Component
+			nextMark++
+		}
 		matches := re.FindStringSubmatch(line)
 		if len(matches) < 5 {
 			continue
@@ -850,7 +1127,7 @@ func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
 		}
 		lastEventTimestamp = timestamp
 
-		relativeMs := int64((timestamp - firstTimestamp - totalAdjustment) * 1000)
+		relativeMs := toMs(timestamp - firstTimestamp - totalAdjustment)
 
 		// Handle special value cases like UP/DOWN for BTN_TOUCH
 		if evValue == "DOWN" {
@@ -859,229 +1136,89 @@ func (a *App) parseRawEvents(session *TouchRecordingSession) *TouchScript {
 			evValue = "00000000"
 		}
 
-		if evType == "EV_ABS" {
-			// Parse as unsigned 32-bit int first, then convert to signed int32
-			// This handles -1 (0xffffffff) correctly -> -1
-			uValue, err := strconv.ParseUint(evValue, 16, 32)
-			if err != nil {
-				continue
-			}
-			value := int32(uValue)
-
-			switch evCode {
-			case "ABS_MT_TRACKING_ID":
-				// Tracking ID -1 (0xffffffff) means finger up
-				if value != -1 && !tracking {
-					// Finger down - Start of new stroke
-					tracking = true
-					touchStartTime = timestamp
-					// Reset start coords to detect if they change in this stroke
-					touchStartX = -1
-					touchStartY = -1
-				} else if value == -1 && tracking {
-					// Finger up - End of stroke
-					tracking = false
-					duration := int((timestamp - touchStartTime) * 1000)
-
-					// If start coords were never updated in this stroke, it means
-					// they didn't change from the previous state (Input Protocol Type B)
-					// So use the current state as the start.
-					if touchStartX == -1 {
-						touchStartX = currentX
-					}
-					if touchStartY == -1 {
-						touchStartY = currentY
-					}
-
-					// Ensure we have valid coordinates before emitting
-					if touchStartX == -1 || touchStartY == -1 || currentX == -1 || currentY == -1 {
-						fmt.Printf("[Automation] Warning: Skipping event with invalid coords: Start(%d,%d) End(%d,%d)\n",
-							touchStartX, touchStartY, currentX, currentY)
-						continue
-					}
-
-					// Scale coordinates using floating point arithmetic to avoid precision loss
-					// Formula: screen_x = (raw_x - min_raw_x) * screen_width / (max_raw_x - min_raw_x)
-					var scaledStartX, scaledStartY, scaledEndX, scaledEndY int
-
-					// Helper for proper rounding: int(val + 0.5)
-					round := func(val float64) int {
-						return int(val + 0.5)
-					}
-
-					if maxX > minX {
-						width := float64(maxX - minX + 1)
-						scaledStartX = round(float64(touchStartX-minX) * float64(screenW) / width)
-						scaledEndX = round(float64(currentX-minX) * float64(screenW) / width)
-					} else {
-						scaledStartX = touchStartX
-						scaledEndX = currentX
-					}
-
-					if maxY > minY {
-						height := float64(maxY - minY + 1)
-						scaledStartY = round(float64(touchStartY-minY) * float64(screenH) / height)
-						scaledEndY = round(float64(currentY-minY) * float64(screenH) / height)
-					} else {
-						scaledStartY = touchStartY
-						scaledEndY = currentY
-					}
-
-					// Debug log for coordinate mapping verification
-					// fmt.Printf("[Automation] Coord mapping: Raw(%d,%d) -> Screen(%d,%d) [Max: %dx%d -> %dx%d]\n",
-					// 	touchStartX, touchStartY, scaledStartX, scaledStartY, maxX, maxY, screenW, screenH)
-
-					// Calculate distance
-					dx := scaledEndX - scaledStartX
-					dy := scaledEndY - scaledStartY
-					distance := dx*dx + dy*dy
-
-					event := TouchEvent{
-						Timestamp: relativeMs,
-					}
-
-					// Distance threshold: 50px movement (50*50=2500)
-					// Duration threshold: 500ms for long press
-					// Prioritize duration over distance to avoid misclassifying long presses as swipes
-					if duration >= 500 {
-						// Long press: held for significant time (even with minor drift)
-						event.Type = "long_press"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-						event.Duration = duration
-					} else if distance < 2500 {
-						// Tap: quick touch with minimal movement
-						event.Type = "tap"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-					} else {
-						// Swipe: significant movement in short time
-						event.Type = "swipe"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-						event.X2 = scaledEndX
-						event.Y2 = scaledEndY
-						event.Duration = duration
-					}
-
-					// Look up element info for this touch event
-					if elemInfo := findElementInfo(event.X, event.Y); elemInfo != nil {
-						event.Selector = elemInfo.Selector
-					}
-
-					script.Events = append(script.Events, event)
-				}
-
-			case "BTN_TOUCH":
-				// Support for older devices or single-touch screens (Protocol A)
-				// Value 1 = Down, 0 = Up
-				if value == 1 && !tracking {
-					// Finger down
-					tracking = true
-					touchStartTime = timestamp
-					touchStartX = -1
-					touchStartY = -1
-				} else if value == 0 && tracking {
-					// Finger up
-					tracking = false
-					duration := int((timestamp - touchStartTime) * 1000)
-
-					// Fallback for coordinates if not updated
-					if touchStartX == -1 {
-						touchStartX = currentX
-					}
-					if touchStartY == -1 {
-						touchStartY = currentY
-					}
-
-					if touchStartX == -1 || touchStartY == -1 || currentX == -1 || currentY == -1 {
-						continue
-					}
+		// Parse as unsigned 32-bit int first, then convert to signed int32
+		// This handles -1 (0xffffffff) correctly -> -1
+		uValue, err := strconv.ParseUint(evValue, 16, 32)
+		if err != nil {
+			continue
+		}
+		value := int32(uValue)
 
-					// Shared logic for event generation...
-					// To avoid code duplication, we could refactor, but for this specific tool usage
-					// we will duplicate the scaling and event creation logic for stability.
+		// Hardware keys become "key" events whichever device reported them
+		if evType == "EV_KEY" && strings.HasPrefix(evCode, "KEY_") {
+			keyChanged(evCode, value, timestamp, relativeMs)
+			continue
+		}
 
-					var scaledStartX, scaledStartY, scaledEndX, scaledEndY int
+		// Anything else from the key devices is not touch input
+		if m := rawEventDeviceRegex.FindStringSubmatch(line); m != nil && m[1] != session.InputDevice {
+			continue
+		}
 
-					// Helper for proper rounding
-					round := func(val float64) int { return int(val + 0.5) }
+		// Keep the unsimplified stream for sendevent replay
+		if rawEvent, ok := newRawInputEvent(relativeMs, evType, evCode, value); ok {
+			rawEvents = append(rawEvents, rawEvent)
+		}
 
-					if maxX > minX {
-						width := float64(maxX - minX + 1)
-						scaledStartX = round(float64(touchStartX-minX) * float64(screenW) / width)
-						scaledEndX = round(float64(currentX-minX) * float64(screenW) / width)
-					} else {
-						scaledStartX = touchStartX
-						scaledEndX = currentX
-					}
+		if evType != "EV_ABS" && evType != "EV_KEY" {
+			continue
+		}
+		slot := slotFor(currentSlot)
 
-					if maxY > minY {
-						height := float64(maxY - minY + 1)
-						scaledStartY = round(float64(touchStartY-minY) * float64(screenH) / height)
-						scaledEndY = round(float64(currentY-minY) * float64(screenH) / height)
-					} else {
-						scaledStartY = touchStartY
-						scaledEndY = currentY
-					}
+		switch evCode {
+		case "ABS_MT_SLOT":
+			currentSlot = int(value)
 
-					dx := scaledEndX - scaledStartX
-					dy := scaledEndY - scaledStartY
-					distance := dx*dx + dy*dy
+		case "ABS_MT_TRACKING_ID":
+			// Tracking ID -1 (0xffffffff) means finger up
+			if value != -1 && !slot.active {
+				fingerDown(slot, timestamp)
+			} else if value == -1 && slot.active {
+				fingerUp(slot, timestamp, relativeMs)
+			}
 
-					event := TouchEvent{
-						Timestamp: relativeMs,
-					}
+		case "BTN_TOUCH":
+			// Support for older devices or single-touch screens (Protocol A)
+			// Value 1 = Down, 0 = Up. On Type B devices the tracking ID has
+			// already opened/closed the slot, so this is a no-op there.
+			if value == 1 && activeCount == 0 {
+				fingerDown(slot, timestamp)
+			} else if value == 0 && slot.active {
+				fingerUp(slot, timestamp, relativeMs)
+			}
 
-					// Distance threshold: 50px movement (50*50=2500)
-					// Duration threshold: 500ms for long press
-					// Prioritize duration over distance to avoid misclassifying long presses as swipes
-					if duration >= 500 {
-						// Long press: held for significant time (even with minor drift)
-						event.Type = "long_press"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-						event.Duration = duration
-					} else if distance < 2500 {
-						// Tap: quick touch with minimal movement
-						event.Type = "tap"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-					} else {
-						// Swipe: significant movement in short time
-						event.Type = "swipe"
-						event.X = scaledStartX
-						event.Y = scaledStartY
-						event.X2 = scaledEndX
-						event.Y2 = scaledEndY
-						event.Duration = duration
-					}
+		case "ABS_MT_POSITION_X":
+			// Some devices only report changes.
+			slot.currentX = int(value)
+			if slot.active && slot.startX == -1 {
+				slot.startX = slot.currentX
+			}
 
-					// Look up element info for this touch event
-					if elemInfo := findElementInfo(event.X, event.Y); elemInfo != nil {
-						event.Selector = elemInfo.Selector
-					}
+		case "ABS_MT_POSITION_Y":
+			slot.currentY = int(value)
+			if slot.active && slot.startY == -1 {
+				slot.startY = slot.currentY
+			}
+		}
+	}
 
-					script.Events = append(script.Events, event)
-				}
+	if discarded > 0 {
+		fmt.Printf("[Automation] Discarded %d accidental strokes\n", discarded)
+	}
 
-			case "ABS_MT_POSITION_X":
-				// Some devices only report changes.
-				currentX = int(value)
-				if tracking {
-					if touchStartX == -1 {
-						touchStartX = currentX
-					}
-				}
+	// Keys are stamped at press time but emitted on release, so restore time order
+	sort.SliceStable(script.Events, func(i, j int) bool {
+		return script.Events[i].Timestamp < script.Events[j].Timestamp
+	})
 
-			case "ABS_MT_POSITION_Y":
-				currentY = int(value)
-				if tracking {
-					if touchStartY == -1 {
-						touchStartY = currentY
-					}
-				}
-			}
+	if len(rawEvents) > 0 {
+		script.RawInput = &RawTouchInput{
+			Device: session.InputDevice,
+			MinX:   session.MinX,
+			MaxX:   session.MaxX,
+			MinY:   session.MinY,
+			MaxY:   session.MaxY,
+			Events: rawEvents,
 		}
 	}
 
@@ -1097,46 +1234,41 @@ func (a *App) ExecuteSingleTouchEvent(deviceId string, event TouchEvent, sourceR
 	fmt.Printf("[Automation] Single Event Request: Type=%s, X=%d, Y=%d, Value=%q\n",
 		event.Type, event.X, event.Y, selectorValue)
 
-	// Get target device resolution for scaling
-	targetResStr, err := a.GetDeviceResolution(deviceId)
-	var scaleX, scaleY float64 = 1.0, 1.0
-
-	if err == nil && sourceResolution != "" {
-		targetW, targetH, ok1 := parseResolution(targetResStr)
-		sourceW, sourceH, ok2 := parseResolution(sourceResolution)
-
-		if ok1 && ok2 && sourceW > 0 && sourceH > 0 {
-			scaleX = float64(targetW) / float64(sourceW)
-			scaleY = float64(targetH) / float64(sourceH)
-		}
-	}
-
-	// Apply scaling
-	finalX := int(float64(event.X) * scaleX)
-	finalY := int(float64(event.Y) * scaleY)
+	// Map recorded coordinates onto the target screen
+	transform := a.newTouchTransform(deviceId, &TouchScript{Resolution: sourceResolution})
+	finalX, finalY := transform.apply(event.X, event.Y)
 
 	// Execute the touch event
 	var cmd string
 	lowerType := strings.ToLower(event.Type)
 	switch lowerType {
 	case "tap", "click":
-		tapX, tapY := finalX, finalY
-		if event.Selector != nil && event.Selector.Type != "coordinates" {
-			resolvedX, resolvedY, found := a.resolveSmartTapCoords(deviceId, event.Selector, finalX, finalY)
-			if found {
-				tapX, tapY = resolvedX, resolvedY
-			}
-		}
+		tapX, tapY, _ := a.resolveEventTarget(deviceId, 0, event, finalX, finalY)
 		cmd = fmt.Sprintf("shell input tap %d %d", tapX, tapY)
 		fmt.Printf("[Automation] Executing Single Tap at (%d, %d)\n", tapX, tapY)
+	case "double_tap":
+		tapX, tapY, _ := a.resolveEventTarget(deviceId, 0, event, finalX, finalY)
+		cmd = doubleTapCommand(tapX, tapY)
+		fmt.Printf("[Automation] Executing Single Double Tap at (%d, %d)\n", tapX, tapY)
 	case "long_press", "long_click":
-		cmd = fmt.Sprintf("shell input swipe %d %d %d %d %d", finalX, finalY, finalX, finalY, 1000)
-		fmt.Printf("[Automation] Executing Single Long Press at (%d, %d)\n", finalX, finalY)
+		tapX, tapY, _ := a.resolveEventTarget(deviceId, 0, event, finalX, finalY)
+		cmd = fmt.Sprintf("shell input swipe %d %d %d %d %d", tapX, tapY, tapX, tapY, 1000)
+		fmt.Printf("[Automation] Executing Single Long Press at (%d, %d)\n", tapX, tapY)
 	case "swipe":
-		finalX2 := int(float64(event.X2) * scaleX)
-		finalY2 := int(float64(event.Y2) * scaleY)
+		finalX2, finalY2 := transform.apply(event.X2, event.Y2)
 		cmd = fmt.Sprintf("shell input swipe %d %d %d %d %d", finalX, finalY, finalX2, finalY2, 300)
 		fmt.Printf("[Automation] Executing Single Swipe: (%d, %d) -> (%d, %d)\n", finalX, finalY, finalX2, finalY2)
+	case "pinch", "multitouch":
+		fmt.Printf("[Automation] Executing Single Multi-touch with %d fingers\n", len(event.Fingers))
+		return a.playMultiTouch(context.Background(), deviceId, event.Fingers, transform)
+	case "key":
+		cmd = keyEventCommand(event)
+		fmt.Printf("[Automation] Executing Single Key: %d\n", event.KeyCode)
+	case "text":
+		fmt.Printf("[Automation] Single Event: Typing %d chars\n", len([]rune(event.Text)))
+		return a.typeText(deviceId, event.Text)
+	case "waitForElement":
+		return a.waitForElementEvent(context.Background(), deviceId, event)
 	case "wait":
 		duration := event.Duration
 		if duration <= 0 {
@@ -1176,56 +1308,14 @@ func (a *App) resolveSmartTapCoords(deviceId string, selector *ElementSelector,
 	time.Sleep(300 * time.Millisecond)
 
 	for {
-		hierarchy, err := a.GetUIHierarchy(deviceId)
+		hierarchy, err := a.GetUIHierarchy(deviceId, true)
 		if err != nil {
 			fmt.Printf("[Automation] Smart Tap: UI Dump failed: %v\n", err)
-		} else {
-			var matches []*UINode
-
-			// Use the unified find helper
-			matches = a.findAllElementNodes(hierarchy.Root, selector.Type, selector.Value)
-
-			if len(matches) > 0 {
-				// Pick the match closest to original coordinates
-				var bestNode *UINode
-				minDist := -1.0
-
-				for _, node := range matches {
-					re := regexp.MustCompile(`\[(\d+),(\d+)\]\[(\d+),(\d+)\]`)
-					m := re.FindStringSubmatch(node.Bounds)
-					if len(m) >= 5 {
-						x1, _ := strconv.Atoi(m[1])
-						y1, _ := strconv.Atoi(m[2])
-						x2, _ := strconv.Atoi(m[3])
-						y2, _ := strconv.Atoi(m[4])
-						cx, cy := (x1+x2)/2, (y1+y2)/2
-
-						dx := float64(cx - origX)
-						dy := float64(cy - origY)
-						dist := dx*dx + dy*dy
-
-						if bestNode == nil || dist < minDist {
-							bestNode = node
-							minDist = dist
-						}
-					}
-				}
-
-				if bestNode != nil {
-					re := regexp.MustCompile(`\[(\d+),(\d+)\]\[(\d+),(\d+)\]`)
-					m := re.FindStringSubmatch(bestNode.Bounds)
-					if len(m) >= 5 {
-						x1, _ := strconv.Atoi(m[1])
-						y1, _ := strconv.Atoi(m[2])
-						x2, _ := strconv.Atoi(m[3])
-						y2, _ := strconv.Atoi(m[4])
-						centerX := (x1 + x2) / 2
-						centerY := (y1 + y2) / 2
-						fmt.Printf("[Automation] Smart Tap: Found best match (dist=%.0f) at ACTUAL(%d, %d)\n",
-							minDist, centerX, centerY)
-						return centerX, centerY, true
-					}
-				}
+		} else if node := a.pickSmartTapNode(hierarchy.Root, selector, origX, origY); node != nil {
+			if rect, err := ParseBounds(node.Bounds); err == nil {
+				centerX, centerY := rect.Center()
+				fmt.Printf("[Automation] Smart Tap: Found match at ACTUAL(%d, %d)\n", centerX, centerY)
+				return centerX, centerY, true
 			}
 		}
 
@@ -1240,70 +1330,276 @@ func (a *App) resolveSmartTapCoords(deviceId string, selector *ElementSelector,
 	return 0, 0, false
 }
 
-// PlayTouchScript plays back a recorded touch script
-func (a *App) PlayTouchScript(deviceId string, script TouchScript) error {
+// pickSmartTapNode resolves a selector against a dump. An explicit index picks that match;
+// otherwise the match closest to the recorded point wins, since recordings tend to repeat
+// the same label (list rows, "OK" buttons) more often than selectors anticipate.
+func (a *App) pickSmartTapNode(root *UINode, selector *ElementSelector, origX, origY int) *UINode {
+	if selector.Index > 0 {
+		return a.FindElementBySelector(root, selector)
+	}
+
+	var best *UINode
+	minDist := -1
+	for _, node := range a.FindAllElementsBySelector(root, selector) {
+		rect, err := ParseBounds(node.Bounds)
+		if err != nil {
+			continue
+		}
+		cx, cy := rect.Center()
+		dist := (cx-origX)*(cx-origX) + (cy-origY)*(cy-origY)
+		if best == nil || dist < minDist {
+			best, minDist = node, dist
+		}
+	}
+	if best == nil {
+		// Selector types without a multi-match form (bounds, coordinates)
+		best = a.FindElementBySelector(root, selector)
+	}
+	return best
+}
+
+// resolveEventTarget returns where a selector-carrying event should land, falling back to the
+// recorded point with a touch-playback-warning event when the element can't be found. The
+// warning text is also returned for the playback history.
+func (a *App) resolveEventTarget(deviceId string, index int, event TouchEvent, x, y int) (int, int, string) {
+	if event.Selector == nil || event.Selector.Type == "coordinates" {
+		return x, y, ""
+	}
+	if rx, ry, found := a.resolveSmartTapCoords(deviceId, event.Selector, x, y); found {
+		return rx, ry, ""
+	}
+	message := fmt.Sprintf("element %s=%q not found, using recorded coordinates", event.Selector.Type, event.Selector.Value)
+	wailsRuntime.EventsEmit(a.ctx, "touch-playback-warning", map[string]interface{}{
+		"deviceId": deviceId,
+		"index":    index,
+		"type":     event.Type,
+		"selector": event.Selector,
+		"message":  message,
+	})
+	return x, y, message
+}
+
+// PlayTouchScript plays back a recorded touch script, optionally repeating it
+func (a *App) PlayTouchScript(deviceId string, script TouchScript, opts PlaybackOptions) error {
+	mode := touchPlaybackMode(script)
+	if mode == "simple" && len(script.Events) == 0 {
+		return fmt.Errorf("script has no events")
+	}
+	if opts.Speed < 0 || opts.Speed > maxPlaybackSpeed {
+		return fmt.Errorf("speed must be between 0 and %gx", maxPlaybackSpeed)
+	}
+	if opts.Speed > 0 && opts.Speed != 1 {
+		script = scaleScriptTiming(script, opts.Speed)
+	}
+
+	restoreOrientation := func() {}
+	if opts.ForceOrientation {
+		if a.IsPlayingTouch(deviceId) {
+			return fmt.Errorf("playback already in progress")
+		}
+		restore, err := a.forceScriptOrientation(deviceId, &script)
+		if err != nil {
+			return err
+		}
+		restoreOrientation = restore
+	}
+
+	transform := a.newTouchTransform(deviceId, &script)
+	if err := transform.checkOrientation(&script); err != nil {
+		restoreOrientation()
+		return err
+	}
+
 	touchPlaybackMu.Lock()
 	if _, exists := touchPlaybackCancel[deviceId]; exists {
 		touchPlaybackMu.Unlock()
+		restoreOrientation()
 		return fmt.Errorf("playback already in progress")
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if opts.MaxDuration > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(opts.MaxDuration)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
 	touchPlaybackCancel[deviceId] = cancel
 	touchPlaybackMu.Unlock()
 
-	go func() {
-		defer func() {
-			touchPlaybackMu.Lock()
-			delete(touchPlaybackCancel, deviceId)
-			touchPlaybackMu.Unlock()
-
-			wailsRuntime.EventsEmit(a.ctx, "touch-playback-completed", map[string]interface{}{
-				"deviceId": deviceId,
-			})
-		}()
+	iterations := opts.Repeat
+	if iterations == 0 {
+		iterations = 1
+	}
+	if opts.StepMode {
+		taskPauseMu.Lock()
+		touchPlaybackStepping[deviceId] = true
+		taskPauseMu.Unlock()
+	}
 
-		// Use the synchronous helper
-		_ = a.playTouchScriptSync(ctx, deviceId, script, func(current, total int) {
+	go func() {
+		defer restoreOrientation()
+		if opts.DisableAnimations {
+			defer a.withAnimationsDisabled(deviceId)()
+		}
+		completed, reason, _ := a.runPlaybackIterations(ctx, deviceId, script, iterations, opts.IntervalMs, func(iteration, current, total int, eventType string) {
 			wailsRuntime.EventsEmit(a.ctx, "touch-playback-progress", map[string]interface{}{
-				"deviceId": deviceId,
-				"current":  current,
-				"total":    total,
+				"deviceId":   deviceId,
+				"current":    current,
+				"total":      total,
+				"eventType":  eventType,
+				"iteration":  iteration,
+				"iterations": iterations,
 			})
 		})
+
+		cancel()
+		a.clearTouchPlaybackPause(deviceId)
+		touchPlaybackMu.Lock()
+		delete(touchPlaybackCancel, deviceId)
+		touchPlaybackMu.Unlock()
+
+		wailsRuntime.EventsEmit(a.ctx, "touch-playback-completed", map[string]interface{}{
+			"deviceId":   deviceId,
+			"iterations": completed,
+			"reason":     reason,
+		})
 	}()
 
 	wailsRuntime.EventsEmit(a.ctx, "touch-playback-started", map[string]interface{}{
-		"deviceId": deviceId,
-		"total":    len(script.Events),
+		"deviceId":   deviceId,
+		"total":      len(script.Events),
+		"mode":       mode,
+		"iterations": iterations,
+		"step":       opts.StepMode,
+		"scaleX":     transform.scaleX,
+		"scaleY":     transform.scaleY,
+		"rotation":   transform.rotation,
+		"absolute":   transform.absolute,
 	})
 
 	return nil
 }
 
+// runPlaybackIterations replays script up to iterations times (negative = until ctx ends) and
+// reports how many runs finished and why it stopped: completed, stopped, timeout or error
+func (a *App) runPlaybackIterations(ctx context.Context, deviceId string, script TouchScript, iterations, intervalMs int, progressCb func(iteration, current, total int, eventType string)) (int, string, error) {
+	completed := 0
+	var runErr error
+	for iteration := 1; iterations < 0 || iteration <= iterations; iteration++ {
+		if iteration > 1 && intervalMs > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Duration(intervalMs) * time.Millisecond):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Use the synchronous helper
+		err := a.playTouchScriptSync(ctx, deviceId, script, func(current, total int, eventType string) {
+			progressCb(iteration, current, total, eventType)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				return completed, "error", err
+			}
+			runErr = err
+			break
+		}
+		completed = iteration
+	}
+
+	switch ctx.Err() {
+	case context.Canceled:
+		return completed, "stopped", runErr
+	case context.DeadlineExceeded:
+		return completed, "timeout", runErr
+	}
+	return completed, "completed", nil
+}
+
+// scaleScriptTiming returns a copy of script running at the given speed: waits and gesture
+// durations are divided by speed, with swipes kept long enough for `input swipe` to register
+// as movement and long presses long enough to stay long presses
+func scaleScriptTiming(script TouchScript, speed float64) TouchScript {
+	scale := func(ms int) int { return int(float64(ms) / speed) }
+	atLeast := func(ms, min int) int {
+		if ms < min {
+			return min
+		}
+		return ms
+	}
+
+	events := make([]TouchEvent, len(script.Events))
+	for i, event := range script.Events {
+		event.Timestamp = int64(float64(event.Timestamp) / speed)
+		switch event.Type {
+		case "swipe":
+			event.Duration = atLeast(scale(event.Duration), minSwipeDurationMs)
+		case "long_press":
+			event.Duration = atLeast(scale(event.Duration), 500)
+		case "wait":
+			event.Duration = scale(event.Duration)
+		case "pinch", "multitouch":
+			event.Duration = atLeast(scale(event.Duration), minSwipeDurationMs)
+			fingers := make([]TouchPointer, len(event.Fingers))
+			for j, f := range event.Fingers {
+				f.Delay = scale(f.Delay)
+				f.Duration = atLeast(scale(f.Duration), minSwipeDurationMs)
+				fingers[j] = f
+			}
+			event.Fingers = fingers
+		}
+		events[i] = event
+	}
+	script.Events = events
+
+	if script.RawInput != nil {
+		raw := *script.RawInput
+		raw.Events = make([]RawInputEvent, len(script.RawInput.Events))
+		for i, ev := range script.RawInput.Events {
+			ev.Time = int64(float64(ev.Time) / speed)
+			raw.Events[i] = ev
+		}
+		script.RawInput = &raw
+	}
+	return script
+}
+
 // playTouchScriptSync is the synchronous core logic for playing a script
-func (a *App) playTouchScriptSync(ctx context.Context, deviceId string, script TouchScript, progressCb func(int, int)) error {
-	startTime := time.Now()
+func (a *App) playTouchScriptSync(ctx context.Context, deviceId string, script TouchScript, progressCb func(current, total int, eventType string)) (err error) {
 	total := len(script.Events)
 
-	// 1. Get target device resolution
-	targetResStr, err := a.GetDeviceResolution(deviceId)
-	var scaleX, scaleY float64 = 1.0, 1.0
+	// Every run, however it ends, goes into the playback history
+	runLog := newPlaybackRunLog(deviceId, &script)
+	defer func() {
+		a.finishPlaybackRun(runLog, ctx.Err() != nil, err)
+	}()
 
-	if err == nil && script.Resolution != "" {
-		// Parse target resolution
-		targetW, targetH, ok1 := parseResolution(targetResStr)
-		// Parse source resolution
-		sourceW, sourceH, ok2 := parseResolution(script.Resolution)
+	// 1. Map recorded coordinates onto the target screen
+	transform := a.newTouchTransform(deviceId, &script)
+	if err := transform.checkOrientation(&script); err != nil {
+		return err
+	}
 
-		if ok1 && ok2 && sourceW > 0 && sourceH > 0 {
-			scaleX = float64(targetW) / float64(sourceW)
-			scaleY = float64(targetH) / float64(sourceH)
-			fmt.Printf("Auto-scaling enabled: Source=%dx%d, Target=%dx%d, ScaleX=%.2f, ScaleY=%.2f\n",
-				sourceW, sourceH, targetW, targetH, scaleX, scaleY)
+	if touchPlaybackMode(script) == "raw" {
+		var keys []TouchEvent
+		for _, event := range script.Events {
+			if event.Type == "key" {
+				keys = append(keys, event)
+			}
+		}
+		err := a.playRawTouchScript(ctx, deviceId, script.RawInput, keys)
+		if err == nil && progressCb != nil {
+			progressCb(total, total, "raw")
 		}
+		return err
 	}
 
+	startTime := time.Now()
+
 	for i, event := range script.Events {
 		fmt.Printf("[Automation] Executing event %d/%d: %s at (%d, %d)\n", i+1, total, event.Type, event.X, event.Y)
 		select {
@@ -1323,29 +1619,34 @@ func (a *App) playTouchScriptSync(ctx context.Context, deviceId string, script T
 			}
 		}
 
-		// Check pause
-		a.checkPause(deviceId)
+		// Check pause; time spent paused doesn't count against the event schedule
+		startTime = startTime.Add(a.touchPlaybackGate(ctx, deviceId, i, total))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
 		// Apply scaling
-		finalX := int(float64(event.X) * scaleX)
-		finalY := int(float64(event.Y) * scaleY)
+		finalX, finalY := transform.apply(event.X, event.Y)
+		actual := time.Since(startTime)
+		eventStart := time.Now()
+		note := ""
 
 		// Execute the touch event
 		var cmd string
 		switch event.Type {
 		case "tap":
-			tapX, tapY := finalX, finalY
-
 			// Smart Tap: if we have identifying info, try to find the element on screen
-			if event.Selector != nil && event.Selector.Type != "coordinates" {
-				resolvedX, resolvedY, found := a.resolveSmartTapCoords(deviceId, event.Selector, finalX, finalY)
-				if found {
-					tapX, tapY = resolvedX, resolvedY
-				}
-			}
+			tapX, tapY, warning := a.resolveEventTarget(deviceId, i, event, finalX, finalY)
+			note = warning
 			cmd = fmt.Sprintf("shell input tap %d %d", tapX, tapY)
+		case "double_tap":
+			tapX, tapY, warning := a.resolveEventTarget(deviceId, i, event, finalX, finalY)
+			note = warning
+			cmd = doubleTapCommand(tapX, tapY)
+			fmt.Printf("[Automation] Executing DOUBLE_TAP: (%d, %d)\n", tapX, tapY)
 		case "long_press":
-			tapX, tapY := finalX, finalY
+			tapX, tapY, warning := a.resolveEventTarget(deviceId, i, event, finalX, finalY)
+			note = warning
 			duration := event.Duration
 			if duration < 500 {
 				duration = 1000 // Default minimal duration for long press if missing
@@ -1354,28 +1655,239 @@ func (a *App) playTouchScriptSync(ctx context.Context, deviceId string, script T
 			cmd = fmt.Sprintf("shell input swipe %d %d %d %d %d", tapX, tapY, tapX, tapY, duration)
 			fmt.Printf("[Automation] Executing LONG_PRESS: (%d, %d) for %dms\n", tapX, tapY, duration)
 		case "swipe":
-			finalX2 := int(float64(event.X2) * scaleX)
-			finalY2 := int(float64(event.Y2) * scaleY)
+			finalX2, finalY2 := transform.apply(event.X2, event.Y2)
+			var clamped1, clamped2 bool
+			finalX, finalY, clamped1 = transform.clamp(finalX, finalY)
+			finalX2, finalY2, clamped2 = transform.clamp(finalX2, finalY2)
+			if clamped1 || clamped2 {
+				note = "swipe clamped to the screen edge"
+			}
 			cmd = fmt.Sprintf("shell input swipe %d %d %d %d %d",
 				finalX, finalY, finalX2, finalY2, event.Duration)
 			fmt.Printf("[Automation] Executing SWIPE: (%d, %d) -> (%d, %d)\n", finalX, finalY, finalX2, finalY2)
+		case "pinch", "multitouch":
+			fmt.Printf("[Automation] Executing MULTITOUCH: %d fingers for %dms\n", len(event.Fingers), event.Duration)
+			if err := a.playMultiTouch(ctx, deviceId, event.Fingers, transform); err != nil {
+				fmt.Printf("[Automation] Multi-touch command failed: %v\n", err)
+			}
+		case "key":
+			cmd = keyEventCommand(event)
+			fmt.Printf("[Automation] Executing KEY: %d\n", event.KeyCode)
+		case "text":
+			fmt.Printf("[Automation] Executing TEXT: %d chars\n", len([]rune(event.Text)))
+			if err := a.typeText(deviceId, event.Text); err != nil {
+				fmt.Printf("[Automation] Text input failed: %v\n", err)
+			}
 		case "wait":
-			time.Sleep(time.Duration(event.Duration) * time.Millisecond)
-			continue
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(event.Duration) * time.Millisecond):
+			}
+		case "waitForElement":
+			waitStart := time.Now()
+			if err := a.waitForElementEvent(ctx, deviceId, event); err != nil {
+				return fmt.Errorf("event %d: %w", i+1, err)
+			}
+			// Later events keep their spacing relative to when the element showed up
+			startTime = startTime.Add(time.Since(waitStart))
+		case "scrollTo":
+			scrollStart := time.Now()
+			result, err := a.scrollToElement(ctx, deviceId, event.Selector, event.Container, event.MaxSwipes, event.Direction)
+			if err != nil {
+				return fmt.Errorf("event %d: %w", i+1, err)
+			}
+			note = fmt.Sprintf("found after %d swipes", result.Swipes)
+			startTime = startTime.Add(time.Since(scrollStart))
 		default:
 			continue
 		}
 
-		_, err = a.RunAdbCommand(deviceId, cmd)
-		if err != nil {
-			fmt.Printf("[Automation] Action command failed: %v\n", err)
-		}
+		if cmd != "" {
+			if _, err := a.RunAdbCommand(deviceId, cmd); err != nil {
+				fmt.Printf("[Automation] Action command failed: %v\n", err)
+			}
+		}
+		runLog.event(i, event, actual, time.Since(eventStart), note)
+
+		if progressCb != nil {
+			progressCb(i+1, total, event.Type)
+		}
+	}
+	return nil
+}
+
+// waitForElementEvent blocks until a waitForElement event's selector appears, or is gone,
+// within the event's duration
+func (a *App) waitForElementEvent(ctx context.Context, deviceId string, event TouchEvent) error {
+	if event.Selector == nil {
+		return fmt.Errorf("waitForElement event has no selector")
+	}
+	_, err := a.pollElement(ctx, deviceId, event.Selector, event.Duration, elementPollIntervalMs, event.Gone)
+	return err
+}
+
+// textInputCommands returns the device shell commands that type ASCII text, or false when the
+// text needs an IME. Newlines can't go through `input text` and are sent as Enter presses.
+func textInputCommands(text string) ([]string, bool) {
+	for _, r := range text {
+		if r > 0x7e || (r < 0x20 && r != '\n') {
+			return nil, false
+		}
+	}
+
+	var cmds []string
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			cmds = append(cmds, "input keyevent 66")
+		}
+		if line == "" {
+			continue
+		}
+		// `input text` turns %s into a space; the whole argument is single-quoted for the shell
+		cmds = append(cmds, "input text "+shellQuote(strings.ReplaceAll(line, " ", "%s")))
+	}
+	return cmds, true
+}
+
+// adbKeyboardTextCommand types arbitrary text through the ADBKeyBoard IME's broadcast receiver
+func adbKeyboardTextCommand(text string) string {
+	return "am broadcast -a ADB_INPUT_B64 --es msg " + base64.StdEncoding.EncodeToString([]byte(text))
+}
+
+// doubleTapCommand issues two taps about 100ms apart; each `input` call takes longer than a
+// double-tap window to start, so the first one is backgrounded
+func doubleTapCommand(x, y int) string {
+	return fmt.Sprintf("shell input tap %d %d & sleep 0.1; input tap %d %d; wait", x, y, x, y)
+}
+
+// keyEventCommand builds the `input keyevent` call for a recorded key press
+func keyEventCommand(event TouchEvent) string {
+	if event.Duration >= 500 {
+		return fmt.Sprintf("shell input keyevent --longpress %d", event.KeyCode)
+	}
+	return fmt.Sprintf("shell input keyevent %d", event.KeyCode)
+}
+
+// playMultiTouch approximates a multi-finger gesture by issuing one `input swipe` per finger
+// concurrently, each starting at its recorded offset within the gesture
+func (a *App) playMultiTouch(ctx context.Context, deviceId string, fingers []TouchPointer, transform touchTransform) error {
+	if len(fingers) == 0 {
+		return fmt.Errorf("multi-touch event has no fingers")
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(fingers))
+	for _, f := range fingers {
+		wg.Add(1)
+		go func(f TouchPointer) {
+			defer wg.Done()
+			if f.Delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Duration(f.Delay) * time.Millisecond):
+				}
+			}
+			duration := f.Duration
+			if duration <= 0 {
+				duration = 300
+			}
+			x1, y1 := transform.apply(f.X, f.Y)
+			x2, y2 := transform.apply(f.X2, f.Y2)
+			cmd := fmt.Sprintf("shell input swipe %d %d %d %d %d", x1, y1, x2, y2, duration)
+			if _, err := a.RunAdbCommand(deviceId, cmd); err != nil {
+				errs <- err
+			}
+		}(f)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// touchTransform maps script coordinates, which are in the recording device's natural
+// (rotation 0) screen space, to `input` coordinates on the target's current display
+type touchTransform struct {
+	scaleX, scaleY float64
+	rotation       int // Target display rotation in quarter turns
+	width, height  int // Target natural resolution
+	absolute       bool
+}
+
+// newTouchTransform measures the target screen; unknown values fall back to an identity mapping
+func (a *App) newTouchTransform(deviceId string, script *TouchScript) touchTransform {
+	t := touchTransform{scaleX: 1, scaleY: 1, absolute: script.AbsoluteCoordinates}
+	if t.absolute {
+		return t
+	}
+
+	targetResStr, err := a.GetDeviceResolution(deviceId)
+	if err == nil {
+		t.width, t.height, _ = parseResolution(targetResStr)
+	}
+	if rotation, err := a.getDisplayRotation(deviceId); err == nil {
+		t.rotation = rotation
+	} else {
+		fmt.Printf("[Automation] Could not read display rotation, assuming portrait: %v\n", err)
+	}
+
+	sourceW, sourceH, ok := parseResolution(script.Resolution)
+	if ok && sourceW > 0 && sourceH > 0 && t.width > 0 && t.height > 0 {
+		t.scaleX = float64(t.width) / float64(sourceW)
+		t.scaleY = float64(t.height) / float64(sourceH)
+		fmt.Printf("Auto-scaling enabled: Source=%dx%d, Target=%dx%d, ScaleX=%.2f, ScaleY=%.2f, Rotation=%d\n",
+			sourceW, sourceH, t.width, t.height, t.scaleX, t.scaleY, t.rotation)
+	}
+	return t
+}
 
-		if progressCb != nil {
-			progressCb(i+1, total)
-		}
+// checkOrientation refuses to replay a portrait recording on a landscape screen and vice versa,
+// since the app layout will not match. Scripts that don't know their orientation aren't checked;
+// their coordinates are still scaled.
+func (t touchTransform) checkOrientation(script *TouchScript) error {
+	if t.absolute || script.Orientation == nil || t.rotation%2 == *script.Orientation%2 {
+		return nil
 	}
-	return nil
+	names := []string{"portrait", "landscape"}
+	return fmt.Errorf("script was recorded in %s but the device is in %s; rotate the device or enable absolute coordinates",
+		names[*script.Orientation%2], names[t.rotation%2])
+}
+
+// apply scales a point to the target resolution and rotates it into display coordinates
+func (t touchTransform) apply(x, y int) (int, int) {
+	if t.absolute {
+		return x, y
+	}
+	sx := int(float64(x) * t.scaleX)
+	sy := int(float64(y) * t.scaleY)
+	if t.width <= 0 || t.height <= 0 {
+		return sx, sy
+	}
+	switch t.rotation {
+	case 1:
+		return sy, t.width - sx
+	case 2:
+		return t.width - sx, t.height - sy
+	case 3:
+		return t.height - sy, sx
+	}
+	return sx, sy
+}
+
+// clamp keeps a mapped point on the target display, reporting whether it had to move it.
+// Points are left alone when the target size is unknown.
+func (t touchTransform) clamp(x, y int) (int, int, bool) {
+	w, h := t.width, t.height
+	if w <= 0 || h <= 0 {
+		return x, y, false
+	}
+	if t.rotation%2 == 1 {
+		w, h = h, w
+	}
+	cx := min(max(x, 0), w-1)
+	cy := min(max(y, 0), h-1)
+	return cx, cy, cx != x || cy != y
 }
 
 // Helper to parse "WxH" string
@@ -1395,14 +1907,21 @@ func parseResolution(res string) (int, int, bool) {
 	return w, h, true
 }
 
-// StopTouchPlayback stops an ongoing touch playback
+// StopTouchPlayback stops an ongoing touch playback on a device, or every device of a batch
 func (a *App) StopTouchPlayback(deviceId string) {
 	touchPlaybackMu.Lock()
 	defer touchPlaybackMu.Unlock()
 
-	if cancel, exists := touchPlaybackCancel[deviceId]; exists {
-		cancel()
-		delete(touchPlaybackCancel, deviceId)
+	// A batch ID from PlayTouchScriptOnDevices stops every device in the batch
+	targets := []string{deviceId}
+	if batch, ok := touchPlaybackBatches[deviceId]; ok {
+		targets = batch
+	}
+	for _, id := range targets {
+		if cancel, exists := touchPlaybackCancel[id]; exists {
+			cancel()
+			delete(touchPlaybackCancel, id)
+		}
 	}
 }
 
@@ -1458,6 +1977,72 @@ func (a *App) checkPause(deviceId string) {
 	}
 }
 
+// PauseTouchPlayback holds a running playback before its next event
+func (a *App) PauseTouchPlayback(deviceId string) {
+	a.PauseTask(deviceId)
+}
+
+// ResumeTouchPlayback lets a paused playback continue, leaving step mode if it was on
+func (a *App) ResumeTouchPlayback(deviceId string) {
+	taskPauseMu.Lock()
+	delete(touchPlaybackStepping, deviceId)
+	taskPauseMu.Unlock()
+	a.ResumeTask(deviceId)
+}
+
+// StepTouchPlayback runs the next event of a paused playback, then pauses again
+func (a *App) StepTouchPlayback(deviceId string) {
+	taskPauseMu.Lock()
+	touchPlaybackStepping[deviceId] = true
+	taskPauseMu.Unlock()
+	a.ResumeTask(deviceId)
+}
+
+// touchPlaybackGate blocks before event next while the device is paused (or stepping) and
+// returns how long it was held. It gives up when ctx ends, so stopping a paused playback
+// doesn't hang.
+func (a *App) touchPlaybackGate(ctx context.Context, deviceId string, next, total int) time.Duration {
+	taskPauseMu.Lock()
+	stepping := touchPlaybackStepping[deviceId]
+	if stepping && !taskIsPaused[deviceId] {
+		taskPauseSignal[deviceId] = make(chan struct{})
+		taskIsPaused[deviceId] = true
+	}
+	ch, paused := taskPauseSignal[deviceId]
+	taskPauseMu.Unlock()
+
+	if !paused {
+		return 0
+	}
+	wailsRuntime.EventsEmit(a.ctx, "touch-playback-paused", map[string]interface{}{
+		"deviceId":  deviceId,
+		"nextIndex": next,
+		"total":     total,
+		"step":      stepping,
+	})
+
+	start := time.Now()
+	select {
+	case <-ch:
+	case <-ctx.Done():
+	}
+	return time.Since(start)
+}
+
+// clearTouchPlaybackPause drops pause and step state once a playback has ended so the next one
+// doesn't start held
+func (a *App) clearTouchPlaybackPause(deviceId string) {
+	taskPauseMu.Lock()
+	defer taskPauseMu.Unlock()
+
+	if ch, paused := taskPauseSignal[deviceId]; paused {
+		close(ch)
+		delete(taskPauseSignal, deviceId)
+	}
+	delete(taskIsPaused, deviceId)
+	delete(touchPlaybackStepping, deviceId)
+}
+
 // getScriptsPath returns the path to the scripts directory
 func (a *App) getScriptsPath() string {
 	configDir, err := os.UserConfigDir()
@@ -1469,28 +2054,22 @@ func (a *App) getScriptsPath() string {
 	return scriptsPath
 }
 
-// SaveTouchScript saves a touch script to file
-func (a *App) SaveTouchScript(script TouchScript) error {
-	scriptsPath := a.getScriptsPath()
-
-	// Sanitize filename
-	safeName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(script.Name, "_")
-	if safeName == "" {
-		safeName = fmt.Sprintf("script_%d", time.Now().Unix())
+// SaveTouchScript saves a touch script to file. Saving under a name that already exists
+// fails with ScriptExistsError unless overwrite is set.
+func (a *App) SaveTouchScript(script TouchScript, overwrite bool) error {
+	if script.Name == "" {
+		script.Name = defaultScriptName()
 	}
 
-	filePath := filepath.Join(scriptsPath, safeName+".json")
-
-	data, err := json.MarshalIndent(script, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal script: %w", err)
-	}
+	scriptIndexMu.Lock()
+	defer scriptIndexMu.Unlock()
+	a.loadScriptIndexLocked()
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write script file: %w", err)
+	filePath, exists := a.scriptFileLocked(script.Name, "")
+	if exists && !overwrite {
+		return &ScriptExistsError{Name: script.Name}
 	}
-
-	return nil
+	return a.writeTouchScriptLocked(filePath, script)
 }
 
 // LoadTouchScripts loads all saved touch scripts
@@ -1521,6 +2100,10 @@ func (a *App) LoadTouchScripts() ([]TouchScript, error) {
 		if err := json.Unmarshal(data, &script); err != nil {
 			continue
 		}
+		if script.Name == "" {
+			script.Name = strings.TrimSuffix(entry.Name(), ".json")
+		}
+		migrateTouchScript(&script)
 
 		scripts = append(scripts, script)
 	}
@@ -1528,55 +2111,232 @@ func (a *App) LoadTouchScripts() ([]TouchScript, error) {
 	return scripts, nil
 }
 
-// DeleteTouchScript deletes a saved touch script
-func (a *App) DeleteTouchScript(name string) error {
-	scriptsPath := a.getScriptsPath()
+// loadTouchScript reads the saved script with the given name
+func (a *App) loadTouchScript(name string) (*TouchScript, error) {
+	scriptIndexMu.Lock()
+	a.loadScriptIndexLocked()
+	filePath, exists := a.scriptFileLocked(name, "")
+	scriptIndexMu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("script not found: %s", name)
+	}
 
-	safeName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(name, "_")
-	filePath := filepath.Join(scriptsPath, safeName+".json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("script not found: %s", name)
+	}
 
-	if err := os.Remove(filePath); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("script not found")
+	var script TouchScript
+	if err := json.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("failed to parse script: %w", err)
+	}
+	migrateTouchScript(&script)
+	return &script, nil
+}
+
+// touchScriptVersion is the current script schema. Version 2 made "text" and "wait" real
+// events: waits carry a positive duration and events are kept in timestamp order.
+const touchScriptVersion = 2
+
+// migrateTouchScript upgrades a script read from disk to the current schema. The file is
+// rewritten on the next save.
+func migrateTouchScript(script *TouchScript) {
+	if script.Version >= touchScriptVersion {
+		return
+	}
+	events := script.Events[:0]
+	for _, event := range script.Events {
+		// Old editors could leave zero-length waits behind; they never did anything
+		if event.Type == "wait" && event.Duration <= 0 {
+			continue
 		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+	script.Events = events
+	script.Version = touchScriptVersion
+}
+
+// DeleteTouchScript deletes a saved touch script
+func (a *App) DeleteTouchScript(name string) error {
+	scriptIndexMu.Lock()
+	defer scriptIndexMu.Unlock()
+	a.loadScriptIndexLocked()
+
+	filePath, exists := a.scriptFileLocked(name, "")
+	if !exists {
+		return fmt.Errorf("script not found")
+	}
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete script: %w", err)
 	}
 
-	return nil
+	delete(scriptIndex, name)
+	return a.saveScriptIndexLocked()
 }
 
-// RenameTouchScript renames a script
+// RenameTouchScript renames a script; the new name must not belong to another script.
+// Scheduled playbacks of the script follow the rename.
 func (a *App) RenameTouchScript(oldName, newName string) error {
-	scriptsPath := a.getScriptsPath()
+	if newName == "" {
+		return fmt.Errorf("script name must not be empty")
+	}
+	if newName == oldName {
+		return nil
+	}
 
-	// 1. Read old file
-	safeOldName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(oldName, "_")
-	oldFilePath := filepath.Join(scriptsPath, safeOldName+".json")
+	scriptIndexMu.Lock()
+	defer scriptIndexMu.Unlock()
+	a.loadScriptIndexLocked()
+
+	oldFilePath, exists := a.scriptFileLocked(oldName, "")
+	if !exists {
+		return fmt.Errorf("script not found: %s", oldName)
+	}
+	if _, taken := scriptIndex[newName]; taken {
+		return &ScriptExistsError{Name: newName}
+	}
 
 	data, err := os.ReadFile(oldFilePath)
 	if err != nil {
 		return fmt.Errorf("script not found: %w", err)
 	}
-
 	var script TouchScript
 	if err := json.Unmarshal(data, &script); err != nil {
 		return fmt.Errorf("failed to parse script: %w", err)
 	}
-
-	// 2. Update name
 	script.Name = newName
 
-	// 3. Save new file
-	if err := a.SaveTouchScript(script); err != nil {
+	// The old file may be reused when both names sanitize to the same file name
+	newFilePath, _ := a.scriptFileLocked(newName, filepath.Base(oldFilePath))
+	delete(scriptIndex, oldName)
+	if err := a.writeTouchScriptLocked(newFilePath, script); err != nil {
+		scriptIndex[oldName] = filepath.Base(oldFilePath)
 		return err
 	}
-
-	// 4. Delete old file if name changed (and safe names are different)
-	safeNewName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(newName, "_")
-	if safeOldName != safeNewName {
+	if newFilePath != oldFilePath {
 		_ = os.Remove(oldFilePath)
 	}
 
+	a.renameScheduledScript(oldName, newName)
+	return nil
+}
+
+// UpdateTouchScriptEvent replaces the event at index in a saved script.
+// Edits apply to the simplified events; a raw-mode script still replays its original capture.
+func (a *App) UpdateTouchScriptEvent(scriptName string, index int, event TouchEvent) error {
+	script, err := a.loadTouchScript(scriptName)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(script.Events) {
+		return fmt.Errorf("event index %d out of range (0-%d)", index, len(script.Events)-1)
+	}
+	if err := validateTouchEvent(script, event); err != nil {
+		return err
+	}
+	script.Events[index] = event
+	return a.SaveTouchScript(*script, true)
+}
+
+// InsertTouchScriptEvent inserts an event before index; index == len(events) appends
+func (a *App) InsertTouchScriptEvent(scriptName string, index int, event TouchEvent) error {
+	script, err := a.loadTouchScript(scriptName)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index > len(script.Events) {
+		return fmt.Errorf("event index %d out of range (0-%d)", index, len(script.Events))
+	}
+	if err := validateTouchEvent(script, event); err != nil {
+		return err
+	}
+	script.Events = append(script.Events[:index], append([]TouchEvent{event}, script.Events[index:]...)...)
+	if event.Type == "wait" {
+		// Events are scheduled by timestamp, so later ones move back to keep their spacing
+		for i := index + 1; i < len(script.Events); i++ {
+			script.Events[i].Timestamp += int64(event.Duration)
+		}
+	}
+	return a.SaveTouchScript(*script, true)
+}
+
+// DeleteTouchScriptEvent removes the event at index from a saved script
+func (a *App) DeleteTouchScriptEvent(scriptName string, index int) error {
+	script, err := a.loadTouchScript(scriptName)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(script.Events) {
+		return fmt.Errorf("event index %d out of range (0-%d)", index, len(script.Events)-1)
+	}
+	script.Events = append(script.Events[:index], script.Events[index+1:]...)
+	return a.SaveTouchScript(*script, true)
+}
+
+// validateTouchEvent rejects events that could not have been recorded on the script's screen
+func validateTouchEvent(script *TouchScript, event TouchEvent) error {
+	if event.Timestamp < 0 {
+		return fmt.Errorf("timestamp must not be negative")
+	}
+	if event.Duration < 0 {
+		return fmt.Errorf("duration must not be negative")
+	}
+
+	var points [][2]int
+	switch event.Type {
+	case "tap", "double_tap", "long_press":
+		points = append(points, [2]int{event.X, event.Y})
+	case "swipe":
+		points = append(points, [2]int{event.X, event.Y}, [2]int{event.X2, event.Y2})
+	case "pinch", "multitouch":
+		if len(event.Fingers) == 0 {
+			return fmt.Errorf("%s event needs at least one finger", event.Type)
+		}
+		for _, f := range event.Fingers {
+			points = append(points, [2]int{f.X, f.Y}, [2]int{f.X2, f.Y2})
+		}
+	case "key":
+		if event.KeyCode <= 0 {
+			return fmt.Errorf("key event needs a key code")
+		}
+	case "text":
+		if event.Text == "" {
+			return fmt.Errorf("text event needs text")
+		}
+	case "wait":
+		if event.Duration <= 0 {
+			return fmt.Errorf("wait event needs a positive duration")
+		}
+	case "waitForElement":
+		if event.Selector == nil || event.Selector.Value == "" {
+			return fmt.Errorf("waitForElement event needs a selector")
+		}
+		if event.Duration < 0 {
+			return fmt.Errorf("timeout must not be negative")
+		}
+	case "scrollTo":
+		if event.Selector == nil || event.Selector.Value == "" {
+			return fmt.Errorf("scrollTo event needs a selector")
+		}
+		switch event.Direction {
+		case "", "up", "down", "left", "right":
+		default:
+			return fmt.Errorf("scroll direction must be up, down, left or right, got %q", event.Direction)
+		}
+		if event.MaxSwipes < 0 {
+			return fmt.Errorf("max swipes must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown event type: %s", event.Type)
+	}
+
+	w, h, ok := parseResolution(script.Resolution)
+	for _, p := range points {
+		if p[0] < 0 || p[1] < 0 || (ok && (p[0] >= w || p[1] >= h)) {
+			return fmt.Errorf("coordinate (%d, %d) is outside the recorded %s screen", p[0], p[1], script.Resolution)
+		}
+	}
 	return nil
 }
 
@@ -1764,7 +2524,7 @@ func (a *App) RunScriptTask(deviceId string, task ScriptTask) error {
 					}
 
 					// Run the script synchronously using our helper
-					err := a.playTouchScriptSync(ctx, deviceId, script, func(current, total int) {
+					err := a.playTouchScriptSync(ctx, deviceId, script, func(current, total int, eventType string) {
 						// Optional: emit more granular progress if needed,
 						// but task-step-running might be enough for general status
 					})
@@ -1815,7 +2575,7 @@ func (a *App) RunScriptTask(deviceId string, task ScriptTask) error {
 							"currentAction": fmt.Sprintf("Checking UI: %s=%s", checkType, step.CheckValue),
 						})
 
-						result, err := a.GetUIHierarchy(deviceId)
+						result, err := a.GetUIHierarchy(deviceId, false)
 						if err == nil && a.FindElement(result.Root, checkType, step.CheckValue) {
 							found = true
 							break
@@ -1888,6 +2648,7 @@ type UINode struct {
 	Password      string   `xml:"password,attr" json:"password"`
 	Selected      string   `xml:"selected,attr" json:"selected"`
 	Bounds        string   `xml:"bounds,attr" json:"bounds"`
+	Index         string   `xml:"index,attr" json:"index"`
 	Nodes         []UINode `xml:"node" json:"nodes"`
 }
 
@@ -1899,10 +2660,23 @@ type UIHierarchy struct {
 type UIHierarchyResult struct {
 	Root   *UINode `json:"root"`
 	RawXML string  `json:"rawXml"`
+	// Generation goes up each time a dump of the device differs from the previous one
+	Generation int64 `json:"generation"`
+	DumpedAt   int64 `json:"dumpedAt"` // Unix ms when the dump started
+	Cached     bool  `json:"cached"`
+}
+
+// syntheticRootText marks the container GetUIHierarchy wraps around several top-level windows
+const syntheticRootText = "Root Container"
+
+// isSyntheticRoot reports whether node is that container rather than something on screen
+func isSyntheticRoot(node *UINode) bool {
+	return node.Text == syntheticRootText && node.Bounds == "[0,0][0,0]"
 }
 
-// GetUIHierarchy dumps the UI hierarchy and parses it
-func (a *App) GetUIHierarchy(deviceId string) (*UIHierarchyResult, error) {
+// dumpUIHierarchy runs uiautomator and parses the dump. Callers go through GetUIHierarchy,
+// which caches the result and keeps two dumps from running at once.
+func (a *App) dumpUIHierarchy(deviceId string) (*UIHierarchyResult, error) {
 	// Try dumping several times as it can be flaky
 	var xmlContent string
 	var err error
@@ -1969,7 +2743,7 @@ func (a *App) GetUIHierarchy(deviceId string) (*UIHierarchyResult, error) {
 	} else {
 		finalRoot = &UINode{
 			Class:   "android.view.View",
-			Text:    "Root Container",
+			Text:    syntheticRootText,
 			Package: root.Nodes[0].Package,
 			Bounds:  "[0,0][0,0]",
 			Nodes:   root.Nodes,
@@ -2015,7 +2789,7 @@ func (a *App) FindElement(node *UINode, checkType, checkValue string) bool {
 
 // GetElementsWithText returns all elements containing the given text (useful for debugging/frontend)
 func (a *App) GetElementsWithText(deviceId string, text string) ([]map[string]interface{}, error) {
-	result, err := a.GetUIHierarchy(deviceId)
+	result, err := a.GetUIHierarchy(deviceId, false)
 	if err != nil {
 		return nil, err
 	}
@@ -2049,141 +2823,6 @@ type SearchResult struct {
 	Index int     `json:"index"`
 }
 
-// SearchElementsXPath searches elements using XPath-like syntax
-// Supports: //node[@attr='value'], //node[@attr], //ClassName, //node[contains(@attr,'value')]
-func (a *App) SearchElementsXPath(root *UINode, xpath string) []SearchResult {
-	var results []SearchResult
-	xpath = strings.TrimSpace(xpath)
-
-	if !strings.HasPrefix(xpath, "//") {
-		return results
-	}
-
-	query := strings.TrimPrefix(xpath, "//")
-
-	// Parse the XPath expression
-	var className string
-	var conditions []struct {
-		attr     string
-		op       string // "=" or "contains"
-		value    string
-		hasValue bool
-	}
-
-	// Check for predicate brackets
-	bracketIdx := strings.Index(query, "[")
-	if bracketIdx != -1 {
-		className = query[:bracketIdx]
-		predicate := query[bracketIdx+1 : len(query)-1] // Remove [ and ]
-
-		// Split by " and " (case insensitive)
-		parts := regexp.MustCompile(`(?i)\s+and\s+`).Split(predicate, -1)
-
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-
-			// contains(@attr, 'value')
-			if strings.HasPrefix(part, "contains(") {
-				re := regexp.MustCompile(`contains\(@(\w+),\s*['"]([^'"]*)['"]\)`)
-				if matches := re.FindStringSubmatch(part); len(matches) == 3 {
-					conditions = append(conditions, struct {
-						attr     string
-						op       string
-						value    string
-						hasValue bool
-					}{matches[1], "contains", matches[2], true})
-				}
-			} else if strings.HasPrefix(part, "@") {
-				// @attr='value' or @attr
-				part = strings.TrimPrefix(part, "@")
-				if strings.Contains(part, "=") {
-					eqParts := strings.SplitN(part, "=", 2)
-					attr := strings.TrimSpace(eqParts[0])
-					value := strings.Trim(strings.TrimSpace(eqParts[1]), "'\"")
-					conditions = append(conditions, struct {
-						attr     string
-						op       string
-						value    string
-						hasValue bool
-					}{attr, "=", value, true})
-				} else {
-					// Just @attr (check if attribute exists and is non-empty)
-					conditions = append(conditions, struct {
-						attr     string
-						op       string
-						value    string
-						hasValue bool
-					}{part, "exists", "", false})
-				}
-			}
-		}
-	} else {
-		className = query
-	}
-
-	// Recursive search
-	var search func(node *UINode, path string, depth int, index int)
-	search = func(node *UINode, path string, depth int, index int) {
-		if node == nil {
-			return
-		}
-
-		// Check class name match
-		classMatch := className == "" || className == "node" || className == "*"
-		if !classMatch {
-			// Check full class name or short name
-			shortName := node.Class
-			if idx := strings.LastIndex(node.Class, "."); idx != -1 {
-				shortName = node.Class[idx+1:]
-			}
-			classMatch = node.Class == className || shortName == className
-		}
-
-		// Check all conditions
-		conditionsMatch := true
-		for _, cond := range conditions {
-			attrValue := a.getNodeAttribute(node, cond.attr)
-
-			switch cond.op {
-			case "=":
-				if attrValue != cond.value {
-					conditionsMatch = false
-				}
-			case "contains":
-				if !strings.Contains(strings.ToLower(attrValue), strings.ToLower(cond.value)) {
-					conditionsMatch = false
-				}
-			case "exists":
-				if attrValue == "" {
-					conditionsMatch = false
-				}
-			}
-
-			if !conditionsMatch {
-				break
-			}
-		}
-
-		if classMatch && conditionsMatch {
-			results = append(results, SearchResult{
-				Node:  node,
-				Path:  path,
-				Depth: depth,
-				Index: index,
-			})
-		}
-
-		// Search children
-		for i := range node.Nodes {
-			childPath := fmt.Sprintf("%s/%s[%d]", path, node.Nodes[i].Class, i)
-			search(&node.Nodes[i], childPath, depth+1, i)
-		}
-	}
-
-	search(root, "/"+root.Class, 0, 0)
-	return results
-}
-
 // getNodeAttribute returns the value of a node attribute by name
 func (a *App) getNodeAttribute(node *UINode, attr string) string {
 	switch strings.ToLower(attr) {
@@ -2195,6 +2834,8 @@ func (a *App) getNodeAttribute(node *UINode, attr string) string {
 		return node.Class
 	case "package":
 		return node.Package
+	case "index":
+		return node.Index
 	case "content-desc", "contentdesc", "description", "desc":
 		return node.ContentDesc
 	case "bounds":
@@ -2223,53 +2864,31 @@ func (a *App) getNodeAttribute(node *UINode, attr string) string {
 	return ""
 }
 
-// SearchElementsAdvanced searches elements using combined conditions
-// Syntax: "attr:value AND attr:value OR attr:value"
-// Operators: = (exact), ~ (contains), ^ (starts with), $ (ends with)
-// Example: "clickable:true AND text~确定" or "class:Button OR class:ImageButton"
+// SearchElementsAdvanced searches elements using the advanced query syntax (see
+// advanced_query.go), e.g. "clickable=true AND (class$Button OR text*=^OK$)"
 func (a *App) SearchElementsAdvanced(root *UINode, query string) []SearchResult {
-	var results []SearchResult
-	query = strings.TrimSpace(query)
+	results, _ := a.searchElementsAdvanced(root, query)
+	return results
+}
 
-	if query == "" {
-		return results
+// searchElementsAdvanced is SearchElementsAdvanced with the parse error kept
+func (a *App) searchElementsAdvanced(root *UINode, query string) ([]SearchResult, error) {
+	if root == nil || strings.TrimSpace(query) == "" {
+		return nil, nil
 	}
+	q, err := compileAdvancedQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return a.searchElementsMatching(root, q), nil
+}
 
-	// Parse OR groups first (lower precedence)
-	orGroups := regexp.MustCompile(`(?i)\s+OR\s+`).Split(query, -1)
-
+// searchElementsMatching walks the tree collecting every node q matches
+func (a *App) searchElementsMatching(root *UINode, q advancedQuery) []SearchResult {
+	var results []SearchResult
 	var search func(node *UINode, path string, depth int, index int)
 	search = func(node *UINode, path string, depth int, index int) {
-		if node == nil {
-			return
-		}
-
-		// Check if any OR group matches
-		anyGroupMatch := false
-		for _, orGroup := range orGroups {
-			// Parse AND conditions within each OR group
-			andParts := regexp.MustCompile(`(?i)\s+AND\s+`).Split(strings.TrimSpace(orGroup), -1)
-
-			allAndMatch := true
-			for _, part := range andParts {
-				part = strings.TrimSpace(part)
-				if part == "" {
-					continue
-				}
-
-				if !a.evaluateCondition(node, part) {
-					allAndMatch = false
-					break
-				}
-			}
-
-			if allAndMatch {
-				anyGroupMatch = true
-				break
-			}
-		}
-
-		if anyGroupMatch {
+		if q.match(a, node) {
 			results = append(results, SearchResult{
 				Node:  node,
 				Path:  path,
@@ -2289,47 +2908,6 @@ func (a *App) SearchElementsAdvanced(root *UINode, query string) []SearchResult
 	return results
 }
 
-// evaluateCondition evaluates a single condition like "text:value" or "clickable=true"
-func (a *App) evaluateCondition(node *UINode, condition string) bool {
-	// Supported operators: : (contains), = (exact), ~ (contains), ^ (starts with), $ (ends with)
-	var attr, op, value string
-
-	// Try different operators in order of specificity
-	for _, operator := range []string{"~", "^", "$", "=", ":"} {
-		if idx := strings.Index(condition, operator); idx != -1 {
-			attr = strings.TrimSpace(condition[:idx])
-			op = operator
-			value = strings.TrimSpace(condition[idx+len(operator):])
-			break
-		}
-	}
-
-	if attr == "" {
-		// No operator found, treat as text contains search
-		lowerCond := strings.ToLower(condition)
-		return strings.Contains(strings.ToLower(node.Text), lowerCond) ||
-			strings.Contains(strings.ToLower(node.ContentDesc), lowerCond) ||
-			strings.Contains(strings.ToLower(node.ResourceID), lowerCond)
-	}
-
-	attrValue := a.getNodeAttribute(node, attr)
-	lowerAttrValue := strings.ToLower(attrValue)
-	lowerValue := strings.ToLower(value)
-
-	switch op {
-	case "=":
-		return lowerAttrValue == lowerValue
-	case ":", "~":
-		return strings.Contains(lowerAttrValue, lowerValue)
-	case "^":
-		return strings.HasPrefix(lowerAttrValue, lowerValue)
-	case "$":
-		return strings.HasSuffix(lowerAttrValue, lowerValue)
-	}
-
-	return false
-}
-
 func buildStepName(prefix, label, fallback string) string {
 	if label != "" {
 		return fmt.Sprintf("%s %q", prefix, label)
@@ -2338,9 +2916,9 @@ func buildStepName(prefix, label, fallback string) string {
 }
 
 // SearchUIElements is the unified search API exposed to frontend
-// Automatically detects query type: XPath (starts with //), Advanced (has :), or simple text
+// Automatically detects query type: XPath (starts with / or (/), Advanced (has :), or simple text
 func (a *App) SearchUIElements(deviceId string, query string) ([]map[string]interface{}, error) {
-	result, err := a.GetUIHierarchy(deviceId)
+	result, err := a.GetUIHierarchy(deviceId, false)
 	if err != nil {
 		return nil, err
 	}
@@ -2348,16 +2926,18 @@ func (a *App) SearchUIElements(deviceId string, query string) ([]map[string]inte
 	var searchResults []SearchResult
 	query = strings.TrimSpace(query)
 
-	if strings.HasPrefix(query, "//") {
+	if strings.HasPrefix(query, "/") || strings.HasPrefix(query, "(/") {
 		// XPath mode
-		searchResults = a.SearchElementsXPath(result.Root, query)
-	} else if strings.Contains(query, ":") || strings.Contains(query, "=") ||
-		regexp.MustCompile(`(?i)\s+(AND|OR)\s+`).MatchString(query) {
-		// Advanced mode
-		searchResults = a.SearchElementsAdvanced(result.Root, query)
-	} else {
-		// Simple text search (default)
-		searchResults = a.SearchElementsAdvanced(result.Root, query)
+		if searchResults, err = a.evalXPath(result.Root, query); err != nil {
+			return nil, fmt.Errorf("invalid XPath: %w", err)
+		}
+	} else if searchResults, err = a.searchElementsAdvanced(result.Root, query); err != nil {
+		if !strings.ContainsAny(query, ":=~^$()") {
+			// Plain text that merely failed to parse (e.g. a trailing "and"): search it as is
+			searchResults = a.searchElementsMatching(result.Root, aqCondition{value: strings.ToLower(query)})
+		} else {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
 	}
 
 	// Convert to frontend-friendly format
@@ -2524,7 +3104,7 @@ func (a *App) captureElementInfoAtPoint(deviceId string, x, y int) *ElementInfo
 	uiHierarchyCacheMu.Unlock()
 
 	// Perform new UI dump
-	result, err := a.GetUIHierarchy(deviceId)
+	result, err := a.GetUIHierarchy(deviceId, true)
 	if err != nil {
 		return nil
 	}
@@ -2586,9 +3166,5 @@ func (a *App) InputNodeText(deviceId string, bounds string, text string) error {
 	// Small delay to ensure focus
 	time.Sleep(200 * time.Millisecond)
 
-	// ADB input text doesn't like spaces directly, replace with %s
-	processedText := strings.ReplaceAll(text, " ", "%s")
-	cmd := fmt.Sprintf("shell input text \"%s\"", processedText)
-	_, err = a.RunAdbCommand(deviceId, cmd)
-	return err
+	return a.typeText(deviceId, text)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
@@ -122,3 +123,91 @@ func (a *App) GetRecordingStatus(deviceId string) map[string]interface{} {
 
 	return result
 }
+
+// recordingSnapshot is the screen dump fast-mode recording resolves taps against. It is taken
+// while the screen is idle, since by the time a tap lifts the app may already be navigating
+// away. At most one dump runs at a time, so quick tap sequences don't pile up uiautomator work.
+type recordingSnapshot struct {
+	mu        sync.Mutex
+	result    *UIHierarchyResult
+	takenAt   time.Time // When the dump started
+	inFlight  bool
+	lastTouch time.Time // Last finger up
+}
+
+// refreshRecordingSnapshot dumps the screen in the background once it has had a moment to
+// settle, unless a dump is already running. A dump overtaken by a touch is thrown away and
+// retried, since it may show the screen mid-change.
+func (a *App) refreshRecordingSnapshot(deviceId string, snap *recordingSnapshot) {
+	snap.mu.Lock()
+	if snap.inFlight {
+		snap.mu.Unlock()
+		return
+	}
+	snap.inFlight = true
+	snap.mu.Unlock()
+
+	go func() {
+		time.Sleep(uiHierarchyMinInterval)
+		start := time.Now()
+		result, err := a.GetUIHierarchy(deviceId, true)
+
+		snap.mu.Lock()
+		snap.inFlight = false
+		stale := snap.lastTouch.After(start)
+		if err == nil && !stale {
+			snap.result, snap.takenAt = result, start
+		}
+		snap.mu.Unlock()
+
+		if err != nil {
+			fmt.Printf("[Automation] Selector capture dump failed: %v\n", err)
+			return
+		}
+		touchRecordMu.Lock()
+		sess, recording := touchRecordData[deviceId]
+		current := recording && sess.snapshot == snap
+		touchRecordMu.Unlock()
+		if stale && current {
+			a.refreshRecordingSnapshot(deviceId, snap)
+		}
+	}()
+}
+
+// recordTapElement attaches the element under a finished touch to the recording, if the
+// snapshot still shows the screen the touch landed on, then starts dumping the next screen
+func (a *App) recordTapElement(deviceId string, snap *recordingSnapshot, x, y int, downAt time.Time) {
+	snap.mu.Lock()
+	result := snap.result
+	usable := result != nil && !snap.takenAt.Before(snap.lastTouch) && snap.takenAt.Before(downAt)
+	snap.result = nil
+	snap.lastTouch = time.Now()
+	snap.mu.Unlock()
+
+	a.refreshRecordingSnapshot(deviceId, snap)
+	if !usable {
+		fmt.Printf("[Automation] No settled snapshot for touch at (%d,%d), keeping coordinates only\n", x, y)
+		return
+	}
+
+	node := a.FindElementAtPoint(result.Root, x, y)
+	if node == nil || node == result.Root {
+		return
+	}
+	info := ElementInfo{
+		X:          x,
+		Y:          y,
+		Class:      node.Class,
+		Bounds:     node.Bounds,
+		Selector:   a.GetBestSelector(node, result.Root),
+		Timestamp:  time.Now().Unix(),
+		Text:       node.Text,
+		ResourceID: node.ResourceID,
+	}
+
+	touchRecordMu.Lock()
+	if sess, ok := touchRecordData[deviceId]; ok && sess.snapshot == snap {
+		sess.ElementInfos = append(sess.ElementInfos, info)
+	}
+	touchRecordMu.Unlock()
+}
@@ -28,7 +28,7 @@ func (a *App) AnalyzeElementSelectors(deviceId string, x, y int, touchTime time.
 		// This happens on the very first screen or if pre-capture failed
 		fmt.Printf("[Automation] No valid PRE-TOUCH cache (started after action or missing). Performing fresh dump...\n")
 		var err error
-		result, err = a.GetUIHierarchy(deviceId)
+		result, err = a.GetUIHierarchy(deviceId, true)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get UI hierarchy: %w", err)
 		}
@@ -234,40 +234,10 @@ func (a *App) AnalyzeElementSelectors(deviceId string, x, y int, touchTime time.
 	return suggestions, elemInfo, nil
 }
 
-// buildXPath builds a simple XPath for the given node
+// buildXPath builds the XPath of the given node, anchored at the nearest ancestor with a
+// unique resource-id when there is one so it survives changes elsewhere in the tree
 func (a *App) buildXPath(root *UINode, target *UINode) string {
-	// Simple implementation: build path from root to target
-	path := a.findNodePath(root, target, "")
-	if path == "" {
-		return ""
-	}
-	return path
-}
-
-// findNodePath recursively finds the path to a target node
-func (a *App) findNodePath(current *UINode, target *UINode, currentPath string) string {
-	if current == target {
-		return currentPath + "/" + getNodeXPathSegment(current)
-	}
-
-	for i := range current.Nodes {
-		newPath := currentPath + "/" + getNodeXPathSegment(current)
-		result := a.findNodePath(&current.Nodes[i], target, newPath)
-		if result != "" {
-			return result
-		}
-	}
-
-	return ""
-}
-
-// getNodeXPathSegment gets the XPath segment for a node
-func getNodeXPathSegment(node *UINode) string {
-	className := node.Class
-	if parts := strings.Split(className, "."); len(parts) > 0 {
-		className = parts[len(parts)-1]
-	}
-	return className
+	return anchoredXPathOf(root, target)
 }
 
 // isUniqueSelector checks if a selector value is unique in the hierarchy
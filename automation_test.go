@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRawEventsPinch(t *testing.T) {
+	session := &TouchRecordingSession{
+		DeviceID:    "emulator-5554",
+		StartTime:   time.Unix(0, 0),
+		RawEvents:   strings.Split(strings.TrimSpace(readTestdata(t, "getevent_pinch.txt")), "\n"),
+		Resolution:  "1080x2340",
+		InputDevice: "/dev/input/event2",
+		MaxX:        1079,
+		MaxY:        2339,
+	}
+	script := (&App{}).parseRawEvents(session, defaultClassifierConfig)
+
+	if len(script.Events) != 2 {
+		t.Fatalf("got %d events, want a pinch and a tap: %+v", len(script.Events), script.Events)
+	}
+
+	pinch := script.Events[0]
+	if pinch.Type != "pinch" {
+		t.Fatalf("first event is %q, want pinch", pinch.Type)
+	}
+	// The gesture ends when the second finger lifts, 220ms after the first went down
+	if pinch.Timestamp != 220 || pinch.Duration != 220 {
+		t.Errorf("pinch timestamp/duration = %d/%d, want 220/220", pinch.Timestamp, pinch.Duration)
+	}
+	wantFingers := []TouchPointer{
+		{X: 500, Y: 1200, X2: 400, Y2: 1100, Delay: 0, Duration: 200},
+		{X: 600, Y: 1300, X2: 700, Y2: 1400, Delay: 30, Duration: 190},
+	}
+	if !reflect.DeepEqual(pinch.Fingers, wantFingers) {
+		t.Errorf("fingers = %+v, want %+v", pinch.Fingers, wantFingers)
+	}
+	if pinch.X != 500 || pinch.Y != 1200 || pinch.X2 != 400 || pinch.Y2 != 1100 {
+		t.Errorf("pinch span = (%d,%d)->(%d,%d), want the first finger's", pinch.X, pinch.Y, pinch.X2, pinch.Y2)
+	}
+
+	// A single finger afterwards is a plain tap again
+	tap := script.Events[1]
+	if tap.Type != "tap" || tap.X != 540 || tap.Y != 1000 || len(tap.Fingers) != 0 {
+		t.Errorf("second event = %+v, want a tap at (540,1000)", tap)
+	}
+	if tap.Timestamp != 960 {
+		t.Errorf("tap timestamp = %d, want 960", tap.Timestamp)
+	}
+
+	if script.RawInput == nil || script.RawInput.Device != "/dev/input/event2" {
+		t.Fatalf("raw input not kept: %+v", script.RawInput)
+	}
+}
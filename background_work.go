@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// jobHeaderRegex starts a registered job: "JOB #u0a123/1001: 8f1c2a3 com.example/.SyncService"
+	jobHeaderRegex = regexp.MustCompile(`^\s+JOB #(\S+?)/(-?\d+): \S+ (\S+)`)
+	// jobHistoryRegex is a "Job history" line: "-1h2m3s4ms   START: #u0a123/1001 com.example/..."
+	jobHistoryRegex = regexp.MustCompile(`^\s+([+-]\S+)\s+(START|STOP)(?:-P)?: #(\S+?/-?\d+) `)
+	// alarmHeaderRegex starts a pending alarm: "RTC_WAKEUP #3: Alarm{7d3a41b type 0 when
+	// 1696150000000 com.example}". Android 12 prints "origWhen <ms> whenElapsed <ms>" instead.
+	alarmHeaderRegex = regexp.MustCompile(`^\s+([A-Z_]+) #\d+: Alarm\{\w+ type \d+ (?:orig)?[wW]hen (-?\d+)(?: whenElapsed -?\d+)? (\S+)\}`)
+	// alarmBatchRegex starts a batch before Android 12: "Batch{3c9b62a num=1 start=1234 end=1234 flgs=0x8}:"
+	alarmBatchRegex = regexp.MustCompile(`^Batch\{(\w+) num=\d+`)
+	// dumpsysFieldRegex matches the key=value pairs on alarm detail lines
+	dumpsysFieldRegex = regexp.MustCompile(`(?:^|\s)(\w+)=(\S+)`)
+)
+
+// parseDumpsysDuration reads Android's TimeUtils.formatDuration output ("+1d2h3m4s5ms", "-10m",
+// "0"). ok is false for "--", "none" and anything else that isn't a duration.
+func parseDumpsysDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), ",")
+	if s == "0" {
+		return 0, true
+	}
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, false
+	}
+	units := map[string]time.Duration{"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute, "s": time.Second, "ms": time.Millisecond}
+	var total time.Duration
+	for s != "" {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		j := i
+		for j < len(s) && s[j] >= 'a' && s[j] <= 'z' {
+			j++
+		}
+		n, err := strconv.Atoi(s[:i])
+		unit, ok := units[s[i:j]]
+		if err != nil || !ok {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+		s = s[j:]
+	}
+	return sign * total, true
+}
+
+// relativeToUnixMs turns a dumpsys relative time into unix milliseconds, or 0 when it has none
+func relativeToUnixMs(s string, now time.Time) int64 {
+	if d, ok := parseDumpsysDuration(s); ok {
+		return now.Add(d).UnixMilli()
+	}
+	return 0
+}
+
+// dumpsysSection returns the lines after the first line starting with one of the headers, up to
+// the next line indented no deeper than the header (keep lets through lines that are, like the
+// unindented batches of older alarm dumps)
+func dumpsysSection(lines []string, headers []string, keep func(string) bool) []string {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		matched := false
+		for _, h := range headers {
+			if strings.HasPrefix(trimmed, h) {
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		end := i + 1
+		for ; end < len(lines); end++ {
+			l := lines[end]
+			if strings.TrimSpace(l) == "" || (keep != nil && keep(l)) {
+				continue
+			}
+			if len(l)-len(strings.TrimLeft(l, " ")) <= indent {
+				break
+			}
+		}
+		return lines[i+1 : end]
+	}
+	return nil
+}
+
+// GetScheduledJobs lists the jobs registered with JobScheduler, limited to one package when
+// packageFilter is set
+func (a *App) GetScheduledJobs(deviceId, packageFilter string) ([]ScheduledJob, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	args := []string{"-s", deviceId, "shell", "dumpsys", "jobscheduler"}
+	if packageFilter != "" {
+		if !packageNameRegex.MatchString(packageFilter) {
+			return nil, fmt.Errorf("invalid package name %q", packageFilter)
+		}
+		// jobscheduler filters its own dump, which keeps it small
+		args = append(args, packageFilter)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, args...).Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to dump jobscheduler: %w", err)
+	}
+	return parseScheduledJobs(string(out), packageFilter, time.Now()), nil
+}
+
+// parseScheduledJobs reads the "Registered N jobs" section, taking last runs from "Job history".
+// Lines of an entry that aren't parsed are kept in its Raw field.
+func parseScheduledJobs(text, packageFilter string, now time.Time) []ScheduledJob {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	lastStart := make(map[string]string)
+	for _, line := range dumpsysSection(lines, []string{"Job history:"}, nil) {
+		if m := jobHistoryRegex.FindStringSubmatch(line); m != nil && m[2] == "START" {
+			lastStart[m[3]] = m[1]
+		}
+	}
+
+	jobs := []ScheduledJob{}
+	var job *ScheduledJob
+	var raw []string
+	flush := func() {
+		if job == nil {
+			return
+		}
+		job.Raw = strings.Join(raw, "\n")
+		if job.LastRun == "" {
+			if t, ok := lastStart[job.UID+"/"+strconv.Itoa(job.JobID)]; ok {
+				job.LastRun = t
+				job.LastRunAt = relativeToUnixMs(t, now)
+			}
+		}
+		if packageFilter == "" || job.Package == packageFilter || strings.HasPrefix(job.Service, packageFilter+"/") {
+			jobs = append(jobs, *job)
+		}
+		job, raw = nil, nil
+	}
+
+	for _, line := range dumpsysSection(lines, []string{"Registered "}, nil) {
+		if m := jobHeaderRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			id, _ := strconv.Atoi(m[2])
+			job = &ScheduledJob{UID: m[1], JobID: id, Service: m[3], Package: strings.Split(m[3], "/")[0]}
+			continue
+		}
+		if job == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(trimmed, ": ")
+		switch {
+		case strings.HasPrefix(trimmed, "Source: "):
+			// Jobs scheduled on behalf of another app (SyncManager, for one) name it here
+			for _, f := range strings.Fields(trimmed) {
+				if p, ok := strings.CutPrefix(f, "pkg="); ok {
+					job.Package = p
+				}
+			}
+		case key == "Required constraints":
+			job.RequiredConstraints = constraintNames(value)
+		case key == "Satisfied constraints":
+			job.SatisfiedConstraints = constraintNames(value)
+		case key == "Unsatisfied constraints":
+			job.UnsatisfiedConstraints = constraintNames(value)
+		case key == "Standby bucket":
+			job.StandbyBucket = value
+		case strings.HasPrefix(trimmed, "PERIODIC: "):
+			for _, f := range strings.Fields(trimmed) {
+				if v, ok := strings.CutPrefix(f, "interval="); ok {
+					job.Interval = v
+				}
+			}
+		case key == "Run time":
+			for _, f := range strings.Fields(value) {
+				f = strings.TrimSuffix(f, ",")
+				if v, ok := strings.CutPrefix(f, "earliest="); ok && v != "none" {
+					job.NextRun = v
+					job.NextRunAt = relativeToUnixMs(v, now)
+				} else if v, ok := strings.CutPrefix(f, "latest="); ok && v != "none" {
+					job.Deadline = v
+					job.DeadlineAt = relativeToUnixMs(v, now)
+				}
+			}
+		case key == "Last successful run":
+			job.LastRun = value
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+				job.LastRunAt = t.UnixMilli()
+			}
+		case key == "Ready":
+			job.Ready = strings.HasPrefix(value, "true")
+		default:
+			raw = append(raw, trimmed)
+		}
+	}
+	flush()
+	return jobs
+}
+
+// constraintNames drops the trailing hex mask from a constraint list ("TIMING_DELAY CONNECTIVITY [0x90000000]")
+func constraintNames(value string) []string {
+	names := []string{}
+	for _, f := range strings.Fields(value) {
+		if !strings.HasPrefix(f, "[") {
+			names = append(names, f)
+		}
+	}
+	return names
+}
+
+// GetScheduledAlarms lists pending AlarmManager alarms grouped by package, limited to one
+// package when packageFilter is set
+func (a *App) GetScheduledAlarms(deviceId, packageFilter string) ([]AlarmPackage, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "dumpsys", "alarm").Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to dump alarms: %w", err)
+	}
+	return parseScheduledAlarms(string(out), packageFilter, time.Now()), nil
+}
+
+// parseScheduledAlarms reads the pending alarms, which are grouped into batches before Android
+// 12 ("Pending alarm batches") and listed flat after it ("Pending alarms")
+func parseScheduledAlarms(text, packageFilter string, now time.Time) []AlarmPackage {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	section := dumpsysSection(lines, []string{"Pending alarm batches:", "Pending alarms:"}, func(l string) bool {
+		return alarmBatchRegex.MatchString(l)
+	})
+
+	byPackage := make(map[string]*AlarmPackage)
+	var alarm *ScheduledAlarm
+	var raw []string
+	batch := ""
+	flush := func() {
+		if alarm == nil {
+			return
+		}
+		alarm.Raw = strings.Join(raw, "\n")
+		if packageFilter == "" || alarm.Package == packageFilter {
+			p, ok := byPackage[alarm.Package]
+			if !ok {
+				p = &AlarmPackage{Package: alarm.Package}
+				byPackage[alarm.Package] = p
+			}
+			p.Alarms = append(p.Alarms, *alarm)
+		}
+		alarm, raw = nil, nil
+	}
+
+	for _, line := range section {
+		if m := alarmBatchRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			batch = m[1]
+			continue
+		}
+		if m := alarmHeaderRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			alarm = &ScheduledAlarm{Type: m[1], Package: m[3], Batch: batch}
+			// RTC alarms carry their wall-clock time; elapsed ones are filled from whenElapsed
+			if strings.HasPrefix(alarm.Type, "RTC") {
+				alarm.TriggerAt, _ = strconv.ParseInt(m[2], 10, 64)
+			}
+			continue
+		}
+		if alarm == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if tag, ok := strings.CutPrefix(trimmed, "tag="); ok {
+			alarm.Tag = tag
+			continue
+		}
+		// The detail lines hold more than is picked out here (counts, flags, the PendingIntent),
+		// so all of them go to Raw
+		raw = append(raw, trimmed)
+		for _, m := range dumpsysFieldRegex.FindAllStringSubmatch(trimmed, -1) {
+			switch m[1] {
+			case "whenElapsed":
+				alarm.TriggerIn = m[2]
+				if alarm.TriggerAt == 0 {
+					alarm.TriggerAt = relativeToUnixMs(m[2], now)
+				}
+			case "window":
+				alarm.Window = m[2]
+			case "repeatInterval":
+				alarm.RepeatInterval = m[2]
+			}
+		}
+	}
+	flush()
+
+	packages := make([]AlarmPackage, 0, len(byPackage))
+	for _, p := range byPackage {
+		sort.SliceStable(p.Alarms, func(i, j int) bool { return p.Alarms[i].TriggerAt < p.Alarms[j].TriggerAt })
+		packages = append(packages, *p)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Package < packages[j].Package })
+	return packages
+}
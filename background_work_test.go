@@ -0,0 +1,262 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// dumpNow is when the testdata dumps were taken (nowRTC in the alarm dumps)
+var dumpNow = time.UnixMilli(1696149400000)
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseDumpsysDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"+1d2h3m4s5ms", 26*time.Hour + 3*time.Minute + 4*time.Second + 5*time.Millisecond, true},
+		{"-10m0s0ms", -10 * time.Minute, true},
+		{"+13m54s877ms,", 13*time.Minute + 54*time.Second + 877*time.Millisecond, true},
+		{"0", 0, true},
+		{"--", 0, false},
+		{"none", 0, false},
+		{"2023-10-01", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDumpsysDuration(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseDumpsysDuration(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseScheduledJobsAndroid9(t *testing.T) {
+	jobs := parseScheduledJobs(readTestdata(t, "jobscheduler_api28.txt"), "", dumpNow)
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+
+	sync := jobs[0]
+	if sync.Package != "com.example" || sync.UID != "u0a50" || sync.JobID != 1 || sync.Service != "com.example/.sync.SyncJobService" {
+		t.Errorf("sync job identity = %+v", sync)
+	}
+	if want := []string{"TIMING_DELAY", "DEADLINE", "CONNECTIVITY"}; !reflect.DeepEqual(sync.RequiredConstraints, want) {
+		t.Errorf("required = %v, want %v", sync.RequiredConstraints, want)
+	}
+	if want := []string{"CONNECTIVITY", "APP_NOT_IDLE", "DEVICE_NOT_DOZING"}; !reflect.DeepEqual(sync.SatisfiedConstraints, want) {
+		t.Errorf("satisfied = %v, want %v", sync.SatisfiedConstraints, want)
+	}
+	if want := []string{"TIMING_DELAY", "DEADLINE"}; !reflect.DeepEqual(sync.UnsatisfiedConstraints, want) {
+		t.Errorf("unsatisfied = %v, want %v", sync.UnsatisfiedConstraints, want)
+	}
+	if sync.StandbyBucket != "ACTIVE" || sync.Interval != "+1h0m0s0ms" || sync.Ready {
+		t.Errorf("bucket/interval/ready = %q %q %t", sync.StandbyBucket, sync.Interval, sync.Ready)
+	}
+	if sync.NextRun != "+49m0s0ms" || sync.NextRunAt != dumpNow.Add(49*time.Minute).UnixMilli() {
+		t.Errorf("next run = %q %d", sync.NextRun, sync.NextRunAt)
+	}
+	if sync.Deadline != "+1h49m0s0ms" || sync.DeadlineAt != dumpNow.Add(109*time.Minute).UnixMilli() {
+		t.Errorf("deadline = %q %d", sync.Deadline, sync.DeadlineAt)
+	}
+	// A persisted last run wins over the history
+	lastRun, _ := time.ParseInLocation("2006-01-02 15:04:05", "2019-03-04 10:15:00", time.Local)
+	if sync.LastRun != "2019-03-04 10:15:00" || sync.LastRunAt != lastRun.UnixMilli() {
+		t.Errorf("last run = %q %d", sync.LastRun, sync.LastRunAt)
+	}
+	wantRaw := "u0a50 tag=*job*/com.example/.sync.SyncJobService\n" +
+		"JobInfo:\n" +
+		"Service: com.example/.sync.SyncJobService\n" +
+		"PERSISTED\n" +
+		"Requires: charging=false batteryNotLow=false deviceIdle=false\n" +
+		"Network type: NetworkRequest [ NONE id=0, [ Capabilities: INTERNET&NOT_RESTRICTED&TRUSTED] ]\n" +
+		"Backoff: policy=1 initial=+30s0ms\n" +
+		"Has early constraint\n" +
+		"Has late constraint\n" +
+		"Tracking: CONNECTIVITY TIME\n" +
+		"Enqueue time: -10m0s0ms"
+	if sync.Raw != wantRaw {
+		t.Errorf("raw =\n%s\nwant\n%s", sync.Raw, wantRaw)
+	}
+
+	upload := jobs[1]
+	if upload.JobID != 7 || upload.StandbyBucket != "WORKING_SET" || upload.NextRun != "" || upload.NextRunAt != 0 || upload.Deadline != "" {
+		t.Errorf("upload job = %+v", upload)
+	}
+	// The latest START in the history, not the first
+	if upload.LastRun != "-5m0s0ms" || upload.LastRunAt != dumpNow.Add(-5*time.Minute).UnixMilli() {
+		t.Errorf("upload last run = %q %d", upload.LastRun, upload.LastRunAt)
+	}
+
+	// SyncManager runs jobs for other apps; Source names the app
+	proxied := jobs[2]
+	if proxied.Package != "com.google.android.gm" || proxied.UID != "1000" || proxied.JobID != 22 || !proxied.Ready {
+		t.Errorf("proxied job = %+v", proxied)
+	}
+
+	filtered := parseScheduledJobs(readTestdata(t, "jobscheduler_api28.txt"), "com.google.android.gm", dumpNow)
+	if len(filtered) != 1 || filtered[0].JobID != 22 {
+		t.Errorf("filter by source package = %+v", filtered)
+	}
+	filtered = parseScheduledJobs(readTestdata(t, "jobscheduler_api28.txt"), "android", dumpNow)
+	if len(filtered) != 1 || filtered[0].JobID != 22 {
+		t.Errorf("filter by service package = %+v", filtered)
+	}
+}
+
+func TestParseScheduledJobsAndroid13(t *testing.T) {
+	jobs := parseScheduledJobs(readTestdata(t, "jobscheduler_api33.txt"), "", dumpNow)
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+
+	work := jobs[0]
+	if work.Package != "com.example" || work.UID != "u0a123" || work.JobID != 1001 {
+		t.Errorf("work job identity = %+v", work)
+	}
+	// The constraint masks are dropped
+	if want := []string{"TIMING_DELAY", "CONNECTIVITY"}; !reflect.DeepEqual(work.RequiredConstraints, want) {
+		t.Errorf("required = %v, want %v", work.RequiredConstraints, want)
+	}
+	if want := []string{"CONNECTIVITY", "DEVICE_NOT_DOZING", "BACKGROUND_NOT_RESTRICTED", "WITHIN_QUOTA"}; !reflect.DeepEqual(work.SatisfiedConstraints, want) {
+		t.Errorf("satisfied = %v, want %v", work.SatisfiedConstraints, want)
+	}
+	if want := []string{"TIMING_DELAY"}; !reflect.DeepEqual(work.UnsatisfiedConstraints, want) {
+		t.Errorf("unsatisfied = %v, want %v", work.UnsatisfiedConstraints, want)
+	}
+	if work.StandbyBucket != "FREQUENT" || work.Interval != "" || work.Ready {
+		t.Errorf("bucket/interval/ready = %q %q %t", work.StandbyBucket, work.Interval, work.Ready)
+	}
+	wantNext := dumpNow.Add(13*time.Minute + 54*time.Second + 877*time.Millisecond).UnixMilli()
+	if work.NextRun != "+13m54s877ms" || work.NextRunAt != wantNext || work.Deadline != "" || work.DeadlineAt != 0 {
+		t.Errorf("run time = %q %d %q %d", work.NextRun, work.NextRunAt, work.Deadline, work.DeadlineAt)
+	}
+	// START-P is a START
+	if work.LastRun != "-5m0s0ms" || work.LastRunAt != dumpNow.Add(-5*time.Minute).UnixMilli() {
+		t.Errorf("last run = %q %d", work.LastRun, work.LastRunAt)
+	}
+	wantRaw := "u0a123 tag=*job*/com.example/androidx.work.impl.background.systemjob.SystemJobService\n" +
+		"JobInfo:\n" +
+		"Service: com.example/androidx.work.impl.background.systemjob.SystemJobService\n" +
+		"Requires: charging=false batteryNotLow=false deviceIdle=false\n" +
+		"Extras: mParcelledData.dataSize=180\n" +
+		"Network type: NetworkRequest [ NONE id=0, [ Capabilities: INTERNET&NOT_RESTRICTED&TRUSTED&VALIDATED Uid: 10123 RequestorUid: 10123 RequestorPkg: com.example UnderlyingNetworks: Null] ]\n" +
+		"Minimum latency: +15m0s0ms\n" +
+		"Backoff: policy=1 initial=+30s0ms\n" +
+		"Has early constraint\n" +
+		"Dynamic constraints:\n" +
+		"Tracking: CONNECTIVITY TIME QUOTA\n" +
+		"Implicit constraints:\n" +
+		"readyNotDozing: true\n" +
+		"readyNotRestrictedInBg: true\n" +
+		"Enqueue time: -1m5s123ms\n" +
+		"Restricted due to: none."
+	if work.Raw != wantRaw {
+		t.Errorf("raw =\n%s\nwant\n%s", work.Raw, wantRaw)
+	}
+
+	refresh := jobs[1]
+	if refresh.Package != "com.other" || refresh.JobID != 42 || refresh.Interval != "+15m0s0ms" || refresh.StandbyBucket != "RARE" {
+		t.Errorf("refresh job = %+v", refresh)
+	}
+	if refresh.NextRunAt != dumpNow.Add(2*time.Minute).UnixMilli() || refresh.DeadlineAt != dumpNow.Add(7*time.Minute).UnixMilli() {
+		t.Errorf("refresh run time = %d %d", refresh.NextRunAt, refresh.DeadlineAt)
+	}
+	if refresh.LastRun != "" || refresh.LastRunAt != 0 {
+		t.Errorf("refresh never ran but last run = %q", refresh.LastRun)
+	}
+
+	if filtered := parseScheduledJobs(readTestdata(t, "jobscheduler_api33.txt"), "com.other", dumpNow); len(filtered) != 1 || filtered[0].JobID != 42 {
+		t.Errorf("filter = %+v", filtered)
+	}
+}
+
+func TestParseScheduledAlarmsAndroid10(t *testing.T) {
+	packages := parseScheduledAlarms(readTestdata(t, "alarm_api29.txt"), "", dumpNow)
+	// com.blocked is under "Pending user blocked background alarms", which isn't pending delivery
+	if len(packages) != 2 || packages[0].Package != "android" || packages[1].Package != "com.example" {
+		t.Fatalf("packages = %+v", packages)
+	}
+
+	tick := packages[0].Alarms
+	if len(tick) != 1 || tick[0].Type != "ELAPSED" || tick[0].Tag != "*alarm*:android.intent.action.TIME_TICK" || tick[0].Batch != "3c9b62a" {
+		t.Fatalf("android alarms = %+v", tick)
+	}
+	if tick[0].TriggerIn != "+30s0ms" || tick[0].TriggerAt != dumpNow.Add(30*time.Second).UnixMilli() {
+		t.Errorf("tick trigger = %q %d", tick[0].TriggerIn, tick[0].TriggerAt)
+	}
+
+	alarms := packages[1].Alarms
+	if len(alarms) != 2 {
+		t.Fatalf("com.example alarms = %+v", alarms)
+	}
+	// Sorted by trigger time
+	reminder, sync := alarms[0], alarms[1]
+	if reminder.Type != "RTC_WAKEUP" || reminder.Tag != "*walarm*:com.example.action.REMINDER" || reminder.Batch != "3c9b62a" {
+		t.Errorf("reminder = %+v", reminder)
+	}
+	// RTC alarms use their wall-clock time
+	if reminder.TriggerAt != 1696150000000 || reminder.TriggerIn != "+9m59s0ms" || reminder.Window != "0" || reminder.RepeatInterval != "0" {
+		t.Errorf("reminder trigger = %+v", reminder)
+	}
+	wantRaw := "type=0 expectedWhenElapsed=+9m59s0ms expectedMaxWhenElapsed=+9m59s0ms whenElapsed=+9m59s0ms maxWhenElapsed=+9m59s0ms when=2023-10-01 08:46:40.000\n" +
+		"window=0 repeatInterval=0 count=0 flags=0x9\n" +
+		"Alarm clock:\n" +
+		"triggerTime=2023-10-01 08:46:40.000\n" +
+		"showIntent=null\n" +
+		"operation=PendingIntent{1a2b3c4: PendingIntentRecord{5d6e7f8 com.example broadcastIntent}}"
+	if reminder.Raw != wantRaw {
+		t.Errorf("raw =\n%s\nwant\n%s", reminder.Raw, wantRaw)
+	}
+	if sync.Type != "ELAPSED_WAKEUP" || sync.Batch != "4b5c6d7" || sync.Window != "+15m0s0ms" || sync.RepeatInterval != "3600000" {
+		t.Errorf("sync = %+v", sync)
+	}
+	if sync.TriggerAt != dumpNow.Add(time.Hour).UnixMilli() {
+		t.Errorf("sync trigger = %d", sync.TriggerAt)
+	}
+
+	if filtered := parseScheduledAlarms(readTestdata(t, "alarm_api29.txt"), "android", dumpNow); len(filtered) != 1 || len(filtered[0].Alarms) != 1 {
+		t.Errorf("filter = %+v", filtered)
+	}
+}
+
+func TestParseScheduledAlarmsAndroid13(t *testing.T) {
+	packages := parseScheduledAlarms(readTestdata(t, "alarm_api33.txt"), "", dumpNow)
+	if len(packages) != 2 || packages[0].Package != "com.example" || packages[1].Package != "com.google.android.gms" {
+		t.Fatalf("packages = %+v", packages)
+	}
+
+	reminder := packages[0].Alarms[0]
+	if reminder.Type != "RTC_WAKEUP" || reminder.Tag != "*walarm*:com.example.action.REMINDER" || reminder.Batch != "" {
+		t.Errorf("reminder = %+v", reminder)
+	}
+	if reminder.TriggerAt != 1696150000000 || reminder.TriggerIn != "+9m59s0ms" || reminder.Window != "+10m0s0ms" || reminder.RepeatInterval != "0" {
+		t.Errorf("reminder trigger = %+v", reminder)
+	}
+	wantRaw := "type=RTC_WAKEUP origWhen=2023-10-01 08:46:40.000 window=+10m0s0ms repeatInterval=0 count=0 flags=0x0\n" +
+		"policyWhenElapsed: requester=+9m59s0ms app_standby=+9m59s0ms device_idle=-- battery_saver=-- tare=+9m59s0ms\n" +
+		"whenElapsed=+9m59s0ms maxWhenElapsed=+19m59s0ms\n" +
+		"operation=PendingIntent{3c4d5e6: PendingIntentRecord{7f8a9b0 com.example broadcastIntent}}"
+	if reminder.Raw != wantRaw {
+		t.Errorf("raw =\n%s\nwant\n%s", reminder.Raw, wantRaw)
+	}
+
+	deadline := packages[1].Alarms[0]
+	if deadline.Type != "ELAPSED_WAKEUP" || deadline.Tag != "*job.deadline*" || deadline.TriggerIn != "+2m0s0ms" {
+		t.Errorf("deadline = %+v", deadline)
+	}
+	if deadline.TriggerAt != dumpNow.Add(2*time.Minute).UnixMilli() {
+		t.Errorf("deadline trigger = %d", deadline.TriggerAt)
+	}
+}
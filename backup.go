@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrBackupNotAllowed is returned when an app opts out of adb backup (android:allowBackup="false")
+var ErrBackupNotAllowed = errors.New("the app does not allow backup")
+
+// emptyBackupSize is the largest .ab that holds no app data: a declined confirmation or an app
+// that excludes itself still produces a header and an empty compressed stream
+const emptyBackupSize = 1536
+
+// pkgFlagsRegex finds the pkgFlags list of dumpsys package ("pkgFlags=[ HAS_CODE ALLOW_BACKUP ]")
+var pkgFlagsRegex = regexp.MustCompile(`pkgFlags=\[([^\]]*)\]`)
+
+// appAllowsBackup reports whether the app sets allowBackup; ok is false when dumpsys didn't say
+func (a *App) appAllowsBackup(deviceId, packageName string) (allowed, ok bool) {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "dumpsys package "+shellQuote(packageName)+" | grep -m1 pkgFlags=").Output()
+	if err != nil {
+		return false, false
+	}
+	m := pkgFlagsRegex.FindStringSubmatch(string(out))
+	if m == nil {
+		return false, false
+	}
+	return strings.Contains(m[1], "ALLOW_BACKUP"), true
+}
+
+// watchFileGrowth emits event with the size of path every second until stop is closed
+func (a *App) watchFileGrowth(path, event string, payload map[string]interface{}, stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		data := map[string]interface{}{"elapsedMs": time.Since(start).Milliseconds()}
+		for k, v := range payload {
+			data[k] = v
+		}
+		if info, err := os.Stat(path); err == nil {
+			data["bytes"] = info.Size()
+		}
+		wailsRuntime.EventsEmit(a.ctx, event, data)
+	}
+}
+
+// BackupApp runs adb backup for one app into destPath (an .ab file; empty saves under the
+// recordings folder). The device asks the user to confirm, so an app-backup-confirm event is
+// sent first and app-backup-progress reports the growing file while the data streams in.
+func (a *App) BackupApp(deviceId, packageName, destPath string, includeApk, includeObb bool) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return "", fmt.Errorf("no package specified")
+	}
+	if allowed, ok := a.appAllowsBackup(deviceId, packageName); ok && !allowed {
+		return "", fmt.Errorf("%w: %s sets android:allowBackup=\"false\"", ErrBackupNotAllowed, packageName)
+	}
+
+	if destPath == "" {
+		dir := filepath.Join(a.GetRecordingsDir(), "Gaze Backups")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create backups folder: %w", err)
+		}
+		destPath = filepath.Join(dir, fmt.Sprintf("%s_%s.ab", packageName, time.Now().Format("20060102_150405")))
+	}
+
+	args := []string{"-s", deviceId, "backup", "-f", destPath, onOff(includeApk, "-apk", "-noapk"), onOff(includeObb, "-obb", "-noobb"), packageName}
+	cmd := a.newAdbCommand(nil, args...)
+
+	wailsRuntime.EventsEmit(a.ctx, "app-backup-confirm", map[string]interface{}{
+		"deviceId": deviceId,
+		"package":  packageName,
+		"action":   "backup",
+		"message":  "Unlock the device and tap \"Back up my data\" to start the backup",
+	})
+	stop := make(chan struct{})
+	go a.watchFileGrowth(destPath, "app-backup-progress", map[string]interface{}{"deviceId": deviceId, "package": packageName}, stop)
+	out, err := a.runTrackedCommand("backup", cmd)
+	close(stop)
+	if err != nil {
+		return "", fmt.Errorf("adb backup failed: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	info, err := os.Stat(destPath)
+	if err != nil {
+		return "", fmt.Errorf("backup file was not written: %w", err)
+	}
+	if info.Size() <= emptyBackupSize {
+		os.Remove(destPath)
+		msg := "the backup is empty: the confirmation was declined or timed out, or the app excludes itself from backup"
+		if a.getSDKInt(deviceId) >= 31 {
+			msg += " (on Android 12+ only debuggable apps can be backed up through adb)"
+		}
+		return "", fmt.Errorf("%w: %s", ErrBackupNotAllowed, msg)
+	}
+	a.Log("Backed up %s from %s to %s (%d bytes)", packageName, deviceId, destPath, info.Size())
+	return destPath, nil
+}
+
+// RestoreApp restores an .ab backup with adb restore. As with backup, the user confirms on
+// the device after the app-backup-confirm event.
+func (a *App) RestoreApp(deviceId, abPath string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	// Encrypted backups are fine here, the device asks for their password
+	if err := checkAbMagic(abPath); err != nil {
+		return err
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "app-backup-confirm", map[string]interface{}{
+		"deviceId": deviceId,
+		"path":     abPath,
+		"action":   "restore",
+		"message":  "Unlock the device and tap \"Restore my data\" to start the restore",
+	})
+	out, err := a.runTrackedCommand("restore", a.newAdbCommand(nil, "-s", deviceId, "restore", abPath))
+	if err != nil {
+		return fmt.Errorf("adb restore failed: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// checkAbMagic makes sure a file starts with the "ANDROID BACKUP" line of an .ab backup
+func checkAbMagic(abPath string) error {
+	f, err := os.Open(abPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "ANDROID BACKUP" {
+		return fmt.Errorf("not an Android backup file")
+	}
+	return nil
+}
+
+// readAbHeader opens an .ab file and reads its header: "ANDROID BACKUP", the format version,
+// the compression flag and the encryption name. The returned reader is positioned at the data.
+func readAbHeader(abPath string) (r *bufio.Reader, f *os.File, compressed bool, err error) {
+	f, err = os.Open(abPath)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to open backup: %w", err)
+	}
+	r = bufio.NewReader(f)
+	header := make([]string, 4)
+	for i := range header {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			f.Close()
+			return nil, nil, false, fmt.Errorf("not an Android backup file: truncated header")
+		}
+		header[i] = strings.TrimSpace(line)
+	}
+	if header[0] != "ANDROID BACKUP" {
+		f.Close()
+		return nil, nil, false, fmt.Errorf("not an Android backup file")
+	}
+	if header[3] != "none" {
+		f.Close()
+		return nil, nil, false, fmt.Errorf("backup is encrypted (%s); only unencrypted backups can be read", header[3])
+	}
+	return r, f, header[2] == "1", nil
+}
+
+// ExtractAbToTar converts an unencrypted .ab backup into a plain tar by dropping the header and
+// inflating the zlib stream
+func (a *App) ExtractAbToTar(abPath, destTar string) error {
+	r, f, compressed, err := readAbHeader(abPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var src io.Reader = r
+	if compressed {
+		z, err := zlib.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("failed to read backup data: %w", err)
+		}
+		defer z.Close()
+		src = z
+	}
+
+	tmp := destTar + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create tar: %w", err)
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to inflate backup: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, destTar)
+}
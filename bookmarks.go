@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRecentPaths is how many visited directories are remembered per device
+const maxRecentPaths = 20
+
+// defaultPathBookmarks are offered on every device
+var defaultPathBookmarks = []PathBookmark{
+	{Path: "/sdcard/Download", Label: "Downloads", Global: true},
+	{Path: "/sdcard/DCIM/Camera", Label: "Camera", Global: true},
+	{Path: "/data/local/tmp", Label: "Temp", Global: true},
+}
+
+// bookmarkStore is the on-disk layout of bookmarks.json
+type bookmarkStore struct {
+	Bookmarks map[string][]PathBookmark `json:"bookmarks"`
+	Recent    map[string][]string       `json:"recent"`
+}
+
+var (
+	bookmarks       *bookmarkStore
+	bookmarksMu     sync.Mutex
+	bookmarksLoaded bool
+)
+
+func (a *App) getBookmarksPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "bookmarks.json")
+}
+
+// loadBookmarksLocked reads bookmarks.json once; callers must hold bookmarksMu
+func (a *App) loadBookmarksLocked() *bookmarkStore {
+	if bookmarksLoaded {
+		return bookmarks
+	}
+	bookmarksLoaded = true
+	bookmarks = &bookmarkStore{
+		Bookmarks: make(map[string][]PathBookmark),
+		Recent:    make(map[string][]string),
+	}
+
+	data, err := os.ReadFile(a.getBookmarksPath())
+	if err != nil {
+		return bookmarks
+	}
+	var stored bookmarkStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return bookmarks
+	}
+	if stored.Bookmarks != nil {
+		bookmarks.Bookmarks = stored.Bookmarks
+	}
+	if stored.Recent != nil {
+		bookmarks.Recent = stored.Recent
+	}
+	return bookmarks
+}
+
+// saveBookmarksLocked writes bookmarks.json; callers must hold bookmarksMu
+func (a *App) saveBookmarksLocked() error {
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getBookmarksPath(), data, 0644)
+}
+
+// AddPathBookmark bookmarks a directory for the device
+func (a *App) AddPathBookmark(deviceId, pathStr, label string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	pathStr = path.Clean("/" + pathStr)
+	if label == "" {
+		label = path.Base(pathStr)
+	}
+
+	bookmarksMu.Lock()
+	defer bookmarksMu.Unlock()
+	store := a.loadBookmarksLocked()
+
+	serial := a.serialFor(deviceId)
+	list := store.Bookmarks[serial]
+	for i, b := range list {
+		if b.Path == pathStr {
+			list[i].Label = label
+			return a.saveBookmarksLocked()
+		}
+	}
+	store.Bookmarks[serial] = append(list, PathBookmark{
+		Path:      pathStr,
+		Label:     label,
+		CreatedAt: time.Now().Unix(),
+	})
+	return a.saveBookmarksLocked()
+}
+
+// RemovePathBookmark deletes a device bookmark
+func (a *App) RemovePathBookmark(deviceId, pathStr string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	pathStr = path.Clean("/" + pathStr)
+
+	bookmarksMu.Lock()
+	defer bookmarksMu.Unlock()
+	store := a.loadBookmarksLocked()
+
+	serial := a.serialFor(deviceId)
+	list := store.Bookmarks[serial]
+	for i, b := range list {
+		if b.Path == pathStr {
+			store.Bookmarks[serial] = append(list[:i], list[i+1:]...)
+			return a.saveBookmarksLocked()
+		}
+	}
+	return fmt.Errorf("bookmark not found: %s", pathStr)
+}
+
+// ListPathBookmarks returns the global defaults followed by the device's own bookmarks,
+// each flagged with whether the path still exists on the device
+func (a *App) ListPathBookmarks(deviceId string) ([]PathBookmark, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	bookmarksMu.Lock()
+	store := a.loadBookmarksLocked()
+	result := append([]PathBookmark{}, defaultPathBookmarks...)
+	result = append(result, store.Bookmarks[a.serialFor(deviceId)]...)
+	bookmarksMu.Unlock()
+
+	existing := a.existingRemotePaths(deviceId, result)
+	for i := range result {
+		result[i].Exists = existing[result[i].Path]
+	}
+	return result, nil
+}
+
+// existingRemotePaths checks all bookmark paths with a single shell call
+func (a *App) existingRemotePaths(deviceId string, list []PathBookmark) map[string]bool {
+	existing := make(map[string]bool)
+	if len(list) == 0 {
+		return existing
+	}
+
+	var quoted []string
+	for _, b := range list {
+		quoted = append(quoted, shellQuote(b.Path))
+	}
+	script := fmt.Sprintf(`for p in %s; do [ -e "$p" ] && echo "$p"; done`, strings.Join(quoted, " "))
+	output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", script).Output()
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			existing[line] = true
+		}
+	}
+	return existing
+}
+
+// recordRecentPath moves pathStr to the front of the device's recent list
+func (a *App) recordRecentPath(deviceId, pathStr string) {
+	bookmarksMu.Lock()
+	defer bookmarksMu.Unlock()
+	store := a.loadBookmarksLocked()
+
+	serial := a.serialFor(deviceId)
+	recent := []string{pathStr}
+	for _, p := range store.Recent[serial] {
+		if p != pathStr && len(recent) < maxRecentPaths {
+			recent = append(recent, p)
+		}
+	}
+	store.Recent[serial] = recent
+	_ = a.saveBookmarksLocked()
+}
+
+// GetRecentPaths returns the most recently listed directories, newest first
+func (a *App) GetRecentPaths(deviceId string) []string {
+	bookmarksMu.Lock()
+	defer bookmarksMu.Unlock()
+	store := a.loadBookmarksLocked()
+
+	recent := store.Recent[a.serialFor(deviceId)]
+	result := make([]string, len(recent))
+	copy(result, recent)
+	return result
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	pngSignature = "\x89PNG\r\n\x1a\n"
+
+	// maxBurstFrames bounds a single burst so a typo cannot fill the disk
+	maxBurstFrames = 1000
+	// burstGifWidth keeps assembled GIFs to a reasonable size
+	burstGifWidth = 480
+)
+
+// screenshotBurst is one StartScreenshotBurst run; the pointer tells a stopped burst from the
+// one started after it
+type screenshotBurst struct {
+	cancel context.CancelFunc
+}
+
+// Screenshot Burst State
+var (
+	bursts  = make(map[string]*screenshotBurst)
+	burstMu sync.Mutex
+)
+
+// StartScreenshotBurst captures count screenshots intervalMs apart into a new folder and
+// optionally assembles them into an animation ("gif" or "mp4"). It returns when the burst
+// finishes or StopScreenshotBurst is called.
+func (a *App) StartScreenshotBurst(deviceId string, intervalMs, count int, assemble string) (*BurstResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if count <= 0 || count > maxBurstFrames {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxBurstFrames)
+	}
+	if intervalMs < 0 {
+		intervalMs = 0
+	}
+	if assemble != "" && assemble != "gif" && assemble != "mp4" {
+		return nil, fmt.Errorf("unsupported animation format: %s", assemble)
+	}
+
+	burstMu.Lock()
+	if _, busy := bursts[deviceId]; busy {
+		burstMu.Unlock()
+		return nil, fmt.Errorf("a screenshot burst is already running on %s", deviceId)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &screenshotBurst{cancel: cancel}
+	bursts[deviceId] = run
+	burstMu.Unlock()
+	defer func() {
+		burstMu.Lock()
+		if bursts[deviceId] == run {
+			delete(bursts, deviceId)
+		}
+		burstMu.Unlock()
+		cancel()
+	}()
+
+	model, _ := a.RunAdbCommand(deviceId, "shell getprop ro.product.model")
+	shotPath, _ := a.SelectScreenshotPath(model)
+	dir := filepath.Join(filepath.Dir(shotPath), "Burst"+strings.TrimPrefix(strings.TrimSuffix(filepath.Base(shotPath), ".png"), "Screenshot"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create burst folder: %w", err)
+	}
+
+	result := &BurstResult{DeviceID: deviceId, Dir: dir, Requested: count, IntervalMs: intervalMs, Frames: []BurstFrame{}}
+
+	shell, err := a.openScreencapShell(ctx, deviceId)
+	if err != nil {
+		a.Log("Persistent screencap shell unavailable on %s, capturing per shot: %v", deviceId, err)
+	}
+	defer func() {
+		if shell != nil {
+			shell.close()
+		}
+	}()
+
+	start := time.Now()
+	var last time.Time
+	for i := 0; i < count; i++ {
+		// Schedule against the start time so slow shots don't push every later frame back
+		if wait := time.Until(start.Add(time.Duration(i*intervalMs) * time.Millisecond)); wait > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		var data []byte
+		if shell != nil {
+			if data, err = shell.capture(); err != nil {
+				a.Log("Persistent screencap failed on %s, falling back: %v", deviceId, err)
+				shell.close()
+				shell = nil
+			}
+		}
+		if shell == nil {
+			data, err = a.captureScreenPNG(ctx, deviceId)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		now := time.Now()
+		frame := BurstFrame{
+			Index:    i,
+			Path:     filepath.Join(dir, fmt.Sprintf("frame_%04d.png", i+1)),
+			OffsetMs: now.Sub(start).Milliseconds(),
+		}
+		if !last.IsZero() {
+			frame.IntervalMs = now.Sub(last).Milliseconds()
+		}
+		last = now
+
+		if err := os.WriteFile(frame.Path, data, 0644); err != nil {
+			return result, fmt.Errorf("failed to save frame: %w", err)
+		}
+		result.Frames = append(result.Frames, frame)
+
+		wailsRuntime.EventsEmit(a.ctx, "screenshot-burst-progress", map[string]interface{}{
+			"deviceId": deviceId,
+			"frame":    frame,
+			"count":    count,
+		})
+	}
+
+	result.PersistentShell = shell != nil
+	result.Cancelled = ctx.Err() != nil
+	if n := len(result.Frames); n > 1 {
+		result.AvgIntervalMs = (result.Frames[n-1].OffsetMs - result.Frames[0].OffsetMs) / int64(n-1)
+	}
+
+	if assemble != "" && len(result.Frames) > 0 {
+		var animErr error
+		if assemble == "gif" {
+			result.AnimationPath = filepath.Join(dir, "burst.gif")
+			animErr = writeBurstGif(result.Frames, result.AvgIntervalMs, result.AnimationPath)
+		} else {
+			result.AnimationPath = filepath.Join(dir, "burst.mp4")
+			animErr = writeBurstVideo(dir, result.AvgIntervalMs, result.AnimationPath)
+		}
+		if animErr != nil {
+			result.AnimationPath = ""
+			return result, fmt.Errorf("frames saved but %s assembly failed: %w", assemble, animErr)
+		}
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "screenshot-burst-complete", result)
+	return result, nil
+}
+
+// StopScreenshotBurst ends a running burst early; frames captured so far are kept
+func (a *App) StopScreenshotBurst(deviceId string) {
+	burstMu.Lock()
+	defer burstMu.Unlock()
+
+	if run, ok := bursts[deviceId]; ok {
+		run.cancel()
+		delete(bursts, deviceId)
+	}
+}
+
+// screencapShell is a long-lived non-pty adb shell that runs screencap on demand, saving the
+// adb connection setup on every frame
+type screencapShell struct {
+	a     *App
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   *bufio.Reader
+}
+
+func (a *App) openScreencapShell(ctx context.Context, deviceId string) (*screencapShell, error) {
+	cmd := a.newAdbCommand(ctx, "-s", deviceId, "shell", "-T")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	a.trackProcess("screenshot-burst", cmd, nil)
+
+	s := &screencapShell{a: a, cmd: cmd, stdin: stdin, out: bufio.NewReaderSize(stdout, 256*1024)}
+	// Probe once so a shell that mangles binary output is caught before the first real frame
+	if _, err := s.capture(); err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// capture asks the shell for one PNG and reads exactly that image back
+func (s *screencapShell) capture() ([]byte, error) {
+	if _, err := io.WriteString(s.stdin, "screencap -p 2>/dev/null\n"); err != nil {
+		return nil, err
+	}
+	// A hung device would block the read forever; killing the shell unblocks it
+	timer := time.AfterFunc(10*time.Second, func() { _ = s.cmd.Process.Kill() })
+	defer timer.Stop()
+	return readPNGStream(s.out)
+}
+
+func (s *screencapShell) close() {
+	_ = s.stdin.Close()
+	_ = s.cmd.Process.Kill()
+	_ = s.cmd.Wait()
+	s.a.untrackProcess(s.cmd)
+}
+
+// readPNGStream reads one PNG from r by walking its chunks up to IEND
+func readPNGStream(r io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	sig := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(r, sig); err != nil {
+		return nil, err
+	}
+	if string(sig) != pngSignature {
+		return nil, fmt.Errorf("unexpected screencap output")
+	}
+	buf.Write(sig)
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(header[:4])
+		if length > 64<<20 {
+			return nil, fmt.Errorf("corrupt PNG chunk")
+		}
+		buf.Write(header)
+		// chunk data plus CRC
+		if _, err := io.CopyN(&buf, r, int64(length)+4); err != nil {
+			return nil, err
+		}
+		if string(header[4:]) == "IEND" {
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+// writeBurstGif encodes the frames as an animated GIF, using the measured intervals as delays
+func writeBurstGif(frames []BurstFrame, avgIntervalMs int64, outPath string) error {
+	anim := &gif.GIF{}
+	for i, frame := range frames {
+		f, err := os.Open(frame.Path)
+		if err != nil {
+			return err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", filepath.Base(frame.Path), err)
+		}
+
+		img = scaleToWidth(img, burstGifWidth)
+		paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, img.Bounds().Min)
+
+		// GIF delays are in hundredths of a second; a frame is shown until the next one was taken
+		delayMs := avgIntervalMs
+		if i+1 < len(frames) {
+			delayMs = frames[i+1].IntervalMs
+		}
+		if delayMs < 20 {
+			delayMs = 20
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(delayMs/10))
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return gif.EncodeAll(out, anim)
+}
+
+// writeBurstVideo encodes frame_NNNN.png files into an mp4 with ffmpeg at the average frame rate
+func writeBurstVideo(dir string, avgIntervalMs int64, outPath string) error {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found on PATH")
+	}
+	fps := 10.0
+	if avgIntervalMs > 0 {
+		fps = 1000.0 / float64(avgIntervalMs)
+	}
+	out, err := exec.Command(ffmpeg, "-y",
+		"-framerate", fmt.Sprintf("%.3f", fps),
+		"-i", filepath.Join(dir, "frame_%04d.png"),
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-pix_fmt", "yuv420p",
+		outPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed: %w, output: %s", err, lastLines(string(out), 3))
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultCameraRollFolders are where camera apps and the screenshot service save by default
+var defaultCameraRollFolders = []string{"/sdcard/DCIM/Camera", "/sdcard/DCIM/Screenshots", "/sdcard/Pictures/Screenshots"}
+
+// cameraRollManifestName is the file in the destination folder that remembers what was pulled
+const cameraRollManifestName = ".gaze-camera-roll.json"
+
+// cameraRollEntry is a pulled device file, identified by its size and modification time
+type cameraRollEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	Local   string `json:"local"` // Relative to the destination folder
+}
+
+// cameraRollManifest maps device serial to remote path to what was pulled from it
+type cameraRollManifest struct {
+	Devices map[string]map[string]cameraRollEntry `json:"devices"`
+}
+
+// cameraRollFile is a file found in one of the camera roll folders
+type cameraRollFile struct {
+	transferFile
+	ModTime int64
+	Rel     string
+}
+
+func loadCameraRollManifest(localDir string) *cameraRollManifest {
+	m := &cameraRollManifest{Devices: make(map[string]map[string]cameraRollEntry)}
+	data, err := os.ReadFile(filepath.Join(localDir, cameraRollManifestName))
+	if err == nil {
+		json.Unmarshal(data, m)
+	}
+	if m.Devices == nil {
+		m.Devices = make(map[string]map[string]cameraRollEntry)
+	}
+	return m
+}
+
+func (m *cameraRollManifest) save(localDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(localDir, cameraRollManifestName), data, 0644)
+}
+
+// parseCameraRollListing parses "size|mtime|path" lines from stat, mapping each file to
+// <folder name>/<file name> under localDir
+func parseCameraRollListing(output, localDir string) []cameraRollFile {
+	var files []cameraRollFile
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimRight(line, "\r"), "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		size, err1 := strconv.ParseInt(parts[0], 10, 64)
+		mtime, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		remote := path.Clean(parts[2])
+		if strings.HasPrefix(path.Base(remote), ".") {
+			// .pending-* and .trashed-* files belong to MediaStore
+			continue
+		}
+		rel := path.Join(path.Base(path.Dir(remote)), path.Base(remote))
+		files = append(files, cameraRollFile{
+			transferFile: transferFile{Remote: remote, Local: filepath.Join(localDir, filepath.FromSlash(rel)), Size: size},
+			ModTime:      mtime,
+			Rel:          rel,
+		})
+	}
+	return files
+}
+
+// PullCameraRoll copies new photos, videos and screenshots into localDir. Files already pulled
+// (same size and modification time, per the manifest in localDir or the local copy) are skipped.
+// With opts.DeleteAfter each file is removed from the device once its checksum matches.
+func (a *App) PullCameraRoll(deviceId, localDir string, opts CameraRollOptions) (*CameraRollSummary, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if localDir == "" {
+		return nil, fmt.Errorf("no destination folder specified")
+	}
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination folder: %w", err)
+	}
+	folders := opts.Folders
+	if len(folders) == 0 {
+		folders = defaultCameraRollFolders
+	}
+	quoted := make([]string, len(folders))
+	for i, f := range folders {
+		quoted[i] = shellQuote(path.Clean("/" + f))
+	}
+
+	script := fmt.Sprintf("for d in %s; do [ -d \"$d\" ] && find \"$d/\" -maxdepth 1 -type f -exec stat -c '%%s|%%Y|%%n' {} +; done", strings.Join(quoted, " "))
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to list camera roll: %w", err)
+	}
+	files := parseCameraRollListing(string(out), localDir)
+
+	serial := a.serialFor(deviceId)
+	manifest := loadCameraRollManifest(localDir)
+	pulled := manifest.Devices[serial]
+	if pulled == nil {
+		pulled = make(map[string]cameraRollEntry)
+		manifest.Devices[serial] = pulled
+	}
+
+	summary := &CameraRollSummary{Found: len(files), FailedFiles: []string{}}
+	var pending []cameraRollFile
+	for _, f := range files {
+		if (opts.Since > 0 && f.ModTime < opts.Since) || (opts.Until > 0 && f.ModTime > opts.Until) {
+			continue
+		}
+		if e, ok := pulled[f.Remote]; ok && e.Size == f.Size && e.ModTime == f.ModTime {
+			summary.Skipped++
+			continue
+		}
+		if info, err := os.Stat(f.Local); err == nil && info.Size() == f.Size && info.ModTime().Unix() == f.ModTime {
+			pulled[f.Remote] = cameraRollEntry{Size: f.Size, ModTime: f.ModTime, Local: f.Rel}
+			summary.Skipped++
+			continue
+		}
+		pending = append(pending, f)
+	}
+
+	transferId, ctx := beginTransfer()
+	defer endTransfer(transferId)
+	summary.TransferID = transferId
+
+	algo := ""
+	if opts.DeleteAfter {
+		// Only a checksum match is good enough to delete the original
+		algo = a.detectChecksumAlgo(deviceId)
+		if algo == "" {
+			return nil, fmt.Errorf("the device has no checksum tool, so pulled files can't be verified for deletion")
+		}
+	}
+	localSize := func(f transferFile) int64 {
+		if info, err := os.Stat(f.Local); err == nil {
+			return info.Size()
+		}
+		return 0
+	}
+
+	start := time.Now()
+	var deleted []transferFile
+	for i, f := range pending {
+		if ctx.Err() != nil {
+			summary.Cancelled = true
+			break
+		}
+		wailsRuntime.EventsEmit(a.ctx, "camera-roll-progress", map[string]interface{}{
+			"deviceId":   deviceId,
+			"transferId": transferId,
+			"file":       f.Rel,
+			"index":      i,
+			"count":      len(pending),
+		})
+		verifyFile := func(t transferFile) error {
+			if n := localSize(t); n != t.Size {
+				return fmt.Errorf("%w for %s: size differs, device %d bytes, local %d bytes", ErrChecksumMismatch, t.Remote, t.Size, n)
+			}
+			if algo != "" {
+				return a.compareChecksums(deviceId, t, algo)
+			}
+			return nil
+		}
+		if _, err := a.runTransfer(ctx, transferId, deviceId, "pull", []transferFile{f.transferFile}, localSize, verifyFile); err != nil {
+			if ctx.Err() != nil {
+				summary.Cancelled = true
+				break
+			}
+			a.Log("Camera roll pull of %s failed: %v", f.Remote, err)
+			summary.Failed++
+			summary.FailedFiles = append(summary.FailedFiles, f.Remote)
+			continue
+		}
+		mtime := time.Unix(f.ModTime, 0)
+		os.Chtimes(f.Local, mtime, mtime)
+		pulled[f.Remote] = cameraRollEntry{Size: f.Size, ModTime: f.ModTime, Local: f.Rel}
+		summary.New++
+		summary.Bytes += f.Size
+
+		if opts.DeleteAfter {
+			out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "rm "+shellQuote(f.Remote)).CombinedOutput()
+			if err != nil {
+				a.Log("Could not delete %s after pulling: %v, %s", f.Remote, err, strings.TrimSpace(string(out)))
+				continue
+			}
+			delete(pulled, f.Remote)
+			deleted = append(deleted, f.transferFile)
+			summary.FreedBytes += f.Size
+		}
+	}
+	summary.DurationMs = time.Since(start).Milliseconds()
+
+	if len(deleted) > 0 {
+		// Rescanning a missing file drops it from the gallery
+		a.scanMediaFiles(deviceId, deleted)
+	}
+	if err := manifest.save(localDir); err != nil {
+		a.Log("Failed to save camera roll manifest: %v", err)
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "camera-roll-completed", map[string]interface{}{
+		"deviceId": deviceId,
+		"summary":  summary,
+	})
+	return summary, nil
+}
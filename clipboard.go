@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	// ErrClipboardUnsupported means the device offers no way to reach its clipboard from adb
+	ErrClipboardUnsupported = errors.New("clipboard access is not supported on this device")
+	// ErrClipboardUnsupportedContent means the clipboard holds something other than text
+	ErrClipboardUnsupportedContent = errors.New("clipboard holds non-text content")
+)
+
+// clipperDataRegex pulls the data out of the Clipper helper app's broadcast result
+var clipperDataRegex = regexp.MustCompile(`(?s)data="(.*)"\s*$`)
+
+// unsupportedShellCommand reports output meaning the service has no such shell command
+func unsupportedShellCommand(out string) bool {
+	for _, marker := range []string{"Unknown command", "No shell command implementation", "Can't find service", "Unknown option", "Error: unknown"} {
+		if strings.Contains(out, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClipData extracts the text from a ClipData dump like `ClipData { text/plain "label"
+// {T:hello} }`. Items that are URIs, intents or HTML are reported as unsupported content.
+func parseClipData(out string) (string, error) {
+	out = strings.TrimRight(out, "\r\n")
+	if out == "" || out == "null" {
+		return "", nil
+	}
+	if !strings.HasPrefix(out, "ClipData {") {
+		return out, nil
+	}
+	if i := strings.Index(out, "{T:"); i >= 0 {
+		text := out[i+len("{T:"):]
+		// Drop the closing braces of the item and the ClipData
+		text = strings.TrimSuffix(strings.TrimRight(text, " "), "}")
+		text = strings.TrimSuffix(strings.TrimRight(text, " "), "}")
+		return text, nil
+	}
+	for _, item := range []string{"{U:", "{I:", "{H:"} {
+		if strings.Contains(out, item) {
+			return "", ErrClipboardUnsupportedContent
+		}
+	}
+	return "", nil
+}
+
+// GetDeviceClipboard returns the device's clipboard text. Android's clipboard service shell
+// command is used where the build has it, otherwise the Clipper helper app if it's installed.
+func (a *App) GetDeviceClipboard(deviceId string) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "cmd clipboard get-primary-clip").CombinedOutput()
+	if err == nil && !unsupportedShellCommand(string(out)) {
+		return parseClipData(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	}
+
+	out, err = a.newAdbCommand(nil, "-s", deviceId, "shell", "am broadcast -a clipper.get").CombinedOutput()
+	if err == nil {
+		text := strings.ReplaceAll(string(out), "\r\n", "\n")
+		if m := clipperDataRegex.FindStringSubmatch(text); m != nil {
+			return m[1], nil
+		}
+		if strings.Contains(text, "result=-1") || strings.Contains(text, "result=0") {
+			// Clipper answers with no data when the clipboard is empty or not text
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("%w: needs Android's clipboard shell command or the Clipper app", ErrClipboardUnsupported)
+}
+
+// SetDeviceClipboard puts text on the device's clipboard
+func (a *App) SetDeviceClipboard(deviceId, text string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "cmd clipboard set-primary-clip "+shellQuote(text)).CombinedOutput()
+	if err == nil && !unsupportedShellCommand(string(out)) {
+		return nil
+	}
+
+	out, err = a.newAdbCommand(nil, "-s", deviceId, "shell", "am broadcast -a clipper.set -e text "+shellQuote(text)).CombinedOutput()
+	if err == nil && strings.Contains(string(out), "result=-1") {
+		return nil
+	}
+	return fmt.Errorf("%w: needs Android's clipboard shell command or the Clipper app", ErrClipboardUnsupported)
+}
+
+// SyncClipboard copies clipboard text between the desktop and the device. direction is
+// "to_device" or "from_device"; the copied text is returned.
+func (a *App) SyncClipboard(deviceId, direction string) (string, error) {
+	switch direction {
+	case "to_device":
+		text, err := wailsRuntime.ClipboardGetText(a.ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to read desktop clipboard: %w", err)
+		}
+		if err := a.SetDeviceClipboard(deviceId, text); err != nil {
+			return "", err
+		}
+		return text, nil
+	case "from_device":
+		text, err := a.GetDeviceClipboard(deviceId)
+		if err != nil {
+			return "", err
+		}
+		if err := wailsRuntime.ClipboardSetText(a.ctx, text); err != nil {
+			return "", fmt.Errorf("failed to write desktop clipboard: %w", err)
+		}
+		return text, nil
+	default:
+		return "", fmt.Errorf("invalid direction %q: expected to_device or from_device", direction)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// commandFlushInterval bounds how long streamed output waits before it is emitted; lines are
+// batched so a chatty command doesn't flood the frontend with one event per line
+const (
+	commandFlushInterval = 100 * time.Millisecond
+	commandFlushLines    = 500
+)
+
+// commandExecution is an adb command whose output is consumed line by line while it runs
+type commandExecution struct {
+	ID       string
+	DeviceID string
+	cmd      *exec.Cmd
+	cancel   context.CancelFunc
+	started  time.Time
+	done     chan struct{}
+
+	exitCode int
+	err      error
+}
+
+// CommandOutputLine is one line of streamed command output
+type CommandOutputLine struct {
+	Stream string `json:"stream"` // "stdout" or "stderr"
+	Line   string `json:"line"`
+}
+
+var (
+	commandExecs   = make(map[string]*commandExecution)
+	commandExecsMu sync.Mutex
+	commandSeq     int64
+)
+
+// startCommandExecution runs `adb -s deviceId args...` in its own process group and calls
+// onLine for every output line, one call at a time. Cancelling ctx, or CancelCommand with the
+// returned ID, kills the whole group.
+func (a *App) startCommandExecution(ctx context.Context, deviceId string, args []string, onLine func(stream, line string)) (*commandExecution, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := a.newAdbCommand(ctx, append([]string{"-s", deviceId}, args...)...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start adb: %w", err)
+	}
+	a.trackProcess("command", cmd, cancel)
+
+	commandExecsMu.Lock()
+	commandSeq++
+	e := &commandExecution{
+		ID:       fmt.Sprintf("cmd_%d_%d", time.Now().Unix(), commandSeq),
+		DeviceID: deviceId,
+		cmd:      cmd,
+		cancel:   cancel,
+		started:  time.Now(),
+		done:     make(chan struct{}),
+	}
+	commandExecs[e.ID] = e
+	commandExecsMu.Unlock()
+
+	var lineMu sync.Mutex
+	var wg sync.WaitGroup
+	scan := func(stream string, r io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			lineMu.Lock()
+			onLine(stream, line)
+			lineMu.Unlock()
+		}
+	}
+	wg.Add(2)
+	go scan("stdout", stdout)
+	go scan("stderr", stderr)
+
+	go func() {
+		wg.Wait()
+		waitErr := cmd.Wait()
+		cancelled := ctx.Err() != nil
+		a.untrackProcess(cmd)
+		cancel()
+
+		e.exitCode = 0
+		if waitErr != nil {
+			e.exitCode = -1
+			var exitErr *exec.ExitError
+			if errors.As(waitErr, &exitErr) {
+				e.exitCode = exitErr.ExitCode()
+			}
+			e.err = waitErr
+		}
+		if cancelled {
+			e.err = context.Canceled
+		}
+
+		commandExecsMu.Lock()
+		delete(commandExecs, e.ID)
+		commandExecsMu.Unlock()
+		close(e.done)
+	}()
+	return e, nil
+}
+
+// wait blocks until the command has exited and its output has been consumed
+func (e *commandExecution) wait() (int, error) {
+	<-e.done
+	return e.exitCode, e.err
+}
+
+// runStreamedCommand is startCommandExecution for callers that wait for the result
+func (a *App) runStreamedCommand(ctx context.Context, deviceId string, args []string, onLine func(stream, line string)) (int, error) {
+	e, err := a.startCommandExecution(ctx, deviceId, args, onLine)
+	if err != nil {
+		return -1, err
+	}
+	return e.wait()
+}
+
+// RunAdbCommandStreamed starts `adb -s deviceId args...` and returns its execution ID at once.
+// Output arrives as command-output:<id> events carrying batches of lines, followed by a single
+// command-finished:<id> with the exit code and duration.
+func (a *App) RunAdbCommandStreamed(deviceId string, args []string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("no command specified")
+	}
+	// There is no confirm token here; commands that need one go through RunAdbCommandConfirmed
+	if err := a.guardDangerousCommand(deviceId, strings.Join(args, " "), ""); err != nil {
+		return "", err
+	}
+
+	var mu sync.Mutex
+	var pending []CommandOutputLine
+	var lastFlush time.Time
+	var e *commandExecution
+	started := make(chan struct{})
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		wailsRuntime.EventsEmit(a.ctx, "command-output:"+e.ID, pending)
+		pending = nil
+		lastFlush = time.Now()
+	}
+
+	e, err := a.startCommandExecution(nil, deviceId, args, func(stream, line string) {
+		<-started
+		mu.Lock()
+		defer mu.Unlock()
+		pending = append(pending, CommandOutputLine{Stream: stream, Line: line})
+		if len(pending) >= commandFlushLines || time.Since(lastFlush) >= commandFlushInterval {
+			flush()
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	close(started)
+	a.updateLastActive(deviceId)
+
+	go func() {
+		// Quiet stretches would otherwise hold the last lines back until the next one arrives
+		ticker := time.NewTicker(commandFlushInterval)
+		defer ticker.Stop()
+	wait:
+		for {
+			select {
+			case <-e.done:
+				break wait
+			case <-ticker.C:
+				mu.Lock()
+				flush()
+				mu.Unlock()
+			}
+		}
+
+		mu.Lock()
+		flush()
+		mu.Unlock()
+		exitCode, err := e.wait()
+		payload := map[string]interface{}{
+			"deviceId":   deviceId,
+			"exitCode":   exitCode,
+			"durationMs": time.Since(e.started).Milliseconds(),
+			"cancelled":  err == context.Canceled,
+		}
+		if err != nil && err != context.Canceled {
+			payload["error"] = err.Error()
+		}
+		wailsRuntime.EventsEmit(a.ctx, "command-finished:"+e.ID, payload)
+	}()
+	return e.ID, nil
+}
+
+// CancelCommand kills a running command started by RunAdbCommandStreamed, including anything
+// it spawned
+func (a *App) CancelCommand(id string) error {
+	commandExecsMu.Lock()
+	e, ok := commandExecs[id]
+	commandExecsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("command not running: %s", id)
+	}
+	e.cancel()
+	return nil
+}
+
+// runMergedStreamed runs cmd with stdout and stderr merged and calls onLine for every non-empty
+// line. A bare \r ends a line too, so progress that redraws itself arrives as it's drawn. The last
+// few lines are returned for error messages.
+func (a *App) runMergedStreamed(kind string, cmd *exec.Cmd, cancel context.CancelFunc, onLine func(line string)) ([]string, error) {
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", kind, err)
+	}
+	a.trackProcess(kind, cmd, cancel)
+	defer a.untrackProcess(cmd)
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+		pw.Close()
+	}()
+
+	var tail []string
+	scanner := bufio.NewScanner(pr)
+	scanner.Split(scanLinesOrCR)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		tail = append(tail, line)
+		if len(tail) > 3 {
+			tail = tail[1:]
+		}
+		onLine(line)
+	}
+	return tail, <-waitErr
+}
+
+// scanLinesOrCR is bufio.ScanLines that also ends a line at a bare \r
+func scanLinesOrCR(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		if b == '\n' || b == '\r' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
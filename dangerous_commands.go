@@ -0,0 +1,256 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrConfirmationRequired is returned when a command matches the dangerous-command policy and
+// no valid confirm token was given
+var ErrConfirmationRequired = errors.New("confirmation required")
+
+// dangerousConfirmTTL is how long a confirm token from CheckDangerousCommand stays valid
+const dangerousConfirmTTL = 2 * time.Minute
+
+// builtinDangerousRules are checked unless disabled in the policy. Patterns match anywhere in
+// the command, with or without the leading "shell ".
+var builtinDangerousRules = []DangerousCommandRule{
+	{
+		ID:          "rm_broad",
+		Description: "Recursive delete of a top-level directory",
+		Pattern:     `\brm\s+(?:-\S+\s+)*-[a-zA-Z]*[rR][a-zA-Z]*\s+(?:-\S+\s+)*['"]?(?:/|/sdcard|/storage(?:/emulated(?:/0)?)?|/data(?:/data|/media(?:/0)?|/local)?|/system|/vendor|\*|\.|~)/?\*?['"]?(?:\s|;|&|\||$)`,
+	},
+	{
+		ID:          "reboot_special",
+		Description: "Reboot into bootloader, recovery or download mode",
+		Pattern:     `\breboot\s+(?:-\S+\s+)*(?:bootloader|recovery|fastboot|sideload|edl|download)\b`,
+	},
+	{
+		ID:          "uninstall_critical",
+		Description: "Uninstall of a core system package",
+		Pattern:     `\buninstall\s+(?:(?:-k|--user\s+\S+|-\S+)\s+)*(?:android|com\.android\.(?:systemui|settings|phone|shell|packageinstaller|providers\.\w+)|com\.google\.android\.(?:gms|gsf))(?:\s|;|&|\||$)`,
+	},
+	{
+		ID:          "wipe",
+		Description: "Wipe or factory reset",
+		Pattern:     `(?i)\bwipe\b|--wipe_data|MASTER_CLEAR|FACTORY_RESET`,
+	},
+	{
+		ID:          "fastboot_flash",
+		Description: "Flash or erase a partition with fastboot",
+		Pattern:     `\bfastboot\s+(?:-\S+\s+\S+\s+)*(?:flash|flashall|erase|format|update|flashing)\b`,
+	},
+	{
+		ID:          "dd_block",
+		Description: "Raw write to a block device",
+		Pattern:     `\bdd\b.*\bof=/dev/block/`,
+	},
+	{
+		ID:          "format_block",
+		Description: "Format a partition",
+		Pattern:     `\b(?:mkfs(?:\.\w+)?|mke2fs|make_ext4fs)\b`,
+	},
+}
+
+// compiledDangerousRule is an enabled rule with its regex
+type compiledDangerousRule struct {
+	rule DangerousCommandRule
+	re   *regexp.Regexp
+}
+
+// dangerousConfirmation is an outstanding confirm token
+type dangerousConfirmation struct {
+	command string
+	expires time.Time
+}
+
+var (
+	builtinDangerousCompiled = compileBuiltinDangerousRules()
+
+	dangerousConfirmations   = make(map[string]dangerousConfirmation)
+	dangerousConfirmationsMu sync.Mutex
+)
+
+func compileBuiltinDangerousRules() map[string]*regexp.Regexp {
+	compiled := make(map[string]*regexp.Regexp, len(builtinDangerousRules))
+	for _, rule := range builtinDangerousRules {
+		compiled[rule.ID] = regexp.MustCompile(rule.Pattern)
+	}
+	return compiled
+}
+
+// GetDangerousCommandPolicy returns the built-in rules, with their enabled state, followed by
+// the user-defined ones
+func (a *App) GetDangerousCommandPolicy() DangerousCommandPolicy {
+	a.dangerousMu.RLock()
+	defer a.dangerousMu.RUnlock()
+
+	policy := DangerousCommandPolicy{Enabled: !a.dangerousSettings.Disabled}
+	disabled := make(map[string]bool, len(a.dangerousSettings.DisabledBuiltins))
+	for _, id := range a.dangerousSettings.DisabledBuiltins {
+		disabled[id] = true
+	}
+	for _, rule := range builtinDangerousRules {
+		rule.Builtin = true
+		rule.Enabled = !disabled[rule.ID]
+		policy.Rules = append(policy.Rules, rule)
+	}
+	policy.Rules = append(policy.Rules, a.dangerousSettings.Custom...)
+	return policy
+}
+
+// UpdateDangerousCommandPolicy replaces the policy. Built-in rules can only be switched on or
+// off; every other rule is user-defined and its pattern must be a valid regular expression.
+func (a *App) UpdateDangerousCommandPolicy(policy DangerousCommandPolicy) error {
+	settings := dangerousCommandSettings{Disabled: !policy.Enabled}
+	var custom []compiledDangerousRule
+	seen := make(map[string]bool)
+	for _, rule := range policy.Rules {
+		if _, ok := builtinDangerousCompiled[rule.ID]; ok {
+			if !rule.Enabled {
+				settings.DisabledBuiltins = append(settings.DisabledBuiltins, rule.ID)
+			}
+			continue
+		}
+		rule.Pattern = strings.TrimSpace(rule.Pattern)
+		if rule.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", rule.Pattern, err)
+		}
+		if rule.ID == "" || seen[rule.ID] {
+			rule.ID = fmt.Sprintf("custom_%d", len(settings.Custom)+1)
+		}
+		seen[rule.ID] = true
+		rule.Builtin = false
+		settings.Custom = append(settings.Custom, rule)
+		custom = append(custom, compiledDangerousRule{rule: rule, re: re})
+	}
+
+	a.dangerousMu.Lock()
+	a.dangerousSettings = settings
+	a.dangerousCustom = custom
+	a.dangerousMu.Unlock()
+	go a.saveSettings()
+	return nil
+}
+
+// setDangerousCommandSettings applies settings loaded from disk, dropping patterns that no
+// longer compile
+func (a *App) setDangerousCommandSettings(settings dangerousCommandSettings) {
+	var custom []compiledDangerousRule
+	for _, rule := range settings.Custom {
+		if re, err := regexp.Compile(rule.Pattern); err == nil {
+			custom = append(custom, compiledDangerousRule{rule: rule, re: re})
+		}
+	}
+	a.dangerousMu.Lock()
+	a.dangerousSettings = settings
+	a.dangerousCustom = custom
+	a.dangerousMu.Unlock()
+}
+
+// matchDangerousCommand returns the first enabled rule the command matches, or nil
+func (a *App) matchDangerousCommand(command string) *DangerousCommandRule {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil
+	}
+	a.dangerousMu.RLock()
+	defer a.dangerousMu.RUnlock()
+	if a.dangerousSettings.Disabled {
+		return nil
+	}
+
+	disabled := make(map[string]bool, len(a.dangerousSettings.DisabledBuiltins))
+	for _, id := range a.dangerousSettings.DisabledBuiltins {
+		disabled[id] = true
+	}
+	for _, rule := range builtinDangerousRules {
+		if !disabled[rule.ID] && builtinDangerousCompiled[rule.ID].MatchString(command) {
+			rule.Builtin = true
+			rule.Enabled = true
+			return &rule
+		}
+	}
+	for _, c := range a.dangerousCustom {
+		if c.rule.Enabled && c.re.MatchString(command) {
+			rule := c.rule
+			return &rule
+		}
+	}
+	return nil
+}
+
+// CheckDangerousCommand reports whether the command needs confirmation. When it does, the
+// returned token lets RunAdbCommandConfirmed, RunShellCommand or ConfirmShellCommand run this
+// exact command once within the next two minutes.
+func (a *App) CheckDangerousCommand(command string) DangerousCommandCheck {
+	rule := a.matchDangerousCommand(command)
+	if rule == nil {
+		return DangerousCommandCheck{}
+	}
+
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	token := hex.EncodeToString(buf)
+
+	dangerousConfirmationsMu.Lock()
+	now := time.Now()
+	for t, c := range dangerousConfirmations {
+		if now.After(c.expires) {
+			delete(dangerousConfirmations, t)
+		}
+	}
+	dangerousConfirmations[token] = dangerousConfirmation{
+		command: strings.TrimSpace(command),
+		expires: now.Add(dangerousConfirmTTL),
+	}
+	dangerousConfirmationsMu.Unlock()
+
+	return DangerousCommandCheck{
+		Dangerous:   true,
+		RuleID:      rule.ID,
+		Description: rule.Description,
+		Token:       token,
+	}
+}
+
+// guardDangerousCommand rejects a dangerous command unless confirmToken was issued for it.
+// Confirmed runs are written to the audit log.
+func (a *App) guardDangerousCommand(deviceId, command, confirmToken string) error {
+	rule := a.matchDangerousCommand(command)
+	if rule == nil {
+		return nil
+	}
+	command = strings.TrimSpace(command)
+
+	if confirmToken != "" {
+		dangerousConfirmationsMu.Lock()
+		c, ok := dangerousConfirmations[confirmToken]
+		if ok {
+			delete(dangerousConfirmations, confirmToken)
+		}
+		dangerousConfirmationsMu.Unlock()
+
+		if ok && c.command == command && time.Now().Before(c.expires) {
+			a.appendAuditLog(AuditEntry{
+				Action:   "dangerous-command",
+				DeviceID: a.serialFor(deviceId),
+				Detail:   command,
+				Rule:     rule.ID,
+			})
+			a.Log("Confirmed dangerous command on %s (%s): %s", deviceId, rule.ID, command)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: command matches %q (%s)", ErrConfirmationRequired, rule.ID, rule.Description)
+}
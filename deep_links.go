@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// chooserComponents are the activities Android shows when several apps can open a link
+var chooserComponents = []string{"ResolverActivity", "ChooserActivity", "com.android.intentresolver/"}
+
+func isChooserComponent(component string) bool {
+	for _, c := range chooserComponents {
+		if strings.Contains(component, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestDeepLink opens url with a VIEW intent and reports which app handled it, whether the
+// disambiguation chooser appeared instead, and how long the launch took
+func (a *App) TestDeepLink(deviceId, url string, expectedPackage string) (*DeepLinkResult, error) {
+	if strings.TrimSpace(url) == "" {
+		return nil, fmt.Errorf("no URL specified")
+	}
+	before, _ := a.resumedActivity(deviceId)
+
+	result := &DeepLinkResult{URL: url, ExpectedPackage: expectedPackage}
+	start := time.Now()
+	r, err := a.StartIntent(deviceId, IntentSpec{
+		Action:     "android.intent.action.VIEW",
+		Data:       url,
+		Categories: []string{"android.intent.category.BROWSABLE"},
+	})
+	if r == nil {
+		return nil, err
+	}
+	result.Warning = r.Warning
+	if err != nil {
+		// "unable to resolve Intent" means nothing on the device handles the link
+		result.Error = r.Error
+		result.ResolveMs = time.Since(start).Milliseconds()
+		return result, nil
+	}
+
+	// am start -W returns once the first activity launches, but a trampoline or the chooser
+	// can still replace it, so wait briefly for the foreground to settle
+	component := r.Activity
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		current, err := a.resumedActivity(deviceId)
+		if err == nil && current != before {
+			component = current
+			break
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	result.ResolveMs = int64(r.TotalTimeMs)
+	if result.ResolveMs == 0 {
+		result.ResolveMs = time.Since(start).Milliseconds()
+	}
+
+	result.Activity = component
+	result.Package, _, _ = strings.Cut(component, "/")
+	result.Chooser = isChooserComponent(component)
+	if result.Chooser {
+		result.Package = ""
+	}
+	result.MatchesExpected = expectedPackage == "" || result.Package == expectedPackage
+	return result, nil
+}
+
+// domainMatches reports whether a handler's host covers the domain being looked up; an empty
+// domain matches everything
+func domainMatches(host, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" || host == domain {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		return domain == rest || strings.HasSuffix(domain, "."+rest)
+	}
+	return false
+}
+
+// parseAppLinks parses pm get-app-links (Android 12+):
+//
+//	com.example.app:
+//	  ID: ...
+//	  Domain verification state:
+//	    example.com: verified
+func parseAppLinks(output, domain string) []DeepLinkHandler {
+	var handlers []DeepLinkHandler
+	pkg := ""
+	inDomains := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent <= 2 && strings.HasSuffix(trimmed, ":"):
+			pkg = strings.TrimSuffix(trimmed, ":")
+			inDomains = false
+		case trimmed == "Domain verification state:":
+			inDomains = true
+		case inDomains && indent >= 6:
+			host, state, ok := strings.Cut(trimmed, ":")
+			if !ok || pkg == "" || !domainMatches(host, domain) {
+				continue
+			}
+			state = strings.TrimSpace(state)
+			handlers = append(handlers, DeepLinkHandler{Package: pkg, Domain: host, State: state, Verified: state == "verified"})
+		default:
+			inDomains = false
+		}
+	}
+	return handlers
+}
+
+// parseDomainPreferredApps parses dumpsys package domain-preferred-apps (Android 6-11):
+//
+//	Package: com.example.app
+//	Domains: example.com www.example.com
+//	Status:  always : 200000000
+func parseDomainPreferredApps(output, domain string) []DeepLinkHandler {
+	var handlers []DeepLinkHandler
+	pkg := ""
+	var hosts []string
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			pkg, hosts = value, nil
+		case "Domains":
+			hosts = strings.Fields(value)
+		case "Status":
+			state, _, _ := strings.Cut(value, ":")
+			state = strings.TrimSpace(state)
+			for _, host := range hosts {
+				if pkg != "" && domainMatches(host, domain) {
+					handlers = append(handlers, DeepLinkHandler{Package: pkg, Domain: host, State: state, Verified: state == "always"})
+				}
+			}
+			pkg, hosts = "", nil
+		}
+	}
+	return handlers
+}
+
+// ListDeepLinkHandlers lists the apps that declare app links for domain (all domains when
+// empty) and their verification state
+func (a *App) ListDeepLinkHandlers(deviceId, domain string) ([]DeepLinkHandler, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if a.getSDKInt(deviceId) >= 31 {
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "pm get-app-links").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read app links: %w, %s", err, strings.TrimSpace(string(out)))
+		}
+		return append([]DeepLinkHandler{}, parseAppLinks(string(out), domain)...), nil
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "dumpsys package domain-preferred-apps").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app links: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return append([]DeepLinkHandler{}, parseDomainPreferredApps(string(out), domain)...), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var demoClockRegex = regexp.MustCompile(`^([01]\d|2[0-3])[0-5]\d$`)
+
+// demoBroadcast is one SystemUI demo mode command
+func demoBroadcast(command string, extras ...string) string {
+	return "am broadcast -a com.android.systemui.demo -e command " + command + " " + strings.Join(extras, " ")
+}
+
+// demoLevel maps an option level to the demo extra value: 0 is full (4), negative hides the icon
+func demoLevel(level int) (show string, value int) {
+	switch {
+	case level < 0:
+		return "hide", 0
+	case level == 0 || level > 4:
+		return "show", 4
+	default:
+		return "show", level
+	}
+}
+
+// demoModeCommands returns the broadcasts that put the status bar into the requested state
+func demoModeCommands(opts DemoModeOptions) ([]string, error) {
+	clock := strings.ReplaceAll(opts.Clock, ":", "")
+	if clock == "" {
+		clock = "1000"
+	}
+	if !demoClockRegex.MatchString(clock) {
+		return nil, fmt.Errorf("invalid clock %q: expected HH:MM", opts.Clock)
+	}
+	battery := opts.BatteryLevel
+	if battery <= 0 || battery > 100 {
+		battery = 100
+	}
+	wifiShow, wifiLevel := demoLevel(opts.WifiLevel)
+	mobileShow, mobileLevel := demoLevel(opts.MobileLevel)
+	dataType := opts.MobileDataType
+	if dataType == "" {
+		dataType = "none"
+	}
+
+	return []string{
+		demoBroadcast("enter"),
+		demoBroadcast("clock", "-e hhmm "+clock),
+		demoBroadcast("battery", fmt.Sprintf("-e level %d -e plugged %t -e powersave false", battery, opts.Charging)),
+		demoBroadcast("network", fmt.Sprintf("-e wifi %s -e level %d -e fully true", wifiShow, wifiLevel)),
+		demoBroadcast("network", fmt.Sprintf("-e mobile %s -e datatype %s -e level %d -e fully true", mobileShow, shellQuote(dataType), mobileLevel)),
+		demoBroadcast("network", "-e airplane hide -e nosim hide"),
+		demoBroadcast("notifications", fmt.Sprintf("-e visible %t", opts.ShowNotifications)),
+		demoBroadcast("status", "-e volume hide -e bluetooth hide -e location hide -e alarm hide -e zen hide -e mute hide -e speakerphone hide"),
+	}, nil
+}
+
+// EnableDemoMode puts the status bar into demo mode: a fixed clock, full battery and signal,
+// and no notification icons. The broadcasts don't touch sysui_tuner_demo_on, so it is set
+// here as the System UI Tuner does, which is what IsDemoModeActive reads back.
+func (a *App) EnableDemoMode(deviceId string, opts DemoModeOptions) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	commands, err := demoModeCommands(opts)
+	if err != nil {
+		return err
+	}
+	script := "settings put global sysui_demo_allowed 1 && settings put global sysui_tuner_demo_on 1 && " +
+		strings.Join(commands, " > /dev/null; ") + " > /dev/null"
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enter demo mode: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DisableDemoMode restores the live status bar
+func (a *App) DisableDemoMode(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", demoBroadcast("exit")+" > /dev/null; settings put global sysui_tuner_demo_on 0").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to exit demo mode: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// IsDemoModeActive reports whether the status bar is in demo mode
+func (a *App) IsDemoModeActive(deviceId string) bool {
+	return a.demoModeActive(deviceId)
+}
+
+func (a *App) demoModeActive(deviceId string) bool {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings get global sysui_tuner_demo_on").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "1"
+}
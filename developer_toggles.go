@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sysPropsTransaction is IBinder.SYSPROPS_TRANSACTION ('_SPR'); sending it to the activity
+// service makes running apps re-read debug.* properties without a restart
+const sysPropsTransaction = 1599295570
+
+// developerToggle is one developer option: the command reading its raw value, how that value
+// maps to on/off, and the command setting it
+type developerToggle struct {
+	name  string
+	label string
+	read  string
+	isOn  func(value string) bool
+	set   func(on bool) string
+	// poke means the setting is a system property that apps only pick up when told to
+	poke bool
+}
+
+func settingIsOn(value string) bool { return value == "1" }
+
+var developerToggles = []developerToggle{
+	{
+		name: "show_touches", label: "Show taps",
+		read: "settings get system show_touches", isOn: settingIsOn,
+		set: func(on bool) string { return "settings put system show_touches " + onOff(on, "1", "0") },
+	},
+	{
+		name: "pointer_location", label: "Pointer location",
+		read: "settings get system pointer_location", isOn: settingIsOn,
+		set: func(on bool) string { return "settings put system pointer_location " + onOff(on, "1", "0") },
+	},
+	{
+		name: "layout_bounds", label: "Show layout bounds",
+		read: "getprop debug.layout", isOn: func(v string) bool { return v == "true" },
+		set:  func(on bool) string { return "setprop debug.layout " + onOff(on, "true", "false") },
+		poke: true,
+	},
+	{
+		name: "gpu_overdraw", label: "Debug GPU overdraw",
+		read: "getprop debug.hwui.overdraw", isOn: func(v string) bool { return v == "show" || v == "show_deuteranomaly" },
+		set:  func(on bool) string { return "setprop debug.hwui.overdraw " + onOff(on, "show", "false") },
+		poke: true,
+	},
+	{
+		name: "dont_keep_activities", label: "Don't keep activities",
+		read: "settings get global always_finish_activities", isOn: settingIsOn,
+		set: func(on bool) string { return "settings put global always_finish_activities " + onOff(on, "1", "0") },
+	},
+}
+
+func findDeveloperToggle(name string) (developerToggle, bool) {
+	for _, t := range developerToggles {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return developerToggle{}, false
+}
+
+// GetDeveloperToggles reads the state of every quick developer option in one adb call
+func (a *App) GetDeveloperToggles(deviceId string) ([]DeveloperToggle, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	reads := make([]string, len(developerToggles))
+	for i, t := range developerToggles {
+		// echo keeps one line per toggle even when a read prints nothing
+		reads[i] = fmt.Sprintf("echo \"$(%s)\"", t.read)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(reads, "; ")).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read developer options: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	lines := strings.Split(strings.ReplaceAll(string(out), "\r\n", "\n"), "\n")
+	result := make([]DeveloperToggle, len(developerToggles))
+	for i, t := range developerToggles {
+		result[i] = DeveloperToggle{Name: t.name, Label: t.label}
+		if i < len(lines) {
+			result[i].Value = strings.TrimSpace(lines[i])
+			result[i].Enabled = t.isOn(result[i].Value)
+		}
+	}
+	return result, nil
+}
+
+// SetDeveloperToggle turns a developer option on or off and returns its state as read back
+func (a *App) SetDeveloperToggle(deviceId, name string, on bool) (*DeveloperToggle, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	t, ok := findDeveloperToggle(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown developer option %q", name)
+	}
+	script := t.set(on)
+	if t.poke {
+		script += fmt.Sprintf(" && service call activity %d > /dev/null", sysPropsTransaction)
+	}
+	script += "; " + t.read
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	text := strings.TrimSpace(strings.ReplaceAll(string(out), "\r\n", "\n"))
+	if err != nil || strings.Contains(text, "Exception") {
+		return nil, fmt.Errorf("failed to set %s: %v, %s", t.label, err, text)
+	}
+
+	lines := strings.Split(text, "\n")
+	value := strings.TrimSpace(lines[len(lines)-1])
+	result := &DeveloperToggle{Name: t.name, Label: t.label, Value: value, Enabled: t.isOn(value)}
+	if result.Enabled != on {
+		return result, fmt.Errorf("%s still reads %q", t.label, value)
+	}
+	return result, nil
+}
+
+// enableShowTouches turns on show_touches for a recording and reports whether it was off, so
+// the caller knows to turn it back off afterwards
+func (a *App) enableShowTouches(deviceId string) bool {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings get system show_touches").Output()
+	if err != nil || settingIsOn(strings.TrimSpace(string(out))) {
+		return false
+	}
+	if _, err := a.SetDeveloperToggle(deviceId, "show_touches", true); err != nil {
+		a.Log("Could not enable show taps on %s: %v", deviceId, err)
+		return false
+	}
+	return true
+}
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -232,6 +233,16 @@ func (a *App) GetDevices(forceLog bool) ([]Device, error) {
 		}
 	}
 
+	// 5.5. Devices in the bootloader only show up in fastboot
+	for _, serial := range a.listFastbootSerials(ctx) {
+		if _, exists := deviceMap[serial]; exists {
+			continue
+		}
+		d := fastbootDevice(serial)
+		deviceMap[serial] = d
+		finalDevices = append(finalDevices, d)
+	}
+
 	// 6. Phase 3: Final Polishing (Metadata & History)
 	for i := range finalDevices {
 		dev := finalDevices[i]
@@ -420,6 +431,81 @@ func (a *App) GetDeviceInfo(deviceId string) (DeviceInfo, error) {
 	return info, nil
 }
 
+// getSDKInt returns the device's API level, or 0 when it cannot be read
+func (a *App) getSDKInt(deviceId string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "getprop", "ro.build.version.sdk").Output()
+	if err != nil {
+		return 0
+	}
+	sdk, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return sdk
+}
+
+// screenOnRegex matches dumpsys power output for an awake, lit display
+var screenOnRegex = regexp.MustCompile(`(?i)mWakefulness=Awake|Display Power: state=ON`)
+
+// isScreenOn reports whether the device display is on
+func (a *App) isScreenOn(deviceId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "dumpsys power | grep -E 'mWakefulness=|Display Power:'").CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("failed to read power state: %w", err)
+	}
+	return screenOnRegex.Match(out), nil
+}
+
+// displayRotationRegex matches the current rotation in dumpsys input ("SurfaceOrientation: 1")
+// or dumpsys window ("mCurrentRotation=ROTATION_90" / "mCurrentRotation=1")
+var displayRotationRegex = regexp.MustCompile(`SurfaceOrientation:\s*(\d)|mCurrentRotation=(?:ROTATION_)?(\d+)`)
+
+// getDisplayRotation returns the default display's rotation in quarter turns (0-3)
+func (a *App) getDisplayRotation(deviceId string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "dumpsys input | grep -m1 SurfaceOrientation; dumpsys window displays | grep -m1 mCurrentRotation").CombinedOutput()
+	if err != nil && len(out) == 0 {
+		return 0, fmt.Errorf("failed to read display rotation: %w", err)
+	}
+	m := displayRotationRegex.FindSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("display rotation not reported")
+	}
+	value := string(m[1])
+	if value == "" {
+		value = string(m[2])
+	}
+	rotation, _ := strconv.Atoi(value)
+	if rotation >= 90 {
+		rotation /= 90
+	}
+	return rotation % 4, nil
+}
+
+// SetDisplayPower wakes or sleeps the device screen
+func (a *App) SetDisplayPower(deviceId string, on bool) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+
+	if current, err := a.isScreenOn(deviceId); err == nil && current == on {
+		return nil
+	}
+
+	// KEYCODE_WAKEUP / KEYCODE_SLEEP, unlike KEYCODE_POWER, are idempotent
+	keycode := "224"
+	if !on {
+		keycode = "223"
+	}
+	if output, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "input keyevent "+keycode).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set display power: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
 // AdbPair pairs a device using the given address and code
 func (a *App) AdbPair(address string, code string) (string, error) {
 	if address == "" || code == "" {
@@ -533,11 +619,10 @@ func (a *App) RestartAdbServer() (string, error) {
 	a.Log("Restarting ADB server...")
 
 	a.scrcpyMu.Lock()
-	for id, cmd := range a.scrcpyCmds {
-		if cmd.Process != nil {
-			_ = cmd.Process.Kill()
+	for _, sessions := range a.scrcpySessions {
+		for _, s := range sessions {
+			_ = s.cmd.Process.Kill()
 		}
-		delete(a.scrcpyCmds, id)
 	}
 	a.scrcpyMu.Unlock()
 
@@ -560,6 +645,16 @@ func (a *App) RestartAdbServer() (string, error) {
 
 // RunAdbCommand executes an arbitrary ADB command
 func (a *App) RunAdbCommand(deviceId string, fullCmd string) (string, error) {
+	return a.RunAdbCommandConfirmed(deviceId, fullCmd, "")
+}
+
+// RunAdbCommandConfirmed is RunAdbCommand for commands the dangerous-command policy blocks;
+// confirmToken comes from CheckDangerousCommand after the user has accepted the warning
+func (a *App) RunAdbCommandConfirmed(deviceId, fullCmd, confirmToken string) (string, error) {
+	if err := a.guardDangerousCommand(deviceId, fullCmd, confirmToken); err != nil {
+		return "", err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -580,6 +675,10 @@ func (a *App) RunAdbCommand(deviceId string, fullCmd string) (string, error) {
 
 	cmd := a.newAdbCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
+	if strings.HasPrefix(fullCmd, "shell input ") {
+		// Injected input changes the screen under any cached UI dump
+		a.InvalidateUIDump(deviceId)
+	}
 	res := string(output)
 	if err != nil {
 		return res, fmt.Errorf("command failed: %w, output: %s", err, res)
@@ -911,10 +1010,17 @@ func (a *App) runDeviceMonitor(ctx context.Context) {
 				return
 			}
 			wailsRuntime.EventsEmit(a.ctx, "devices-changed", devices)
+			a.evaluateConnectTriggers(devices)
+			a.stopResourceMonitorsForOffline(devices)
+			a.stopAppMemoryTracesForOffline(devices)
+			a.stopJankMonitorsForOffline(devices)
+			a.stopAppNetworkMonitorsForOffline(devices)
 		})
 		debounceMu.Unlock()
 	}
 
+	go a.watchFastbootDevices(ctx, emitDevicesChanged)
+
 	for {
 		select {
 		case <-ctx.Done():
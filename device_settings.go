@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// settingsNamespaces are the tables the settings command can read and write
+var settingsNamespaces = []string{"system", "secure", "global"}
+
+// settingKeyRegex matches a setting name, and the start of an entry in settings list output
+var settingKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_.:@/-]+$`)
+
+// dangerousSettings are keys whose careless edit can cut off adb, lock the user out or leave
+// the device half set up; they're flagged, not blocked
+var dangerousSettings = map[string]string{
+	"global/adb_enabled":                    "turning this off disconnects adb",
+	"global/adb_wifi_enabled":               "turning this off drops wireless debugging connections",
+	"global/development_settings_enabled":   "turning this off disables USB debugging",
+	"global/device_provisioned":             "0 makes the device behave as if setup never finished",
+	"global/package_verifier_enable":        "controls Play Protect verification of installs",
+	"global/verifier_verify_adb_installs":   "controls verification of adb installs",
+	"global/airplane_mode_on":               "cuts wireless connections, including wireless adb",
+	"global/wifi_on":                        "turning this off drops wireless adb",
+	"global/hidden_api_policy":              "changes which private APIs apps may call",
+	"secure/user_setup_complete":            "0 sends the device back into setup",
+	"secure/enabled_accessibility_services": "accessibility services can read and control the screen",
+	"secure/enabled_input_methods":          "removing the active keyboard can leave no way to type",
+	"secure/default_input_method":           "an invalid value leaves no keyboard",
+	"secure/install_non_market_apps":        "allows installs from unknown sources",
+	"secure/android_id":                     "changing it resets app identity and licensing",
+	"secure/lockscreen.disabled":            "affects lock screen security",
+	"system/screen_off_timeout":             "very small values make the device hard to use",
+}
+
+var settingsSnapshotsMu sync.Mutex
+
+func (a *App) getSettingsSnapshotsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	path := filepath.Join(configDir, "Gaze", "settings_snapshots")
+	_ = os.MkdirAll(path, 0755)
+	return path
+}
+
+func validSettingsNamespace(namespace string) error {
+	for _, ns := range settingsNamespaces {
+		if ns == namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown settings namespace %q: expected system, secure or global", namespace)
+}
+
+// parseSettingsList splits settings list output into key/value pairs. Values may contain '=' and
+// newlines; a line that doesn't start with "<key>=" continues the previous value.
+func parseSettingsList(output string) map[string]string {
+	values := make(map[string]string)
+	lastKey := ""
+	for _, line := range strings.Split(strings.TrimSuffix(strings.ReplaceAll(output, "\r\n", "\n"), "\n"), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok && settingKeyRegex.MatchString(key) {
+			values[key] = value
+			lastKey = key
+			continue
+		}
+		if lastKey != "" {
+			values[lastKey] += "\n" + line
+		}
+	}
+	return values
+}
+
+func settingEntries(namespace string, values map[string]string) []SettingEntry {
+	entries := make([]SettingEntry, 0, len(values))
+	for k, v := range values {
+		e := SettingEntry{Namespace: namespace, Key: k, Value: v}
+		if note, ok := dangerousSettings[namespace+"/"+k]; ok {
+			e.Dangerous = true
+			e.Note = note
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func (a *App) listSettings(deviceId, namespace string) (map[string]string, error) {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings list "+namespace).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s settings: %w, %s", namespace, err, strings.TrimSpace(string(out)))
+	}
+	return parseSettingsList(string(out)), nil
+}
+
+// GetSettings lists one settings table, sorted by key, with risky keys flagged
+func (a *App) GetSettings(deviceId, namespace string) ([]SettingEntry, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if err := validSettingsNamespace(namespace); err != nil {
+		return nil, err
+	}
+	values, err := a.listSettings(deviceId, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return settingEntries(namespace, values), nil
+}
+
+// PutSetting writes a setting and returns it as read back from the device
+func (a *App) PutSetting(deviceId, namespace, key, value string) (*SettingEntry, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if err := validSettingsNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if !settingKeyRegex.MatchString(key) {
+		return nil, fmt.Errorf("invalid setting key %q", key)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell",
+		fmt.Sprintf("settings put %s %s %s", namespace, key, shellQuote(value))).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return nil, fmt.Errorf("failed to put %s/%s: %v, %s", namespace, key, err, strings.TrimSpace(string(out)))
+	}
+
+	got, err := a.newAdbCommand(nil, "-s", deviceId, "shell", fmt.Sprintf("settings get %s %s", namespace, key)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read back %s/%s: %w", namespace, key, err)
+	}
+	entry := settingEntries(namespace, map[string]string{key: strings.TrimSuffix(strings.ReplaceAll(string(got), "\r\n", "\n"), "\n")})[0]
+	a.Log("Set %s/%s on %s", namespace, key, deviceId)
+	return &entry, nil
+}
+
+// DeleteSetting removes a setting so the system default applies again
+func (a *App) DeleteSetting(deviceId, namespace, key string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if err := validSettingsNamespace(namespace); err != nil {
+		return err
+	}
+	if !settingKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid setting key %q", key)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", fmt.Sprintf("settings delete %s %s", namespace, key)).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return fmt.Errorf("failed to delete %s/%s: %v, %s", namespace, key, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SnapshotSettings records all three settings tables so a later snapshot can be diffed against it
+func (a *App) SnapshotSettings(deviceId, name string) (*SettingsSnapshot, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	now := time.Now()
+	snap := &SettingsSnapshot{
+		// Zero-padded so file names sort by capture time
+		ID:        fmt.Sprintf("settings_%020d", now.UnixNano()),
+		Name:      strings.TrimSpace(name),
+		DeviceID:  deviceId,
+		Serial:    a.serialFor(deviceId),
+		CreatedAt: now.Unix(),
+		Values:    make(map[string]map[string]string),
+	}
+	if snap.Name == "" {
+		snap.Name = now.Format("2006-01-02 15:04:05")
+	}
+	for _, ns := range settingsNamespaces {
+		values, err := a.listSettings(deviceId, ns)
+		if err != nil {
+			return nil, err
+		}
+		snap.Values[ns] = values
+		snap.Count += len(values)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	settingsSnapshotsMu.Lock()
+	defer settingsSnapshotsMu.Unlock()
+	if err := writeFileAtomic(filepath.Join(a.getSettingsSnapshotsPath(), snap.ID+".json"), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+func (a *App) readSettingsSnapshot(id string) (*SettingsSnapshot, error) {
+	if id == "" || filepath.Base(id) != id || !strings.HasPrefix(id, "settings_") {
+		return nil, fmt.Errorf("invalid snapshot id: %s", id)
+	}
+	data, err := os.ReadFile(filepath.Join(a.getSettingsSnapshotsPath(), id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap SettingsSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// ListSettingsSnapshots returns stored snapshots newest first, without their values.
+// An empty deviceId lists every device's.
+func (a *App) ListSettingsSnapshots(deviceId string) []SettingsSnapshot {
+	settingsSnapshotsMu.Lock()
+	defer settingsSnapshotsMu.Unlock()
+
+	serial := ""
+	if deviceId != "" {
+		serial = a.serialFor(deviceId)
+	}
+	entries, _ := os.ReadDir(a.getSettingsSnapshotsPath())
+	result := []SettingsSnapshot{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		name := entries[i].Name()
+		if entries[i].IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		snap, err := a.readSettingsSnapshot(strings.TrimSuffix(name, ".json"))
+		if err != nil || (serial != "" && snap.Serial != serial) {
+			continue
+		}
+		snap.Values = nil
+		result = append(result, *snap)
+	}
+	return result
+}
+
+// DeleteSettingsSnapshot removes a stored snapshot
+func (a *App) DeleteSettingsSnapshot(id string) error {
+	if id == "" || filepath.Base(id) != id || !strings.HasPrefix(id, "settings_") {
+		return fmt.Errorf("invalid snapshot id: %s", id)
+	}
+	settingsSnapshotsMu.Lock()
+	defer settingsSnapshotsMu.Unlock()
+	if err := os.Remove(filepath.Join(a.getSettingsSnapshotsPath(), id+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DiffSettingsSnapshots lists the keys added, removed or changed going from snapshot a to b
+func (a *App) DiffSettingsSnapshots(aId, bId string) ([]SettingChange, error) {
+	settingsSnapshotsMu.Lock()
+	before, err := a.readSettingsSnapshot(aId)
+	var after *SettingsSnapshot
+	if err == nil {
+		after, err = a.readSettingsSnapshot(bId)
+	}
+	settingsSnapshotsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return diffSettings(before.Values, after.Values), nil
+}
+
+func diffSettings(before, after map[string]map[string]string) []SettingChange {
+	changes := []SettingChange{}
+	for _, ns := range settingsNamespaces {
+		keys := map[string]bool{}
+		for k := range before[ns] {
+			keys[k] = true
+		}
+		for k := range after[ns] {
+			keys[k] = true
+		}
+		for _, k := range sortedKeys(keys) {
+			b, inBefore := before[ns][k]
+			f, inAfter := after[ns][k]
+			c := SettingChange{Namespace: ns, Key: k, Before: b, After: f}
+			switch {
+			case !inBefore:
+				c.Change = "added"
+			case !inAfter:
+				c.Change = "removed"
+			case b != f:
+				c.Change = "changed"
+			default:
+				continue
+			}
+			_, c.Dangerous = dangerousSettings[ns+"/"+k]
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
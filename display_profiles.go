@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	nightModeRegex   = regexp.MustCompile(`Night mode: (\w+)`)
+	wmDensityRegex   = regexp.MustCompile(`(Physical|Override) density: (\d+)`)
+	wmSizeRegex      = regexp.MustCompile(`(Physical|Override) size: (\d+x\d+)`)
+	displaySizeRegex = regexp.MustCompile(`^\d{2,5}x\d{2,5}$`)
+)
+
+// displayProfileStore is the on-disk layout of display_profiles.json
+type displayProfileStore struct {
+	Profiles []DisplayProfile `json:"profiles"`
+}
+
+var (
+	displayProfiles       *displayProfileStore
+	displayProfilesMu     sync.Mutex
+	displayProfilesLoaded bool
+
+	// displayRestorePoints are the settings each device had before its last ApplyDisplayProfile
+	displayRestorePoints = make(map[string]DisplayProfile)
+)
+
+func (a *App) getDisplayProfilesPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "display_profiles.json")
+}
+
+// loadDisplayProfilesLocked reads display_profiles.json once; callers must hold displayProfilesMu
+func (a *App) loadDisplayProfilesLocked() *displayProfileStore {
+	if displayProfilesLoaded {
+		return displayProfiles
+	}
+	displayProfilesLoaded = true
+	displayProfiles = &displayProfileStore{Profiles: []DisplayProfile{}}
+
+	data, err := os.ReadFile(a.getDisplayProfilesPath())
+	if err != nil {
+		return displayProfiles
+	}
+	var stored displayProfileStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return displayProfiles
+	}
+	if stored.Profiles != nil {
+		displayProfiles.Profiles = stored.Profiles
+	}
+	return displayProfiles
+}
+
+// saveDisplayProfilesLocked writes display_profiles.json; callers must hold displayProfilesMu
+func (a *App) saveDisplayProfilesLocked() error {
+	data, err := json.MarshalIndent(displayProfiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getDisplayProfilesPath(), data, 0644)
+}
+
+// GetDisplaySettings reads dark mode, font scale, density and screen size in one adb call
+func (a *App) GetDisplaySettings(deviceId string) (*DisplaySettings, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	script := `cmd uimode night; echo "font_scale=$(settings get system font_scale)"; wm density; wm size`
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read display settings: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return parseDisplaySettings(string(out)), nil
+}
+
+func parseDisplaySettings(out string) *DisplaySettings {
+	s := &DisplaySettings{FontScale: 1}
+	if m := nightModeRegex.FindStringSubmatch(out); m != nil {
+		s.DarkMode = m[1]
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "font_scale="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+				s.FontScale = f
+			}
+		}
+	}
+	for _, m := range wmDensityRegex.FindAllStringSubmatch(out, -1) {
+		dpi, _ := strconv.Atoi(m[2])
+		if m[1] == "Physical" {
+			s.PhysicalDensity = dpi
+		} else {
+			s.Density = dpi
+		}
+	}
+	for _, m := range wmSizeRegex.FindAllStringSubmatch(out, -1) {
+		if m[1] == "Physical" {
+			s.PhysicalSize = m[2]
+		} else {
+			s.Size = m[2]
+		}
+	}
+	return s
+}
+
+// SetDarkMode switches the system night mode: yes, no or auto
+func (a *App) SetDarkMode(deviceId, mode string) (*DisplaySettings, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if mode != "yes" && mode != "no" && mode != "auto" {
+		return nil, fmt.Errorf("invalid dark mode %q: expected yes, no or auto", mode)
+	}
+	return a.applyDisplayCommand(deviceId, "cmd uimode night "+mode, func(s *DisplaySettings) bool { return s.DarkMode == mode })
+}
+
+// SetFontScale sets the system font size multiplier, 1 being the default
+func (a *App) SetFontScale(deviceId string, scale float64) (*DisplaySettings, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if scale < 0.5 || scale > 3 {
+		return nil, fmt.Errorf("font scale must be between 0.5 and 3")
+	}
+	value := strconv.FormatFloat(scale, 'f', -1, 64)
+	return a.applyDisplayCommand(deviceId, "settings put system font_scale "+value, func(s *DisplaySettings) bool { return s.FontScale == scale })
+}
+
+// SetDisplayDensity overrides the screen density in dpi; 0 resets it to the physical density
+func (a *App) SetDisplayDensity(deviceId string, dpi int) (*DisplaySettings, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if dpi == 0 {
+		return a.applyDisplayCommand(deviceId, "wm density reset", func(s *DisplaySettings) bool { return s.Density == 0 })
+	}
+	if dpi < 72 || dpi > 1000 {
+		return nil, fmt.Errorf("density must be between 72 and 1000 dpi")
+	}
+	return a.applyDisplayCommand(deviceId, fmt.Sprintf("wm density %d", dpi), func(s *DisplaySettings) bool {
+		return s.Density == dpi || (s.Density == 0 && s.PhysicalDensity == dpi)
+	})
+}
+
+// SetDisplaySize overrides the screen resolution as WIDTHxHEIGHT; "" resets it
+func (a *App) SetDisplaySize(deviceId, size string) (*DisplaySettings, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if size == "" {
+		return a.applyDisplayCommand(deviceId, "wm size reset", func(s *DisplaySettings) bool { return s.Size == "" })
+	}
+	if !displaySizeRegex.MatchString(size) {
+		return nil, fmt.Errorf("invalid size %q: expected WIDTHxHEIGHT", size)
+	}
+	return a.applyDisplayCommand(deviceId, "wm size "+size, func(s *DisplaySettings) bool {
+		return s.Size == size || (s.Size == "" && s.PhysicalSize == size)
+	})
+}
+
+// applyDisplayCommand runs one display change and reads the settings back to confirm it
+func (a *App) applyDisplayCommand(deviceId, command string, applied func(*DisplaySettings) bool) (*DisplaySettings, error) {
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", command).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(string(out), "Error") {
+		return nil, fmt.Errorf("%s failed: %v, %s", command, err, strings.TrimSpace(string(out)))
+	}
+	s, err := a.GetDisplaySettings(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if !applied(s) {
+		return s, fmt.Errorf("%s did not take effect", command)
+	}
+	return s, nil
+}
+
+// displayProfileFrom captures current settings as a profile that puts them back exactly
+func displayProfileFrom(name string, s *DisplaySettings) DisplayProfile {
+	p := DisplayProfile{Name: name, DarkMode: s.DarkMode, FontScale: s.FontScale, Density: s.Density, Size: s.Size}
+	if p.Density == 0 {
+		p.Density = -1
+	}
+	if p.Size == "" {
+		p.Size = "reset"
+	}
+	return p
+}
+
+// ApplyDisplayProfile switches the device to a profile's dark mode, font scale, density and
+// size. The settings it replaced are returned, and kept for RestoreDisplayProfile.
+func (a *App) ApplyDisplayProfile(deviceId string, profile DisplayProfile) (*DisplayProfile, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	current, err := a.GetDisplaySettings(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	restore := displayProfileFrom("Before "+profile.Name, current)
+
+	if err := a.applyDisplayProfile(deviceId, profile); err != nil {
+		return nil, err
+	}
+	displayProfilesMu.Lock()
+	// Re-applying a profile keeps the original restore point rather than the profile's own values
+	if kept, ok := displayRestorePoints[a.serialFor(deviceId)]; ok {
+		restore = kept
+	} else {
+		displayRestorePoints[a.serialFor(deviceId)] = restore
+	}
+	displayProfilesMu.Unlock()
+	a.Log("Applied display profile %q on %s", profile.Name, deviceId)
+	return &restore, nil
+}
+
+func (a *App) applyDisplayProfile(deviceId string, p DisplayProfile) error {
+	if p.DarkMode != "" && p.DarkMode != "custom" {
+		if _, err := a.SetDarkMode(deviceId, p.DarkMode); err != nil {
+			return err
+		}
+	}
+	if p.FontScale > 0 {
+		if _, err := a.SetFontScale(deviceId, p.FontScale); err != nil {
+			return err
+		}
+	}
+	if p.Density != 0 {
+		dpi := p.Density
+		if dpi < 0 {
+			dpi = 0
+		}
+		if _, err := a.SetDisplayDensity(deviceId, dpi); err != nil {
+			return err
+		}
+	}
+	if p.Size != "" {
+		size := p.Size
+		if size == "reset" {
+			size = ""
+		}
+		if _, err := a.SetDisplaySize(deviceId, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreDisplayProfile puts back what the device had before the first ApplyDisplayProfile
+func (a *App) RestoreDisplayProfile(deviceId string) error {
+	serial := a.serialFor(deviceId)
+	displayProfilesMu.Lock()
+	restore, ok := displayRestorePoints[serial]
+	displayProfilesMu.Unlock()
+	if !ok {
+		return fmt.Errorf("no display profile has been applied to %s", deviceId)
+	}
+	if err := a.applyDisplayProfile(deviceId, restore); err != nil {
+		return err
+	}
+	displayProfilesMu.Lock()
+	delete(displayRestorePoints, serial)
+	displayProfilesMu.Unlock()
+	return nil
+}
+
+// ListDisplayProfiles returns the saved profiles
+func (a *App) ListDisplayProfiles() []DisplayProfile {
+	displayProfilesMu.Lock()
+	defer displayProfilesMu.Unlock()
+	return append([]DisplayProfile{}, a.loadDisplayProfilesLocked().Profiles...)
+}
+
+// SaveDisplayProfile stores a profile, replacing one with the same name
+func (a *App) SaveDisplayProfile(profile DisplayProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if profile.DarkMode != "" && profile.DarkMode != "yes" && profile.DarkMode != "no" && profile.DarkMode != "auto" {
+		return fmt.Errorf("invalid dark mode %q", profile.DarkMode)
+	}
+	if profile.Size != "" && profile.Size != "reset" && !displaySizeRegex.MatchString(profile.Size) {
+		return fmt.Errorf("invalid size %q", profile.Size)
+	}
+
+	displayProfilesMu.Lock()
+	defer displayProfilesMu.Unlock()
+	store := a.loadDisplayProfilesLocked()
+	profile.UpdatedAt = time.Now().Unix()
+	for i, p := range store.Profiles {
+		if p.Name == profile.Name {
+			store.Profiles[i] = profile
+			return a.saveDisplayProfilesLocked()
+		}
+	}
+	store.Profiles = append(store.Profiles, profile)
+	return a.saveDisplayProfilesLocked()
+}
+
+// CaptureDisplayProfile saves the device's current display settings as a named profile
+func (a *App) CaptureDisplayProfile(deviceId, name string) (*DisplayProfile, error) {
+	current, err := a.GetDisplaySettings(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	profile := displayProfileFrom(name, current)
+	if profile.DarkMode == "custom" {
+		profile.DarkMode = ""
+	}
+	if err := a.SaveDisplayProfile(profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// DeleteDisplayProfile removes a saved profile
+func (a *App) DeleteDisplayProfile(name string) error {
+	displayProfilesMu.Lock()
+	defer displayProfilesMu.Unlock()
+	store := a.loadDisplayProfilesLocked()
+	for i, p := range store.Profiles {
+		if p.Name == name {
+			store.Profiles = append(store.Profiles[:i], store.Profiles[i+1:]...)
+			return a.saveDisplayProfilesLocked()
+		}
+	}
+	return nil
+}
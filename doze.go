@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// dozeModeScripts are the shell commands behind each SetDozeMode mode. Forcing idle only sticks
+// while the device thinks it's on battery, so the charger is unplugged first and reset again on
+// unforce.
+var dozeModeScripts = map[string]string{
+	"force-idle": "dumpsys battery unplug && dumpsys deviceidle force-idle",
+	"unforce":    "dumpsys deviceidle unforce && dumpsys battery reset",
+	"step":       "dumpsys deviceidle step deep",
+	"light-step": "dumpsys deviceidle step light",
+}
+
+// standbyBucketNames maps the numbers am get-standby-bucket prints to the names set takes
+var standbyBucketNames = map[int]string{
+	5:  "exempted",
+	10: "active",
+	20: "working_set",
+	30: "frequent",
+	40: "rare",
+	45: "restricted",
+	50: "never",
+}
+
+// SetDozeMode forces the device into or out of idle ("force-idle", "unforce") or steps the deep or
+// light idle state machine one state ("step", "light-step"), and returns the state afterwards
+func (a *App) SetDozeMode(deviceId, mode string) (*DozeState, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	script, ok := dozeModeScripts[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown doze mode %q", mode)
+	}
+	sdk := a.getSDKInt(deviceId)
+	if sdk > 0 && sdk < 23 {
+		return nil, fmt.Errorf("doze needs Android 6.0 or newer (device is API %d)", sdk)
+	}
+	if mode == "light-step" && sdk < 24 {
+		// Light idle arrived in Android 7; plain step moves the only state machine there is
+		script = "dumpsys deviceidle step"
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w, %s", mode, err, text)
+	}
+	// force-idle explains itself instead of failing when doze is disabled on the device
+	if strings.HasPrefix(text, "Unable to go") {
+		return nil, fmt.Errorf("%s", text)
+	}
+	a.Log("Doze %s on %s: %s", mode, deviceId, text)
+	return a.GetDozeState(deviceId)
+}
+
+// GetDozeState reads the deep and light idle states and the idle whitelist
+func (a *App) GetDozeState(deviceId string) (*DozeState, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	script := "dumpsys deviceidle get deep; dumpsys deviceidle get light; dumpsys deviceidle get force; dumpsys deviceidle get charging; echo ---; dumpsys deviceidle whitelist"
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read doze state: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return parseDozeState(string(out)), nil
+}
+
+// parseDozeState reads the four "get" lines and the whitelist ("user,com.example,10123") that
+// follows the separator
+func parseDozeState(output string) *DozeState {
+	state := &DozeState{UserWhitelist: []string{}, SystemWhitelist: []string{}}
+	head, whitelist, _ := strings.Cut(output, "---")
+	var values []string
+	for _, line := range strings.Split(head, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	for len(values) < 4 {
+		values = append(values, "")
+	}
+	state.Deep, state.Light = values[0], values[1]
+	state.Forced = values[2] == "true"
+	state.Charging = values[3] == "true"
+
+	// A package can appear as both "system-excidle" and "system"; list it once
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(whitelist, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), ",")
+		if len(parts) < 2 {
+			continue
+		}
+		switch parts[0] {
+		case "user":
+			state.UserWhitelist = append(state.UserWhitelist, parts[1])
+		case "system", "system-excidle":
+			if !seen[parts[1]] {
+				seen[parts[1]] = true
+				state.SystemWhitelist = append(state.SystemWhitelist, parts[1])
+			}
+		}
+	}
+	sort.Strings(state.UserWhitelist)
+	sort.Strings(state.SystemWhitelist)
+	return state
+}
+
+// SetDozeWhitelist adds pkg to or removes it from the user idle whitelist, which exempts it from
+// doze and app standby like "Don't optimize" in battery settings
+func (a *App) SetDozeWhitelist(deviceId, pkg string, whitelisted bool) (*DozeState, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	arg := onOff(whitelisted, "+", "-") + pkg
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "cmd deviceidle whitelist "+arg).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update the whitelist: %w, %s", err, text)
+	}
+	if strings.HasPrefix(text, "Unknown package") || strings.Contains(text, "Exception") {
+		return nil, fmt.Errorf("%s", text)
+	}
+	a.Log("Doze whitelist %s on %s", arg, deviceId)
+	return a.GetDozeState(deviceId)
+}
+
+// SetAppStandbyBucket puts pkg into an app standby bucket (active, working_set, frequent, rare,
+// restricted) and returns the bucket read back, which the system may already have changed if the
+// app is in use
+func (a *App) SetAppStandbyBucket(deviceId, pkg, bucket string) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return "", fmt.Errorf("invalid package name %q", pkg)
+	}
+	sdk := a.getSDKInt(deviceId)
+	switch bucket {
+	case "active", "working_set", "frequent", "rare":
+	case "restricted":
+		if sdk > 0 && sdk < 30 {
+			return "", fmt.Errorf("the restricted bucket needs Android 11 or newer (device is API %d)", sdk)
+		}
+	default:
+		return "", fmt.Errorf("unknown standby bucket %q", bucket)
+	}
+	if sdk > 0 && sdk < 28 {
+		return "", fmt.Errorf("app standby buckets need Android 9 or newer (device is API %d)", sdk)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "am set-standby-bucket "+pkg+" "+bucket).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil || strings.Contains(text, "Exception") || strings.HasPrefix(text, "Error") {
+		return "", fmt.Errorf("failed to set standby bucket: %v %s", err, text)
+	}
+	actual, err := a.GetAppStandbyBucket(deviceId, pkg)
+	if err != nil {
+		return "", err
+	}
+	a.Log("Set standby bucket of %s on %s to %s (reads %s)", pkg, deviceId, bucket, actual)
+	return actual, nil
+}
+
+// GetAppStandbyBucket returns the name of pkg's current standby bucket
+func (a *App) GetAppStandbyBucket(deviceId, pkg string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return "", fmt.Errorf("invalid package name %q", pkg)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "am get-standby-bucket "+pkg).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return "", fmt.Errorf("failed to read standby bucket: %w, %s", err, text)
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return "", fmt.Errorf("unexpected standby bucket output: %s", text)
+	}
+	if name, ok := standbyBucketNames[n]; ok {
+		return name, nil
+	}
+	return strconv.Itoa(n), nil
+}
+
+// RunJobNow forces a scheduled JobScheduler job (WorkManager's included) to run immediately,
+// ignoring its constraints, which also works while the device is forced idle
+func (a *App) RunJobNow(deviceId, pkg string, jobId int) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return "", fmt.Errorf("invalid package name %q", pkg)
+	}
+	if sdk := a.getSDKInt(deviceId); sdk > 0 && sdk < 24 {
+		return "", fmt.Errorf("cmd jobscheduler needs Android 7.0 or newer (device is API %d)", sdk)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", fmt.Sprintf("cmd jobscheduler run -f %s %d", pkg, jobId)).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return "", fmt.Errorf("failed to run job: %w, %s", err, text)
+	}
+	// Missing packages and jobs are reported on stdout with a zero exit code on older releases
+	if strings.HasPrefix(text, "Package not found") || strings.HasPrefix(text, "Could not find job") || strings.Contains(text, "Exception") {
+		return "", fmt.Errorf("%s", text)
+	}
+	a.Log("Forced job %d of %s on %s", jobId, pkg, deviceId)
+	return text, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"time"
+)
+
+const (
+	// elementCompareSize is the square both images are scaled to before comparing
+	elementCompareSize = 64
+	// defaultImageDiffThreshold is the mean per-pixel delta (0-1) AssertElementImage accepts
+	defaultImageDiffThreshold = 0.05
+)
+
+// ElementImage is a screenshot cropped to one element
+type ElementImage struct {
+	Image   string `json:"image"`  // PNG data URL
+	Bounds  string `json:"bounds"` // The cropped area, after clamping to the screen
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Clamped bool   `json:"clamped"` // Part of the element was off-screen
+}
+
+// ElementImageAssertResult is the outcome of comparing an element with a reference image
+type ElementImageAssertResult struct {
+	Passed    bool          `json:"passed"`
+	Score     float64       `json:"score"` // Mean per-pixel delta, 0 = identical, 1 = inverted
+	Threshold float64       `json:"threshold"`
+	Element   *ElementImage `json:"element"`
+}
+
+// CaptureElementImage crops a screenshot to the element the selector matches
+func (a *App) CaptureElementImage(deviceId string, selector ElementSelector) (*ElementImage, error) {
+	crop, result, err := a.captureElement(deviceId, &selector)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, crop); err != nil {
+		return nil, err
+	}
+	result.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	return result, nil
+}
+
+// AssertElementImage compares the element the selector matches with the image at
+// referencePath (PNG or JPEG). Both are scaled to a common size and the mean per-pixel delta
+// must not exceed threshold (0-1, default 0.05).
+func (a *App) AssertElementImage(deviceId string, selector ElementSelector, referencePath string, threshold float64) (*ElementImageAssertResult, error) {
+	if threshold <= 0 {
+		threshold = defaultImageDiffThreshold
+	}
+	f, err := os.Open(referencePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reference image: %w", err)
+	}
+	reference, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode reference image: %w", err)
+	}
+
+	crop, element, err := a.captureElement(deviceId, &selector)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, crop); err == nil {
+		element.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	score := imageDiffScore(crop, reference)
+	return &ElementImageAssertResult{
+		Passed:    score <= threshold,
+		Score:     score,
+		Threshold: threshold,
+		Element:   element,
+	}, nil
+}
+
+// captureElement dumps the UI, takes a screenshot and crops it to the selector's element
+func (a *App) captureElement(deviceId string, selector *ElementSelector) (image.Image, *ElementImage, error) {
+	if deviceId == "" {
+		return nil, nil, fmt.Errorf("no device specified")
+	}
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUIDumpFailed, err)
+	}
+	node := a.FindElementBySelector(hierarchy.Root, selector)
+	if node == nil {
+		return nil, nil, fmt.Errorf("%w: %s %q", ErrNoSelectorMatch, selector.Type, selector.Value)
+	}
+	bounds, err := ParseBounds(node.Bounds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	data, err := a.captureScreenPNG(ctx, deviceId)
+	if err != nil {
+		return nil, nil, err
+	}
+	screen, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	want := image.Rect(bounds.X1, bounds.Y1, bounds.X2, bounds.Y2)
+	rect := want.Intersect(screen.Bounds())
+	if rect.Empty() {
+		return nil, nil, fmt.Errorf("element %s is entirely off-screen", node.Bounds)
+	}
+	crop := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(crop, crop.Bounds(), screen, rect.Min, draw.Src)
+
+	return crop, &ElementImage{
+		Bounds:  fmt.Sprintf("[%d,%d][%d,%d]", rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y),
+		Width:   rect.Dx(),
+		Height:  rect.Dy(),
+		Clamped: rect != want,
+	}, nil
+}
+
+// imageDiffScore scales both images to elementCompareSize square and returns their mean
+// per-channel RGB delta, from 0 (identical) to 1
+func imageDiffScore(a, b image.Image) float64 {
+	sa := resizeImage(a, elementCompareSize, elementCompareSize)
+	sb := resizeImage(b, elementCompareSize, elementCompareSize)
+	var total int64
+	for i := 0; i < len(sa.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			d := int64(sa.Pix[i+c]) - int64(sb.Pix[i+c])
+			if d < 0 {
+				d = -d
+			}
+			total += d
+		}
+	}
+	return float64(total) / float64(elementCompareSize*elementCompareSize*3*255)
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ========================================
@@ -22,6 +25,59 @@ type ElementActionConfig struct {
 	OnError       string // "stop" or "continue" (default: "stop")
 }
 
+// elementPollIntervalMs is how often script waits re-dump the hierarchy
+const elementPollIntervalMs = 500
+
+// ElementWaitResult reports how a wait for an element ended
+type ElementWaitResult struct {
+	Found     bool   `json:"found"`            // false when waiting for the element to go away
+	Bounds    string `json:"bounds,omitempty"` // Bounds of the matched element
+	ElapsedMs int64  `json:"elapsedMs"`
+	Polls     int    `json:"polls"` // Hierarchy dumps taken
+
+	node *UINode
+}
+
+var (
+	// ErrUIDumpFailed is returned when the UI hierarchy could not be dumped
+	ErrUIDumpFailed = errors.New("UI dump failed")
+	// ErrInvalidSelector is returned for unknown selector types and unparsable queries
+	ErrInvalidSelector = errors.New("invalid selector")
+	// ErrNoSelectorMatch is returned when a selector matches nothing on screen
+	ErrNoSelectorMatch = errors.New("no element matches the selector")
+)
+
+// ScrollResult reports where ScrollToElement found its target
+type ScrollResult struct {
+	Found     bool   `json:"found"`
+	Bounds    string `json:"bounds,omitempty"`
+	Swipes    int    `json:"swipes"`
+	EndOfList bool   `json:"endOfList"` // The list stopped moving before the target showed up
+	ElapsedMs int64  `json:"elapsedMs"`
+}
+
+// SelectorTestResult lists every element a selector matches on the current screen
+type SelectorTestResult struct {
+	Count       int                  `json:"count"`
+	Unique      bool                 `json:"unique"`
+	ElapsedMs   int64                `json:"elapsedMs"` // Including the hierarchy dump
+	Matches     []SelectorMatch      `json:"matches"`
+	Suggestions []SelectorSuggestion `json:"suggestions"` // For the first match
+}
+
+// SelectorMatch is one element matched by TestSelector; Index is what ElementSelector.Index
+// needs to pick it
+type SelectorMatch struct {
+	Index       int    `json:"index"`
+	Bounds      string `json:"bounds"`
+	CenterX     int    `json:"centerX"`
+	CenterY     int    `json:"centerY"`
+	Text        string `json:"text"`
+	ResourceID  string `json:"resourceId"`
+	Class       string `json:"class"`
+	ContentDesc string `json:"contentDesc"`
+}
+
 // DefaultElementActionConfig returns default configuration
 func DefaultElementActionConfig() ElementActionConfig {
 	return ElementActionConfig{
@@ -44,14 +100,14 @@ func (a *App) ClickElement(ctx context.Context, deviceId string, selector *Eleme
 		config = &cfg
 	}
 
-	node, err := a.waitForElement(ctx, deviceId, selector, config.Timeout, config.RetryInterval)
+	nodeBounds, err := a.actionBounds(ctx, deviceId, selector, config)
 	if err != nil {
 		return err
 	}
 
-	bounds, err := ParseBounds(node.Bounds)
+	bounds, err := ParseBounds(nodeBounds)
 	if err != nil {
-		return fmt.Errorf("invalid bounds: %s", node.Bounds)
+		return fmt.Errorf("invalid bounds: %s", nodeBounds)
 	}
 
 	x, y := bounds.Center()
@@ -69,14 +125,14 @@ func (a *App) LongClickElement(ctx context.Context, deviceId string, selector *E
 		duration = 1000
 	}
 
-	node, err := a.waitForElement(ctx, deviceId, selector, config.Timeout, config.RetryInterval)
+	nodeBounds, err := a.actionBounds(ctx, deviceId, selector, config)
 	if err != nil {
 		return err
 	}
 
-	bounds, err := ParseBounds(node.Bounds)
+	bounds, err := ParseBounds(nodeBounds)
 	if err != nil {
-		return fmt.Errorf("invalid bounds: %s", node.Bounds)
+		return fmt.Errorf("invalid bounds: %s", nodeBounds)
 	}
 
 	x, y := bounds.Center()
@@ -112,16 +168,12 @@ func (a *App) InputTextToElement(ctx context.Context, deviceId string, selector
 
 	// Clear existing text if requested
 	if clearFirst {
-		// Select all and delete
-		a.RunAdbCommand(deviceId, "shell input keyevent 67") // Delete key, multiple times
+		if err := a.ClearTextField(deviceId, utf8.RuneCountInString(node.Text)); err != nil {
+			return err
+		}
 	}
 
-	// Escape special characters for shell
-	escapedText := strings.ReplaceAll(text, " ", "%s")
-	escapedText = strings.ReplaceAll(escapedText, "'", "\\'")
-
-	_, err = a.RunAdbCommand(deviceId, fmt.Sprintf("shell input text '%s'", escapedText))
-	return err
+	return a.typeText(deviceId, text)
 }
 
 // SwipeOnElement finds an element and performs a swipe from its center
@@ -137,14 +189,14 @@ func (a *App) SwipeOnElement(ctx context.Context, deviceId string, selector *Ele
 		duration = 500
 	}
 
-	node, err := a.waitForElement(ctx, deviceId, selector, config.Timeout, config.RetryInterval)
+	nodeBounds, err := a.actionBounds(ctx, deviceId, selector, config)
 	if err != nil {
 		return err
 	}
 
-	bounds, err := ParseBounds(node.Bounds)
+	bounds, err := ParseBounds(nodeBounds)
 	if err != nil {
-		return fmt.Errorf("invalid bounds: %s", node.Bounds)
+		return fmt.Errorf("invalid bounds: %s", nodeBounds)
 	}
 
 	x, y := bounds.Center()
@@ -171,46 +223,28 @@ func (a *App) SwipeOnElement(ctx context.Context, deviceId string, selector *Ele
 // Wait Operations
 // ========================================
 
-// WaitForElement waits for an element to appear
-func (a *App) WaitForElement(ctx context.Context, deviceId string, selector *ElementSelector, timeout int) error {
-	if timeout <= 0 {
-		timeout = 10000
+// WaitForElement polls the UI until the selector matches or timeoutMs passes, returning the
+// matched bounds and how long it took
+func (a *App) WaitForElement(deviceId string, selector ElementSelector, timeoutMs, pollMs int) (*ElementWaitResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
 	}
-	_, err := a.waitForElement(ctx, deviceId, selector, timeout, 1000)
-	return err
+	return a.pollElement(context.Background(), deviceId, &selector, timeoutMs, pollMs, false)
 }
 
-// WaitElementGone waits for an element to disappear
-func (a *App) WaitElementGone(ctx context.Context, deviceId string, selector *ElementSelector, timeout int) error {
-	if timeout <= 0 {
-		timeout = 10000
+// WaitForElementGone polls the UI until the selector no longer matches, e.g. a spinner
+// being dismissed
+func (a *App) WaitForElementGone(deviceId string, selector ElementSelector, timeoutMs, pollMs int) (*ElementWaitResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
 	}
+	return a.pollElement(context.Background(), deviceId, &selector, timeoutMs, pollMs, true)
+}
 
-	startTime := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if time.Since(startTime) > time.Duration(timeout)*time.Millisecond {
-			return fmt.Errorf("timeout waiting for element to disappear")
-		}
-
-		hierarchy, err := a.GetUIHierarchy(deviceId)
-		if err != nil {
-			time.Sleep(1 * time.Second)
-			continue
-		}
-
-		node := a.FindElementBySelector(hierarchy.Root, selector)
-		if node == nil {
-			return nil // Element is gone
-		}
-
-		time.Sleep(1 * time.Second)
-	}
+// waitElementGone is WaitForElementGone bound to a playback or workflow context
+func (a *App) waitElementGone(ctx context.Context, deviceId string, selector *ElementSelector, timeout int) error {
+	_, err := a.pollElement(ctx, deviceId, selector, timeout, 1000, true)
+	return err
 }
 
 // ========================================
@@ -219,7 +253,7 @@ func (a *App) WaitElementGone(ctx context.Context, deviceId string, selector *El
 
 // AssertElementExists checks if an element exists
 func (a *App) AssertElementExists(deviceId string, selector *ElementSelector) (bool, error) {
-	hierarchy, err := a.GetUIHierarchy(deviceId)
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
 	if err != nil {
 		return false, err
 	}
@@ -230,7 +264,7 @@ func (a *App) AssertElementExists(deviceId string, selector *ElementSelector) (b
 
 // AssertElementText checks if an element's text matches
 func (a *App) AssertElementText(deviceId string, selector *ElementSelector, expectedText string, contains bool) (bool, error) {
-	hierarchy, err := a.GetUIHierarchy(deviceId)
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
 	if err != nil {
 		return false, err
 	}
@@ -246,13 +280,87 @@ func (a *App) AssertElementText(deviceId string, selector *ElementSelector, expe
 	return node.Text == expectedText, nil
 }
 
+// ========================================
+// Selector Testing
+// ========================================
+
+// TestSelector dumps the current screen and reports every element the selector matches, so a
+// selector can be checked for uniqueness before it goes into a workflow. Failures wrap
+// ErrUIDumpFailed, ErrInvalidSelector or ErrNoSelectorMatch.
+func (a *App) TestSelector(deviceId string, selector ElementSelector) (*SelectorTestResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if err := validateSelector(&selector); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUIDumpFailed, err)
+	}
+	nodes := a.FindAllElementsBySelector(hierarchy.Root, &selector)
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s %q", ErrNoSelectorMatch, selector.Type, selector.Value)
+	}
+
+	result := &SelectorTestResult{
+		Count:       len(nodes),
+		Unique:      len(nodes) == 1,
+		Matches:     make([]SelectorMatch, 0, len(nodes)),
+		Suggestions: a.GenerateSelectorSuggestions(nodes[0], hierarchy.Root),
+	}
+	for i, node := range nodes {
+		match := SelectorMatch{
+			Index:       i,
+			Bounds:      node.Bounds,
+			Text:        node.Text,
+			ResourceID:  node.ResourceID,
+			Class:       node.Class,
+			ContentDesc: node.ContentDesc,
+		}
+		if bounds, err := ParseBounds(node.Bounds); err == nil {
+			match.CenterX, match.CenterY = bounds.Center()
+		}
+		result.Matches = append(result.Matches, match)
+	}
+	result.ElapsedMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// validateSelector rejects selectors that could never match before anything is dumped
+func validateSelector(selector *ElementSelector) error {
+	if strings.TrimSpace(selector.Value) == "" {
+		return fmt.Errorf("%w: empty %s value", ErrInvalidSelector, selector.Type)
+	}
+	switch selector.Type {
+	case "text", "id", "desc", "description", "class", "contains", "coordinates":
+	case "xpath":
+		if _, err := compileXPath(selector.Value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+	case "advanced":
+		if _, err := compileAdvancedQuery(selector.Value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+	case "bounds":
+		if _, err := ParseBounds(selector.Value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidSelector, selector.Type)
+	}
+	return nil
+}
+
 // ========================================
 // Element Properties
 // ========================================
 
 // GetElementProperties returns all properties of an element
 func (a *App) GetElementProperties(deviceId string, selector *ElementSelector) (map[string]interface{}, error) {
-	hierarchy, err := a.GetUIHierarchy(deviceId)
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
 	if err != nil {
 		return nil, err
 	}
@@ -292,69 +400,131 @@ func (a *App) GetElementProperties(deviceId string, selector *ElementSelector) (
 // Scroll Operations
 // ========================================
 
-// ScrollToElement scrolls until the element is found
-func (a *App) ScrollToElement(ctx context.Context, deviceId string, selector *ElementSelector, direction string, maxScrolls int) error {
-	if maxScrolls <= 0 {
-		maxScrolls = 10
+// ScrollToElement swipes through a list until selector appears, returning its bounds. Swipes
+// stay inside container's bounds, or the largest scrollable element when container is nil.
+// direction is the finger's direction ("up" reveals what is below) and defaults to "up".
+func (a *App) ScrollToElement(deviceId string, selector ElementSelector, container *ElementSelector, maxSwipes int, direction string) (*ScrollResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
 	}
+	return a.scrollToElement(context.Background(), deviceId, &selector, container, maxSwipes, direction)
+}
 
-	for i := 0; i < maxScrolls; i++ {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+func (a *App) scrollToElement(ctx context.Context, deviceId string, selector, container *ElementSelector, maxSwipes int, direction string) (*ScrollResult, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("selector is nil")
+	}
+	if maxSwipes <= 0 {
+		maxSwipes = 10
+	}
+	if direction == "" {
+		direction = "up"
+	}
 
-		// Check if element exists
-		hierarchy, err := a.GetUIHierarchy(deviceId)
+	start := time.Now()
+	result := &ScrollResult{}
+	var lastState string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hierarchy, err := a.GetUIHierarchy(deviceId, false)
 		if err != nil {
-			continue
+			return nil, err
 		}
-
-		node := a.FindElementBySelector(hierarchy.Root, selector)
-		if node != nil {
-			return nil // Found
+		if node := a.FindElementBySelector(hierarchy.Root, selector); node != nil {
+			result.Found = true
+			result.Bounds = node.Bounds
+			result.ElapsedMs = time.Since(start).Milliseconds()
+			return result, nil
 		}
 
-		// Perform scroll
-		err = a.performScroll(deviceId, direction)
-		if err != nil {
-			return err
+		scroller := a.scrollContainer(hierarchy.Root, container)
+		if container != nil && scroller == nil {
+			return nil, fmt.Errorf("scroll container %s %q not found", container.Type, container.Value)
+		}
+		// Two identical dumps of the list in a row mean the last swipe moved nothing
+		state := hierarchy.RawXML
+		if scroller != nil {
+			data, _ := json.Marshal(scroller)
+			state = string(data)
 		}
+		if result.Swipes > 0 && state == lastState {
+			result.EndOfList = true
+			break
+		}
+		if result.Swipes >= maxSwipes {
+			break
+		}
+		lastState = state
 
-		time.Sleep(500 * time.Millisecond)
+		if err := a.swipeWithin(deviceId, scroller, direction); err != nil {
+			return nil, err
+		}
+		// Scrolling leaves the focused window alone, so the cache can't tell the dump is stale
+		a.InvalidateUIDump(deviceId)
+		result.Swipes++
+		// Let the fling settle before dumping again
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
 	}
 
-	return fmt.Errorf("element not found after %d scrolls", maxScrolls)
+	if result.EndOfList {
+		return nil, fmt.Errorf("%w: %s %q, reached the end of the list after %d swipes", ErrNoSelectorMatch, selector.Type, selector.Value, result.Swipes)
+	}
+	return nil, fmt.Errorf("%w: %s %q after %d swipes", ErrNoSelectorMatch, selector.Type, selector.Value, result.Swipes)
 }
 
-func (a *App) performScroll(deviceId string, direction string) error {
-	// Get screen size for scroll coordinates
-	resolution, _ := a.GetDeviceResolution(deviceId)
-	width, height := 1080, 1920
-	if parts := strings.Split(resolution, "x"); len(parts) == 2 {
-		fmt.Sscanf(parts[0], "%d", &width)
-		fmt.Sscanf(parts[1], "%d", &height)
+// scrollContainer resolves the container selector, or picks the largest scrollable element
+func (a *App) scrollContainer(root *UINode, container *ElementSelector) *UINode {
+	if container != nil {
+		return a.FindElementBySelector(root, container)
 	}
+	var best *UINode
+	bestArea := 0
+	a.collectMatchingNodes(root, func(n *UINode) bool {
+		if n.Scrollable != "true" {
+			return false
+		}
+		if b, err := ParseBounds(n.Bounds); err == nil && b.Area() > bestArea {
+			best, bestArea = n, b.Area()
+		}
+		return false
+	})
+	return best
+}
 
-	centerX := width / 2
-	centerY := height / 2
-	scrollDist := height / 3
+// swipeWithin swipes across the middle half of node, or of the screen when node is nil
+func (a *App) swipeWithin(deviceId string, node *UINode, direction string) error {
+	var rect *BoundsRect
+	if node != nil {
+		rect, _ = ParseBounds(node.Bounds)
+	}
+	if rect == nil || rect.Area() <= 0 {
+		resolution, _ := a.GetDeviceResolution(deviceId)
+		width, height := 1080, 1920
+		if parts := strings.Split(resolution, "x"); len(parts) == 2 {
+			fmt.Sscanf(parts[0], "%d", &width)
+			fmt.Sscanf(parts[1], "%d", &height)
+		}
+		rect = &BoundsRect{X1: 0, Y1: 0, X2: width, Y2: height}
+	}
 
+	cx, cy := rect.Center()
+	dx, dy := (rect.X2-rect.X1)/4, (rect.Y2-rect.Y1)/4
 	var x1, y1, x2, y2 int
 	switch strings.ToLower(direction) {
 	case "up":
-		x1, y1 = centerX, centerY+scrollDist/2
-		x2, y2 = centerX, centerY-scrollDist/2
+		x1, y1, x2, y2 = cx, cy+dy, cx, cy-dy
 	case "down":
-		x1, y1 = centerX, centerY-scrollDist/2
-		x2, y2 = centerX, centerY+scrollDist/2
+		x1, y1, x2, y2 = cx, cy-dy, cx, cy+dy
 	case "left":
-		x1, y1 = centerX+scrollDist/2, centerY
-		x2, y2 = centerX-scrollDist/2, centerY
+		x1, y1, x2, y2 = cx+dx, cy, cx-dx, cy
 	case "right":
-		x1, y1 = centerX-scrollDist/2, centerY
-		x2, y2 = centerX+scrollDist/2, centerY
+		x1, y1, x2, y2 = cx-dx, cy, cx+dx, cy
 	default:
 		return fmt.Errorf("invalid scroll direction: %s", direction)
 	}
@@ -373,12 +543,43 @@ func (a *App) waitForElement(ctx context.Context, deviceId string, selector *Ele
 		return nil, fmt.Errorf("selector is nil")
 	}
 
-	// Handle bounds selector directly (no need to wait/search)
-	if selector.Type == "bounds" {
-		return &UINode{Bounds: selector.Value}, nil
+	result, err := a.pollElement(ctx, deviceId, selector, timeout, retryInterval, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.node, nil
+}
+
+// actionBounds returns the bounds a tap-style action should hit. A plain bounds selector
+// already names them, so only the coordinates are used and no dump is taken; anything else
+// waits for the element.
+func (a *App) actionBounds(ctx context.Context, deviceId string, selector *ElementSelector, config *ElementActionConfig) (string, error) {
+	if selector != nil && selector.Type == "bounds" && selector.Within == nil && selector.ChildIndex == nil {
+		return selector.Value, nil
+	}
+	node, err := a.waitForElement(ctx, deviceId, selector, config.Timeout, config.RetryInterval)
+	if err != nil {
+		return "", err
+	}
+	return node.Bounds, nil
+}
+
+// pollElement dumps the hierarchy once per poll and checks it for the selector until it
+// appears (or, with gone set, disappears). A failed dump counts as neither.
+func (a *App) pollElement(ctx context.Context, deviceId string, selector *ElementSelector, timeout, pollInterval int, gone bool) (*ElementWaitResult, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("selector is nil")
+	}
+	if timeout <= 0 {
+		timeout = 10000
+	}
+	if pollInterval <= 0 {
+		pollInterval = 1000
 	}
 
 	startTime := time.Now()
+	deadline := startTime.Add(time.Duration(timeout) * time.Millisecond)
+	result := &ElementWaitResult{}
 	for {
 		select {
 		case <-ctx.Done():
@@ -386,22 +587,33 @@ func (a *App) waitForElement(ctx context.Context, deviceId string, selector *Ele
 		default:
 		}
 
-		if time.Since(startTime) > time.Duration(timeout)*time.Millisecond {
-			return nil, fmt.Errorf("element not found within timeout (selector: %s=%s)", selector.Type, selector.Value)
+		hierarchy, err := a.GetUIHierarchy(deviceId, false)
+		if err == nil {
+			result.Polls++
+			node := a.FindElementBySelector(hierarchy.Root, selector)
+			if (node != nil) != gone {
+				result.ElapsedMs = time.Since(startTime).Milliseconds()
+				if node != nil {
+					result.Found = true
+					result.Bounds = node.Bounds
+					result.node = node
+				}
+				return result, nil
+			}
 		}
 
-		hierarchy, err := a.GetUIHierarchy(deviceId)
-		if err != nil {
-			time.Sleep(time.Duration(retryInterval) * time.Millisecond)
-			continue
+		if !time.Now().Before(deadline) {
+			if gone {
+				return nil, fmt.Errorf("timeout waiting for element to disappear (selector: %s=%s)", selector.Type, selector.Value)
+			}
+			return nil, fmt.Errorf("element not found within timeout (selector: %s=%s)", selector.Type, selector.Value)
 		}
 
-		node := a.FindElementBySelector(hierarchy.Root, selector)
-		if node != nil {
-			return node, nil
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(pollInterval) * time.Millisecond):
 		}
-
-		time.Sleep(time.Duration(retryInterval) * time.Millisecond)
 	}
 }
 
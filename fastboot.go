@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrFastbootNotFound is returned by fastboot actions when no fastboot binary was located
+var ErrFastbootNotFound = errors.New("fastboot was not found; install the Android SDK platform-tools or put fastboot on PATH")
+
+// fastbootPollInterval is how often the device monitor looks for bootloader-mode devices
+const fastbootPollInterval = 2 * time.Second
+
+// partitionNameRegex matches a partition name as fastboot takes it ("boot", "vendor_boot_a")
+var partitionNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// newFastbootCommand creates an exec.Cmd for fastboot
+func (a *App) newFastbootCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	if a.fastbootPath == "" {
+		return nil, ErrFastbootNotFound
+	}
+	if ctx == nil {
+		return exec.Command(a.fastbootPath, args...), nil
+	}
+	return exec.CommandContext(ctx, a.fastbootPath, args...), nil
+}
+
+// parseFastbootDevices reads `fastboot devices` ("SERIAL\tfastboot", "tcp:192.168.1.5\tfastboot")
+func parseFastbootDevices(output string) []string {
+	var serials []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.HasPrefix(fields[1], "fastboot") {
+			serials = append(serials, fields[0])
+		}
+	}
+	sort.Strings(serials)
+	return serials
+}
+
+// listFastbootSerials returns the serials of devices in the bootloader or fastbootd, or nil when
+// fastboot isn't available
+func (a *App) listFastbootSerials(ctx context.Context) []string {
+	cmdCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cmd, err := a.newFastbootCommand(cmdCtx, "devices")
+	if err != nil {
+		return nil
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseFastbootDevices(string(out))
+}
+
+// fastbootDevice is the device list entry for a bootloader-mode device. The "fastboot" state tells
+// the UI to offer fastboot actions instead of adb ones.
+func fastbootDevice(serial string) *Device {
+	d := &Device{ID: serial, Serial: serial, State: "fastboot", IDs: []string{serial}, Type: "wired"}
+	if strings.HasPrefix(serial, "tcp:") || strings.HasPrefix(serial, "udp:") {
+		d.Type = "wireless"
+	}
+	return d
+}
+
+// ListFastbootDevices lists the devices fastboot can see
+func (a *App) ListFastbootDevices() ([]Device, error) {
+	if a.fastbootPath == "" {
+		return nil, ErrFastbootNotFound
+	}
+	devices := []Device{}
+	for _, serial := range a.listFastbootSerials(context.Background()) {
+		devices = append(devices, *fastbootDevice(serial))
+	}
+	return devices, nil
+}
+
+// watchFastbootDevices polls for bootloader-mode devices, which adb track-devices doesn't
+// report, and calls onChange whenever the set changes
+func (a *App) watchFastbootDevices(ctx context.Context, onChange func()) {
+	if a.fastbootPath == "" {
+		return
+	}
+	ticker := time.NewTicker(fastbootPollInterval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current := strings.Join(a.listFastbootSerials(ctx), ",")
+		if current != last {
+			last = current
+			onChange()
+		}
+	}
+}
+
+// parseFastbootVars reads `fastboot getvar all`, whose lines look like "(bootloader) product:sargo"
+// or "(bootloader) partition-size:boot_a:0x4000000"; the value follows the last colon
+func parseFastbootVars(output string) *FastbootVars {
+	vars := &FastbootVars{Vars: make(map[string]string)}
+	for _, line := range strings.Split(output, "\n") {
+		line, ok := strings.CutPrefix(strings.TrimSpace(line), "(bootloader) ")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndex(line, ":")
+		if i <= 0 {
+			continue
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		vars.Vars[key] = value
+		switch key {
+		case "product":
+			vars.Product = value
+		case "serialno":
+			vars.Serial = value
+		case "unlocked":
+			vars.Unlocked = value == "yes"
+		case "current-slot":
+			vars.CurrentSlot = value
+		case "slot-count":
+			vars.SlotCount = value
+		case "is-userspace":
+			vars.Userspace = value == "yes"
+		case "version-bootloader":
+			vars.BootloaderVersion = value
+		case "version-baseband":
+			vars.BasebandVersion = value
+		case "secure":
+			vars.SecureBoot = value == "yes"
+		}
+	}
+	// Some bootloaders report the lock state only as device-state
+	if state, ok := vars.Vars["device-state"]; ok && vars.Vars["unlocked"] == "" {
+		vars.Unlocked = state == "unlocked"
+	}
+	return vars
+}
+
+// GetFastbootVars reads the bootloader variables of a fastboot device: product, lock state,
+// slots and versions, with everything reported in Vars
+func (a *App) GetFastbootVars(serial string) (*FastbootVars, error) {
+	if serial == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	cmd, err := a.newFastbootCommand(ctx, "-s", serial, "getvar", "all")
+	if err != nil {
+		return nil, err
+	}
+	// fastboot prints the variables on stderr
+	out, err := cmd.CombinedOutput()
+	vars := parseFastbootVars(string(out))
+	if len(vars.Vars) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no variables reported")
+		}
+		return nil, fmt.Errorf("failed to read fastboot variables: %w, %s", err, lastLines(strings.TrimSpace(string(out)), 3))
+	}
+	if vars.Serial == "" {
+		vars.Serial = serial
+	}
+	return vars, nil
+}
+
+// FastbootReboot reboots a fastboot device into the system ("" or "system"), the bootloader,
+// fastbootd ("fastboot") or recovery
+func (a *App) FastbootReboot(serial, target string) error {
+	if serial == "" {
+		return fmt.Errorf("no device specified")
+	}
+	args := []string{"-s", serial, "reboot"}
+	switch target {
+	case "", "system":
+	case "bootloader", "fastboot", "recovery":
+		args = append(args, target)
+	default:
+		return fmt.Errorf("unknown reboot target %q", target)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd, err := a.newFastbootCommand(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fastboot reboot failed: %w, %s", err, lastLines(strings.TrimSpace(string(out)), 3))
+	}
+	a.Log("Rebooted %s from fastboot to %s", serial, onOff(target == "", "system", target))
+	return nil
+}
+
+// fastbootFlashCommand is the command a flash is confirmed and audited as
+func fastbootFlashCommand(partition, imagePath string) string {
+	return "fastboot flash " + partition + " " + imagePath
+}
+
+// CheckFastbootFlash returns the confirmation FlashPartition needs for this partition and image
+func (a *App) CheckFastbootFlash(partition, imagePath string) DangerousCommandCheck {
+	return a.CheckDangerousCommand(fastbootFlashCommand(partition, imagePath))
+}
+
+// FlashPartition writes imagePath to a partition of a fastboot device. confirmToken comes from
+// CheckFastbootFlash. Output is sent as fastboot-output events while it runs, followed by
+// fastboot-finished.
+func (a *App) FlashPartition(serial, partition, imagePath, confirmToken string) error {
+	if serial == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if !partitionNameRegex.MatchString(partition) {
+		return fmt.Errorf("invalid partition name %q", partition)
+	}
+	if info, err := os.Stat(imagePath); err != nil || info.IsDir() {
+		return fmt.Errorf("image %s not found", imagePath)
+	}
+	if err := a.guardDangerousCommand(serial, fastbootFlashCommand(partition, imagePath), confirmToken); err != nil {
+		return err
+	}
+
+	err := a.runFastbootStreamed(serial, "-s", serial, "flash", partition, imagePath)
+	result := map[string]interface{}{"serial": serial, "partition": partition, "success": err == nil}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+	wailsRuntime.EventsEmit(a.ctx, "fastboot-finished", result)
+	if err != nil {
+		return err
+	}
+	a.Log("Flashed %s on %s with %s", partition, serial, imagePath)
+	return nil
+}
+
+// runFastbootStreamed runs fastboot and emits each output line as a fastboot-output event
+func (a *App) runFastbootStreamed(serial string, args ...string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd, err := a.newFastbootCommand(ctx, args...)
+	if err != nil {
+		return err
+	}
+	tail, err := a.runMergedStreamed("fastboot", cmd, cancel, func(line string) {
+		wailsRuntime.EventsEmit(a.ctx, "fastboot-output", map[string]interface{}{"serial": serial, "line": line})
+	})
+	if err != nil {
+		return fmt.Errorf("fastboot failed: %w, %s", err, strings.Join(tail, "\n"))
+	}
+	return nil
+}
@@ -5,22 +5,48 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"
 	"image/jpeg"
 	_ "image/png"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+var (
+	// ErrPermissionDenied is returned when the device refuses access to a path
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNoSuchDirectory is returned when the requested path does not exist or is not a directory
+	ErrNoSuchDirectory = errors.New("no such directory")
+	// ErrPathNotFound is returned when a file operation targets a missing path
+	ErrPathNotFound = errors.New("no such file or directory")
+	// ErrReadOnlyFilesystem is returned when the target lives on a read-only mount
+	ErrReadOnlyFilesystem = errors.New("read-only file system")
+	// ErrDirectoryNotEmpty is returned when a non-recursive delete hits a populated directory
+	ErrDirectoryNotEmpty = errors.New("directory not empty")
+)
+
+var lsDateTimeRegex = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}\s+\d{2}:\d{2})|([A-Z][a-z]{2}\s+\d{1,2}\s+(\d{2}:\d{2}|\d{4}))`)
+
+// shellQuote wraps s in single quotes so it survives the device shell unchanged
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // ListFiles returns a list of files in the specified directory on the device
 func (a *App) ListFiles(deviceId, pathStr string) ([]FileInfo, error) {
 	a.updateLastActive(deviceId)
@@ -31,78 +57,160 @@ func (a *App) ListFiles(deviceId, pathStr string) ([]FileInfo, error) {
 	pathStr = path.Clean("/" + pathStr)
 	cmdPath := pathStr
 	if cmdPath != "/" {
+		// Trailing slash makes ls follow a symlinked directory (e.g. /sdcard) instead of listing the link itself
 		cmdPath += "/"
 	}
 
-	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "ls", "-la", "\""+cmdPath+"\"")
-	output, err := cmd.CombinedOutput()
+	files, err := a.listFilesWith(deviceId, pathStr, "ls -la "+shellQuote(cmdPath))
+	if err != nil && !errors.Is(err, ErrPermissionDenied) && !errors.Is(err, ErrNoSuchDirectory) {
+		// Older toolbox builds ship a limited ls; toybox is present on every modern Android
+		if toyboxFiles, toyboxErr := a.listFilesWith(deviceId, pathStr, "toybox ls -la "+shellQuote(cmdPath)); toyboxErr == nil {
+			files, err = toyboxFiles, nil
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w (output: %s)", err, string(output))
+		return nil, err
+	}
+
+	a.resolveSymlinkDirs(deviceId, pathStr, files)
+	go a.recordRecentPath(deviceId, pathStr)
+	return files, nil
+}
+
+// listFilesWith runs a single ls variant and parses its output
+func (a *App) listFilesWith(deviceId, pathStr, shellCmd string) ([]FileInfo, error) {
+	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", shellCmd)
+	output, cmdErr := cmd.CombinedOutput()
+	outStr := string(output)
+
+	files := parseLsOutput(outStr, pathStr)
+	if len(files) > 0 {
+		// Partial errors (e.g. unreadable entries) still leave a usable listing
+		return files, nil
 	}
 
-	dateTimeRegex := regexp.MustCompile(`(\d{4}-\d{2}-\d{2}\s+\d{2}:\d{2})|([A-Z][a-z]{2}\s+\d{1,2}\s+(\d{2}:\d{2}|\d{4}))`)
+	lower := strings.ToLower(outStr)
+	switch {
+	case strings.Contains(lower, "permission denied"):
+		return nil, fmt.Errorf("%w: %s", ErrPermissionDenied, pathStr)
+	case strings.Contains(lower, "no such file or directory"), strings.Contains(lower, "not a directory"):
+		return nil, fmt.Errorf("%w: %s", ErrNoSuchDirectory, pathStr)
+	}
+
+	if cmdErr != nil {
+		return nil, fmt.Errorf("failed to list files: %w (output: %s)", cmdErr, outStr)
+	}
+	return files, nil
+}
 
-	lines := strings.Split(string(output), "\n")
+// parseLsOutput parses `ls -la` lines into FileInfo entries under pathStr
+func parseLsOutput(output, pathStr string) []FileInfo {
 	var files []FileInfo
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "total ") {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "total ") {
 			continue
 		}
 
-		loc := dateTimeRegex.FindStringIndex(line)
+		loc := lsDateTimeRegex.FindStringIndex(line)
 		if loc == nil {
 			continue
 		}
 
 		modTime := line[loc[0]:loc[1]]
-		afterDateTime := strings.TrimSpace(line[loc[1]:])
-		beforeDateTime := strings.TrimSpace(line[:loc[0]])
-		beforeParts := strings.Fields(beforeDateTime)
+		// Exactly one separator follows the time; anything after it belongs to the name
+		name := strings.TrimPrefix(line[loc[1]:], " ")
+		beforeParts := strings.Fields(line[:loc[0]])
 
 		if len(beforeParts) < 1 {
 			continue
 		}
 
 		mode := beforeParts[0]
+		if len(mode) < 10 || !strings.ContainsRune("-dlcbps", rune(mode[0])) {
+			continue
+		}
 		isDir := strings.HasPrefix(mode, "d")
 		isLink := strings.HasPrefix(mode, "l")
 
-		var size int64
-		if len(beforeParts) >= 1 {
-			fmt.Sscanf(beforeParts[len(beforeParts)-1], "%d", &size)
+		// mode, links, owner, group, size
+		var owner, group string
+		if len(beforeParts) >= 5 {
+			owner = beforeParts[2]
+			group = beforeParts[3]
+		} else if len(beforeParts) >= 3 {
+			// toolbox ls omits the link count (and the size for directories)
+			owner = beforeParts[1]
+			group = beforeParts[2]
 		}
 
-		name := afterDateTime
+		var size int64
+		fmt.Sscanf(beforeParts[len(beforeParts)-1], "%d", &size)
+
+		var linkTarget string
 		if isLink {
-			arrowIdx := strings.Index(name, " -> ")
-			if arrowIdx != -1 {
+			if arrowIdx := strings.Index(name, " -> "); arrowIdx != -1 {
+				linkTarget = name[arrowIdx+4:]
 				name = name[:arrowIdx]
 			}
-			isDir = true
 		}
 
-		cleanName := strings.TrimSpace(name)
-		if cleanName == "." || cleanName == ".." || cleanName == "" || cleanName == "?" {
+		if name == "." || name == ".." || strings.TrimSpace(name) == "" || name == "?" {
 			continue
 		}
-
-		if cleanName == path.Base(pathStr) || cleanName == pathStr {
+		if name == pathStr {
 			continue
 		}
 
 		files = append(files, FileInfo{
-			Name:    cleanName,
-			Size:    size,
-			Mode:    mode,
-			ModTime: modTime,
-			IsDir:   isDir,
-			Path:    path.Join(pathStr, cleanName),
+			Name:       name,
+			Size:       size,
+			Mode:       mode,
+			Owner:      owner,
+			Group:      group,
+			ModTime:    modTime,
+			IsDir:      isDir,
+			IsSymlink:  isLink,
+			LinkTarget: linkTarget,
+			// Paths stay lexical so parent navigation never jumps through a symlink target
+			Path: path.Join(pathStr, name),
 		})
 	}
 
-	return files, nil
+	return files
+}
+
+// resolveSymlinkDirs marks symlinks that point at directories as navigable
+func (a *App) resolveSymlinkDirs(deviceId, pathStr string, files []FileInfo) {
+	var names []string
+	for _, f := range files {
+		if f.IsSymlink {
+			names = append(names, shellQuote(f.Name))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	// One D or F line per link, so a failed check is told apart from "not a directory"
+	script := fmt.Sprintf(`cd %s && for f in %s; do if [ -d "$f" ]; then echo "D|$f"; else echo "F|$f"; fi; done`, shellQuote(pathStr), strings.Join(names, " "))
+	output, _ := exec.Command(a.adbPath, "-s", deviceId, "shell", script).Output()
+
+	kinds := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if kind, name, ok := strings.Cut(strings.TrimRight(line, "\r"), "|"); ok && (kind == "D" || kind == "F") {
+			kinds[name] = kind
+		}
+	}
+	for i := range files {
+		if !files[i].IsSymlink {
+			continue
+		}
+		kind, checked := kinds[files[i].Name]
+		// Keep links navigable when the check itself failed
+		files[i].IsDir = kind == "D" || !checked
+	}
 }
 
 // DownloadFile pulls a file from the device to a user-selected local path
@@ -156,61 +264,145 @@ func (a *App) UploadFile(deviceId, localPath, remotePath string) error {
 
 // DeleteFile deletes a file or directory on the device
 func (a *App) DeleteFile(deviceId, pathStr string) error {
-	a.updateLastActive(deviceId)
-	if deviceId == "" {
-		return fmt.Errorf("no device specified")
-	}
-	pathStr = path.Clean("/" + pathStr)
-	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "rm", "-rf", "\""+pathStr+"\"")
+	_, err := a.DeleteRemotePath(deviceId, pathStr, true, false)
+	return err
+}
+
+// MoveFile moves or renames a file or directory on the device
+func (a *App) MoveFile(deviceId, src, dest string) error {
+	return a.MoveRemotePath(deviceId, src, dest)
+}
+
+// CopyFile copies a file or directory on the device
+func (a *App) CopyFile(deviceId, src, dest string) error {
+	return a.CopyRemotePath(deviceId, src, dest)
+}
+
+// Mkdir creates a new directory on the device
+func (a *App) Mkdir(deviceId, pathStr string) error {
+	return a.CreateRemoteDirectory(deviceId, pathStr)
+}
+
+// runRemoteFileOp runs a shell snippet and maps common failures to typed errors
+func (a *App) runRemoteFileOp(deviceId, target, script string) error {
+	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", script)
 	output, err := cmd.CombinedOutput()
+	if typed := classifyRemoteError(string(output), target); typed != nil {
+		return typed
+	}
 	if err != nil {
 		return fmt.Errorf("%w: %s", err, string(output))
 	}
 	return nil
 }
 
-// MoveFile moves or renames a file or directory on the device
-func (a *App) MoveFile(deviceId, src, dest string) error {
+// classifyRemoteError inspects shell output for well-known filesystem failures
+func classifyRemoteError(output, target string) error {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "read-only file system"):
+		return fmt.Errorf("%w: %s", ErrReadOnlyFilesystem, target)
+	case strings.Contains(lower, "permission denied"), strings.Contains(lower, "operation not permitted"):
+		return fmt.Errorf("%w: %s", ErrPermissionDenied, target)
+	case strings.Contains(lower, "no such file or directory"):
+		return fmt.Errorf("%w: %s", ErrPathNotFound, target)
+	case strings.Contains(lower, "directory not empty"):
+		return fmt.Errorf("%w: %s", ErrDirectoryNotEmpty, target)
+	}
+	return nil
+}
+
+// DeleteRemotePath removes a file or directory. With dryRun set nothing is deleted and
+// the summary reports what would have been removed.
+func (a *App) DeleteRemotePath(deviceId, pathStr string, recursive bool, dryRun bool) (*DeleteSummary, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	pathStr = path.Clean("/" + pathStr)
+	if pathStr == "/" {
+		return nil, fmt.Errorf("refusing to delete the root directory")
+	}
+
+	summary := &DeleteSummary{Path: pathStr, DryRun: dryRun}
+	findArgs := ""
+	if !recursive {
+		findArgs = " -maxdepth 0"
+	}
+	// A large tree takes a while to walk; streaming keeps memory flat and makes it cancellable
+	var errOutput strings.Builder
+	findScript := "find " + shellQuote(pathStr) + findArgs + " -exec stat -c '%F|%s' {} +"
+	_, _ = a.runStreamedCommand(nil, deviceId, []string{"shell", findScript}, func(stream, line string) {
+		parts := strings.SplitN(line, "|", 2)
+		if len(parts) != 2 {
+			errOutput.WriteString(line + "\n")
+			return
+		}
+		if strings.Contains(parts[0], "directory") {
+			summary.Dirs++
+			return
+		}
+		size, _ := strconv.ParseInt(parts[1], 10, 64)
+		summary.Files++
+		summary.TotalSize += size
+	})
+	if summary.Files == 0 && summary.Dirs == 0 {
+		if typed := classifyRemoteError(errOutput.String(), pathStr); typed != nil {
+			return nil, typed
+		}
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+
+	script := "rm -f " + shellQuote(pathStr)
+	if recursive {
+		script = "rm -rf " + shellQuote(pathStr)
+	} else if summary.Dirs > 0 {
+		script = "rmdir " + shellQuote(pathStr)
+	}
+	if err := a.runRemoteFileOp(deviceId, pathStr, script); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// RenameRemotePath renames a file or directory in place
+func (a *App) RenameRemotePath(deviceId, oldPath, newPath string) error {
+	return a.MoveRemotePath(deviceId, oldPath, newPath)
+}
+
+// MoveRemotePath moves a file or directory on the device
+func (a *App) MoveRemotePath(deviceId, src, dest string) error {
+	a.updateLastActive(deviceId)
 	if deviceId == "" {
 		return fmt.Errorf("no device specified")
 	}
 	src = path.Clean("/" + src)
 	dest = path.Clean("/" + dest)
-	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "mv", "\""+src+"\"", "\""+dest+"\"")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%w: %s", err, string(output))
-	}
-	return nil
+	return a.runRemoteFileOp(deviceId, src, "mv "+shellQuote(src)+" "+shellQuote(dest))
 }
 
-// CopyFile copies a file or directory on the device
-func (a *App) CopyFile(deviceId, src, dest string) error {
+// CopyRemotePath copies a file or directory on the device
+func (a *App) CopyRemotePath(deviceId, src, dest string) error {
+	a.updateLastActive(deviceId)
 	if deviceId == "" {
 		return fmt.Errorf("no device specified")
 	}
 	src = path.Clean("/" + src)
 	dest = path.Clean("/" + dest)
-	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "cp", "-R", "\""+src+"\"", "\""+dest+"\"")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%w: %s", err, string(output))
-	}
-	return nil
+	return a.runRemoteFileOp(deviceId, src, "cp -r "+shellQuote(src)+" "+shellQuote(dest))
 }
 
-// Mkdir creates a new directory on the device
-func (a *App) Mkdir(deviceId, pathStr string) error {
+// CreateRemoteDirectory creates a directory (and any missing parents) on the device
+func (a *App) CreateRemoteDirectory(deviceId, pathStr string) error {
+	a.updateLastActive(deviceId)
 	if deviceId == "" {
 		return fmt.Errorf("no device specified")
 	}
 	pathStr = path.Clean("/" + pathStr)
-	cmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "mkdir", "-p", "\""+pathStr+"\"")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%w: %s", err, string(output))
-	}
-	return nil
+	return a.runRemoteFileOp(deviceId, pathStr, "mkdir -p "+shellQuote(pathStr))
 }
 
 // OpenFileOnHost pulls a file from the device to a temporary location and opens it
@@ -386,3 +578,290 @@ func (a *App) generateVideoThumbnail(localPath string) ([]byte, error) {
 
 	return os.ReadFile(tmpThumb)
 }
+
+// defaultPreviewSizeCap is the largest file PreviewRemoteFile accepts unless configured otherwise
+const defaultPreviewSizeCap = 20 * 1024 * 1024
+
+// mimeTypesByExt maps common file extensions to mime types
+var mimeTypesByExt = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+	".heic": "image/heic",
+	".svg":  "image/svg+xml",
+	".mp4":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".3gp":  "video/3gpp",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".opus": "audio/opus",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".pdf":  "application/pdf",
+	".apk":  "application/vnd.android.package-archive",
+	".zip":  "application/zip",
+	".html": "text/html",
+	".htm":  "text/html",
+	".txt":  "text/plain",
+	".log":  "text/plain",
+	".json": "application/json",
+	".xml":  "text/xml",
+	".csv":  "text/csv",
+	".md":   "text/markdown",
+	".prop": "text/plain",
+	".conf": "text/plain",
+	".ini":  "text/plain",
+	".sh":   "text/x-shellscript",
+}
+
+// GetPreviewSizeCap returns the maximum file size accepted for previews
+func (a *App) GetPreviewSizeCap() int64 {
+	a.previewMu.RLock()
+	defer a.previewMu.RUnlock()
+	return a.previewSizeCap
+}
+
+// SetPreviewSizeCap changes the maximum file size accepted for previews
+func (a *App) SetPreviewSizeCap(bytes int64) error {
+	if bytes <= 0 {
+		return fmt.Errorf("preview size cap must be positive")
+	}
+	a.previewMu.Lock()
+	a.previewSizeCap = bytes
+	a.previewMu.Unlock()
+	go a.saveSettings()
+	return nil
+}
+
+// PreviewRemoteFile reads up to maxBytes of a device file into memory for quick viewing.
+// Images are read whole, since a truncated one can't be shown.
+func (a *App) PreviewRemoteFile(deviceId, pathStr string, maxBytes int) (*FilePreview, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if maxBytes <= 0 {
+		maxBytes = 1024 * 1024
+	}
+
+	pathStr = path.Clean("/" + pathStr)
+	isDir, size, err := a.statRemote(deviceId, pathStr)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		return nil, fmt.Errorf("cannot preview a directory: %s", pathStr)
+	}
+	sizeCap := a.GetPreviewSizeCap()
+	if size > sizeCap {
+		return nil, fmt.Errorf("file is too large to preview (%d bytes, limit %d bytes); download it instead", size, sizeCap)
+	}
+
+	// exec-out skips the pty so binary data is not CR/LF mangled
+	cmd := exec.Command(a.adbPath, "-s", deviceId, "exec-out", "cat "+shellQuote(pathStr))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	data, readErr := io.ReadAll(io.LimitReader(stdout, int64(maxBytes)))
+
+	mimeType := mimeTypesByExt[strings.ToLower(path.Ext(pathStr))]
+	sniffed := http.DetectContentType(data)
+	if strings.HasPrefix(sniffed, "image/") {
+		mimeType = sniffed
+	} else if mimeType == "" {
+		mimeType = sniffed
+	}
+	// A cut-off image doesn't decode, so images are read whole, up to the size cap checked above
+	if readErr == nil && strings.HasPrefix(mimeType, "image/") && int64(len(data)) < size {
+		var rest []byte
+		rest, readErr = io.ReadAll(io.LimitReader(stdout, sizeCap-int64(len(data))))
+		data = append(data, rest...)
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read file: %w", readErr)
+	}
+
+	preview := &FilePreview{
+		Path:      pathStr,
+		Size:      size,
+		MimeType:  mimeType,
+		Truncated: int64(len(data)) < size,
+	}
+
+	// A truncated read may end mid-rune; drop the partial tail before judging the text
+	text := data
+	for i := 0; i < utf8.UTFMax-1 && preview.Truncated && len(text) > 0 && !utf8.Valid(text); i++ {
+		text = text[:len(text)-1]
+	}
+
+	switch {
+	case strings.HasPrefix(mimeType, "image/") && preview.Truncated:
+		// The read ended early, e.g. the file changed while it was read
+		return nil, fmt.Errorf("failed to read the whole image: got %d of %d bytes", len(data), size)
+	case strings.HasPrefix(mimeType, "image/"):
+		preview.Kind = "image"
+		preview.Content = "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	case strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || utf8.Valid(text) && !bytes.ContainsRune(text, 0):
+		preview.Kind = "text"
+		if !strings.HasPrefix(mimeType, "text/") && mimeType != "application/json" {
+			preview.MimeType = "text/plain"
+		}
+		preview.Content = strings.ToValidUTF8(string(text), "\uFFFD")
+	default:
+		preview.Kind = "binary"
+	}
+
+	return preview, nil
+}
+
+// OpenRemoteFile opens a device file in the device's default viewer for its type
+func (a *App) OpenRemoteFile(deviceId, pathStr string) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	pathStr = path.Clean("/" + pathStr)
+
+	isDir, _, err := a.statRemote(deviceId, pathStr)
+	if err != nil {
+		return "", err
+	}
+	if isDir {
+		return "", fmt.Errorf("cannot open a directory: %s", pathStr)
+	}
+
+	mimeType := a.remoteMimeType(deviceId, pathStr)
+
+	// Viewer apps cannot read app-private or /data/local/tmp files, so stage a copy in shared
+	// storage. Each copy gets its own folder so nothing of the user's is overwritten.
+	sharedPath := pathStr
+	if !strings.HasPrefix(pathStr, "/sdcard/") && !strings.HasPrefix(pathStr, "/storage/") {
+		a.removeStagedOpenFiles(deviceId)
+		stageDir := path.Join(openStagingDir, strconv.FormatInt(time.Now().UnixNano(), 36))
+		sharedPath = path.Join(stageDir, path.Base(pathStr))
+		script := "mkdir -p " + shellQuote(stageDir) + " && cp " + shellQuote(pathStr) + " " + shellQuote(sharedPath)
+		if err := a.runRemoteFileOp(deviceId, pathStr, script); err != nil {
+			_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -rf "+shellQuote(stageDir)).Run()
+			return "", fmt.Errorf("failed to stage file in shared storage: %w", err)
+		}
+		a.openFileMu.Lock()
+		a.openStaged[deviceId] = append(a.openStaged[deviceId], sharedPath)
+		a.openFileMu.Unlock()
+	}
+
+	uri := a.mediaContentURI(deviceId, sharedPath)
+	if uri == "" {
+		// Pre-Nougat devices still accept file:// URIs
+		uri = "file://" + sharedPath
+	}
+
+	script := fmt.Sprintf("am start -a android.intent.action.VIEW -d %s -t %s --grant-read-uri-permission", shellQuote(uri), shellQuote(mimeType))
+	output, err := exec.Command(a.adbPath, "-s", deviceId, "shell", script).CombinedOutput()
+	outStr := strings.TrimSpace(string(output))
+	if strings.Contains(outStr, "No Activity found") {
+		return outStr, fmt.Errorf("no app on the device can open %s files", mimeType)
+	}
+	if err != nil || strings.Contains(outStr, "Error:") {
+		return outStr, fmt.Errorf("failed to open file: %s", outStr)
+	}
+	return outStr, nil
+}
+
+// remoteMimeType guesses a mime type from the extension, asking the device's file tool as a fallback
+func (a *App) remoteMimeType(deviceId, pathStr string) string {
+	if mimeType, ok := mimeTypesByExt[strings.ToLower(path.Ext(pathStr))]; ok {
+		return mimeType
+	}
+	output, err := exec.Command(a.adbPath, "-s", deviceId, "shell", "file -b --mime-type "+shellQuote(pathStr)).Output()
+	if err == nil {
+		if mimeType := strings.TrimSpace(string(output)); strings.Count(mimeType, "/") == 1 && !strings.Contains(mimeType, " ") {
+			return mimeType
+		}
+	}
+	return "*/*"
+}
+
+// openStagingDir holds the copies OpenRemoteFile stages for viewer apps
+const openStagingDir = "/sdcard/Download/Gaze"
+
+// removeStagedOpenFiles deletes the copies staged on a device by earlier opens, whose
+// viewers have long since read them
+func (a *App) removeStagedOpenFiles(deviceId string) {
+	a.openFileMu.Lock()
+	staged := a.openStaged[deviceId]
+	delete(a.openStaged, deviceId)
+	a.openFileMu.Unlock()
+	if len(staged) == 0 {
+		return
+	}
+
+	var files []transferFile
+	quoted := make([]string, len(staged))
+	for i, p := range staged {
+		quoted[i] = shellQuote(path.Dir(p))
+		files = append(files, transferFile{Remote: p})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	script := "rm -rf " + strings.Join(quoted, " ") + "; rmdir " + shellQuote(openStagingDir) + " 2>/dev/null"
+	if out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", script).CombinedOutput(); err != nil {
+		a.Log("Failed to remove staged files on %s: %v, %s", deviceId, err, strings.TrimSpace(string(out)))
+		return
+	}
+	// Drop the MediaStore rows of the deleted copies
+	a.scanMediaFiles(deviceId, files)
+}
+
+// removeAllStagedOpenFiles cleans up staged copies on every device, on shutdown
+func (a *App) removeAllStagedOpenFiles() {
+	a.openFileMu.Lock()
+	var devices []string
+	for deviceId := range a.openStaged {
+		devices = append(devices, deviceId)
+	}
+	a.openFileMu.Unlock()
+	for _, deviceId := range devices {
+		a.removeStagedOpenFiles(deviceId)
+	}
+}
+
+// mediaContentURI looks up the MediaStore row for a shared-storage file, scanning it first if needed
+func (a *App) mediaContentURI(deviceId, sharedPath string) string {
+	realPath := sharedPath
+	if output, err := exec.Command(a.adbPath, "-s", deviceId, "shell", "realpath "+shellQuote(sharedPath)).Output(); err == nil {
+		if p := strings.TrimSpace(string(output)); strings.HasPrefix(p, "/") {
+			realPath = p
+		}
+	}
+
+	idRegex := regexp.MustCompile(`_id=(\d+)`)
+	query := func() string {
+		where := "_data='" + strings.ReplaceAll(realPath, "'", "''") + "'"
+		script := "content query --uri content://media/external/file --projection _id --where " + shellQuote(where)
+		output, _ := exec.Command(a.adbPath, "-s", deviceId, "shell", script).Output()
+		if m := idRegex.FindStringSubmatch(string(output)); m != nil {
+			return "content://media/external/file/" + m[1]
+		}
+		return ""
+	}
+
+	if uri := query(); uri != "" {
+		return uri
+	}
+	a.scanMediaFiles(deviceId, []transferFile{{Remote: sharedPath}})
+	time.Sleep(1 * time.Second)
+	return query()
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultFrameBudgetNs is the 60 Hz frame budget used when framestats has no deadline column
+const defaultFrameBudgetNs = 16_666_667
+
+var (
+	// gfxinfoStat matches the summary lines: "Janky frames: 20 (4.00%)", "90th percentile: 13ms"
+	gfxinfoStat      = regexp.MustCompile(`^([A-Za-z0-9 ()]+?):\s+(\d+)(?:ms)?(?:\s+\(([\d.]+)%\))?\s*$`)
+	gfxinfoHistogram = regexp.MustCompile(`(\d+)ms=(\d+)`)
+
+	jankMonitors   = make(map[string]context.CancelFunc)
+	jankMonitorsMu sync.Mutex
+)
+
+// GetFrameStats reads `dumpsys gfxinfo <package> framestats`: the summary with its histogram
+// and, where the device provides them, per-frame timings of the last ~120 frames
+func (a *App) GetFrameStats(deviceId, packageName string) (*FrameStats, error) {
+	return a.readFrameStats(context.Background(), deviceId, packageName)
+}
+
+// ResetFrameStats clears the app's gfxinfo counters so the next read covers only what follows
+func (a *App) ResetFrameStats(deviceId, packageName string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return fmt.Errorf("no package specified")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "dumpsys", "gfxinfo", packageName, "reset").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to reset frame stats: %w, output: %s", err, string(out))
+	}
+	return nil
+}
+
+func (a *App) readFrameStats(ctx context.Context, deviceId, packageName string) (*FrameStats, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return nil, fmt.Errorf("no package specified")
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", "dumpsys", "gfxinfo", packageName, "framestats").Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to read gfxinfo: %w", err)
+	}
+	stats, err := parseGfxinfo(string(out))
+	if err != nil {
+		return nil, err
+	}
+	stats.Package = packageName
+	stats.Timestamp = time.Now().UnixMilli()
+	return stats, nil
+}
+
+// parseGfxinfo reads the summary counters and every ---PROFILEDATA--- block. Releases without
+// framestats simply yield no frames.
+func parseGfxinfo(text string) (*FrameStats, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if msg := strings.TrimSpace(text); strings.HasPrefix(msg, "No process found") {
+		return nil, fmt.Errorf("%s", msg)
+	}
+	if !strings.Contains(text, "Total frames rendered") {
+		return nil, fmt.Errorf("unrecognised gfxinfo output")
+	}
+
+	stats := &FrameStats{Frames: []FrameTiming{}}
+	var header []string
+	inProfile := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---PROFILEDATA---" {
+			inProfile = !inProfile
+			header = nil
+			continue
+		}
+		if inProfile {
+			fields := strings.Split(strings.TrimSuffix(trimmed, ","), ",")
+			if header == nil {
+				header = fields
+				continue
+			}
+			if frame, ok := parseFrameTiming(header, fields); ok {
+				stats.Frames = append(stats.Frames, frame)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "HISTOGRAM:"):
+			for _, m := range gfxinfoHistogram.FindAllStringSubmatch(trimmed, -1) {
+				ms, _ := strconv.Atoi(m[1])
+				count, _ := strconv.Atoi(m[2])
+				if count > 0 {
+					stats.Histogram = append(stats.Histogram, FrameHistogramBucket{Ms: ms, Count: count})
+				}
+			}
+		case strings.HasPrefix(trimmed, "GPU HISTOGRAM:"):
+			// Not reported
+		default:
+			m := gfxinfoStat.FindStringSubmatch(trimmed)
+			if m == nil {
+				continue
+			}
+			v, _ := strconv.Atoi(m[2])
+			switch m[1] {
+			case "Total frames rendered":
+				stats.TotalFrames = v
+			case "Janky frames":
+				stats.JankyFrames = v
+				stats.JankyPercent, _ = strconv.ParseFloat(m[3], 64)
+			case "50th percentile":
+				stats.P50 = v
+			case "90th percentile":
+				stats.P90 = v
+			case "95th percentile":
+				stats.P95 = v
+			case "99th percentile":
+				stats.P99 = v
+			case "Number Missed Vsync":
+				stats.MissedVsync = v
+			case "Number High input latency":
+				stats.HighInputLatency = v
+			case "Number Slow UI thread":
+				stats.SlowUIThread = v
+			case "Number Slow bitmap uploads":
+				stats.SlowBitmapUploads = v
+			case "Number Slow issue draw commands":
+				stats.SlowDrawCommands = v
+			case "Number Frame deadline missed":
+				stats.FrameDeadlineMissed = v
+			}
+		}
+	}
+	stats.FramestatsAvailable = len(stats.Frames) > 0
+	return stats, nil
+}
+
+// parseFrameTiming turns one framestats row into a frame. Rows with non-zero Flags are
+// frames the renderer itself marks as not representative (the first frame of a window,
+// skipped draws) and are dropped.
+func parseFrameTiming(header, fields []string) (FrameTiming, bool) {
+	col := func(name string) int64 {
+		for i, h := range header {
+			if h == name && i < len(fields) {
+				v, _ := strconv.ParseInt(fields[i], 10, 64)
+				return v
+			}
+		}
+		return 0
+	}
+	if col("Flags") != 0 {
+		return FrameTiming{}, false
+	}
+	intended := col("IntendedVsync")
+	completed := col("FrameCompleted")
+	if intended <= 0 || completed <= intended {
+		return FrameTiming{}, false
+	}
+
+	ms := func(from, to int64) float64 {
+		if from <= 0 || to < from {
+			return 0
+		}
+		return roundTenth(float64(to-from) / 1e6)
+	}
+	frame := FrameTiming{
+		IntendedVsync: intended,
+		DurationMs:    ms(intended, completed),
+		InputMs:       ms(col("HandleInputStart"), col("AnimationStart")),
+		AnimationMs:   ms(col("AnimationStart"), col("PerformTraversalsStart")),
+		LayoutMs:      ms(col("PerformTraversalsStart"), col("DrawStart")),
+		DrawMs:        ms(col("DrawStart"), col("SyncQueued")),
+		SyncMs:        ms(col("SyncStart"), col("IssueDrawCommandsStart")),
+		GPUMs:         ms(col("IssueDrawCommandsStart"), completed),
+	}
+	// Android 12 added the deadline the frame had to meet; older releases assume 60 Hz
+	if deadline := col("FrameDeadline"); deadline > 0 {
+		frame.Janky = completed > deadline
+	} else {
+		frame.Janky = completed-intended > defaultFrameBudgetNs
+	}
+	return frame, true
+}
+
+// StartJankMonitor repeatedly resets the app's frame stats, waits intervalSec seconds and
+// emits what was rendered in between as a jank-sample event
+func (a *App) StartJankMonitor(deviceId, packageName string, intervalSec int) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if packageName == "" {
+		return fmt.Errorf("no package specified")
+	}
+	if intervalSec <= 0 {
+		intervalSec = 5
+	}
+
+	a.StopJankMonitor(deviceId, packageName)
+	ctx, cancel := context.WithCancel(context.Background())
+	key := deviceId + "|" + packageName
+	jankMonitorsMu.Lock()
+	jankMonitors[key] = cancel
+	jankMonitorsMu.Unlock()
+
+	go func() {
+		interval := time.Duration(intervalSec) * time.Second
+		for {
+			windowStart := time.Now()
+			resetErr := a.ResetFrameStats(deviceId, packageName)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			payload := map[string]interface{}{
+				"deviceId":    deviceId,
+				"packageName": packageName,
+				"windowStart": windowStart.UnixMilli(),
+				"windowEnd":   time.Now().UnixMilli(),
+			}
+			stats, err := a.readFrameStats(ctx, deviceId, packageName)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil && resetErr != nil {
+				// Without the reset the numbers cover the app's whole lifetime
+				payload["warning"] = resetErr.Error()
+			}
+			if err != nil {
+				payload["error"] = err.Error()
+			} else {
+				payload["stats"] = stats
+			}
+			wailsRuntime.EventsEmit(a.ctx, "jank-sample", payload)
+		}
+	}()
+	return nil
+}
+
+// StopJankMonitor stops the app's jank monitor
+func (a *App) StopJankMonitor(deviceId, packageName string) {
+	key := deviceId + "|" + packageName
+	jankMonitorsMu.Lock()
+	cancel := jankMonitors[key]
+	delete(jankMonitors, key)
+	jankMonitorsMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// stopJankMonitorsForOffline stops monitors whose device is no longer online; with nil it
+// stops them all
+func (a *App) stopJankMonitorsForOffline(devices []Device) {
+	online := onlineDeviceIDs(devices)
+	jankMonitorsMu.Lock()
+	for key, cancel := range jankMonitors {
+		deviceId, _, _ := strings.Cut(key, "|")
+		if !online[deviceId] {
+			cancel()
+			delete(jankMonitors, key)
+		}
+	}
+	jankMonitorsMu.Unlock()
+}
@@ -355,6 +355,8 @@ const DevicesView: React.FC<DevicesViewProps> = ({
             device: { color: "green", icon: <CheckCircleOutlined />, text: t("devices.online") },
             offline: { color: "default", icon: <StopOutlined />, text: t("devices.offline") },
             unauthorized: { color: "red", icon: <CloseCircleOutlined />, text: t("devices.unauthorized") },
+            fastboot: { color: "purple", icon: <ThunderboltOutlined />, text: t("devices.fastboot") },
+            sideload: { color: "gold", icon: <ArrowUpOutlined />, text: t("devices.sideload") },
           }[state] || { color: "red", icon: <CloseCircleOutlined />, text: state };
 
         const formatDuration = (seconds: number) => {
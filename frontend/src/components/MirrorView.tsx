@@ -29,9 +29,10 @@ import {
   SelectRecordPath,
   SelectScreenshotPath,
   TakeScreenshot,
-  ListCameras,
+  ListScrcpyCameras,
   ListDisplays,
   GetDeviceInfo,
+  GetDefaultScrcpyConfig,
 } from "../../wailsjs/go/main/App";
 
 const { Option } = Select;
@@ -81,13 +82,24 @@ const MirrorView: React.FC = () => {
     noClipboardSync: false,
     showFps: false,
     noPowerOn: false,
+    cameraFacing: "",
+    newDisplay: false,
+    newDisplaySize: "",
+    newDisplayDpi: 0,
+    startApp: "",
+    audioSource: "output",
+    audioBitRate: 0,
+    audioBuffer: 0,
+    crop: "",
+    rotation: 0,
+    allowMultiple: false,
   };
 
   // Scrcpy states per device
   const [deviceConfigs, setDeviceConfigs] = useState<Record<string, main.ScrcpyConfig>>({});
   const [deviceShouldRecord, setDeviceShouldRecord] = useState<Record<string, boolean>>({});
-  const [availableCameras, setAvailableCameras] = useState<string[]>([]);
-  const [availableDisplays, setAvailableDisplays] = useState<string[]>([]);
+  const [availableCameras, setAvailableCameras] = useState<main.ScrcpyCamera[]>([]);
+  const [availableDisplays, setAvailableDisplays] = useState<main.ScrcpyDisplay[]>([]);
   const [deviceAndroidVer, setDeviceAndroidVer] = useState<number>(0);
   const fetchingRef = useRef<string | null>(null);
   const lastFetchedDeviceRef = useRef<string>("");
@@ -152,21 +164,34 @@ const MirrorView: React.FC = () => {
     try {
       fetchingRef.current = selectedDevice;
       // Fetch in parallel for better performance and isolation
-      const [info, cameras, displays] = await Promise.all([
+      const [info, cameras, displays, presetConfig] = await Promise.all([
         GetDeviceInfo(selectedDevice).catch(err => {
           console.error("GetDeviceInfo error:", err);
           return null;
         }),
-        ListCameras(selectedDevice).catch(err => {
-          console.error("ListCameras error:", err);
+        ListScrcpyCameras(selectedDevice).catch(err => {
+          console.error("ListScrcpyCameras error:", err);
           return [];
         }),
         ListDisplays(selectedDevice).catch(err => {
           console.error("ListDisplays error:", err);
           return [];
+        }),
+        GetDefaultScrcpyConfig(selectedDevice).catch(err => {
+          console.error("GetDefaultScrcpyConfig error:", err);
+          return null;
         })
       ]);
 
+      // Start from the device's default preset unless the user already tweaked its config
+      if (presetConfig) {
+        const device = selectedDevice;
+        setDeviceConfigs(prev => prev[device] ? prev : {
+          ...prev,
+          [device]: { ...defaultConfig, ...presetConfig },
+        });
+      }
+
       if (info) {
         if (info.androidVer) {
           const ver = parseInt(info.androidVer);
@@ -216,7 +241,8 @@ const MirrorView: React.FC = () => {
   const handleStartScrcpy = async (deviceId: string, overrideConfig?: main.ScrcpyConfig) => {
     try {
       let config = { ...(overrideConfig || currentConfig) };
-      await StartScrcpy(deviceId, config);
+      const session = await StartScrcpy(deviceId, config);
+      (session?.warnings || []).forEach((w: string) => message.warning(w));
 
       if (currentShouldRecord && !currentRecordStatus.isRecording) {
         const device = devices.find((d: Device) => d.id === deviceId);
@@ -258,7 +284,7 @@ const MirrorView: React.FC = () => {
       if (!defaultPath) return;
 
       // The global listener will handle the toasts based on events emitted by TakeScreenshot
-      await TakeScreenshot(selectedDevice, defaultPath);
+      await TakeScreenshot(selectedDevice, defaultPath, false);
       // Immediately refresh to show re-ordered device list
       await fetchDevices();
     } catch (err) {
@@ -297,6 +323,7 @@ const MirrorView: React.FC = () => {
   const updateScrcpyConfig = async (newConfig: main.ScrcpyConfig) => {
     setScrcpyConfig(newConfig);
     if (currentMirrorStatus.isMirroring && selectedDevice) {
+      await StopScrcpy(selectedDevice);
       await handleStartScrcpy(selectedDevice, newConfig);
     }
   };
@@ -1016,11 +1043,9 @@ const MirrorView: React.FC = () => {
                         style={{ width: 120 }}
                       >
                          <Option value="">Default</Option>
-                         {availableCameras.map(cam => {
-                            const idMatch = cam.match(/--camera-id=([^ ]+)/);
-                            const id = idMatch ? idMatch[1] : cam;
-                            return <Option key={id} value={id}>{cam.replace("--camera-id=", "")}</Option>;
-                         })}
+                         {availableCameras.map(cam => (
+                            <Option key={cam.id} value={cam.id}>{`${cam.id} (${cam.facing}, ${cam.size})`}</Option>
+                         ))}
                       </Select>
                     </div>
                     <div className="setting-item">
@@ -1034,10 +1059,10 @@ const MirrorView: React.FC = () => {
                         style={{ width: 120 }}
                       >
                          <Option value="">Default</Option>
-                         <Option value="1920x1080">1920x1080</Option>
-                         <Option value="1280x720">1280x720</Option>
-                         <Option value="1024x768">1024x768</Option>
-                         <Option value="640x480">640x480</Option>
+                         {(availableCameras.find(cam => cam.id === currentConfig.cameraId)?.sizes
+                           ?? ["1920x1080", "1280x720", "1024x768", "640x480"]).map(size => (
+                            <Option key={size} value={size}>{size}</Option>
+                         ))}
                       </Select>
                     </div>
                    </>
@@ -1055,12 +1080,9 @@ const MirrorView: React.FC = () => {
                       style={{ width: 120 }}
                     >
                       <Option value={0}>0 (Main)</Option>
-                      {availableDisplays.map(disp => {
-                         const idMatch = disp.match(/--display-id=([^ ]+)/);
-                         const id = idMatch ? parseInt(idMatch[1]) : 0;
-                         if (id === 0) return null;
-                         return <Option key={id} value={id}>{disp.replace("--display-id=", "")}</Option>;
-                      }).filter(Boolean)}
+                      {availableDisplays.filter(disp => disp.id !== 0).map(disp => (
+                         <Option key={disp.id} value={disp.id}>{`${disp.id} (${disp.size})`}</Option>
+                      ))}
                     </Select>
                   </div>
                 )}
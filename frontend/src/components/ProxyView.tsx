@@ -4,7 +4,7 @@ import { PoweroffOutlined, PlayCircleOutlined, DeleteOutlined, SettingOutlined,
 import DeviceSelector from './DeviceSelector';
 import { useDeviceStore } from '../stores';
 // @ts-ignore
-import { StartProxy, StopProxy, GetProxyStatus, GetLocalIP, RunAdbCommand, StartNetworkMonitor, StopNetworkMonitor, SetProxyLimit, SetProxyWSEnabled, SetProxyMITM, InstallProxyCert, SetProxyLatency, SetMITMBypassPatterns } from '../../wailsjs/go/main/App';
+import { StartProxy, StopProxy, GetProxyStatus, GetLocalIP, SetGlobalProxy, ClearGlobalProxy, StartNetworkMonitor, StopNetworkMonitor, SetProxyLimit, SetProxyWSEnabled, SetProxyMITM, InstallProxyCert, SetProxyLatency, SetMITMBypassPatterns } from '../../wailsjs/go/main/App';
 // @ts-ignore
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import { useVirtualizer } from '@tanstack/react-virtual';
@@ -140,9 +140,11 @@ const ProxyView: React.FC = () => {
             // 2. Automagically set device proxy if selected
             if (selectedDevice && localIP) {
                 try {
-                    const cmd = `shell settings put global http_proxy ${localIP}:${port}`;
-                    await RunAdbCommand(selectedDevice, cmd);
+                    const applied = await SetGlobalProxy(selectedDevice, localIP, port);
                     message.success(t('proxy.start_success', { ip: localIP, port }));
+                    if (applied?.warning) {
+                        message.warning(applied.warning);
+                    }
                 } catch (adbErr: any) {
                     const errorStr = String(adbErr);
                     if (errorStr.includes("WRITE_SECURE_SETTINGS")) {
@@ -165,7 +167,7 @@ const ProxyView: React.FC = () => {
             // 1. Automagically clear device proxy
             if (selectedDevice) {
                 try {
-                    await RunAdbCommand(selectedDevice, "shell settings put global http_proxy :0");
+                    await ClearGlobalProxy(selectedDevice);
                 } catch (e) { }
             }
 
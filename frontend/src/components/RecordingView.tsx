@@ -434,16 +434,32 @@ const RecordingView: React.FC = () => {
     if (event.selector && event.selector.value) {
       elementSuffix = ` [${event.selector.type}: ${event.selector.value}]`;
     }
+    const elementLabel = event.elementText || event.elementId;
+    if (elementLabel && elementLabel !== event.selector?.value) {
+      elementSuffix += ` "${elementLabel}"`;
+    }
 
     switch (event.type) {
       case "tap":
         return `${index + 1}. tap (${event.x}, ${event.y})${elementSuffix} @ ${event.timestamp}ms`;
+      case "double_tap":
+        return `${index + 1}. double tap (${event.x}, ${event.y})${elementSuffix} @ ${event.timestamp}ms`;
       case "long_press":
         return `${index + 1}. long press (${event.x}, ${event.y})${elementSuffix} ${event.duration}ms @ ${event.timestamp}ms`;
       case "swipe":
         return `${index + 1}. swipe (${event.x}, ${event.y}) → (${event.x2}, ${event.y2})${elementSuffix} ${event.duration}ms @ ${event.timestamp}ms`;
+      case "pinch":
+        return `${index + 1}. pinch ${(event.fingers || []).length} fingers ${event.duration}ms @ ${event.timestamp}ms`;
+      case "key":
+        return `${index + 1}. key ${event.keyCode}${event.duration ? ` (long ${event.duration}ms)` : ''} @ ${event.timestamp}ms`;
+      case "text":
+        return `${index + 1}. type "${event.text}" @ ${event.timestamp}ms`;
       case "wait":
         return `${index + 1}. wait ${event.duration}ms`;
+      case "waitForElement":
+        return `${index + 1}. wait until${event.gone ? ' gone' : ''}${elementSuffix} (timeout ${event.duration || 10000}ms) @ ${event.timestamp}ms`;
+      case "scrollTo":
+        return `${index + 1}. scroll ${event.direction || 'up'} until${elementSuffix} (max ${event.maxSwipes || 10} swipes) @ ${event.timestamp}ms`;
       default:
         return `${index + 1}. unknown`;
     }
@@ -1,11 +1,12 @@
-import React, { useState } from "react";
-import { Button, Space, Input, message, theme } from "antd";
-import { ClearOutlined } from "@ant-design/icons";
+import React, { useEffect, useState } from "react";
+import { Button, Space, Input, Modal, Tag, message, theme } from "antd";
+import { ClearOutlined, StarOutlined } from "@ant-design/icons";
 import { useTranslation } from "react-i18next";
 import DeviceSelector from "./DeviceSelector";
 import { useDeviceStore } from "../stores";
 // @ts-ignore
-import { RunAdbCommand } from "../../wailsjs/go/main/App";
+import { RunShellCommand, CheckDangerousCommand, GetShellHistory, ListShellSnippets, SaveShellSnippet, DeleteShellSnippet } from "../../wailsjs/go/main/App";
+import { main } from "../../wailsjs/go/models";
 
 const ShellView: React.FC = () => {
   const { t } = useTranslation();
@@ -15,6 +16,54 @@ const ShellView: React.FC = () => {
   const [shellOutput, setShellOutput] = useState("");
   const [history, setHistory] = useState<string[]>([]);
   const [historyIndex, setHistoryIndex] = useState(-1);
+  const [snippets, setSnippets] = useState<main.ShellSnippet[]>([]);
+
+  useEffect(() => {
+    ListShellSnippets().then((list: main.ShellSnippet[]) => setSnippets(list || []));
+  }, []);
+
+  useEffect(() => {
+    if (!selectedDevice) return;
+    setHistoryIndex(-1);
+    GetShellHistory(selectedDevice, 50)
+      .then((entries: main.ShellHistoryEntry[]) => setHistory((entries || []).map((e) => e.command)))
+      .catch(() => setHistory([]));
+  }, [selectedDevice]);
+
+  const handleSaveSnippet = () => {
+    const cmd = shellCmd.trim();
+    if (!cmd) return;
+    let name = "";
+    Modal.confirm({
+      title: t("shell.save_snippet"),
+      okText: t("common.ok"),
+      cancelText: t("common.cancel"),
+      autoFocusButton: null,
+      content: (
+        <Input
+          autoFocus
+          placeholder={t("shell.snippet_name")}
+          onChange={(e) => (name = e.target.value)}
+        />
+      ),
+      onOk: async () => {
+        if (!name.trim()) return;
+        try {
+          await SaveShellSnippet(name, cmd);
+          setSnippets(await ListShellSnippets());
+          message.success(t("shell.snippet_saved"));
+        } catch (err) {
+          message.error(String(err));
+          throw err;
+        }
+      },
+    });
+  };
+
+  const handleDeleteSnippet = async (name: string) => {
+    await DeleteShellSnippet(name);
+    setSnippets(await ListShellSnippets());
+  };
 
   const presets = [
     { label: t("shell.presets.current_activity"), cmd: "shell dumpsys window | grep mCurrentFocus" },
@@ -35,13 +84,29 @@ const ShellView: React.FC = () => {
     });
     setHistoryIndex(-1);
 
-    try {
-      const res = await RunAdbCommand(selectedDevice, cmdToRun.trim());
-      setShellOutput(res);
-    } catch (err) {
-      message.error(t("app.command_failed"));
-      setShellOutput(String(err));
+    const run = async (token: string) => {
+      try {
+        const res = await RunShellCommand(selectedDevice, cmdToRun.trim(), token);
+        setShellOutput(res);
+      } catch (err) {
+        message.error(t("app.command_failed"));
+        setShellOutput(String(err));
+      }
+    };
+
+    const check = await CheckDangerousCommand(cmdToRun.trim());
+    if (!check.dangerous) {
+      await run("");
+      return;
     }
+    Modal.confirm({
+      title: t("shell.dangerous_title"),
+      content: t("shell.dangerous_content", { command: cmdToRun.trim(), rule: check.description }),
+      okText: t("shell.run_anyway"),
+      okButtonProps: { danger: true },
+      cancelText: t("common.cancel"),
+      onOk: () => run(check.token || ""),
+    });
   };
 
   const handleKeyDown = (e: React.KeyboardEvent) => {
@@ -108,6 +173,29 @@ const ShellView: React.FC = () => {
         ))}
       </div>
 
+      {snippets.length > 0 && (
+        <div style={{ marginBottom: 12, display: "flex", flexWrap: "wrap", gap: 8 }}>
+          {snippets.map((s) => (
+            <Tag
+              key={s.name}
+              closable
+              title={s.command}
+              style={{ cursor: "pointer" }}
+              onClick={() => {
+                setShellCmd(s.command);
+                handleShellCommand(s.command);
+              }}
+              onClose={(e) => {
+                e.preventDefault();
+                handleDeleteSnippet(s.name);
+              }}
+            >
+              {s.name}
+            </Tag>
+          ))}
+        </div>
+      )}
+
       <Space.Compact style={{ width: "100%", marginBottom: 16 }}>
         <Input
           placeholder={t("shell.placeholder")}
@@ -117,6 +205,7 @@ const ShellView: React.FC = () => {
           onKeyDown={handleKeyDown}
           autoFocus
         />
+        <Button icon={<StarOutlined />} title={t("shell.save_snippet")} onClick={handleSaveSnippet} />
         <Button type="primary" onClick={() => handleShellCommand()}>
           {t("shell.run")}
         </Button>
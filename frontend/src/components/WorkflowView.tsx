@@ -49,6 +49,8 @@ import {
   LockOutlined,
   ForkOutlined,
   PartitionOutlined,
+  FileTextOutlined,
+  ExportOutlined,
   PicCenterOutlined,
   PicRightOutlined,
   LoadingOutlined,
@@ -101,6 +103,8 @@ const STEP_TYPES = {
     { key: 'branch', icon: <ForkOutlined />, color: 'purple' },
     { key: 'scroll_to', icon: <ReloadOutlined />, color: 'magenta' },
     { key: 'assert_element', icon: <CheckCircleOutlined />, color: 'lime' },
+    { key: 'assert_activity', icon: <CheckCircleOutlined />, color: 'lime' },
+    { key: 'wait_notification', icon: <CheckCircleOutlined />, color: 'lime' },
     { key: 'set_variable', icon: <IdcardOutlined />, color: 'orange' },
   ],
   SCRIPT_ACTIONS: [
@@ -158,8 +162,16 @@ interface WorkflowStep {
   preWait?: number; // Delay before execution in ms
   swipeDistance?: number; // Distance for swipe actions
   swipeDuration?: number; // Duration for swipe actions in ms
-  conditionType?: string; // Condition type for branch steps: 'exists', 'not_exists', 'text_equals', 'text_contains', 'variable_equals'
+  container?: ElementSelector; // List to swipe in for scroll_to
+  maxSwipes?: number; // Swipe limit for scroll_to
+  conditionType?: string; // Condition type for branch steps: 'exists', 'not_exists', 'text_equals', 'text_contains', 'variable_equals', 'shell_success', 'activity_is'
+  then?: WorkflowStep[]; // if: steps run when the condition holds
+  else?: WorkflowStep[]; // if: steps run otherwise
+  body?: WorkflowStep[]; // repeat: steps run each iteration
+  times?: number; // repeat: fixed iteration count
+  maxIterations?: number; // repeat: guard for while-condition loops
   onError?: 'stop' | 'continue'; // Error handling strategy
+  retries?: number; // Extra attempts after a failure
 
   // Flow connections (next step IDs)
   nextStepId?: string;
@@ -184,6 +196,7 @@ interface WorkflowStep {
 }
 
 interface Workflow {
+  version?: number; // Schema version, set by the backend on save
   id: string;
   name: string;
   description?: string;
@@ -362,6 +375,7 @@ const WorkflowView: React.FC = () => {
 
   // Execution state
   const [isRunning, setIsRunning] = useState(false);
+  const [lastRunId, setLastRunId] = useState<string | null>(null);
   const [runningWorkflowIds, setRunningWorkflowIds] = useState<string[]>([]);
   const [isPaused, setIsPaused] = useState(false);
   const [currentStepId, setCurrentStepId] = useState<string | null>(null);
@@ -850,6 +864,8 @@ const WorkflowView: React.FC = () => {
       ...step,
       selectorType: step.selector?.type,
       selectorValue: step.selector?.value,
+      containerType: step.container?.type || 'id',
+      containerValue: step.container?.value,
       type: step.type,
       conditionType: step.conditionType || 'exists', // Default to 'exists' for branch nodes
     });
@@ -1097,11 +1113,17 @@ const WorkflowView: React.FC = () => {
           value: values.value !== undefined ? String(values.value) : undefined,
           timeout: values.timeout,
           onError: values.onError,
+          retries: values.retries || undefined,
           loop: values.loop,
           preWait: Number(values.preWait || 0),
           postDelay: Number(values.postDelay || 0),
           swipeDistance: values.swipeDistance,
           swipeDuration: values.swipeDuration,
+          container: values.type === 'scroll_to' && values.containerValue ? {
+            type: values.containerType || 'id',
+            value: values.containerValue,
+          } : undefined,
+          maxSwipes: values.type === 'scroll_to' ? values.maxSwipes : undefined,
           conditionType: values.conditionType,
           nextStepId: (node.data.step as WorkflowStep).nextStepId,
           trueStepId: (node.data.step as WorkflowStep).trueStepId,
@@ -1245,6 +1267,7 @@ const WorkflowView: React.FC = () => {
         runtime.EventsOff("workflow-error", onError);
         runtime.EventsOff("workflow-step-running", onStep);
         runtime.EventsOff("workflow-step-waiting", onWait);
+        runtime.EventsOff("workflow-step-finished", onStepFinished);
         runtime.EventsOff("task-paused", onPaused);
         runtime.EventsOff("task-resumed", onResumed);
       };
@@ -1259,6 +1282,9 @@ const WorkflowView: React.FC = () => {
       const onComplete = (data: any) => {
         if (data.deviceId === deviceObj.id) {
           setRunningWorkflowIds(prev => prev.filter(id => id !== data.workflowId));
+          if (data.runId && data.workflowId === selectedWorkflow.id) {
+            setLastRunId(data.runId);
+          }
 
           // Clean up step map for this workflow
           setWorkflowStepMap(prev => {
@@ -1336,6 +1362,13 @@ const WorkflowView: React.FC = () => {
         }
       };
 
+      const onStepFinished = (data: any) => {
+        if (data.deviceId === deviceObj.id && data.status === 'failed') {
+          const name = data.stepName || t(`workflow.step_type.${data.stepType}`);
+          setExecutionLogs(prev => [...prev, `[${new Date().toLocaleTimeString()}] ${t("workflow.step_failed", { name, attempts: data.attempts })}: ${data.error}`]);
+        }
+      };
+
       const onPaused = (data: any) => {
         if (data.deviceId === deviceObj.id) {
           setIsPaused(true);
@@ -1353,6 +1386,7 @@ const WorkflowView: React.FC = () => {
       runtime.EventsOn("workflow-error", onError);
       runtime.EventsOn("workflow-step-running", onStep);
       runtime.EventsOn("workflow-step-waiting", onWait);
+      runtime.EventsOn("workflow-step-finished", onStepFinished);
       runtime.EventsOn("task-paused", onPaused);
       runtime.EventsOn("task-resumed", onResumed);
     });
@@ -1360,7 +1394,7 @@ const WorkflowView: React.FC = () => {
     try {
       setIsPaused(false); // Reset pause state when starting
       setWorkflowStepMap({}); // Clear all workflow step tracking
-      await (window as any).go.main.App.RunWorkflow(deviceObj, sanitizedWorkflow);
+      await (window as any).go.main.App.RunWorkflow(deviceObj, sanitizedWorkflow, {});
       await executionPromise;
       setExecutionLogs(prev => [...prev, `[${new Date().toLocaleTimeString()}] ${t("workflow.completed")}`]);
     } catch (err) {
@@ -1375,6 +1409,52 @@ const WorkflowView: React.FC = () => {
     }
   };
 
+  const handleRunWithDataset = async () => {
+    if (!selectedDevice || !selectedWorkflow) {
+      message.warning(t("app.select_device"));
+      return;
+    }
+    const csvPath = await (window as any).go.main.App.SelectWorkflowDataset();
+    if (!csvPath) return;
+
+    const workflowToRun = await handleSaveGraph(true);
+    if (!workflowToRun) return;
+
+    setIsRunning(true);
+    setExecutionLogs([`[${new Date().toLocaleTimeString()}] ${t("workflow.dataset_started", { name: workflowToRun.name })}`]);
+    try {
+      const result = await (window as any).go.main.App.RunWorkflowWithDataset(selectedDevice, workflowToRun.id, csvPath);
+      const lastRow = result.rows?.[result.rows.length - 1];
+      if (lastRow?.runId) setLastRunId(lastRow.runId);
+      const rowLogs = (result.rows || []).map((row: any) =>
+        `[${t("workflow.dataset_row", { row: row.row })}] ${row.passed ? t("workflow.completed") : `${t("workflow.error")}: ${row.error}`}`
+      );
+      setExecutionLogs(prev => [...prev, ...rowLogs, t("workflow.dataset_summary", { passed: result.passed, failed: result.failed })]);
+      if (result.failed > 0) {
+        message.warning(t("workflow.dataset_summary", { passed: result.passed, failed: result.failed }));
+      } else {
+        message.success(t("workflow.dataset_summary", { passed: result.passed, failed: result.failed }));
+      }
+    } catch (err) {
+      setExecutionLogs(prev => [...prev, `[${new Date().toLocaleTimeString()}] ${t("workflow.error")}: ${err}`]);
+      message.error(String(err));
+    } finally {
+      setIsRunning(false);
+      setCurrentStepId(null);
+      setWorkflowStepMap({});
+    }
+  };
+
+  const handleExportReport = async () => {
+    if (!lastRunId) return;
+    try {
+      const path = await (window as any).go.main.App.ExportWorkflowRunHTML(lastRunId, "");
+      message.success(t("workflow.report_exported", { path }));
+    } catch (err) {
+      message.error(String(err));
+    }
+  };
+
   const handleOpenVariablesModal = () => {
     if (!selectedWorkflow) return;
     const vars = selectedWorkflow.variables || {};
@@ -1544,9 +1624,19 @@ const WorkflowView: React.FC = () => {
                   </Button>
                 </Space>
               ) : (
-                <Button type="primary" icon={<PlayCircleOutlined />} onClick={handleRunWorkflow} disabled={!selectedDevice}>
-                  {t("workflow.run")}
-                </Button>
+                <Space>
+                  {lastRunId && (
+                    <Button icon={<ExportOutlined />} onClick={handleExportReport}>
+                      {t("workflow.export_report")}
+                    </Button>
+                  )}
+                  <Button icon={<FileTextOutlined />} onClick={handleRunWithDataset} disabled={!selectedDevice}>
+                    {t("workflow.run_dataset")}
+                  </Button>
+                  <Button type="primary" icon={<PlayCircleOutlined />} onClick={handleRunWorkflow} disabled={!selectedDevice}>
+                    {t("workflow.run")}
+                  </Button>
+                </Space>
               )}
             </>
           )}
@@ -1911,14 +2001,14 @@ const WorkflowView: React.FC = () => {
                         const type = getFieldValue('type');
                         const isBranch = type === 'branch';
                         const conditionType = getFieldValue('conditionType') || 'exists';
-                        const needsSelector = ['click_element', 'long_click_element', 'input_text', 'swipe_element', 'wait_element', 'wait_gone', 'assert_element', 'branch'].includes(type);
+                        const needsSelector = ['click_element', 'long_click_element', 'input_text', 'swipe_element', 'scroll_to', 'wait_element', 'wait_gone', 'assert_element', 'branch'].includes(type);
                         const isAppAction = ['launch_app', 'stop_app', 'clear_app', 'open_settings'].includes(type);
-                        const needsValue = ['set_variable', 'input_text', 'swipe_element', 'wait', 'adb', 'script', 'run_workflow'].includes(type) || isAppAction;
+                        const needsValue = ['set_variable', 'input_text', 'swipe_element', 'scroll_to', 'assert_element', 'assert_activity', 'wait_notification', 'wait', 'adb', 'script', 'run_workflow'].includes(type) || isAppAction;
                         const isWorkflow = type === 'run_workflow';
 
                         // For branch conditions, determine if we need value field
-                        const branchNeedsValue = isBranch && ['text_equals', 'text_contains', 'variable_equals'].includes(conditionType);
-                        const branchNeedsSelector = isBranch && conditionType !== 'variable_equals';
+                        const branchNeedsValue = isBranch && ['text_equals', 'text_contains', 'variable_equals', 'shell_success', 'activity_is'].includes(conditionType);
+                        const branchNeedsSelector = isBranch && !['variable_equals', 'shell_success', 'activity_is'].includes(conditionType);
                         const actualNeedsSelector = isBranch ? branchNeedsSelector : needsSelector;
 
                         return (
@@ -1991,6 +2081,8 @@ const WorkflowView: React.FC = () => {
                                     { label: t("workflow.condition.text_equals"), value: "text_equals" },
                                     { label: t("workflow.condition.text_contains"), value: "text_contains" },
                                     { label: t("workflow.condition.variable_equals"), value: "variable_equals" },
+                                    { label: t("workflow.condition.shell_success"), value: "shell_success" },
+                                    { label: t("workflow.condition.activity_is"), value: "activity_is" },
                                   ]}
                                 />
                               </Form.Item>
@@ -2010,9 +2102,12 @@ const WorkflowView: React.FC = () => {
 
                             {(needsValue || branchNeedsValue) && (
                               <Form.Item name="value" label={
-                                isBranch && ['text_equals', 'text_contains'].includes(conditionType) ? t("workflow.expected_text") :
+                                (isBranch && ['text_equals', 'text_contains'].includes(conditionType)) || type === 'assert_element' ? t("workflow.expected_text") :
                                   isBranch && conditionType === 'variable_equals' ? t("workflow.expected_value") :
-                                    type === 'swipe_element' ? t("workflow.swipe_direction") :
+                                  isBranch && conditionType === 'shell_success' ? t("workflow.shell_command") :
+                                  (isBranch && conditionType === 'activity_is') || type === 'assert_activity' ? t("workflow.expected_activity") :
+                                  type === 'wait_notification' ? t("workflow.notification_package") :
+                                    type === 'swipe_element' || type === 'scroll_to' ? t("workflow.swipe_direction") :
                                       type === 'set_variable' ? t("workflow.variable_value") :
                                         t("workflow.value")
                               }>
@@ -2023,7 +2118,7 @@ const WorkflowView: React.FC = () => {
                                   />
                                 ) : type === 'wait' ? (
                                   <InputNumber addonAfter="ms" min={100} step={100} style={{ width: '100%' }} />
-                                ) : type === 'swipe_element' ? (
+                                ) : type === 'swipe_element' || type === 'scroll_to' ? (
                                   <Select options={[
                                     { label: t("workflow.direction_up"), value: 'up' },
                                     { label: t("workflow.direction_down"), value: 'down' },
@@ -2072,6 +2167,29 @@ const WorkflowView: React.FC = () => {
                               </Form.Item>
                             )}
 
+                            {type === 'scroll_to' && (
+                              <>
+                                <Form.Item label={t("workflow.scroll_container")} tooltip={t("workflow.scroll_container_tip")}>
+                                  <div style={{ display: 'flex', gap: 8 }}>
+                                    <Form.Item name="containerType" noStyle>
+                                      <Select style={{ width: 140 }} options={[
+                                        { label: 'Resource ID', value: 'id' },
+                                        { label: 'Class', value: 'class' },
+                                        { label: 'XPath', value: 'xpath' },
+                                        { label: 'Content Desc', value: 'description' },
+                                      ]} />
+                                    </Form.Item>
+                                    <Form.Item name="containerValue" noStyle>
+                                      <Input style={{ flex: 1 }} allowClear />
+                                    </Form.Item>
+                                  </div>
+                                </Form.Item>
+                                <Form.Item name="maxSwipes" label={t("workflow.max_swipes")}>
+                                  <InputNumber min={1} max={100} style={{ width: '100%' }} placeholder="10" />
+                                </Form.Item>
+                              </>
+                            )}
+
                             {type === 'swipe_element' && (
                               <div style={{ display: 'flex', gap: 16 }}>
                                 <Form.Item name="swipeDistance" label={t("workflow.distance")} style={{ flex: 1 }}>
@@ -2098,6 +2216,9 @@ const WorkflowView: React.FC = () => {
                       <Form.Item name="loop" label={t("workflow.loop")} style={{ width: 100 }}>
                         <InputNumber min={1} style={{ width: '100%' }} placeholder="1" />
                       </Form.Item>
+                      <Form.Item name="retries" label={t("workflow.retries")} style={{ width: 100 }}>
+                        <InputNumber min={0} max={10} style={{ width: '100%' }} placeholder="0" />
+                      </Form.Item>
                     </div>
 
                     <div style={{ display: 'flex', gap: 16 }}>
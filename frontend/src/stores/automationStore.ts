@@ -75,7 +75,7 @@ interface AutomationState {
   currentScript: main.TouchScript | null;
   scripts: main.TouchScript[];
   tasks: ScriptTask[];
-  playbackProgress: { current: number; total: number } | null;
+  playbackProgress: { current: number; total: number; iteration?: number; iterations?: number } | null;
   taskProgress: { 
     stepIndex: number; 
     totalSteps: number; 
@@ -100,12 +100,12 @@ interface AutomationState {
   isAnalyzing: boolean;
 
   // Actions
-  startRecording: (deviceId: string, mode?: 'fast' | 'precise') => Promise<void>;
+  startRecording: (deviceId: string, mode?: 'fast' | 'precise', options?: Partial<main.RecordingOptions>) => Promise<void>;
   stopRecording: () => Promise<main.TouchScript | null>;
-  playScript: (deviceId: string, script: main.TouchScript) => Promise<void>;
+  playScript: (deviceId: string, script: main.TouchScript, options?: Partial<main.PlaybackOptions>) => Promise<void>;
   stopPlayback: () => void;
   loadScripts: () => Promise<void>;
-  saveScript: (script: main.TouchScript) => Promise<void>;
+  saveScript: (script: main.TouchScript, overwrite?: boolean) => Promise<void>;
   deleteScript: (name: string) => Promise<void>;
   deleteScripts: (names: string[]) => Promise<void>;
   renameScript: (oldName: string, newName: string) => Promise<void>;
@@ -161,9 +161,11 @@ export const useAutomationStore = create<AutomationState>((set, get) => ({
   isAnalyzing: false,
 
   // Actions
-  startRecording: async (deviceId: string, mode: 'fast' | 'precise' = 'fast') => {
+  startRecording: async (deviceId: string, mode: 'fast' | 'precise' = 'fast', options?: Partial<main.RecordingOptions>) => {
     try {
-      await StartTouchRecording(deviceId, mode);
+      await StartTouchRecording(deviceId, mode, main.RecordingOptions.createFrom({
+        ignoreZones: [], minStrokeMs: 0, discardShortStrokes: false, shortStrokeMs: 0, shortStrokePx: 0, disableSelectorCapture: false, showTouches: false, ...options,
+      }));
       set({
         isRecording: true,
         recordingDeviceId: deviceId,
@@ -186,7 +188,7 @@ export const useAutomationStore = create<AutomationState>((set, get) => ({
     if (!recordingDeviceId) return null;
 
     try {
-      const script = await StopTouchRecording(recordingDeviceId);
+      const script = await StopTouchRecording(recordingDeviceId, { longPressMs: 0, tapMaxDistance: 0, doubleTapMs: 0, doubleTapDistance: 0 });
       set({
         isRecording: false,
         recordingDeviceId: null,
@@ -209,9 +211,9 @@ export const useAutomationStore = create<AutomationState>((set, get) => ({
     }
   },
 
-  playScript: async (deviceId: string, script: main.TouchScript) => {
+  playScript: async (deviceId: string, script: main.TouchScript, options?: Partial<main.PlaybackOptions>) => {
     try {
-      await PlayTouchScript(deviceId, script);
+      await PlayTouchScript(deviceId, script, { repeat: 0, intervalMs: 0, maxDuration: 0, speed: 1, stepMode: false, disableAnimations: false, forceOrientation: false, ...options });
       set({
         isPlaying: true,
         playingDeviceId: deviceId,
@@ -245,9 +247,9 @@ export const useAutomationStore = create<AutomationState>((set, get) => ({
     }
   },
 
-  saveScript: async (script: main.TouchScript) => {
+  saveScript: async (script: main.TouchScript, overwrite = false) => {
     try {
-      await SaveTouchScript(script);
+      await SaveTouchScript(script, overwrite);
       await get().loadScripts();
     } catch (err) {
       console.error('Failed to save script:', err);
@@ -486,7 +488,7 @@ export const useAutomationStore = create<AutomationState>((set, get) => ({
 
     const handlePlaybackProgress = (data: any) => {
       set({
-        playbackProgress: { current: data.current, total: data.total },
+        playbackProgress: { current: data.current, total: data.total, iteration: data.iteration, iterations: data.iterations },
       });
     };
 
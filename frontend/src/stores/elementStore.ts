@@ -26,6 +26,7 @@ export interface UINode {
   longClickable: string;
   password: string;
   selected: string;
+  index?: string;
   nodes: UINode[];
 }
 
@@ -33,6 +34,8 @@ export interface ElementSelector {
   type: 'text' | 'id' | 'desc' | 'class' | 'xpath' | 'bounds' | 'contains' | 'coordinates' | 'advanced';
   value: string;
   index?: number;
+  within?: ElementSelector;
+  childIndex?: number;
 }
 
 export interface SelectorSuggestion {
@@ -40,6 +43,36 @@ export interface SelectorSuggestion {
   value: string;
   priority: number;
   description: string;
+  selector?: ElementSelector;
+}
+
+export interface SelectorMatch {
+  index: number;
+  bounds: string;
+  centerX: number;
+  centerY: number;
+  text: string;
+  resourceId: string;
+  class: string;
+  contentDesc: string;
+}
+
+export interface SelectorTestResult {
+  count: number;
+  unique: boolean;
+  elapsedMs: number;
+  matches: SelectorMatch[];
+  suggestions: SelectorSuggestion[];
+}
+
+export interface NodePathStep {
+  class: string;
+  index: number;
+  resourceId?: string;
+  text?: string;
+  contentDesc?: string;
+  bounds: string;
+  label: string;
 }
 
 export interface ElementInfo {
@@ -342,6 +375,7 @@ interface ElementState {
   // Shared UI hierarchy
   hierarchy: UINode | null;
   rawXml: string | null;
+  generation: number | null;
   isLoading: boolean;
   lastFetchTime: number | null;
   lastFetchDeviceId: string | null;
@@ -368,6 +402,8 @@ interface ElementState {
   inputText: (deviceId: string, selector: ElementSelector, text: string) => Promise<void>;
   waitForElement: (deviceId: string, selector: ElementSelector, timeout?: number) => Promise<void>;
   getElementProperties: (deviceId: string, selector: ElementSelector) => Promise<Record<string, any>>;
+  testSelector: (deviceId: string, selector: ElementSelector) => Promise<SelectorTestResult>;
+  getNodePath: (node: UINode) => Promise<NodePathStep[]>;
 
   // Event subscription
   subscribeToEvents: () => () => void;
@@ -377,6 +413,7 @@ export const useElementStore = create<ElementState>((set, get) => ({
   // Initial state
   hierarchy: null,
   rawXml: null,
+  generation: null,
   isLoading: false,
   lastFetchTime: null,
   lastFetchDeviceId: null,
@@ -387,7 +424,7 @@ export const useElementStore = create<ElementState>((set, get) => ({
 
   // Actions
   fetchHierarchy: async (deviceId: string, force = false) => {
-    const { lastFetchTime, lastFetchDeviceId, rawXml } = get();
+    const { lastFetchTime, lastFetchDeviceId, generation } = get();
 
     // Cache check: skip if same device and fetched within 2 seconds
     const now = Date.now();
@@ -397,13 +434,14 @@ export const useElementStore = create<ElementState>((set, get) => ({
 
     set({ isLoading: true });
     try {
-      const result = await (window as any).go.main.App.GetUIHierarchy(deviceId);
+      const result = await (window as any).go.main.App.GetUIHierarchy(deviceId, force);
 
-      // Only update if content changed
-      if (result.rawXml !== rawXml) {
+      // Only update if content changed; the backend bumps generation when the dump differs
+      if (lastFetchDeviceId !== deviceId || result.generation !== generation) {
         set({
           hierarchy: result.root,
           rawXml: result.rawXml,
+          generation: result.generation,
           lastFetchTime: now,
           lastFetchDeviceId: deviceId,
           isLoading: false,
@@ -423,6 +461,7 @@ export const useElementStore = create<ElementState>((set, get) => ({
     set({
       hierarchy: null,
       rawXml: null,
+      generation: null,
       selectedNode: null,
       highlightedNode: null,
       lastFetchTime: null,
@@ -493,10 +532,10 @@ export const useElementStore = create<ElementState>((set, get) => ({
 
   waitForElement: async (deviceId: string, selector: ElementSelector, timeout = 10000) => {
     await (window as any).go.main.App.WaitForElement(
-      { Cancelled: false } as any,
       deviceId,
       selector,
-      timeout
+      timeout,
+      0 // default poll interval
     );
   },
 
@@ -504,6 +543,16 @@ export const useElementStore = create<ElementState>((set, get) => ({
     return await (window as any).go.main.App.GetElementProperties(deviceId, selector);
   },
 
+  testSelector: async (deviceId: string, selector: ElementSelector) => {
+    return await (window as any).go.main.App.TestSelector(deviceId, selector);
+  },
+
+  getNodePath: async (node: UINode) => {
+    const { hierarchy } = get();
+    if (!hierarchy) return [];
+    return await (window as any).go.main.App.GetNodePath(hierarchy, node);
+  },
+
   // Event subscription
   subscribeToEvents: () => {
     // Listen for UI hierarchy updates from other sources
@@ -512,6 +561,7 @@ export const useElementStore = create<ElementState>((set, get) => ({
         set({
           hierarchy: data.root,
           rawXml: data.rawXml,
+          generation: data.generation ?? null,
           lastFetchTime: Date.now(),
           lastFetchDeviceId: data.deviceId,
         });
@@ -1,5 +1,6 @@
 import { create } from 'zustand';
-import { StartLogcat, StopLogcat } from '../../wailsjs/go/main/App';
+import { CreateLogcatSession, StartLogcatSession, StopLogcat, UpdateLogcatFilter } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
 
 // @ts-ignore
 const EventsOn = (window as any).runtime.EventsOn;
@@ -23,6 +24,7 @@ interface LogcatState {
   // Internal buffer (replaces useRef)
   logBuffer: string[];
   flushTimerId: number | null;
+  sessionId: string;
 
   // Actions
   setLogs: (logs: string[] | ((prev: string[]) => string[])) => void;
@@ -45,6 +47,29 @@ interface LogcatState {
 
 const MAX_LOGS = 200000;
 
+const buildLogcatFilter = (state: LogcatState): main.LogcatFilter => ({
+  minPriority: '',
+  tags: [],
+  excludeTags: [],
+  messageRegex: '',
+  include: state.preFilter.trim(),
+  includeRegex: state.preUseRegex,
+  exclude: state.excludeFilter.trim(),
+  excludeRegex: state.excludeUseRegex,
+});
+
+// Push filter changes to the running session without restarting it
+const syncFilter = (state: LogcatState) => {
+  if (state.isLogging && state.sessionId) {
+    UpdateLogcatFilter(state.sessionId, buildLogcatFilter(state)).catch(() => {});
+  }
+};
+
+const formatLogcatRecord = (r: main.LogcatRecord): string => {
+  if (!r.priority) return r.message;
+  return `${r.timestamp} ${r.priority}/${r.tag}(${r.pid}): ${r.message}`;
+};
+
 export const useLogcatStore = create<LogcatState>((set, get) => ({
   // Initial state
   logs: [],
@@ -60,6 +85,7 @@ export const useLogcatStore = create<LogcatState>((set, get) => ({
 
   logBuffer: [],
   flushTimerId: null,
+  sessionId: '',
 
   // Actions
   setLogs: (logsOrUpdater) => {
@@ -77,13 +103,13 @@ export const useLogcatStore = create<LogcatState>((set, get) => ({
 
   setLogFilter: (filter) => set({ logFilter: filter }),
   setUseRegex: (useRegex) => set({ useRegex }),
-  setPreFilter: (filter) => set({ preFilter: filter }),
-  setPreUseRegex: (useRegex) => set({ preUseRegex: useRegex }),
-  setExcludeFilter: (filter) => set({ excludeFilter: filter }),
-  setExcludeUseRegex: (useRegex) => set({ excludeUseRegex: useRegex }),
+  setPreFilter: (filter) => { set({ preFilter: filter }); syncFilter(get()); },
+  setPreUseRegex: (useRegex) => { set({ preUseRegex: useRegex }); syncFilter(get()); },
+  setExcludeFilter: (filter) => { set({ excludeFilter: filter }); syncFilter(get()); },
+  setExcludeUseRegex: (useRegex) => { set({ excludeUseRegex: useRegex }); syncFilter(get()); },
 
   toggleLogcat: async (deviceId: string, pkg: string) => {
-    const { isLogging, preFilter, preUseRegex, excludeFilter, excludeUseRegex } = get();
+    const { isLogging } = get();
 
     if (isLogging) {
       get().stopLogcat();
@@ -106,58 +132,29 @@ export const useLogcatStore = create<LogcatState>((set, get) => ({
 
       set({ flushTimerId: timerId });
 
-      // Subscribe to logcat events
-      EventsOn('logcat-data', (data: string | string[]) => {
-        const lines = Array.isArray(data) ? data : [data];
-
-        // Access current filter state using get()
-        const { preFilter, preUseRegex, excludeFilter, excludeUseRegex, logBuffer } = get();
-
-        const filteredLines: string[] = [];
-
-        for (const line of lines) {
-          let shouldKeep = true;
-
-          // 1. Pre-filter (Include)
-          if (preFilter.trim()) {
-            try {
-              if (preUseRegex) {
-                const regex = new RegExp(preFilter, 'i');
-                if (!regex.test(line)) shouldKeep = false;
-              } else {
-                if (!line.toLowerCase().includes(preFilter.toLowerCase())) shouldKeep = false;
-              }
-            } catch {
-              if (!line.toLowerCase().includes(preFilter.toLowerCase())) shouldKeep = false;
-            }
-          }
-
-          // 2. Exclude filter (Negative)
-          if (shouldKeep && excludeFilter.trim()) {
-            try {
-              if (excludeUseRegex) {
-                const regex = new RegExp(excludeFilter, 'i');
-                if (regex.test(line)) shouldKeep = false;
-              } else {
-                if (line.toLowerCase().includes(excludeFilter.toLowerCase())) shouldKeep = false;
-              }
-            } catch {
-              if (line.toLowerCase().includes(excludeFilter.toLowerCase())) shouldKeep = false;
-            }
-          }
-
-          if (shouldKeep) {
-            filteredLines.push(line);
-          }
-        }
-
-        if (filteredLines.length > 0) {
-          set({ logBuffer: [...logBuffer, ...filteredLines] });
+      // Filtering happens in Go; records only need formatting for display
+      const onData = (records: main.LogcatRecord[]) => {
+        const lines = records.map(formatLogcatRecord);
+        if (lines.length > 0) {
+          set(state => ({ logBuffer: [...state.logBuffer, ...lines] }));
         }
-      });
+      };
 
       try {
-        await StartLogcat(deviceId, pkg, preFilter, preUseRegex, excludeFilter, excludeUseRegex);
+        const sessionId = await CreateLogcatSession(deviceId, pkg, buildLogcatFilter(get()), []);
+        set({ sessionId });
+
+        // Subscribe before the stream starts so the backlog and first status aren't missed
+        EventsOn(`logcat-data:${sessionId}`, onData);
+        EventsOn(`logcat-status:${sessionId}`, (status: main.LogcatStatus) => {
+          set(state => ({ logBuffer: [...state.logBuffer, `--- ${status.message} ---`] }));
+        });
+        EventsOn('logcat-stopped', (info: { sessionId: string }) => {
+          if (info.sessionId === get().sessionId) {
+            get().stopLogcat();
+          }
+        });
+        await StartLogcatSession(sessionId);
       } catch (err) {
         get().stopLogcat();
         throw err;
@@ -166,16 +163,20 @@ export const useLogcatStore = create<LogcatState>((set, get) => ({
   },
 
   stopLogcat: () => {
-    const { flushTimerId } = get();
+    const { flushTimerId, sessionId } = get();
 
-    StopLogcat();
-    EventsOff('logcat-data');
+    if (sessionId) {
+      StopLogcat(sessionId);
+      EventsOff(`logcat-data:${sessionId}`);
+      EventsOff(`logcat-status:${sessionId}`);
+    }
+    EventsOff('logcat-stopped');
 
     if (flushTimerId) {
       clearInterval(flushTimerId);
     }
 
-    set({ isLogging: false, logBuffer: [], flushTimerId: null });
+    set({ isLogging: false, logBuffer: [], flushTimerId: null, sessionId: '' });
   },
 
   reset: () => {
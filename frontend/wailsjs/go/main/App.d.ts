@@ -1,7 +1,7 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
-import {time} from '../models';
 import {main} from '../models';
+import {time} from '../models';
 import {context} from '../models';
 
 export function AdbConnect(arg1:string):Promise<string>;
@@ -10,42 +10,150 @@ export function AdbDisconnect(arg1:string):Promise<string>;
 
 export function AdbPair(arg1:string,arg2:string):Promise<string>;
 
+export function AddPathBookmark(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function AddPortForward(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function AddPortReverse(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function AddWorkflowTrigger(arg1:main.WorkflowTrigger):Promise<main.WorkflowTrigger>;
+
 export function AnalyzeElementSelectors(arg1:string,arg2:number,arg3:number,arg4:time.Time):Promise<Array<main.SelectorSuggestion>>;
 
+export function AnalyzeRemoteStorage(arg1:string,arg2:string,arg3:number):Promise<main.StorageAnalysis>;
+
+export function ApplyDisplayProfile(arg1:string,arg2:main.DisplayProfile):Promise<main.DisplayProfile>;
+
+export function ApplyPortForwardFavorites(arg1:string):Promise<Array<main.PortForwardResult>>;
+
 export function AssertElementExists(arg1:string,arg2:main.ElementSelector):Promise<boolean>;
 
+export function AssertElementImage(arg1:string,arg2:main.ElementSelector,arg3:string,arg4:number):Promise<main.ElementImageAssertResult>;
+
 export function AssertElementText(arg1:string,arg2:main.ElementSelector,arg3:string,arg4:boolean):Promise<boolean>;
 
+export function BackupApp(arg1:string,arg2:string,arg3:string,arg4:boolean,arg5:boolean):Promise<string>;
+
+export function CancelCommand(arg1:string):Promise<void>;
+
 export function CancelOpenFile(arg1:string):Promise<void>;
 
+export function CancelScheduledPlayback(arg1:string):Promise<void>;
+
+export function CancelStorageAnalysis(arg1:string):Promise<void>;
+
+export function CancelTransfer(arg1:string):Promise<void>;
+
+export function CaptureDisplayProfile(arg1:string,arg2:string):Promise<main.DisplayProfile>;
+
+export function CaptureElementImage(arg1:string,arg2:main.ElementSelector):Promise<main.ElementImage>;
+
+export function CaptureHeapDump(arg1:string,arg2:string,arg3:string):Promise<main.HeapDumpResult>;
+
+export function CheckDangerousCommand(arg1:string):Promise<main.DangerousCommandCheck>;
+
+export function CheckFastbootFlash(arg1:string,arg2:string):Promise<main.DangerousCommandCheck>;
+
+export function ChecksumRemoteFile(arg1:string,arg2:string,arg3:string):Promise<string>;
+
 export function ClearAppData(arg1:string,arg2:string):Promise<string>;
 
+export function ClearGlobalProxy(arg1:string):Promise<void>;
+
+export function ClearMockLocation(arg1:string):Promise<void>;
+
+export function ClearShellHistory(arg1:string):Promise<void>;
+
+export function ClearTextField(arg1:string,arg2:number):Promise<void>;
+
 export function ClickElement(arg1:context.Context,arg2:string,arg3:main.ElementSelector,arg4:main.ElementActionConfig):Promise<void>;
 
+export function CloseShellSession(arg1:string):Promise<void>;
+
+export function CollapseShade(arg1:string):Promise<void>;
+
+export function CollectTracesViaBugreport(arg1:string,arg2:string):Promise<Array<main.TraceFile>>;
+
+export function ConfirmShellCommand(arg1:string,arg2:string):Promise<void>;
+
+export function ConvertScriptToSelectors(arg1:string,arg2:string):Promise<main.TouchScript>;
+
 export function CopyFile(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function CopyRemotePath(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function CopyScrcpyPreset(arg1:string,arg2:string):Promise<void>;
+
+export function CreateLogcatSession(arg1:string,arg2:string,arg3:main.LogcatFilter,arg4:Array<string>):Promise<string>;
+
+export function CreateRemoteDirectory(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteDisplayProfile(arg1:string):Promise<void>;
+
 export function DeleteFile(arg1:string,arg2:string):Promise<void>;
 
+export function DeleteIntentPreset(arg1:string):Promise<void>;
+
+export function DeleteRemotePath(arg1:string,arg2:string,arg3:boolean,arg4:boolean):Promise<main.DeleteSummary>;
+
+export function DeleteScrcpyPreset(arg1:string):Promise<void>;
+
 export function DeleteScriptTask(arg1:string):Promise<void>;
 
+export function DeleteSetting(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function DeleteSettingsSnapshot(arg1:string):Promise<void>;
+
+export function DeleteShellSnippet(arg1:string):Promise<void>;
+
 export function DeleteTouchScript(arg1:string):Promise<void>;
 
+export function DeleteTouchScriptEvent(arg1:string,arg2:number):Promise<void>;
+
+export function DeleteUISnapshot(arg1:string):Promise<void>;
+
 export function DeleteWorkflow(arg1:string):Promise<void>;
 
+export function DeleteWorkflowRun(arg1:string):Promise<void>;
+
+export function DiffSettingsSnapshots(arg1:string,arg2:string):Promise<Array<main.SettingChange>>;
+
+export function DiffUISnapshots(arg1:string,arg2:string):Promise<main.UISnapshotDiff>;
+
+export function DisableAnimations(arg1:string):Promise<main.AnimationScales>;
+
 export function DisableApp(arg1:string,arg2:string):Promise<string>;
 
+export function DisableDemoMode(arg1:string):Promise<void>;
+
+export function DismissNotification(arg1:string,arg2:string):Promise<void>;
+
 export function DownloadFile(arg1:string,arg2:string):Promise<string>;
 
 export function EnableApp(arg1:string,arg2:string):Promise<string>;
 
+export function EnableDemoMode(arg1:string,arg2:main.DemoModeOptions):Promise<void>;
+
 export function ExecuteBatchOperation(arg1:main.BatchOperation):Promise<main.BatchOperationResult>;
 
 export function ExecuteSingleTouchEvent(arg1:string,arg2:main.TouchEvent,arg3:string):Promise<void>;
 
 export function ExecuteSingleWorkflowStep(arg1:string,arg2:main.WorkflowStep):Promise<void>;
 
+export function ExpandNotificationShade(arg1:string):Promise<void>;
+
 export function ExportAPK(arg1:string,arg2:string):Promise<string>;
 
+export function ExportSecurityReport(arg1:string,arg2:string):Promise<string>;
+
+export function ExportTouchScript(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ExportWorkflowRunHTML(arg1:string,arg2:string):Promise<string>;
+
+export function ExtractAbToTar(arg1:string,arg2:string):Promise<void>;
+
+export function FastbootReboot(arg1:string,arg2:string):Promise<void>;
+
 export function FindAllElementsBySelector(arg1:main.UINode,arg2:main.ElementSelector):Promise<Array<main.UINode>>;
 
 export function FindElement(arg1:main.UINode,arg2:string,arg3:string):Promise<boolean>;
@@ -54,18 +162,44 @@ export function FindElementAtPoint(arg1:main.UINode,arg2:number,arg3:number):Pro
 
 export function FindElementBySelector(arg1:main.UINode,arg2:main.ElementSelector):Promise<main.UINode>;
 
+export function FlashPartition(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
 export function ForceStopApp(arg1:string,arg2:string):Promise<string>;
 
 export function GenerateSelectorSuggestions(arg1:main.UINode,arg2:main.UINode):Promise<Array<main.SelectorSuggestion>>;
 
+export function GetActivityStack(arg1:string):Promise<Array<main.ActivityTask>>;
+
+export function GetAnimationScales(arg1:string):Promise<main.AnimationScales>;
+
 export function GetAppInfo(arg1:string,arg2:string,arg3:boolean):Promise<main.AppPackage>;
 
+export function GetAppMemoryInfo(arg1:string,arg2:string):Promise<main.AppMemoryInfo>;
+
+export function GetAppStandbyBucket(arg1:string,arg2:string):Promise<string>;
+
 export function GetAppVersion():Promise<string>;
 
+export function GetAuditLog(arg1:number):Promise<Array<main.AuditEntry>>;
+
 export function GetBackendLogs():Promise<Array<string>>;
 
 export function GetBestSelector(arg1:main.UINode,arg2:main.UINode):Promise<main.ElementSelector>;
 
+export function GetClassifierConfig():Promise<main.ClassifierConfig>;
+
+export function GetCurrentActivity(arg1:string):Promise<main.CurrentActivity>;
+
+export function GetDangerousCommandPolicy():Promise<main.DangerousCommandPolicy>;
+
+export function GetDefaultScrcpyConfig(arg1:string):Promise<main.ScrcpyConfig>;
+
+export function GetDefaultScrcpyPreset(arg1:string):Promise<string>;
+
+export function GetDeveloperToggles(arg1:string):Promise<Array<main.DeveloperToggle>>;
+
+export function GetDeviceClipboard(arg1:string):Promise<string>;
+
 export function GetDeviceIP(arg1:string):Promise<string>;
 
 export function GetDeviceInfo(arg1:string):Promise<main.DeviceInfo>;
@@ -74,58 +208,188 @@ export function GetDeviceResolution(arg1:string):Promise<string>;
 
 export function GetDevices(arg1:boolean):Promise<Array<main.Device>>;
 
+export function GetDisplaySettings(arg1:string):Promise<main.DisplaySettings>;
+
+export function GetDozeState(arg1:string):Promise<main.DozeState>;
+
 export function GetElementProperties(arg1:string,arg2:main.ElementSelector):Promise<{[key: string]: any}>;
 
 export function GetElementsWithText(arg1:string,arg2:string):Promise<Array<{[key: string]: any}>>;
 
+export function GetFastbootVars(arg1:string):Promise<main.FastbootVars>;
+
+export function GetFrameStats(arg1:string,arg2:string):Promise<main.FrameStats>;
+
+export function GetGlobalProxy(arg1:string):Promise<main.GlobalProxy>;
+
 export function GetHistoryDevices():Promise<Array<main.HistoryDevice>>;
 
+export function GetInstrumentationSummary(arg1:string):Promise<main.InstrumentationSummary>;
+
 export function GetLocalIP():Promise<string>;
 
+export function GetLocaleAndTime(arg1:string):Promise<main.LocaleTimeInfo>;
+
+export function GetLogBufferSize(arg1:string):Promise<Array<main.LogBufferInfo>>;
+
+export function GetLogcatStats(arg1:string):Promise<main.LogcatStats>;
+
+export function GetLogcatStatuses(arg1:string):Promise<Array<main.LogcatStatus>>;
+
 export function GetMITMBypassPatterns():Promise<Array<string>>;
 
+export function GetMonkeyResult(arg1:string):Promise<main.MonkeyResult>;
+
+export function GetNetworkStats(arg1:string):Promise<main.AppNetworkStats>;
+
+export function GetNodePath(arg1:main.UINode,arg2:main.UINode):Promise<Array<main.NodePathStep>>;
+
+export function GetOrientation(arg1:string):Promise<main.OrientationInfo>;
+
+export function GetPacketCaptureStatus(arg1:string):Promise<main.PacketCaptureStatus>;
+
+export function GetPlaybackHistory(arg1:string,arg2:number):Promise<Array<main.PlaybackRun>>;
+
+export function GetPlaybackRunDetail(arg1:string):Promise<main.PlaybackRun>;
+
+export function GetPreviewSizeCap():Promise<number>;
+
 export function GetProxySettings():Promise<{[key: string]: any}>;
 
 export function GetProxyStatus():Promise<boolean>;
 
+export function GetRadioStates(arg1:string):Promise<{[key: string]: boolean}>;
+
+export function GetRecentPaths(arg1:string):Promise<Array<string>>;
+
 export function GetRecordingEventCount(arg1:string):Promise<number>;
 
 export function GetRecordingStatus(arg1:string):Promise<{[key: string]: any}>;
 
+export function GetRecordingsDir():Promise<string>;
+
+export function GetResourceHistory(arg1:string):Promise<Array<main.ResourceSample>>;
+
+export function GetScheduledAlarms(arg1:string,arg2:string):Promise<Array<main.AlarmPackage>>;
+
+export function GetScheduledJobs(arg1:string,arg2:string):Promise<Array<main.ScheduledJob>>;
+
+export function GetSecurityReport(arg1:string):Promise<main.SecurityReport>;
+
 export function GetSelectorMatchCount(arg1:main.UINode,arg2:main.ElementSelector):Promise<number>;
 
+export function GetSettings(arg1:string,arg2:string):Promise<Array<main.SettingEntry>>;
+
+export function GetShellHistory(arg1:string,arg2:number):Promise<Array<main.ShellHistoryEntry>>;
+
+export function GetSystemTraceStatus(arg1:string):Promise<main.SystemTraceStatus>;
+
 export function GetThumbnail(arg1:string,arg2:string,arg3:string):Promise<string>;
 
 export function GetTouchInputDevice(arg1:string):Promise<string>;
 
-export function GetUIHierarchy(arg1:string):Promise<main.UIHierarchyResult>;
+export function GetUIHierarchy(arg1:string,arg2:boolean):Promise<main.UIHierarchyResult>;
+
+export function GetUISnapshot(arg1:string):Promise<main.UISnapshot>;
+
+export function GetWifiInfo(arg1:string):Promise<main.WifiInfo>;
+
+export function GetWorkflowReportLimit():Promise<number>;
+
+export function GetWorkflowRunReport(arg1:string):Promise<main.WorkflowRunReport>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportTouchScript(arg1:string):Promise<main.TouchScript>;
+
 export function InputNodeText(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function InputTextToElement(arg1:context.Context,arg2:string,arg3:main.ElementSelector,arg4:string,arg5:boolean,arg6:main.ElementActionConfig):Promise<void>;
 
+export function InsertTouchScriptEvent(arg1:string,arg2:number,arg3:main.TouchEvent):Promise<void>;
+
+export function InspectSideloadPackage(arg1:string):Promise<main.SideloadPackageInfo>;
+
 export function InstallAPK(arg1:string,arg2:string):Promise<string>;
 
 export function InstallProxyCert(arg1:string):Promise<string>;
 
+export function InvalidateUIDump(arg1:string):Promise<void>;
+
 export function IsAppRunning(arg1:string,arg2:string):Promise<boolean>;
 
+export function IsDemoModeActive(arg1:string):Promise<boolean>;
+
+export function IsKeyboardPassthroughActive(arg1:string):Promise<boolean>;
+
+export function IsMethodTracing(arg1:string):Promise<string>;
+
 export function IsPlayingTouch(arg1:string):Promise<boolean>;
 
 export function IsRecording(arg1:string):Promise<boolean>;
 
 export function IsRecordingTouch(arg1:string):Promise<boolean>;
 
-export function ListCameras(arg1:string):Promise<Array<string>>;
+export function KillProcess(arg1:string,arg2:number,arg3:number):Promise<main.KillResult>;
+
+export function ListAnrTraces(arg1:string):Promise<Array<main.TraceFile>>;
 
-export function ListDisplays(arg1:string):Promise<Array<string>>;
+export function ListDeepLinkHandlers(arg1:string,arg2:string):Promise<Array<main.DeepLinkHandler>>;
+
+export function ListDisplayProfiles():Promise<Array<main.DisplayProfile>>;
+
+export function ListDisplays(arg1:string):Promise<Array<main.ScrcpyDisplay>>;
+
+export function ListFastbootDevices():Promise<Array<main.Device>>;
 
 export function ListFiles(arg1:string,arg2:string):Promise<Array<main.FileInfo>>;
 
+export function ListInstrumentations(arg1:string):Promise<Array<main.InstrumentationTarget>>;
+
+export function ListIntentPresets():Promise<Array<main.IntentPreset>>;
+
+export function ListKeyCodes():Promise<Array<main.KeyCodeInfo>>;
+
+export function ListLogcatSessions():Promise<{[key: string]: string}>;
+
+export function ListManagedProcesses():Promise<Array<main.ManagedProcess>>;
+
+export function ListNotifications(arg1:string):Promise<Array<main.NotificationEntry>>;
+
 export function ListPackages(arg1:string,arg2:string):Promise<Array<main.AppPackage>>;
 
+export function ListPathBookmarks(arg1:string):Promise<Array<main.PathBookmark>>;
+
+export function ListPortForwardFavorites(arg1:string):Promise<Array<main.PortForward>>;
+
+export function ListPortForwards(arg1:string):Promise<Array<main.PortForward>>;
+
+export function ListProcesses(arg1:string):Promise<Array<main.ProcessInfo>>;
+
+export function ListRecordings():Promise<Array<main.RecordingInfo>>;
+
+export function ListScheduledPlaybacks():Promise<Array<main.ScheduledPlayback>>;
+
+export function ListScrcpyCameras(arg1:string):Promise<Array<main.ScrcpyCamera>>;
+
+export function ListScrcpyPresets():Promise<Array<main.ScrcpyPreset>>;
+
+export function ListScrcpySessions():Promise<Array<main.ScrcpySession>>;
+
+export function ListSettingsSnapshots(arg1:string):Promise<Array<main.SettingsSnapshot>>;
+
+export function ListShellSnippets():Promise<Array<main.ShellSnippet>>;
+
+export function ListTombstones(arg1:string):Promise<Array<main.TraceFile>>;
+
+export function ListTouchScripts(arg1:main.ScriptFilter):Promise<Array<main.TouchScriptSummary>>;
+
+export function ListUISnapshots():Promise<Array<main.UISnapshotSummary>>;
+
+export function ListWorkflowRuns(arg1:string):Promise<Array<main.WorkflowRunReport>>;
+
+export function ListWorkflowTriggers():Promise<Array<main.WorkflowTrigger>>;
+
 export function LoadScriptTasks():Promise<Array<main.ScriptTask>>;
 
 export function LoadTouchScripts():Promise<Array<main.TouchScript>>;
@@ -142,46 +406,132 @@ export function Mkdir(arg1:string,arg2:string):Promise<void>;
 
 export function MoveFile(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function MoveRemotePath(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function MoveTouchScriptToFolder(arg1:string,arg2:string):Promise<void>;
+
 export function OpenFileOnHost(arg1:string,arg2:string):Promise<void>;
 
 export function OpenPath(arg1:string):Promise<void>;
 
+export function OpenRemoteFile(arg1:string,arg2:string):Promise<string>;
+
 export function OpenSettings(arg1:string,arg2:string,arg3:string):Promise<string>;
 
+export function OpenShellSession(arg1:string):Promise<string>;
+
 export function PauseTask(arg1:string):Promise<void>;
 
+export function PauseTouchPlayback(arg1:string):Promise<void>;
+
 export function PerformNodeAction(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function PickPointOnScreen(arg1:string,arg2:number):Promise<{[key: string]: any}>;
 
-export function PlayTouchScript(arg1:string,arg2:main.TouchScript):Promise<void>;
+export function PlayLocationRoute(arg1:string,arg2:Array<main.GeoPoint>,arg3:number):Promise<void>;
+
+export function PlayTouchScript(arg1:string,arg2:main.TouchScript,arg3:main.PlaybackOptions):Promise<void>;
+
+export function PlayTouchScriptOnDevices(arg1:Array<string>,arg2:string,arg3:main.PlaybackOptions):Promise<string>;
+
+export function PreviewRemoteFile(arg1:string,arg2:string,arg3:number):Promise<main.FilePreview>;
+
+export function PullCameraRoll(arg1:string,arg2:string,arg3:main.CameraRollOptions):Promise<main.CameraRollSummary>;
+
+export function PullFile(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<main.TransferResult>;
+
+export function PullTrace(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function PushDroppedFiles(arg1:string,arg2:Array<string>,arg3:string,arg4:boolean):Promise<string>;
+
+export function PushFile(arg1:string,arg2:string,arg3:string,arg4:boolean,arg5:boolean):Promise<main.TransferResult>;
+
+export function PutSetting(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.SettingEntry>;
+
+export function RebootToSideload(arg1:string):Promise<void>;
+
+export function RemoveAllPortForwards(arg1:string):Promise<void>;
 
 export function RemoveHistoryDevice(arg1:string):Promise<void>;
 
+export function RemovePathBookmark(arg1:string,arg2:string):Promise<void>;
+
+export function RemovePortForward(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function RemovePortForwardFavorite(arg1:string,arg2:main.PortForward):Promise<void>;
+
+export function RemoveWorkflowTrigger(arg1:string):Promise<void>;
+
+export function RenameRemotePath(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function RenameTouchScript(arg1:string,arg2:string):Promise<void>;
 
+export function ResetFrameStats(arg1:string,arg2:string):Promise<void>;
+
+export function ResetLogcatStats(arg1:string):Promise<void>;
+
+export function ResizeShell(arg1:string,arg2:number,arg3:number):Promise<void>;
+
 export function RestartAdbServer():Promise<string>;
 
+export function RestoreAnimations(arg1:string):Promise<main.AnimationScales>;
+
+export function RestoreApp(arg1:string,arg2:string):Promise<void>;
+
+export function RestoreDisplayProfile(arg1:string):Promise<void>;
+
 export function ResumeTask(arg1:string):Promise<void>;
 
+export function ResumeTouchPlayback(arg1:string):Promise<void>;
+
 export function RunAdbCommand(arg1:string,arg2:string):Promise<string>;
 
+export function RunAdbCommandConfirmed(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function RunAdbCommandStreamed(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function RunInstrumentationTests(arg1:string,arg2:main.InstrumentationOptions):Promise<main.InstrumentationSummary>;
+
+export function RunIntentPreset(arg1:string,arg2:string):Promise<main.IntentResult>;
+
+export function RunJobNow(arg1:string,arg2:string,arg3:number):Promise<string>;
+
 export function RunScriptTask(arg1:string,arg2:main.ScriptTask):Promise<void>;
 
-export function RunWorkflow(arg1:main.Device,arg2:main.Workflow):Promise<void>;
+export function RunShellCommand(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function RunWorkflow(arg1:main.Device,arg2:main.Workflow,arg3:{[key: string]: string}):Promise<void>;
+
+export function RunWorkflowWithDataset(arg1:string,arg2:string,arg3:string):Promise<main.WorkflowDatasetResult>;
+
+export function SaveDisplayProfile(arg1:main.DisplayProfile):Promise<void>;
+
+export function SaveIntentPreset(arg1:string,arg2:string,arg3:main.IntentSpec):Promise<void>;
+
+export function SavePortForwardFavorite(arg1:string,arg2:main.PortForward):Promise<void>;
+
+export function SaveScrcpyPreset(arg1:string,arg2:main.ScrcpyConfig):Promise<void>;
 
 export function SaveScriptTask(arg1:main.ScriptTask):Promise<void>;
 
-export function SaveTouchScript(arg1:main.TouchScript):Promise<void>;
+export function SaveShellSnippet(arg1:string,arg2:string):Promise<void>;
+
+export function SaveTouchScript(arg1:main.TouchScript,arg2:boolean):Promise<void>;
+
+export function SaveUISnapshot(arg1:string,arg2:string):Promise<main.UISnapshotSummary>;
 
 export function SaveWorkflow(arg1:main.Workflow):Promise<void>;
 
-export function ScrollToElement(arg1:context.Context,arg2:string,arg3:main.ElementSelector,arg4:string,arg5:number):Promise<void>;
+export function SchedulePlayback(arg1:string,arg2:string,arg3:string):Promise<main.ScheduledPlayback>;
+
+export function ScrollToElement(arg1:string,arg2:main.ElementSelector,arg3:main.ElementSelector,arg4:number,arg5:string):Promise<main.ScrollResult>;
 
 export function SearchElementsAdvanced(arg1:main.UINode,arg2:string):Promise<Array<main.SearchResult>>;
 
 export function SearchElementsXPath(arg1:main.UINode,arg2:string):Promise<Array<main.SearchResult>>;
 
+export function SearchShellHistory(arg1:string,arg2:string,arg3:number):Promise<Array<main.ShellHistoryEntry>>;
+
 export function SearchUIElements(arg1:string,arg2:string):Promise<Array<{[key: string]: any}>>;
 
 export function SelectAPKForBatch():Promise<string>;
@@ -192,10 +542,70 @@ export function SelectRecordPath(arg1:string):Promise<string>;
 
 export function SelectScreenshotPath(arg1:string):Promise<string>;
 
+export function SelectWorkflowDataset():Promise<string>;
+
+export function SendBroadcast(arg1:string,arg2:main.IntentSpec):Promise<main.IntentResult>;
+
+export function SendKeyCombo(arg1:string,arg2:Array<number>):Promise<string>;
+
+export function SendKeyEvent(arg1:string,arg2:number,arg3:boolean):Promise<void>;
+
+export function SetAirplaneMode(arg1:string,arg2:boolean):Promise<main.RadioToggleResult>;
+
+export function SetAnimationScales(arg1:string,arg2:number,arg3:number,arg4:number):Promise<main.AnimationScales>;
+
+export function SetAppStandbyBucket(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function SetBluetooth(arg1:string,arg2:boolean):Promise<main.RadioToggleResult>;
+
+export function SetClassifierConfig(arg1:main.ClassifierConfig):Promise<void>;
+
+export function SetDarkMode(arg1:string,arg2:string):Promise<main.DisplaySettings>;
+
+export function SetDefaultScrcpyPreset(arg1:string,arg2:string):Promise<void>;
+
+export function SetDeveloperToggle(arg1:string,arg2:string,arg3:boolean):Promise<main.DeveloperToggle>;
+
+export function SetDeviceClipboard(arg1:string,arg2:string):Promise<void>;
+
+export function SetDeviceLocale(arg1:string,arg2:string):Promise<main.LocaleTimeChange>;
+
 export function SetDeviceNetworkLimit(arg1:string,arg2:number):Promise<string>;
 
+export function SetDeviceTime(arg1:string,arg2:time.Time):Promise<main.LocaleTimeChange>;
+
+export function SetDisplayDensity(arg1:string,arg2:number):Promise<main.DisplaySettings>;
+
+export function SetDisplayPower(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetDisplaySize(arg1:string,arg2:string):Promise<main.DisplaySettings>;
+
+export function SetDozeMode(arg1:string,arg2:string):Promise<main.DozeState>;
+
+export function SetDozeWhitelist(arg1:string,arg2:string,arg3:boolean):Promise<main.DozeState>;
+
+export function SetFontScale(arg1:string,arg2:number):Promise<main.DisplaySettings>;
+
+export function SetGlobalProxy(arg1:string,arg2:string,arg3:number):Promise<main.GlobalProxy>;
+
+export function SetLogBufferSize(arg1:string,arg2:string):Promise<void>;
+
+export function SetLogcatBuffers(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetLogcatStatsEvents(arg1:string,arg2:boolean):Promise<void>;
+
 export function SetMITMBypassPatterns(arg1:Array<string>):Promise<void>;
 
+export function SetMobileData(arg1:string,arg2:boolean):Promise<main.RadioToggleResult>;
+
+export function SetMockLocation(arg1:string,arg2:number,arg3:number,arg4:number,arg5:number):Promise<string>;
+
+export function SetMockLocationApp(arg1:string,arg2:string):Promise<void>;
+
+export function SetOrientation(arg1:string,arg2:string):Promise<main.OrientationInfo>;
+
+export function SetPreviewSizeCap(arg1:number):Promise<void>;
+
 export function SetProxyLatency(arg1:number):Promise<void>;
 
 export function SetProxyLimit(arg1:number,arg2:number):Promise<void>;
@@ -204,47 +614,137 @@ export function SetProxyMITM(arg1:boolean):Promise<void>;
 
 export function SetProxyWSEnabled(arg1:boolean):Promise<void>;
 
+export function SetRecordingsDir(arg1:string):Promise<void>;
+
+export function SetTimezone(arg1:string,arg2:string):Promise<main.LocaleTimeChange>;
+
+export function SetTouchScriptTags(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetUIDumpCacheTTL(arg1:number):Promise<void>;
+
+export function SetWifiEnabled(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetWorkflowReportLimit(arg1:number):Promise<void>;
+
+export function SetWorkflowTriggerEnabled(arg1:string,arg2:boolean):Promise<void>;
+
 export function Shutdown(arg1:context.Context):Promise<void>;
 
+export function SideloadPackage(arg1:string,arg2:string):Promise<main.SideloadResult>;
+
+export function SnapshotSettings(arg1:string,arg2:string):Promise<main.SettingsSnapshot>;
+
 export function StartActivity(arg1:string,arg2:string):Promise<string>;
 
+export function StartActivityWatcher(arg1:string):Promise<void>;
+
 export function StartApp(arg1:string,arg2:string):Promise<string>;
 
+export function StartAppMemoryTrace(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function StartAppNetworkMonitor(arg1:string,arg2:number):Promise<void>;
+
 export function StartDeviceMonitor():Promise<void>;
 
-export function StartLogcat(arg1:string,arg2:string,arg3:string,arg4:boolean,arg5:string,arg6:boolean):Promise<void>;
+export function StartIntent(arg1:string,arg2:main.IntentSpec):Promise<main.IntentResult>;
+
+export function StartJankMonitor(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function StartKeyboardPassthrough(arg1:string):Promise<void>;
+
+export function StartLogcatSession(arg1:string):Promise<void>;
+
+export function StartMethodTrace(arg1:string,arg2:string):Promise<void>;
+
+export function StartMonkeyTest(arg1:string,arg2:main.MonkeyOptions):Promise<main.MonkeyResult>;
 
 export function StartNetworkMonitor(arg1:string):Promise<void>;
 
+export function StartNotificationWatcher(arg1:string):Promise<void>;
+
+export function StartPacketCapture(arg1:string,arg2:main.PacketCaptureOptions):Promise<main.PacketCaptureStatus>;
+
 export function StartProxy(arg1:number):Promise<string>;
 
 export function StartRecording(arg1:string,arg2:main.ScrcpyConfig):Promise<void>;
 
-export function StartScrcpy(arg1:string,arg2:main.ScrcpyConfig):Promise<void>;
+export function StartResourceMonitor(arg1:string,arg2:number):Promise<void>;
+
+export function StartScrcpy(arg1:string,arg2:main.ScrcpyConfig):Promise<main.ScrcpySession>;
+
+export function StartScreenRecording(arg1:string,arg2:main.RecordOptions):Promise<main.RecordingInfo>;
+
+export function StartScreenrecord(arg1:string,arg2:main.RecordOptions):Promise<main.RecordingInfo>;
+
+export function StartScreenshotBurst(arg1:string,arg2:number,arg3:number,arg4:string):Promise<main.BurstResult>;
+
+export function StartService(arg1:string,arg2:main.IntentSpec,arg3:boolean):Promise<main.IntentResult>;
+
+export function StartSystemTrace(arg1:string,arg2:main.TraceOptions):Promise<main.SystemTraceStatus>;
 
-export function StartTouchRecording(arg1:string,arg2:string):Promise<void>;
+export function StartThumbnailStream(arg1:string,arg2:number,arg3:number):Promise<void>;
+
+export function StartTouchRecording(arg1:string,arg2:string,arg3:main.RecordingOptions):Promise<void>;
 
 export function StartWirelessServer():Promise<string>;
 
+export function StepTouchPlayback(arg1:string):Promise<void>;
+
+export function StopActivityWatcher(arg1:string):Promise<void>;
+
+export function StopAllLogcat():Promise<void>;
+
 export function StopAllNetworkMonitors():Promise<void>;
 
+export function StopAppMemoryTrace(arg1:string,arg2:string):Promise<void>;
+
+export function StopAppNetworkMonitor(arg1:string):Promise<void>;
+
 export function StopDeviceMonitor():Promise<void>;
 
-export function StopLogcat():Promise<void>;
+export function StopInstrumentationTests(arg1:string):Promise<main.InstrumentationSummary>;
+
+export function StopJankMonitor(arg1:string,arg2:string):Promise<void>;
+
+export function StopKeyboardPassthrough(arg1:string):Promise<void>;
+
+export function StopLocationRoute(arg1:string):Promise<void>;
+
+export function StopLogcat(arg1:string):Promise<void>;
+
+export function StopMethodTrace(arg1:string,arg2:string):Promise<string>;
+
+export function StopMonkeyTest(arg1:string):Promise<main.MonkeyResult>;
 
 export function StopNetworkMonitor(arg1:string):Promise<void>;
 
+export function StopNotificationWatcher(arg1:string):Promise<void>;
+
+export function StopPacketCapture(arg1:string):Promise<main.PacketCaptureStatus>;
+
 export function StopProxy():Promise<string>;
 
 export function StopRecording(arg1:string):Promise<void>;
 
+export function StopResourceMonitor(arg1:string):Promise<void>;
+
 export function StopScrcpy(arg1:string):Promise<void>;
 
+export function StopScreenRecording(arg1:string):Promise<main.RecordingInfo>;
+
+export function StopScreenshotBurst(arg1:string):Promise<void>;
+
+export function StopService(arg1:string,arg2:main.IntentSpec):Promise<main.IntentResult>;
+
+export function StopSystemTrace(arg1:string):Promise<main.SystemTraceStatus>;
+
 export function StopTask(arg1:string):Promise<void>;
 
+export function StopThumbnailStream(arg1:string):Promise<void>;
+
 export function StopTouchPlayback(arg1:string):Promise<void>;
 
-export function StopTouchRecording(arg1:string):Promise<main.TouchScript>;
+export function StopTouchRecording(arg1:string,arg2:main.ClassifierConfig):Promise<main.TouchScript>;
 
 export function StopWorkflow(arg1:main.Device):Promise<void>;
 
@@ -256,16 +756,42 @@ export function SwipeOnElement(arg1:context.Context,arg2:string,arg3:main.Elemen
 
 export function SwitchToWireless(arg1:string):Promise<string>;
 
-export function TakeScreenshot(arg1:string,arg2:string):Promise<string>;
+export function SyncClipboard(arg1:string,arg2:string):Promise<string>;
+
+export function SyncFolderFromDevice(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<main.SyncSummary>;
+
+export function SyncFolderToDevice(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<main.SyncSummary>;
+
+export function TakeScreenshot(arg1:string,arg2:string,arg3:boolean):Promise<string>;
 
 export function TapAtCoordinates(arg1:string,arg2:number,arg3:number):Promise<void>;
 
+export function TestDeepLink(arg1:string,arg2:string,arg3:string):Promise<main.DeepLinkResult>;
+
+export function TestSelector(arg1:string,arg2:main.ElementSelector):Promise<main.SelectorTestResult>;
+
 export function TogglePinDevice(arg1:string):Promise<void>;
 
+export function TrimTouchScript(arg1:string,arg2:number,arg3:number):Promise<main.TouchScript>;
+
+export function TypeText(arg1:string,arg2:string):Promise<main.TypeTextResult>;
+
 export function UninstallApp(arg1:string,arg2:string):Promise<string>;
 
+export function UnlockOrientation(arg1:string):Promise<void>;
+
+export function UpdateDangerousCommandPolicy(arg1:main.DangerousCommandPolicy):Promise<void>;
+
+export function UpdateLogcatFilter(arg1:string,arg2:main.LogcatFilter):Promise<void>;
+
+export function UpdateTouchScriptEvent(arg1:string,arg2:number,arg3:main.TouchEvent):Promise<void>;
+
 export function UploadFile(arg1:string,arg2:string,arg3:string):Promise<void>;
 
-export function WaitElementGone(arg1:context.Context,arg2:string,arg3:main.ElementSelector,arg4:number):Promise<void>;
+export function WaitForElement(arg1:string,arg2:main.ElementSelector,arg3:number,arg4:number):Promise<main.ElementWaitResult>;
+
+export function WaitForElementGone(arg1:string,arg2:main.ElementSelector,arg3:number,arg4:number):Promise<main.ElementWaitResult>;
+
+export function WaitForNotification(arg1:string,arg2:string,arg3:number):Promise<main.NotificationEntry>;
 
-export function WaitForElement(arg1:context.Context,arg2:string,arg3:main.ElementSelector,arg4:number):Promise<void>;
+export function WriteToShell(arg1:string,arg2:string):Promise<void>;
@@ -14,54 +14,242 @@ export function AdbPair(arg1, arg2) {
   return window['go']['main']['App']['AdbPair'](arg1, arg2);
 }
 
+export function AddPathBookmark(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddPathBookmark'](arg1, arg2, arg3);
+}
+
+export function AddPortForward(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddPortForward'](arg1, arg2, arg3);
+}
+
+export function AddPortReverse(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddPortReverse'](arg1, arg2, arg3);
+}
+
+export function AddWorkflowTrigger(arg1) {
+  return window['go']['main']['App']['AddWorkflowTrigger'](arg1);
+}
+
 export function AnalyzeElementSelectors(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['AnalyzeElementSelectors'](arg1, arg2, arg3, arg4);
 }
 
+export function AnalyzeRemoteStorage(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AnalyzeRemoteStorage'](arg1, arg2, arg3);
+}
+
+export function ApplyDisplayProfile(arg1, arg2) {
+  return window['go']['main']['App']['ApplyDisplayProfile'](arg1, arg2);
+}
+
+export function ApplyPortForwardFavorites(arg1) {
+  return window['go']['main']['App']['ApplyPortForwardFavorites'](arg1);
+}
+
 export function AssertElementExists(arg1, arg2) {
   return window['go']['main']['App']['AssertElementExists'](arg1, arg2);
 }
 
+export function AssertElementImage(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AssertElementImage'](arg1, arg2, arg3, arg4);
+}
+
 export function AssertElementText(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['AssertElementText'](arg1, arg2, arg3, arg4);
 }
 
+export function BackupApp(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['BackupApp'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CancelCommand(arg1) {
+  return window['go']['main']['App']['CancelCommand'](arg1);
+}
+
 export function CancelOpenFile(arg1) {
   return window['go']['main']['App']['CancelOpenFile'](arg1);
 }
 
+export function CancelScheduledPlayback(arg1) {
+  return window['go']['main']['App']['CancelScheduledPlayback'](arg1);
+}
+
+export function CancelStorageAnalysis(arg1) {
+  return window['go']['main']['App']['CancelStorageAnalysis'](arg1);
+}
+
+export function CancelTransfer(arg1) {
+  return window['go']['main']['App']['CancelTransfer'](arg1);
+}
+
+export function CaptureDisplayProfile(arg1, arg2) {
+  return window['go']['main']['App']['CaptureDisplayProfile'](arg1, arg2);
+}
+
+export function CaptureElementImage(arg1, arg2) {
+  return window['go']['main']['App']['CaptureElementImage'](arg1, arg2);
+}
+
+export function CaptureHeapDump(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CaptureHeapDump'](arg1, arg2, arg3);
+}
+
+export function CheckDangerousCommand(arg1) {
+  return window['go']['main']['App']['CheckDangerousCommand'](arg1);
+}
+
+export function CheckFastbootFlash(arg1, arg2) {
+  return window['go']['main']['App']['CheckFastbootFlash'](arg1, arg2);
+}
+
+export function ChecksumRemoteFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ChecksumRemoteFile'](arg1, arg2, arg3);
+}
+
 export function ClearAppData(arg1, arg2) {
   return window['go']['main']['App']['ClearAppData'](arg1, arg2);
 }
 
+export function ClearGlobalProxy(arg1) {
+  return window['go']['main']['App']['ClearGlobalProxy'](arg1);
+}
+
+export function ClearMockLocation(arg1) {
+  return window['go']['main']['App']['ClearMockLocation'](arg1);
+}
+
+export function ClearShellHistory(arg1) {
+  return window['go']['main']['App']['ClearShellHistory'](arg1);
+}
+
+export function ClearTextField(arg1, arg2) {
+  return window['go']['main']['App']['ClearTextField'](arg1, arg2);
+}
+
 export function ClickElement(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['ClickElement'](arg1, arg2, arg3, arg4);
 }
 
+export function CloseShellSession(arg1) {
+  return window['go']['main']['App']['CloseShellSession'](arg1);
+}
+
+export function CollapseShade(arg1) {
+  return window['go']['main']['App']['CollapseShade'](arg1);
+}
+
+export function CollectTracesViaBugreport(arg1, arg2) {
+  return window['go']['main']['App']['CollectTracesViaBugreport'](arg1, arg2);
+}
+
+export function ConfirmShellCommand(arg1, arg2) {
+  return window['go']['main']['App']['ConfirmShellCommand'](arg1, arg2);
+}
+
+export function ConvertScriptToSelectors(arg1, arg2) {
+  return window['go']['main']['App']['ConvertScriptToSelectors'](arg1, arg2);
+}
+
 export function CopyFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['CopyFile'](arg1, arg2, arg3);
 }
 
+export function CopyRemotePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CopyRemotePath'](arg1, arg2, arg3);
+}
+
+export function CopyScrcpyPreset(arg1, arg2) {
+  return window['go']['main']['App']['CopyScrcpyPreset'](arg1, arg2);
+}
+
+export function CreateLogcatSession(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreateLogcatSession'](arg1, arg2, arg3, arg4);
+}
+
+export function CreateRemoteDirectory(arg1, arg2) {
+  return window['go']['main']['App']['CreateRemoteDirectory'](arg1, arg2);
+}
+
+export function DeleteDisplayProfile(arg1) {
+  return window['go']['main']['App']['DeleteDisplayProfile'](arg1);
+}
+
 export function DeleteFile(arg1, arg2) {
   return window['go']['main']['App']['DeleteFile'](arg1, arg2);
 }
 
+export function DeleteIntentPreset(arg1) {
+  return window['go']['main']['App']['DeleteIntentPreset'](arg1);
+}
+
+export function DeleteRemotePath(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['DeleteRemotePath'](arg1, arg2, arg3, arg4);
+}
+
+export function DeleteScrcpyPreset(arg1) {
+  return window['go']['main']['App']['DeleteScrcpyPreset'](arg1);
+}
+
 export function DeleteScriptTask(arg1) {
   return window['go']['main']['App']['DeleteScriptTask'](arg1);
 }
 
+export function DeleteSetting(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DeleteSetting'](arg1, arg2, arg3);
+}
+
+export function DeleteSettingsSnapshot(arg1) {
+  return window['go']['main']['App']['DeleteSettingsSnapshot'](arg1);
+}
+
+export function DeleteShellSnippet(arg1) {
+  return window['go']['main']['App']['DeleteShellSnippet'](arg1);
+}
+
 export function DeleteTouchScript(arg1) {
   return window['go']['main']['App']['DeleteTouchScript'](arg1);
 }
 
+export function DeleteTouchScriptEvent(arg1, arg2) {
+  return window['go']['main']['App']['DeleteTouchScriptEvent'](arg1, arg2);
+}
+
+export function DeleteUISnapshot(arg1) {
+  return window['go']['main']['App']['DeleteUISnapshot'](arg1);
+}
+
 export function DeleteWorkflow(arg1) {
   return window['go']['main']['App']['DeleteWorkflow'](arg1);
 }
 
+export function DeleteWorkflowRun(arg1) {
+  return window['go']['main']['App']['DeleteWorkflowRun'](arg1);
+}
+
+export function DiffSettingsSnapshots(arg1, arg2) {
+  return window['go']['main']['App']['DiffSettingsSnapshots'](arg1, arg2);
+}
+
+export function DiffUISnapshots(arg1, arg2) {
+  return window['go']['main']['App']['DiffUISnapshots'](arg1, arg2);
+}
+
+export function DisableAnimations(arg1) {
+  return window['go']['main']['App']['DisableAnimations'](arg1);
+}
+
 export function DisableApp(arg1, arg2) {
   return window['go']['main']['App']['DisableApp'](arg1, arg2);
 }
 
+export function DisableDemoMode(arg1) {
+  return window['go']['main']['App']['DisableDemoMode'](arg1);
+}
+
+export function DismissNotification(arg1, arg2) {
+  return window['go']['main']['App']['DismissNotification'](arg1, arg2);
+}
+
 export function DownloadFile(arg1, arg2) {
   return window['go']['main']['App']['DownloadFile'](arg1, arg2);
 }
@@ -70,6 +258,10 @@ export function EnableApp(arg1, arg2) {
   return window['go']['main']['App']['EnableApp'](arg1, arg2);
 }
 
+export function EnableDemoMode(arg1, arg2) {
+  return window['go']['main']['App']['EnableDemoMode'](arg1, arg2);
+}
+
 export function ExecuteBatchOperation(arg1) {
   return window['go']['main']['App']['ExecuteBatchOperation'](arg1);
 }
@@ -82,10 +274,34 @@ export function ExecuteSingleWorkflowStep(arg1, arg2) {
   return window['go']['main']['App']['ExecuteSingleWorkflowStep'](arg1, arg2);
 }
 
+export function ExpandNotificationShade(arg1) {
+  return window['go']['main']['App']['ExpandNotificationShade'](arg1);
+}
+
 export function ExportAPK(arg1, arg2) {
   return window['go']['main']['App']['ExportAPK'](arg1, arg2);
 }
 
+export function ExportSecurityReport(arg1, arg2) {
+  return window['go']['main']['App']['ExportSecurityReport'](arg1, arg2);
+}
+
+export function ExportTouchScript(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportTouchScript'](arg1, arg2, arg3);
+}
+
+export function ExportWorkflowRunHTML(arg1, arg2) {
+  return window['go']['main']['App']['ExportWorkflowRunHTML'](arg1, arg2);
+}
+
+export function ExtractAbToTar(arg1, arg2) {
+  return window['go']['main']['App']['ExtractAbToTar'](arg1, arg2);
+}
+
+export function FastbootReboot(arg1, arg2) {
+  return window['go']['main']['App']['FastbootReboot'](arg1, arg2);
+}
+
 export function FindAllElementsBySelector(arg1, arg2) {
   return window['go']['main']['App']['FindAllElementsBySelector'](arg1, arg2);
 }
@@ -102,6 +318,10 @@ export function FindElementBySelector(arg1, arg2) {
   return window['go']['main']['App']['FindElementBySelector'](arg1, arg2);
 }
 
+export function FlashPartition(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['FlashPartition'](arg1, arg2, arg3, arg4);
+}
+
 export function ForceStopApp(arg1, arg2) {
   return window['go']['main']['App']['ForceStopApp'](arg1, arg2);
 }
@@ -110,14 +330,34 @@ export function GenerateSelectorSuggestions(arg1, arg2) {
   return window['go']['main']['App']['GenerateSelectorSuggestions'](arg1, arg2);
 }
 
+export function GetActivityStack(arg1) {
+  return window['go']['main']['App']['GetActivityStack'](arg1);
+}
+
+export function GetAnimationScales(arg1) {
+  return window['go']['main']['App']['GetAnimationScales'](arg1);
+}
+
 export function GetAppInfo(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetAppInfo'](arg1, arg2, arg3);
 }
 
+export function GetAppMemoryInfo(arg1, arg2) {
+  return window['go']['main']['App']['GetAppMemoryInfo'](arg1, arg2);
+}
+
+export function GetAppStandbyBucket(arg1, arg2) {
+  return window['go']['main']['App']['GetAppStandbyBucket'](arg1, arg2);
+}
+
 export function GetAppVersion() {
   return window['go']['main']['App']['GetAppVersion']();
 }
 
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
 export function GetBackendLogs() {
   return window['go']['main']['App']['GetBackendLogs']();
 }
@@ -126,6 +366,34 @@ export function GetBestSelector(arg1, arg2) {
   return window['go']['main']['App']['GetBestSelector'](arg1, arg2);
 }
 
+export function GetClassifierConfig() {
+  return window['go']['main']['App']['GetClassifierConfig']();
+}
+
+export function GetCurrentActivity(arg1) {
+  return window['go']['main']['App']['GetCurrentActivity'](arg1);
+}
+
+export function GetDangerousCommandPolicy() {
+  return window['go']['main']['App']['GetDangerousCommandPolicy']();
+}
+
+export function GetDefaultScrcpyConfig(arg1) {
+  return window['go']['main']['App']['GetDefaultScrcpyConfig'](arg1);
+}
+
+export function GetDefaultScrcpyPreset(arg1) {
+  return window['go']['main']['App']['GetDefaultScrcpyPreset'](arg1);
+}
+
+export function GetDeveloperToggles(arg1) {
+  return window['go']['main']['App']['GetDeveloperToggles'](arg1);
+}
+
+export function GetDeviceClipboard(arg1) {
+  return window['go']['main']['App']['GetDeviceClipboard'](arg1);
+}
+
 export function GetDeviceIP(arg1) {
   return window['go']['main']['App']['GetDeviceIP'](arg1);
 }
@@ -142,6 +410,14 @@ export function GetDevices(arg1) {
   return window['go']['main']['App']['GetDevices'](arg1);
 }
 
+export function GetDisplaySettings(arg1) {
+  return window['go']['main']['App']['GetDisplaySettings'](arg1);
+}
+
+export function GetDozeState(arg1) {
+  return window['go']['main']['App']['GetDozeState'](arg1);
+}
+
 export function GetElementProperties(arg1, arg2) {
   return window['go']['main']['App']['GetElementProperties'](arg1, arg2);
 }
@@ -150,18 +426,82 @@ export function GetElementsWithText(arg1, arg2) {
   return window['go']['main']['App']['GetElementsWithText'](arg1, arg2);
 }
 
+export function GetFastbootVars(arg1) {
+  return window['go']['main']['App']['GetFastbootVars'](arg1);
+}
+
+export function GetFrameStats(arg1, arg2) {
+  return window['go']['main']['App']['GetFrameStats'](arg1, arg2);
+}
+
+export function GetGlobalProxy(arg1) {
+  return window['go']['main']['App']['GetGlobalProxy'](arg1);
+}
+
 export function GetHistoryDevices() {
   return window['go']['main']['App']['GetHistoryDevices']();
 }
 
+export function GetInstrumentationSummary(arg1) {
+  return window['go']['main']['App']['GetInstrumentationSummary'](arg1);
+}
+
 export function GetLocalIP() {
   return window['go']['main']['App']['GetLocalIP']();
 }
 
+export function GetLocaleAndTime(arg1) {
+  return window['go']['main']['App']['GetLocaleAndTime'](arg1);
+}
+
+export function GetLogBufferSize(arg1) {
+  return window['go']['main']['App']['GetLogBufferSize'](arg1);
+}
+
+export function GetLogcatStats(arg1) {
+  return window['go']['main']['App']['GetLogcatStats'](arg1);
+}
+
+export function GetLogcatStatuses(arg1) {
+  return window['go']['main']['App']['GetLogcatStatuses'](arg1);
+}
+
 export function GetMITMBypassPatterns() {
   return window['go']['main']['App']['GetMITMBypassPatterns']();
 }
 
+export function GetMonkeyResult(arg1) {
+  return window['go']['main']['App']['GetMonkeyResult'](arg1);
+}
+
+export function GetNetworkStats(arg1) {
+  return window['go']['main']['App']['GetNetworkStats'](arg1);
+}
+
+export function GetNodePath(arg1, arg2) {
+  return window['go']['main']['App']['GetNodePath'](arg1, arg2);
+}
+
+export function GetOrientation(arg1) {
+  return window['go']['main']['App']['GetOrientation'](arg1);
+}
+
+export function GetPacketCaptureStatus(arg1) {
+  return window['go']['main']['App']['GetPacketCaptureStatus'](arg1);
+}
+
+export function GetPlaybackHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetPlaybackHistory'](arg1, arg2);
+}
+
+export function GetPlaybackRunDetail(arg1) {
+  return window['go']['main']['App']['GetPlaybackRunDetail'](arg1);
+}
+
+export function GetPreviewSizeCap() {
+  return window['go']['main']['App']['GetPreviewSizeCap']();
+}
+
 export function GetProxySettings() {
   return window['go']['main']['App']['GetProxySettings']();
 }
@@ -170,6 +510,14 @@ export function GetProxyStatus() {
   return window['go']['main']['App']['GetProxyStatus']();
 }
 
+export function GetRadioStates(arg1) {
+  return window['go']['main']['App']['GetRadioStates'](arg1);
+}
+
+export function GetRecentPaths(arg1) {
+  return window['go']['main']['App']['GetRecentPaths'](arg1);
+}
+
 export function GetRecordingEventCount(arg1) {
   return window['go']['main']['App']['GetRecordingEventCount'](arg1);
 }
@@ -178,10 +526,42 @@ export function GetRecordingStatus(arg1) {
   return window['go']['main']['App']['GetRecordingStatus'](arg1);
 }
 
+export function GetRecordingsDir() {
+  return window['go']['main']['App']['GetRecordingsDir']();
+}
+
+export function GetResourceHistory(arg1) {
+  return window['go']['main']['App']['GetResourceHistory'](arg1);
+}
+
+export function GetScheduledAlarms(arg1, arg2) {
+  return window['go']['main']['App']['GetScheduledAlarms'](arg1, arg2);
+}
+
+export function GetScheduledJobs(arg1, arg2) {
+  return window['go']['main']['App']['GetScheduledJobs'](arg1, arg2);
+}
+
+export function GetSecurityReport(arg1) {
+  return window['go']['main']['App']['GetSecurityReport'](arg1);
+}
+
 export function GetSelectorMatchCount(arg1, arg2) {
   return window['go']['main']['App']['GetSelectorMatchCount'](arg1, arg2);
 }
 
+export function GetSettings(arg1, arg2) {
+  return window['go']['main']['App']['GetSettings'](arg1, arg2);
+}
+
+export function GetShellHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetShellHistory'](arg1, arg2);
+}
+
+export function GetSystemTraceStatus(arg1) {
+  return window['go']['main']['App']['GetSystemTraceStatus'](arg1);
+}
+
 export function GetThumbnail(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetThumbnail'](arg1, arg2, arg3);
 }
@@ -190,14 +570,34 @@ export function GetTouchInputDevice(arg1) {
   return window['go']['main']['App']['GetTouchInputDevice'](arg1);
 }
 
-export function GetUIHierarchy(arg1) {
-  return window['go']['main']['App']['GetUIHierarchy'](arg1);
+export function GetUIHierarchy(arg1, arg2) {
+  return window['go']['main']['App']['GetUIHierarchy'](arg1, arg2);
+}
+
+export function GetUISnapshot(arg1) {
+  return window['go']['main']['App']['GetUISnapshot'](arg1);
+}
+
+export function GetWifiInfo(arg1) {
+  return window['go']['main']['App']['GetWifiInfo'](arg1);
+}
+
+export function GetWorkflowReportLimit() {
+  return window['go']['main']['App']['GetWorkflowReportLimit']();
+}
+
+export function GetWorkflowRunReport(arg1) {
+  return window['go']['main']['App']['GetWorkflowRunReport'](arg1);
 }
 
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportTouchScript(arg1) {
+  return window['go']['main']['App']['ImportTouchScript'](arg1);
+}
+
 export function InputNodeText(arg1, arg2, arg3) {
   return window['go']['main']['App']['InputNodeText'](arg1, arg2, arg3);
 }
@@ -206,6 +606,14 @@ export function InputTextToElement(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['InputTextToElement'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
+export function InsertTouchScriptEvent(arg1, arg2, arg3) {
+  return window['go']['main']['App']['InsertTouchScriptEvent'](arg1, arg2, arg3);
+}
+
+export function InspectSideloadPackage(arg1) {
+  return window['go']['main']['App']['InspectSideloadPackage'](arg1);
+}
+
 export function InstallAPK(arg1, arg2) {
   return window['go']['main']['App']['InstallAPK'](arg1, arg2);
 }
@@ -214,10 +622,26 @@ export function InstallProxyCert(arg1) {
   return window['go']['main']['App']['InstallProxyCert'](arg1);
 }
 
+export function InvalidateUIDump(arg1) {
+  return window['go']['main']['App']['InvalidateUIDump'](arg1);
+}
+
 export function IsAppRunning(arg1, arg2) {
   return window['go']['main']['App']['IsAppRunning'](arg1, arg2);
 }
 
+export function IsDemoModeActive(arg1) {
+  return window['go']['main']['App']['IsDemoModeActive'](arg1);
+}
+
+export function IsKeyboardPassthroughActive(arg1) {
+  return window['go']['main']['App']['IsKeyboardPassthroughActive'](arg1);
+}
+
+export function IsMethodTracing(arg1) {
+  return window['go']['main']['App']['IsMethodTracing'](arg1);
+}
+
 export function IsPlayingTouch(arg1) {
   return window['go']['main']['App']['IsPlayingTouch'](arg1);
 }
@@ -230,122 +654,394 @@ export function IsRecordingTouch(arg1) {
   return window['go']['main']['App']['IsRecordingTouch'](arg1);
 }
 
-export function ListCameras(arg1) {
-  return window['go']['main']['App']['ListCameras'](arg1);
+export function KillProcess(arg1, arg2, arg3) {
+  return window['go']['main']['App']['KillProcess'](arg1, arg2, arg3);
+}
+
+export function ListAnrTraces(arg1) {
+  return window['go']['main']['App']['ListAnrTraces'](arg1);
+}
+
+export function ListDeepLinkHandlers(arg1, arg2) {
+  return window['go']['main']['App']['ListDeepLinkHandlers'](arg1, arg2);
+}
+
+export function ListDisplayProfiles() {
+  return window['go']['main']['App']['ListDisplayProfiles']();
 }
 
 export function ListDisplays(arg1) {
   return window['go']['main']['App']['ListDisplays'](arg1);
 }
 
+export function ListFastbootDevices() {
+  return window['go']['main']['App']['ListFastbootDevices']();
+}
+
 export function ListFiles(arg1, arg2) {
   return window['go']['main']['App']['ListFiles'](arg1, arg2);
 }
 
-export function ListPackages(arg1, arg2) {
-  return window['go']['main']['App']['ListPackages'](arg1, arg2);
+export function ListInstrumentations(arg1) {
+  return window['go']['main']['App']['ListInstrumentations'](arg1);
 }
 
-export function LoadScriptTasks() {
-  return window['go']['main']['App']['LoadScriptTasks']();
+export function ListIntentPresets() {
+  return window['go']['main']['App']['ListIntentPresets']();
 }
 
-export function LoadTouchScripts() {
-  return window['go']['main']['App']['LoadTouchScripts']();
+export function ListKeyCodes() {
+  return window['go']['main']['App']['ListKeyCodes']();
 }
 
-export function LoadWorkflows() {
-  return window['go']['main']['App']['LoadWorkflows']();
+export function ListLogcatSessions() {
+  return window['go']['main']['App']['ListLogcatSessions']();
 }
 
-export function Log(arg1, arg2) {
-  return window['go']['main']['App']['Log'](arg1, arg2);
+export function ListManagedProcesses() {
+  return window['go']['main']['App']['ListManagedProcesses']();
 }
 
-export function LongClickElement(arg1, arg2, arg3, arg4, arg5) {
-  return window['go']['main']['App']['LongClickElement'](arg1, arg2, arg3, arg4, arg5);
+export function ListNotifications(arg1) {
+  return window['go']['main']['App']['ListNotifications'](arg1);
 }
 
-export function LongPressAtCoordinates(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['LongPressAtCoordinates'](arg1, arg2, arg3, arg4);
+export function ListPackages(arg1, arg2) {
+  return window['go']['main']['App']['ListPackages'](arg1, arg2);
 }
 
-export function Mkdir(arg1, arg2) {
-  return window['go']['main']['App']['Mkdir'](arg1, arg2);
+export function ListPathBookmarks(arg1) {
+  return window['go']['main']['App']['ListPathBookmarks'](arg1);
 }
 
-export function MoveFile(arg1, arg2, arg3) {
-  return window['go']['main']['App']['MoveFile'](arg1, arg2, arg3);
+export function ListPortForwardFavorites(arg1) {
+  return window['go']['main']['App']['ListPortForwardFavorites'](arg1);
 }
 
-export function OpenFileOnHost(arg1, arg2) {
-  return window['go']['main']['App']['OpenFileOnHost'](arg1, arg2);
+export function ListPortForwards(arg1) {
+  return window['go']['main']['App']['ListPortForwards'](arg1);
 }
 
-export function OpenPath(arg1) {
-  return window['go']['main']['App']['OpenPath'](arg1);
+export function ListProcesses(arg1) {
+  return window['go']['main']['App']['ListProcesses'](arg1);
 }
 
-export function OpenSettings(arg1, arg2, arg3) {
-  return window['go']['main']['App']['OpenSettings'](arg1, arg2, arg3);
+export function ListRecordings() {
+  return window['go']['main']['App']['ListRecordings']();
 }
 
-export function PauseTask(arg1) {
-  return window['go']['main']['App']['PauseTask'](arg1);
+export function ListScheduledPlaybacks() {
+  return window['go']['main']['App']['ListScheduledPlaybacks']();
 }
 
-export function PerformNodeAction(arg1, arg2, arg3) {
-  return window['go']['main']['App']['PerformNodeAction'](arg1, arg2, arg3);
+export function ListScrcpyCameras(arg1) {
+  return window['go']['main']['App']['ListScrcpyCameras'](arg1);
 }
 
-export function PickPointOnScreen(arg1, arg2) {
-  return window['go']['main']['App']['PickPointOnScreen'](arg1, arg2);
+export function ListScrcpyPresets() {
+  return window['go']['main']['App']['ListScrcpyPresets']();
 }
 
-export function PlayTouchScript(arg1, arg2) {
-  return window['go']['main']['App']['PlayTouchScript'](arg1, arg2);
+export function ListScrcpySessions() {
+  return window['go']['main']['App']['ListScrcpySessions']();
 }
 
-export function RemoveHistoryDevice(arg1) {
-  return window['go']['main']['App']['RemoveHistoryDevice'](arg1);
+export function ListSettingsSnapshots(arg1) {
+  return window['go']['main']['App']['ListSettingsSnapshots'](arg1);
 }
 
-export function RenameTouchScript(arg1, arg2) {
-  return window['go']['main']['App']['RenameTouchScript'](arg1, arg2);
+export function ListShellSnippets() {
+  return window['go']['main']['App']['ListShellSnippets']();
+}
+
+export function ListTombstones(arg1) {
+  return window['go']['main']['App']['ListTombstones'](arg1);
+}
+
+export function ListTouchScripts(arg1) {
+  return window['go']['main']['App']['ListTouchScripts'](arg1);
+}
+
+export function ListUISnapshots() {
+  return window['go']['main']['App']['ListUISnapshots']();
+}
+
+export function ListWorkflowRuns(arg1) {
+  return window['go']['main']['App']['ListWorkflowRuns'](arg1);
+}
+
+export function ListWorkflowTriggers() {
+  return window['go']['main']['App']['ListWorkflowTriggers']();
+}
+
+export function LoadScriptTasks() {
+  return window['go']['main']['App']['LoadScriptTasks']();
+}
+
+export function LoadTouchScripts() {
+  return window['go']['main']['App']['LoadTouchScripts']();
+}
+
+export function LoadWorkflows() {
+  return window['go']['main']['App']['LoadWorkflows']();
+}
+
+export function Log(arg1, arg2) {
+  return window['go']['main']['App']['Log'](arg1, arg2);
+}
+
+export function LongClickElement(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['LongClickElement'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function LongPressAtCoordinates(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['LongPressAtCoordinates'](arg1, arg2, arg3, arg4);
+}
+
+export function Mkdir(arg1, arg2) {
+  return window['go']['main']['App']['Mkdir'](arg1, arg2);
+}
+
+export function MoveFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MoveFile'](arg1, arg2, arg3);
+}
+
+export function MoveRemotePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MoveRemotePath'](arg1, arg2, arg3);
+}
+
+export function MoveTouchScriptToFolder(arg1, arg2) {
+  return window['go']['main']['App']['MoveTouchScriptToFolder'](arg1, arg2);
+}
+
+export function OpenFileOnHost(arg1, arg2) {
+  return window['go']['main']['App']['OpenFileOnHost'](arg1, arg2);
+}
+
+export function OpenPath(arg1) {
+  return window['go']['main']['App']['OpenPath'](arg1);
+}
+
+export function OpenRemoteFile(arg1, arg2) {
+  return window['go']['main']['App']['OpenRemoteFile'](arg1, arg2);
+}
+
+export function OpenSettings(arg1, arg2, arg3) {
+  return window['go']['main']['App']['OpenSettings'](arg1, arg2, arg3);
+}
+
+export function OpenShellSession(arg1) {
+  return window['go']['main']['App']['OpenShellSession'](arg1);
+}
+
+export function PauseTask(arg1) {
+  return window['go']['main']['App']['PauseTask'](arg1);
+}
+
+export function PauseTouchPlayback(arg1) {
+  return window['go']['main']['App']['PauseTouchPlayback'](arg1);
+}
+
+export function PerformNodeAction(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PerformNodeAction'](arg1, arg2, arg3);
+}
+
+export function PickPointOnScreen(arg1, arg2) {
+  return window['go']['main']['App']['PickPointOnScreen'](arg1, arg2);
+}
+
+export function PlayLocationRoute(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PlayLocationRoute'](arg1, arg2, arg3);
+}
+
+export function PlayTouchScript(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PlayTouchScript'](arg1, arg2, arg3);
+}
+
+export function PlayTouchScriptOnDevices(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PlayTouchScriptOnDevices'](arg1, arg2, arg3);
+}
+
+export function PreviewRemoteFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewRemoteFile'](arg1, arg2, arg3);
+}
+
+export function PullCameraRoll(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PullCameraRoll'](arg1, arg2, arg3);
+}
+
+export function PullFile(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['PullFile'](arg1, arg2, arg3, arg4);
+}
+
+export function PullTrace(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PullTrace'](arg1, arg2, arg3);
+}
+
+export function PushDroppedFiles(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['PushDroppedFiles'](arg1, arg2, arg3, arg4);
+}
+
+export function PushFile(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['PushFile'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function PutSetting(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['PutSetting'](arg1, arg2, arg3, arg4);
+}
+
+export function RebootToSideload(arg1) {
+  return window['go']['main']['App']['RebootToSideload'](arg1);
+}
+
+export function RemoveAllPortForwards(arg1) {
+  return window['go']['main']['App']['RemoveAllPortForwards'](arg1);
+}
+
+export function RemoveHistoryDevice(arg1) {
+  return window['go']['main']['App']['RemoveHistoryDevice'](arg1);
+}
+
+export function RemovePathBookmark(arg1, arg2) {
+  return window['go']['main']['App']['RemovePathBookmark'](arg1, arg2);
+}
+
+export function RemovePortForward(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RemovePortForward'](arg1, arg2, arg3);
+}
+
+export function RemovePortForwardFavorite(arg1, arg2) {
+  return window['go']['main']['App']['RemovePortForwardFavorite'](arg1, arg2);
+}
+
+export function RemoveWorkflowTrigger(arg1) {
+  return window['go']['main']['App']['RemoveWorkflowTrigger'](arg1);
+}
+
+export function RenameRemotePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameRemotePath'](arg1, arg2, arg3);
+}
+
+export function RenameTouchScript(arg1, arg2) {
+  return window['go']['main']['App']['RenameTouchScript'](arg1, arg2);
+}
+
+export function ResetFrameStats(arg1, arg2) {
+  return window['go']['main']['App']['ResetFrameStats'](arg1, arg2);
+}
+
+export function ResetLogcatStats(arg1) {
+  return window['go']['main']['App']['ResetLogcatStats'](arg1);
+}
+
+export function ResizeShell(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ResizeShell'](arg1, arg2, arg3);
 }
 
 export function RestartAdbServer() {
   return window['go']['main']['App']['RestartAdbServer']();
 }
 
+export function RestoreAnimations(arg1) {
+  return window['go']['main']['App']['RestoreAnimations'](arg1);
+}
+
+export function RestoreApp(arg1, arg2) {
+  return window['go']['main']['App']['RestoreApp'](arg1, arg2);
+}
+
+export function RestoreDisplayProfile(arg1) {
+  return window['go']['main']['App']['RestoreDisplayProfile'](arg1);
+}
+
 export function ResumeTask(arg1) {
   return window['go']['main']['App']['ResumeTask'](arg1);
 }
 
+export function ResumeTouchPlayback(arg1) {
+  return window['go']['main']['App']['ResumeTouchPlayback'](arg1);
+}
+
 export function RunAdbCommand(arg1, arg2) {
   return window['go']['main']['App']['RunAdbCommand'](arg1, arg2);
 }
 
+export function RunAdbCommandConfirmed(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunAdbCommandConfirmed'](arg1, arg2, arg3);
+}
+
+export function RunAdbCommandStreamed(arg1, arg2) {
+  return window['go']['main']['App']['RunAdbCommandStreamed'](arg1, arg2);
+}
+
+export function RunInstrumentationTests(arg1, arg2) {
+  return window['go']['main']['App']['RunInstrumentationTests'](arg1, arg2);
+}
+
+export function RunIntentPreset(arg1, arg2) {
+  return window['go']['main']['App']['RunIntentPreset'](arg1, arg2);
+}
+
+export function RunJobNow(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunJobNow'](arg1, arg2, arg3);
+}
+
 export function RunScriptTask(arg1, arg2) {
   return window['go']['main']['App']['RunScriptTask'](arg1, arg2);
 }
 
-export function RunWorkflow(arg1, arg2) {
-  return window['go']['main']['App']['RunWorkflow'](arg1, arg2);
+export function RunShellCommand(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunShellCommand'](arg1, arg2, arg3);
+}
+
+export function RunWorkflow(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunWorkflow'](arg1, arg2, arg3);
+}
+
+export function RunWorkflowWithDataset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunWorkflowWithDataset'](arg1, arg2, arg3);
+}
+
+export function SaveDisplayProfile(arg1) {
+  return window['go']['main']['App']['SaveDisplayProfile'](arg1);
+}
+
+export function SaveIntentPreset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveIntentPreset'](arg1, arg2, arg3);
+}
+
+export function SavePortForwardFavorite(arg1, arg2) {
+  return window['go']['main']['App']['SavePortForwardFavorite'](arg1, arg2);
+}
+
+export function SaveScrcpyPreset(arg1, arg2) {
+  return window['go']['main']['App']['SaveScrcpyPreset'](arg1, arg2);
 }
 
 export function SaveScriptTask(arg1) {
   return window['go']['main']['App']['SaveScriptTask'](arg1);
 }
 
-export function SaveTouchScript(arg1) {
-  return window['go']['main']['App']['SaveTouchScript'](arg1);
+export function SaveShellSnippet(arg1, arg2) {
+  return window['go']['main']['App']['SaveShellSnippet'](arg1, arg2);
+}
+
+export function SaveTouchScript(arg1, arg2) {
+  return window['go']['main']['App']['SaveTouchScript'](arg1, arg2);
+}
+
+export function SaveUISnapshot(arg1, arg2) {
+  return window['go']['main']['App']['SaveUISnapshot'](arg1, arg2);
 }
 
 export function SaveWorkflow(arg1) {
   return window['go']['main']['App']['SaveWorkflow'](arg1);
 }
 
+export function SchedulePlayback(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SchedulePlayback'](arg1, arg2, arg3);
+}
+
 export function ScrollToElement(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['ScrollToElement'](arg1, arg2, arg3, arg4, arg5);
 }
@@ -358,6 +1054,10 @@ export function SearchElementsXPath(arg1, arg2) {
   return window['go']['main']['App']['SearchElementsXPath'](arg1, arg2);
 }
 
+export function SearchShellHistory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchShellHistory'](arg1, arg2, arg3);
+}
+
 export function SearchUIElements(arg1, arg2) {
   return window['go']['main']['App']['SearchUIElements'](arg1, arg2);
 }
@@ -378,14 +1078,134 @@ export function SelectScreenshotPath(arg1) {
   return window['go']['main']['App']['SelectScreenshotPath'](arg1);
 }
 
+export function SelectWorkflowDataset() {
+  return window['go']['main']['App']['SelectWorkflowDataset']();
+}
+
+export function SendBroadcast(arg1, arg2) {
+  return window['go']['main']['App']['SendBroadcast'](arg1, arg2);
+}
+
+export function SendKeyCombo(arg1, arg2) {
+  return window['go']['main']['App']['SendKeyCombo'](arg1, arg2);
+}
+
+export function SendKeyEvent(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SendKeyEvent'](arg1, arg2, arg3);
+}
+
+export function SetAirplaneMode(arg1, arg2) {
+  return window['go']['main']['App']['SetAirplaneMode'](arg1, arg2);
+}
+
+export function SetAnimationScales(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetAnimationScales'](arg1, arg2, arg3, arg4);
+}
+
+export function SetAppStandbyBucket(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetAppStandbyBucket'](arg1, arg2, arg3);
+}
+
+export function SetBluetooth(arg1, arg2) {
+  return window['go']['main']['App']['SetBluetooth'](arg1, arg2);
+}
+
+export function SetClassifierConfig(arg1) {
+  return window['go']['main']['App']['SetClassifierConfig'](arg1);
+}
+
+export function SetDarkMode(arg1, arg2) {
+  return window['go']['main']['App']['SetDarkMode'](arg1, arg2);
+}
+
+export function SetDefaultScrcpyPreset(arg1, arg2) {
+  return window['go']['main']['App']['SetDefaultScrcpyPreset'](arg1, arg2);
+}
+
+export function SetDeveloperToggle(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetDeveloperToggle'](arg1, arg2, arg3);
+}
+
+export function SetDeviceClipboard(arg1, arg2) {
+  return window['go']['main']['App']['SetDeviceClipboard'](arg1, arg2);
+}
+
+export function SetDeviceLocale(arg1, arg2) {
+  return window['go']['main']['App']['SetDeviceLocale'](arg1, arg2);
+}
+
 export function SetDeviceNetworkLimit(arg1, arg2) {
   return window['go']['main']['App']['SetDeviceNetworkLimit'](arg1, arg2);
 }
 
+export function SetDeviceTime(arg1, arg2) {
+  return window['go']['main']['App']['SetDeviceTime'](arg1, arg2);
+}
+
+export function SetDisplayDensity(arg1, arg2) {
+  return window['go']['main']['App']['SetDisplayDensity'](arg1, arg2);
+}
+
+export function SetDisplayPower(arg1, arg2) {
+  return window['go']['main']['App']['SetDisplayPower'](arg1, arg2);
+}
+
+export function SetDisplaySize(arg1, arg2) {
+  return window['go']['main']['App']['SetDisplaySize'](arg1, arg2);
+}
+
+export function SetDozeMode(arg1, arg2) {
+  return window['go']['main']['App']['SetDozeMode'](arg1, arg2);
+}
+
+export function SetDozeWhitelist(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetDozeWhitelist'](arg1, arg2, arg3);
+}
+
+export function SetFontScale(arg1, arg2) {
+  return window['go']['main']['App']['SetFontScale'](arg1, arg2);
+}
+
+export function SetGlobalProxy(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetGlobalProxy'](arg1, arg2, arg3);
+}
+
+export function SetLogBufferSize(arg1, arg2) {
+  return window['go']['main']['App']['SetLogBufferSize'](arg1, arg2);
+}
+
+export function SetLogcatBuffers(arg1, arg2) {
+  return window['go']['main']['App']['SetLogcatBuffers'](arg1, arg2);
+}
+
+export function SetLogcatStatsEvents(arg1, arg2) {
+  return window['go']['main']['App']['SetLogcatStatsEvents'](arg1, arg2);
+}
+
 export function SetMITMBypassPatterns(arg1) {
   return window['go']['main']['App']['SetMITMBypassPatterns'](arg1);
 }
 
+export function SetMobileData(arg1, arg2) {
+  return window['go']['main']['App']['SetMobileData'](arg1, arg2);
+}
+
+export function SetMockLocation(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['SetMockLocation'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SetMockLocationApp(arg1, arg2) {
+  return window['go']['main']['App']['SetMockLocationApp'](arg1, arg2);
+}
+
+export function SetOrientation(arg1, arg2) {
+  return window['go']['main']['App']['SetOrientation'](arg1, arg2);
+}
+
+export function SetPreviewSizeCap(arg1) {
+  return window['go']['main']['App']['SetPreviewSizeCap'](arg1);
+}
+
 export function SetProxyLatency(arg1) {
   return window['go']['main']['App']['SetProxyLatency'](arg1);
 }
@@ -402,30 +1222,106 @@ export function SetProxyWSEnabled(arg1) {
   return window['go']['main']['App']['SetProxyWSEnabled'](arg1);
 }
 
+export function SetRecordingsDir(arg1) {
+  return window['go']['main']['App']['SetRecordingsDir'](arg1);
+}
+
+export function SetTimezone(arg1, arg2) {
+  return window['go']['main']['App']['SetTimezone'](arg1, arg2);
+}
+
+export function SetTouchScriptTags(arg1, arg2) {
+  return window['go']['main']['App']['SetTouchScriptTags'](arg1, arg2);
+}
+
+export function SetUIDumpCacheTTL(arg1) {
+  return window['go']['main']['App']['SetUIDumpCacheTTL'](arg1);
+}
+
+export function SetWifiEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetWifiEnabled'](arg1, arg2);
+}
+
+export function SetWorkflowReportLimit(arg1) {
+  return window['go']['main']['App']['SetWorkflowReportLimit'](arg1);
+}
+
+export function SetWorkflowTriggerEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetWorkflowTriggerEnabled'](arg1, arg2);
+}
+
 export function Shutdown(arg1) {
   return window['go']['main']['App']['Shutdown'](arg1);
 }
 
+export function SideloadPackage(arg1, arg2) {
+  return window['go']['main']['App']['SideloadPackage'](arg1, arg2);
+}
+
+export function SnapshotSettings(arg1, arg2) {
+  return window['go']['main']['App']['SnapshotSettings'](arg1, arg2);
+}
+
 export function StartActivity(arg1, arg2) {
   return window['go']['main']['App']['StartActivity'](arg1, arg2);
 }
 
+export function StartActivityWatcher(arg1) {
+  return window['go']['main']['App']['StartActivityWatcher'](arg1);
+}
+
 export function StartApp(arg1, arg2) {
   return window['go']['main']['App']['StartApp'](arg1, arg2);
 }
 
+export function StartAppMemoryTrace(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartAppMemoryTrace'](arg1, arg2, arg3);
+}
+
+export function StartAppNetworkMonitor(arg1, arg2) {
+  return window['go']['main']['App']['StartAppNetworkMonitor'](arg1, arg2);
+}
+
 export function StartDeviceMonitor() {
   return window['go']['main']['App']['StartDeviceMonitor']();
 }
 
-export function StartLogcat(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['StartLogcat'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function StartIntent(arg1, arg2) {
+  return window['go']['main']['App']['StartIntent'](arg1, arg2);
+}
+
+export function StartJankMonitor(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartJankMonitor'](arg1, arg2, arg3);
+}
+
+export function StartKeyboardPassthrough(arg1) {
+  return window['go']['main']['App']['StartKeyboardPassthrough'](arg1);
+}
+
+export function StartLogcatSession(arg1) {
+  return window['go']['main']['App']['StartLogcatSession'](arg1);
+}
+
+export function StartMethodTrace(arg1, arg2) {
+  return window['go']['main']['App']['StartMethodTrace'](arg1, arg2);
+}
+
+export function StartMonkeyTest(arg1, arg2) {
+  return window['go']['main']['App']['StartMonkeyTest'](arg1, arg2);
 }
 
 export function StartNetworkMonitor(arg1) {
   return window['go']['main']['App']['StartNetworkMonitor'](arg1);
 }
 
+export function StartNotificationWatcher(arg1) {
+  return window['go']['main']['App']['StartNotificationWatcher'](arg1);
+}
+
+export function StartPacketCapture(arg1, arg2) {
+  return window['go']['main']['App']['StartPacketCapture'](arg1, arg2);
+}
+
 export function StartProxy(arg1) {
   return window['go']['main']['App']['StartProxy'](arg1);
 }
@@ -434,34 +1330,114 @@ export function StartRecording(arg1, arg2) {
   return window['go']['main']['App']['StartRecording'](arg1, arg2);
 }
 
+export function StartResourceMonitor(arg1, arg2) {
+  return window['go']['main']['App']['StartResourceMonitor'](arg1, arg2);
+}
+
 export function StartScrcpy(arg1, arg2) {
   return window['go']['main']['App']['StartScrcpy'](arg1, arg2);
 }
 
-export function StartTouchRecording(arg1, arg2) {
-  return window['go']['main']['App']['StartTouchRecording'](arg1, arg2);
+export function StartScreenRecording(arg1, arg2) {
+  return window['go']['main']['App']['StartScreenRecording'](arg1, arg2);
+}
+
+export function StartScreenrecord(arg1, arg2) {
+  return window['go']['main']['App']['StartScreenrecord'](arg1, arg2);
+}
+
+export function StartScreenshotBurst(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['StartScreenshotBurst'](arg1, arg2, arg3, arg4);
+}
+
+export function StartService(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartService'](arg1, arg2, arg3);
+}
+
+export function StartSystemTrace(arg1, arg2) {
+  return window['go']['main']['App']['StartSystemTrace'](arg1, arg2);
+}
+
+export function StartThumbnailStream(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartThumbnailStream'](arg1, arg2, arg3);
+}
+
+export function StartTouchRecording(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartTouchRecording'](arg1, arg2, arg3);
 }
 
 export function StartWirelessServer() {
   return window['go']['main']['App']['StartWirelessServer']();
 }
 
+export function StepTouchPlayback(arg1) {
+  return window['go']['main']['App']['StepTouchPlayback'](arg1);
+}
+
+export function StopActivityWatcher(arg1) {
+  return window['go']['main']['App']['StopActivityWatcher'](arg1);
+}
+
+export function StopAllLogcat() {
+  return window['go']['main']['App']['StopAllLogcat']();
+}
+
 export function StopAllNetworkMonitors() {
   return window['go']['main']['App']['StopAllNetworkMonitors']();
 }
 
+export function StopAppMemoryTrace(arg1, arg2) {
+  return window['go']['main']['App']['StopAppMemoryTrace'](arg1, arg2);
+}
+
+export function StopAppNetworkMonitor(arg1) {
+  return window['go']['main']['App']['StopAppNetworkMonitor'](arg1);
+}
+
 export function StopDeviceMonitor() {
   return window['go']['main']['App']['StopDeviceMonitor']();
 }
 
-export function StopLogcat() {
-  return window['go']['main']['App']['StopLogcat']();
+export function StopInstrumentationTests(arg1) {
+  return window['go']['main']['App']['StopInstrumentationTests'](arg1);
+}
+
+export function StopJankMonitor(arg1, arg2) {
+  return window['go']['main']['App']['StopJankMonitor'](arg1, arg2);
+}
+
+export function StopKeyboardPassthrough(arg1) {
+  return window['go']['main']['App']['StopKeyboardPassthrough'](arg1);
+}
+
+export function StopLocationRoute(arg1) {
+  return window['go']['main']['App']['StopLocationRoute'](arg1);
+}
+
+export function StopLogcat(arg1) {
+  return window['go']['main']['App']['StopLogcat'](arg1);
+}
+
+export function StopMethodTrace(arg1, arg2) {
+  return window['go']['main']['App']['StopMethodTrace'](arg1, arg2);
+}
+
+export function StopMonkeyTest(arg1) {
+  return window['go']['main']['App']['StopMonkeyTest'](arg1);
 }
 
 export function StopNetworkMonitor(arg1) {
   return window['go']['main']['App']['StopNetworkMonitor'](arg1);
 }
 
+export function StopNotificationWatcher(arg1) {
+  return window['go']['main']['App']['StopNotificationWatcher'](arg1);
+}
+
+export function StopPacketCapture(arg1) {
+  return window['go']['main']['App']['StopPacketCapture'](arg1);
+}
+
 export function StopProxy() {
   return window['go']['main']['App']['StopProxy']();
 }
@@ -470,20 +1446,44 @@ export function StopRecording(arg1) {
   return window['go']['main']['App']['StopRecording'](arg1);
 }
 
+export function StopResourceMonitor(arg1) {
+  return window['go']['main']['App']['StopResourceMonitor'](arg1);
+}
+
 export function StopScrcpy(arg1) {
   return window['go']['main']['App']['StopScrcpy'](arg1);
 }
 
+export function StopScreenRecording(arg1) {
+  return window['go']['main']['App']['StopScreenRecording'](arg1);
+}
+
+export function StopScreenshotBurst(arg1) {
+  return window['go']['main']['App']['StopScreenshotBurst'](arg1);
+}
+
+export function StopService(arg1, arg2) {
+  return window['go']['main']['App']['StopService'](arg1, arg2);
+}
+
+export function StopSystemTrace(arg1) {
+  return window['go']['main']['App']['StopSystemTrace'](arg1);
+}
+
 export function StopTask(arg1) {
   return window['go']['main']['App']['StopTask'](arg1);
 }
 
+export function StopThumbnailStream(arg1) {
+  return window['go']['main']['App']['StopThumbnailStream'](arg1);
+}
+
 export function StopTouchPlayback(arg1) {
   return window['go']['main']['App']['StopTouchPlayback'](arg1);
 }
 
-export function StopTouchRecording(arg1) {
-  return window['go']['main']['App']['StopTouchRecording'](arg1);
+export function StopTouchRecording(arg1, arg2) {
+  return window['go']['main']['App']['StopTouchRecording'](arg1, arg2);
 }
 
 export function StopWorkflow(arg1) {
@@ -506,30 +1506,82 @@ export function SwitchToWireless(arg1) {
   return window['go']['main']['App']['SwitchToWireless'](arg1);
 }
 
-export function TakeScreenshot(arg1, arg2) {
-  return window['go']['main']['App']['TakeScreenshot'](arg1, arg2);
+export function SyncClipboard(arg1, arg2) {
+  return window['go']['main']['App']['SyncClipboard'](arg1, arg2);
+}
+
+export function SyncFolderFromDevice(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SyncFolderFromDevice'](arg1, arg2, arg3, arg4);
+}
+
+export function SyncFolderToDevice(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SyncFolderToDevice'](arg1, arg2, arg3, arg4);
+}
+
+export function TakeScreenshot(arg1, arg2, arg3) {
+  return window['go']['main']['App']['TakeScreenshot'](arg1, arg2, arg3);
 }
 
 export function TapAtCoordinates(arg1, arg2, arg3) {
   return window['go']['main']['App']['TapAtCoordinates'](arg1, arg2, arg3);
 }
 
+export function TestDeepLink(arg1, arg2, arg3) {
+  return window['go']['main']['App']['TestDeepLink'](arg1, arg2, arg3);
+}
+
+export function TestSelector(arg1, arg2) {
+  return window['go']['main']['App']['TestSelector'](arg1, arg2);
+}
+
 export function TogglePinDevice(arg1) {
   return window['go']['main']['App']['TogglePinDevice'](arg1);
 }
 
+export function TrimTouchScript(arg1, arg2, arg3) {
+  return window['go']['main']['App']['TrimTouchScript'](arg1, arg2, arg3);
+}
+
+export function TypeText(arg1, arg2) {
+  return window['go']['main']['App']['TypeText'](arg1, arg2);
+}
+
 export function UninstallApp(arg1, arg2) {
   return window['go']['main']['App']['UninstallApp'](arg1, arg2);
 }
 
-export function UploadFile(arg1, arg2, arg3) {
-  return window['go']['main']['App']['UploadFile'](arg1, arg2, arg3);
+export function UnlockOrientation(arg1) {
+  return window['go']['main']['App']['UnlockOrientation'](arg1);
+}
+
+export function UpdateDangerousCommandPolicy(arg1) {
+  return window['go']['main']['App']['UpdateDangerousCommandPolicy'](arg1);
+}
+
+export function UpdateLogcatFilter(arg1, arg2) {
+  return window['go']['main']['App']['UpdateLogcatFilter'](arg1, arg2);
 }
 
-export function WaitElementGone(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['WaitElementGone'](arg1, arg2, arg3, arg4);
+export function UpdateTouchScriptEvent(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateTouchScriptEvent'](arg1, arg2, arg3);
+}
+
+export function UploadFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UploadFile'](arg1, arg2, arg3);
 }
 
 export function WaitForElement(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['WaitForElement'](arg1, arg2, arg3, arg4);
 }
+
+export function WaitForElementGone(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['WaitForElementGone'](arg1, arg2, arg3, arg4);
+}
+
+export function WaitForNotification(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WaitForNotification'](arg1, arg2, arg3);
+}
+
+export function WriteToShell(arg1, arg2) {
+  return window['go']['main']['App']['WriteToShell'](arg1, arg2);
+}
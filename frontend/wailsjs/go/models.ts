@@ -1,5 +1,264 @@
 export namespace main {
 	
+	export class ActivityTask {
+	    id: number;
+	    affinity: string;
+	    type?: string;
+	    activities: string[];
+	    focused: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActivityTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.affinity = source["affinity"];
+	        this.type = source["type"];
+	        this.activities = source["activities"];
+	        this.focused = source["focused"];
+	    }
+	}
+	export class ScheduledAlarm {
+	    package: string;
+	    type: string;
+	    tag: string;
+	    triggerIn: string;
+	    triggerAt: number;
+	    window?: string;
+	    repeatInterval?: string;
+	    batch?: string;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduledAlarm(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.type = source["type"];
+	        this.tag = source["tag"];
+	        this.triggerIn = source["triggerIn"];
+	        this.triggerAt = source["triggerAt"];
+	        this.window = source["window"];
+	        this.repeatInterval = source["repeatInterval"];
+	        this.batch = source["batch"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class AlarmPackage {
+	    package: string;
+	    alarms: ScheduledAlarm[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AlarmPackage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.alarms = this.convertValues(source["alarms"], ScheduledAlarm);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnimationScales {
+	    window: number;
+	    transition: number;
+	    animator: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnimationScales(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.window = source["window"];
+	        this.transition = source["transition"];
+	        this.animator = source["animator"];
+	    }
+	}
+	export class MeminfoRow {
+	    name: string;
+	    values: {[key: string]: number};
+	
+	    static createFrom(source: any = {}) {
+	        return new MeminfoRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.values = source["values"];
+	    }
+	}
+	export class AppMemoryInfo {
+	    package: string;
+	    process: string;
+	    pid: number;
+	    timestamp: number;
+	    javaHeap: number;
+	    nativeHeap: number;
+	    code: number;
+	    stack: number;
+	    graphics: number;
+	    privateOther: number;
+	    system: number;
+	    totalPss: number;
+	    totalRss: number;
+	    totalSwapPss: number;
+	    totalPrivateDirty: number;
+	    totalPrivateClean: number;
+	    views: number;
+	    viewRootImpl: number;
+	    activities: number;
+	    appContexts: number;
+	    rows: MeminfoRow[];
+	    summary: {[key: string]: number};
+	    summaryRss: {[key: string]: number};
+	    objects: {[key: string]: number};
+	    sql: {[key: string]: number};
+	    extras: {[key: string]: string};
+	
+	    static createFrom(source: any = {}) {
+	        return new AppMemoryInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.process = source["process"];
+	        this.pid = source["pid"];
+	        this.timestamp = source["timestamp"];
+	        this.javaHeap = source["javaHeap"];
+	        this.nativeHeap = source["nativeHeap"];
+	        this.code = source["code"];
+	        this.stack = source["stack"];
+	        this.graphics = source["graphics"];
+	        this.privateOther = source["privateOther"];
+	        this.system = source["system"];
+	        this.totalPss = source["totalPss"];
+	        this.totalRss = source["totalRss"];
+	        this.totalSwapPss = source["totalSwapPss"];
+	        this.totalPrivateDirty = source["totalPrivateDirty"];
+	        this.totalPrivateClean = source["totalPrivateClean"];
+	        this.views = source["views"];
+	        this.viewRootImpl = source["viewRootImpl"];
+	        this.activities = source["activities"];
+	        this.appContexts = source["appContexts"];
+	        this.rows = this.convertValues(source["rows"], MeminfoRow);
+	        this.summary = source["summary"];
+	        this.summaryRss = source["summaryRss"];
+	        this.objects = source["objects"];
+	        this.sql = source["sql"];
+	        this.extras = source["extras"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AppNetworkUsage {
+	    uid: number;
+	    label: string;
+	    packages: string[];
+	    rxMobile: number;
+	    txMobile: number;
+	    rxWifi: number;
+	    txWifi: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppNetworkUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.label = source["label"];
+	        this.packages = source["packages"];
+	        this.rxMobile = source["rxMobile"];
+	        this.txMobile = source["txMobile"];
+	        this.rxWifi = source["rxWifi"];
+	        this.txWifi = source["txWifi"];
+	    }
+	}
+	export class AppNetworkStats {
+	    deviceId: string;
+	    timestamp: number;
+	    source: string;
+	    rxMobile: number;
+	    txMobile: number;
+	    rxWifi: number;
+	    txWifi: number;
+	    apps: AppNetworkUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AppNetworkStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.timestamp = source["timestamp"];
+	        this.source = source["source"];
+	        this.rxMobile = source["rxMobile"];
+	        this.txMobile = source["txMobile"];
+	        this.rxWifi = source["rxWifi"];
+	        this.txWifi = source["txWifi"];
+	        this.apps = this.convertValues(source["apps"], AppNetworkUsage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class AppPackage {
 	    name: string;
 	    label: string;
@@ -34,6 +293,26 @@ export namespace main {
 	        this.launchableActivities = source["launchableActivities"];
 	    }
 	}
+	export class AuditEntry {
+	    timestamp: number;
+	    action: string;
+	    deviceId?: string;
+	    detail: string;
+	    rule?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timestamp = source["timestamp"];
+	        this.action = source["action"];
+	        this.deviceId = source["deviceId"];
+	        this.detail = source["detail"];
+	        this.rule = source["rule"];
+	    }
+	}
 	export class BatchOperation {
 	    type: string;
 	    deviceIds: string[];
@@ -113,126 +392,2926 @@ export namespace main {
 		}
 	}
 	
-	export class Device {
-	    id: string;
-	    serial: string;
-	    state: string;
-	    model: string;
-	    brand: string;
-	    type: string;
-	    ids: string[];
-	    wifiAddr: string;
-	    lastActive: number;
-	    isPinned: boolean;
+	export class BurstFrame {
+	    index: number;
+	    path: string;
+	    offsetMs: number;
+	    intervalMs: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Device(source);
+	        return new BurstFrame(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.serial = source["serial"];
-	        this.state = source["state"];
-	        this.model = source["model"];
-	        this.brand = source["brand"];
-	        this.type = source["type"];
-	        this.ids = source["ids"];
-	        this.wifiAddr = source["wifiAddr"];
-	        this.lastActive = source["lastActive"];
-	        this.isPinned = source["isPinned"];
+	        this.index = source["index"];
+	        this.path = source["path"];
+	        this.offsetMs = source["offsetMs"];
+	        this.intervalMs = source["intervalMs"];
 	    }
 	}
-	export class DeviceInfo {
-	    model: string;
-	    brand: string;
-	    manufacturer: string;
-	    androidVer: string;
-	    sdk: string;
-	    abi: string;
-	    serial: string;
-	    resolution: string;
-	    density: string;
-	    cpu: string;
-	    memory: string;
-	    props: {[key: string]: string};
+	export class BurstResult {
+	    deviceId: string;
+	    dir: string;
+	    requested: number;
+	    intervalMs: number;
+	    frames: BurstFrame[];
+	    avgIntervalMs: number;
+	    persistentShell: boolean;
+	    cancelled: boolean;
+	    animationPath?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new DeviceInfo(source);
+	        return new BurstResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.model = source["model"];
-	        this.brand = source["brand"];
-	        this.manufacturer = source["manufacturer"];
-	        this.androidVer = source["androidVer"];
-	        this.sdk = source["sdk"];
-	        this.abi = source["abi"];
-	        this.serial = source["serial"];
-	        this.resolution = source["resolution"];
-	        this.density = source["density"];
-	        this.cpu = source["cpu"];
-	        this.memory = source["memory"];
-	        this.props = source["props"];
+	        this.deviceId = source["deviceId"];
+	        this.dir = source["dir"];
+	        this.requested = source["requested"];
+	        this.intervalMs = source["intervalMs"];
+	        this.frames = this.convertValues(source["frames"], BurstFrame);
+	        this.avgIntervalMs = source["avgIntervalMs"];
+	        this.persistentShell = source["persistentShell"];
+	        this.cancelled = source["cancelled"];
+	        this.animationPath = source["animationPath"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ElementActionConfig {
-	    Timeout: number;
-	    RetryInterval: number;
-	    PreWait: number;
-	    PostDelay: number;
-	    OnError: string;
+	export class CameraRollOptions {
+	    since: number;
+	    until: number;
+	    deleteAfter: boolean;
+	    folders: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ElementActionConfig(source);
+	        return new CameraRollOptions(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.Timeout = source["Timeout"];
-	        this.RetryInterval = source["RetryInterval"];
-	        this.PreWait = source["PreWait"];
-	        this.PostDelay = source["PostDelay"];
-	        this.OnError = source["OnError"];
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.deleteAfter = source["deleteAfter"];
+	        this.folders = source["folders"];
 	    }
 	}
-	export class ElementSelector {
-	    type: string;
-	    value: string;
-	    index?: number;
+	export class CameraRollSummary {
+	    transferId: string;
+	    found: number;
+	    new: number;
+	    skipped: number;
+	    failed: number;
+	    failedFiles: string[];
+	    bytes: number;
+	    freedBytes: number;
+	    durationMs: number;
+	    cancelled: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ElementSelector(source);
+	        return new CameraRollSummary(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.type = source["type"];
-	        this.value = source["value"];
-	        this.index = source["index"];
+	        this.transferId = source["transferId"];
+	        this.found = source["found"];
+	        this.new = source["new"];
+	        this.skipped = source["skipped"];
+	        this.failed = source["failed"];
+	        this.failedFiles = source["failedFiles"];
+	        this.bytes = source["bytes"];
+	        this.freedBytes = source["freedBytes"];
+	        this.durationMs = source["durationMs"];
+	        this.cancelled = source["cancelled"];
 	    }
 	}
-	export class ElementInfo {
-	    x: number;
-	    y: number;
-	    class: string;
-	    bounds: string;
-	    selector?: ElementSelector;
-	    timestamp: number;
+	export class ClassifierConfig {
+	    longPressMs: number;
+	    tapMaxDistance: number;
+	    doubleTapMs: number;
+	    doubleTapDistance: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ElementInfo(source);
+	        return new ClassifierConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.x = source["x"];
-	        this.y = source["y"];
+	        this.longPressMs = source["longPressMs"];
+	        this.tapMaxDistance = source["tapMaxDistance"];
+	        this.doubleTapMs = source["doubleTapMs"];
+	        this.doubleTapDistance = source["doubleTapDistance"];
+	    }
+	}
+	export class CurrentActivity {
+	    component: string;
+	    package: string;
+	    activity: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrentActivity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.component = source["component"];
+	        this.package = source["package"];
+	        this.activity = source["activity"];
+	    }
+	}
+	export class DangerousCommandCheck {
+	    dangerous: boolean;
+	    ruleId?: string;
+	    description?: string;
+	    token?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DangerousCommandCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dangerous = source["dangerous"];
+	        this.ruleId = source["ruleId"];
+	        this.description = source["description"];
+	        this.token = source["token"];
+	    }
+	}
+	export class DangerousCommandRule {
+	    id: string;
+	    pattern: string;
+	    description: string;
+	    builtin: boolean;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DangerousCommandRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.pattern = source["pattern"];
+	        this.description = source["description"];
+	        this.builtin = source["builtin"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class DangerousCommandPolicy {
+	    enabled: boolean;
+	    rules: DangerousCommandRule[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DangerousCommandPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.rules = this.convertValues(source["rules"], DangerousCommandRule);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DeepLinkHandler {
+	    package: string;
+	    domain: string;
+	    state: string;
+	    verified: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeepLinkHandler(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.domain = source["domain"];
+	        this.state = source["state"];
+	        this.verified = source["verified"];
+	    }
+	}
+	export class DeepLinkResult {
+	    url: string;
+	    expectedPackage: string;
+	    package: string;
+	    activity: string;
+	    chooser: boolean;
+	    matchesExpected: boolean;
+	    resolveMs: number;
+	    warning?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeepLinkResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.expectedPackage = source["expectedPackage"];
+	        this.package = source["package"];
+	        this.activity = source["activity"];
+	        this.chooser = source["chooser"];
+	        this.matchesExpected = source["matchesExpected"];
+	        this.resolveMs = source["resolveMs"];
+	        this.warning = source["warning"];
+	        this.error = source["error"];
+	    }
+	}
+	export class DeleteSummary {
+	    path: string;
+	    files: number;
+	    dirs: number;
+	    totalSize: number;
+	    dryRun: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeleteSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.files = source["files"];
+	        this.dirs = source["dirs"];
+	        this.totalSize = source["totalSize"];
+	        this.dryRun = source["dryRun"];
+	    }
+	}
+	export class DemoModeOptions {
+	    clock: string;
+	    batteryLevel: number;
+	    charging: boolean;
+	    wifiLevel: number;
+	    mobileLevel: number;
+	    mobileDataType: string;
+	    showNotifications: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DemoModeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.clock = source["clock"];
+	        this.batteryLevel = source["batteryLevel"];
+	        this.charging = source["charging"];
+	        this.wifiLevel = source["wifiLevel"];
+	        this.mobileLevel = source["mobileLevel"];
+	        this.mobileDataType = source["mobileDataType"];
+	        this.showNotifications = source["showNotifications"];
+	    }
+	}
+	export class DeveloperToggle {
+	    name: string;
+	    label: string;
+	    enabled: boolean;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeveloperToggle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.enabled = source["enabled"];
+	        this.value = source["value"];
+	    }
+	}
+	export class Device {
+	    id: string;
+	    serial: string;
+	    state: string;
+	    model: string;
+	    brand: string;
+	    type: string;
+	    ids: string[];
+	    wifiAddr: string;
+	    lastActive: number;
+	    isPinned: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Device(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.serial = source["serial"];
+	        this.state = source["state"];
+	        this.model = source["model"];
+	        this.brand = source["brand"];
+	        this.type = source["type"];
+	        this.ids = source["ids"];
+	        this.wifiAddr = source["wifiAddr"];
+	        this.lastActive = source["lastActive"];
+	        this.isPinned = source["isPinned"];
+	    }
+	}
+	export class DeviceInfo {
+	    model: string;
+	    brand: string;
+	    manufacturer: string;
+	    androidVer: string;
+	    sdk: string;
+	    abi: string;
+	    serial: string;
+	    resolution: string;
+	    density: string;
+	    cpu: string;
+	    memory: string;
+	    props: {[key: string]: string};
+	
+	    static createFrom(source: any = {}) {
+	        return new DeviceInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.brand = source["brand"];
+	        this.manufacturer = source["manufacturer"];
+	        this.androidVer = source["androidVer"];
+	        this.sdk = source["sdk"];
+	        this.abi = source["abi"];
+	        this.serial = source["serial"];
+	        this.resolution = source["resolution"];
+	        this.density = source["density"];
+	        this.cpu = source["cpu"];
+	        this.memory = source["memory"];
+	        this.props = source["props"];
+	    }
+	}
+	export class DisplayProfile {
+	    name: string;
+	    darkMode: string;
+	    fontScale: number;
+	    density: number;
+	    size: string;
+	    updatedAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DisplayProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.darkMode = source["darkMode"];
+	        this.fontScale = source["fontScale"];
+	        this.density = source["density"];
+	        this.size = source["size"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class DisplaySettings {
+	    darkMode: string;
+	    fontScale: number;
+	    density: number;
+	    physicalDensity: number;
+	    size: string;
+	    physicalSize: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DisplaySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.darkMode = source["darkMode"];
+	        this.fontScale = source["fontScale"];
+	        this.density = source["density"];
+	        this.physicalDensity = source["physicalDensity"];
+	        this.size = source["size"];
+	        this.physicalSize = source["physicalSize"];
+	    }
+	}
+	export class DozeState {
+	    deep: string;
+	    light: string;
+	    forced: boolean;
+	    charging: boolean;
+	    userWhitelist: string[];
+	    systemWhitelist: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DozeState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deep = source["deep"];
+	        this.light = source["light"];
+	        this.forced = source["forced"];
+	        this.charging = source["charging"];
+	        this.userWhitelist = source["userWhitelist"];
+	        this.systemWhitelist = source["systemWhitelist"];
+	    }
+	}
+	export class ElementActionConfig {
+	    Timeout: number;
+	    RetryInterval: number;
+	    PreWait: number;
+	    PostDelay: number;
+	    OnError: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementActionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Timeout = source["Timeout"];
+	        this.RetryInterval = source["RetryInterval"];
+	        this.PreWait = source["PreWait"];
+	        this.PostDelay = source["PostDelay"];
+	        this.OnError = source["OnError"];
+	    }
+	}
+	export class ElementImage {
+	    image: string;
+	    bounds: string;
+	    width: number;
+	    height: number;
+	    clamped: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementImage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.image = source["image"];
+	        this.bounds = source["bounds"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.clamped = source["clamped"];
+	    }
+	}
+	export class ElementImageAssertResult {
+	    passed: boolean;
+	    score: number;
+	    threshold: number;
+	    element?: ElementImage;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementImageAssertResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passed = source["passed"];
+	        this.score = source["score"];
+	        this.threshold = source["threshold"];
+	        this.element = this.convertValues(source["element"], ElementImage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ElementSelector {
+	    type: string;
+	    value: string;
+	    index?: number;
+	    within?: ElementSelector;
+	    childIndex?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementSelector(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.value = source["value"];
+	        this.index = source["index"];
+	        this.within = this.convertValues(source["within"], ElementSelector);
+	        this.childIndex = source["childIndex"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ElementInfo {
+	    x: number;
+	    y: number;
+	    class: string;
+	    bounds: string;
+	    selector?: ElementSelector;
+	    timestamp: number;
+	    text?: string;
+	    resourceId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.class = source["class"];
+	        this.bounds = source["bounds"];
+	        this.selector = this.convertValues(source["selector"], ElementSelector);
+	        this.timestamp = source["timestamp"];
+	        this.text = source["text"];
+	        this.resourceId = source["resourceId"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ElementWaitResult {
+	    found: boolean;
+	    bounds?: string;
+	    elapsedMs: number;
+	    polls: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElementWaitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.found = source["found"];
+	        this.bounds = source["bounds"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.polls = source["polls"];
+	    }
+	}
+	export class FastbootVars {
+	    serial: string;
+	    product: string;
+	    unlocked: boolean;
+	    secureBoot: boolean;
+	    currentSlot?: string;
+	    slotCount?: string;
+	    userspace: boolean;
+	    bootloaderVersion?: string;
+	    basebandVersion?: string;
+	    vars: {[key: string]: string};
+	
+	    static createFrom(source: any = {}) {
+	        return new FastbootVars(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.serial = source["serial"];
+	        this.product = source["product"];
+	        this.unlocked = source["unlocked"];
+	        this.secureBoot = source["secureBoot"];
+	        this.currentSlot = source["currentSlot"];
+	        this.slotCount = source["slotCount"];
+	        this.userspace = source["userspace"];
+	        this.bootloaderVersion = source["bootloaderVersion"];
+	        this.basebandVersion = source["basebandVersion"];
+	        this.vars = source["vars"];
+	    }
+	}
+	export class FileInfo {
+	    name: string;
+	    size: number;
+	    mode: string;
+	    owner: string;
+	    group: string;
+	    modTime: string;
+	    isDir: boolean;
+	    isSymlink: boolean;
+	    linkTarget?: string;
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.mode = source["mode"];
+	        this.owner = source["owner"];
+	        this.group = source["group"];
+	        this.modTime = source["modTime"];
+	        this.isDir = source["isDir"];
+	        this.isSymlink = source["isSymlink"];
+	        this.linkTarget = source["linkTarget"];
+	        this.path = source["path"];
+	    }
+	}
+	export class FilePreview {
+	    path: string;
+	    kind: string;
+	    mimeType: string;
+	    content: string;
+	    size: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.mimeType = source["mimeType"];
+	        this.content = source["content"];
+	        this.size = source["size"];
+	        this.truncated = source["truncated"];
+	    }
+	}
+	export class FrameHistogramBucket {
+	    ms: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameHistogramBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ms = source["ms"];
+	        this.count = source["count"];
+	    }
+	}
+	export class FrameTiming {
+	    intendedVsync: number;
+	    durationMs: number;
+	    janky: boolean;
+	    inputMs: number;
+	    animationMs: number;
+	    layoutMs: number;
+	    drawMs: number;
+	    syncMs: number;
+	    gpuMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameTiming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.intendedVsync = source["intendedVsync"];
+	        this.durationMs = source["durationMs"];
+	        this.janky = source["janky"];
+	        this.inputMs = source["inputMs"];
+	        this.animationMs = source["animationMs"];
+	        this.layoutMs = source["layoutMs"];
+	        this.drawMs = source["drawMs"];
+	        this.syncMs = source["syncMs"];
+	        this.gpuMs = source["gpuMs"];
+	    }
+	}
+	export class FrameStats {
+	    package: string;
+	    timestamp: number;
+	    totalFrames: number;
+	    jankyFrames: number;
+	    jankyPercent: number;
+	    p50: number;
+	    p90: number;
+	    p95: number;
+	    p99: number;
+	    missedVsync: number;
+	    highInputLatency: number;
+	    slowUiThread: number;
+	    slowBitmapUploads: number;
+	    slowDrawCommands: number;
+	    frameDeadlineMissed: number;
+	    histogram: FrameHistogramBucket[];
+	    framestatsAvailable: boolean;
+	    frames: FrameTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.timestamp = source["timestamp"];
+	        this.totalFrames = source["totalFrames"];
+	        this.jankyFrames = source["jankyFrames"];
+	        this.jankyPercent = source["jankyPercent"];
+	        this.p50 = source["p50"];
+	        this.p90 = source["p90"];
+	        this.p95 = source["p95"];
+	        this.p99 = source["p99"];
+	        this.missedVsync = source["missedVsync"];
+	        this.highInputLatency = source["highInputLatency"];
+	        this.slowUiThread = source["slowUiThread"];
+	        this.slowBitmapUploads = source["slowBitmapUploads"];
+	        this.slowDrawCommands = source["slowDrawCommands"];
+	        this.frameDeadlineMissed = source["frameDeadlineMissed"];
+	        this.histogram = this.convertValues(source["histogram"], FrameHistogramBucket);
+	        this.framestatsAvailable = source["framestatsAvailable"];
+	        this.frames = this.convertValues(source["frames"], FrameTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class GeoPoint {
+	    lat: number;
+	    lng: number;
+	    alt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GeoPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lat = source["lat"];
+	        this.lng = source["lng"];
+	        this.alt = source["alt"];
+	    }
+	}
+	export class GlobalProxy {
+	    enabled: boolean;
+	    host: string;
+	    port: number;
+	    raw: string;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlobalProxy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.raw = source["raw"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class HeapDumpResult {
+	    package: string;
+	    localPath: string;
+	    size: number;
+	    converted: boolean;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HeapDumpResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.localPath = source["localPath"];
+	        this.size = source["size"];
+	        this.converted = source["converted"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class HistoryDevice {
+	    id: string;
+	    serial: string;
+	    model: string;
+	    brand: string;
+	    type: string;
+	    wifiAddr: string;
+	    lastSeen: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryDevice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.serial = source["serial"];
+	        this.model = source["model"];
+	        this.brand = source["brand"];
+	        this.type = source["type"];
+	        this.wifiAddr = source["wifiAddr"];
+	        this.lastSeen = source["lastSeen"];
+	    }
+	}
+	export class InstrumentationOptions {
+	    runner: string;
+	    class: string;
+	    method: string;
+	    package: string;
+	    args: {[key: string]: string};
+	    pullOutputs: boolean;
+	    outputDir: string;
+	    localDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstrumentationOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.runner = source["runner"];
+	        this.class = source["class"];
+	        this.method = source["method"];
+	        this.package = source["package"];
+	        this.args = source["args"];
+	        this.pullOutputs = source["pullOutputs"];
+	        this.outputDir = source["outputDir"];
+	        this.localDir = source["localDir"];
+	    }
+	}
+	export class InstrumentationTestResult {
+	    class: string;
+	    name: string;
+	    status: string;
+	    stack?: string;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstrumentationTestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.class = source["class"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.stack = source["stack"];
+	        this.durationMs = source["durationMs"];
+	    }
+	}
+	export class InstrumentationSummary {
+	    deviceId: string;
+	    runner: string;
+	    command: string;
+	    state: string;
+	    startedAt: number;
+	    durationMs: number;
+	    total: number;
+	    passed: number;
+	    failed: number;
+	    ignored: number;
+	    tests: InstrumentationTestResult[];
+	    runError?: string;
+	    outputPath?: string;
+	    outputError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstrumentationSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.runner = source["runner"];
+	        this.command = source["command"];
+	        this.state = source["state"];
+	        this.startedAt = source["startedAt"];
+	        this.durationMs = source["durationMs"];
+	        this.total = source["total"];
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.ignored = source["ignored"];
+	        this.tests = this.convertValues(source["tests"], InstrumentationTestResult);
+	        this.runError = source["runError"];
+	        this.outputPath = source["outputPath"];
+	        this.outputError = source["outputError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InstrumentationTarget {
+	    component: string;
+	    testPackage: string;
+	    runner: string;
+	    targetPackage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstrumentationTarget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.component = source["component"];
+	        this.testPackage = source["testPackage"];
+	        this.runner = source["runner"];
+	        this.targetPackage = source["targetPackage"];
+	    }
+	}
+	
+	export class IntentExtra {
+	    key: string;
+	    type: string;
+	    value: string;
+	    values?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IntentExtra(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.type = source["type"];
+	        this.value = source["value"];
+	        this.values = source["values"];
+	    }
+	}
+	export class IntentSpec {
+	    action: string;
+	    data: string;
+	    mimeType: string;
+	    component: string;
+	    package: string;
+	    categories: string[];
+	    flags: string[];
+	    extras: IntentExtra[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IntentSpec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.data = source["data"];
+	        this.mimeType = source["mimeType"];
+	        this.component = source["component"];
+	        this.package = source["package"];
+	        this.categories = source["categories"];
+	        this.flags = source["flags"];
+	        this.extras = this.convertValues(source["extras"], IntentExtra);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IntentPreset {
+	    name: string;
+	    kind: string;
+	    intent: IntentSpec;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntentPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.intent = this.convertValues(source["intent"], IntentSpec);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IntentResult {
+	    command: string;
+	    output: string;
+	    status?: string;
+	    activity?: string;
+	    totalTimeMs?: number;
+	    warning?: string;
+	    error?: string;
+	    broadcastResult?: number;
+	    broadcastData?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntentResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.output = source["output"];
+	        this.status = source["status"];
+	        this.activity = source["activity"];
+	        this.totalTimeMs = source["totalTimeMs"];
+	        this.warning = source["warning"];
+	        this.error = source["error"];
+	        this.broadcastResult = source["broadcastResult"];
+	        this.broadcastData = source["broadcastData"];
+	    }
+	}
+	
+	export class KeyCodeInfo {
+	    name: string;
+	    code: number;
+	    group: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new KeyCodeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.code = source["code"];
+	        this.group = source["group"];
+	    }
+	}
+	export class KillResult {
+	    pid: number;
+	    method: string;
+	    package?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new KillResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.method = source["method"];
+	        this.package = source["package"];
+	    }
+	}
+	export class LocaleTimeChange {
+	    setting: string;
+	    value: string;
+	    method: string;
+	    restartRequired: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocaleTimeChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.setting = source["setting"];
+	        this.value = source["value"];
+	        this.method = source["method"];
+	        this.restartRequired = source["restartRequired"];
+	        this.message = source["message"];
+	    }
+	}
+	export class LocaleTimeInfo {
+	    locale: string;
+	    timezone: string;
+	    deviceTime: number;
+	    clockSkewSec: number;
+	    autoTime: boolean;
+	    autoTimezone: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocaleTimeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.locale = source["locale"];
+	        this.timezone = source["timezone"];
+	        this.deviceTime = source["deviceTime"];
+	        this.clockSkewSec = source["clockSkewSec"];
+	        this.autoTime = source["autoTime"];
+	        this.autoTimezone = source["autoTimezone"];
+	    }
+	}
+	export class LogBufferInfo {
+	    buffer: string;
+	    sizeBytes: number;
+	    consumedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogBufferInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.buffer = source["buffer"];
+	        this.sizeBytes = source["sizeBytes"];
+	        this.consumedBytes = source["consumedBytes"];
+	    }
+	}
+	export class LogcatFilter {
+	    minPriority: string;
+	    tags: string[];
+	    excludeTags: string[];
+	    messageRegex: string;
+	    include: string;
+	    includeRegex: boolean;
+	    exclude: string;
+	    excludeRegex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogcatFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minPriority = source["minPriority"];
+	        this.tags = source["tags"];
+	        this.excludeTags = source["excludeTags"];
+	        this.messageRegex = source["messageRegex"];
+	        this.include = source["include"];
+	        this.includeRegex = source["includeRegex"];
+	        this.exclude = source["exclude"];
+	        this.excludeRegex = source["excludeRegex"];
+	    }
+	}
+	export class TagCount {
+	    tag: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	        this.count = source["count"];
+	    }
+	}
+	export class LogcatStats {
+	    sessionId: string;
+	    total: number;
+	    unparsed: number;
+	    byPriority: {[key: string]: number};
+	    topTags: TagCount[];
+	    tagCount: number;
+	    errorsPerMinute: number;
+	    since: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogcatStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.total = source["total"];
+	        this.unparsed = source["unparsed"];
+	        this.byPriority = source["byPriority"];
+	        this.topTags = this.convertValues(source["topTags"], TagCount);
+	        this.tagCount = source["tagCount"];
+	        this.errorsPerMinute = source["errorsPerMinute"];
+	        this.since = source["since"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LogcatStatus {
+	    sessionId: string;
+	    state: string;
+	    pids: string[];
+	    message: string;
+	    timestamp: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogcatStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.state = source["state"];
+	        this.pids = source["pids"];
+	        this.message = source["message"];
+	        this.timestamp = source["timestamp"];
+	    }
+	}
+	export class ManagedProcess {
+	    pid: number;
+	    kind: string;
+	    command: string;
+	    startedAt: number;
+	    ageMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ManagedProcess(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.kind = source["kind"];
+	        this.command = source["command"];
+	        this.startedAt = source["startedAt"];
+	        this.ageMs = source["ageMs"];
+	    }
+	}
+	
+	export class MonkeyCrash {
+	    type: string;
+	    package: string;
+	    pid: number;
+	    shortMsg: string;
+	    details: string;
+	    atEvent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonkeyCrash(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.package = source["package"];
+	        this.pid = source["pid"];
+	        this.shortMsg = source["shortMsg"];
+	        this.details = source["details"];
+	        this.atEvent = source["atEvent"];
+	    }
+	}
+	export class MonkeyOptions {
+	    packages: string[];
+	    count: number;
+	    throttleMs: number;
+	    seed: number;
+	    percentages: {[key: string]: number};
+	    ignoreCrashes: boolean;
+	    ignoreTimeouts: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonkeyOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.packages = source["packages"];
+	        this.count = source["count"];
+	        this.throttleMs = source["throttleMs"];
+	        this.seed = source["seed"];
+	        this.percentages = source["percentages"];
+	        this.ignoreCrashes = source["ignoreCrashes"];
+	        this.ignoreTimeouts = source["ignoreTimeouts"];
+	    }
+	}
+	export class MonkeyResult {
+	    deviceId: string;
+	    packages: string[];
+	    seed: number;
+	    command: string;
+	    eventsRequested: number;
+	    eventsInjected: number;
+	    state: string;
+	    startedAt: number;
+	    durationMs: number;
+	    crashes: MonkeyCrash[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonkeyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.packages = source["packages"];
+	        this.seed = source["seed"];
+	        this.command = source["command"];
+	        this.eventsRequested = source["eventsRequested"];
+	        this.eventsInjected = source["eventsInjected"];
+	        this.state = source["state"];
+	        this.startedAt = source["startedAt"];
+	        this.durationMs = source["durationMs"];
+	        this.crashes = this.convertValues(source["crashes"], MonkeyCrash);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NodePathStep {
+	    class: string;
+	    index: number;
+	    resourceId?: string;
+	    text?: string;
+	    contentDesc?: string;
+	    bounds: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NodePathStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.class = source["class"];
+	        this.index = source["index"];
+	        this.resourceId = source["resourceId"];
+	        this.text = source["text"];
+	        this.contentDesc = source["contentDesc"];
+	        this.bounds = source["bounds"];
+	        this.label = source["label"];
+	    }
+	}
+	export class NotificationEntry {
+	    key: string;
+	    package: string;
+	    id: number;
+	    tag?: string;
+	    channel?: string;
+	    importance: number;
+	    when?: number;
+	    title?: string;
+	    text?: string;
+	    subText?: string;
+	    ongoing: boolean;
+	    redacted: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.package = source["package"];
+	        this.id = source["id"];
+	        this.tag = source["tag"];
+	        this.channel = source["channel"];
+	        this.importance = source["importance"];
+	        this.when = source["when"];
+	        this.title = source["title"];
+	        this.text = source["text"];
+	        this.subText = source["subText"];
+	        this.ongoing = source["ongoing"];
+	        this.redacted = source["redacted"];
+	    }
+	}
+	export class OrientationInfo {
+	    rotation: number;
+	    name: string;
+	    autoRotate: boolean;
+	    userRotation: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrientationInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rotation = source["rotation"];
+	        this.name = source["name"];
+	        this.autoRotate = source["autoRotate"];
+	        this.userRotation = source["userRotation"];
+	    }
+	}
+	export class PacketCaptureOptions {
+	    interface: string;
+	    filter: string;
+	    snapLen: number;
+	    outputDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PacketCaptureOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.interface = source["interface"];
+	        this.filter = source["filter"];
+	        this.snapLen = source["snapLen"];
+	        this.outputDir = source["outputDir"];
+	    }
+	}
+	export class PacketCaptureStatus {
+	    deviceId: string;
+	    interface: string;
+	    filter: string;
+	    state: string;
+	    startedAt: number;
+	    finishedAt?: number;
+	    bytes: number;
+	    remotePath: string;
+	    localPath?: string;
+	    finished: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PacketCaptureStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.interface = source["interface"];
+	        this.filter = source["filter"];
+	        this.state = source["state"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.bytes = source["bytes"];
+	        this.remotePath = source["remotePath"];
+	        this.localPath = source["localPath"];
+	        this.finished = source["finished"];
+	        this.error = source["error"];
+	    }
+	}
+	export class PathBookmark {
+	    path: string;
+	    label: string;
+	    global: boolean;
+	    exists: boolean;
+	    createdAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PathBookmark(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	        this.global = source["global"];
+	        this.exists = source["exists"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class PlaybackEventTiming {
+	    index: number;
+	    type: string;
+	    scheduledMs: number;
+	    actualMs: number;
+	    driftMs: number;
+	    durationMs: number;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaybackEventTiming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.type = source["type"];
+	        this.scheduledMs = source["scheduledMs"];
+	        this.actualMs = source["actualMs"];
+	        this.driftMs = source["driftMs"];
+	        this.durationMs = source["durationMs"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class PlaybackOptions {
+	    repeat: number;
+	    intervalMs: number;
+	    maxDuration: number;
+	    speed: number;
+	    stepMode: boolean;
+	    disableAnimations: boolean;
+	    forceOrientation: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaybackOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repeat = source["repeat"];
+	        this.intervalMs = source["intervalMs"];
+	        this.maxDuration = source["maxDuration"];
+	        this.speed = source["speed"];
+	        this.stepMode = source["stepMode"];
+	        this.disableAnimations = source["disableAnimations"];
+	        this.forceOrientation = source["forceOrientation"];
+	    }
+	}
+	export class PlaybackRun {
+	    id: string;
+	    deviceId: string;
+	    scriptName: string;
+	    startTime: number;
+	    endTime: number;
+	    status: string;
+	    error?: string;
+	    eventCount: number;
+	    maxDriftMs: number;
+	    avgDriftMs: number;
+	    warnings?: string[];
+	    events?: PlaybackEventTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaybackRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.deviceId = source["deviceId"];
+	        this.scriptName = source["scriptName"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.eventCount = source["eventCount"];
+	        this.maxDriftMs = source["maxDriftMs"];
+	        this.avgDriftMs = source["avgDriftMs"];
+	        this.warnings = source["warnings"];
+	        this.events = this.convertValues(source["events"], PlaybackEventTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PortForward {
+	    direction: string;
+	    local: string;
+	    remote: string;
+	    label?: string;
+	    favorite: boolean;
+	    createdAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PortForward(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.direction = source["direction"];
+	        this.local = source["local"];
+	        this.remote = source["remote"];
+	        this.label = source["label"];
+	        this.favorite = source["favorite"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class PortForwardResult {
+	    forward: PortForward;
+	    success: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PortForwardResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.forward = this.convertValues(source["forward"], PortForward);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProcessInfo {
+	    pid: number;
+	    ppid: number;
+	    user: string;
+	    nice: number;
+	    rss: number;
+	    name: string;
+	    package?: string;
+	    killable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.ppid = source["ppid"];
+	        this.user = source["user"];
+	        this.nice = source["nice"];
+	        this.rss = source["rss"];
+	        this.name = source["name"];
+	        this.package = source["package"];
+	        this.killable = source["killable"];
+	    }
+	}
+	export class ProcessUsage {
+	    pid: number;
+	    name: string;
+	    cpu: number;
+	    rss: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.name = source["name"];
+	        this.cpu = source["cpu"];
+	        this.rss = source["rss"];
+	    }
+	}
+	export class RadioToggleResult {
+	    radio: string;
+	    requested: boolean;
+	    enabled: boolean;
+	    verified: boolean;
+	    method: string;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RadioToggleResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.radio = source["radio"];
+	        this.requested = source["requested"];
+	        this.enabled = source["enabled"];
+	        this.verified = source["verified"];
+	        this.method = source["method"];
+	        this.warning = source["warning"];
+	    }
+	}
+	export class RawInputEvent {
+	    t: number;
+	    type: number;
+	    code: number;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RawInputEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.t = source["t"];
+	        this.type = source["type"];
+	        this.code = source["code"];
+	        this.value = source["value"];
+	    }
+	}
+	export class RawTouchInput {
+	    device: string;
+	    minX: number;
+	    maxX: number;
+	    minY: number;
+	    maxY: number;
+	    events: RawInputEvent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RawTouchInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.device = source["device"];
+	        this.minX = source["minX"];
+	        this.maxX = source["maxX"];
+	        this.minY = source["minY"];
+	        this.maxY = source["maxY"];
+	        this.events = this.convertValues(source["events"], RawInputEvent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RecordOptions {
+	    format: string;
+	    maxDuration: number;
+	    bitRate: number;
+	    maxSize: number;
+	    maxFileSize: number;
+	    noPlayback: boolean;
+	    noAudio: boolean;
+	    videoCodec: string;
+	    backend: string;
+	    size: string;
+	    concat: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecordOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.maxDuration = source["maxDuration"];
+	        this.bitRate = source["bitRate"];
+	        this.maxSize = source["maxSize"];
+	        this.maxFileSize = source["maxFileSize"];
+	        this.noPlayback = source["noPlayback"];
+	        this.noAudio = source["noAudio"];
+	        this.videoCodec = source["videoCodec"];
+	        this.backend = source["backend"];
+	        this.size = source["size"];
+	        this.concat = source["concat"];
+	    }
+	}
+	export class RecordingInfo {
+	    path: string;
+	    name: string;
+	    size: number;
+	    durationMs: number;
+	    deviceId: string;
+	    model: string;
+	    backend: string;
+	    parts?: string[];
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecordingInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.durationMs = source["durationMs"];
+	        this.deviceId = source["deviceId"];
+	        this.model = source["model"];
+	        this.backend = source["backend"];
+	        this.parts = source["parts"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class ScreenRect {
+	    left: number;
+	    top: number;
+	    right: number;
+	    bottom: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenRect(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.left = source["left"];
+	        this.top = source["top"];
+	        this.right = source["right"];
+	        this.bottom = source["bottom"];
+	    }
+	}
+	export class RecordingOptions {
+	    ignoreZones: ScreenRect[];
+	    minStrokeMs: number;
+	    discardShortStrokes: boolean;
+	    shortStrokeMs: number;
+	    shortStrokePx: number;
+	    disableSelectorCapture: boolean;
+	    showTouches: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecordingOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ignoreZones = this.convertValues(source["ignoreZones"], ScreenRect);
+	        this.minStrokeMs = source["minStrokeMs"];
+	        this.discardShortStrokes = source["discardShortStrokes"];
+	        this.shortStrokeMs = source["shortStrokeMs"];
+	        this.shortStrokePx = source["shortStrokePx"];
+	        this.disableSelectorCapture = source["disableSelectorCapture"];
+	        this.showTouches = source["showTouches"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ResourceSample {
+	    deviceId: string;
+	    timestamp: number;
+	    cpuPercent: number;
+	    coreCount: number;
+	    coreLoads: number[];
+	    memTotal: number;
+	    memUsed: number;
+	    memFree: number;
+	    memAvailable: number;
+	    topByCpu: ProcessUsage[];
+	    topByMemory: ProcessUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResourceSample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.timestamp = source["timestamp"];
+	        this.cpuPercent = source["cpuPercent"];
+	        this.coreCount = source["coreCount"];
+	        this.coreLoads = source["coreLoads"];
+	        this.memTotal = source["memTotal"];
+	        this.memUsed = source["memUsed"];
+	        this.memFree = source["memFree"];
+	        this.memAvailable = source["memAvailable"];
+	        this.topByCpu = this.convertValues(source["topByCpu"], ProcessUsage);
+	        this.topByMemory = this.convertValues(source["topByMemory"], ProcessUsage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScheduledJob {
+	    package: string;
+	    uid: string;
+	    jobId: number;
+	    service: string;
+	    requiredConstraints: string[];
+	    satisfiedConstraints: string[];
+	    unsatisfiedConstraints: string[];
+	    standbyBucket?: string;
+	    interval?: string;
+	    nextRun?: string;
+	    nextRunAt: number;
+	    deadline?: string;
+	    deadlineAt: number;
+	    lastRun?: string;
+	    lastRunAt: number;
+	    ready: boolean;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduledJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.package = source["package"];
+	        this.uid = source["uid"];
+	        this.jobId = source["jobId"];
+	        this.service = source["service"];
+	        this.requiredConstraints = source["requiredConstraints"];
+	        this.satisfiedConstraints = source["satisfiedConstraints"];
+	        this.unsatisfiedConstraints = source["unsatisfiedConstraints"];
+	        this.standbyBucket = source["standbyBucket"];
+	        this.interval = source["interval"];
+	        this.nextRun = source["nextRun"];
+	        this.nextRunAt = source["nextRunAt"];
+	        this.deadline = source["deadline"];
+	        this.deadlineAt = source["deadlineAt"];
+	        this.lastRun = source["lastRun"];
+	        this.lastRunAt = source["lastRunAt"];
+	        this.ready = source["ready"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class ScheduledPlayback {
+	    id: string;
+	    deviceId: string;
+	    scriptName: string;
+	    cron: string;
+	    nextRun: number;
+	    lastRun?: number;
+	    lastError?: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduledPlayback(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.deviceId = source["deviceId"];
+	        this.scriptName = source["scriptName"];
+	        this.cron = source["cron"];
+	        this.nextRun = source["nextRun"];
+	        this.lastRun = source["lastRun"];
+	        this.lastError = source["lastError"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class ScrcpyCamera {
+	    id: string;
+	    facing: string;
+	    size: string;
+	    fps: number[];
+	    sizes: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrcpyCamera(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.facing = source["facing"];
+	        this.size = source["size"];
+	        this.fps = source["fps"];
+	        this.sizes = source["sizes"];
+	    }
+	}
+	export class ScrcpyConfig {
+	    maxSize: number;
+	    bitRate: number;
+	    maxFps: number;
+	    stayAwake: boolean;
+	    turnScreenOff: boolean;
+	    noAudio: boolean;
+	    alwaysOnTop: boolean;
+	    showTouches: boolean;
+	    fullscreen: boolean;
+	    readOnly: boolean;
+	    powerOffOnClose: boolean;
+	    windowBorderless: boolean;
+	    videoCodec: string;
+	    audioCodec: string;
+	    recordPath: string;
+	    displayId: number;
+	    videoSource: string;
+	    cameraId: string;
+	    cameraSize: string;
+	    displayOrientation: string;
+	    captureOrientation: string;
+	    keyboardMode: string;
+	    mouseMode: string;
+	    noClipboardSync: boolean;
+	    showFps: boolean;
+	    noPowerOn: boolean;
+	    cameraFacing: string;
+	    newDisplay: boolean;
+	    newDisplaySize: string;
+	    newDisplayDpi: number;
+	    startApp: string;
+	    audioSource: string;
+	    audioBitRate: number;
+	    audioBuffer: number;
+	    crop: string;
+	    rotation: number;
+	    allowMultiple: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrcpyConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxSize = source["maxSize"];
+	        this.bitRate = source["bitRate"];
+	        this.maxFps = source["maxFps"];
+	        this.stayAwake = source["stayAwake"];
+	        this.turnScreenOff = source["turnScreenOff"];
+	        this.noAudio = source["noAudio"];
+	        this.alwaysOnTop = source["alwaysOnTop"];
+	        this.showTouches = source["showTouches"];
+	        this.fullscreen = source["fullscreen"];
+	        this.readOnly = source["readOnly"];
+	        this.powerOffOnClose = source["powerOffOnClose"];
+	        this.windowBorderless = source["windowBorderless"];
+	        this.videoCodec = source["videoCodec"];
+	        this.audioCodec = source["audioCodec"];
+	        this.recordPath = source["recordPath"];
+	        this.displayId = source["displayId"];
+	        this.videoSource = source["videoSource"];
+	        this.cameraId = source["cameraId"];
+	        this.cameraSize = source["cameraSize"];
+	        this.displayOrientation = source["displayOrientation"];
+	        this.captureOrientation = source["captureOrientation"];
+	        this.keyboardMode = source["keyboardMode"];
+	        this.mouseMode = source["mouseMode"];
+	        this.noClipboardSync = source["noClipboardSync"];
+	        this.showFps = source["showFps"];
+	        this.noPowerOn = source["noPowerOn"];
+	        this.cameraFacing = source["cameraFacing"];
+	        this.newDisplay = source["newDisplay"];
+	        this.newDisplaySize = source["newDisplaySize"];
+	        this.newDisplayDpi = source["newDisplayDpi"];
+	        this.startApp = source["startApp"];
+	        this.audioSource = source["audioSource"];
+	        this.audioBitRate = source["audioBitRate"];
+	        this.audioBuffer = source["audioBuffer"];
+	        this.crop = source["crop"];
+	        this.rotation = source["rotation"];
+	        this.allowMultiple = source["allowMultiple"];
+	    }
+	}
+	export class ScrcpyDisplay {
+	    id: number;
+	    size: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrcpyDisplay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.size = source["size"];
+	    }
+	}
+	export class ScrcpyPreset {
+	    name: string;
+	    config: ScrcpyConfig;
+	    builtIn: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrcpyPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.config = this.convertValues(source["config"], ScrcpyConfig);
+	        this.builtIn = source["builtIn"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScrcpySession {
+	    sessionId: string;
+	    deviceId: string;
+	    pid: number;
+	    startTime: number;
+	    config: ScrcpyConfig;
+	    warnings?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrcpySession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.deviceId = source["deviceId"];
+	        this.pid = source["pid"];
+	        this.startTime = source["startTime"];
+	        this.config = this.convertValues(source["config"], ScrcpyConfig);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScriptFilter {
+	    tag: string;
+	    folder: string;
+	    name: string;
+	    sortBy: string;
+	    descending: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	        this.folder = source["folder"];
+	        this.name = source["name"];
+	        this.sortBy = source["sortBy"];
+	        this.descending = source["descending"];
+	    }
+	}
+	export class TaskStep {
+	    type: string;
+	    value: string;
+	    loop: number;
+	    postDelay: number;
+	    checkType: string;
+	    checkValue: string;
+	    waitTimeout: number;
+	    onFailure: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.value = source["value"];
+	        this.loop = source["loop"];
+	        this.postDelay = source["postDelay"];
+	        this.checkType = source["checkType"];
+	        this.checkValue = source["checkValue"];
+	        this.waitTimeout = source["waitTimeout"];
+	        this.onFailure = source["onFailure"];
+	    }
+	}
+	export class ScriptTask {
+	    name: string;
+	    steps: TaskStep[];
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.steps = this.convertValues(source["steps"], TaskStep);
+	        this.createdAt = source["createdAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScrollResult {
+	    found: boolean;
+	    bounds?: string;
+	    swipes: number;
+	    endOfList: boolean;
+	    elapsedMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrollResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.found = source["found"];
+	        this.bounds = source["bounds"];
+	        this.swipes = source["swipes"];
+	        this.endOfList = source["endOfList"];
+	        this.elapsedMs = source["elapsedMs"];
+	    }
+	}
+	export class UINode {
+	    text: string;
+	    resourceId: string;
+	    class: string;
+	    package: string;
+	    contentDesc: string;
+	    checkable: string;
+	    checked: string;
+	    clickable: string;
+	    enabled: string;
+	    focusable: string;
+	    focused: string;
+	    scrollable: string;
+	    longClickable: string;
+	    password: string;
+	    selected: string;
+	    bounds: string;
+	    index: string;
+	    nodes: UINode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UINode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.resourceId = source["resourceId"];
 	        this.class = source["class"];
+	        this.package = source["package"];
+	        this.contentDesc = source["contentDesc"];
+	        this.checkable = source["checkable"];
+	        this.checked = source["checked"];
+	        this.clickable = source["clickable"];
+	        this.enabled = source["enabled"];
+	        this.focusable = source["focusable"];
+	        this.focused = source["focused"];
+	        this.scrollable = source["scrollable"];
+	        this.longClickable = source["longClickable"];
+	        this.password = source["password"];
+	        this.selected = source["selected"];
+	        this.bounds = source["bounds"];
+	        this.index = source["index"];
+	        this.nodes = this.convertValues(source["nodes"], UINode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchResult {
+	    node?: UINode;
+	    path: string;
+	    depth: number;
+	    index: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.node = this.convertValues(source["node"], UINode);
+	        this.path = source["path"];
+	        this.depth = source["depth"];
+	        this.index = source["index"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecurityCheck {
+	    id: string;
+	    label: string;
+	    value: string;
+	    status: string;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecurityCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.value = source["value"];
+	        this.status = source["status"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class SecurityReport {
+	    deviceId: string;
+	    serial: string;
+	    model: string;
+	    collectedAt: number;
+	    checks: SecurityCheck[];
+	    passed: number;
+	    warnings: number;
+	    accessibilityServices: string[];
+	    deviceOwners: string[];
+	    deviceAdmins: string[];
+	    installSources: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SecurityReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.serial = source["serial"];
+	        this.model = source["model"];
+	        this.collectedAt = source["collectedAt"];
+	        this.checks = this.convertValues(source["checks"], SecurityCheck);
+	        this.passed = source["passed"];
+	        this.warnings = source["warnings"];
+	        this.accessibilityServices = source["accessibilityServices"];
+	        this.deviceOwners = source["deviceOwners"];
+	        this.deviceAdmins = source["deviceAdmins"];
+	        this.installSources = source["installSources"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SelectorMatch {
+	    index: number;
+	    bounds: string;
+	    centerX: number;
+	    centerY: number;
+	    text: string;
+	    resourceId: string;
+	    class: string;
+	    contentDesc: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectorMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
 	        this.bounds = source["bounds"];
+	        this.centerX = source["centerX"];
+	        this.centerY = source["centerY"];
+	        this.text = source["text"];
+	        this.resourceId = source["resourceId"];
+	        this.class = source["class"];
+	        this.contentDesc = source["contentDesc"];
+	    }
+	}
+	export class SelectorSuggestion {
+	    type: string;
+	    value: string;
+	    priority: number;
+	    description: string;
+	    selector?: ElementSelector;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectorSuggestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.value = source["value"];
+	        this.priority = source["priority"];
+	        this.description = source["description"];
 	        this.selector = this.convertValues(source["selector"], ElementSelector);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SelectorTestResult {
+	    count: number;
+	    unique: boolean;
+	    elapsedMs: number;
+	    matches: SelectorMatch[];
+	    suggestions: SelectorSuggestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectorTestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.count = source["count"];
+	        this.unique = source["unique"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.matches = this.convertValues(source["matches"], SelectorMatch);
+	        this.suggestions = this.convertValues(source["suggestions"], SelectorSuggestion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SettingChange {
+	    namespace: string;
+	    key: string;
+	    change: string;
+	    before: string;
+	    after: string;
+	    dangerous: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.namespace = source["namespace"];
+	        this.key = source["key"];
+	        this.change = source["change"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.dangerous = source["dangerous"];
+	    }
+	}
+	export class SettingEntry {
+	    namespace: string;
+	    key: string;
+	    value: string;
+	    dangerous: boolean;
+	    note?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.namespace = source["namespace"];
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.dangerous = source["dangerous"];
+	        this.note = source["note"];
+	    }
+	}
+	export class SettingsSnapshot {
+	    id: string;
+	    name: string;
+	    deviceId: string;
+	    serial: string;
+	    createdAt: number;
+	    count: number;
+	    values?: {[key: string]: };
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.deviceId = source["deviceId"];
+	        this.serial = source["serial"];
+	        this.createdAt = source["createdAt"];
+	        this.count = source["count"];
+	        this.values = source["values"];
+	    }
+	}
+	export class ShellHistoryEntry {
+	    deviceId: string;
+	    command: string;
+	    exitCode: number;
+	    timestamp: number;
+	    durationMs: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShellHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.command = source["command"];
+	        this.exitCode = source["exitCode"];
 	        this.timestamp = source["timestamp"];
+	        this.durationMs = source["durationMs"];
+	        this.count = source["count"];
+	    }
+	}
+	export class ShellSnippet {
+	    name: string;
+	    command: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShellSnippet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class SideloadPackageInfo {
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    kind?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SideloadPackageInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.kind = source["kind"];
+	    }
+	}
+	export class SideloadResult {
+	    deviceId: string;
+	    package: SideloadPackageInfo;
+	    durationMs: number;
+	    warning?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SideloadResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.package = this.convertValues(source["package"], SideloadPackageInfo);
+	        this.durationMs = source["durationMs"];
+	        this.warning = source["warning"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StorageEntry {
+	    path: string;
+	    size: number;
+	    permissionDenied: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StorageEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.permissionDenied = source["permissionDenied"];
+	    }
+	}
+	export class StorageNode {
+	    path: string;
+	    name: string;
+	    size: number;
+	    permissionDenied: boolean;
+	    children?: StorageNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StorageNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.permissionDenied = source["permissionDenied"];
+	        this.children = this.convertValues(source["children"], StorageNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StorageAnalysis {
+	    root?: StorageNode;
+	    largest: StorageEntry[];
+	    entries: number;
+	    denied: number;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StorageAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = this.convertValues(source["root"], StorageNode);
+	        this.largest = this.convertValues(source["largest"], StorageEntry);
+	        this.entries = source["entries"];
+	        this.denied = source["denied"];
+	        this.durationMs = source["durationMs"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -254,154 +3333,194 @@ export namespace main {
 		}
 	}
 	
-	export class FileInfo {
-	    name: string;
-	    size: number;
-	    mode: string;
-	    modTime: string;
-	    isDir: boolean;
-	    path: string;
+	
+	export class SyncSummary {
+	    syncId: string;
+	    direction: string;
+	    copied: number;
+	    skipped: number;
+	    deleted: number;
+	    failed: string[];
+	    bytes: number;
+	    durationMs: number;
+	    cancelled: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileInfo(source);
+	        return new SyncSummary(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.mode = source["mode"];
-	        this.modTime = source["modTime"];
-	        this.isDir = source["isDir"];
-	        this.path = source["path"];
+	        this.syncId = source["syncId"];
+	        this.direction = source["direction"];
+	        this.copied = source["copied"];
+	        this.skipped = source["skipped"];
+	        this.deleted = source["deleted"];
+	        this.failed = source["failed"];
+	        this.bytes = source["bytes"];
+	        this.durationMs = source["durationMs"];
+	        this.cancelled = source["cancelled"];
 	    }
 	}
-	export class HistoryDevice {
-	    id: string;
-	    serial: string;
-	    model: string;
-	    brand: string;
-	    type: string;
-	    wifiAddr: string;
-	    lastSeen: number;
+	export class SystemTraceStatus {
+	    deviceId: string;
+	    backend: string;
+	    state: string;
+	    startedAt: number;
+	    finishedAt?: number;
+	    durationSec: number;
+	    categories: string[];
+	    appPackage?: string;
+	    remotePath: string;
+	    localPath?: string;
+	    finished: boolean;
+	    error?: string;
+	    hint?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new HistoryDevice(source);
+	        return new SystemTraceStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.serial = source["serial"];
-	        this.model = source["model"];
-	        this.brand = source["brand"];
-	        this.type = source["type"];
-	        this.wifiAddr = source["wifiAddr"];
-	        this.lastSeen = source["lastSeen"];
+	        this.deviceId = source["deviceId"];
+	        this.backend = source["backend"];
+	        this.state = source["state"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.durationSec = source["durationSec"];
+	        this.categories = source["categories"];
+	        this.appPackage = source["appPackage"];
+	        this.remotePath = source["remotePath"];
+	        this.localPath = source["localPath"];
+	        this.finished = source["finished"];
+	        this.error = source["error"];
+	        this.hint = source["hint"];
 	    }
 	}
-	export class ScrcpyConfig {
-	    maxSize: number;
-	    bitRate: number;
-	    maxFps: number;
-	    stayAwake: boolean;
-	    turnScreenOff: boolean;
-	    noAudio: boolean;
-	    alwaysOnTop: boolean;
-	    showTouches: boolean;
-	    fullscreen: boolean;
-	    readOnly: boolean;
-	    powerOffOnClose: boolean;
-	    windowBorderless: boolean;
-	    videoCodec: string;
-	    audioCodec: string;
-	    recordPath: string;
-	    displayId: number;
-	    videoSource: string;
-	    cameraId: string;
-	    cameraSize: string;
-	    displayOrientation: string;
-	    captureOrientation: string;
-	    keyboardMode: string;
-	    mouseMode: string;
-	    noClipboardSync: boolean;
-	    showFps: boolean;
-	    noPowerOn: boolean;
+	
+	
+	export class TouchPointer {
+	    x: number;
+	    y: number;
+	    x2: number;
+	    y2: number;
+	    delay?: number;
+	    duration: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ScrcpyConfig(source);
+	        return new TouchPointer(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.maxSize = source["maxSize"];
-	        this.bitRate = source["bitRate"];
-	        this.maxFps = source["maxFps"];
-	        this.stayAwake = source["stayAwake"];
-	        this.turnScreenOff = source["turnScreenOff"];
-	        this.noAudio = source["noAudio"];
-	        this.alwaysOnTop = source["alwaysOnTop"];
-	        this.showTouches = source["showTouches"];
-	        this.fullscreen = source["fullscreen"];
-	        this.readOnly = source["readOnly"];
-	        this.powerOffOnClose = source["powerOffOnClose"];
-	        this.windowBorderless = source["windowBorderless"];
-	        this.videoCodec = source["videoCodec"];
-	        this.audioCodec = source["audioCodec"];
-	        this.recordPath = source["recordPath"];
-	        this.displayId = source["displayId"];
-	        this.videoSource = source["videoSource"];
-	        this.cameraId = source["cameraId"];
-	        this.cameraSize = source["cameraSize"];
-	        this.displayOrientation = source["displayOrientation"];
-	        this.captureOrientation = source["captureOrientation"];
-	        this.keyboardMode = source["keyboardMode"];
-	        this.mouseMode = source["mouseMode"];
-	        this.noClipboardSync = source["noClipboardSync"];
-	        this.showFps = source["showFps"];
-	        this.noPowerOn = source["noPowerOn"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.x2 = source["x2"];
+	        this.y2 = source["y2"];
+	        this.delay = source["delay"];
+	        this.duration = source["duration"];
 	    }
 	}
-	export class TaskStep {
+	export class TouchEvent {
+	    timestamp: number;
 	    type: string;
-	    value: string;
-	    loop: number;
-	    postDelay: number;
-	    checkType: string;
-	    checkValue: string;
-	    waitTimeout: number;
-	    onFailure: string;
+	    x: number;
+	    y: number;
+	    x2?: number;
+	    y2?: number;
+	    duration?: number;
+	    selector?: ElementSelector;
+	    fingers?: TouchPointer[];
+	    keyCode?: number;
+	    text?: string;
+	    gone?: boolean;
+	    container?: ElementSelector;
+	    direction?: string;
+	    maxSwipes?: number;
+	    elementText?: string;
+	    elementId?: string;
+	    activity?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new TaskStep(source);
+	        return new TouchEvent(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timestamp = source["timestamp"];
 	        this.type = source["type"];
-	        this.value = source["value"];
-	        this.loop = source["loop"];
-	        this.postDelay = source["postDelay"];
-	        this.checkType = source["checkType"];
-	        this.checkValue = source["checkValue"];
-	        this.waitTimeout = source["waitTimeout"];
-	        this.onFailure = source["onFailure"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.x2 = source["x2"];
+	        this.y2 = source["y2"];
+	        this.duration = source["duration"];
+	        this.selector = this.convertValues(source["selector"], ElementSelector);
+	        this.fingers = this.convertValues(source["fingers"], TouchPointer);
+	        this.keyCode = source["keyCode"];
+	        this.text = source["text"];
+	        this.gone = source["gone"];
+	        this.container = this.convertValues(source["container"], ElementSelector);
+	        this.direction = source["direction"];
+	        this.maxSwipes = source["maxSwipes"];
+	        this.elementText = source["elementText"];
+	        this.elementId = source["elementId"];
+	        this.activity = source["activity"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ScriptTask {
+	
+	export class TouchScript {
+	    version: number;
 	    name: string;
-	    steps: TaskStep[];
+	    deviceId: string;
+	    deviceModel?: string;
+	    resolution: string;
+	    orientation?: number;
 	    createdAt: string;
+	    events: TouchEvent[];
+	    playbackMode?: string;
+	    rawInput?: RawTouchInput;
+	    absoluteCoordinates?: boolean;
+	    tags?: string[];
+	    folder?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ScriptTask(source);
+	        return new TouchScript(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
 	        this.name = source["name"];
-	        this.steps = this.convertValues(source["steps"], TaskStep);
+	        this.deviceId = source["deviceId"];
+	        this.deviceModel = source["deviceModel"];
+	        this.resolution = source["resolution"];
+	        this.orientation = source["orientation"];
 	        this.createdAt = source["createdAt"];
+	        this.events = this.convertValues(source["events"], TouchEvent);
+	        this.playbackMode = source["playbackMode"];
+	        this.rawInput = this.convertValues(source["rawInput"], RawTouchInput);
+	        this.absoluteCoordinates = source["absoluteCoordinates"];
+	        this.tags = source["tags"];
+	        this.folder = source["folder"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -422,84 +3541,160 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class UINode {
-	    text: string;
-	    resourceId: string;
-	    class: string;
-	    package: string;
-	    contentDesc: string;
-	    checkable: string;
-	    checked: string;
-	    clickable: string;
-	    enabled: string;
-	    focusable: string;
-	    focused: string;
-	    scrollable: string;
-	    longClickable: string;
-	    password: string;
-	    selected: string;
-	    bounds: string;
-	    nodes: UINode[];
+	export class TouchScriptSummary {
+	    name: string;
+	    deviceModel?: string;
+	    resolution: string;
+	    createdAt: string;
+	    modifiedAt: number;
+	    eventCount: number;
+	    tags: string[];
+	    folder: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TouchScriptSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.deviceModel = source["deviceModel"];
+	        this.resolution = source["resolution"];
+	        this.createdAt = source["createdAt"];
+	        this.modifiedAt = source["modifiedAt"];
+	        this.eventCount = source["eventCount"];
+	        this.tags = source["tags"];
+	        this.folder = source["folder"];
+	    }
+	}
+	export class TraceFile {
+	    name: string;
+	    path: string;
+	    localPath?: string;
+	    size: number;
+	    modTime: number;
+	    kind: string;
+	    process: string;
+	    pid: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.localPath = source["localPath"];
+	        this.size = source["size"];
+	        this.modTime = source["modTime"];
+	        this.kind = source["kind"];
+	        this.process = source["process"];
+	        this.pid = source["pid"];
+	    }
+	}
+	export class TraceOptions {
+	    durationSec: number;
+	    bufferSizeMb: number;
+	    categories: string[];
+	    appPackage: string;
+	    heapProfile: boolean;
+	    outputDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TraceOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.durationSec = source["durationSec"];
+	        this.bufferSizeMb = source["bufferSizeMb"];
+	        this.categories = source["categories"];
+	        this.appPackage = source["appPackage"];
+	        this.heapProfile = source["heapProfile"];
+	        this.outputDir = source["outputDir"];
+	    }
+	}
+	export class TransferResult {
+	    transferId: string;
+	    files: number;
+	    bytes: number;
+	    durationMs: number;
+	    throughput: number;
+	    retries: number;
+	    verified: boolean;
+	    checksumAlgo?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new UINode(source);
+	        return new TransferResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.text = source["text"];
-	        this.resourceId = source["resourceId"];
-	        this.class = source["class"];
-	        this.package = source["package"];
-	        this.contentDesc = source["contentDesc"];
-	        this.checkable = source["checkable"];
-	        this.checked = source["checked"];
-	        this.clickable = source["clickable"];
-	        this.enabled = source["enabled"];
-	        this.focusable = source["focusable"];
-	        this.focused = source["focused"];
-	        this.scrollable = source["scrollable"];
-	        this.longClickable = source["longClickable"];
-	        this.password = source["password"];
-	        this.selected = source["selected"];
-	        this.bounds = source["bounds"];
-	        this.nodes = this.convertValues(source["nodes"], UINode);
+	        this.transferId = source["transferId"];
+	        this.files = source["files"];
+	        this.bytes = source["bytes"];
+	        this.durationMs = source["durationMs"];
+	        this.throughput = source["throughput"];
+	        this.retries = source["retries"];
+	        this.verified = source["verified"];
+	        this.checksumAlgo = source["checksumAlgo"];
 	    }
+	}
+	export class TypeTextResult {
+	    method: string;
+	    typed: number;
+	    dropped: string[];
 	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
+	    static createFrom(source: any = {}) {
+	        return new TypeTextResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.method = source["method"];
+	        this.typed = source["typed"];
+	        this.dropped = source["dropped"];
+	    }
 	}
-	export class SearchResult {
-	    node?: UINode;
-	    path: string;
+	export class UIDiffChange {
+	    field: string;
+	    before: string;
+	    after: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UIDiffChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	}
+	export class UIDiffRow {
 	    depth: number;
-	    index: number;
+	    status: string;
+	    label: string;
+	    selector?: ElementSelector;
+	    before?: UINode;
+	    after?: UINode;
+	    changes?: UIDiffChange[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchResult(source);
+	        return new UIDiffRow(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.node = this.convertValues(source["node"], UINode);
-	        this.path = source["path"];
 	        this.depth = source["depth"];
-	        this.index = source["index"];
+	        this.status = source["status"];
+	        this.label = source["label"];
+	        this.selector = this.convertValues(source["selector"], ElementSelector);
+	        this.before = this.convertValues(source["before"], UINode);
+	        this.after = this.convertValues(source["after"], UINode);
+	        this.changes = this.convertValues(source["changes"], UIDiffChange);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -520,49 +3715,24 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class SelectorSuggestion {
-	    type: string;
-	    value: string;
-	    priority: number;
-	    description: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new SelectorSuggestion(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.type = source["type"];
-	        this.value = source["value"];
-	        this.priority = source["priority"];
-	        this.description = source["description"];
-	    }
-	}
-	
-	export class TouchEvent {
-	    timestamp: number;
-	    type: string;
-	    x: number;
-	    y: number;
-	    x2?: number;
-	    y2?: number;
-	    duration?: number;
-	    selector?: ElementSelector;
+	export class UIHierarchyResult {
+	    root?: UINode;
+	    rawXml: string;
+	    generation: number;
+	    dumpedAt: number;
+	    cached: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new TouchEvent(source);
+	        return new UIHierarchyResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.timestamp = source["timestamp"];
-	        this.type = source["type"];
-	        this.x = source["x"];
-	        this.y = source["y"];
-	        this.x2 = source["x2"];
-	        this.y2 = source["y2"];
-	        this.duration = source["duration"];
-	        this.selector = this.convertValues(source["selector"], ElementSelector);
+	        this.root = this.convertValues(source["root"], UINode);
+	        this.rawXml = source["rawXml"];
+	        this.generation = source["generation"];
+	        this.dumpedAt = source["dumpedAt"];
+	        this.cached = source["cached"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -583,16 +3753,17 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class TouchScript {
+	
+	export class UISnapshot {
 	    name: string;
 	    deviceId: string;
-	    deviceModel?: string;
-	    resolution: string;
+	    deviceModel: string;
 	    createdAt: string;
-	    events: TouchEvent[];
+	    root?: UINode;
+	    screenshot?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new TouchScript(source);
+	        return new UISnapshot(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -600,9 +3771,9 @@ export namespace main {
 	        this.name = source["name"];
 	        this.deviceId = source["deviceId"];
 	        this.deviceModel = source["deviceModel"];
-	        this.resolution = source["resolution"];
 	        this.createdAt = source["createdAt"];
-	        this.events = this.convertValues(source["events"], TouchEvent);
+	        this.root = this.convertValues(source["root"], UINode);
+	        this.screenshot = source["screenshot"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -623,18 +3794,26 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class UIHierarchyResult {
-	    root?: UINode;
-	    rawXml: string;
+	export class UISnapshotDiff {
+	    a: string;
+	    b: string;
+	    added: number;
+	    removed: number;
+	    changed: number;
+	    rows: UIDiffRow[];
 	
 	    static createFrom(source: any = {}) {
-	        return new UIHierarchyResult(source);
+	        return new UISnapshotDiff(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.root = this.convertValues(source["root"], UINode);
-	        this.rawXml = source["rawXml"];
+	        this.a = source["a"];
+	        this.b = source["b"];
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.changed = source["changed"];
+	        this.rows = this.convertValues(source["rows"], UIDiffRow);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -655,7 +3834,54 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class UISnapshotSummary {
+	    name: string;
+	    deviceModel: string;
+	    createdAt: string;
+	    nodeCount: number;
+	    hasScreenshot: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UISnapshotSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.deviceModel = source["deviceModel"];
+	        this.createdAt = source["createdAt"];
+	        this.nodeCount = source["nodeCount"];
+	        this.hasScreenshot = source["hasScreenshot"];
+	    }
+	}
+	export class WifiInfo {
+	    enabled: boolean;
+	    connected: boolean;
+	    state: string;
+	    ssid: string;
+	    bssid: string;
+	    rssi: number;
+	    linkSpeedMbps: number;
+	    frequencyMhz: number;
+	    ip: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WifiInfo(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.connected = source["connected"];
+	        this.state = source["state"];
+	        this.ssid = source["ssid"];
+	        this.bssid = source["bssid"];
+	        this.rssi = source["rssi"];
+	        this.linkSpeedMbps = source["linkSpeedMbps"];
+	        this.frequencyMhz = source["frequencyMhz"];
+	        this.ip = source["ip"];
+	    }
+	}
 	export class WorkflowStep {
 	    id: string;
 	    type: string;
@@ -664,12 +3890,20 @@ export namespace main {
 	    value?: string;
 	    timeout?: number;
 	    onError?: string;
+	    retries?: number;
 	    loop?: number;
 	    postDelay?: number;
 	    preWait?: number;
 	    swipeDistance?: number;
 	    swipeDuration?: number;
 	    conditionType?: string;
+	    container?: ElementSelector;
+	    maxSwipes?: number;
+	    then?: WorkflowStep[];
+	    else?: WorkflowStep[];
+	    body?: WorkflowStep[];
+	    times?: number;
+	    maxIterations?: number;
 	    nextStepId?: string;
 	    nextSource?: string;
 	    nextTarget?: string;
@@ -695,12 +3929,20 @@ export namespace main {
 	        this.value = source["value"];
 	        this.timeout = source["timeout"];
 	        this.onError = source["onError"];
+	        this.retries = source["retries"];
 	        this.loop = source["loop"];
 	        this.postDelay = source["postDelay"];
 	        this.preWait = source["preWait"];
 	        this.swipeDistance = source["swipeDistance"];
 	        this.swipeDuration = source["swipeDuration"];
 	        this.conditionType = source["conditionType"];
+	        this.container = this.convertValues(source["container"], ElementSelector);
+	        this.maxSwipes = source["maxSwipes"];
+	        this.then = this.convertValues(source["then"], WorkflowStep);
+	        this.else = this.convertValues(source["else"], WorkflowStep);
+	        this.body = this.convertValues(source["body"], WorkflowStep);
+	        this.times = source["times"];
+	        this.maxIterations = source["maxIterations"];
 	        this.nextStepId = source["nextStepId"];
 	        this.nextSource = source["nextSource"];
 	        this.nextTarget = source["nextTarget"];
@@ -733,6 +3975,7 @@ export namespace main {
 		}
 	}
 	export class Workflow {
+	    version?: number;
 	    id: string;
 	    name: string;
 	    description?: string;
@@ -740,6 +3983,7 @@ export namespace main {
 	    variables?: {[key: string]: string};
 	    createdAt: string;
 	    updatedAt: string;
+	    disableAnimations?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Workflow(source);
@@ -747,6 +3991,7 @@ export namespace main {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
 	        this.id = source["id"];
 	        this.name = source["name"];
 	        this.description = source["description"];
@@ -754,6 +3999,156 @@ export namespace main {
 	        this.variables = source["variables"];
 	        this.createdAt = source["createdAt"];
 	        this.updatedAt = source["updatedAt"];
+	        this.disableAnimations = source["disableAnimations"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkflowDatasetRow {
+	    row: number;
+	    variables: {[key: string]: string};
+	    passed: boolean;
+	    error?: string;
+	    elapsedMs: number;
+	    runId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowDatasetRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.variables = source["variables"];
+	        this.passed = source["passed"];
+	        this.error = source["error"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.runId = source["runId"];
+	    }
+	}
+	export class WorkflowDatasetResult {
+	    workflow: string;
+	    rows: WorkflowDatasetRow[];
+	    passed: number;
+	    failed: number;
+	    cancelled: boolean;
+	    elapsedMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowDatasetResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workflow = source["workflow"];
+	        this.rows = this.convertValues(source["rows"], WorkflowDatasetRow);
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.cancelled = source["cancelled"];
+	        this.elapsedMs = source["elapsedMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class WorkflowStepReport {
+	    path: string;
+	    stepId: string;
+	    name?: string;
+	    type: string;
+	    selector?: string;
+	    status: string;
+	    attempts: number;
+	    durationMs: number;
+	    error?: string;
+	    screenshot?: string;
+	    hierarchy?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowStepReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.stepId = source["stepId"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.selector = source["selector"];
+	        this.status = source["status"];
+	        this.attempts = source["attempts"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	        this.screenshot = source["screenshot"];
+	        this.hierarchy = source["hierarchy"];
+	    }
+	}
+	export class WorkflowRunReport {
+	    id: string;
+	    workflowId: string;
+	    workflowName: string;
+	    deviceId: string;
+	    variables?: {[key: string]: string};
+	    trigger?: string;
+	    startTime: number;
+	    endTime: number;
+	    status: string;
+	    error?: string;
+	    passed: number;
+	    failed: number;
+	    steps?: WorkflowStepReport[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowRunReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workflowId = source["workflowId"];
+	        this.workflowName = source["workflowName"];
+	        this.deviceId = source["deviceId"];
+	        this.variables = source["variables"];
+	        this.trigger = source["trigger"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.steps = this.convertValues(source["steps"], WorkflowStepReport);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -774,6 +4169,44 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	export class WorkflowTrigger {
+	    id: string;
+	    name?: string;
+	    type: string;
+	    workflowId: string;
+	    disabled?: boolean;
+	    serial?: string;
+	    model?: string;
+	    package?: string;
+	    cron?: string;
+	    nextRun?: number;
+	    lastFired?: number;
+	    lastError?: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowTrigger(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.workflowId = source["workflowId"];
+	        this.disabled = source["disabled"];
+	        this.serial = source["serial"];
+	        this.model = source["model"];
+	        this.package = source["package"];
+	        this.cron = source["cron"];
+	        this.nextRun = source["nextRun"];
+	        this.lastFired = source["lastFired"];
+	        this.lastError = source["lastError"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
 
 }
 
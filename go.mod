@@ -5,6 +5,7 @@ go 1.21
 toolchain go1.21.6
 
 require (
+	github.com/creack/pty v1.1.21
 	github.com/elazarl/goproxy v1.7.2
 	github.com/energye/systray v0.0.0-00010101000000-000000000000
 	github.com/wailsapp/wails/v2 v2.9.2
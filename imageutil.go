@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+)
+
+// captureScreenPNG grabs the current screen as PNG bytes via exec-out, which skips the pty so
+// the data is not CR/LF mangled and no temporary file is left on the device
+func (a *App) captureScreenPNG(ctx context.Context, deviceId string) ([]byte, error) {
+	var stderr strings.Builder
+	cmd := a.newAdbCommand(ctx, "-s", deviceId, "exec-out", "screencap -p")
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("screencap failed: %w, %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if len(data) < len(pngSignature) || string(data[:len(pngSignature)]) != pngSignature {
+		return nil, fmt.Errorf("screencap returned no image")
+	}
+	return data, nil
+}
+
+// scaleToWidth downsizes an image to at most maxWidth pixels wide by area averaging,
+// keeping the aspect ratio; smaller images are returned unchanged
+func scaleToWidth(src image.Image, maxWidth int) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if maxWidth <= 0 || sw <= maxWidth {
+		return src
+	}
+	w := maxWidth
+	h := sh * w / sw
+	if h < 1 {
+		h = 1
+	}
+	return resizeImage(src, w, h)
+}
+
+// resizeImage scales src to exactly w x h by area averaging; aspect ratio is not kept
+func resizeImage(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	origin := rgba.Bounds().Min
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy0, sy1 := y*sh/h, (y+1)*sh/h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < w; x++ {
+			sx0, sx1 := x*sw/w, (x+1)*sw/w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, bl, al, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				i := rgba.PixOffset(origin.X+sx0, origin.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(rgba.Pix[i])
+					g += uint32(rgba.Pix[i+1])
+					bl += uint32(rgba.Pix[i+2])
+					al += uint32(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(bl / n)
+			dst.Pix[o+3] = uint8(al / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultTestOutputsDir is where AndroidX Test Storage writes screenshots and other test files
+const defaultTestOutputsDir = "/sdcard/googletest/test_outputfiles"
+
+// instrumentationLineRegex parses "instrumentation:com.x.test/androidx.test.runner.AndroidJUnitRunner (target=com.x)"
+var instrumentationLineRegex = regexp.MustCompile(`^instrumentation:(\S+)/(\S+) \(target=([^)]+)\)`)
+
+// instrumentationArgRegex keeps -e keys and filters to characters that need no quoting trouble
+var instrumentationArgRegex = regexp.MustCompile(`^[\w.$#,-]+$`)
+
+// Instrumentation status codes from the raw (-r) protocol
+var instrumentationStatuses = map[int]string{
+	0:  "passed",
+	-1: "error",
+	-2: "failed",
+	-3: "ignored",
+	-4: "assumption_failed",
+}
+
+// instrumentationRun is a running or finished am instrument
+type instrumentationRun struct {
+	mu      sync.Mutex
+	summary InstrumentationSummary
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+var (
+	instrumentationRuns   = make(map[string]*instrumentationRun)
+	instrumentationRunsMu sync.Mutex
+)
+
+// ListInstrumentations returns the test runners installed on the device
+func (a *App) ListInstrumentations(deviceId string) ([]InstrumentationTarget, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "pm list instrumentation").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instrumentation: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	targets := []InstrumentationTarget{}
+	for _, line := range strings.Split(string(out), "\n") {
+		m := instrumentationLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		runner := m[2]
+		if strings.HasPrefix(runner, ".") {
+			runner = m[1] + runner
+		}
+		targets = append(targets, InstrumentationTarget{
+			Component:     m[1] + "/" + m[2],
+			TestPackage:   m[1],
+			Runner:        runner,
+			TargetPackage: m[3],
+		})
+	}
+	return targets, nil
+}
+
+// buildInstrumentCommand turns the options into am instrument -r -w
+func buildInstrumentCommand(opts InstrumentationOptions) (string, error) {
+	if !strings.Contains(opts.Runner, "/") {
+		return "", fmt.Errorf("runner must be a component such as com.app.test/androidx.test.runner.AndroidJUnitRunner")
+	}
+	args := []string{"am", "instrument", "-r", "-w"}
+	class := strings.TrimSpace(opts.Class)
+	if class != "" && opts.Method != "" {
+		class += "#" + strings.TrimSpace(opts.Method)
+	}
+	extras := map[string]string{}
+	for k, v := range opts.Args {
+		extras[k] = v
+	}
+	if class != "" {
+		extras["class"] = class
+	}
+	if opts.Package != "" {
+		extras["package"] = opts.Package
+	}
+	keys := make([]string, 0, len(extras))
+	for k := range extras {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !instrumentationArgRegex.MatchString(k) {
+			return "", fmt.Errorf("invalid instrumentation argument %q", k)
+		}
+		args = append(args, "-e", k, shellQuote(extras[k]))
+	}
+	args = append(args, shellQuote(opts.Runner))
+	return strings.Join(args, " "), nil
+}
+
+// instrumentationParser follows the raw protocol of am instrument -r. Each test produces a
+// start block (code 1) and an end block; values such as stack traces can span several lines.
+type instrumentationParser struct {
+	block     map[string]string
+	result    map[string]string
+	lastKey   string
+	inResult  bool
+	started   map[string]time.Time
+	onStart   func(class, name string)
+	onFinish  func(InstrumentationTestResult)
+	code      *int
+	failedMsg string
+}
+
+func newInstrumentationParser() *instrumentationParser {
+	return &instrumentationParser{block: map[string]string{}, result: map[string]string{}, started: map[string]time.Time{}}
+}
+
+func (p *instrumentationParser) feed(line string, now time.Time) {
+	switch {
+	case strings.HasPrefix(line, "INSTRUMENTATION_STATUS: "):
+		k, v, _ := strings.Cut(strings.TrimPrefix(line, "INSTRUMENTATION_STATUS: "), "=")
+		p.block[k], p.lastKey, p.inResult = v, k, false
+	case strings.HasPrefix(line, "INSTRUMENTATION_STATUS_CODE: "):
+		code, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "INSTRUMENTATION_STATUS_CODE: ")))
+		p.endBlock(code, now)
+		p.block, p.lastKey = map[string]string{}, ""
+	case strings.HasPrefix(line, "INSTRUMENTATION_RESULT: "):
+		k, v, _ := strings.Cut(strings.TrimPrefix(line, "INSTRUMENTATION_RESULT: "), "=")
+		p.result[k], p.lastKey, p.inResult = v, k, true
+	case strings.HasPrefix(line, "INSTRUMENTATION_CODE: "):
+		code, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "INSTRUMENTATION_CODE: ")))
+		p.code = &code
+	case strings.HasPrefix(line, "INSTRUMENTATION_FAILED: "):
+		p.failedMsg = strings.TrimPrefix(line, "INSTRUMENTATION_FAILED: ")
+	case p.lastKey != "":
+		if p.inResult {
+			p.result[p.lastKey] += "\n" + line
+		} else {
+			p.block[p.lastKey] += "\n" + line
+		}
+	}
+}
+
+func (p *instrumentationParser) endBlock(code int, now time.Time) {
+	class, name := p.block["class"], p.block["test"]
+	if name == "" {
+		return
+	}
+	key := class + "#" + name
+	if code == 1 {
+		p.started[key] = now
+		if p.onStart != nil {
+			p.onStart(class, name)
+		}
+		return
+	}
+	status, ok := instrumentationStatuses[code]
+	if !ok {
+		status = "unknown"
+	}
+	result := InstrumentationTestResult{Class: class, Name: name, Status: status, Stack: strings.TrimSpace(p.block["stack"])}
+	if start, ok := p.started[key]; ok {
+		result.DurationMs = now.Sub(start).Milliseconds()
+		delete(p.started, key)
+	}
+	if p.onFinish != nil {
+		p.onFinish(result)
+	}
+}
+
+// RunInstrumentationTests runs am instrument in the background. instrumentation-test-started
+// and instrumentation-test-finished follow each test, and instrumentation-finished carries the
+// summary. With opts.PullOutputs the test output folder is pulled once the run ends.
+func (a *App) RunInstrumentationTests(deviceId string, opts InstrumentationOptions) (*InstrumentationSummary, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	command, err := buildInstrumentCommand(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	instrumentationRunsMu.Lock()
+	if r, ok := instrumentationRuns[deviceId]; ok {
+		select {
+		case <-r.done:
+		default:
+			instrumentationRunsMu.Unlock()
+			return nil, fmt.Errorf("tests are already running on %s", deviceId)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &instrumentationRun{
+		summary: InstrumentationSummary{
+			DeviceID:  deviceId,
+			Runner:    opts.Runner,
+			Command:   command,
+			State:     "running",
+			StartedAt: time.Now().Unix(),
+			Tests:     []InstrumentationTestResult{},
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	instrumentationRuns[deviceId] = run
+	instrumentationRunsMu.Unlock()
+
+	go func() {
+		defer close(run.done)
+		defer cancel()
+		start := time.Now()
+
+		parser := newInstrumentationParser()
+		parser.onStart = func(class, name string) {
+			wailsRuntime.EventsEmit(a.ctx, "instrumentation-test-started", map[string]interface{}{
+				"deviceId": deviceId,
+				"class":    class,
+				"name":     name,
+				"current":  parser.block["current"],
+				"total":    parser.block["numtests"],
+			})
+		}
+		parser.onFinish = func(r InstrumentationTestResult) {
+			run.mu.Lock()
+			s := &run.summary
+			s.Tests = append(s.Tests, r)
+			switch r.Status {
+			case "passed":
+				s.Passed++
+			case "ignored", "assumption_failed":
+				s.Ignored++
+			default:
+				s.Failed++
+			}
+			run.mu.Unlock()
+			wailsRuntime.EventsEmit(a.ctx, "instrumentation-test-finished", map[string]interface{}{
+				"deviceId": deviceId,
+				"result":   r,
+			})
+		}
+		_, err := a.runStreamedCommand(ctx, deviceId, []string{"shell", command}, func(stream, line string) {
+			if stream == "stdout" {
+				parser.feed(line, time.Now())
+			}
+		})
+
+		run.mu.Lock()
+		s := &run.summary
+		s.DurationMs = time.Since(start).Milliseconds()
+		s.Total = len(s.Tests)
+		if msg := strings.TrimSpace(parser.result["shortMsg"]); msg != "" {
+			// e.g. "Process crashed." when the app under test dies mid-run
+			s.RunError = msg
+		} else if parser.failedMsg != "" {
+			s.RunError = "instrumentation failed: " + parser.failedMsg
+		}
+		switch {
+		case ctx.Err() != nil:
+			s.State = "cancelled"
+		case err != nil && parser.code == nil:
+			s.State = "error"
+			s.RunError = err.Error()
+		case s.RunError != "" || s.Failed > 0:
+			s.State = "failed"
+		default:
+			s.State = "passed"
+		}
+		run.mu.Unlock()
+
+		if opts.PullOutputs && s.State != "cancelled" {
+			outputPath, err := a.pullTestOutputs(deviceId, opts)
+			run.mu.Lock()
+			if err != nil {
+				a.Log("Failed to pull test outputs from %s: %v", deviceId, err)
+				s.OutputError = err.Error()
+			}
+			s.OutputPath = outputPath
+			run.mu.Unlock()
+		}
+
+		run.mu.Lock()
+		summary := run.summary
+		run.mu.Unlock()
+		wailsRuntime.EventsEmit(a.ctx, "instrumentation-finished", summary)
+	}()
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	summary := run.summary
+	return &summary, nil
+}
+
+// pullTestOutputs copies the device's test output folder into a timestamped local folder
+func (a *App) pullTestOutputs(deviceId string, opts InstrumentationOptions) (string, error) {
+	remote := opts.OutputDir
+	if remote == "" {
+		remote = defaultTestOutputsDir
+	}
+	local := opts.LocalDir
+	if local == "" {
+		local = filepath.Join(a.GetRecordingsDir(), "Gaze Test Outputs", time.Now().Format("20060102_150405"))
+	}
+	if err := os.MkdirAll(local, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output folder: %w", err)
+	}
+	if _, err := a.PullFile(deviceId, remote, local, false); err != nil {
+		return "", err
+	}
+	return local, nil
+}
+
+// StopInstrumentationTests cancels a run. The runner is force-stopped through its test
+// package, since killing adb leaves the instrumentation running on the device.
+func (a *App) StopInstrumentationTests(deviceId string) (*InstrumentationSummary, error) {
+	instrumentationRunsMu.Lock()
+	run, ok := instrumentationRuns[deviceId]
+	instrumentationRunsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no test run on %s", deviceId)
+	}
+
+	run.mu.Lock()
+	testPackage, _, _ := strings.Cut(run.summary.Runner, "/")
+	run.mu.Unlock()
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "am force-stop "+shellQuote(testPackage)).CombinedOutput(); err != nil {
+		a.Log("Failed to stop %s on %s: %v, %s", testPackage, deviceId, err, strings.TrimSpace(string(out)))
+	}
+	run.cancel()
+	<-run.done
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	summary := run.summary
+	return &summary, nil
+}
+
+// GetInstrumentationSummary returns the running or most recent test run on a device
+func (a *App) GetInstrumentationSummary(deviceId string) (*InstrumentationSummary, error) {
+	instrumentationRunsMu.Lock()
+	run, ok := instrumentationRuns[deviceId]
+	instrumentationRunsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no test run on %s", deviceId)
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	summary := run.summary
+	return &summary, nil
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// intentExtraSwitches maps IntentExtra.Type to the am switch that carries it
+var intentExtraSwitches = map[string]string{
+	"string":       "--es",
+	"int":          "--ei",
+	"bool":         "--ez",
+	"long":         "--el",
+	"float":        "--ef",
+	"string_array": "--esa",
+}
+
+// intentFlagNames are the Intent flags most often needed from the intent builder
+var intentFlagNames = map[string]int64{
+	"FLAG_ACTIVITY_NEW_TASK":             0x10000000,
+	"FLAG_ACTIVITY_CLEAR_TOP":            0x04000000,
+	"FLAG_ACTIVITY_SINGLE_TOP":           0x20000000,
+	"FLAG_ACTIVITY_CLEAR_TASK":           0x00008000,
+	"FLAG_ACTIVITY_NO_HISTORY":           0x40000000,
+	"FLAG_ACTIVITY_MULTIPLE_TASK":        0x08000000,
+	"FLAG_ACTIVITY_REORDER_TO_FRONT":     0x00020000,
+	"FLAG_ACTIVITY_NO_ANIMATION":         0x00010000,
+	"FLAG_ACTIVITY_EXCLUDE_FROM_RECENTS": 0x00800000,
+	"FLAG_INCLUDE_STOPPED_PACKAGES":      0x00000020,
+	"FLAG_RECEIVER_FOREGROUND":           0x10000000,
+	"FLAG_GRANT_READ_URI_PERMISSION":     0x00000001,
+}
+
+// intentKinds are the am verbs an intent preset can run with
+var intentKinds = map[string]bool{"activity": true, "broadcast": true, "service": true}
+
+// intentExtraKeyRegex keeps extra keys to characters am accepts unquoted on every version
+var intentExtraKeyRegex = regexp.MustCompile(`^[\w.$:-]+$`)
+
+// parseIntentFlags ORs named flags and hex or decimal values together
+func parseIntentFlags(flags []string) (int64, error) {
+	var mask int64
+	for _, f := range flags {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if v, ok := intentFlagNames[strings.ToUpper(f)]; ok {
+			mask |= v
+			continue
+		}
+		v, err := strconv.ParseInt(f, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unknown intent flag %q", f)
+		}
+		mask |= v
+	}
+	return mask, nil
+}
+
+// buildIntentArgs turns an IntentSpec into quoted am arguments
+func buildIntentArgs(spec IntentSpec) ([]string, error) {
+	var args []string
+	if spec.Action != "" {
+		args = append(args, "-a", shellQuote(spec.Action))
+	}
+	if spec.Data != "" {
+		args = append(args, "-d", shellQuote(spec.Data))
+	}
+	if spec.MimeType != "" {
+		args = append(args, "-t", shellQuote(spec.MimeType))
+	}
+	for _, c := range spec.Categories {
+		if c = strings.TrimSpace(c); c != "" {
+			args = append(args, "-c", shellQuote(c))
+		}
+	}
+	if spec.Component != "" {
+		args = append(args, "-n", shellQuote(spec.Component))
+	} else if spec.Package != "" {
+		args = append(args, "-p", shellQuote(spec.Package))
+	}
+	mask, err := parseIntentFlags(spec.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if mask != 0 {
+		args = append(args, "-f", fmt.Sprintf("0x%08x", mask))
+	}
+
+	for _, e := range spec.Extras {
+		sw, ok := intentExtraSwitches[e.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown extra type %q for %s", e.Type, e.Key)
+		}
+		if !intentExtraKeyRegex.MatchString(e.Key) {
+			return nil, fmt.Errorf("invalid extra key %q", e.Key)
+		}
+		value := e.Value
+		switch e.Type {
+		case "int", "long":
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("extra %s: %q is not an integer", e.Key, value)
+			}
+		case "float":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("extra %s: %q is not a number", e.Key, value)
+			}
+		case "bool":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("extra %s: %q is not true or false", e.Key, value)
+			}
+			value = strconv.FormatBool(b)
+		case "string_array":
+			// am splits on unescaped commas
+			items := make([]string, len(e.Values))
+			for i, v := range e.Values {
+				items[i] = strings.ReplaceAll(v, ",", `\,`)
+			}
+			value = strings.Join(items, ",")
+		}
+		args = append(args, sw, shellQuote(e.Key), shellQuote(value))
+	}
+
+	if spec.Action == "" && spec.Data == "" && spec.Component == "" {
+		return nil, fmt.Errorf("an intent needs an action, data URI or component")
+	}
+	return args, nil
+}
+
+// runIntent runs am <verb> with the spec and parses what it printed
+func (a *App) runIntent(deviceId string, verb []string, spec IntentSpec) (*IntentResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	args, err := buildIntentArgs(spec)
+	if err != nil {
+		return nil, err
+	}
+	command := "am " + strings.Join(append(verb, args...), " ")
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", command).CombinedOutput()
+	result := parseAmOutput(string(out))
+	result.Command = command
+	if err != nil && result.Error == "" {
+		result.Error = strings.TrimSpace(err.Error())
+	}
+	if result.Error != "" {
+		return result, fmt.Errorf("%s", result.Error)
+	}
+	return result, nil
+}
+
+// parseAmOutput picks the status, resolved component, warnings and errors out of am's output
+func parseAmOutput(output string) *IntentResult {
+	result := &IntentResult{Output: strings.TrimSpace(output)}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, found := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch {
+		case !found:
+		case key == "Status":
+			result.Status = value
+		case key == "Activity" || key == "Component":
+			result.Activity = value
+		case key == "TotalTime":
+			result.TotalTimeMs, _ = strconv.Atoi(value)
+		case key == "Warning":
+			result.Warning = value
+		case key == "Error" || strings.HasSuffix(key, "Exception"):
+			if result.Error == "" {
+				result.Error = line
+			}
+		case key == "Broadcast completed":
+			// result=0, data="..."
+			for _, part := range strings.SplitN(value, ", ", 2) {
+				if v, ok := strings.CutPrefix(part, "result="); ok {
+					result.BroadcastResult, _ = strconv.Atoi(v)
+				} else if v, ok := strings.CutPrefix(part, "data="); ok {
+					result.BroadcastData = strings.Trim(v, `"`)
+				}
+			}
+		case key == "Service not stopped":
+			result.Error = line
+		}
+	}
+	return result
+}
+
+// StartIntent starts an activity with am start -W and reports the activity that resolved,
+// the launch time and any "Activity not started" warning
+func (a *App) StartIntent(deviceId string, intent IntentSpec) (*IntentResult, error) {
+	return a.runIntent(deviceId, []string{"start", "-W"}, intent)
+}
+
+// SendBroadcast sends the intent as a broadcast and returns the receiver's result code and data
+func (a *App) SendBroadcast(deviceId string, intent IntentSpec) (*IntentResult, error) {
+	return a.runIntent(deviceId, []string{"broadcast"}, intent)
+}
+
+// StartService starts a service; foreground uses start-foreground-service (Android 8+)
+func (a *App) StartService(deviceId string, intent IntentSpec, foreground bool) (*IntentResult, error) {
+	return a.runIntent(deviceId, []string{onOff(foreground, "start-foreground-service", "startservice")}, intent)
+}
+
+// StopService stops a service started with the same intent
+func (a *App) StopService(deviceId string, intent IntentSpec) (*IntentResult, error) {
+	return a.runIntent(deviceId, []string{"stopservice"}, intent)
+}
+
+var (
+	intentPresets       []IntentPreset
+	intentPresetsMu     sync.Mutex
+	intentPresetsLoaded bool
+)
+
+func (a *App) getIntentPresetsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "intent_presets.json")
+}
+
+// loadIntentPresetsLocked reads intent_presets.json once; callers must hold intentPresetsMu
+func (a *App) loadIntentPresetsLocked() []IntentPreset {
+	if intentPresetsLoaded {
+		return intentPresets
+	}
+	intentPresetsLoaded = true
+	if data, err := os.ReadFile(a.getIntentPresetsPath()); err == nil {
+		json.Unmarshal(data, &intentPresets)
+	}
+	return intentPresets
+}
+
+// saveIntentPresetsLocked writes intent_presets.json; callers must hold intentPresetsMu
+func (a *App) saveIntentPresetsLocked() error {
+	data, err := json.MarshalIndent(intentPresets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getIntentPresetsPath(), data, 0644)
+}
+
+// SaveIntentPreset creates or updates a named intent. kind is activity, broadcast or service.
+func (a *App) SaveIntentPreset(name, kind string, intent IntentSpec) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("preset name is required")
+	}
+	if !intentKinds[kind] {
+		return fmt.Errorf("unknown intent kind %q", kind)
+	}
+	if _, err := buildIntentArgs(intent); err != nil {
+		return err
+	}
+
+	intentPresetsMu.Lock()
+	defer intentPresetsMu.Unlock()
+	a.loadIntentPresetsLocked()
+	for i, p := range intentPresets {
+		if strings.EqualFold(p.Name, name) {
+			intentPresets[i].Kind, intentPresets[i].Intent = kind, intent
+			return a.saveIntentPresetsLocked()
+		}
+	}
+	intentPresets = append(intentPresets, IntentPreset{Name: name, Kind: kind, Intent: intent})
+	return a.saveIntentPresetsLocked()
+}
+
+// ListIntentPresets returns the saved intents sorted by name
+func (a *App) ListIntentPresets() []IntentPreset {
+	intentPresetsMu.Lock()
+	defer intentPresetsMu.Unlock()
+	result := append([]IntentPreset{}, a.loadIntentPresetsLocked()...)
+	sort.Slice(result, func(i, j int) bool { return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name) })
+	return result
+}
+
+// DeleteIntentPreset removes a saved intent
+func (a *App) DeleteIntentPreset(name string) error {
+	intentPresetsMu.Lock()
+	defer intentPresetsMu.Unlock()
+	a.loadIntentPresetsLocked()
+	for i, p := range intentPresets {
+		if strings.EqualFold(p.Name, name) {
+			intentPresets = append(intentPresets[:i], intentPresets[i+1:]...)
+			return a.saveIntentPresetsLocked()
+		}
+	}
+	return fmt.Errorf("preset not found: %s", name)
+}
+
+// RunIntentPreset sends a saved intent the way it was saved: started, broadcast or as a service
+func (a *App) RunIntentPreset(deviceId, name string) (*IntentResult, error) {
+	intentPresetsMu.Lock()
+	var preset *IntentPreset
+	for _, p := range a.loadIntentPresetsLocked() {
+		if strings.EqualFold(p.Name, name) {
+			p := p
+			preset = &p
+			break
+		}
+	}
+	intentPresetsMu.Unlock()
+	if preset == nil {
+		return nil, fmt.Errorf("preset not found: %s", name)
+	}
+
+	switch preset.Kind {
+	case "broadcast":
+		return a.SendBroadcast(deviceId, preset.Intent)
+	case "service":
+		return a.StartService(deviceId, preset.Intent, false)
+	default:
+		return a.StartIntent(deviceId, preset.Intent)
+	}
+}
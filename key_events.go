@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// maxKeyCode is the highest KEYCODE_* value accepted; Android 14 defines up to 316
+const maxKeyCode = 400
+
+// namedKeyCodes are the keys offered in the key palette
+var namedKeyCodes = []KeyCodeInfo{
+	{Name: "BACK", Code: 4, Group: "navigation"},
+	{Name: "HOME", Code: 3, Group: "navigation"},
+	{Name: "APP_SWITCH", Code: 187, Group: "navigation"},
+	{Name: "MENU", Code: 82, Group: "navigation"},
+	{Name: "SEARCH", Code: 84, Group: "navigation"},
+	{Name: "NOTIFICATION", Code: 83, Group: "navigation"},
+	{Name: "ASSIST", Code: 219, Group: "navigation"},
+	{Name: "DPAD_UP", Code: 19, Group: "dpad"},
+	{Name: "DPAD_DOWN", Code: 20, Group: "dpad"},
+	{Name: "DPAD_LEFT", Code: 21, Group: "dpad"},
+	{Name: "DPAD_RIGHT", Code: 22, Group: "dpad"},
+	{Name: "DPAD_CENTER", Code: 23, Group: "dpad"},
+	{Name: "ENTER", Code: 66, Group: "editing"},
+	{Name: "DEL", Code: 67, Group: "editing"},
+	{Name: "FORWARD_DEL", Code: 112, Group: "editing"},
+	{Name: "TAB", Code: 61, Group: "editing"},
+	{Name: "ESCAPE", Code: 111, Group: "editing"},
+	{Name: "MOVE_HOME", Code: 122, Group: "editing"},
+	{Name: "MOVE_END", Code: 123, Group: "editing"},
+	{Name: "PAGE_UP", Code: 92, Group: "editing"},
+	{Name: "PAGE_DOWN", Code: 93, Group: "editing"},
+	{Name: "VOLUME_UP", Code: 24, Group: "volume"},
+	{Name: "VOLUME_DOWN", Code: 25, Group: "volume"},
+	{Name: "VOLUME_MUTE", Code: 164, Group: "volume"},
+	{Name: "MEDIA_PLAY_PAUSE", Code: 85, Group: "media"},
+	{Name: "MEDIA_PLAY", Code: 126, Group: "media"},
+	{Name: "MEDIA_PAUSE", Code: 127, Group: "media"},
+	{Name: "MEDIA_STOP", Code: 86, Group: "media"},
+	{Name: "MEDIA_NEXT", Code: 87, Group: "media"},
+	{Name: "MEDIA_PREVIOUS", Code: 88, Group: "media"},
+	{Name: "MEDIA_REWIND", Code: 89, Group: "media"},
+	{Name: "MEDIA_FAST_FORWARD", Code: 90, Group: "media"},
+	{Name: "POWER", Code: 26, Group: "system"},
+	{Name: "WAKEUP", Code: 224, Group: "system"},
+	{Name: "SLEEP", Code: 223, Group: "system"},
+	{Name: "CAMERA", Code: 27, Group: "system"},
+	{Name: "BRIGHTNESS_UP", Code: 221, Group: "system"},
+	{Name: "BRIGHTNESS_DOWN", Code: 220, Group: "system"},
+	{Name: "SYSRQ", Code: 120, Group: "system"},
+}
+
+// browserKeyCodes maps KeyboardEvent.key values of non-printing keys to Android key codes
+var browserKeyCodes = map[string]int{
+	"Enter":      66,
+	"Backspace":  67,
+	"Delete":     112,
+	"Tab":        61,
+	"Escape":     4, // Back is what Escape means on a phone
+	"ArrowUp":    19,
+	"ArrowDown":  20,
+	"ArrowLeft":  21,
+	"ArrowRight": 22,
+	"Home":       122,
+	"End":        123,
+	"PageUp":     92,
+	"PageDown":   93,
+	"Insert":     124,
+}
+
+// ListKeyCodes returns the named key codes for the key palette
+func (a *App) ListKeyCodes() []KeyCodeInfo {
+	return append([]KeyCodeInfo{}, namedKeyCodes...)
+}
+
+// SendKeyEvent presses a key, or holds it when longPress is set
+func (a *App) SendKeyEvent(deviceId string, keycode int, longPress bool) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if keycode <= 0 || keycode > maxKeyCode {
+		return fmt.Errorf("invalid key code %d", keycode)
+	}
+	cmd := "input keyevent " + strconv.Itoa(keycode)
+	if longPress {
+		cmd = "input keyevent --longpress " + strconv.Itoa(keycode)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", cmd).CombinedOutput()
+	// Keys navigate or edit, so a cached UI dump no longer matches the screen
+	a.InvalidateUIDump(deviceId)
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return fmt.Errorf("failed to send key %d: %v, %s", keycode, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SendKeyCombo presses keys together, e.g. CTRL_LEFT + A. Android 13 added `input
+// keycombination`; older releases can only press them one after another, which the returned
+// method ("combination" or "sequence") reports.
+func (a *App) SendKeyCombo(deviceId string, keycodes []int) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if len(keycodes) == 0 {
+		return "", fmt.Errorf("no keys specified")
+	}
+	codes := make([]string, len(keycodes))
+	for i, k := range keycodes {
+		if k <= 0 || k > maxKeyCode {
+			return "", fmt.Errorf("invalid key code %d", k)
+		}
+		codes[i] = strconv.Itoa(k)
+	}
+
+	method, cmd := "sequence", "input keyevent "+strings.Join(codes, " ")
+	if len(keycodes) > 1 && a.getSDKInt(deviceId) >= 33 {
+		method, cmd = "combination", "input keycombination "+strings.Join(codes, " ")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", cmd).CombinedOutput()
+	a.InvalidateUIDump(deviceId)
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return "", fmt.Errorf("failed to send keys: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	return method, nil
+}
+
+// passthroughKey is one key press forwarded from the desktop
+type passthroughKey struct {
+	text  string // Printable character, typed as text
+	combo []int  // Otherwise the key codes to press
+}
+
+type keyboardPassthrough struct {
+	cancel func()
+	keys   chan passthroughKey
+	mu     sync.Mutex // Guards sends on keys against Stop closing it
+	closed bool
+}
+
+var (
+	keyboardPassthroughs   = make(map[string]*keyboardPassthrough)
+	keyboardPassthroughsMu sync.Mutex
+)
+
+// passthroughKeyFrom translates a frontend key event ({key, ctrl, alt, meta, shift}, key being
+// KeyboardEvent.key) into text or key codes; false for keys with no Android equivalent
+func passthroughKeyFrom(data map[string]interface{}) (passthroughKey, bool) {
+	key, _ := data["key"].(string)
+	flag := func(name string) bool { v, _ := data[name].(bool); return v }
+	ctrl, alt, meta := flag("ctrl"), flag("alt"), flag("meta")
+
+	if code, ok := browserKeyCodes[key]; ok {
+		return passthroughKey{combo: []int{code}}, true
+	}
+	if utf8.RuneCountInString(key) != 1 {
+		return passthroughKey{}, false
+	}
+	if !ctrl && !alt && !meta {
+		return passthroughKey{text: key}, true
+	}
+	// Shortcuts like Ctrl+A, Ctrl+C: KEYCODE_A is 29 and KEYCODE_0 is 7
+	r := []rune(strings.ToLower(key))[0]
+	var code int
+	switch {
+	case r >= 'a' && r <= 'z':
+		code = 29 + int(r-'a')
+	case r >= '0' && r <= '9':
+		code = 7 + int(r-'0')
+	default:
+		return passthroughKey{}, false
+	}
+	var combo []int
+	if ctrl || meta {
+		combo = append(combo, 113) // CTRL_LEFT
+	}
+	if alt {
+		combo = append(combo, 57) // ALT_LEFT
+	}
+	return passthroughKey{combo: append(combo, code)}, true
+}
+
+// StartKeyboardPassthrough forwards desktop key presses to the device. The frontend emits
+// keyboard-passthrough:<deviceId> events; printable keys are typed as text and the rest are
+// sent as key events, in the order they were pressed.
+func (a *App) StartKeyboardPassthrough(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	keyboardPassthroughsMu.Lock()
+	defer keyboardPassthroughsMu.Unlock()
+	if _, ok := keyboardPassthroughs[deviceId]; ok {
+		return nil
+	}
+
+	p := &keyboardPassthrough{keys: make(chan passthroughKey, 256)}
+	p.cancel = wailsRuntime.EventsOn(a.ctx, "keyboard-passthrough:"+deviceId, func(optionalData ...interface{}) {
+		if len(optionalData) == 0 {
+			return
+		}
+		data, ok := optionalData[0].(map[string]interface{})
+		if !ok {
+			return
+		}
+		key, ok := passthroughKeyFrom(data)
+		if !ok {
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.closed {
+			return
+		}
+		select {
+		case p.keys <- key:
+		default:
+			// The device can't keep up; dropping beats blocking the event loop
+		}
+	})
+	keyboardPassthroughs[deviceId] = p
+	go a.runKeyboardPassthrough(deviceId, p)
+	wailsRuntime.EventsEmit(a.ctx, "keyboard-passthrough-started", deviceId)
+	return nil
+}
+
+// runKeyboardPassthrough sends forwarded keys until the channel closes. Characters typed faster
+// than adb round trips are batched into one TypeText call.
+func (a *App) runKeyboardPassthrough(deviceId string, p *keyboardPassthrough) {
+	for key := range p.keys {
+		if key.text == "" {
+			if _, err := a.SendKeyCombo(deviceId, key.combo); err != nil {
+				a.Log("Keyboard pass-through on %s: %v", deviceId, err)
+			}
+			continue
+		}
+
+		text := key.text
+		var pending *passthroughKey
+	drain:
+		for {
+			select {
+			case next, ok := <-p.keys:
+				if !ok {
+					break drain
+				}
+				if next.text == "" {
+					pending = &next
+					break drain
+				}
+				text += next.text
+			default:
+				break drain
+			}
+		}
+		if _, err := a.TypeText(deviceId, text); err != nil {
+			a.Log("Keyboard pass-through on %s: %v", deviceId, err)
+		}
+		if pending != nil {
+			if _, err := a.SendKeyCombo(deviceId, pending.combo); err != nil {
+				a.Log("Keyboard pass-through on %s: %v", deviceId, err)
+			}
+		}
+	}
+}
+
+// StopKeyboardPassthrough stops forwarding key presses to the device
+func (a *App) StopKeyboardPassthrough(deviceId string) {
+	keyboardPassthroughsMu.Lock()
+	p, ok := keyboardPassthroughs[deviceId]
+	delete(keyboardPassthroughs, deviceId)
+	keyboardPassthroughsMu.Unlock()
+	if !ok {
+		return
+	}
+	p.cancel()
+	p.mu.Lock()
+	p.closed = true
+	close(p.keys)
+	p.mu.Unlock()
+	wailsRuntime.EventsEmit(a.ctx, "keyboard-passthrough-stopped", deviceId)
+}
+
+// IsKeyboardPassthroughActive reports whether key presses are being forwarded to the device
+func (a *App) IsKeyboardPassthroughActive(deviceId string) bool {
+	keyboardPassthroughsMu.Lock()
+	defer keyboardPassthroughsMu.Unlock()
+	_, ok := keyboardPassthroughs[deviceId]
+	return ok
+}
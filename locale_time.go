@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	localeTagRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+	timezoneRegex  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9_+-]+)*$`)
+)
+
+// GetLocaleAndTime reads the device's locale, time zone, clock and automatic time settings
+func (a *App) GetLocaleAndTime(deviceId string) (*LocaleTimeInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	// One value per line, in this order
+	script := strings.Join([]string{
+		`echo "$(settings get system system_locales)"`,
+		`echo "$(getprop persist.sys.locale)"`,
+		`echo "$(getprop persist.sys.language)-$(getprop persist.sys.country)"`,
+		`echo "$(getprop ro.product.locale)"`,
+		`echo "$(getprop persist.sys.timezone)"`,
+		`echo "$(date +%s)"`,
+		`echo "$(settings get global auto_time)"`,
+		`echo "$(settings get global auto_time_zone)"`,
+	}, "; ")
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale and time: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	lines := strings.Split(strings.ReplaceAll(string(out), "\r\n", "\n"), "\n")
+	get := func(i int) string {
+		if i < len(lines) {
+			if v := strings.TrimSpace(lines[i]); v != "null" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	info := &LocaleTimeInfo{Timezone: get(4), AutoTime: get(6) == "1", AutoTimezone: get(7) == "1"}
+	// persist.sys.locale is what the framework applied; system_locales is only a preference
+	// list that builds may ignore, so it comes second
+	info.Locale = get(1)
+	firstPreferred, _, _ := strings.Cut(get(0), ",")
+	for _, candidate := range []string{firstPreferred, strings.Trim(get(2), "-"), get(3)} {
+		if info.Locale != "" {
+			break
+		}
+		info.Locale = candidate
+	}
+	if sec, err := strconv.ParseInt(get(5), 10, 64); err == nil {
+		info.DeviceTime = sec
+		info.ClockSkewSec = sec - time.Now().Unix()
+	}
+	return info, nil
+}
+
+// customLocalePackage is the Custom Locale app on emulator images. It holds
+// CHANGE_CONFIGURATION, which lets it switch the system language without root.
+const customLocalePackage = "com.android.customlocale2"
+
+// SetDeviceLocale changes the system language, e.g. "ja-JP" or "ar-EG". With root the locale
+// property is set directly and applies on the next boot. Without root only emulators can do
+// it, through their Custom Locale app, which applies it at once; the system_locales setting
+// alone is ignored by the framework. The result is checked against persist.sys.locale, which
+// the framework writes when it applies a locale.
+func (a *App) SetDeviceLocale(deviceId, localeTag string) (*LocaleTimeChange, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	tag := strings.ReplaceAll(strings.TrimSpace(localeTag), "_", "-")
+	if !localeTagRegex.MatchString(tag) {
+		return nil, fmt.Errorf("invalid locale %q: expected a tag like en-US", localeTag)
+	}
+	sdk := a.getSDKInt(deviceId)
+	result := &LocaleTimeChange{Setting: "locale"}
+
+	var script string
+	if asRoot, err := a.rootWrapper(deviceId); err == nil {
+		if sdk >= 21 {
+			script = "setprop persist.sys.locale " + tag
+		} else {
+			lang, country, _ := strings.Cut(tag, "-")
+			script = fmt.Sprintf("setprop persist.sys.language %s && setprop persist.sys.country %s", lang, strings.ToUpper(country))
+		}
+		if sdk >= 24 {
+			script += " && settings put system system_locales " + tag
+		}
+		script = asRoot(script)
+		result.Method = "setprop"
+		result.RestartRequired = "reboot"
+	} else if a.hasPackage(deviceId, customLocalePackage) {
+		script = "am broadcast -a com.android.intent.action.SET_LOCALE --es com.android.intent.extra.LOCALE " + tag + " " + customLocalePackage
+		result.Method = "customlocale"
+		result.RestartRequired = "none"
+	} else {
+		return nil, fmt.Errorf("%w: without root the system language can only be changed in Settings > System > Languages", ErrRootRequired)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(string(out), "Failed") {
+		return nil, fmt.Errorf("failed to set locale: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	// The Custom Locale app updates the configuration asynchronously
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		result.Value = a.persistedLocale(deviceId, sdk)
+		if strings.EqualFold(result.Value, tag) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+	if !strings.EqualFold(result.Value, tag) {
+		return result, fmt.Errorf("locale reads back as %q", result.Value)
+	}
+	a.Log("Set locale on %s to %s via %s", deviceId, tag, result.Method)
+	return result, nil
+}
+
+// persistedLocale reads the locale the framework boots with and writes when it applies one
+func (a *App) persistedLocale(deviceId string, sdk int) string {
+	script := "getprop persist.sys.locale"
+	if sdk > 0 && sdk < 21 {
+		script = `echo "$(getprop persist.sys.language)-$(getprop persist.sys.country)"`
+	}
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", script).Output()
+	return strings.Trim(strings.TrimSpace(string(out)), "-")
+}
+
+// hasPackage reports whether pkg is installed
+func (a *App) hasPackage(deviceId, pkg string) bool {
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "pm path "+pkg).Output()
+	return strings.Contains(string(out), "package:")
+}
+
+// SetTimezone changes the time zone, e.g. "America/New_York". Android 9+ does this through the
+// alarm service without root; older releases need root to set the property.
+func (a *App) SetTimezone(deviceId, tz string) (*LocaleTimeChange, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	tz = strings.TrimSpace(tz)
+	if !timezoneRegex.MatchString(tz) {
+		return nil, fmt.Errorf("invalid time zone %q: expected an IANA name like Europe/Berlin", tz)
+	}
+	sdk := a.getSDKInt(deviceId)
+	// Apps that cached TimeZone.getDefault() keep the old zone until restarted
+	result := &LocaleTimeChange{Setting: "timezone", RestartRequired: "apps"}
+
+	// Automatic time zone would undo the change as soon as the network reports one
+	script := "settings put global auto_time_zone 0 && "
+	if sdk >= 28 {
+		script += "cmd alarm set-timezone " + tz
+		result.Method = "cmd alarm"
+	} else if asRoot, err := a.rootWrapper(deviceId); err == nil {
+		script += asRoot("setprop persist.sys.timezone " + tz)
+		result.Method = "setprop"
+	} else {
+		return nil, fmt.Errorf("%w: changing the time zone on Android %d needs root", ErrRootRequired, sdk)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(string(out), "Unknown") {
+		return nil, fmt.Errorf("failed to set time zone: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	info, err := a.GetLocaleAndTime(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	result.Value = info.Timezone
+	if info.Timezone != tz {
+		return result, fmt.Errorf("time zone reads back as %q", info.Timezone)
+	}
+	a.Log("Set time zone on %s to %s via %s", deviceId, tz, result.Method)
+	return result, nil
+}
+
+// SetDeviceTime sets the device clock, turning off automatic (network) time so it sticks.
+// Only root can set the clock.
+func (a *App) SetDeviceTime(deviceId string, t time.Time) (*LocaleTimeChange, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	asRoot, err := a.rootWrapper(deviceId)
+	if err != nil {
+		return nil, fmt.Errorf("setting the clock is not supported on this device: %w", err)
+	}
+	utc := t.UTC()
+	var dateCmd string
+	if a.getSDKInt(deviceId) >= 23 {
+		// toybox: MMDDhhmmCCYY.ss
+		dateCmd = "date -u " + utc.Format("010215042006.05")
+	} else {
+		// toolbox: -s YYYYMMDD.hhmmss
+		dateCmd = "date -u -s " + utc.Format("20060102.150405")
+	}
+	script := "settings put global auto_time 0 && " + asRoot(dateCmd)
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set time: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	// Lets the clock and scheduled alarms catch up; refused from the shell on some builds
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", asRoot("am broadcast -a android.intent.action.TIME_SET")).Run()
+
+	info, err := a.GetLocaleAndTime(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	result := &LocaleTimeChange{
+		Setting:         "time",
+		Value:           time.Unix(info.DeviceTime, 0).UTC().Format(time.RFC3339),
+		Method:          "date",
+		RestartRequired: "none",
+	}
+	if d := info.DeviceTime - utc.Unix(); d < -5 || d > 5 {
+		return result, fmt.Errorf("device clock reads %s", result.Value)
+	}
+	a.Log("Set time on %s to %s", deviceId, utc.Format(time.RFC3339))
+	return result, nil
+}
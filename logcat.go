@@ -5,228 +5,764 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// StartLogcat starts the logcat stream for a device
-func (a *App) StartLogcat(deviceId, packageName, preFilter string, preUseRegex bool, excludeFilter string, excludeUseRegex bool) error {
+// logcatSession is a logcat stream that may restart its underlying process
+// (e.g. when the watched package gets a new PID) while keeping the same ID
+type logcatSession struct {
+	ID          string
+	DeviceID    string
+	PackageName string
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	// usePidFlag is true when the device's logcat supports --pid (Android 7+)
+	usePidFlag bool
+
+	// buffers are the -b buffers to read; empty means logcat's default set
+	buffers []string
+
+	procMu     sync.Mutex
+	procCancel context.CancelFunc
+	procPid    string
+	procCmd    *exec.Cmd
+
+	pidMu sync.RWMutex
+	pids  []string
+
+	statusMu sync.Mutex
+	statuses []LogcatStatus
+
+	matcher   *logcatMatcher
+	matcherMu sync.RWMutex
+
+	stats *logcatStats
+	// statsEvents turns on the periodic logcat-stats event; see SetLogcatStatsEvents
+	statsEvents atomic.Bool
+
+	// started is set once StartLogcatSession has run
+	started atomic.Bool
+}
+
+// maxLogcatStatuses caps the status history kept per session
+const maxLogcatStatuses = 50
+
+var logcatSeq int64
+
+// CreateLogcatSession sets up a logcat session for a device and returns its ID without
+// reading anything yet, so the caller can subscribe to its events before StartLogcatSession.
+// Parsed records that pass the filter are emitted as "logcat-data:<sessionId>" events and
+// package tracking notices as "logcat-status:<sessionId>" events. StopLogcat drops a session
+// that was never started like any other.
+func (a *App) CreateLogcatSession(deviceId, packageName string, filter LogcatFilter, buffers []string) (string, error) {
 	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if err := validateLogBuffers(buffers); err != nil {
+		return "", err
+	}
 
-	if a.logcatCmd != nil {
-		a.StopLogcat()
+	matcher, err := compileLogcatFilter(filter)
+	if err != nil {
+		return "", err
 	}
 
+	a.logcatMu.Lock()
+	logcatSeq++
+	sessionId := fmt.Sprintf("logcat_%d_%d", time.Now().Unix(), logcatSeq)
+	a.logcatMu.Unlock()
+
 	ctx, cancel := context.WithCancel(context.Background())
-	a.logcatCancel = cancel
+	session := &logcatSession{
+		ID:          sessionId,
+		DeviceID:    deviceId,
+		PackageName: packageName,
+		ctx:         ctx,
+		cancel:      cancel,
+		matcher:     matcher,
+		usePidFlag:  packageName != "" && a.getSDKInt(deviceId) >= 24,
+		stats:       newLogcatStats(),
+		buffers:     buffers,
+	}
 
-	var cmd *exec.Cmd
-	shellCmd := "logcat -v time"
+	a.logcatMu.Lock()
+	a.logcatSessions[sessionId] = session
+	a.logcatMu.Unlock()
 
-	if preFilter != "" {
-		grepCmd := "grep -i"
-		if preUseRegex {
-			grepCmd += "E"
-		}
-		safeFilter := strings.ReplaceAll(preFilter, "'", "'\\''")
-		shellCmd += fmt.Sprintf(" | %s '%s'", grepCmd, safeFilter)
+	return sessionId, nil
+}
+
+// StartLogcatSession starts streaming a session made by CreateLogcatSession
+func (a *App) StartLogcatSession(sessionId string) error {
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+	if !session.started.CompareAndSwap(false, true) {
+		return fmt.Errorf("logcat session already started: %s", sessionId)
 	}
 
-	if excludeFilter != "" {
-		grepCmd := "grep -iv"
-		if excludeUseRegex {
-			grepCmd += "E"
+	// Without a package, or when --pid is unavailable, stream everything right away;
+	// otherwise the PID watcher starts the process once the app is running
+	if session.PackageName == "" || !session.usePidFlag {
+		if err := a.startLogcatProcess(session, "", false); err != nil {
+			a.stopLogcatSession(session)
+			return err
 		}
-		safeExclude := strings.ReplaceAll(excludeFilter, "'", "'\\''")
-		shellCmd += fmt.Sprintf(" | %s '%s'", grepCmd, safeExclude)
 	}
 
-	if preFilter != "" || excludeFilter != "" {
-		cmd = exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "shell", shellCmd)
-	} else {
-		cmd = exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "logcat", "-v", "time")
+	if session.PackageName != "" {
+		go a.watchLogcatPids(session)
+	}
+	go a.emitLogcatStats(session)
+	return nil
+}
+
+// startLogcatProcess (re)starts the adb logcat process of a session, optionally scoped with --pid.
+// With tailOnly set the buffer backlog is skipped, which avoids replaying lines on a restart.
+func (a *App) startLogcatProcess(session *logcatSession, pid string, tailOnly bool) error {
+	session.procMu.Lock()
+	defer session.procMu.Unlock()
+
+	if session.procCancel != nil {
+		session.procCancel()
+		session.procCancel = nil
+	}
+	if session.ctx.Err() != nil {
+		return fmt.Errorf("logcat session stopped")
+	}
+
+	args := []string{"-s", session.DeviceID, "logcat", "-v", "threadtime"}
+	if len(session.buffers) > 0 {
+		args = append(args, "-b", strings.Join(session.buffers, ","))
 	}
-	a.logcatCmd = cmd
+	if pid != "" {
+		args = append(args, "--pid="+pid)
+	}
+	if tailOnly {
+		args = append(args, "-T", "1")
+	}
+
+	procCtx, procCancel := context.WithCancel(session.ctx)
+	cmd := exec.CommandContext(procCtx, a.adbPath, args...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		cancel()
-		a.logcatCmd = nil
+		procCancel()
 		return fmt.Errorf("failed to get stdout pipe: %w", err)
 	}
-
 	if err := cmd.Start(); err != nil {
-		cancel()
-		a.logcatCmd = nil
+		procCancel()
 		return fmt.Errorf("failed to start logcat: %w", err)
 	}
 
-	var currentPids []string
-	var currentUid string
-	var pidMutex sync.RWMutex
+	session.procCancel = procCancel
+	session.procPid = pid
+	session.procCmd = cmd
+	a.trackProcess("logcat", cmd, procCancel)
+
+	go a.readLogcat(session, procCtx, cmd, bufio.NewReader(stdout), pid != "")
+	return nil
+}
+
+// readLogcat pumps one logcat process into batched events until it exits
+func (a *App) readLogcat(session *logcatSession, procCtx context.Context, cmd *exec.Cmd, reader *bufio.Reader, pidScoped bool) {
+	dataEvent := "logcat-data:" + session.ID
+
+	var (
+		chunk      []LogcatRecord
+		maxChunk   = 200
+		flushInter = 100 * time.Millisecond
+		lastFlush  = time.Now()
+	)
+
+	// logcat announces buffer switches with "--------- beginning of <buffer>" / "switch to <buffer>"
+	buffer := "main"
+	if len(session.buffers) == 1 {
+		buffer = session.buffers[0]
+	}
 
-	if packageName != "" {
-		uidCmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "pm list packages -U "+packageName)
-		uidOut, _ := uidCmd.Output()
-		uidStr := string(uidOut)
-		if strings.Contains(uidStr, "uid:") {
-			parts := strings.Split(uidStr, "uid:")
-			if len(parts) > 1 {
-				currentUid = strings.TrimSpace(strings.Fields(parts[1])[0])
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		if m := bufferBannerRegex.FindStringSubmatch(line); m != nil {
+			buffer = m[1]
+		}
+
+		var record LogcatRecord
+		if buffer == "events" {
+			record = parseEventLogLine(line)
+		} else {
+			record = parseLogcatLine(line)
+		}
+		record.Buffer = buffer
+
+		// Without --pid (pre-Nougat, or several processes): match the parsed pid field against
+		// the watched processes
+		if session.PackageName != "" && !pidScoped {
+			session.pidMu.RLock()
+			found := false
+			for _, pid := range session.pids {
+				if record.PID != 0 && strconv.Itoa(record.PID) == pid {
+					found = true
+					break
+				}
 			}
+			session.pidMu.RUnlock()
+			if !found {
+				continue
+			}
+		}
+
+		session.stats.add(record)
+
+		session.matcherMu.RLock()
+		keep := session.matcher.match(record)
+		session.matcherMu.RUnlock()
+		if !keep {
+			continue
+		}
+
+		chunk = append(chunk, record)
+
+		if len(chunk) >= maxChunk || (len(chunk) > 0 && time.Since(lastFlush) >= flushInter) {
+			wailsRuntime.EventsEmit(a.ctx, dataEvent, chunk)
+			chunk = nil
+			lastFlush = time.Now()
 		}
 	}
 
-	if packageName != "" {
-		go func() {
-			ticker := time.NewTicker(2 * time.Second)
-			defer ticker.Stop()
+	if len(chunk) > 0 {
+		wailsRuntime.EventsEmit(a.ctx, dataEvent, chunk)
+	}
 
-			checkPid := func() {
-				c := exec.Command(a.adbPath, "-s", deviceId, "shell", "pgrep -f", packageName)
-				out, _ := c.Output()
-				raw := strings.TrimSpace(string(out))
+	replaced := procCtx.Err() != nil && session.ctx.Err() == nil
+	stoppedByUser := session.ctx.Err() != nil
+	waitErr := cmd.Wait()
+	a.untrackProcess(cmd)
 
-				if raw == "" {
-					c2 := exec.Command(a.adbPath, "-s", deviceId, "shell", "pidof", packageName)
-					out2, _ := c2.Output()
-					raw = strings.TrimSpace(string(out2))
-				}
+	if replaced {
+		// A newer process took over this session
+		return
+	}
 
-				if raw == "" {
-					c3 := exec.Command(a.adbPath, "-s", deviceId, "shell", "ps -A")
-					out3, _ := c3.Output()
-					lines := strings.Split(string(out3), "\n")
-					var matchedPids []string
-					for _, line := range lines {
-						if strings.Contains(line, packageName) {
-							fields := strings.Fields(line)
-							if len(fields) > 1 {
-								matchedPids = append(matchedPids, fields[1])
-							}
-						}
-					}
-					raw = strings.Join(matchedPids, " ")
-				}
+	// With --pid, logcat exits when the process dies; the PID watcher will attach to the next one
+	if pidScoped && !stoppedByUser {
+		session.procMu.Lock()
+		session.procCancel = nil
+		session.procPid = ""
+		session.procMu.Unlock()
+		return
+	}
 
-				pids := strings.Fields(raw)
-
-				pidMutex.Lock()
-				changed := len(pids) != len(currentPids)
-				if !changed {
-					for i, p := range pids {
-						if p != currentPids[i] {
-							changed = true
-							break
-						}
-					}
-				}
+	reason := "stopped"
+	if !stoppedByUser {
+		reason = "process exited"
+		if waitErr != nil {
+			reason = fmt.Sprintf("process exited: %v", waitErr)
+		}
+	}
+	session.cancel()
 
-				if changed {
-					currentPids = pids
-					if len(pids) > 0 {
-						status := fmt.Sprintf("--- Monitoring %s (UID: %s, PIDs: %s) ---", packageName, currentUid, strings.Join(pids, ", "))
-						wailsRuntime.EventsEmit(a.ctx, "logcat-data", status)
-					} else {
-						wailsRuntime.EventsEmit(a.ctx, "logcat-data", fmt.Sprintf("--- Waiting for %s processes... ---", packageName))
-					}
-				}
-				pidMutex.Unlock()
-			}
+	a.logcatMu.Lock()
+	if a.logcatSessions[session.ID] == session {
+		delete(a.logcatSessions, session.ID)
+	}
+	a.logcatMu.Unlock()
 
-			checkPid()
+	wailsRuntime.EventsEmit(a.ctx, "logcat-stopped", map[string]interface{}{
+		"sessionId": session.ID,
+		"deviceId":  session.DeviceID,
+		"reason":    reason,
+	})
+}
 
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-ticker.C:
-					checkPid()
+// watchLogcatPids polls the package's processes and re-targets the stream when they change
+func (a *App) watchLogcatPids(session *logcatSession) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	check := func() {
+		pids := a.findPackagePids(session.ctx, session.DeviceID, session.PackageName)
+		if session.ctx.Err() != nil {
+			return
+		}
+
+		session.pidMu.Lock()
+		changed := strings.Join(pids, ",") != strings.Join(session.pids, ",")
+		wasRunning := len(session.pids) > 0
+		session.pids = pids
+		session.pidMu.Unlock()
+
+		if session.usePidFlag && len(pids) > 0 {
+			// --pid takes a single PID, so an app running several processes is read unscoped
+			// and filtered on all of them, like on pre-Nougat devices
+			want := ""
+			if len(pids) == 1 {
+				want = pids[0]
+			}
+			session.procMu.Lock()
+			attached, running := session.procPid, session.procCancel != nil
+			session.procMu.Unlock()
+			if !running || attached != want {
+				// A running stream already showed the backlog of the processes it covered
+				if err := a.startLogcatProcess(session, want, running); err != nil {
+					a.Log("Failed to restart logcat for %s: %v", session.PackageName, err)
 				}
 			}
-		}()
+		}
+
+		if !changed {
+			return
+		}
+		switch {
+		case len(pids) == 0:
+			a.emitLogcatStatus(session, "waiting", nil, fmt.Sprintf("Waiting for %s processes...", session.PackageName))
+		case wasRunning:
+			a.emitLogcatStatus(session, "restarted", pids, fmt.Sprintf("%s restarted (PIDs: %s)", session.PackageName, strings.Join(pids, ", ")))
+		default:
+			a.emitLogcatStatus(session, "attached", pids, fmt.Sprintf("Monitoring %s (PIDs: %s)", session.PackageName, strings.Join(pids, ", ")))
+		}
 	}
 
-	go func() {
-		reader := bufio.NewReader(stdout)
+	a.emitLogcatStatus(session, "waiting", nil, fmt.Sprintf("Waiting for %s processes...", session.PackageName))
+	check()
 
-		var (
-			chunk      []string
-			maxChunk   = 200
-			flushInter = 100 * time.Millisecond
-			lastFlush  = time.Now()
-		)
+	for {
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
 
-		for {
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				break
-			}
+// findPackagePids returns the PIDs of a package, main process first
+func (a *App) findPackagePids(ctx context.Context, deviceId, packageName string) []string {
+	// pidof matches the exact process name, so the main process comes back on its own
+	out, _ := exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "shell", "pidof", packageName).Output()
+	pids := strings.Fields(string(out))
+
+	// Secondary processes (":remote" etc.) and devices without pidof
+	psOut, _ := exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "shell", "ps -A -o PID,NAME 2>/dev/null || ps").Output()
+	seen := make(map[string]bool)
+	for _, p := range pids {
+		seen[p] = true
+	}
+	for _, line := range strings.Split(string(psOut), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		name := fields[len(fields)-1]
+		if name != packageName && !strings.HasPrefix(name, packageName+":") {
+			continue
+		}
+		// "ps -o PID,NAME" puts the pid first; legacy ps puts it second
+		pid := fields[0]
+		if _, err := strconv.Atoi(pid); err != nil && len(fields) > 1 {
+			pid = fields[1]
+		}
+		if !seen[pid] {
+			seen[pid] = true
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
 
-			if packageName != "" {
-				pidMutex.RLock()
-				pids := currentPids
-				uid := currentUid
-				pidMutex.RUnlock()
-
-				if len(pids) > 0 {
-					found := false
-					for _, pid := range pids {
-						if strings.Contains(line, "("+pid+")") ||
-							strings.Contains(line, "( "+pid+")") ||
-							strings.Contains(line, "("+pid+" )") ||
-							strings.Contains(line, "["+pid+"]") ||
-							strings.Contains(line, "[ "+pid+"]") ||
-							strings.Contains(line, " "+pid+":") ||
-							strings.Contains(line, "/"+pid+"(") ||
-							strings.Contains(line, " "+pid+" ") ||
-							strings.Contains(line, " "+pid+"):") ||
-							strings.Contains(line, " "+pid+":") {
-							found = true
-							break
-						}
-					}
-
-					if !found && uid != "" && strings.Contains(line, " "+uid+" ") {
-						found = true
-					}
-
-					if !found {
-						continue
-					}
-				} else {
-					continue
-				}
-			}
+// emitLogcatStatus records a package tracking notice and emits it as logcat-status:<sessionId>
+func (a *App) emitLogcatStatus(session *logcatSession, state string, pids []string, message string) {
+	status := LogcatStatus{
+		SessionID: session.ID,
+		State:     state,
+		PIDs:      pids,
+		Message:   message,
+		Timestamp: time.Now().UnixMilli(),
+	}
 
-			chunk = append(chunk, line)
+	session.statusMu.Lock()
+	session.statuses = append(session.statuses, status)
+	if len(session.statuses) > maxLogcatStatuses {
+		session.statuses = session.statuses[len(session.statuses)-maxLogcatStatuses:]
+	}
+	session.statusMu.Unlock()
 
-			if len(chunk) >= maxChunk || (len(chunk) > 0 && time.Since(lastFlush) >= flushInter) {
-				wailsRuntime.EventsEmit(a.ctx, "logcat-data", chunk)
-				chunk = nil
-				lastFlush = time.Now()
-			}
+	wailsRuntime.EventsEmit(a.ctx, "logcat-status:"+session.ID, status)
+}
+
+// GetLogcatStatuses returns the package tracking notices of a session, oldest first
+func (a *App) GetLogcatStatuses(sessionId string) ([]LogcatStatus, error) {
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	session.statusMu.Lock()
+	defer session.statusMu.Unlock()
+	result := make([]LogcatStatus, len(session.statuses))
+	copy(result, session.statuses)
+	return result, nil
+}
+
+// UpdateLogcatFilter swaps the filter of a running session without restarting the stream
+func (a *App) UpdateLogcatFilter(sessionId string, filter LogcatFilter) error {
+	matcher, err := compileLogcatFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	session.matcherMu.Lock()
+	session.matcher = matcher
+	session.matcherMu.Unlock()
+	return nil
+}
+
+// StopLogcat stops a logcat stream by session ID, or every stream of a device when given a device ID
+func (a *App) StopLogcat(id string) {
+	a.logcatMu.Lock()
+	var toStop []*logcatSession
+	for sessionId, s := range a.logcatSessions {
+		if sessionId == id || s.DeviceID == id {
+			toStop = append(toStop, s)
 		}
+	}
+	a.logcatMu.Unlock()
 
-		if len(chunk) > 0 {
-			wailsRuntime.EventsEmit(a.ctx, "logcat-data", chunk)
+	for _, s := range toStop {
+		a.stopLogcatSession(s)
+	}
+}
+
+// StopAllLogcat stops every running logcat stream
+func (a *App) StopAllLogcat() {
+	a.logcatMu.Lock()
+	var toStop []*logcatSession
+	for _, s := range a.logcatSessions {
+		toStop = append(toStop, s)
+	}
+	a.logcatMu.Unlock()
+
+	for _, s := range toStop {
+		a.stopLogcatSession(s)
+	}
+}
+
+// ListLogcatSessions returns the IDs of running logcat sessions mapped to their device
+func (a *App) ListLogcatSessions() map[string]string {
+	a.logcatMu.Lock()
+	defer a.logcatMu.Unlock()
+
+	result := make(map[string]string, len(a.logcatSessions))
+	for id, s := range a.logcatSessions {
+		result[id] = s.DeviceID
+	}
+	return result
+}
+
+// stopLogcatSession cancels a session. A running reader emits logcat-stopped itself;
+// a session still waiting for its package has no reader, so the event is sent here.
+func (a *App) stopLogcatSession(s *logcatSession) {
+	s.procMu.Lock()
+	hasProc := s.procCancel != nil
+	s.procMu.Unlock()
+
+	// The reader untracks the process once Wait returns, so shutdown still waits for it to exit
+	s.cancel()
+
+	if !hasProc {
+		a.logcatMu.Lock()
+		if a.logcatSessions[s.ID] == s {
+			delete(a.logcatSessions, s.ID)
+		}
+		a.logcatMu.Unlock()
+
+		wailsRuntime.EventsEmit(a.ctx, "logcat-stopped", map[string]interface{}{
+			"sessionId": s.ID,
+			"deviceId":  s.DeviceID,
+			"reason":    "stopped",
+		})
+	}
+}
+
+// logBufferNames are the buffers accepted by logcat -b
+var logBufferNames = map[string]bool{
+	"main": true, "system": true, "crash": true, "events": true, "radio": true, "kernel": true, "security": true, "stats": true, "all": true, "default": true,
+}
+
+func validateLogBuffers(buffers []string) error {
+	for _, b := range buffers {
+		if !logBufferNames[b] {
+			return fmt.Errorf("unknown log buffer: %s", b)
 		}
-	}()
+	}
+	return nil
+}
+
+// SetLogcatBuffers changes which buffers a session reads, restarting its stream under the same ID
+func (a *App) SetLogcatBuffers(sessionId string, buffers []string) error {
+	if err := validateLogBuffers(buffers); err != nil {
+		return err
+	}
+
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	session.procMu.Lock()
+	session.buffers = buffers
+	running := session.procCancel != nil
+	pid := session.procPid
+	session.procMu.Unlock()
+
+	// A package session still waiting for its process picks up the buffers when it attaches
+	if !running {
+		return nil
+	}
+	return a.startLogcatProcess(session, pid, true)
+}
+
+// bufferSizeRegex matches `logcat -g` lines such as
+// "main: ring buffer is 256 KiB (241 KiB consumed), max entry is 5120 B, ..." or the older "256Kb (255Kb consumed)"
+var bufferSizeRegex = regexp.MustCompile(`^(\w+): ring buffer is (\d+)\s*([KMG]i?B|[KMG]b|b|B)? \((\d+)\s*([KMG]i?B|[KMG]b|b|B)? consumed\)`)
+
+// GetLogBufferSize reports the size and usage of each log ring buffer
+func (a *App) GetLogBufferSize(deviceId string) ([]LogBufferInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	output, err := a.RunAdbCommand(deviceId, "shell logcat -b all -g")
+	if err != nil {
+		// Older logcat does not know the "all" pseudo buffer
+		output, err = a.RunAdbCommand(deviceId, "shell logcat -g")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []LogBufferInfo
+	for _, line := range strings.Split(output, "\n") {
+		m := bufferSizeRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(m[2], 10, 64)
+		consumed, _ := strconv.ParseInt(m[4], 10, 64)
+		result = append(result, LogBufferInfo{
+			Buffer:        m[1],
+			SizeBytes:     size * logSizeUnit(m[3]),
+			ConsumedBytes: consumed * logSizeUnit(m[5]),
+		})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("unexpected logcat -g output: %s", output)
+	}
+	return result, nil
+}
+
+// SetLogBufferSize resizes all log ring buffers, e.g. "256K" or "16M"
+func (a *App) SetLogBufferSize(deviceId, size string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	size = strings.ToUpper(strings.TrimSpace(size))
+	if !regexp.MustCompile(`^\d+[KM]?$`).MatchString(size) {
+		return fmt.Errorf("invalid buffer size %q, expected e.g. 256K or 16M", size)
+	}
 
+	output, err := a.RunAdbCommand(deviceId, "shell logcat -G "+size)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(strings.ToLower(output), "failed") || strings.Contains(strings.ToLower(output), "invalid") {
+		return fmt.Errorf("failed to set log buffer size: %s", output)
+	}
 	return nil
 }
 
-// StopLogcat stops the logcat stream
-func (a *App) StopLogcat() {
-	if a.logcatCancel != nil {
-		a.logcatCancel()
+func logSizeUnit(unit string) int64 {
+	switch strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "b")) {
+	case "K", "KI":
+		return 1024
+	case "M", "MI":
+		return 1024 * 1024
+	case "G", "GI":
+		return 1024 * 1024 * 1024
 	}
-	if a.logcatCmd != nil && a.logcatCmd.Process != nil {
-		_ = a.logcatCmd.Process.Kill()
+	return 1
+}
+
+// threadtimeRegex matches `logcat -v threadtime` lines: date time pid tid priority tag: message
+var threadtimeRegex = regexp.MustCompile(`^(\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2}\.\d+)\s+(\d+)\s+(\d+)\s+([VDIWEFS])\s+(.*?)\s*: ?(.*)$`)
+
+// logcatPriorities orders priorities from least to most severe
+var logcatPriorities = map[string]int{"V": 0, "D": 1, "I": 2, "W": 3, "E": 4, "F": 5, "S": 6}
+
+// parseLogcatLine turns a threadtime line into a record; unparseable lines keep their text as the message
+func parseLogcatLine(line string) LogcatRecord {
+	line = strings.TrimRight(line, "\r\n")
+	m := threadtimeRegex.FindStringSubmatch(line)
+	if m == nil {
+		return LogcatRecord{Message: line}
+	}
+	pid, _ := strconv.Atoi(m[2])
+	tid, _ := strconv.Atoi(m[3])
+	return LogcatRecord{
+		Timestamp: m[1],
+		PID:       pid,
+		TID:       tid,
+		Priority:  m[4],
+		Tag:       strings.TrimSpace(m[5]),
+		Message:   m[6],
+	}
+}
+
+// bufferBannerRegex matches logcat's buffer separator lines
+var bufferBannerRegex = regexp.MustCompile(`^-+ (?:beginning of|switch to) (\w+)`)
+
+// parseEventLogLine parses an events-buffer line. Event tags are names or raw numeric IDs and the
+// payload is a bracketed value list that may carry non-printable bytes, so it gets its own branch.
+func parseEventLogLine(line string) LogcatRecord {
+	line = strings.Map(func(r rune) rune {
+		if r == '\t' || (r >= 0x20 && r != 0x7f && r != utf8.RuneError) {
+			return r
+		}
+		return '.'
+	}, strings.TrimRight(line, "\r\n"))
+
+	record := parseLogcatLine(line)
+	if record.Priority == "" {
+		return record
+	}
+	record.Tag = strings.TrimSpace(record.Tag)
+	record.Message = strings.TrimSpace(record.Message)
+	return record
+}
+
+// logcatMatcher is the compiled form of a LogcatFilter
+type logcatMatcher struct {
+	minPriority  int
+	tags         map[string]bool
+	excludeTags  map[string]bool
+	messageRegex *regexp.Regexp
+	include      func(string) bool
+	exclude      func(string) bool
+}
+
+func compileLogcatFilter(filter LogcatFilter) (*logcatMatcher, error) {
+	m := &logcatMatcher{}
+
+	if filter.MinPriority != "" {
+		level, ok := logcatPriorities[strings.ToUpper(filter.MinPriority[:1])]
+		if !ok {
+			return nil, fmt.Errorf("invalid priority: %s", filter.MinPriority)
+		}
+		m.minPriority = level
+	}
+
+	if len(filter.Tags) > 0 {
+		m.tags = make(map[string]bool)
+		for _, t := range filter.Tags {
+			m.tags[t] = true
+		}
+	}
+	if len(filter.ExcludeTags) > 0 {
+		m.excludeTags = make(map[string]bool)
+		for _, t := range filter.ExcludeTags {
+			m.excludeTags[t] = true
+		}
+	}
+
+	if filter.MessageRegex != "" {
+		re, err := regexp.Compile(filter.MessageRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message regex: %w", err)
+		}
+		m.messageRegex = re
+	}
+
+	var err error
+	if m.include, err = textMatcher(filter.Include, filter.IncludeRegex); err != nil {
+		return nil, err
+	}
+	if m.exclude, err = textMatcher(filter.Exclude, filter.ExcludeRegex); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// textMatcher builds a case-insensitive substring or regex test; nil when pattern is empty
+func textMatcher(pattern string, useRegex bool) (func(string) bool, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if useRegex {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter regex: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	lower := strings.ToLower(pattern)
+	return func(s string) bool { return strings.Contains(strings.ToLower(s), lower) }, nil
+}
+
+func (m *logcatMatcher) match(r LogcatRecord) bool {
+	if m == nil {
+		return true
+	}
+	// Lines without a priority (buffer banners etc.) bypass the structured checks
+	if r.Priority != "" {
+		if logcatPriorities[r.Priority] < m.minPriority {
+			return false
+		}
+		if m.tags != nil && !m.tags[r.Tag] {
+			return false
+		}
+		if m.excludeTags[r.Tag] {
+			return false
+		}
+	}
+	if m.messageRegex != nil && !m.messageRegex.MatchString(r.Message) {
+		return false
+	}
+	if m.include != nil || m.exclude != nil {
+		text := r.Tag + ": " + r.Message
+		if m.include != nil && !m.include(text) {
+			return false
+		}
+		if m.exclude != nil && m.exclude(text) {
+			return false
+		}
 	}
-	a.logcatCmd = nil
-	a.logcatCancel = nil
+	return true
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	logcatStatsStripes  = 16
+	logcatStatsTopTags  = 20
+	logcatStatsInterval = 3 * time.Second
+)
+
+// logcatPriorityOrder lists priorities in index order for the counters below
+var logcatPriorityOrder = []string{"V", "D", "I", "W", "E", "F", "S"}
+
+// logcatStats aggregates line counts for a session. Tag counters are spread over
+// several locks so the reader goroutine rarely contends with GetLogcatStats.
+type logcatStats struct {
+	stripes [logcatStatsStripes]struct {
+		mu   sync.Mutex
+		tags map[string]int64
+	}
+	priorities [7]int64 // atomic, indexed like logcatPriorityOrder
+	unparsed   int64    // atomic
+	total      int64    // atomic
+	since      int64    // atomic, unix millis
+
+	// Per-second error buckets for the rolling errors-per-minute figure
+	errMu      sync.Mutex
+	errBuckets [60]int64
+	errSeconds [60]int64
+}
+
+func newLogcatStats() *logcatStats {
+	s := &logcatStats{}
+	s.reset()
+	return s
+}
+
+func (s *logcatStats) reset() {
+	for i := range s.stripes {
+		s.stripes[i].mu.Lock()
+		s.stripes[i].tags = make(map[string]int64)
+		s.stripes[i].mu.Unlock()
+	}
+	for i := range s.priorities {
+		atomic.StoreInt64(&s.priorities[i], 0)
+	}
+	atomic.StoreInt64(&s.unparsed, 0)
+	atomic.StoreInt64(&s.total, 0)
+	atomic.StoreInt64(&s.since, time.Now().UnixMilli())
+
+	s.errMu.Lock()
+	s.errBuckets = [60]int64{}
+	s.errSeconds = [60]int64{}
+	s.errMu.Unlock()
+}
+
+// add counts one record
+func (s *logcatStats) add(r LogcatRecord) {
+	atomic.AddInt64(&s.total, 1)
+
+	level, ok := logcatPriorities[r.Priority]
+	if r.Priority == "" || !ok {
+		atomic.AddInt64(&s.unparsed, 1)
+		return
+	}
+	atomic.AddInt64(&s.priorities[level], 1)
+
+	h := fnv.New32a()
+	h.Write([]byte(r.Tag))
+	stripe := &s.stripes[h.Sum32()%logcatStatsStripes]
+	stripe.mu.Lock()
+	stripe.tags[r.Tag]++
+	stripe.mu.Unlock()
+
+	// S is only a filter level; nothing real is logged at it
+	if r.Priority == "E" || r.Priority == "F" {
+		now := time.Now().Unix()
+		idx := now % 60
+		s.errMu.Lock()
+		if s.errSeconds[idx] != now {
+			s.errSeconds[idx] = now
+			s.errBuckets[idx] = 0
+		}
+		s.errBuckets[idx]++
+		s.errMu.Unlock()
+	}
+}
+
+// snapshot returns the current counters
+func (s *logcatStats) snapshot(sessionId string) LogcatStats {
+	result := LogcatStats{
+		SessionID:  sessionId,
+		Total:      atomic.LoadInt64(&s.total),
+		Unparsed:   atomic.LoadInt64(&s.unparsed),
+		ByPriority: make(map[string]int64),
+		Since:      atomic.LoadInt64(&s.since),
+	}
+	for i, p := range logcatPriorityOrder {
+		result.ByPriority[p] = atomic.LoadInt64(&s.priorities[i])
+	}
+
+	var tags []TagCount
+	for i := range s.stripes {
+		s.stripes[i].mu.Lock()
+		for tag, count := range s.stripes[i].tags {
+			tags = append(tags, TagCount{Tag: tag, Count: count})
+		}
+		s.stripes[i].mu.Unlock()
+	}
+	result.TagCount = len(tags)
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Count > tags[j].Count })
+	if len(tags) > logcatStatsTopTags {
+		tags = tags[:logcatStatsTopTags]
+	}
+	result.TopTags = tags
+
+	now := time.Now().Unix()
+	s.errMu.Lock()
+	for i := range s.errBuckets {
+		if now-s.errSeconds[i] < 60 {
+			result.ErrorsPerMinute += s.errBuckets[i]
+		}
+	}
+	s.errMu.Unlock()
+
+	return result
+}
+
+// emitLogcatStats publishes a stats snapshot periodically, while enabled, until the session ends
+func (a *App) emitLogcatStats(session *logcatSession) {
+	ticker := time.NewTicker(logcatStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-ticker.C:
+			if !session.statsEvents.Load() {
+				continue
+			}
+			wailsRuntime.EventsEmit(a.ctx, "logcat-stats", session.stats.snapshot(session.ID))
+		}
+	}
+}
+
+// SetLogcatStatsEvents turns the logcat-stats event, sent every few seconds, on or off for a
+// session. It is off by default; GetLogcatStats works either way.
+func (a *App) SetLogcatStatsEvents(sessionId string, enabled bool) error {
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	session.statsEvents.Store(enabled)
+	return nil
+}
+
+// GetLogcatStats returns per-tag and per-priority line counts for a session
+func (a *App) GetLogcatStats(sessionId string) (*LogcatStats, error) {
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	stats := session.stats.snapshot(sessionId)
+	return &stats, nil
+}
+
+// ResetLogcatStats clears the counters of a session
+func (a *App) ResetLogcatStats(sessionId string) error {
+	a.logcatMu.Lock()
+	session, ok := a.logcatSessions[sessionId]
+	a.logcatMu.Unlock()
+	if !ok {
+		return fmt.Errorf("logcat session not found: %s", sessionId)
+	}
+
+	session.stats.reset()
+	return nil
+}
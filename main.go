@@ -246,10 +246,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 		// 1. Mirror
 		mMirrorTop := systray.AddMenuItem("  Screen Mirror", "")
 		mMirrorTop.Click(func() {
-			go func() {
-				config := ScrcpyConfig{BitRate: 8, MaxFps: 60, StayAwake: true, VideoCodec: "h264", AudioCodec: "opus"}
-				app.StartScrcpy(d.ID, config)
-			}()
+			go startMirrorFromTray(app, d.ID)
 		})
 
 		// 2. Screenshot
@@ -258,7 +255,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 			go func() {
 				savePath, err := app.SelectScreenshotPath(d.Model)
 				if err == nil && savePath != "" {
-					_, _ = app.TakeScreenshot(d.ID, savePath)
+					_, _ = app.TakeScreenshot(d.ID, savePath, false)
 				}
 			}()
 		})
@@ -319,7 +316,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 				mRunWf := mWorkflowTop.AddSubMenuItem(wf.Name, "")
 				mRunWf.Click(func() {
 					go func() {
-						app.RunWorkflow(d, wf)
+						app.RunWorkflow(d, wf, nil)
 					}()
 				})
 			}
@@ -375,7 +372,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 					return // Cancelled by user
 				}
 
-				finalPath, err := app.TakeScreenshot(d.ID, savePath)
+				finalPath, err := app.TakeScreenshot(d.ID, savePath, false)
 				if err != nil {
 					return
 				}
@@ -386,17 +383,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 		// Submenus for connected devices
 		mMirror := devItem.AddSubMenuItem("Screen Mirror", "")
 		mMirror.Click(func() {
-			go func() {
-				// Default config for tray launch
-				config := ScrcpyConfig{
-					BitRate:    8,
-					MaxFps:     60,
-					StayAwake:  true,
-					VideoCodec: "h264",
-					AudioCodec: "opus",
-				}
-				app.StartScrcpy(d.ID, config)
-			}()
+			go startMirrorFromTray(app, d.ID)
 		})
 
 		// Recording
@@ -477,7 +464,7 @@ func updateTrayMenu(ctx context.Context, app *App) {
 				mRun := mWf.AddSubMenuItem(wf.Name, "")
 				mRun.Click(func() {
 					go func() {
-						app.RunWorkflow(d, wf)
+						app.RunWorkflow(d, wf, nil)
 					}()
 				})
 			}
@@ -550,3 +537,15 @@ func updateTrayMenu(ctx context.Context, app *App) {
 		wailsRuntime.Quit(ctx)
 	})
 }
+
+// startMirrorFromTray starts mirroring with the device's default config. The tray has no way to
+// show the error, so it is logged and reported to the window the way a crashing scrcpy is.
+func startMirrorFromTray(app *App, deviceId string) {
+	if _, err := app.StartScrcpy(deviceId, app.GetDefaultScrcpyConfig(deviceId)); err != nil {
+		app.Log("Failed to start mirroring %s from the tray: %v", deviceId, err)
+		wailsRuntime.EventsEmit(app.ctx, "scrcpy-failed", map[string]interface{}{
+			"deviceId": deviceId,
+			"error":    err.Error(),
+		})
+	}
+}
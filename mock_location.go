@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrMockLocationNotAllowed is returned when no app on the device may provide mock locations
+var ErrMockLocationNotAllowed = errors.New("mock locations are not allowed")
+
+// appiumSettingsPackage is the Appium Settings helper, which runs a mock location provider
+// driven by intents; it's used where the location service has no test provider commands
+const appiumSettingsPackage = "io.appium.settings"
+
+// packageNameRegex matches an Android package name, which goes into a shell command
+var packageNameRegex = regexp.MustCompile(`^[A-Za-z]\w*(\.[A-Za-z]\w*)+$`)
+
+// locationRouteTick is how often a route's position is updated
+const locationRouteTick = time.Second
+
+// locationRoute is one run of PlayLocationRoute. The pointer identifies the run, so a finished
+// run doesn't remove the entry of a newer one started after StopLocationRoute.
+type locationRoute struct {
+	cancel context.CancelFunc
+}
+
+var (
+	locationRoutes  = make(map[string]*locationRoute)
+	locationRouteMu sync.Mutex
+)
+
+// mockLocationBackend sets the position by whichever mechanism the device supports
+type mockLocationBackend struct {
+	method string
+	set    func(p GeoPoint, accuracy float64) error
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 7, 64)
+}
+
+// prepareMockLocation picks and sets up the way to mock the device's location: the emulator
+// console, Android 12's location test providers, or the Appium Settings helper app
+func (a *App) prepareMockLocation(deviceId string) (*mockLocationBackend, error) {
+	if strings.HasPrefix(deviceId, "emulator-") {
+		return &mockLocationBackend{method: "emulator geo fix", set: func(p GeoPoint, _ float64) error {
+			// geo fix takes longitude first
+			out, err := a.newAdbCommand(nil, "-s", deviceId, "emu", "geo", "fix", formatCoord(p.Lng), formatCoord(p.Lat), formatCoord(p.Alt)).CombinedOutput()
+			if err != nil || strings.Contains(string(out), "KO") {
+				return fmt.Errorf("geo fix failed: %v, %s", err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		}}, nil
+	}
+
+	if a.getSDKInt(deviceId) >= 31 {
+		setup := "appops set com.android.shell android:mock_location allow; " +
+			"cmd location providers add-test-provider gps 2>/dev/null; " +
+			"cmd location providers set-test-provider-enabled gps true"
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", setup).CombinedOutput()
+		if err == nil && !strings.Contains(string(out), "Exception") {
+			return &mockLocationBackend{method: "cmd location", set: func(p GeoPoint, accuracy float64) error {
+				cmd := fmt.Sprintf("cmd location providers set-test-provider-location gps --location %s,%s --accuracy %s",
+					formatCoord(p.Lat), formatCoord(p.Lng), strconv.FormatFloat(accuracy, 'f', 1, 64))
+				out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", cmd).CombinedOutput()
+				if err != nil || strings.Contains(string(out), "Exception") {
+					return fmt.Errorf("failed to set location: %v, %s", err, strings.TrimSpace(string(out)))
+				}
+				return nil
+			}}, nil
+		}
+		a.Log("Location test provider unavailable on %s: %s", deviceId, strings.TrimSpace(string(out)))
+	}
+
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "pm list packages "+appiumSettingsPackage).Output()
+	if !strings.Contains(string(out), "package:"+appiumSettingsPackage) {
+		return nil, fmt.Errorf("%w: install the Appium Settings app (%s), or use an Android 12+ device or an emulator", ErrMockLocationNotAllowed, appiumSettingsPackage)
+	}
+	if err := a.SetMockLocationApp(deviceId, appiumSettingsPackage); err != nil {
+		return nil, err
+	}
+	return &mockLocationBackend{method: "appium settings", set: func(p GeoPoint, accuracy float64) error {
+		cmd := fmt.Sprintf("am start-foreground-service --user 0 -n %s/.LocationService --es latitude %s --es longitude %s --es altitude %s --es accuracy %s",
+			appiumSettingsPackage, formatCoord(p.Lat), formatCoord(p.Lng), formatCoord(p.Alt), strconv.FormatFloat(accuracy, 'f', 1, 64))
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", cmd).CombinedOutput()
+		if err != nil || strings.Contains(string(out), "Error") {
+			return fmt.Errorf("failed to set location: %v, %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}}, nil
+}
+
+// SetMockLocationApp makes pkg the "mock location app" from developer options, which Android
+// requires before an app's mock positions are accepted
+func (a *App) SetMockLocationApp(deviceId, pkg string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return fmt.Errorf("invalid package name %q", pkg)
+	}
+	script := "appops set " + pkg + " android:mock_location allow"
+	if a.getSDKInt(deviceId) < 23 {
+		// Before Android 6 it's a single global switch
+		script = "settings put secure mock_location 1"
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(string(out), "Error") {
+		return fmt.Errorf("%w: %s could not be made the mock location app: %v, %s", ErrMockLocationNotAllowed, pkg, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func validGeoPoint(p GeoPoint) error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("invalid coordinates %g,%g", p.Lat, p.Lng)
+	}
+	return nil
+}
+
+// SetMockLocation moves the device to a fixed position and returns the mechanism used.
+// accuracy is in meters; 0 means 5.
+func (a *App) SetMockLocation(deviceId string, lat, lng, alt, accuracy float64) (string, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	p := GeoPoint{Lat: lat, Lng: lng, Alt: alt}
+	if err := validGeoPoint(p); err != nil {
+		return "", err
+	}
+	if accuracy <= 0 {
+		accuracy = 5
+	}
+	backend, err := a.prepareMockLocation(deviceId)
+	if err != nil {
+		return "", err
+	}
+	if err := backend.set(p, accuracy); err != nil {
+		return "", err
+	}
+	return backend.method, nil
+}
+
+// ClearMockLocation stops mocking and lets the real providers report again
+func (a *App) ClearMockLocation(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	a.StopLocationRoute(deviceId)
+	script := "cmd location providers remove-test-provider gps 2>/dev/null; am stopservice " + appiumSettingsPackage + "/.LocationService >/dev/null 2>&1; true"
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to clear mock location: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// haversineMeters is the great-circle distance between two points
+func haversineMeters(p, q GeoPoint) float64 {
+	const earthRadius = 6371000.0
+	lat1, lat2 := p.Lat*math.Pi/180, q.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (q.Lng - p.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// pointAlongRoute returns the position distance meters along points, and the index of the
+// segment it falls in. Segments are short enough that linear interpolation is fine.
+func pointAlongRoute(points []GeoPoint, legs []float64, distance float64) (GeoPoint, int) {
+	for i, leg := range legs {
+		if distance <= leg || i == len(legs)-1 {
+			f := 1.0
+			if leg > 0 {
+				f = math.Min(1, distance/leg)
+			}
+			p, q := points[i], points[i+1]
+			return GeoPoint{
+				Lat: p.Lat + (q.Lat-p.Lat)*f,
+				Lng: p.Lng + (q.Lng-p.Lng)*f,
+				Alt: p.Alt + (q.Alt-p.Alt)*f,
+			}, i
+		}
+		distance -= leg
+	}
+	return points[len(points)-1], len(legs) - 1
+}
+
+// PlayLocationRoute moves the mock location along points at speedKmh, updating it every
+// second until the end of the route or StopLocationRoute. Each device runs its own route.
+func (a *App) PlayLocationRoute(deviceId string, points []GeoPoint, speedKmh float64) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if len(points) < 2 {
+		return fmt.Errorf("a route needs at least two points")
+	}
+	for _, p := range points {
+		if err := validGeoPoint(p); err != nil {
+			return err
+		}
+	}
+	if speedKmh <= 0 || speedKmh > 1000 {
+		return fmt.Errorf("speed must be between 0 and 1000 km/h")
+	}
+
+	legs := make([]float64, len(points)-1)
+	total := 0.0
+	for i := range legs {
+		legs[i] = haversineMeters(points[i], points[i+1])
+		total += legs[i]
+	}
+
+	locationRouteMu.Lock()
+	if _, exists := locationRoutes[deviceId]; exists {
+		locationRouteMu.Unlock()
+		return fmt.Errorf("a route is already playing on this device")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &locationRoute{cancel: cancel}
+	locationRoutes[deviceId] = run
+	locationRouteMu.Unlock()
+
+	release := func() {
+		cancel()
+		locationRouteMu.Lock()
+		if locationRoutes[deviceId] == run {
+			delete(locationRoutes, deviceId)
+		}
+		locationRouteMu.Unlock()
+	}
+
+	backend, err := a.prepareMockLocation(deviceId)
+	if err != nil {
+		release()
+		return err
+	}
+
+	go func() {
+		reason := "completed"
+		defer func() {
+			release()
+			wailsRuntime.EventsEmit(a.ctx, "location-route-completed", map[string]interface{}{
+				"deviceId": deviceId,
+				"reason":   reason,
+			})
+		}()
+
+		metersPerSecond := speedKmh / 3.6
+		start := time.Now()
+		ticker := time.NewTicker(locationRouteTick)
+		defer ticker.Stop()
+		for {
+			travelled := math.Min(total, time.Since(start).Seconds()*metersPerSecond)
+			pos, segment := pointAlongRoute(points, legs, travelled)
+			if err := backend.set(pos, 5); err != nil {
+				a.Log("Location route on %s: %v", deviceId, err)
+				reason = "error"
+				return
+			}
+			wailsRuntime.EventsEmit(a.ctx, "location-route-progress", map[string]interface{}{
+				"deviceId":  deviceId,
+				"lat":       pos.Lat,
+				"lng":       pos.Lng,
+				"segment":   segment,
+				"distanceM": travelled,
+				"totalM":    total,
+			})
+			if travelled >= total {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				reason = "stopped"
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	wailsRuntime.EventsEmit(a.ctx, "location-route-started", map[string]interface{}{
+		"deviceId": deviceId,
+		"points":   len(points),
+		"totalM":   total,
+		"method":   backend.method,
+	})
+	return nil
+}
+
+// StopLocationRoute stops a playing route; the device stays at its last position
+func (a *App) StopLocationRoute(deviceId string) {
+	locationRouteMu.Lock()
+	defer locationRouteMu.Unlock()
+	if run, ok := locationRoutes[deviceId]; ok {
+		run.cancel()
+		delete(locationRoutes, deviceId)
+	}
+}
+
+// stopAllLocationRoutes stops every playing route on shutdown
+func (a *App) stopAllLocationRoutes() {
+	locationRouteMu.Lock()
+	defer locationRouteMu.Unlock()
+	for id, run := range locationRoutes {
+		run.cancel()
+		delete(locationRoutes, id)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// monkeyPctSwitches maps MonkeyOptions.Percentages keys to monkey's --pct switches
+var monkeyPctSwitches = map[string]string{
+	"touch":     "--pct-touch",
+	"motion":    "--pct-motion",
+	"trackball": "--pct-trackball",
+	"nav":       "--pct-nav",
+	"majornav":  "--pct-majornav",
+	"syskeys":   "--pct-syskeys",
+	"appswitch": "--pct-appswitch",
+	"anyevent":  "--pct-anyevent",
+}
+
+var (
+	monkeyEventRegex    = regexp.MustCompile(`// Sending event #(\d+)`)
+	monkeyInjectedRegex = regexp.MustCompile(`Events injected:\s*(\d+)`)
+	// monkeyCrashRegex matches the header of a crash or ANR block:
+	// "// CRASH: com.example (pid 1234)" or "// NOT RESPONDING: com.example (pid 1234)"
+	monkeyCrashRegex = regexp.MustCompile(`^// (CRASH|NOT RESPONDING): (\S+) \(pid (\d+)\)`)
+)
+
+// maxMonkeyCrashLines caps how much of each crash block is kept
+const maxMonkeyCrashLines = 60
+
+// monkeyRun is a running or finished monkey test
+type monkeyRun struct {
+	mu     sync.Mutex
+	result MonkeyResult
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	monkeyRuns   = make(map[string]*monkeyRun)
+	monkeyRunsMu sync.Mutex
+)
+
+// buildMonkeyCommand turns MonkeyOptions into the device-side monkey command line
+func buildMonkeyCommand(opts MonkeyOptions) (string, error) {
+	args := []string{"monkey"}
+	for _, pkg := range opts.Packages {
+		if !packageNameRegex.MatchString(pkg) {
+			return "", fmt.Errorf("invalid package name %q", pkg)
+		}
+		args = append(args, "-p", pkg)
+	}
+	if len(opts.Packages) == 0 {
+		return "", fmt.Errorf("at least one package is required")
+	}
+	args = append(args, "-s", strconv.FormatInt(opts.Seed, 10))
+	if opts.ThrottleMs > 0 {
+		args = append(args, "--throttle", strconv.Itoa(opts.ThrottleMs))
+	}
+
+	keys := make([]string, 0, len(opts.Percentages))
+	total := 0
+	for k, v := range opts.Percentages {
+		if _, ok := monkeyPctSwitches[k]; !ok {
+			return "", fmt.Errorf("unknown event type %q", k)
+		}
+		if v < 0 || v > 100 {
+			return "", fmt.Errorf("%s percentage must be between 0 and 100", k)
+		}
+		total += v
+		keys = append(keys, k)
+	}
+	if total > 100 {
+		return "", fmt.Errorf("event percentages add up to %d%%", total)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, monkeyPctSwitches[k], strconv.Itoa(opts.Percentages[k]))
+	}
+
+	if opts.IgnoreCrashes {
+		args = append(args, "--ignore-crashes")
+	}
+	if opts.IgnoreTimeouts {
+		args = append(args, "--ignore-timeouts")
+	}
+	args = append(args, "-v", "-v", strconv.Itoa(opts.Count))
+	return strings.Join(args, " "), nil
+}
+
+// monkeyOutputParser follows monkey's verbose output, counting events and collecting crash and
+// ANR blocks. A block runs from its header until monkey goes back to reporting events.
+type monkeyOutputParser struct {
+	sent     int
+	injected int
+	current  *MonkeyCrash
+	onCrash  func(MonkeyCrash)
+}
+
+func (p *monkeyOutputParser) finishCrash() {
+	if p.current == nil {
+		return
+	}
+	p.current.Details = strings.TrimSpace(p.current.Details)
+	p.onCrash(*p.current)
+	p.current = nil
+}
+
+func (p *monkeyOutputParser) feed(line string) {
+	if m := monkeyCrashRegex.FindStringSubmatch(line); m != nil {
+		p.finishCrash()
+		kind := "crash"
+		if m[1] == "NOT RESPONDING" {
+			kind = "anr"
+		}
+		pid, _ := strconv.Atoi(m[3])
+		p.current = &MonkeyCrash{Type: kind, Package: m[2], PID: pid, AtEvent: p.sent}
+		return
+	}
+	if p.current != nil {
+		// Events, and monkey's own status lines, end the block
+		if strings.HasPrefix(line, ":") || strings.HasPrefix(line, "** ") || monkeyEventRegex.MatchString(line) {
+			p.finishCrash()
+		} else {
+			text := strings.TrimPrefix(strings.TrimPrefix(line, "//"), " ")
+			if v, ok := strings.CutPrefix(text, "Short Msg: "); ok && p.current.ShortMsg == "" {
+				p.current.ShortMsg = v
+			} else if v, ok := strings.CutPrefix(text, "Reason: "); ok && p.current.ShortMsg == "" {
+				p.current.ShortMsg = v
+			}
+			if strings.Count(p.current.Details, "\n") < maxMonkeyCrashLines {
+				p.current.Details += text + "\n"
+			}
+			return
+		}
+	}
+	if m := monkeyEventRegex.FindStringSubmatch(line); m != nil {
+		p.sent, _ = strconv.Atoi(m[1])
+	} else if strings.HasPrefix(line, ":Sending ") {
+		p.sent++
+	} else if m := monkeyInjectedRegex.FindStringSubmatch(line); m != nil {
+		p.injected, _ = strconv.Atoi(m[1])
+	}
+}
+
+// StartMonkeyTest runs monkey against the given packages in the background. monkey-progress
+// reports the events sent so far, monkey-crash each crash or ANR as it happens, and
+// monkey-finished the final MonkeyResult. A zero seed picks a random one, which the result
+// records so the run can be repeated.
+func (a *App) StartMonkeyTest(deviceId string, opts MonkeyOptions) (*MonkeyResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if opts.Count <= 0 {
+		opts.Count = 1000
+	}
+	if opts.Seed == 0 {
+		opts.Seed = rand.Int63n(1<<31-1) + 1
+	}
+	command, err := buildMonkeyCommand(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	monkeyRunsMu.Lock()
+	if r, ok := monkeyRuns[deviceId]; ok {
+		select {
+		case <-r.done:
+		default:
+			monkeyRunsMu.Unlock()
+			return nil, fmt.Errorf("a monkey test is already running on %s", deviceId)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &monkeyRun{
+		result: MonkeyResult{
+			DeviceID:        deviceId,
+			Packages:        opts.Packages,
+			Seed:            opts.Seed,
+			Command:         command,
+			EventsRequested: opts.Count,
+			State:           "running",
+			StartedAt:       time.Now().Unix(),
+			Crashes:         []MonkeyCrash{},
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	monkeyRuns[deviceId] = run
+	monkeyRunsMu.Unlock()
+
+	go func() {
+		defer close(run.done)
+		defer cancel()
+		start := time.Now()
+		lastEmit := time.Time{}
+		aborted := false
+
+		parser := &monkeyOutputParser{onCrash: func(c MonkeyCrash) {
+			run.mu.Lock()
+			run.result.Crashes = append(run.result.Crashes, c)
+			run.mu.Unlock()
+			wailsRuntime.EventsEmit(a.ctx, "monkey-crash", map[string]interface{}{
+				"deviceId": deviceId,
+				"crash":    c,
+			})
+		}}
+		_, err := a.runStreamedCommand(ctx, deviceId, []string{"shell", command}, func(stream, line string) {
+			parser.feed(line)
+			if strings.HasPrefix(line, "** Monkey aborted") {
+				aborted = true
+			}
+			if time.Since(lastEmit) >= 500*time.Millisecond {
+				lastEmit = time.Now()
+				wailsRuntime.EventsEmit(a.ctx, "monkey-progress", map[string]interface{}{
+					"deviceId":  deviceId,
+					"sent":      parser.sent,
+					"requested": opts.Count,
+					"elapsedMs": time.Since(start).Milliseconds(),
+				})
+			}
+		})
+		parser.finishCrash()
+
+		run.mu.Lock()
+		r := &run.result
+		r.EventsInjected = parser.injected
+		if r.EventsInjected == 0 {
+			r.EventsInjected = parser.sent
+		}
+		r.DurationMs = time.Since(start).Milliseconds()
+		switch {
+		case r.State == "stopped":
+		case aborted:
+			r.State = "aborted"
+		case err != nil:
+			r.State = "error"
+			r.Error = err.Error()
+		default:
+			r.State = "completed"
+		}
+		result := *r
+		run.mu.Unlock()
+
+		a.Log("Monkey on %s %s after %d events (seed %d, %d crashes)", deviceId, result.State, result.EventsInjected, result.Seed, len(result.Crashes))
+		wailsRuntime.EventsEmit(a.ctx, "monkey-finished", result)
+	}()
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	result := run.result
+	return &result, nil
+}
+
+// StopMonkeyTest kills the device-side monkey process and waits for the run to wrap up
+func (a *App) StopMonkeyTest(deviceId string) (*MonkeyResult, error) {
+	monkeyRunsMu.Lock()
+	run, ok := monkeyRuns[deviceId]
+	monkeyRunsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no monkey test on %s", deviceId)
+	}
+
+	run.mu.Lock()
+	if run.result.State == "running" {
+		run.result.State = "stopped"
+	}
+	run.mu.Unlock()
+
+	// Killing adb alone leaves monkey running on the device
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "kill $(pidof com.android.commands.monkey) 2>/dev/null; pkill -f com.android.commands.monkey 2>/dev/null; true").CombinedOutput()
+	if err != nil {
+		a.Log("Failed to kill monkey on %s: %v, %s", deviceId, err, strings.TrimSpace(string(out)))
+	}
+	select {
+	case <-run.done:
+	case <-time.After(5 * time.Second):
+		run.cancel()
+		<-run.done
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	result := run.result
+	return &result, nil
+}
+
+// GetMonkeyResult returns the running or most recent monkey test on a device
+func (a *App) GetMonkeyResult(deviceId string) (*MonkeyResult, error) {
+	monkeyRunsMu.Lock()
+	run, ok := monkeyRuns[deviceId]
+	monkeyRunsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no monkey test on %s", deviceId)
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	result := run.result
+	return &result, nil
+}
+
+// stopAllMonkeyTests kills running monkey tests on shutdown
+func (a *App) stopAllMonkeyTests() {
+	monkeyRunsMu.Lock()
+	var running []string
+	for id, run := range monkeyRuns {
+		select {
+		case <-run.done:
+		default:
+			running = append(running, id)
+		}
+	}
+	monkeyRunsMu.Unlock()
+	for _, id := range running {
+		a.StopMonkeyTest(id)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Multi-device Playback State
+var (
+	// touchPlaybackBatches maps a batch ID to the devices it started, so StopTouchPlayback can
+	// stop them together
+	touchPlaybackBatches = make(map[string][]string)
+)
+
+// multiPlaybackStep is one device's position in a batch, as sent in multi-playback-progress
+type multiPlaybackStep struct {
+	Current    int    `json:"current"`
+	Total      int    `json:"total"`
+	Iteration  int    `json:"iteration"`
+	Iterations int    `json:"iterations"`
+	Status     string `json:"status"` // playing, completed, stopped, timeout or error
+}
+
+// PlayTouchScriptOnDevices plays a saved script on several devices concurrently and returns the
+// batch ID. Each device scales the script to its own screen and gets its own cancel function, so
+// StopTouchPlayback works with either a device ID or the batch ID. A multi-playback-completed
+// event carries the per-device report once every device has finished.
+func (a *App) PlayTouchScriptOnDevices(deviceIds []string, scriptName string, opts PlaybackOptions) (string, error) {
+	if len(deviceIds) == 0 {
+		return "", fmt.Errorf("no device specified")
+	}
+	script, err := a.loadTouchScript(scriptName)
+	if err != nil {
+		return "", err
+	}
+	if touchPlaybackMode(*script) == "simple" && len(script.Events) == 0 {
+		return "", fmt.Errorf("script has no events")
+	}
+	if opts.Speed < 0 || opts.Speed > maxPlaybackSpeed {
+		return "", fmt.Errorf("speed must be between 0 and %gx", maxPlaybackSpeed)
+	}
+	if opts.Speed > 0 && opts.Speed != 1 {
+		*script = scaleScriptTiming(*script, opts.Speed)
+	}
+	restoreOrientations := make(map[string]func(), len(deviceIds))
+	restoreAll := func() {
+		for _, restore := range restoreOrientations {
+			restore()
+		}
+	}
+	for _, deviceId := range deviceIds {
+		if opts.ForceOrientation {
+			if a.IsPlayingTouch(deviceId) {
+				restoreAll()
+				return "", fmt.Errorf("playback already in progress on %s", deviceId)
+			}
+			restore, err := a.forceScriptOrientation(deviceId, script)
+			if err != nil {
+				restoreAll()
+				return "", fmt.Errorf("%s: %w", deviceId, err)
+			}
+			restoreOrientations[deviceId] = restore
+		}
+		if err := a.newTouchTransform(deviceId, script).checkOrientation(script); err != nil {
+			restoreAll()
+			return "", fmt.Errorf("%s: %w", deviceId, err)
+		}
+	}
+
+	iterations := opts.Repeat
+	if iterations == 0 {
+		iterations = 1
+	}
+	batchId := fmt.Sprintf("batch_%d", time.Now().UnixNano())
+
+	touchPlaybackMu.Lock()
+	for _, deviceId := range deviceIds {
+		if _, exists := touchPlaybackCancel[deviceId]; exists {
+			touchPlaybackMu.Unlock()
+			restoreAll()
+			return "", fmt.Errorf("playback already in progress on %s", deviceId)
+		}
+	}
+	cancels := make(map[string]context.CancelFunc, len(deviceIds))
+	contexts := make(map[string]context.Context, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		if opts.MaxDuration > 0 {
+			contexts[deviceId], cancels[deviceId] = context.WithTimeout(context.Background(), time.Duration(opts.MaxDuration)*time.Second)
+		} else {
+			contexts[deviceId], cancels[deviceId] = context.WithCancel(context.Background())
+		}
+		touchPlaybackCancel[deviceId] = cancels[deviceId]
+	}
+	touchPlaybackBatches[batchId] = append([]string{}, deviceIds...)
+	touchPlaybackMu.Unlock()
+
+	var stepsMu sync.Mutex
+	steps := make(map[string]*multiPlaybackStep, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		steps[deviceId] = &multiPlaybackStep{Total: len(script.Events), Iterations: iterations, Status: "playing"}
+	}
+	emitProgress := func() {
+		stepsMu.Lock()
+		snapshot := make(map[string]multiPlaybackStep, len(steps))
+		for id, step := range steps {
+			snapshot[id] = *step
+		}
+		stepsMu.Unlock()
+		wailsRuntime.EventsEmit(a.ctx, "multi-playback-progress", map[string]interface{}{
+			"batchId": batchId,
+			"devices": snapshot,
+		})
+	}
+
+	start := time.Now()
+	results := make([]DevicePlaybackResult, len(deviceIds))
+	var wg sync.WaitGroup
+	for i, deviceId := range deviceIds {
+		wg.Add(1)
+		go func(i int, deviceId string) {
+			defer wg.Done()
+			deviceStart := time.Now()
+			ctx, cancel := contexts[deviceId], cancels[deviceId]
+			if restore, ok := restoreOrientations[deviceId]; ok {
+				defer restore()
+			}
+			if opts.DisableAnimations {
+				defer a.withAnimationsDisabled(deviceId)()
+			}
+
+			completed, reason, err := a.runPlaybackIterations(ctx, deviceId, *script, iterations, opts.IntervalMs, func(iteration, current, total int, eventType string) {
+				stepsMu.Lock()
+				steps[deviceId].Current, steps[deviceId].Total, steps[deviceId].Iteration = current, total, iteration
+				stepsMu.Unlock()
+				emitProgress()
+			})
+
+			cancel()
+			a.clearTouchPlaybackPause(deviceId)
+			touchPlaybackMu.Lock()
+			delete(touchPlaybackCancel, deviceId)
+			touchPlaybackMu.Unlock()
+
+			result := DevicePlaybackResult{
+				DeviceID:   deviceId,
+				Success:    reason == "completed",
+				Reason:     reason,
+				Iterations: completed,
+				DurationMs: time.Since(deviceStart).Milliseconds(),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results[i] = result
+
+			stepsMu.Lock()
+			steps[deviceId].Status = reason
+			stepsMu.Unlock()
+			emitProgress()
+
+			wailsRuntime.EventsEmit(a.ctx, "touch-playback-completed", map[string]interface{}{
+				"deviceId":   deviceId,
+				"iterations": completed,
+				"reason":     reason,
+				"batchId":    batchId,
+			})
+		}(i, deviceId)
+	}
+
+	go func() {
+		wg.Wait()
+		touchPlaybackMu.Lock()
+		delete(touchPlaybackBatches, batchId)
+		touchPlaybackMu.Unlock()
+
+		report := MultiPlaybackReport{
+			BatchID:    batchId,
+			ScriptName: script.Name,
+			Results:    results,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		a.Log("Multi-device playback %s of %q finished on %d devices", batchId, script.Name, len(deviceIds))
+		wailsRuntime.EventsEmit(a.ctx, "multi-playback-completed", report)
+	}()
+
+	for _, deviceId := range deviceIds {
+		wailsRuntime.EventsEmit(a.ctx, "touch-playback-started", map[string]interface{}{
+			"deviceId":   deviceId,
+			"total":      len(script.Events),
+			"mode":       touchPlaybackMode(*script),
+			"iterations": iterations,
+			"batchId":    batchId,
+		})
+	}
+	return batchId, nil
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrNotificationDismissUnsupported is returned on Android versions with no shell command that
+// removes another app's notification
+var ErrNotificationDismissUnsupported = errors.New("dismissing notifications needs Android 11 or later")
+
+// flagOngoingEvent is Notification.FLAG_ONGOING_EVENT
+const flagOngoingEvent = 0x2
+
+var (
+	// notificationRecordRegex starts an entry: "NotificationRecord(0x0c5f2b7a: pkg=com.x user=UserHandle{0}
+	// id=1 tag=null importance=2 key=0|com.x|1|null|10112: Notification(channel=BAT ..."
+	notificationRecordRegex = regexp.MustCompile(`NotificationRecord\(0x[0-9a-f]+: pkg=(\S+) user=\S+ id=(-?\d+) tag=(\S+)(.*)`)
+	notificationKeyRegex    = regexp.MustCompile(`\bkey=([^\s:]+(?::[^\s:]+)*?)(?::\s|\s|$)`)
+	notificationFieldRegex  = regexp.MustCompile(`\b(channel|importance|flags)=(\S+?)[\s)]`)
+	// notificationExtraRegex matches "android.title=String (Battery saver)"; redacted builds print
+	// "android.title=String [length=13]" instead
+	notificationExtraRegex = regexp.MustCompile(`^(android\.(?:title|text|bigText|subText))=\w+ (\((.*)\)|\[length=\d+\])$`)
+	notificationTimeRegex  = regexp.MustCompile(`\b(?:mCreationTimeMs|when)=(\d{10,})`)
+)
+
+// parseNotifications parses dumpsys notification --noredact. Fields the build redacts or
+// doesn't print are left empty rather than failing the entry.
+func parseNotifications(output string) []NotificationEntry {
+	entries := []NotificationEntry{}
+	seen := make(map[string]bool)
+	var current *NotificationEntry
+	finish := func() {
+		if current != nil && current.Key != "" && !seen[current.Key] {
+			seen[current.Key] = true
+			entries = append(entries, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Snoozed notifications") {
+			// Snoozed ones aren't showing, and everything after is history
+			break
+		}
+		if m := notificationRecordRegex.FindStringSubmatch(trimmed); m != nil {
+			finish()
+			id, _ := strconv.Atoi(m[2])
+			current = &NotificationEntry{Package: m[1], ID: id}
+			if m[3] != "null" {
+				current.Tag = m[3]
+			}
+			if km := notificationKeyRegex.FindStringSubmatch(m[4]); km != nil {
+				current.Key = km[1]
+			}
+			for _, fm := range notificationFieldRegex.FindAllStringSubmatch(m[4]+" ", -1) {
+				applyNotificationField(current, fm[1], fm[2])
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ":") && !strings.Contains(trimmed, "=") && len(line)-len(strings.TrimLeft(line, " ")) <= 2 {
+			// A new dumpsys section
+			finish()
+			continue
+		}
+		if m := notificationExtraRegex.FindStringSubmatch(trimmed); m != nil {
+			if m[3] == "" && strings.HasPrefix(m[2], "[") {
+				current.Redacted = true
+				continue
+			}
+			switch m[1] {
+			case "android.title":
+				current.Title = m[3]
+			case "android.text":
+				current.Text = m[3]
+			case "android.bigText":
+				if current.Text == "" {
+					current.Text = m[3]
+				}
+			case "android.subText":
+				current.SubText = m[3]
+			}
+			continue
+		}
+		if m := notificationTimeRegex.FindStringSubmatch(trimmed); m != nil && current.When == 0 {
+			current.When, _ = strconv.ParseInt(m[1], 10, 64)
+			continue
+		}
+		if v, ok := strings.CutPrefix(trimmed, "key="); ok && current.Key == "" {
+			current.Key = v
+		} else if v, ok := strings.CutPrefix(trimmed, "flags="); ok && !strings.Contains(v, " ") {
+			applyNotificationField(current, "flags", v)
+		}
+	}
+	finish()
+	return entries
+}
+
+func applyNotificationField(e *NotificationEntry, name, value string) {
+	switch name {
+	case "channel":
+		if value != "null" {
+			e.Channel = value
+		}
+	case "importance":
+		e.Importance, _ = strconv.Atoi(value)
+	case "flags":
+		if flags, err := strconv.ParseInt(strings.TrimPrefix(value, "0x"), 16, 64); err == nil {
+			e.Ongoing = flags&flagOngoingEvent != 0
+		}
+	}
+}
+
+// ListNotifications returns the notifications currently posted on the device
+func (a *App) ListNotifications(deviceId string) ([]NotificationEntry, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "dumpsys notification --noredact").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications: %w, %s", err, strings.TrimSpace(lastLines(string(out), 3)))
+	}
+	return parseNotifications(string(out)), nil
+}
+
+// DismissNotification removes a notification by key. The shell can't cancel another app's
+// notification, so it is snoozed for a year, which takes it off the shade the same way.
+func (a *App) DismissNotification(deviceId, key string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if key == "" {
+		return fmt.Errorf("no notification key specified")
+	}
+	if a.getSDKInt(deviceId) < 30 {
+		return ErrNotificationDismissUnsupported
+	}
+	const year = 365 * 24 * time.Hour
+	cmd := fmt.Sprintf("cmd notification snooze --for %d %s", year.Milliseconds(), shellQuote(key))
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", cmd).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(strings.ToLower(string(out)), "error") {
+		return fmt.Errorf("failed to dismiss notification: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ExpandNotificationShade pulls down the notification shade
+func (a *App) ExpandNotificationShade(deviceId string) error {
+	return a.statusBarCommand(deviceId, "expand-notifications", "1")
+}
+
+// CollapseShade closes the notification shade and quick settings
+func (a *App) CollapseShade(deviceId string) error {
+	return a.statusBarCommand(deviceId, "collapse", "2")
+}
+
+// statusBarCommand runs cmd statusbar, or the matching service call on builds without it
+func (a *App) statusBarCommand(deviceId, command, serviceCode string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	script := "cmd statusbar " + command
+	if a.getSDKInt(deviceId) < 26 {
+		script = "service call statusbar " + serviceCode
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") || strings.Contains(string(out), "Unknown command") {
+		return fmt.Errorf("failed to %s the shade: %v, %s", command, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Notification watcher state
+var (
+	notificationWatchers   = make(map[string]context.CancelFunc)
+	notificationWatchersMu sync.Mutex
+)
+
+// watchNotifications polls every two seconds and calls onPosted for each key that wasn't there
+// on the first poll or any earlier one. It returns when ctx ends or onPosted returns false.
+func (a *App) watchNotifications(ctx context.Context, deviceId string, onPosted func(NotificationEntry) bool) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	var known map[string]bool
+	for {
+		if entries, err := a.ListNotifications(deviceId); err == nil {
+			first := known == nil
+			if first {
+				known = make(map[string]bool, len(entries))
+			}
+			for _, e := range entries {
+				if known[e.Key] {
+					continue
+				}
+				known[e.Key] = true
+				if !first && !onPosted(e) {
+					return
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartNotificationWatcher emits notification-posted for each notification that appears
+func (a *App) StartNotificationWatcher(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	notificationWatchersMu.Lock()
+	defer notificationWatchersMu.Unlock()
+	if _, ok := notificationWatchers[deviceId]; ok {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	notificationWatchers[deviceId] = cancel
+	go a.watchNotifications(ctx, deviceId, func(e NotificationEntry) bool {
+		wailsRuntime.EventsEmit(a.ctx, "notification-posted", map[string]interface{}{
+			"deviceId":     deviceId,
+			"notification": e,
+		})
+		return true
+	})
+	return nil
+}
+
+// StopNotificationWatcher stops the watcher for a device
+func (a *App) StopNotificationWatcher(deviceId string) {
+	notificationWatchersMu.Lock()
+	defer notificationWatchersMu.Unlock()
+	if cancel, ok := notificationWatchers[deviceId]; ok {
+		cancel()
+		delete(notificationWatchers, deviceId)
+	}
+}
+
+// stopAllNotificationWatchers stops every watcher on shutdown
+func (a *App) stopAllNotificationWatchers() {
+	notificationWatchersMu.Lock()
+	defer notificationWatchersMu.Unlock()
+	for id, cancel := range notificationWatchers {
+		cancel()
+		delete(notificationWatchers, id)
+	}
+}
+
+// WaitForNotification waits up to timeoutMs for a new notification from pkg (any app when
+// empty) and returns it
+func (a *App) WaitForNotification(deviceId, pkg string, timeoutMs int) (*NotificationEntry, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if timeoutMs <= 0 {
+		timeoutMs = 10000
+	}
+	return a.waitForNotification(context.Background(), deviceId, pkg, time.Duration(timeoutMs)*time.Millisecond)
+}
+
+func (a *App) waitForNotification(ctx context.Context, deviceId, pkg string, timeout time.Duration) (*NotificationEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var found *NotificationEntry
+	a.watchNotifications(ctx, deviceId, func(e NotificationEntry) bool {
+		if pkg == "" || e.Package == pkg {
+			found = &e
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		who := "any app"
+		if pkg != "" {
+			who = pkg
+		}
+		return nil, fmt.Errorf("no notification from %s within %s", who, timeout)
+	}
+	return found, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRotationOverridden is returned when the display didn't take the requested rotation,
+// usually because the foreground app or an OEM launcher pins its own orientation
+var ErrRotationOverridden = errors.New("rotation was overridden on the device")
+
+// orientationNames maps SetOrientation's names to user_rotation values (quarter turns)
+var orientationNames = map[string]int{
+	"portrait":          0,
+	"landscape":         1,
+	"reverse_portrait":  2,
+	"reverse_landscape": 3,
+}
+
+// rotationNames is orientationNames inverted
+var rotationNames = []string{"portrait", "landscape", "reverse_portrait", "reverse_landscape"}
+
+// GetOrientation reads the current display rotation and whether auto-rotate is on
+func (a *App) GetOrientation(deviceId string) (*OrientationInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	rotation, err := a.getDisplayRotation(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings get system accelerometer_rotation; settings get system user_rotation").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rotation settings: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	info := &OrientationInfo{Rotation: rotation, Name: rotationNames[rotation]}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) > 0 {
+		info.AutoRotate = settingIsOn(strings.TrimSpace(lines[0]))
+	}
+	if len(lines) > 1 {
+		info.UserRotation, _ = strconv.Atoi(strings.TrimSpace(lines[1]))
+	}
+	return info, nil
+}
+
+// SetOrientation locks the display to portrait, landscape, reverse_portrait or
+// reverse_landscape by turning auto-rotate off and setting user_rotation
+func (a *App) SetOrientation(deviceId string, orientation string) (*OrientationInfo, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	rotation, ok := orientationNames[orientation]
+	if !ok {
+		return nil, fmt.Errorf("unknown orientation %q", orientation)
+	}
+	return a.lockRotation(deviceId, rotation)
+}
+
+// lockRotation sets the rotation and waits for the display to follow. Some launchers ignore
+// user_rotation, so on Android 12+ it retries through the window manager's lock.
+func (a *App) lockRotation(deviceId string, rotation int) (*OrientationInfo, error) {
+	script := fmt.Sprintf("settings put system accelerometer_rotation 0 && settings put system user_rotation %d", rotation)
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set rotation: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	if info, ok := a.waitForRotation(deviceId, rotation); ok {
+		return info, nil
+	}
+
+	if a.getSDKInt(deviceId) >= 31 {
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", fmt.Sprintf("wm user-rotation lock %d", rotation)).CombinedOutput()
+		if err != nil {
+			a.Log("wm user-rotation failed on %s: %v, %s", deviceId, err, strings.TrimSpace(string(out)))
+		} else if info, ok := a.waitForRotation(deviceId, rotation); ok {
+			return info, nil
+		}
+	}
+
+	current, _ := a.getDisplayRotation(deviceId)
+	return nil, fmt.Errorf("%w: asked for %s but the display is %s; the foreground app may fix its own orientation",
+		ErrRotationOverridden, rotationNames[rotation], rotationNames[current])
+}
+
+// waitForRotation polls for up to 3 seconds for the display to reach rotation
+func (a *App) waitForRotation(deviceId string, rotation int) (*OrientationInfo, bool) {
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		info, err := a.GetOrientation(deviceId)
+		if err == nil && info.Rotation == rotation {
+			return info, true
+		}
+		if time.Now().After(deadline) {
+			return info, false
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+}
+
+// UnlockOrientation turns auto-rotate back on
+func (a *App) UnlockOrientation(deviceId string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	script := "settings put system accelerometer_rotation 1"
+	if a.getSDKInt(deviceId) >= 31 {
+		script += "; wm user-rotation free >/dev/null 2>&1"
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to unlock rotation: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// forceScriptOrientation rotates the device to the orientation a script was recorded in and
+// returns the function that puts back the previous rotation settings. Scripts without a
+// recorded orientation leave the device as it is.
+func (a *App) forceScriptOrientation(deviceId string, script *TouchScript) (func(), error) {
+	if script.AbsoluteCoordinates || script.Orientation == nil {
+		return func() {}, nil
+	}
+	recorded := *script.Orientation % 4
+	before, err := a.GetOrientation(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if !before.AutoRotate && before.Rotation == recorded {
+		return func() {}, nil
+	}
+	if _, err := a.lockRotation(deviceId, recorded); err != nil {
+		return nil, err
+	}
+	return func() {
+		if before.AutoRotate {
+			if err := a.UnlockOrientation(deviceId); err != nil {
+				a.Log("Could not restore auto-rotate on %s: %v", deviceId, err)
+			}
+			return
+		}
+		if _, err := a.lockRotation(deviceId, before.Rotation); err != nil {
+			a.Log("Could not restore rotation on %s: %v", deviceId, err)
+		}
+	}, nil
+}
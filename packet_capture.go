@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrRootRequired is returned for features that need root (adb root or su) on a device without it
+var ErrRootRequired = errors.New("root access required")
+
+// packetCaptureProgressInterval is how often capture size updates are emitted
+const packetCaptureProgressInterval = 3 * time.Second
+
+// remoteTcpdumpPath is where a bundled tcpdump is pushed when the device has none
+const remoteTcpdumpPath = "/data/local/tmp/gaze-tcpdump"
+
+var tcpdumpInterfaceRegex = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+type packetCapture struct {
+	status   PacketCaptureStatus
+	asRoot   func(string) string
+	stopping bool
+	done     chan struct{}
+}
+
+var (
+	packetCaptures   = make(map[string]*packetCapture)
+	packetCapturesMu sync.Mutex
+)
+
+// rootWrapper works out how to run a command as root on the device: directly when adbd runs as
+// root, otherwise through su (AOSP's "su 0" or Magisk/SuperSU's "su -c"). ErrRootRequired when
+// neither works.
+func (a *App) rootWrapper(deviceId string) (func(string) string, error) {
+	// su can hang waiting for a grant prompt on the device
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	script := `echo "A$(id -u)"; echo "B$(su 0 id -u 2>/dev/null)"; echo "C$(su -c 'id -u' 2>/dev/null)"`
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("failed to check root: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		switch strings.TrimSpace(line) {
+		case "A0":
+			return func(cmd string) string { return cmd }, nil
+		case "B0":
+			return func(cmd string) string { return "su 0 sh -c " + shellQuote(cmd) }, nil
+		case "C0":
+			return func(cmd string) string { return "su -c " + shellQuote(cmd) }, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: run adb root on a userdebug build or grant su to the shell", ErrRootRequired)
+}
+
+// locateTcpdump returns the device's tcpdump, pushing a bundled static build for the device's
+// ABI when it has none. Gaze doesn't ship tcpdump; users drop a static build named
+// tcpdump-<arch> (arm64, arm, x86_64 or x86) into <config>/Gaze/bin and the error says so.
+func (a *App) locateTcpdump(deviceId string, asRoot func(string) string) (string, error) {
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell",
+		asRoot("command -v tcpdump || ls "+remoteTcpdumpPath)).CombinedOutput()
+	for _, line := range strings.Split(string(out), "\n") {
+		if p := strings.TrimSpace(line); strings.HasPrefix(p, "/") {
+			return p, nil
+		}
+	}
+
+	abiOut, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "getprop ro.product.cpu.abi").Output()
+	abi := strings.TrimSpace(string(abiOut))
+	name := map[string]string{
+		"arm64-v8a":   "tcpdump-arm64",
+		"armeabi-v7a": "tcpdump-arm",
+		"armeabi":     "tcpdump-arm",
+		"x86_64":      "tcpdump-x86_64",
+		"x86":         "tcpdump-x86",
+	}[abi]
+	if name == "" {
+		return "", fmt.Errorf("tcpdump not found on the device and Gaze has no tcpdump build for ABI %q; install tcpdump on the device at %s", abi, remoteTcpdumpPath)
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	local := filepath.Join(configDir, "Gaze", "bin", name)
+	if _, err := os.Stat(local); err != nil {
+		return "", fmt.Errorf("tcpdump not found on the device; download a statically linked Android tcpdump for %s and save it as %s, Gaze pushes it to the device from there", abi, local)
+	}
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "push", local, remoteTcpdumpPath).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to push tcpdump: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "chmod 755 "+remoteTcpdumpPath).Run()
+	a.Log("Pushed %s to %s", name, deviceId)
+	return remoteTcpdumpPath, nil
+}
+
+// StartPacketCapture runs tcpdump on the device in the background, writing to a pcap there.
+// Root is required. Size updates are emitted as packet-capture-progress until
+// StopPacketCapture pulls the file.
+func (a *App) StartPacketCapture(deviceId string, opts PacketCaptureOptions) (*PacketCaptureStatus, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if opts.Interface == "" {
+		opts.Interface = "any"
+	}
+	if !tcpdumpInterfaceRegex.MatchString(opts.Interface) {
+		return nil, fmt.Errorf("invalid interface %q", opts.Interface)
+	}
+	if opts.SnapLen < 0 || opts.SnapLen > 262144 {
+		return nil, fmt.Errorf("invalid snap length %d", opts.SnapLen)
+	}
+
+	packetCapturesMu.Lock()
+	if c, ok := packetCaptures[deviceId]; ok && !c.status.Finished {
+		packetCapturesMu.Unlock()
+		return nil, fmt.Errorf("a packet capture is already running on %s", deviceId)
+	}
+	packetCapturesMu.Unlock()
+
+	asRoot, err := a.rootWrapper(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	tcpdump, err := a.locateTcpdump(deviceId, asRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := opts.OutputDir
+	if dir == "" {
+		dir = filepath.Join(a.GetRecordingsDir(), "Gaze Captures")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create captures folder: %w", err)
+	}
+	model, _ := a.RunAdbCommand(deviceId, "shell getprop ro.product.model")
+
+	remotePath := fmt.Sprintf("/data/local/tmp/gaze_%d.pcap", time.Now().UnixNano())
+	// -U flushes every packet so the size updates reflect what's been captured
+	args := []string{tcpdump, "-i", opts.Interface, "-s", strconv.Itoa(opts.SnapLen), "-U", "-w", remotePath}
+	if strings.TrimSpace(opts.Filter) != "" {
+		args = append(args, shellQuote(strings.TrimSpace(opts.Filter)))
+	}
+
+	c := &packetCapture{
+		status: PacketCaptureStatus{
+			DeviceID:   deviceId,
+			Interface:  opts.Interface,
+			Filter:     opts.Filter,
+			State:      "running",
+			StartedAt:  time.Now().Unix(),
+			RemotePath: remotePath,
+			LocalPath:  filepath.Join(dir, recordingFileName(model, "pcap")),
+		},
+		asRoot: asRoot,
+		done:   make(chan struct{}),
+	}
+	packetCapturesMu.Lock()
+	packetCaptures[deviceId] = c
+	packetCapturesMu.Unlock()
+
+	go func() {
+		cmd := a.newAdbCommand(nil, "-s", deviceId, "shell", asRoot(strings.Join(args, " ")))
+		out, runErr := a.runTrackedCommand("tcpdump", cmd)
+		a.finishPacketCapture(c, runErr, string(out))
+	}()
+	go a.watchPacketCapture(c)
+
+	a.Log("Started packet capture on %s (%s)", deviceId, strings.Join(args, " "))
+	status := c.status
+	return &status, nil
+}
+
+// watchPacketCapture reports the pcap's size on the device until the capture ends
+func (a *App) watchPacketCapture(c *packetCapture) {
+	ticker := time.NewTicker(packetCaptureProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+		out, err := a.newAdbCommand(nil, "-s", c.status.DeviceID, "shell",
+			c.asRoot("stat -c %s "+c.status.RemotePath)).Output()
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			continue
+		}
+		packetCapturesMu.Lock()
+		if c.status.Finished {
+			packetCapturesMu.Unlock()
+			return
+		}
+		c.status.Bytes = size
+		status := c.status
+		packetCapturesMu.Unlock()
+		wailsRuntime.EventsEmit(a.ctx, "packet-capture-progress", status)
+	}
+}
+
+// finishPacketCapture pulls the pcap once tcpdump has exited, whether it was stopped or died
+func (a *App) finishPacketCapture(c *packetCapture, runErr error, output string) {
+	deviceId := c.status.DeviceID
+	packetCapturesMu.Lock()
+	c.status.State = "pulling"
+	stopped := c.stopping
+	packetCapturesMu.Unlock()
+
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", c.asRoot("chmod 644 "+c.status.RemotePath)).Run()
+	out, pullErr := a.newAdbCommand(nil, "-s", deviceId, "pull", c.status.RemotePath, c.status.LocalPath).CombinedOutput()
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", c.asRoot("rm -f "+c.status.RemotePath)).Run()
+
+	var err error
+	switch {
+	case pullErr != nil:
+		err = fmt.Errorf("failed to pull capture: %w, %s", pullErr, strings.TrimSpace(string(out)))
+		if runErr != nil && !stopped {
+			err = fmt.Errorf("tcpdump failed: %s", lastLines(strings.TrimSpace(output), 3))
+		}
+	case !stopped && runErr != nil:
+		// tcpdump quit on its own, e.g. a bad filter or interface; keep whatever it wrote
+		a.Log("tcpdump on %s exited: %v, %s", deviceId, runErr, lastLines(strings.TrimSpace(output), 3))
+	}
+
+	packetCapturesMu.Lock()
+	c.status.Finished = true
+	c.status.FinishedAt = time.Now().Unix()
+	if fi, statErr := os.Stat(c.status.LocalPath); statErr == nil {
+		c.status.Bytes = fi.Size()
+	}
+	if err != nil {
+		c.status.State = "failed"
+		c.status.Error = err.Error()
+		c.status.LocalPath = ""
+	} else {
+		c.status.State = "done"
+	}
+	status := c.status
+	packetCapturesMu.Unlock()
+	close(c.done)
+
+	a.Log("Packet capture on %s %s", deviceId, status.State)
+	wailsRuntime.EventsEmit(a.ctx, "packet-capture-finished", status)
+}
+
+// StopPacketCapture stops tcpdump, pulls the pcap into the captures folder and deletes it from
+// the device
+func (a *App) StopPacketCapture(deviceId string) (*PacketCaptureStatus, error) {
+	packetCapturesMu.Lock()
+	c, ok := packetCaptures[deviceId]
+	if ok && !c.status.Finished {
+		c.stopping = true
+		c.status.State = "stopping"
+	}
+	packetCapturesMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no packet capture on %s", deviceId)
+	}
+
+	// SIGINT lets tcpdump flush; the bracket keeps the pattern from matching pkill's own shell
+	pattern := "[t]cpdump.*" + c.status.RemotePath
+	if err := a.newAdbCommand(nil, "-s", deviceId, "shell", c.asRoot("pkill -INT -f "+shellQuote(pattern))).Run(); err != nil {
+		a.Log("Stopping packet capture on %s: %v", deviceId, err)
+	}
+	select {
+	case <-c.done:
+	case <-time.After(2 * time.Minute):
+		return nil, fmt.Errorf("capture did not finish in time")
+	}
+
+	packetCapturesMu.Lock()
+	status := c.status
+	packetCapturesMu.Unlock()
+	if status.Error != "" {
+		return &status, fmt.Errorf("%s", status.Error)
+	}
+	return &status, nil
+}
+
+// GetPacketCaptureStatus returns the device's running or most recent capture, or nil
+func (a *App) GetPacketCaptureStatus(deviceId string) *PacketCaptureStatus {
+	packetCapturesMu.Lock()
+	defer packetCapturesMu.Unlock()
+	c, ok := packetCaptures[deviceId]
+	if !ok {
+		return nil
+	}
+	status := c.status
+	return &status
+}
+
+// stopAllPacketCaptures stops tcpdump on every device so it doesn't keep filling /data on shutdown
+func (a *App) stopAllPacketCaptures() {
+	packetCapturesMu.Lock()
+	var running []*packetCapture
+	for _, c := range packetCaptures {
+		if !c.status.Finished {
+			c.stopping = true
+			running = append(running, c)
+		}
+	}
+	packetCapturesMu.Unlock()
+	for _, c := range running {
+		pattern := "[t]cpdump.*" + c.status.RemotePath
+		_ = a.newAdbCommand(nil, "-s", c.status.DeviceID, "shell", c.asRoot("pkill -INT -f "+shellQuote(pattern))).Run()
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPlaybackHistory is how many runs are kept before the oldest are pruned
+const maxPlaybackHistory = 200
+
+var playbackHistoryMu sync.Mutex
+
+// playbackRunLog collects timing for a run while it plays
+type playbackRunLog struct {
+	run PlaybackRun
+}
+
+func (a *App) getPlaybackHistoryPath() string {
+	path := filepath.Join(a.getScriptsPath(), "history")
+	_ = os.MkdirAll(path, 0755)
+	return path
+}
+
+func newPlaybackRunLog(deviceId string, script *TouchScript) *playbackRunLog {
+	now := time.Now()
+	return &playbackRunLog{
+		run: PlaybackRun{
+			// Zero-padded so file names sort by start time
+			ID:         fmt.Sprintf("run_%020d", now.UnixNano()),
+			DeviceID:   deviceId,
+			ScriptName: script.Name,
+			StartTime:  now.UnixMilli(),
+		},
+	}
+}
+
+// event records one executed event; actual is its offset from the start of the schedule,
+// which excludes time spent paused or waiting for elements
+func (l *playbackRunLog) event(index int, event TouchEvent, actual, duration time.Duration, warning string) {
+	timing := PlaybackEventTiming{
+		Index:       index,
+		Type:        event.Type,
+		ScheduledMs: event.Timestamp,
+		ActualMs:    actual.Milliseconds(),
+		DurationMs:  duration.Milliseconds(),
+		Warning:     warning,
+	}
+	timing.DriftMs = timing.ActualMs - timing.ScheduledMs
+	l.run.Events = append(l.run.Events, timing)
+	if warning != "" {
+		l.run.Warnings = append(l.run.Warnings, fmt.Sprintf("event %d: %s", index+1, warning))
+	}
+}
+
+// finishPlaybackRun fills in the outcome and summary, then stores the run
+func (a *App) finishPlaybackRun(l *playbackRunLog, aborted bool, err error) {
+	run := &l.run
+	run.EndTime = time.Now().UnixMilli()
+	switch {
+	case err == nil:
+		run.Status = "completed"
+	case aborted:
+		run.Status = "aborted"
+	default:
+		run.Status = "failed"
+		run.Error = err.Error()
+	}
+
+	run.EventCount = len(run.Events)
+	var sum int64
+	for _, e := range run.Events {
+		sum += e.DriftMs
+		if e.DriftMs > run.MaxDriftMs {
+			run.MaxDriftMs = e.DriftMs
+		}
+	}
+	if run.EventCount > 0 {
+		run.AvgDriftMs = sum / int64(run.EventCount)
+	}
+
+	if err := a.savePlaybackRun(run); err != nil {
+		fmt.Printf("[Automation] Failed to save playback history: %v\n", err)
+	}
+}
+
+// savePlaybackRun writes the run and prunes the oldest beyond maxPlaybackHistory
+func (a *App) savePlaybackRun(run *PlaybackRun) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	playbackHistoryMu.Lock()
+	defer playbackHistoryMu.Unlock()
+
+	dir := a.getPlaybackHistoryPath()
+	if err := writeFileAtomic(filepath.Join(dir, run.ID+".json"), data, 0644); err != nil {
+		return err
+	}
+
+	files := playbackHistoryFiles(dir)
+	for len(files) > maxPlaybackHistory {
+		_ = os.Remove(filepath.Join(dir, files[0]))
+		files = files[1:]
+	}
+	return nil
+}
+
+// playbackHistoryFiles lists stored runs, oldest first
+func playbackHistoryFiles(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), "run_") && strings.HasSuffix(entry.Name(), ".json") {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// GetPlaybackHistory returns the most recent runs, newest first, optionally only those of one
+// script. Per-event timing is left out; use GetPlaybackRunDetail for it. limit <= 0 means all.
+func (a *App) GetPlaybackHistory(scriptName string, limit int) ([]PlaybackRun, error) {
+	playbackHistoryMu.Lock()
+	defer playbackHistoryMu.Unlock()
+
+	dir := a.getPlaybackHistoryPath()
+	files := playbackHistoryFiles(dir)
+	runs := make([]PlaybackRun, 0)
+	for i := len(files) - 1; i >= 0; i-- {
+		if limit > 0 && len(runs) >= limit {
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(dir, files[i]))
+		if err != nil {
+			continue
+		}
+		var run PlaybackRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			continue
+		}
+		if scriptName != "" && run.ScriptName != scriptName {
+			continue
+		}
+		run.Events = nil
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// GetPlaybackRunDetail returns a stored run including its per-event timing table
+func (a *App) GetPlaybackRunDetail(runId string) (*PlaybackRun, error) {
+	if runId == "" || filepath.Base(runId) != runId || !strings.HasPrefix(runId, "run_") {
+		return nil, fmt.Errorf("invalid run id: %s", runId)
+	}
+
+	playbackHistoryMu.Lock()
+	defer playbackHistoryMu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(a.getPlaybackHistoryPath(), runId+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("playback run not found: %s", runId)
+		}
+		return nil, fmt.Errorf("failed to read playback run: %w", err)
+	}
+	var run PlaybackRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse playback run: %w", err)
+	}
+	return &run, nil
+}
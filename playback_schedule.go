@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// playbackSchedulerTick is how often due schedules are checked; cron has minute resolution
+const playbackSchedulerTick = 15 * time.Second
+
+// Scheduled Playback State
+var (
+	playbackSchedules       []ScheduledPlayback
+	playbackSchedulesMu     sync.Mutex
+	playbackSchedulesLoaded bool
+	playbackSchedulerCancel context.CancelFunc
+)
+
+// SchedulePlayback runs a saved touch script on a device whenever the cron expression matches.
+// Schedules are persisted and resume when the app starts.
+func (a *App) SchedulePlayback(deviceId, scriptName, cron string) (*ScheduledPlayback, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if _, err := a.loadTouchScript(scriptName); err != nil {
+		return nil, err
+	}
+	sched, err := parseCron(cron)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if sched.next(now).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", cron)
+	}
+
+	entry := ScheduledPlayback{
+		ID:         fmt.Sprintf("sched_%d", now.UnixNano()),
+		DeviceID:   deviceId,
+		ScriptName: scriptName,
+		Cron:       strings.TrimSpace(cron),
+		NextRun:    sched.next(now).Unix(),
+		CreatedAt:  now.Unix(),
+	}
+
+	playbackSchedulesMu.Lock()
+	defer playbackSchedulesMu.Unlock()
+	a.loadPlaybackSchedulesLocked()
+	playbackSchedules = append(playbackSchedules, entry)
+	if err := a.savePlaybackSchedulesLocked(); err != nil {
+		return nil, fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return &entry, nil
+}
+
+// ListScheduledPlaybacks returns all schedules ordered by their next run
+func (a *App) ListScheduledPlaybacks() []ScheduledPlayback {
+	playbackSchedulesMu.Lock()
+	defer playbackSchedulesMu.Unlock()
+	a.loadPlaybackSchedulesLocked()
+
+	result := append([]ScheduledPlayback{}, playbackSchedules...)
+	sort.Slice(result, func(i, j int) bool { return result[i].NextRun < result[j].NextRun })
+	return result
+}
+
+// CancelScheduledPlayback removes a schedule; a playback it already started keeps running
+func (a *App) CancelScheduledPlayback(id string) error {
+	playbackSchedulesMu.Lock()
+	defer playbackSchedulesMu.Unlock()
+	a.loadPlaybackSchedulesLocked()
+
+	for i, s := range playbackSchedules {
+		if s.ID == id {
+			playbackSchedules = append(playbackSchedules[:i], playbackSchedules[i+1:]...)
+			return a.savePlaybackSchedulesLocked()
+		}
+	}
+	return fmt.Errorf("schedule not found: %s", id)
+}
+
+// renameScheduledScript points schedules of a renamed script at its new name
+func (a *App) renameScheduledScript(oldName, newName string) {
+	playbackSchedulesMu.Lock()
+	defer playbackSchedulesMu.Unlock()
+	a.loadPlaybackSchedulesLocked()
+
+	changed := false
+	for i := range playbackSchedules {
+		if playbackSchedules[i].ScriptName == oldName {
+			playbackSchedules[i].ScriptName = newName
+			changed = true
+		}
+	}
+	if changed {
+		_ = a.savePlaybackSchedulesLocked()
+	}
+}
+
+// startPlaybackScheduler loads persisted schedules and fires them in the background until shutdown
+func (a *App) startPlaybackScheduler() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	playbackSchedulesMu.Lock()
+	playbackSchedulerCancel = cancel
+	a.loadPlaybackSchedulesLocked()
+	playbackSchedulesMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(playbackSchedulerTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				a.runDuePlaybacks(now)
+			}
+		}
+	}()
+}
+
+func (a *App) stopPlaybackScheduler() {
+	playbackSchedulesMu.Lock()
+	defer playbackSchedulesMu.Unlock()
+	if playbackSchedulerCancel != nil {
+		playbackSchedulerCancel()
+		playbackSchedulerCancel = nil
+	}
+}
+
+// runDuePlaybacks starts every schedule whose next run has passed and advances it
+func (a *App) runDuePlaybacks(now time.Time) {
+	playbackSchedulesMu.Lock()
+	var due []ScheduledPlayback
+	for i := range playbackSchedules {
+		s := &playbackSchedules[i]
+		if s.NextRun > now.Unix() {
+			continue
+		}
+		due = append(due, *s)
+		s.LastRun = now.Unix()
+		if sched, err := parseCron(s.Cron); err == nil {
+			s.NextRun = sched.next(now).Unix()
+		}
+	}
+	if len(due) > 0 {
+		_ = a.savePlaybackSchedulesLocked()
+	}
+	playbackSchedulesMu.Unlock()
+
+	for _, s := range due {
+		err := a.startScheduledPlayback(s)
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+			a.Log("Scheduled playback %s of %q on %s failed: %v", s.ID, s.ScriptName, s.DeviceID, err)
+		}
+
+		playbackSchedulesMu.Lock()
+		for i := range playbackSchedules {
+			if playbackSchedules[i].ID == s.ID {
+				playbackSchedules[i].LastError = errMsg
+			}
+		}
+		_ = a.savePlaybackSchedulesLocked()
+		playbackSchedulesMu.Unlock()
+
+		wailsRuntime.EventsEmit(a.ctx, "scheduled-playback-fired", map[string]interface{}{
+			"id":         s.ID,
+			"deviceId":   s.DeviceID,
+			"scriptName": s.ScriptName,
+			"error":      errMsg,
+		})
+	}
+}
+
+func (a *App) startScheduledPlayback(s ScheduledPlayback) error {
+	state, err := a.newAdbCommand(nil, "-s", s.DeviceID, "get-state").Output()
+	if err != nil || strings.TrimSpace(string(state)) != "device" {
+		return fmt.Errorf("device %s is not connected", s.DeviceID)
+	}
+	script, err := a.loadTouchScript(s.ScriptName)
+	if err != nil {
+		return err
+	}
+	return a.PlayTouchScript(s.DeviceID, *script, PlaybackOptions{})
+}
+
+func (a *App) getPlaybackSchedulesPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "scheduled_playbacks.json")
+}
+
+// loadPlaybackSchedulesLocked reads scheduled_playbacks.json once and recomputes next runs so
+// runs missed while the app was closed are skipped rather than fired in a burst; callers must
+// hold playbackSchedulesMu
+func (a *App) loadPlaybackSchedulesLocked() {
+	if playbackSchedulesLoaded {
+		return
+	}
+	playbackSchedulesLoaded = true
+	playbackSchedules = []ScheduledPlayback{}
+
+	data, err := os.ReadFile(a.getPlaybackSchedulesPath())
+	if err != nil {
+		return
+	}
+	var stored []ScheduledPlayback
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return
+	}
+	now := time.Now()
+	for _, s := range stored {
+		sched, err := parseCron(s.Cron)
+		if err != nil || sched.next(now).IsZero() {
+			continue
+		}
+		s.NextRun = sched.next(now).Unix()
+		playbackSchedules = append(playbackSchedules, s)
+	}
+}
+
+// savePlaybackSchedulesLocked writes scheduled_playbacks.json; callers must hold playbackSchedulesMu
+func (a *App) savePlaybackSchedulesLocked() error {
+	data, err := json.MarshalIndent(playbackSchedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getPlaybackSchedulesPath(), data, 0644)
+}
+
+// cronSchedule is a parsed five-field cron expression; each field is the set of allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+var cronAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// parseCron accepts "minute hour day-of-month month day-of-week" with *, lists, ranges and
+// steps, plus the @hourly/@daily/@weekly/@monthly shortcuts
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields", expr)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Both 0 and 7 mean Sunday
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("bad step in %q", part)
+			}
+			step = n
+			part = part[:idx]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("bad range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("bad value %q", part)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// matchesDay follows cron's rule that a restricted day-of-month and day-of-week are OR'ed
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	domOK := c.dom[t.Day()]
+	dowOK := c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowOK
+	case c.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// next returns the first matching minute strictly after t, or the zero time if none
+// exists within five years (e.g. "0 0 31 2 *")
+func (c *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// portForwardStore is the on-disk layout of port_forwards.json, favorites keyed by device serial
+type portForwardStore struct {
+	Favorites map[string][]PortForward `json:"favorites"`
+}
+
+var (
+	portForwards       *portForwardStore
+	portForwardsMu     sync.Mutex
+	portForwardsLoaded bool
+)
+
+func (a *App) getPortForwardsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "port_forwards.json")
+}
+
+// loadPortForwardsLocked reads port_forwards.json once; callers must hold portForwardsMu
+func (a *App) loadPortForwardsLocked() *portForwardStore {
+	if portForwardsLoaded {
+		return portForwards
+	}
+	portForwardsLoaded = true
+	portForwards = &portForwardStore{Favorites: make(map[string][]PortForward)}
+
+	data, err := os.ReadFile(a.getPortForwardsPath())
+	if err != nil {
+		return portForwards
+	}
+	var stored portForwardStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return portForwards
+	}
+	if stored.Favorites != nil {
+		portForwards.Favorites = stored.Favorites
+	}
+	return portForwards
+}
+
+// savePortForwardsLocked writes port_forwards.json; callers must hold portForwardsMu
+func (a *App) savePortForwardsLocked() error {
+	data, err := json.MarshalIndent(portForwards, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getPortForwardsPath(), data, 0644)
+}
+
+// validateForwardSpec checks an adb socket spec. jdwp: only names a process on the device, so
+// it's accepted only where allowJdwp is set (the device side of a forward), and tcp:0 (let adb
+// pick a port) only where allowAnyPort is set (the host side of a forward).
+func validateForwardSpec(spec string, allowJdwp, allowAnyPort bool) error {
+	kind, value, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok || value == "" {
+		return fmt.Errorf("invalid spec %q: expected tcp:<port>, localabstract:<name> or jdwp:<pid>", spec)
+	}
+	switch kind {
+	case "tcp":
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 0 || port > 65535 || (port == 0 && !allowAnyPort) {
+			return fmt.Errorf("invalid port in %q", spec)
+		}
+	case "localabstract", "localreserved", "localfilesystem":
+		if strings.ContainsAny(value, " \t\n") {
+			return fmt.Errorf("invalid socket name in %q", spec)
+		}
+	case "jdwp":
+		if !allowJdwp {
+			return fmt.Errorf("%q: jdwp can only be the device side of a forward", spec)
+		}
+		if pid, err := strconv.Atoi(value); err != nil || pid <= 0 {
+			return fmt.Errorf("invalid pid in %q", spec)
+		}
+	default:
+		return fmt.Errorf("unsupported spec type %q in %q", kind, spec)
+	}
+	return nil
+}
+
+// forwardError turns adb's forward/reverse failures into something a person can act on
+func forwardError(output string, err error, spec string) error {
+	msg := strings.TrimSpace(output)
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "address already in use") || strings.Contains(lower, "cannot bind"):
+		return fmt.Errorf("%s is already in use; pick another port or remove the existing forward", spec)
+	case strings.Contains(lower, "device offline"):
+		return fmt.Errorf("device is offline")
+	case strings.Contains(lower, "not found") && strings.Contains(lower, "device"):
+		return fmt.Errorf("device is not connected")
+	case strings.Contains(lower, "listener") && strings.Contains(lower, "not found"):
+		return fmt.Errorf("no forward for %s", spec)
+	case strings.Contains(lower, "more than one device"):
+		return fmt.Errorf("more than one device connected; select a device")
+	case strings.Contains(lower, "already reversed") || strings.Contains(lower, "already exists"):
+		return fmt.Errorf("%s is already forwarded", spec)
+	}
+	if msg == "" {
+		return err
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// ListPortForwards returns the device's forwards (host to device) and reverses (device to
+// host), marking the ones that are saved as favorites
+func (a *App) ListPortForwards(deviceId string) ([]PortForward, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	var result []PortForward
+
+	// forward --list covers every device: "<serial> <local> <remote>"
+	out, err := a.newAdbCommand(nil, "forward", "--list").CombinedOutput()
+	if err != nil {
+		return nil, forwardError(string(out), err, "")
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == deviceId {
+			result = append(result, PortForward{Direction: "forward", Local: fields[1], Remote: fields[2]})
+		}
+	}
+
+	// reverse --list is per device: "<transport> <device spec> <host spec>"
+	out, err = a.newAdbCommand(nil, "-s", deviceId, "reverse", "--list").CombinedOutput()
+	if err != nil {
+		return nil, forwardError(string(out), err, "")
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 {
+			result = append(result, PortForward{Direction: "reverse", Remote: fields[1], Local: fields[2]})
+		}
+	}
+
+	portForwardsMu.Lock()
+	favorites := a.loadPortForwardsLocked().Favorites[a.serialFor(deviceId)]
+	portForwardsMu.Unlock()
+	for i := range result {
+		for _, f := range favorites {
+			if f.Direction == result[i].Direction && f.Local == result[i].Local && f.Remote == result[i].Remote {
+				result[i].Favorite = true
+				result[i].Label = f.Label
+			}
+		}
+	}
+	return result, nil
+}
+
+// AddPortForward forwards a host socket to the device, e.g. tcp:8700 to jdwp:1234. A local of
+// tcp:0 lets adb choose the port, which is returned.
+func (a *App) AddPortForward(deviceId, local, remote string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if err := validateForwardSpec(local, false, true); err != nil {
+		return "", err
+	}
+	if err := validateForwardSpec(remote, true, false); err != nil {
+		return "", err
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "forward", local, remote).CombinedOutput()
+	if err != nil {
+		return "", forwardError(string(out), err, local)
+	}
+	a.Log("Forwarded %s to %s on %s", local, remote, deviceId)
+	if local == "tcp:0" {
+		// adb prints the port it picked
+		return "tcp:" + strings.TrimSpace(string(out)), nil
+	}
+	return local, nil
+}
+
+// AddPortReverse makes a host socket reachable from the device, e.g. tcp:8081 for a dev server
+func (a *App) AddPortReverse(deviceId, remote, local string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if err := validateForwardSpec(remote, false, false); err != nil {
+		return err
+	}
+	if err := validateForwardSpec(local, false, false); err != nil {
+		return err
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "reverse", remote, local).CombinedOutput()
+	if err != nil {
+		return forwardError(string(out), err, remote)
+	}
+	a.Log("Reversed %s to %s on %s", remote, local, deviceId)
+	return nil
+}
+
+// RemovePortForward removes one forward, identified by its host spec, or one reverse,
+// identified by its device spec
+func (a *App) RemovePortForward(deviceId, direction, spec string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if direction != "forward" && direction != "reverse" {
+		return fmt.Errorf("unknown direction %q", direction)
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, direction, "--remove", spec).CombinedOutput()
+	if err != nil {
+		return forwardError(string(out), err, spec)
+	}
+	return nil
+}
+
+// RemoveAllPortForwards removes every forward and reverse of the device
+func (a *App) RemoveAllPortForwards(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	for _, direction := range []string{"forward", "reverse"} {
+		out, err := a.newAdbCommand(nil, "-s", deviceId, direction, "--remove-all").CombinedOutput()
+		if err != nil {
+			return forwardError(string(out), err, "")
+		}
+	}
+	return nil
+}
+
+// SavePortForwardFavorite remembers a forward or reverse for the device, replacing the label
+// of an identical one
+func (a *App) SavePortForwardFavorite(deviceId string, fwd PortForward) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	switch fwd.Direction {
+	case "forward":
+		if err := validateForwardSpec(fwd.Local, false, false); err != nil {
+			return err
+		}
+		if err := validateForwardSpec(fwd.Remote, true, false); err != nil {
+			return err
+		}
+	case "reverse":
+		if err := validateForwardSpec(fwd.Remote, false, false); err != nil {
+			return err
+		}
+		if err := validateForwardSpec(fwd.Local, false, false); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown direction %q", fwd.Direction)
+	}
+	fwd.Favorite = true
+
+	portForwardsMu.Lock()
+	defer portForwardsMu.Unlock()
+	store := a.loadPortForwardsLocked()
+	serial := a.serialFor(deviceId)
+	list := store.Favorites[serial]
+	for i, f := range list {
+		if f.Direction == fwd.Direction && f.Local == fwd.Local && f.Remote == fwd.Remote {
+			list[i].Label = fwd.Label
+			return a.savePortForwardsLocked()
+		}
+	}
+	fwd.CreatedAt = time.Now().Unix()
+	store.Favorites[serial] = append(list, fwd)
+	return a.savePortForwardsLocked()
+}
+
+// RemovePortForwardFavorite forgets a saved forward or reverse
+func (a *App) RemovePortForwardFavorite(deviceId string, fwd PortForward) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	portForwardsMu.Lock()
+	defer portForwardsMu.Unlock()
+	store := a.loadPortForwardsLocked()
+	serial := a.serialFor(deviceId)
+	list := store.Favorites[serial]
+	for i, f := range list {
+		if f.Direction == fwd.Direction && f.Local == fwd.Local && f.Remote == fwd.Remote {
+			store.Favorites[serial] = append(list[:i], list[i+1:]...)
+			return a.savePortForwardsLocked()
+		}
+	}
+	return nil
+}
+
+// ListPortForwardFavorites returns the device's saved forwards and reverses
+func (a *App) ListPortForwardFavorites(deviceId string) []PortForward {
+	portForwardsMu.Lock()
+	defer portForwardsMu.Unlock()
+	list := a.loadPortForwardsLocked().Favorites[a.serialFor(deviceId)]
+	return append([]PortForward{}, list...)
+}
+
+// ApplyPortForwardFavorites sets up all of the device's favorites, e.g. after it reconnects.
+// Each one is tried; failures are reported per entry rather than stopping the rest.
+func (a *App) ApplyPortForwardFavorites(deviceId string) []PortForwardResult {
+	var results []PortForwardResult
+	for _, f := range a.ListPortForwardFavorites(deviceId) {
+		var err error
+		if f.Direction == "reverse" {
+			err = a.AddPortReverse(deviceId, f.Remote, f.Local)
+		} else {
+			_, err = a.AddPortForward(deviceId, f.Local, f.Remote)
+		}
+		r := PortForwardResult{Forward: f, Success: err == nil}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group so killProcessGroup reaches its children
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessGroup kills cmd and everything it spawned
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	kill.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+var (
+	kernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procAttachConsole         = kernel32.NewProc("AttachConsole")
+	procFreeConsole           = kernel32.NewProc("FreeConsole")
+	procSetConsoleCtrlHandler = kernel32.NewProc("SetConsoleCtrlHandler")
+	procGenerateConsoleCtrl   = kernel32.NewProc("GenerateConsoleCtrlEvent")
+)
+
+// interruptProcess asks a process started by setProcessGroup to exit cleanly. Windows has no
+// SIGINT, so a CTRL_BREAK is sent to its process group through the console it runs in; killing
+// scrcpy instead would leave its recording without the trailer that makes it playable.
+func interruptProcess(p *os.Process) error {
+	// Ignore the break ourselves while it is delivered
+	procSetConsoleCtrlHandler.Call(0, 1)
+	defer procSetConsoleCtrlHandler.Call(0, 0)
+
+	// A GUI launch has no console of its own, so borrow the child's for the event; when Gaze
+	// already has one (started from a terminal) the child shares it and attaching fails harmlessly
+	if r, _, _ := procAttachConsole.Call(uintptr(p.Pid)); r != 0 {
+		defer procFreeConsole.Call()
+	}
+
+	if r, _, err := procGenerateConsoleCtrl.Call(syscall.CTRL_BREAK_EVENT, uintptr(p.Pid)); r == 0 {
+		return fmt.Errorf("sending CTRL_BREAK to process %d: %w", p.Pid, err)
+	}
+	return nil
+}
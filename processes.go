@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appUserPattern matches the users Android gives app processes: u0_a123, u10_i5 (isolated)
+var appUserPattern = regexp.MustCompile(`^u\d+_[ai]\d+$`)
+
+// ListProcesses returns every process on the device, sorted by resident memory
+func (a *App) ListProcesses(deviceId string) ([]ProcessInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// toybox ps (Android 8+) takes -A and -o; toolbox ps lists everything by default and
+	// rejects both, printing nothing useful
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", "ps -A -o PID,PPID,USER,NICE,RSS,NAME 2>/dev/null || ps -p 2>/dev/null || ps").Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	procs := parsePsOutput(string(out))
+	if len(procs) == 0 {
+		return nil, fmt.Errorf("unrecognised ps output")
+	}
+	sort.SliceStable(procs, func(i, j int) bool { return procs[i].RSS > procs[j].RSS })
+	return procs, nil
+}
+
+// parsePsOutput reads ps output by its header, so both the toybox -o layout and the toolbox
+// "USER PID PPID VSIZE RSS [PRIO NICE RTPRI SCHED] WCHAN PC NAME" layout work
+func parsePsOutput(text string) []ProcessInfo {
+	var procs []ProcessInfo
+	cols := map[string]int{}
+	var header []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if header == nil {
+			if fields[0] != "PID" && fields[0] != "USER" {
+				continue
+			}
+			header = fields
+			for i, name := range header {
+				cols[strings.ToUpper(name)] = i
+			}
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		pid, err := strconv.Atoi(get("PID"))
+		if err != nil {
+			continue
+		}
+		p := ProcessInfo{PID: pid, User: get("USER")}
+		p.PPID, _ = strconv.Atoi(get("PPID"))
+		p.Nice, _ = strconv.Atoi(get("NICE")) // "NI" in some toybox builds
+		if _, ok := cols["NICE"]; !ok {
+			p.Nice, _ = strconv.Atoi(get("NI"))
+		}
+		if rss, err := strconv.ParseInt(get("RSS"), 10, 64); err == nil {
+			p.RSS = rss * 1024
+		}
+		// NAME is last and has no spaces; toolbox adds an unlabelled state column before it
+		// and leaves others blank, so column positions can't be trusted for it
+		p.Name = fields[len(fields)-1]
+		p.Package = processPackage(p.Name, p.User)
+		p.Killable = p.User == "shell" || p.Package != ""
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// processPackage returns the package an app process belongs to: app processes are named
+// after their package, with ":suffix" for secondary processes
+func processPackage(name, user string) string {
+	if strings.HasPrefix(name, "[") || strings.Contains(name, "/") {
+		return ""
+	}
+	pkg, _, _ := strings.Cut(name, ":")
+	if !strings.Contains(pkg, ".") {
+		return ""
+	}
+	if appUserPattern.MatchString(user) || user == "system" || user == "radio" || user == "bluetooth" || user == "nfc" {
+		return pkg
+	}
+	return ""
+}
+
+// KillProcess sends signal (15 when zero) to the process. Without root the shell can only
+// signal its own processes, so for an app process it falls back to `am force-stop`. The
+// result says which method worked.
+func (a *App) KillProcess(deviceId string, pid int, signal int) (*KillResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if pid <= 1 {
+		return nil, fmt.Errorf("invalid pid %d", pid)
+	}
+	if signal <= 0 {
+		signal = 15
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	out, err := a.newAdbCommand(ctx, "-s", deviceId, "shell", fmt.Sprintf("kill -%d %d; echo __EXIT:$?", signal, pid)).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run kill: %w", err)
+	}
+	output := string(out)
+	if strings.Contains(output, "__EXIT:0") {
+		a.Log("Sent signal %d to %d on %s", signal, pid, deviceId)
+		return &KillResult{PID: pid, Method: "kill"}, nil
+	}
+	killErr := strings.TrimSpace(strings.Split(output, "__EXIT:")[0])
+	lower := strings.ToLower(killErr)
+	if !strings.Contains(lower, "not permitted") && !strings.Contains(lower, "permission denied") {
+		return nil, fmt.Errorf("kill failed: %s", killErr)
+	}
+
+	// Name and owner of the process, to see whether it is an app we may force-stop
+	out, _ = a.newAdbCommand(ctx, "-s", deviceId, "shell", fmt.Sprintf("stat -c %%U /proc/%d; cat /proc/%d/cmdline", pid, pid)).Output()
+	lines := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)
+	pkg := ""
+	if len(lines) == 2 {
+		name, _, _ := strings.Cut(lines[1], "\x00")
+		pkg = processPackage(strings.TrimSpace(name), strings.TrimSpace(lines[0]))
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("kill failed: %s (not an app process, root required)", killErr)
+	}
+
+	out, err = a.newAdbCommand(ctx, "-s", deviceId, "shell", "am", "force-stop", pkg).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("force-stop of %s failed: %w, output: %s", pkg, err, strings.TrimSpace(string(out)))
+	}
+	a.Log("Force-stopped %s (pid %d) on %s", pkg, pid, deviceId)
+	return &KillResult{PID: pid, Method: "force-stop", Package: pkg}, nil
+}
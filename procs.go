@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// managedProcess is a child process registered in App.managedProcs
+type managedProcess struct {
+	kind    string
+	cmd     *exec.Cmd
+	cancel  context.CancelFunc
+	started time.Time
+}
+
+// trackProcess registers a started command so it is terminated when Gaze exits. cancel, when
+// given, is called first so CommandContext owners see a normal cancellation.
+func (a *App) trackProcess(kind string, cmd *exec.Cmd, cancel context.CancelFunc) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	a.managedProcsMu.Lock()
+	a.managedProcs[cmd] = &managedProcess{kind: kind, cmd: cmd, cancel: cancel, started: time.Now()}
+	a.managedProcsMu.Unlock()
+}
+
+// untrackProcess removes a command from the registry once it has exited; safe to call twice
+func (a *App) untrackProcess(cmd *exec.Cmd) {
+	a.managedProcsMu.Lock()
+	delete(a.managedProcs, cmd)
+	a.managedProcsMu.Unlock()
+}
+
+// runTrackedCommand is CombinedOutput for commands that can run long enough to outlive the app
+func (a *App) runTrackedCommand(kind string, cmd *exec.Cmd) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	a.trackProcess(kind, cmd, nil)
+	defer a.untrackProcess(cmd)
+	err := cmd.Wait()
+	return out.Bytes(), err
+}
+
+// ListManagedProcesses returns the tracked child processes, oldest first
+func (a *App) ListManagedProcesses() []ManagedProcess {
+	a.managedProcsMu.Lock()
+	defer a.managedProcsMu.Unlock()
+
+	result := []ManagedProcess{}
+	for _, p := range a.managedProcs {
+		args := append([]string{filepath.Base(p.cmd.Path)}, p.cmd.Args[1:]...)
+		result = append(result, ManagedProcess{
+			PID:       p.cmd.Process.Pid,
+			Kind:      p.kind,
+			Command:   strings.Join(args, " "),
+			StartedAt: p.started.Unix(),
+			AgeMs:     time.Since(p.started).Milliseconds(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt < result[j].StartedAt })
+	return result
+}
+
+// stopManagedProcesses cancels and terminates every tracked process, waits up to grace for
+// their owners to reap them and kills whatever is left
+func (a *App) stopManagedProcesses(grace time.Duration) {
+	a.managedProcsMu.Lock()
+	procs := make([]*managedProcess, 0, len(a.managedProcs))
+	for _, p := range a.managedProcs {
+		procs = append(procs, p)
+	}
+	a.managedProcsMu.Unlock()
+	if len(procs) == 0 {
+		return
+	}
+
+	for _, p := range procs {
+		os.Stderr.WriteString("[SHUTDOWN] Stopping " + p.kind + " (" + filepath.Base(p.cmd.Path) + ")\n")
+		if p.cancel != nil {
+			p.cancel()
+		}
+		if runtime.GOOS == "windows" {
+			_ = p.cmd.Process.Kill()
+		} else {
+			_ = p.cmd.Process.Signal(syscall.SIGTERM)
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	for time.Now().Before(deadline) {
+		a.managedProcsMu.Lock()
+		remaining := len(a.managedProcs)
+		a.managedProcsMu.Unlock()
+		if remaining == 0 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	a.managedProcsMu.Lock()
+	defer a.managedProcsMu.Unlock()
+	for cmd := range a.managedProcs {
+		_ = cmd.Process.Kill()
+		delete(a.managedProcs, cmd)
+	}
+}
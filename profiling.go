@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// heapDumpTimeout bounds the wait for a heap dump file to be written
+	heapDumpTimeout = 5 * time.Minute
+	// remoteFileSettle is how many unchanged size polls in a row count as the file being done
+	remoteFileSettle = 3
+)
+
+// ErrAppNotDebuggable is returned when heap dumps or method traces are requested for an app
+// that isn't debuggable on a user build
+var ErrAppNotDebuggable = errors.New("the app isn't debuggable; build it with android:debuggable=\"true\" or use a userdebug/eng device")
+
+// methodTrace is a running am profile session
+type methodTrace struct {
+	pkg        string
+	remotePath string
+	startedAt  time.Time
+}
+
+var (
+	methodTraces   = make(map[string]*methodTrace)
+	methodTracesMu sync.Mutex
+)
+
+// checkProfilable verifies pkg is installed, debuggable (or the build is) and running, so the
+// am errors don't have to be decoded after the fact
+func (a *App) checkProfilable(deviceId, pkg string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if !packageNameRegex.MatchString(pkg) {
+		return fmt.Errorf("invalid package name %q", pkg)
+	}
+	script := fmt.Sprintf("echo path=$(pm path %[1]s); echo debuggable=$(getprop ro.debuggable); echo pid=$(pidof %[1]s); dumpsys package %[1]s | grep -m1 pkgFlags=", pkg)
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w, %s", pkg, err, strings.TrimSpace(string(out)))
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			values[k] = v
+		}
+	}
+	if !strings.HasPrefix(values["path"], "package:") {
+		return fmt.Errorf("unknown package %s: it isn't installed on the device", pkg)
+	}
+	if m := pkgFlagsRegex.FindStringSubmatch(string(out)); m != nil && !strings.Contains(m[1], "DEBUGGABLE") && values["debuggable"] != "1" {
+		return ErrAppNotDebuggable
+	}
+	if values["pid"] == "" {
+		return fmt.Errorf("%s isn't running; start the app first", pkg)
+	}
+	return nil
+}
+
+// amProfilingError turns am's complaints into the errors checkProfilable would have given
+func amProfilingError(pkg, output string) error {
+	switch {
+	case strings.Contains(output, "Unknown package") || strings.Contains(output, "Unknown process"):
+		return fmt.Errorf("unknown package or process %s", pkg)
+	case strings.Contains(output, "not debuggable"):
+		return ErrAppNotDebuggable
+	case strings.Contains(output, "Exception") || strings.HasPrefix(output, "Error"):
+		return fmt.Errorf("%s", lastLines(output, 3))
+	}
+	return nil
+}
+
+// waitForRemoteFile polls the size of a device file, emitting event with it, until it has been
+// non-empty and unchanged for remoteFileSettle polls
+func (a *App) waitForRemoteFile(deviceId, remotePath, event string, payload map[string]interface{}, timeout time.Duration) (int64, error) {
+	start := time.Now()
+	var last int64 = -1
+	stable := 0
+	for {
+		out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "stat -c %s "+shellQuote(remotePath)+" 2>/dev/null").Output()
+		size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err == nil {
+			data := map[string]interface{}{"bytes": size, "elapsedMs": time.Since(start).Milliseconds()}
+			for k, v := range payload {
+				data[k] = v
+			}
+			wailsRuntime.EventsEmit(a.ctx, event, data)
+			if size > 0 && size == last {
+				stable++
+				if stable >= remoteFileSettle {
+					return size, nil
+				}
+			} else {
+				stable = 0
+			}
+			last = size
+		}
+		if time.Since(start) > timeout {
+			if last > 0 {
+				return last, fmt.Errorf("%s was still growing after %s", remotePath, timeout)
+			}
+			return 0, fmt.Errorf("%s was not written within %s", remotePath, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// profilingOutputDir resolves destDir, defaulting to the recordings folder
+func (a *App) profilingOutputDir(destDir string) (string, error) {
+	if destDir == "" {
+		destDir = a.GetRecordingsDir()
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output folder: %w", err)
+	}
+	return destDir, nil
+}
+
+// CaptureHeapDump dumps the Java heap of a running debuggable app, pulls it into destDir (empty
+// uses the recordings folder) and removes it from the device. heap-dump-progress reports the
+// file size while the app writes it. When the SDK's hprof-conv is found the dump is converted to
+// the standard hprof format that tools other than Android Studio read.
+func (a *App) CaptureHeapDump(deviceId, packageName, destDir string) (*HeapDumpResult, error) {
+	a.updateLastActive(deviceId)
+	if err := a.checkProfilable(deviceId, packageName); err != nil {
+		return nil, err
+	}
+	dir, err := a.profilingOutputDir(destDir)
+	if err != nil {
+		return nil, err
+	}
+
+	remotePath := "/data/local/tmp/" + packageName + ".hprof"
+	defer a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -f "+shellQuote(remotePath)).Run()
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -f "+shellQuote(remotePath)).Run()
+
+	// Newer releases block until the dump is written; older ones return at once
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "am dumpheap "+packageName+" "+shellQuote(remotePath)).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if perr := amProfilingError(packageName, text); perr != nil {
+		return nil, perr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("am dumpheap failed: %w, %s", err, text)
+	}
+	payload := map[string]interface{}{"deviceId": deviceId, "package": packageName}
+	if _, err := a.waitForRemoteFile(deviceId, remotePath, "heap-dump-progress", payload, heapDumpTimeout); err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("%s_%s", packageName, time.Now().Format("20060102_150405"))
+	result := &HeapDumpResult{Package: packageName, LocalPath: filepath.Join(dir, base+".hprof")}
+	pullPath := result.LocalPath
+	conv := a.findPlatformTool("hprof-conv")
+	if conv != "" {
+		pullPath = filepath.Join(dir, base+".android.hprof")
+	}
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "pull", remotePath, pullPath).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to pull heap dump: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	if conv != "" {
+		if out, err := exec.Command(conv, pullPath, result.LocalPath).CombinedOutput(); err != nil {
+			// The Android-format dump is still usable in Android Studio
+			result.LocalPath = pullPath
+			result.Warning = fmt.Sprintf("hprof-conv failed: %v %s", err, lastLines(strings.TrimSpace(string(out)), 2))
+		} else {
+			_ = os.Remove(pullPath)
+			result.Converted = true
+		}
+	} else {
+		result.Warning = "hprof-conv was not found; the dump is in Android's format, which Android Studio opens but MAT and other tools need converted"
+	}
+	if info, err := os.Stat(result.LocalPath); err == nil {
+		result.Size = info.Size()
+	}
+	a.Log("Heap dump of %s on %s saved to %s (%d bytes)", packageName, deviceId, result.LocalPath, result.Size)
+	return result, nil
+}
+
+// StartMethodTrace starts Java method tracing (am profile) in a running debuggable app;
+// StopMethodTrace ends it and pulls the .trace file
+func (a *App) StartMethodTrace(deviceId, packageName string) error {
+	a.updateLastActive(deviceId)
+	methodTracesMu.Lock()
+	if t, ok := methodTraces[deviceId]; ok {
+		methodTracesMu.Unlock()
+		return fmt.Errorf("%s is already being traced on %s", t.pkg, deviceId)
+	}
+	methodTracesMu.Unlock()
+	if err := a.checkProfilable(deviceId, packageName); err != nil {
+		return err
+	}
+
+	remotePath := "/data/local/tmp/" + packageName + ".trace"
+	script := "rm -f " + shellQuote(remotePath) + "; am profile start " + packageName + " " + shellQuote(remotePath)
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if perr := amProfilingError(packageName, text); perr != nil {
+		return perr
+	}
+	if err != nil {
+		return fmt.Errorf("am profile start failed: %w, %s", err, text)
+	}
+
+	methodTracesMu.Lock()
+	methodTraces[deviceId] = &methodTrace{pkg: packageName, remotePath: remotePath, startedAt: time.Now()}
+	methodTracesMu.Unlock()
+	wailsRuntime.EventsEmit(a.ctx, "method-trace-started", map[string]interface{}{"deviceId": deviceId, "package": packageName})
+	a.Log("Started method tracing of %s on %s", packageName, deviceId)
+	return nil
+}
+
+// StopMethodTrace stops the method trace on the device, pulls it into destDir (empty uses the
+// recordings folder), removes it from the device and returns the local path
+func (a *App) StopMethodTrace(deviceId, destDir string) (string, error) {
+	methodTracesMu.Lock()
+	t, ok := methodTraces[deviceId]
+	delete(methodTraces, deviceId)
+	methodTracesMu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("no method trace is running on %s", deviceId)
+	}
+	defer a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -f "+shellQuote(t.remotePath)).Run()
+
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "am profile stop "+t.pkg).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if perr := amProfilingError(t.pkg, text); perr != nil {
+		return "", perr
+	}
+	if err != nil {
+		return "", fmt.Errorf("am profile stop failed: %w, %s", err, text)
+	}
+	dir, err := a.profilingOutputDir(destDir)
+	if err != nil {
+		return "", err
+	}
+	// The trace is flushed after am returns
+	payload := map[string]interface{}{"deviceId": deviceId, "package": t.pkg}
+	if _, err := a.waitForRemoteFile(deviceId, t.remotePath, "method-trace-progress", payload, time.Minute); err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(dir, fmt.Sprintf("%s_%s.trace", t.pkg, t.startedAt.Format("20060102_150405")))
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "pull", t.remotePath, localPath).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to pull method trace: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	wailsRuntime.EventsEmit(a.ctx, "method-trace-stopped", map[string]interface{}{"deviceId": deviceId, "package": t.pkg, "path": localPath})
+	a.Log("Method trace of %s on %s saved to %s", t.pkg, deviceId, localPath)
+	return localPath, nil
+}
+
+// IsMethodTracing returns the package being traced on the device, or ""
+func (a *App) IsMethodTracing(deviceId string) string {
+	methodTracesMu.Lock()
+	defer methodTracesMu.Unlock()
+	if t, ok := methodTraces[deviceId]; ok {
+		return t.pkg
+	}
+	return ""
+}
+
+// stopAllMethodTraces stops profiling and discards the traces on shutdown
+func (a *App) stopAllMethodTraces() {
+	methodTracesMu.Lock()
+	traces := methodTraces
+	methodTraces = make(map[string]*methodTrace)
+	methodTracesMu.Unlock()
+	for deviceId, t := range traces {
+		_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "am profile stop "+t.pkg+"; rm -f "+shellQuote(t.remotePath)).Run()
+	}
+}
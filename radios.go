@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// radioToggleTimeout is how long a toggle gets to show up in settings; Bluetooth in particular
+// takes a few seconds to come up
+const radioToggleTimeout = 6 * time.Second
+
+// radioStrategy is one way of flipping a radio, used on devices at or above minSDK (and below
+// maxSDK when set)
+type radioStrategy struct {
+	name    string
+	minSDK  int
+	maxSDK  int
+	command func(on bool) string
+}
+
+// onOff renders a toggle the way most shell tools take it
+func onOff(on bool, yes, no string) string {
+	if on {
+		return yes
+	}
+	return no
+}
+
+var airplaneStrategies = []radioStrategy{
+	{name: "cmd connectivity", minSDK: 29, command: func(on bool) string {
+		return "cmd connectivity airplane-mode " + onOff(on, "enable", "disable")
+	}},
+	// The broadcast is refused from the shell on Android 7+, but there settings alone still
+	// takes effect the next time the radios are touched
+	{name: "settings + broadcast", command: func(on bool) string {
+		return fmt.Sprintf("settings put global airplane_mode_on %s && am broadcast -a android.intent.action.AIRPLANE_MODE --ez state %t",
+			onOff(on, "1", "0"), on)
+	}},
+}
+
+var mobileDataStrategies = []radioStrategy{
+	{name: "svc data", command: func(on bool) string {
+		return "svc data " + onOff(on, "enable", "disable")
+	}},
+}
+
+var bluetoothStrategies = []radioStrategy{
+	{name: "cmd bluetooth_manager", minSDK: 30, command: func(on bool) string {
+		return "cmd bluetooth_manager " + onOff(on, "enable", "disable")
+	}},
+	{name: "svc bluetooth", minSDK: 26, command: func(on bool) string {
+		return "svc bluetooth " + onOff(on, "enable", "disable")
+	}},
+	// Older devices can only be asked; the user has to accept the prompt on the device
+	{name: "enable request", maxSDK: 26, command: func(on bool) string {
+		return "am start -a android.bluetooth.adapter.action." + onOff(on, "REQUEST_ENABLE", "REQUEST_DISABLE")
+	}},
+}
+
+// SetAirplaneMode turns airplane mode on or off and reports the state read back from settings
+func (a *App) SetAirplaneMode(deviceId string, on bool) (*RadioToggleResult, error) {
+	return a.toggleRadio(deviceId, "airplane_mode", on, airplaneStrategies, "airplane_mode_on")
+}
+
+// SetMobileData turns mobile data on or off and reports the state read back from settings
+func (a *App) SetMobileData(deviceId string, on bool) (*RadioToggleResult, error) {
+	return a.toggleRadio(deviceId, "mobile_data", on, mobileDataStrategies, "mobile_data")
+}
+
+// SetBluetooth turns Bluetooth on or off and reports the state read back from settings
+func (a *App) SetBluetooth(deviceId string, on bool) (*RadioToggleResult, error) {
+	return a.toggleRadio(deviceId, "bluetooth", on, bluetoothStrategies, "bluetooth_on")
+}
+
+// GetRadioStates reads airplane mode, mobile data, Wi-Fi and Bluetooth from global settings
+func (a *App) GetRadioStates(deviceId string) (map[string]bool, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	keys := []string{"airplane_mode_on", "mobile_data", "wifi_on", "bluetooth_on"}
+	script := make([]string, len(keys))
+	for i, k := range keys {
+		script[i] = "settings get global " + k
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(script, "; ")).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	states := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		if i < len(lines) {
+			// wifi_on is 2 when on while airplane mode is; bluetooth_on is 2 for BLE-only
+			v := strings.TrimSpace(lines[i])
+			states[strings.TrimSuffix(k, "_on")] = v != "" && v != "0" && v != "null"
+		}
+	}
+	return states, nil
+}
+
+// toggleRadio runs the first strategy the device's Android version supports and accepts, then
+// polls the setting until it matches or radioToggleTimeout passes
+func (a *App) toggleRadio(deviceId, name string, on bool, strategies []radioStrategy, settingKey string) (*RadioToggleResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	sdk := a.getSDKInt(deviceId)
+	result := &RadioToggleResult{Radio: name, Requested: on}
+
+	var lastErr error
+	for _, s := range strategies {
+		if sdk > 0 && (sdk < s.minSDK || (s.maxSDK > 0 && sdk >= s.maxSDK)) {
+			continue
+		}
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", s.command(on)).CombinedOutput()
+		text := strings.TrimSpace(string(out))
+		if err == nil && !strings.Contains(text, "Unknown command") && !strings.Contains(text, "Exception") {
+			result.Method = s.name
+			break
+		}
+		lastErr = fmt.Errorf("%s: %v %s", s.name, err, text)
+		// The settings write before a refused broadcast still counts
+		if strings.HasPrefix(s.name, "settings") && strings.Contains(text, "SecurityException") {
+			result.Method = s.name
+			result.Warning = "the airplane mode broadcast was refused; some radios change only after they're next used"
+			break
+		}
+	}
+	if result.Method == "" {
+		return nil, fmt.Errorf("failed to set %s: %v", name, lastErr)
+	}
+
+	deadline := time.Now().Add(radioToggleTimeout)
+	for {
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings get global "+settingKey).Output()
+		if err == nil {
+			v := strings.TrimSpace(string(out))
+			result.Enabled = v != "" && v != "0" && v != "null"
+			if result.Enabled == on {
+				result.Verified = true
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if !result.Verified && result.Warning == "" {
+		result.Warning = fmt.Sprintf("%s still reads %t after %s", settingKey, result.Enabled, radioToggleTimeout)
+	}
+	a.Log("Set %s to %t on %s via %s (verified: %t)", name, on, deviceId, result.Method, result.Verified)
+	return result, nil
+}
@@ -0,0 +1,623 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// screenRecording is a recording in progress tracked in App.recordings
+type screenRecording struct {
+	info      RecordingInfo
+	opts      RecordOptions
+	startedAt time.Time
+	stop      func() error
+	done      chan struct{}
+}
+
+// recordingMeta is what we remember about a recording file beyond what the file itself tells us
+type recordingMeta struct {
+	DeviceID  string `json:"deviceId"`
+	Model     string `json:"model"`
+	Backend   string `json:"backend"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+var (
+	recordingIndex       map[string]recordingMeta
+	recordingIndexMu     sync.Mutex
+	recordingIndexLoaded bool
+
+	recordingNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// recordingExtensions are the container formats ListRecordings picks up
+var recordingExtensions = map[string]bool{".mp4": true, ".mkv": true}
+
+// GetRecordingsDir returns the folder new recordings are written to
+func (a *App) GetRecordingsDir() string {
+	a.recordingMu.Lock()
+	dir := a.recordingsDir
+	a.recordingMu.Unlock()
+	if dir != "" {
+		return dir
+	}
+
+	home, _ := os.UserHomeDir()
+	downloadsDir := filepath.Join(home, "Downloads")
+	if _, err := os.Stat(downloadsDir); err == nil {
+		return downloadsDir
+	}
+	return home
+}
+
+// SetRecordingsDir changes the folder new recordings are written to
+func (a *App) SetRecordingsDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("no directory specified")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create recordings folder: %w", err)
+	}
+	a.recordingMu.Lock()
+	a.recordingsDir = dir
+	a.recordingMu.Unlock()
+	go a.saveSettings()
+	return nil
+}
+
+// StartScreenRecording records the device screen into the recordings folder, using scrcpy
+// unless opts.Backend asks for adb screenrecord (or scrcpy cannot be launched)
+func (a *App) StartScreenRecording(deviceId string, opts RecordOptions) (*RecordingInfo, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	switch opts.Backend {
+	case "screenrecord":
+		return a.StartScreenrecord(deviceId, opts)
+	case "", "scrcpy":
+	default:
+		return nil, fmt.Errorf("unknown recording backend: %s", opts.Backend)
+	}
+
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = "mp4"
+	}
+	if format != "mp4" && format != "mkv" {
+		return nil, fmt.Errorf("unsupported recording format: %s", opts.Format)
+	}
+	opts.Format = format
+
+	recordPath, model, err := a.prepareRecording(deviceId, format)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"-s", deviceId, "--record=" + recordPath, "--record-format=" + format}
+	if opts.NoPlayback {
+		args = append(args, "--no-playback")
+	} else {
+		args = append(args, "--window-title", "ADB GUI - "+deviceId+" (recording)")
+	}
+	if opts.MaxDuration > 0 {
+		args = append(args, fmt.Sprintf("--time-limit=%d", opts.MaxDuration))
+	}
+	if opts.BitRate > 0 {
+		args = append(args, fmt.Sprintf("--video-bit-rate=%dM", opts.BitRate))
+	}
+	if opts.MaxSize > 0 {
+		args = append(args, fmt.Sprintf("--max-size=%d", opts.MaxSize))
+	}
+	if opts.VideoCodec != "" {
+		args = append(args, "--video-codec="+opts.VideoCodec)
+	}
+	if opts.NoAudio {
+		args = a.appendNoAudio(args)
+	}
+
+	cmd := a.newScrcpyCommand(args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// A process group of its own lets interruptProcess reach scrcpy with a console break on Windows
+	setProcessGroup(cmd)
+
+	a.Log("Starting screen recording: %s %v", a.scrcpyPath, cmd.Args)
+	if err := cmd.Start(); err != nil {
+		if opts.Backend == "" {
+			a.Log("scrcpy recording failed to start (%v), falling back to screenrecord", err)
+			return a.StartScreenrecord(deviceId, opts)
+		}
+		return nil, fmt.Errorf("failed to start recording: %w", err)
+	}
+
+	rec := &screenRecording{
+		info: RecordingInfo{
+			Path:      recordPath,
+			Name:      filepath.Base(recordPath),
+			DeviceID:  deviceId,
+			Model:     model,
+			Backend:   "scrcpy",
+			CreatedAt: time.Now().Unix(),
+		},
+		opts:      opts,
+		startedAt: time.Now(),
+		stop:      func() error { return interruptProcess(cmd.Process) },
+		done:      make(chan struct{}),
+	}
+
+	a.recordingMu.Lock()
+	a.recordings[deviceId] = rec
+	a.recordingMu.Unlock()
+	a.trackProcess("scrcpy-record", cmd, nil)
+
+	wailsRuntime.EventsEmit(a.ctx, "screen-recording-started", rec.info)
+
+	go a.watchRecording(rec)
+	go func() {
+		_ = cmd.Wait()
+		a.untrackProcess(cmd)
+		a.finishRecording(rec)
+	}()
+
+	info := rec.info
+	return &info, nil
+}
+
+// StopScreenRecording asks the recorder to finish so the file is finalized, then returns its details
+func (a *App) StopScreenRecording(deviceId string) (*RecordingInfo, error) {
+	a.recordingMu.Lock()
+	rec, ok := a.recordings[deviceId]
+	a.recordingMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("device %s is not being recorded", deviceId)
+	}
+
+	if err := rec.stop(); err != nil && !strings.Contains(err.Error(), "already finished") {
+		return nil, fmt.Errorf("failed to stop recording: %w", err)
+	}
+
+	// screenrecord segments still have to be pulled from the device, so allow for that
+	select {
+	case <-rec.done:
+	case <-time.After(2 * time.Minute):
+		return nil, fmt.Errorf("recording did not finish in time: %s", rec.info.Path)
+	}
+
+	info := rec.info
+	return &info, nil
+}
+
+// prepareRecording checks the device is free and picks the output path for a new recording
+func (a *App) prepareRecording(deviceId, ext string) (string, string, error) {
+	a.recordingMu.Lock()
+	_, busy := a.recordings[deviceId]
+	a.recordingMu.Unlock()
+	if busy {
+		return "", "", fmt.Errorf("device %s is already being recorded", deviceId)
+	}
+
+	dir := a.GetRecordingsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create recordings folder: %w", err)
+	}
+	model, _ := a.RunAdbCommand(deviceId, "shell getprop ro.product.model")
+	return filepath.Join(dir, recordingFileName(model, ext)), model, nil
+}
+
+// ListRecordings returns the recordings in the recordings folder, newest first
+func (a *App) ListRecordings() ([]RecordingInfo, error) {
+	dir := a.GetRecordingsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recordings folder: %w", err)
+	}
+
+	recordingIndexMu.Lock()
+	index := a.loadRecordingIndexLocked()
+	metas := make(map[string]recordingMeta, len(index))
+	for k, v := range index {
+		metas[k] = v
+	}
+	recordingIndexMu.Unlock()
+
+	a.recordingMu.Lock()
+	active := make(map[string]bool)
+	for _, rec := range a.recordings {
+		active[rec.info.Path] = true
+	}
+	a.recordingMu.Unlock()
+
+	result := []RecordingInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !recordingExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		p := filepath.Join(dir, entry.Name())
+		if active[p] {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		info := RecordingInfo{
+			Path:       p,
+			Name:       entry.Name(),
+			Size:       fi.Size(),
+			DurationMs: probeVideoDuration(p),
+			CreatedAt:  fi.ModTime().Unix(),
+		}
+		if meta, ok := metas[p]; ok {
+			info.DeviceID = meta.DeviceID
+			info.Model = meta.Model
+			info.Backend = meta.Backend
+			info.CreatedAt = meta.CreatedAt
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt > result[j].CreatedAt })
+	return result, nil
+}
+
+// watchRecording emits the elapsed time every second and enforces the file size limit
+func (a *App) watchRecording(rec *screenRecording) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rec.done:
+			return
+		case <-ticker.C:
+			var size int64
+			if fi, err := os.Stat(rec.info.Path); err == nil {
+				size = fi.Size()
+			}
+			wailsRuntime.EventsEmit(a.ctx, "screen-recording-elapsed", map[string]interface{}{
+				"deviceId": rec.info.DeviceID,
+				"path":     rec.info.Path,
+				"elapsed":  int64(time.Since(rec.startedAt).Seconds()),
+				"size":     size,
+			})
+			if rec.opts.MaxFileSize > 0 && size >= rec.opts.MaxFileSize {
+				a.Log("Recording %s reached its size limit, stopping", rec.info.Path)
+				_ = rec.stop()
+			}
+		}
+	}
+}
+
+// finishRecording drops a finished recording from the registry, indexes the file and reports it
+func (a *App) finishRecording(rec *screenRecording) {
+	if fi, err := os.Stat(rec.info.Path); err == nil {
+		rec.info.Size = fi.Size()
+	}
+	rec.info.DurationMs = probeVideoDuration(rec.info.Path)
+	if rec.info.DurationMs == 0 {
+		rec.info.DurationMs = time.Since(rec.startedAt).Milliseconds()
+	}
+
+	a.recordingMu.Lock()
+	if a.recordings[rec.info.DeviceID] == rec {
+		delete(a.recordings, rec.info.DeviceID)
+	}
+	a.recordingMu.Unlock()
+	close(rec.done)
+
+	if rec.info.Size > 0 {
+		a.saveRecordingMeta(rec.info)
+	}
+	wailsRuntime.EventsEmit(a.ctx, "screen-recording-stopped", rec.info)
+}
+
+// stopAllScreenRecordings finalizes every recording in progress; used on shutdown
+func (a *App) stopAllScreenRecordings() {
+	a.recordingMu.Lock()
+	var active []*screenRecording
+	for _, rec := range a.recordings {
+		active = append(active, rec)
+	}
+	a.recordingMu.Unlock()
+
+	for _, rec := range active {
+		_ = rec.stop()
+	}
+	for _, rec := range active {
+		select {
+		case <-rec.done:
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func recordingFileName(model, ext string) string {
+	cleanModel := "Device"
+	if model != "" {
+		cleanModel = recordingNameRegex.ReplaceAllString(strings.ReplaceAll(model, " ", "_"), "")
+	}
+	return fmt.Sprintf("Gaze_%s_%s.%s", cleanModel, time.Now().Format("20060102_150405"), ext)
+}
+
+func (a *App) getRecordingIndexPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "recordings.json")
+}
+
+// loadRecordingIndexLocked reads recordings.json once; callers must hold recordingIndexMu
+func (a *App) loadRecordingIndexLocked() map[string]recordingMeta {
+	if recordingIndexLoaded {
+		return recordingIndex
+	}
+	recordingIndexLoaded = true
+	recordingIndex = make(map[string]recordingMeta)
+
+	data, err := os.ReadFile(a.getRecordingIndexPath())
+	if err != nil {
+		return recordingIndex
+	}
+	var stored map[string]recordingMeta
+	if err := json.Unmarshal(data, &stored); err == nil && stored != nil {
+		recordingIndex = stored
+	}
+	return recordingIndex
+}
+
+// saveRecordingMeta remembers which device produced a recording, pruning entries whose files are gone
+func (a *App) saveRecordingMeta(info RecordingInfo) {
+	recordingIndexMu.Lock()
+	defer recordingIndexMu.Unlock()
+
+	index := a.loadRecordingIndexLocked()
+	index[info.Path] = recordingMeta{
+		DeviceID:  info.DeviceID,
+		Model:     info.Model,
+		Backend:   info.Backend,
+		CreatedAt: info.CreatedAt,
+	}
+	for p := range index {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			delete(index, p)
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(a.getRecordingIndexPath(), data, 0644)
+}
+
+// probeVideoDuration reads the duration from an mp4 or mkv header, returning 0 when unknown
+func probeVideoDuration(p string) int64 {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".mp4":
+		return mp4Duration(f)
+	case ".mkv":
+		return mkvDuration(f)
+	}
+	return 0
+}
+
+// mp4Duration finds moov/mvhd and converts its duration to milliseconds
+func mp4Duration(f io.ReadSeeker) int64 {
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0
+	}
+	pos := int64(0)
+	for pos+8 <= end {
+		if _, err := f.Seek(pos, io.SeekStart); err != nil {
+			return 0
+		}
+		var header [8]byte
+		if _, err := io.ReadFull(f, header[:]); err != nil {
+			return 0
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:])
+		headerLen := int64(8)
+		switch size {
+		case 0:
+			size = end - pos
+		case 1:
+			var large [8]byte
+			if _, err := io.ReadFull(f, large[:]); err != nil {
+				return 0
+			}
+			size = int64(binary.BigEndian.Uint64(large[:]))
+			headerLen = 16
+		}
+		if size < headerLen {
+			return 0
+		}
+
+		switch boxType {
+		case "moov":
+			// Descend: the next box starts right after this header
+			pos += headerLen
+			end = pos - headerLen + size
+			continue
+		case "mvhd":
+			var version [4]byte
+			if _, err := io.ReadFull(f, version[:]); err != nil {
+				return 0
+			}
+			var timescale, duration uint64
+			if version[0] == 1 {
+				var buf [28]byte
+				if _, err := io.ReadFull(f, buf[:]); err != nil {
+					return 0
+				}
+				timescale = uint64(binary.BigEndian.Uint32(buf[16:20]))
+				duration = binary.BigEndian.Uint64(buf[20:28])
+			} else {
+				var buf [16]byte
+				if _, err := io.ReadFull(f, buf[:]); err != nil {
+					return 0
+				}
+				timescale = uint64(binary.BigEndian.Uint32(buf[8:12]))
+				duration = uint64(binary.BigEndian.Uint32(buf[12:16]))
+			}
+			if timescale == 0 {
+				return 0
+			}
+			return int64(duration * 1000 / timescale)
+		}
+		pos += size
+	}
+	return 0
+}
+
+// Matroska element IDs needed to locate Segment/Info/Duration
+const (
+	ebmlIDSegment       = 0x18538067
+	ebmlIDInfo          = 0x1549A966
+	ebmlIDCluster       = 0x1F43B675
+	ebmlIDTimecodeScale = 0x2AD7B1
+	ebmlIDDuration      = 0x4489
+)
+
+// mkvDuration reads Segment/Info/Duration (scaled by TimecodeScale) in milliseconds
+func mkvDuration(f io.ReadSeeker) int64 {
+	// Walk top-level elements until the Segment, then its children until Info
+	for {
+		id, size, err := readEBMLHeader(f)
+		if err != nil {
+			return 0
+		}
+		if id == ebmlIDSegment {
+			break
+		}
+		if _, err := f.Seek(size, io.SeekCurrent); err != nil {
+			return 0
+		}
+	}
+	for {
+		id, size, err := readEBMLHeader(f)
+		if err != nil || id == ebmlIDCluster {
+			return 0
+		}
+		if id == ebmlIDInfo {
+			return mkvInfoDuration(f, size)
+		}
+		if size < 0 {
+			return 0
+		}
+		if _, err := f.Seek(size, io.SeekCurrent); err != nil {
+			return 0
+		}
+	}
+}
+
+func mkvInfoDuration(f io.ReadSeeker, infoSize int64) int64 {
+	timecodeScale := uint64(1000000)
+	duration := -1.0
+
+	var read int64
+	for infoSize < 0 || read < infoSize {
+		start, _ := f.Seek(0, io.SeekCurrent)
+		id, size, err := readEBMLHeader(f)
+		if err != nil || size < 0 || size > 8 && (id == ebmlIDTimecodeScale || id == ebmlIDDuration) {
+			break
+		}
+		switch id {
+		case ebmlIDTimecodeScale, ebmlIDDuration:
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(f, buf); err != nil {
+				return 0
+			}
+			if id == ebmlIDTimecodeScale {
+				var v uint64
+				for _, b := range buf {
+					v = v<<8 | uint64(b)
+				}
+				timecodeScale = v
+			} else if size == 4 {
+				duration = float64(math.Float32frombits(binary.BigEndian.Uint32(buf)))
+			} else if size == 8 {
+				duration = math.Float64frombits(binary.BigEndian.Uint64(buf))
+			}
+		default:
+			if _, err := f.Seek(size, io.SeekCurrent); err != nil {
+				return 0
+			}
+		}
+		end, _ := f.Seek(0, io.SeekCurrent)
+		read += end - start
+	}
+
+	if duration < 0 {
+		return 0
+	}
+	return int64(duration * float64(timecodeScale) / 1e6)
+}
+
+// readEBMLHeader reads an element ID (with marker bits) and its data size; size is -1 when unknown
+func readEBMLHeader(r io.Reader) (uint64, int64, error) {
+	id, _, err := readEBMLVint(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	raw, length, err := readEBMLVint(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	value := raw &^ (uint64(1) << (uint(length) * 7))
+	if value == (uint64(1)<<(uint(length)*7))-1 {
+		return id, -1, nil
+	}
+	return id, int64(value), nil
+}
+
+// readEBMLVint reads a variable-length integer, keeping the length marker bit in the result
+func readEBMLVint(r io.Reader) (uint64, int, error) {
+	var first [1]byte
+	if _, err := io.ReadFull(r, first[:]); err != nil {
+		return 0, 0, err
+	}
+	length := 1
+	for mask := byte(0x80); length <= 8 && first[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 {
+		return 0, 0, fmt.Errorf("invalid EBML vint")
+	}
+	value := uint64(first[0])
+	if length > 1 {
+		rest := make([]byte, length-1)
+		if _, err := io.ReadFull(r, rest); err != nil {
+			return 0, 0, err
+		}
+		for _, b := range rest {
+			value = value<<8 | uint64(b)
+		}
+	}
+	return value, length, nil
+}
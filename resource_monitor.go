@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// resourceHistoryWindow is how far back GetResourceHistory reaches
+	resourceHistoryWindow = time.Hour
+	// resourceTopN is how many processes each sample lists by CPU and by memory
+	resourceTopN = 10
+	// resourceMaxFailures stops a monitor whose device stopped answering
+	resourceMaxFailures = 3
+
+	resourceSectionMarker = "__GAZE_SECTION__"
+)
+
+// resourceMonitor polls one device
+type resourceMonitor struct {
+	cancel   context.CancelFunc
+	interval time.Duration
+}
+
+// cpuTimes is one cpu line of /proc/stat
+type cpuTimes struct {
+	busy, total uint64
+}
+
+var (
+	resourceMonitors = make(map[string]*resourceMonitor)
+	resourceHistory  = make(map[string][]ResourceSample)
+	resourceMu       sync.Mutex
+)
+
+// StartResourceMonitor samples CPU, memory and the busiest processes every intervalSec
+// seconds, emitting resource-sample events and keeping the last hour for GetResourceHistory.
+// Starting it again changes the interval.
+func (a *App) StartResourceMonitor(deviceId string, intervalSec int) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if intervalSec <= 0 {
+		intervalSec = 2
+	}
+	if intervalSec > 60 {
+		intervalSec = 60
+	}
+
+	a.StopResourceMonitor(deviceId)
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &resourceMonitor{cancel: cancel, interval: time.Duration(intervalSec) * time.Second}
+	resourceMu.Lock()
+	resourceMonitors[deviceId] = m
+	resourceMu.Unlock()
+
+	go a.runResourceMonitor(ctx, deviceId, m)
+	return nil
+}
+
+// StopResourceMonitor stops polling the device; its history is kept
+func (a *App) StopResourceMonitor(deviceId string) {
+	resourceMu.Lock()
+	m := resourceMonitors[deviceId]
+	delete(resourceMonitors, deviceId)
+	resourceMu.Unlock()
+	if m != nil {
+		m.cancel()
+	}
+}
+
+// GetResourceHistory returns the samples taken in the last hour, oldest first
+func (a *App) GetResourceHistory(deviceId string) []ResourceSample {
+	resourceMu.Lock()
+	defer resourceMu.Unlock()
+	return append([]ResourceSample{}, resourceHistory[deviceId]...)
+}
+
+// stopAllResourceMonitors is called on shutdown
+func (a *App) stopAllResourceMonitors() {
+	resourceMu.Lock()
+	monitors := resourceMonitors
+	resourceMonitors = make(map[string]*resourceMonitor)
+	resourceMu.Unlock()
+	for _, m := range monitors {
+		m.cancel()
+	}
+}
+
+// onlineDeviceIDs returns every adb ID of the devices that are ready for commands
+func onlineDeviceIDs(devices []Device) map[string]bool {
+	online := make(map[string]bool)
+	for _, d := range devices {
+		if d.State != "device" {
+			continue
+		}
+		online[d.ID] = true
+		for _, id := range d.IDs {
+			online[id] = true
+		}
+	}
+	return online
+}
+
+// stopResourceMonitorsForOffline stops monitors whose device is no longer listed as online
+func (a *App) stopResourceMonitorsForOffline(devices []Device) {
+	online := onlineDeviceIDs(devices)
+
+	resourceMu.Lock()
+	var gone []string
+	for id := range resourceMonitors {
+		if !online[id] {
+			gone = append(gone, id)
+		}
+	}
+	resourceMu.Unlock()
+	for _, id := range gone {
+		a.StopResourceMonitor(id)
+		wailsRuntime.EventsEmit(a.ctx, "resource-monitor-stopped", map[string]interface{}{
+			"deviceId": id,
+			"reason":   "device disconnected",
+		})
+	}
+}
+
+func (a *App) runResourceMonitor(ctx context.Context, deviceId string, m *resourceMonitor) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	var prevCPU []cpuTimes
+	failures := 0
+	for {
+		sample, cpu, err := a.sampleResources(ctx, deviceId, prevCPU)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failures++
+			if failures >= resourceMaxFailures {
+				resourceMu.Lock()
+				if resourceMonitors[deviceId] == m {
+					delete(resourceMonitors, deviceId)
+				}
+				resourceMu.Unlock()
+				m.cancel()
+				a.Log("Resource monitor for %s stopped: %v", deviceId, err)
+				wailsRuntime.EventsEmit(a.ctx, "resource-monitor-stopped", map[string]interface{}{
+					"deviceId": deviceId,
+					"reason":   err.Error(),
+				})
+				return
+			}
+		} else {
+			failures = 0
+			prevCPU = cpu
+			a.recordResourceSample(sample)
+			wailsRuntime.EventsEmit(a.ctx, "resource-sample", sample)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordResourceSample appends to the device's history and drops what has aged out
+func (a *App) recordResourceSample(sample ResourceSample) {
+	cutoff := time.Now().Add(-resourceHistoryWindow).UnixMilli()
+	resourceMu.Lock()
+	defer resourceMu.Unlock()
+	history := append(resourceHistory[sample.DeviceID], sample)
+	drop := 0
+	for drop < len(history) && history[drop].Timestamp < cutoff {
+		drop++
+	}
+	resourceHistory[sample.DeviceID] = history[drop:]
+}
+
+// sampleResources reads /proc/stat, /proc/meminfo and top in one round trip. CPU load comes
+// from the /proc/stat delta against prev; on the first sample, or where /proc/stat is
+// unreadable, top's summary line is used instead.
+func (a *App) sampleResources(ctx context.Context, deviceId string, prev []cpuTimes) (ResourceSample, []cpuTimes, error) {
+	sample := ResourceSample{DeviceID: deviceId, Timestamp: time.Now().UnixMilli()}
+
+	// toybox top needs -b to skip the terminal UI; toolbox top rejects it
+	script := "cat /proc/stat; echo " + resourceSectionMarker + "; cat /proc/meminfo; echo " + resourceSectionMarker +
+		"; top -b -n 1 2>/dev/null || top -n 1"
+	cmdCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(cmdCtx, "-s", deviceId, "shell", script).Output()
+	if err != nil && len(out) == 0 {
+		return sample, nil, fmt.Errorf("failed to sample resources: %w", err)
+	}
+	sections := strings.SplitN(strings.ReplaceAll(string(out), "\r\n", "\n"), resourceSectionMarker+"\n", 3)
+	if len(sections) < 3 {
+		return sample, nil, fmt.Errorf("unexpected resource output")
+	}
+
+	cpu := parseProcStat(sections[0])
+	if len(prev) == len(cpu) && len(cpu) > 0 {
+		sample.CPUPercent = cpuLoad(prev[0], cpu[0])
+		for i := 1; i < len(cpu); i++ {
+			sample.CoreLoads = append(sample.CoreLoads, cpuLoad(prev[i], cpu[i]))
+		}
+	}
+	if len(cpu) > 1 {
+		sample.CoreCount = len(cpu) - 1
+	}
+
+	mem := parseMeminfo(sections[1])
+	sample.MemTotal = mem["MemTotal"]
+	sample.MemFree = mem["MemFree"]
+	sample.MemAvailable = mem["MemAvailable"]
+	if sample.MemAvailable == 0 {
+		// Kernels before 3.14 have no MemAvailable
+		sample.MemAvailable = mem["MemFree"] + mem["Buffers"] + mem["Cached"]
+	}
+	sample.MemUsed = sample.MemTotal - sample.MemAvailable
+
+	topCPU, procs := parseTopOutput(sections[2])
+	if sample.CoreLoads == nil {
+		sample.CPUPercent = topCPU
+	}
+	sort.SliceStable(procs, func(i, j int) bool { return procs[i].CPU > procs[j].CPU })
+	sample.TopByCPU = append([]ProcessUsage{}, procs[:min(resourceTopN, len(procs))]...)
+	sort.SliceStable(procs, func(i, j int) bool { return procs[i].RSS > procs[j].RSS })
+	sample.TopByMemory = append([]ProcessUsage{}, procs[:min(resourceTopN, len(procs))]...)
+
+	if sample.MemTotal == 0 && len(procs) == 0 {
+		return sample, cpu, fmt.Errorf("no resource data from device")
+	}
+	return sample, cpu, nil
+}
+
+// parseProcStat returns the aggregate cpu line followed by one entry per core
+func parseProcStat(text string) []cpuTimes {
+	var result []cpuTimes
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		var t cpuTimes
+		for i, f := range fields[1:] {
+			v, _ := strconv.ParseUint(f, 10, 64)
+			t.total += v
+			// idle and iowait are the 4th and 5th columns
+			if i != 3 && i != 4 {
+				t.busy += v
+			}
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func cpuLoad(prev, cur cpuTimes) float64 {
+	total := cur.total - prev.total
+	if cur.total <= prev.total || cur.busy < prev.busy {
+		return 0
+	}
+	return roundTenth(float64(cur.busy-prev.busy) * 100 / float64(total))
+}
+
+// parseMeminfo returns /proc/meminfo values in bytes
+func parseMeminfo(text string) map[string]int64 {
+	result := make(map[string]int64)
+	for _, line := range strings.Split(text, "\n") {
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && strings.EqualFold(fields[1], "kB") {
+			v *= 1024
+		}
+		result[strings.TrimSpace(key)] = v
+	}
+	return result
+}
+
+// parseTopOutput reads the overall CPU load (0-100) and the process table from toybox or
+// toolbox top. Process CPU is as top reports it: toybox counts 100% per core, toolbox
+// 100% for the whole device.
+func parseTopOutput(text string) (float64, []ProcessUsage) {
+	var totalCPU float64
+	var procs []ProcessUsage
+	var header []string
+	pidCol, cpuCol, memCol, nameCol := -1, -1, -1, -1
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if header == nil {
+			lower := strings.ToLower(trimmed)
+			switch {
+			case strings.Contains(lower, "%cpu") && strings.Contains(lower, "%idle"):
+				// toybox: "800%cpu  12%user   0%nice  16%sys 772%idle ..."
+				var capacity, idle float64
+				for _, f := range strings.Fields(lower) {
+					if v, ok := strings.CutSuffix(f, "%cpu"); ok {
+						capacity, _ = strconv.ParseFloat(v, 64)
+					} else if v, ok := strings.CutSuffix(f, "%idle"); ok {
+						idle, _ = strconv.ParseFloat(v, 64)
+					}
+				}
+				if capacity > 0 {
+					totalCPU = roundTenth((capacity - idle) * 100 / capacity)
+				}
+				continue
+			case strings.HasPrefix(lower, "user ") && strings.Contains(lower, "system"):
+				// toolbox: "User 5%, System 3%, IOW 0%, IRQ 0%"
+				totalCPU = 0
+				for _, part := range strings.Split(trimmed, ",") {
+					fields := strings.Fields(part)
+					if len(fields) == 2 {
+						v, _ := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+						totalCPU += v
+					}
+				}
+				continue
+			case strings.HasPrefix(trimmed, "PID "):
+				// toybox glues the state and CPU columns together as "S[%CPU]"
+				header = strings.Fields(strings.NewReplacer("[", " ", "]", " ").Replace(trimmed))
+				for i, name := range header {
+					switch strings.ToUpper(name) {
+					case "PID":
+						pidCol = i
+					case "%CPU", "CPU%":
+						cpuCol = i
+					case "RES", "RSS":
+						memCol = i
+					case "ARGS", "NAME", "CMD", "COMMAND":
+						nameCol = i
+					}
+				}
+				if nameCol < 0 {
+					nameCol = len(header) - 1
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		if pidCol < 0 || len(fields) <= pidCol {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[pidCol])
+		if err != nil {
+			continue
+		}
+		p := ProcessUsage{PID: pid}
+		if cpuCol >= 0 && cpuCol < len(fields) {
+			p.CPU, _ = strconv.ParseFloat(strings.TrimSuffix(fields[cpuCol], "%"), 64)
+		}
+		if memCol >= 0 && memCol < len(fields) {
+			p.RSS = parseTopSize(fields[memCol])
+		}
+		if len(fields) >= len(header) && nameCol < len(fields) {
+			p.Name = strings.Join(fields[nameCol:], " ")
+		} else {
+			// toolbox leaves the PCY column empty for some processes
+			p.Name = fields[len(fields)-1]
+		}
+		procs = append(procs, p)
+	}
+	return totalCPU, procs
+}
+
+// parseTopSize converts top's memory columns ("980K", "4.2M", "1.1G", "20000K") to bytes
+func parseTopSize(s string) int64 {
+	mult := float64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K', 'k':
+			mult = 1 << 10
+		case 'M', 'm':
+			mult = 1 << 20
+		case 'G', 'g':
+			mult = 1 << 30
+		case 'T', 't':
+			mult = 1 << 40
+		}
+		if mult > 1 {
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(v * mult)
+}
+
+func roundTenth(v float64) float64 {
+	return float64(int64(v*10+0.5)) / 10
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,24 +11,56 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// StartScrcpy starts scrcpy for the given device with custom configuration
-func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
+var (
+	scrcpyCropRegex       = regexp.MustCompile(`^\d+:\d+:\d+:\d+$`)
+	scrcpyCameraRegex     = regexp.MustCompile(`--camera-id=(\S+)\s+\((\w+),\s*(\d+x\d+)(?:,\s*fps=\[([^\]]*)\])?`)
+	scrcpyCameraSizeRegex = regexp.MustCompile(`^\s+-\s+(\d+x\d+)`)
+	scrcpyDisplayRegex    = regexp.MustCompile(`--display-id=(\d+)\s+\((\d+x\d+)\)`)
+	scrcpyVersionRegex    = regexp.MustCompile(`scrcpy v?(\d+)\.\d+`)
+	scrcpySessionSeq      int64
+
+	scrcpyVersionOnce sync.Once
+	scrcpyMajor       int
+)
+
+// ErrScrcpyUnsupported is returned when the device is too old for the requested scrcpy feature
+var ErrScrcpyUnsupported = errors.New("not supported on this device")
+
+// scrcpyProcess is a running mirror window tracked in App.scrcpySessions
+type scrcpyProcess struct {
+	ScrcpySession
+	cmd     *exec.Cmd
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+// StartScrcpy starts scrcpy for the given device with custom configuration. Options the device
+// cannot honor, such as audio before Android 11, are dropped and reported in Warnings.
+func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) (*ScrcpySession, error) {
 	a.updateLastActive(deviceId)
 	if deviceId == "" {
-		return fmt.Errorf("no device specified")
+		return nil, fmt.Errorf("no device specified")
 	}
 
 	a.scrcpyMu.Lock()
-	if cmd, exists := a.scrcpyCmds[deviceId]; exists && cmd.Process != nil {
-		_ = cmd.Process.Kill()
-	}
+	running := len(a.scrcpySessions[deviceId])
 	a.scrcpyMu.Unlock()
+	if running > 0 && !config.AllowMultiple {
+		return nil, fmt.Errorf("scrcpy is already mirroring %s; stop it first or enable allowMultiple", deviceId)
+	}
+	if err := a.checkScrcpyCapabilities(deviceId, config); err != nil {
+		return nil, err
+	}
 
 	args := []string{"-s", deviceId}
 
@@ -52,10 +85,30 @@ func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
 		args = append(args, "--video-codec", config.VideoCodec)
 	}
 
-	if config.NoAudio {
-		args = append(args, "--no-audio")
-	} else if config.AudioCodec != "" {
-		args = append(args, "--audio-codec", config.AudioCodec)
+	// Camera capture never carries audio
+	var warnings []string
+	audioEnabled := !config.NoAudio && !isCamera
+	if audioEnabled {
+		if reason := a.audioUnsupportedReason(deviceId); reason != "" {
+			warnings = append(warnings, reason)
+			audioEnabled = false
+		}
+	}
+	if !audioEnabled {
+		args = a.appendNoAudio(args)
+	} else {
+		if config.AudioCodec != "" {
+			args = append(args, "--audio-codec", config.AudioCodec)
+		}
+		if config.AudioSource != "" && config.AudioSource != "output" {
+			args = append(args, "--audio-source", config.AudioSource)
+		}
+		if config.AudioBitRate > 0 {
+			args = append(args, "--audio-bit-rate", fmt.Sprintf("%dK", config.AudioBitRate))
+		}
+		if config.AudioBuffer > 0 {
+			args = append(args, "--audio-buffer", fmt.Sprintf("%d", config.AudioBuffer))
+		}
 	}
 
 	if config.AlwaysOnTop {
@@ -84,6 +137,8 @@ func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
 		args = append(args, "--video-source", "camera")
 		if config.CameraId != "" {
 			args = append(args, "--camera-id", config.CameraId)
+		} else if config.CameraFacing != "" {
+			args = append(args, "--camera-facing", config.CameraFacing)
 		}
 		if config.CameraSize != "" {
 			args = append(args, "--camera-size", config.CameraSize)
@@ -92,12 +147,40 @@ func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
 		if config.VideoSource == "display" {
 			args = append(args, "--video-source", "display")
 		}
-		if config.DisplayId > 0 {
+		if config.NewDisplay {
+			// --new-display[=WIDTHxHEIGHT][/DPI]
+			spec := config.NewDisplaySize
+			if config.NewDisplayDpi > 0 {
+				spec += fmt.Sprintf("/%d", config.NewDisplayDpi)
+			}
+			if spec != "" {
+				args = append(args, "--new-display="+spec)
+			} else {
+				args = append(args, "--new-display")
+			}
+		} else if config.DisplayId > 0 {
 			args = append(args, "--display-id", fmt.Sprintf("%d", config.DisplayId))
 		}
 	}
+	if config.StartApp != "" {
+		args = append(args, "--start-app="+config.StartApp)
+	}
 	if config.DisplayOrientation != "" && config.DisplayOrientation != "0" {
 		args = append(args, "--display-orientation", config.DisplayOrientation)
+	} else if config.Rotation != 0 {
+		// scrcpy 3 replaced --rotation with --display-orientation
+		switch config.Rotation {
+		case 90, 180, 270:
+			args = append(args, "--display-orientation", fmt.Sprintf("%d", config.Rotation))
+		default:
+			return nil, fmt.Errorf("invalid rotation %d: must be 0, 90, 180 or 270", config.Rotation)
+		}
+	}
+	if config.Crop != "" {
+		if !scrcpyCropRegex.MatchString(config.Crop) {
+			return nil, fmt.Errorf("invalid crop %q: expected width:height:x:y", config.Crop)
+		}
+		args = append(args, "--crop", config.Crop)
 	}
 	if config.CaptureOrientation != "" && config.CaptureOrientation != "0" {
 		args = append(args, "--capture-orientation", config.CaptureOrientation)
@@ -118,20 +201,11 @@ func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
 		args = append(args, "--no-power-on")
 	}
 
-	if config.VideoSource == "camera" {
-		foundNoAudio := false
-		for _, arg := range args {
-			if arg == "--no-audio" {
-				foundNoAudio = true
-				break
-			}
-		}
-		if !foundNoAudio {
-			args = append(args, "--no-audio")
-		}
+	windowTitle := "ADB GUI - " + deviceId
+	if running > 0 {
+		windowTitle = fmt.Sprintf("%s (%d)", windowTitle, running+1)
 	}
-
-	args = append(args, "--window-title", "ADB GUI - "+deviceId)
+	args = append(args, "--window-title", windowTitle)
 
 	cmd := a.newScrcpyCommand(args...)
 
@@ -142,62 +216,156 @@ func (a *App) StartScrcpy(deviceId string, config ScrcpyConfig) error {
 	a.Log("Starting scrcpy: %s %v", a.scrcpyPath, cmd.Args)
 
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start scrcpy: %w", err)
+		return nil, fmt.Errorf("failed to start scrcpy: %w", err)
+	}
+
+	startTime := time.Now()
+	proc := &scrcpyProcess{
+		ScrcpySession: ScrcpySession{
+			SessionID: fmt.Sprintf("scrcpy-%d", atomic.AddInt64(&scrcpySessionSeq, 1)),
+			DeviceID:  deviceId,
+			PID:       cmd.Process.Pid,
+			StartTime: startTime.Unix(),
+			Config:    config,
+			Warnings:  warnings,
+		},
+		cmd:  cmd,
+		done: make(chan struct{}),
 	}
 
 	a.scrcpyMu.Lock()
-	a.scrcpyCmds[deviceId] = cmd
+	a.scrcpySessions[deviceId] = append(a.scrcpySessions[deviceId], proc)
 	a.scrcpyMu.Unlock()
-
-	startTime := time.Now()
+	a.trackProcess("scrcpy", cmd, nil)
 
 	wailsRuntime.EventsEmit(a.ctx, "scrcpy-started", map[string]interface{}{
 		"deviceId":  deviceId,
+		"sessionId": proc.SessionID,
+		"pid":       proc.PID,
 		"startTime": startTime.Unix(),
+		"warnings":  warnings,
 	})
 
-	go func() {
-		err := cmd.Wait()
-		duration := time.Since(startTime)
+	go a.waitScrcpy(proc, &stderrBuf)
 
-		a.scrcpyMu.Lock()
-		defer a.scrcpyMu.Unlock()
+	session := proc.ScrcpySession
+	return &session, nil
+}
 
-		if a.scrcpyCmds[deviceId] == cmd {
-			delete(a.scrcpyCmds, deviceId)
+// waitScrcpy reaps a mirror process, drops it from the registry and reports how it ended
+func (a *App) waitScrcpy(proc *scrcpyProcess, stderrBuf *bytes.Buffer) {
+	err := proc.cmd.Wait()
+	a.untrackProcess(proc.cmd)
+	close(proc.done)
+	duration := time.Since(time.Unix(proc.StartTime, 0))
+	deviceId := proc.DeviceID
 
-			if err != nil && duration < 5*time.Second {
-				errorMsg := stderrBuf.String()
-				if errorMsg == "" {
-					errorMsg = err.Error()
-				}
-				a.Log("Scrcpy failed quickly (%v): %s", duration, errorMsg)
-				wailsRuntime.EventsEmit(a.ctx, "scrcpy-failed", map[string]interface{}{
-					"deviceId": deviceId,
-					"error":    errorMsg,
-				})
-			} else {
-				wailsRuntime.EventsEmit(a.ctx, "scrcpy-stopped", deviceId)
-			}
+	a.scrcpyMu.Lock()
+	sessions := a.scrcpySessions[deviceId]
+	for i, s := range sessions {
+		if s == proc {
+			sessions = append(sessions[:i], sessions[i+1:]...)
+			break
 		}
-	}()
+	}
+	if len(sessions) == 0 {
+		delete(a.scrcpySessions, deviceId)
+	} else {
+		a.scrcpySessions[deviceId] = sessions
+	}
+	remaining := len(sessions)
+	a.scrcpyMu.Unlock()
 
-	return nil
+	exitCode := 0
+	errorMsg := ""
+	if err != nil {
+		exitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		}
+		errorMsg = strings.TrimSpace(stderrBuf.String())
+		if errorMsg == "" {
+			errorMsg = err.Error()
+		}
+	}
+
+	// scrcpy's device-side cleanup restores the panel when we stop it; after a crash it stays dark
+	cfg := proc.Config
+	if err != nil && !proc.stopped.Load() && cfg.TurnScreenOff && !cfg.PowerOffOnClose && cfg.VideoSource != "camera" {
+		go a.restoreDisplayPower(deviceId)
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "scrcpy-exited", map[string]interface{}{
+		"deviceId":   deviceId,
+		"sessionId":  proc.SessionID,
+		"exitCode":   exitCode,
+		"durationMs": duration.Milliseconds(),
+		"stopped":    proc.stopped.Load(),
+		"error":      errorMsg,
+	})
+
+	if err != nil && !proc.stopped.Load() && duration < 5*time.Second {
+		a.Log("Scrcpy failed quickly (%v): %s", duration, errorMsg)
+		wailsRuntime.EventsEmit(a.ctx, "scrcpy-failed", map[string]interface{}{
+			"deviceId": deviceId,
+			"error":    errorMsg,
+		})
+	} else if remaining == 0 {
+		wailsRuntime.EventsEmit(a.ctx, "scrcpy-stopped", deviceId)
+	}
+}
+
+// restoreDisplayPower relights a panel that scrcpy --turn-screen-off left off. The power manager
+// still considers such a device awake, so a sleep/wake cycle is needed to reset the display mode.
+func (a *App) restoreDisplayPower(deviceId string) {
+	a.Log("Restoring display power on %s after scrcpy exited", deviceId)
+	script := "input keyevent 223; sleep 0.5; input keyevent 224"
+	if output, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput(); err != nil {
+		a.Log("Failed to restore display power on %s: %v, %s", deviceId, err, string(output))
+	}
 }
 
-// StopScrcpy stops scrcpy for the given device
+// StopScrcpy stops every mirror window for the given device and waits briefly for them to exit
 func (a *App) StopScrcpy(deviceId string) error {
+	a.scrcpyMu.Lock()
+	sessions := append([]*scrcpyProcess(nil), a.scrcpySessions[deviceId]...)
+	a.scrcpyMu.Unlock()
+
+	var firstErr error
+	for _, s := range sessions {
+		s.stopped.Store(true)
+		err := s.cmd.Process.Kill()
+		if err != nil && !strings.Contains(err.Error(), "already finished") && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, s := range sessions {
+		select {
+		case <-s.done:
+		case <-time.After(3 * time.Second):
+		}
+	}
+	return firstErr
+}
+
+// ListScrcpySessions returns all running mirror windows, oldest first
+func (a *App) ListScrcpySessions() []ScrcpySession {
 	a.scrcpyMu.Lock()
 	defer a.scrcpyMu.Unlock()
 
-	if cmd, exists := a.scrcpyCmds[deviceId]; exists && cmd.Process != nil {
-		err := cmd.Process.Kill()
-		if err != nil && (strings.Contains(err.Error(), "process already finished") || strings.Contains(err.Error(), "already finished")) {
-			return nil
+	result := []ScrcpySession{}
+	for _, sessions := range a.scrcpySessions {
+		for _, s := range sessions {
+			result = append(result, s.ScrcpySession)
 		}
-		return err
 	}
-	return nil
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].StartTime != result[j].StartTime {
+			return result[i].StartTime < result[j].StartTime
+		}
+		return result[i].SessionID < result[j].SessionID
+	})
+	return result
 }
 
 // StartRecording starts a separate scrcpy process just for recording without a window
@@ -233,7 +401,7 @@ func (a *App) StartRecording(deviceId string, config ScrcpyConfig) error {
 	}
 
 	if config.NoAudio {
-		args = append(args, "--no-audio")
+		args = a.appendNoAudio(args)
 	} else if config.AudioCodec != "" {
 		args = append(args, "--audio-codec", config.AudioCodec)
 	}
@@ -269,6 +437,7 @@ func (a *App) StartRecording(deviceId string, config ScrcpyConfig) error {
 	a.scrcpyMu.Lock()
 	a.scrcpyRecordCmd[deviceId] = cmd
 	a.scrcpyMu.Unlock()
+	a.trackProcess("scrcpy-record", cmd, nil)
 
 	wailsRuntime.EventsEmit(a.ctx, "scrcpy-record-started", map[string]interface{}{
 		"deviceId":   deviceId,
@@ -278,6 +447,7 @@ func (a *App) StartRecording(deviceId string, config ScrcpyConfig) error {
 
 	go func() {
 		_ = cmd.Wait()
+		a.untrackProcess(cmd)
 		a.scrcpyMu.Lock()
 		delete(a.scrcpyRecordCmd, deviceId)
 		a.scrcpyMu.Unlock()
@@ -289,6 +459,14 @@ func (a *App) StartRecording(deviceId string, config ScrcpyConfig) error {
 
 // StopRecording stops the recording process for the given device
 func (a *App) StopRecording(deviceId string) error {
+	a.recordingMu.Lock()
+	_, managed := a.recordings[deviceId]
+	a.recordingMu.Unlock()
+	if managed {
+		_, err := a.StopScreenRecording(deviceId)
+		return err
+	}
+
 	a.scrcpyMu.Lock()
 	defer a.scrcpyMu.Unlock()
 
@@ -311,36 +489,38 @@ func (a *App) StopRecording(deviceId string) error {
 // IsRecording checks if a recording process is running for the device
 func (a *App) IsRecording(deviceId string) bool {
 	a.scrcpyMu.Lock()
-	defer a.scrcpyMu.Unlock()
 	_, exists := a.scrcpyRecordCmd[deviceId]
+	a.scrcpyMu.Unlock()
+	if exists {
+		return true
+	}
+
+	a.recordingMu.Lock()
+	defer a.recordingMu.Unlock()
+	_, exists = a.recordings[deviceId]
 	return exists
 }
 
-// ListCameras returns a list of available cameras for the given device
-func (a *App) ListCameras(deviceId string) ([]string, error) {
+// ListScrcpyCameras returns the device cameras with their supported capture sizes
+func (a *App) ListScrcpyCameras(deviceId string) ([]ScrcpyCamera, error) {
 	if deviceId == "" {
 		return nil, fmt.Errorf("no device specified")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if sdk := a.getSDKInt(deviceId); sdk > 0 && sdk < 31 {
+		return nil, fmt.Errorf("%w: camera mirroring requires Android 12 (API 31), device is API %d", ErrScrcpyUnsupported, sdk)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cmd := a.newScrcpyCommandContext(ctx, "-s", deviceId, "--list-cameras")
+	cmd := a.newScrcpyCommandContext(ctx, "-s", deviceId, "--list-camera-sizes")
 	output, err := cmd.CombinedOutput()
-	a.Log("ListCameras for %s: err=%v, output=%s", deviceId, err, string(output))
-
-	lines := strings.Split(string(output), "\n")
-	var cameras []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.Contains(line, "--camera-id=") {
-			cameras = append(cameras, line)
-		}
-	}
-	return cameras, nil
+	a.Log("ListScrcpyCameras for %s: err=%v, output=%s", deviceId, err, string(output))
+
+	return parseScrcpyCameras(string(output)), nil
 }
 
-// ListDisplays returns a list of available displays for the given device
-func (a *App) ListDisplays(deviceId string) ([]string, error) {
+// ListDisplays returns the displays scrcpy can mirror on the given device
+func (a *App) ListDisplays(deviceId string) ([]ScrcpyDisplay, error) {
 	if deviceId == "" {
 		return nil, fmt.Errorf("no device specified")
 	}
@@ -351,17 +531,91 @@ func (a *App) ListDisplays(deviceId string) ([]string, error) {
 	output, err := cmd.CombinedOutput()
 	a.Log("ListDisplays for %s: err=%v, output=%s", deviceId, err, string(output))
 
-	lines := strings.Split(string(output), "\n")
-	var displays []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.Contains(line, "--display-id=") {
-			displays = append(displays, line)
-		}
+	displays := []ScrcpyDisplay{}
+	for _, m := range scrcpyDisplayRegex.FindAllStringSubmatch(string(output), -1) {
+		id, _ := strconv.Atoi(m[1])
+		displays = append(displays, ScrcpyDisplay{ID: id, Size: m[2]})
 	}
 	return displays, nil
 }
 
+// parseScrcpyCameras reads lines like
+//
+//	--camera-id=0    (back, 4000x3000, fps=[15, 30])
+//	    - 1920x1080
+func parseScrcpyCameras(output string) []ScrcpyCamera {
+	cameras := []ScrcpyCamera{}
+	var current *ScrcpyCamera
+	for _, line := range strings.Split(output, "\n") {
+		if m := scrcpyCameraRegex.FindStringSubmatch(line); m != nil {
+			cameras = append(cameras, ScrcpyCamera{ID: m[1], Facing: m[2], Size: m[3], Sizes: []string{}})
+			current = &cameras[len(cameras)-1]
+			for _, f := range strings.Split(m[4], ",") {
+				if fps, err := strconv.Atoi(strings.TrimSpace(f)); err == nil {
+					current.Fps = append(current.Fps, fps)
+				}
+			}
+			continue
+		}
+		if m := scrcpyCameraSizeRegex.FindStringSubmatch(line); m != nil && current != nil {
+			current.Sizes = append(current.Sizes, m[1])
+		}
+	}
+	return cameras
+}
+
+// audioUnsupportedReason explains why audio forwarding cannot work, or returns "" when it can
+func (a *App) audioUnsupportedReason(deviceId string) string {
+	if major := a.scrcpyMajorVersion(); major > 0 && major < 2 {
+		return fmt.Sprintf("audio disabled: scrcpy %d.x does not forward audio (2.0 or newer required)", major)
+	}
+	if sdk := a.getSDKInt(deviceId); sdk > 0 && sdk < 30 {
+		return fmt.Sprintf("audio disabled: audio forwarding requires Android 11 (API 30), device is API %d", sdk)
+	}
+	return ""
+}
+
+// appendNoAudio adds --no-audio for scrcpy 2.0 and newer. Older releases never forward audio
+// and reject the flag as unknown.
+func (a *App) appendNoAudio(args []string) []string {
+	if a.scrcpyMajorVersion() >= 2 {
+		args = append(args, "--no-audio")
+	}
+	return args
+}
+
+// scrcpyMajorVersion returns the bundled scrcpy's major version, or 0 if it cannot be determined
+func (a *App) scrcpyMajorVersion() int {
+	scrcpyVersionOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		out, _ := a.newScrcpyCommandContext(ctx, "--version").CombinedOutput()
+		if m := scrcpyVersionRegex.FindStringSubmatch(string(out)); m != nil {
+			scrcpyMajor, _ = strconv.Atoi(m[1])
+		}
+	})
+	return scrcpyMajor
+}
+
+// checkScrcpyCapabilities rejects options the device's Android version cannot run, since scrcpy
+// itself only fails with a stack trace once the window is already detached
+func (a *App) checkScrcpyCapabilities(deviceId string, config ScrcpyConfig) error {
+	if config.VideoSource != "camera" && !config.NewDisplay {
+		return nil
+	}
+	sdk := a.getSDKInt(deviceId)
+	if sdk <= 0 {
+		return nil
+	}
+	if config.VideoSource == "camera" && sdk < 31 {
+		return fmt.Errorf("%w: camera mirroring requires Android 12 (API 31), device is API %d", ErrScrcpyUnsupported, sdk)
+	}
+	if config.NewDisplay && sdk < 29 {
+		return fmt.Errorf("%w: virtual displays require Android 10 (API 29), device is API %d", ErrScrcpyUnsupported, sdk)
+	}
+	return nil
+}
+
 // SelectRecordPath returns a default recording path in the Downloads folder
 func (a *App) SelectRecordPath(deviceModel string) (string, error) {
 	defaultDir, _ := os.UserHomeDir()
@@ -402,8 +656,9 @@ func (a *App) SelectScreenshotPath(deviceModel string) (string, error) {
 	return fullPath, nil
 }
 
-// TakeScreenshot captures a screenshot of the device and saves it to the host
-func (a *App) TakeScreenshot(deviceId, savePath string) (string, error) {
+// TakeScreenshot captures a screenshot of the device and saves it to the host. withDemoMode
+// puts the status bar into demo mode for the capture.
+func (a *App) TakeScreenshot(deviceId, savePath string, withDemoMode bool) (string, error) {
 	if deviceId == "" {
 		return "", fmt.Errorf("no device specified")
 	}
@@ -430,6 +685,19 @@ func (a *App) TakeScreenshot(deviceId, savePath string) (string, error) {
 		return "", fmt.Errorf("SCREEN_OFF")
 	}
 
+	// Demo mode someone turned on by hand is left on
+	if withDemoMode && !a.demoModeActive(deviceId) {
+		// A failed enable may still have applied some broadcasts, so always restore
+		defer a.DisableDemoMode(deviceId)
+		if err := a.EnableDemoMode(deviceId, DemoModeOptions{}); err != nil {
+			// Still worth capturing; the status bar just isn't cleaned up
+			a.Log("Screenshot on %s without demo mode: %v", deviceId, err)
+		} else {
+			// Give SystemUI a frame to redraw the status bar
+			time.Sleep(300 * time.Millisecond)
+		}
+	}
+
 	wailsRuntime.EventsEmit(a.ctx, "screenshot-progress", "screenshot_capturing")
 	remotePath := "/sdcard/screenshot_tmp.png"
 	capCmd := exec.Command(a.adbPath, "-s", deviceId, "shell", "screencap", "-p", remotePath)
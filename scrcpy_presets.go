@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultScrcpyConfig is used when a device has no default preset
+var defaultScrcpyConfig = ScrcpyConfig{BitRate: 8, MaxFps: 60, StayAwake: true, VideoCodec: "h264", AudioCodec: "opus"}
+
+// builtInScrcpyPresets are always offered and cannot be deleted or overwritten
+var builtInScrcpyPresets = []ScrcpyPreset{
+	{Name: "Low-latency", BuiltIn: true, Config: ScrcpyConfig{MaxSize: 1280, BitRate: 4, MaxFps: 60, StayAwake: true, VideoCodec: "h264", NoAudio: true}},
+	{Name: "High quality", BuiltIn: true, Config: ScrcpyConfig{BitRate: 16, MaxFps: 60, StayAwake: true, VideoCodec: "h265", AudioCodec: "opus"}},
+	{Name: "Battery saver", BuiltIn: true, Config: ScrcpyConfig{MaxSize: 800, BitRate: 2, MaxFps: 30, TurnScreenOff: true, VideoCodec: "h264", NoAudio: true}},
+}
+
+// scrcpyPresetStore is the on-disk layout of scrcpy_presets.json
+type scrcpyPresetStore struct {
+	Presets  []ScrcpyPreset    `json:"presets"`
+	Defaults map[string]string `json:"defaults"` // device serial -> preset name
+}
+
+var (
+	scrcpyPresets       *scrcpyPresetStore
+	scrcpyPresetsMu     sync.Mutex
+	scrcpyPresetsLoaded bool
+)
+
+func (a *App) getScrcpyPresetsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "scrcpy_presets.json")
+}
+
+// loadScrcpyPresetsLocked reads scrcpy_presets.json once; callers must hold scrcpyPresetsMu
+func (a *App) loadScrcpyPresetsLocked() *scrcpyPresetStore {
+	if scrcpyPresetsLoaded {
+		return scrcpyPresets
+	}
+	scrcpyPresetsLoaded = true
+	scrcpyPresets = &scrcpyPresetStore{Defaults: make(map[string]string)}
+
+	data, err := os.ReadFile(a.getScrcpyPresetsPath())
+	if err != nil {
+		return scrcpyPresets
+	}
+	var stored scrcpyPresetStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return scrcpyPresets
+	}
+	scrcpyPresets.Presets = stored.Presets
+	if stored.Defaults != nil {
+		scrcpyPresets.Defaults = stored.Defaults
+	}
+	return scrcpyPresets
+}
+
+// saveScrcpyPresetsLocked writes scrcpy_presets.json; callers must hold scrcpyPresetsMu
+func (a *App) saveScrcpyPresetsLocked() error {
+	data, err := json.MarshalIndent(scrcpyPresets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.getScrcpyPresetsPath(), data, 0644)
+}
+
+// findScrcpyPresetLocked looks a preset up by name, built-ins first; callers must hold scrcpyPresetsMu
+func (a *App) findScrcpyPresetLocked(name string) (ScrcpyPreset, bool) {
+	for _, p := range builtInScrcpyPresets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	for _, p := range a.loadScrcpyPresetsLocked().Presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return ScrcpyPreset{}, false
+}
+
+// SaveScrcpyPreset creates or updates a user preset
+func (a *App) SaveScrcpyPreset(name string, cfg ScrcpyConfig) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("preset name is required")
+	}
+	// Per-recording and per-session fields don't belong in a reusable preset
+	cfg.RecordPath = ""
+	cfg.AllowMultiple = false
+
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+
+	if existing, ok := a.findScrcpyPresetLocked(name); ok && existing.BuiltIn {
+		return fmt.Errorf("%q is a built-in preset; save it under a different name", existing.Name)
+	}
+
+	store := a.loadScrcpyPresetsLocked()
+	for i, p := range store.Presets {
+		if strings.EqualFold(p.Name, name) {
+			store.Presets[i].Config = cfg
+			return a.saveScrcpyPresetsLocked()
+		}
+	}
+	store.Presets = append(store.Presets, ScrcpyPreset{Name: name, Config: cfg})
+	return a.saveScrcpyPresetsLocked()
+}
+
+// CopyScrcpyPreset saves an existing preset, including a built-in one, under a new name
+func (a *App) CopyScrcpyPreset(source, name string) error {
+	scrcpyPresetsMu.Lock()
+	preset, ok := a.findScrcpyPresetLocked(source)
+	scrcpyPresetsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("preset not found: %s", source)
+	}
+	return a.SaveScrcpyPreset(name, preset.Config)
+}
+
+// ListScrcpyPresets returns the built-in presets followed by the user's presets sorted by name
+func (a *App) ListScrcpyPresets() []ScrcpyPreset {
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+
+	user := append([]ScrcpyPreset{}, a.loadScrcpyPresetsLocked().Presets...)
+	sort.Slice(user, func(i, j int) bool { return strings.ToLower(user[i].Name) < strings.ToLower(user[j].Name) })
+	return append(append([]ScrcpyPreset{}, builtInScrcpyPresets...), user...)
+}
+
+// DeleteScrcpyPreset removes a user preset and clears it as a default wherever it was used
+func (a *App) DeleteScrcpyPreset(name string) error {
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+
+	if existing, ok := a.findScrcpyPresetLocked(name); ok && existing.BuiltIn {
+		return fmt.Errorf("built-in preset %q cannot be deleted", existing.Name)
+	}
+
+	store := a.loadScrcpyPresetsLocked()
+	for i, p := range store.Presets {
+		if strings.EqualFold(p.Name, name) {
+			store.Presets = append(store.Presets[:i], store.Presets[i+1:]...)
+			for serial, def := range store.Defaults {
+				if strings.EqualFold(def, name) {
+					delete(store.Defaults, serial)
+				}
+			}
+			return a.saveScrcpyPresetsLocked()
+		}
+	}
+	return fmt.Errorf("preset not found: %s", name)
+}
+
+// SetDefaultScrcpyPreset picks the preset used when mirroring the device; an empty name clears it
+func (a *App) SetDefaultScrcpyPreset(deviceId, presetName string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+
+	store := a.loadScrcpyPresetsLocked()
+	serial := a.serialFor(deviceId)
+	if presetName == "" {
+		delete(store.Defaults, serial)
+		return a.saveScrcpyPresetsLocked()
+	}
+
+	preset, ok := a.findScrcpyPresetLocked(presetName)
+	if !ok {
+		return fmt.Errorf("preset not found: %s", presetName)
+	}
+	store.Defaults[serial] = preset.Name
+	return a.saveScrcpyPresetsLocked()
+}
+
+// GetDefaultScrcpyPreset returns the name of the device's default preset, or "" when none is set
+func (a *App) GetDefaultScrcpyPreset(deviceId string) string {
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+	return a.loadScrcpyPresetsLocked().Defaults[a.serialFor(deviceId)]
+}
+
+// GetDefaultScrcpyConfig returns the config of the device's default preset, falling back to
+// the stock settings when none is set or the preset no longer exists
+func (a *App) GetDefaultScrcpyConfig(deviceId string) ScrcpyConfig {
+	scrcpyPresetsMu.Lock()
+	defer scrcpyPresetsMu.Unlock()
+
+	name := a.loadScrcpyPresetsLocked().Defaults[a.serialFor(deviceId)]
+	if preset, ok := a.findScrcpyPresetLocked(name); name != "" && ok {
+		return preset.Config
+	}
+	return defaultScrcpyConfig
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// screenrecordSegmentLimit is the longest clip adb screenrecord will produce in one run
+const screenrecordSegmentLimit = 180
+
+var screenrecordSizeRegex = regexp.MustCompile(`^\d+x\d+$`)
+
+// StartScreenrecord records with the device's own screenrecord binary. It has no audio and stops
+// after three minutes, so segments are chained until StopScreenRecording or opts.MaxDuration.
+func (a *App) StartScreenrecord(deviceId string, opts RecordOptions) (*RecordingInfo, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if opts.Size != "" && !screenrecordSizeRegex.MatchString(opts.Size) {
+		return nil, fmt.Errorf("invalid size %q: expected WIDTHxHEIGHT", opts.Size)
+	}
+	// screenrecord only writes mp4
+	opts.Format = "mp4"
+	opts.Backend = "screenrecord"
+
+	recordPath, model, err := a.prepareRecording(deviceId, "mp4")
+	if err != nil {
+		return nil, err
+	}
+
+	remotePrefix := fmt.Sprintf("/sdcard/gaze_rec_%d", time.Now().UnixNano())
+	var stopping atomic.Bool
+
+	rec := &screenRecording{
+		info: RecordingInfo{
+			Path:      recordPath,
+			Name:      filepath.Base(recordPath),
+			DeviceID:  deviceId,
+			Model:     model,
+			Backend:   "screenrecord",
+			CreatedAt: time.Now().Unix(),
+		},
+		opts:      opts,
+		startedAt: time.Now(),
+		stop: func() error {
+			stopping.Store(true)
+			// SIGINT lets screenrecord write the moov atom before exiting; the bracket keeps
+			// the pattern from matching the shell that runs pkill
+			pattern := "[s]creenrecord.*" + remotePrefix
+			return a.newAdbCommand(nil, "-s", deviceId, "shell", "pkill -INT -f "+shellQuote(pattern)).Run()
+		},
+		done: make(chan struct{}),
+	}
+
+	a.recordingMu.Lock()
+	a.recordings[deviceId] = rec
+	a.recordingMu.Unlock()
+
+	wailsRuntime.EventsEmit(a.ctx, "screen-recording-started", rec.info)
+
+	go a.watchRecording(rec)
+	go func() {
+		parts := a.runScreenrecordSegments(rec, remotePrefix, &stopping)
+		a.collectScreenrecordParts(rec, parts)
+		a.finishRecording(rec)
+	}()
+
+	info := rec.info
+	return &info, nil
+}
+
+// runScreenrecordSegments records consecutive clips until stopped, the time budget runs out or
+// the device-side process fails, and returns the remote paths that were written
+func (a *App) runScreenrecordSegments(rec *screenRecording, remotePrefix string, stopping *atomic.Bool) []string {
+	deviceId := rec.info.DeviceID
+	var parts []string
+
+	for i := 0; !stopping.Load(); i++ {
+		limit := screenrecordSegmentLimit
+		if rec.opts.MaxDuration > 0 {
+			remaining := rec.opts.MaxDuration - int(time.Since(rec.startedAt).Seconds())
+			if remaining <= 0 {
+				break
+			}
+			if remaining < limit {
+				limit = remaining
+			}
+		}
+
+		remote := fmt.Sprintf("%s_%03d.mp4", remotePrefix, i)
+		args := []string{"screenrecord", "--time-limit", fmt.Sprintf("%d", limit)}
+		if rec.opts.BitRate > 0 {
+			args = append(args, "--bit-rate", fmt.Sprintf("%d", rec.opts.BitRate*1000000))
+		}
+		if rec.opts.Size != "" {
+			args = append(args, "--size", rec.opts.Size)
+		}
+		args = append(args, remote)
+
+		segStart := time.Now()
+		output, err := a.runTrackedCommand("screenrecord", a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(args, " ")))
+		parts = append(parts, remote)
+
+		if err != nil && !stopping.Load() {
+			a.Log("screenrecord segment %d on %s ended early: %v, %s", i, deviceId, err, strings.TrimSpace(string(output)))
+			wailsRuntime.EventsEmit(a.ctx, "screen-recording-error", map[string]interface{}{
+				"deviceId": deviceId,
+				"error":    strings.TrimSpace(string(output)),
+			})
+			break
+		}
+		// A segment that ends well before its limit without a stop request means the device gave up
+		if !stopping.Load() && time.Since(segStart) < time.Duration(limit-5)*time.Second {
+			a.Log("screenrecord segment %d on %s stopped after %v", i, deviceId, time.Since(segStart))
+			break
+		}
+	}
+	return parts
+}
+
+// collectScreenrecordParts pulls the recorded segments, joins them when asked and removes them from the device
+func (a *App) collectScreenrecordParts(rec *screenRecording, remoteParts []string) {
+	deviceId := rec.info.DeviceID
+	defer func() {
+		if len(remoteParts) > 0 {
+			_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -f "+strings.Join(quoteAll(remoteParts), " ")).Run()
+		}
+	}()
+
+	base := strings.TrimSuffix(rec.info.Path, filepath.Ext(rec.info.Path))
+	var local []string
+	for i, remote := range remoteParts {
+		dest := rec.info.Path
+		if len(remoteParts) > 1 {
+			dest = fmt.Sprintf("%s_part%d.mp4", base, i+1)
+		}
+		if output, err := a.newAdbCommand(nil, "-s", deviceId, "pull", remote, dest).CombinedOutput(); err != nil {
+			a.Log("Failed to pull %s: %v, %s", remote, err, strings.TrimSpace(string(output)))
+			continue
+		}
+		if fi, err := os.Stat(dest); err != nil || fi.Size() == 0 {
+			// An interrupted first frame leaves an empty file behind
+			_ = os.Remove(dest)
+			continue
+		}
+		local = append(local, dest)
+	}
+
+	switch {
+	case len(local) == 0:
+		return
+	case len(local) == 1:
+		if local[0] != rec.info.Path {
+			if err := os.Rename(local[0], rec.info.Path); err != nil {
+				rec.info.Path = local[0]
+				rec.info.Name = filepath.Base(local[0])
+			}
+		}
+		return
+	}
+
+	if rec.opts.Concat {
+		err := concatVideos(local, rec.info.Path)
+		if err == nil {
+			for _, p := range local {
+				_ = os.Remove(p)
+			}
+			return
+		}
+		a.Log("Failed to join screenrecord segments: %v", err)
+	}
+	rec.info.Path = local[0]
+	rec.info.Name = filepath.Base(local[0])
+	rec.info.Parts = local
+}
+
+// concatVideos joins mp4 clips without re-encoding using ffmpeg's concat demuxer
+func concatVideos(inputs []string, output string) error {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found on PATH")
+	}
+
+	list, err := os.CreateTemp("", "gaze-concat-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(list.Name())
+	for _, p := range inputs {
+		fmt.Fprintf(list, "file '%s'\n", strings.ReplaceAll(p, "'", `'\''`))
+	}
+	list.Close()
+
+	if out, err := exec.Command(ffmpeg, "-y", "-f", "concat", "-safe", "0", "-i", list.Name(), "-c", "copy", output).CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w, output: %s", err, lastLines(string(out), 3))
+	}
+	return nil
+}
+
+func quoteAll(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = shellQuote(s)
+	}
+	return quoted
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
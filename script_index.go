@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Script Index State. Filenames are sanitized display names, so different names can map to
+// the same file ("Login – EU" and "Login @ EU"); the index records which file holds which name.
+var (
+	scriptIndex       map[string]string // display name -> file name in the scripts directory
+	scriptIndexMu     sync.Mutex
+	scriptIndexLoaded bool
+)
+
+// ScriptExistsError is returned when saving a new script under a name that is already taken
+type ScriptExistsError struct {
+	Name string
+}
+
+func (e *ScriptExistsError) Error() string {
+	return fmt.Sprintf("script already exists: %s", e.Name)
+}
+
+func (a *App) getScriptIndexPath() string {
+	return filepath.Join(filepath.Dir(a.getScriptsPath()), "scripts_index.json")
+}
+
+// loadScriptIndexLocked reads scripts_index.json once and reconciles it with the scripts
+// directory: entries whose file is gone are dropped and unindexed files are added under the
+// name stored inside them. Callers must hold scriptIndexMu.
+func (a *App) loadScriptIndexLocked() {
+	if scriptIndexLoaded {
+		return
+	}
+	scriptIndexLoaded = true
+	scriptIndex = make(map[string]string)
+
+	if data, err := os.ReadFile(a.getScriptIndexPath()); err == nil {
+		_ = json.Unmarshal(data, &scriptIndex)
+	}
+
+	scriptsPath := a.getScriptsPath()
+	indexed := make(map[string]bool)
+	for name, file := range scriptIndex {
+		if _, err := os.Stat(filepath.Join(scriptsPath, file)); err != nil {
+			delete(scriptIndex, name)
+			continue
+		}
+		indexed[file] = true
+	}
+
+	entries, _ := os.ReadDir(scriptsPath)
+	changed := false
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || indexed[entry.Name()] {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if data, err := os.ReadFile(filepath.Join(scriptsPath, entry.Name())); err == nil {
+			var script TouchScript
+			if json.Unmarshal(data, &script) == nil && script.Name != "" {
+				name = script.Name
+			}
+		}
+		if _, taken := scriptIndex[name]; taken {
+			continue
+		}
+		scriptIndex[name] = entry.Name()
+		changed = true
+	}
+	if changed {
+		_ = a.saveScriptIndexLocked()
+	}
+}
+
+// saveScriptIndexLocked writes scripts_index.json; callers must hold scriptIndexMu
+func (a *App) saveScriptIndexLocked() error {
+	data, err := json.MarshalIndent(scriptIndex, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(a.getScriptIndexPath(), data, 0644)
+}
+
+// scriptFileLocked returns the path holding the named script and whether it exists. For a new
+// name it picks a free file name, treating reuse (a file being renamed) as free. Callers must
+// hold scriptIndexMu.
+func (a *App) scriptFileLocked(name, reuse string) (string, bool) {
+	scriptsPath := a.getScriptsPath()
+	if file, ok := scriptIndex[name]; ok {
+		return filepath.Join(scriptsPath, file), true
+	}
+
+	used := make(map[string]bool, len(scriptIndex))
+	for _, file := range scriptIndex {
+		used[file] = true
+	}
+	base := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(name, "_")
+	for n := 1; ; n++ {
+		file := base + ".json"
+		if n > 1 {
+			file = fmt.Sprintf("%s_%d.json", base, n)
+		}
+		path := filepath.Join(scriptsPath, file)
+		if file == reuse {
+			return path, false
+		}
+		if used[file] {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		return path, false
+	}
+}
+
+// writeTouchScriptLocked stores script at path and records it in the index; callers must hold
+// scriptIndexMu
+func (a *App) writeTouchScriptLocked(path string, script TouchScript) error {
+	script.Version = touchScriptVersion
+	data, err := json.MarshalIndent(script, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal script: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write script file: %w", err)
+	}
+	scriptIndex[script.Name] = filepath.Base(path)
+	return a.saveScriptIndexLocked()
+}
+
+// writeFileAtomic writes through a temp file in the same directory and renames it into place,
+// so a crash mid-write never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// defaultScriptName names a script saved without one
+func defaultScriptName() string {
+	return fmt.Sprintf("script_%d", time.Now().Unix())
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Script Summary Cache. Headers are re-read only when a file's size or modification time changes.
+var (
+	scriptSummaryCache   = make(map[string]cachedScriptSummary) // file path -> summary
+	scriptSummaryCacheMu sync.Mutex
+)
+
+type cachedScriptSummary struct {
+	modTime time.Time
+	size    int64
+	summary TouchScriptSummary
+}
+
+// ListTouchScripts returns the headers of the saved scripts that match filter, without the
+// events. Only the header fields of each file are decoded, so large recordings stay cheap.
+func (a *App) ListTouchScripts(filter ScriptFilter) ([]TouchScriptSummary, error) {
+	scriptIndexMu.Lock()
+	a.loadScriptIndexLocked()
+	files := make(map[string]string, len(scriptIndex))
+	for name, file := range scriptIndex {
+		files[name] = filepath.Join(a.getScriptsPath(), file)
+	}
+	scriptIndexMu.Unlock()
+
+	folder := normalizeScriptFolder(filter.Folder)
+	nameFilter := strings.ToLower(filter.Name)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if nameFilter == "" || strings.Contains(strings.ToLower(name), nameFilter) {
+			names = append(names, name)
+		}
+	}
+
+	// Headers are read in parallel; a cold list of a large library is bound by JSON scanning
+	read := make([]TouchScriptSummary, len(names))
+	readErr := make([]error, len(names))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.NumCPU(), len(names)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				read[i], readErr[i] = readScriptSummary(files[names[i]])
+			}
+		}()
+	}
+	for i := range names {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	summaries := make([]TouchScriptSummary, 0, len(names))
+	for i, summary := range read {
+		if readErr[i] != nil {
+			continue
+		}
+		summary.Name = names[i]
+		if filter.Folder != "" && summary.Folder != folder {
+			continue
+		}
+		if filter.Tag != "" && !containsFold(summary.Tags, filter.Tag) {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	less := func(i, j int) bool {
+		return strings.ToLower(summaries[i].Name) < strings.ToLower(summaries[j].Name)
+	}
+	switch filter.SortBy {
+	case "created":
+		less = func(i, j int) bool {
+			ti, erri := time.Parse(time.RFC3339, summaries[i].CreatedAt)
+			tj, errj := time.Parse(time.RFC3339, summaries[j].CreatedAt)
+			if erri != nil || errj != nil {
+				return summaries[i].CreatedAt < summaries[j].CreatedAt
+			}
+			return ti.Before(tj)
+		}
+	case "modified":
+		less = func(i, j int) bool { return summaries[i].ModifiedAt < summaries[j].ModifiedAt }
+	}
+	if filter.Descending {
+		sort.SliceStable(summaries, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(summaries, less)
+	}
+	return summaries, nil
+}
+
+// MoveTouchScriptToFolder files a script under folder; "" moves it back to the top level
+func (a *App) MoveTouchScriptToFolder(name, folder string) error {
+	script, err := a.loadTouchScript(name)
+	if err != nil {
+		return err
+	}
+	script.Folder = normalizeScriptFolder(folder)
+	return a.SaveTouchScript(*script, true)
+}
+
+// SetTouchScriptTags replaces a script's tags. Blank and duplicate tags are dropped.
+func (a *App) SetTouchScriptTags(name string, tags []string) error {
+	script, err := a.loadTouchScript(name)
+	if err != nil {
+		return err
+	}
+	var cleaned []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !containsFold(cleaned, tag) {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	script.Tags = cleaned
+	return a.SaveTouchScript(*script, true)
+}
+
+// normalizeScriptFolder trims spaces and stray slashes so "/Login/ EU/" and "Login/EU" match
+func normalizeScriptFolder(folder string) string {
+	var parts []string
+	for _, part := range strings.Split(folder, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// readScriptSummary returns the header of the script at path, from the cache when the file
+// hasn't changed
+func readScriptSummary(path string) (TouchScriptSummary, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return TouchScriptSummary{}, err
+	}
+
+	scriptSummaryCacheMu.Lock()
+	cached, ok := scriptSummaryCache[path]
+	scriptSummaryCacheMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.summary, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return TouchScriptSummary{}, err
+	}
+	defer f.Close()
+	summary, err := decodeScriptSummary(f)
+	if err != nil {
+		return TouchScriptSummary{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	summary.ModifiedAt = info.ModTime().UnixMilli()
+
+	scriptSummaryCacheMu.Lock()
+	scriptSummaryCache[path] = cachedScriptSummary{modTime: info.ModTime(), size: info.Size(), summary: summary}
+	scriptSummaryCacheMu.Unlock()
+	return summary, nil
+}
+
+// decodeScriptSummary walks the top level of a script's JSON, decoding the header fields and
+// only counting the events; nothing from the event and raw input arrays is kept
+func decodeScriptSummary(r io.Reader) (TouchScriptSummary, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return TouchScriptSummary{}, fmt.Errorf("not a script object")
+	}
+
+	summary := TouchScriptSummary{Tags: []string{}}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return summary, err
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "name":
+			err = dec.Decode(&summary.Name)
+		case "deviceModel":
+			err = dec.Decode(&summary.DeviceModel)
+		case "resolution":
+			err = dec.Decode(&summary.Resolution)
+		case "createdAt":
+			err = dec.Decode(&summary.CreatedAt)
+		case "folder":
+			err = dec.Decode(&summary.Folder)
+		case "tags":
+			err = dec.Decode(&summary.Tags)
+		case "events":
+			summary.EventCount, err = countJSONArray(dec)
+		default:
+			err = skipJSONValue(dec)
+		}
+		if err != nil {
+			return summary, err
+		}
+	}
+	if summary.Tags == nil {
+		summary.Tags = []string{}
+	}
+	return summary, nil
+}
+
+// countJSONArray consumes an array (or null) and returns how many elements it held
+func countJSONArray(dec *json.Decoder) (int, error) {
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return 0, err
+	}
+	n := 0
+	for dec.More() {
+		if err := skipJSONValue(dec); err != nil {
+			return n, err
+		}
+		n++
+	}
+	_, err = dec.Token() // ']'
+	return n, err
+}
+
+// skipJSONValue consumes the next value without keeping it. Decoding into a RawMessage only
+// runs the scanner, which is far quicker than walking the value token by token.
+func skipJSONValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// securityPatchMaxAge is how old the security patch may be before the report warns
+const securityPatchMaxAge = 90 * 24 * time.Hour
+
+// securityReportScript prints key=value lines, and multi-line values between "key<<" and ">>"
+const securityReportScript = `echo "model=$(getprop ro.product.model)"
+echo "selinux=$(getenforce 2>/dev/null)"
+echo "debuggable=$(getprop ro.debuggable)"
+echo "secure=$(getprop ro.secure)"
+echo "buildtype=$(getprop ro.build.type)"
+echo "verifiedboot=$(getprop ro.boot.verifiedbootstate)"
+echo "vbmeta=$(getprop ro.boot.vbmeta.device_state)"
+echo "patch=$(getprop ro.build.version.security_patch)"
+echo "uid=$(id -u)"
+echo "devoptions=$(settings get global development_settings_enabled)"
+echo "unknownsources=$(settings get secure install_non_market_apps)"
+echo "crypto=$(getprop ro.crypto.state)"
+echo "cryptotype=$(getprop ro.crypto.type)"
+echo "a11y=$(settings get secure enabled_accessibility_services)"
+echo "installers<<"; appops query-op --user 0 REQUEST_INSTALL_PACKAGES allow 2>/dev/null; echo ">>"
+echo "owners<<"; dpm list-owners 2>/dev/null; echo ">>"
+echo "policy<<"; dumpsys device_policy 2>/dev/null; echo ">>"`
+
+// deviceAdminRegex matches an admin component listed under "Enabled Device Admins" in
+// dumpsys device_policy ("    com.example/.AdminReceiver:")
+var deviceAdminRegex = regexp.MustCompile(`^ {4}([\w.]+/[\w.$]+):\s*$`)
+
+// parseSecurityValues splits the script output into single values and multi-line blocks
+func parseSecurityValues(output string) (map[string]string, map[string][]string) {
+	values := make(map[string]string)
+	blocks := make(map[string][]string)
+	block := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if block != "" {
+			if line == ">>" {
+				block = ""
+			} else {
+				blocks[block] = append(blocks[block], line)
+			}
+			continue
+		}
+		if name, ok := strings.CutSuffix(line, "<<"); ok {
+			block = name
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			v = strings.TrimSpace(v)
+			if v == "null" {
+				v = ""
+			}
+			values[k] = v
+		}
+	}
+	return values, blocks
+}
+
+// parseDeviceAdmins lists the components in the "Enabled Device Admins" sections
+func parseDeviceAdmins(lines []string) []string {
+	admins := []string{}
+	inAdmins := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Enabled Device Admins") {
+			inAdmins = true
+			continue
+		}
+		if !inAdmins {
+			continue
+		}
+		if m := deviceAdminRegex.FindStringSubmatch(line); m != nil {
+			admins = append(admins, m[1])
+		} else if trimmed != "" && !strings.HasPrefix(line, "    ") {
+			inAdmins = false
+		}
+	}
+	return admins
+}
+
+// buildSecurityReport classifies the collected values. now is passed in so the patch age is
+// measured against the collection time.
+func buildSecurityReport(values map[string]string, blocks map[string][]string, now time.Time) *SecurityReport {
+	report := &SecurityReport{Model: values["model"], CollectedAt: now.Unix(), AccessibilityServices: []string{}, DeviceOwners: []string{}, DeviceAdmins: []string{}, InstallSources: []string{}}
+	add := func(id, label, value, status, detail string) {
+		if value == "" {
+			value = "unknown"
+			if status != "info" {
+				status, detail = "info", "Not reported by this build"
+			}
+		}
+		report.Checks = append(report.Checks, SecurityCheck{ID: id, Label: label, Value: value, Status: status, Detail: detail})
+	}
+
+	switch v := values["selinux"]; v {
+	case "Enforcing":
+		add("selinux", "SELinux", v, "pass", "")
+	default:
+		add("selinux", "SELinux", v, "warn", "SELinux is not enforcing")
+	}
+
+	if values["debuggable"] == "1" {
+		add("debuggable", "Debuggable build", "ro.debuggable=1, "+values["buildtype"], "warn", "Every app on this build can be debugged")
+	} else {
+		add("debuggable", "Debuggable build", values["debuggable"], "pass", "")
+	}
+	if values["secure"] == "0" {
+		add("secure", "ro.secure", "0", "warn", "adb shell starts as root")
+	} else {
+		add("secure", "ro.secure", values["secure"], "pass", "")
+	}
+
+	boot := values["verifiedboot"]
+	switch boot {
+	case "green":
+		add("verified_boot", "Verified boot", boot, "pass", "")
+	case "":
+		add("verified_boot", "Verified boot", "", "warn", "")
+	default:
+		add("verified_boot", "Verified boot", boot, "warn", "The boot chain isn't verified by the OEM key (yellow, orange = unlocked, red = failed)")
+	}
+	if state := values["vbmeta"]; state == "unlocked" {
+		add("bootloader", "Bootloader", state, "warn", "The bootloader is unlocked")
+	} else {
+		add("bootloader", "Bootloader", state, "pass", "")
+	}
+
+	patch := values["patch"]
+	if t, err := time.Parse("2006-01-02", patch); err == nil && now.Sub(t) > securityPatchMaxAge {
+		add("security_patch", "Security patch", patch, "warn", fmt.Sprintf("Older than %d days", int(securityPatchMaxAge.Hours()/24)))
+	} else {
+		add("security_patch", "Security patch", patch, "pass", "")
+	}
+
+	if values["uid"] == "0" {
+		add("adb_root", "adb as root", "yes", "warn", "adbd is running as root")
+	} else {
+		add("adb_root", "adb as root", "no", "pass", "")
+	}
+
+	// Developer options are necessarily on for adb to work at all
+	add("developer_options", "Developer options", onOff(values["devoptions"] != "0", "on", "off"), "info", "")
+
+	installers := []string{}
+	for _, line := range blocks["installers"] {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(line, " ") {
+			installers = append(installers, line)
+		}
+	}
+	report.InstallSources = installers
+	switch {
+	case values["unknownsources"] == "1":
+		add("unknown_sources", "Unknown sources", "allowed", "warn", "Apps can be installed from outside the store")
+	case len(installers) > 0:
+		add("unknown_sources", "Unknown sources", strings.Join(installers, ", "), "warn", "These apps may install other apps")
+	default:
+		add("unknown_sources", "Unknown sources", "not allowed", "pass", "")
+	}
+
+	crypto := values["crypto"]
+	if crypto == "encrypted" {
+		value := crypto
+		if t := values["cryptotype"]; t != "" {
+			value += " (" + t + ")"
+		}
+		add("encryption", "Encryption", value, "pass", "")
+	} else {
+		add("encryption", "Encryption", crypto, "warn", "Storage is not encrypted")
+	}
+
+	for _, s := range strings.Split(values["a11y"], ":") {
+		if s = strings.TrimSpace(s); s != "" {
+			report.AccessibilityServices = append(report.AccessibilityServices, s)
+		}
+	}
+	if n := len(report.AccessibilityServices); n > 0 {
+		add("accessibility", "Accessibility services", strings.Join(report.AccessibilityServices, ", "), "warn", "Accessibility services can read and control the screen")
+	} else {
+		add("accessibility", "Accessibility services", "none", "pass", "")
+	}
+
+	for _, line := range blocks["owners"] {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "no owners") {
+			report.DeviceOwners = append(report.DeviceOwners, line)
+		}
+	}
+	report.DeviceAdmins = parseDeviceAdmins(blocks["policy"])
+	switch {
+	case len(report.DeviceOwners) > 0:
+		add("device_admin", "Device management", strings.Join(report.DeviceOwners, "; "), "warn", "The device or a profile is managed")
+	case len(report.DeviceAdmins) > 0:
+		add("device_admin", "Device management", strings.Join(report.DeviceAdmins, ", "), "warn", "Device admin apps are active")
+	default:
+		add("device_admin", "Device management", "none", "pass", "")
+	}
+
+	for _, c := range report.Checks {
+		switch c.Status {
+		case "pass":
+			report.Passed++
+		case "warn":
+			report.Warnings++
+		}
+	}
+	return report
+}
+
+// GetSecurityReport collects the device's security settings into pass/warn checks
+func (a *App) GetSecurityReport(deviceId string) (*SecurityReport, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", securityReportScript).CombinedOutput()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to read security settings: %w", err)
+	}
+	values, blocks := parseSecurityValues(string(out))
+	report := buildSecurityReport(values, blocks, time.Now())
+	report.DeviceID = deviceId
+	report.Serial = a.serialFor(deviceId)
+	return report, nil
+}
+
+// ExportSecurityReport collects a fresh report and writes it as JSON. With an empty destPath a
+// save dialog is shown; the written path is returned, or "" if the dialog was cancelled.
+func (a *App) ExportSecurityReport(deviceId, destPath string) (string, error) {
+	report, err := a.GetSecurityReport(deviceId)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if destPath == "" {
+		destPath, err = wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+			DefaultFilename: fmt.Sprintf("security_%s_%s.json", report.Serial, time.Now().Format("20060102")),
+			Title:           "Export Security Report",
+			Filters:         []wailsRuntime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to open save dialog: %w", err)
+		}
+		if destPath == "" {
+			return "", nil
+		}
+	}
+	if err := writeFileAtomic(destPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return destPath, nil
+}
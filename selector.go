@@ -59,6 +59,13 @@ func (a *App) FindElementBySelector(root *UINode, selector *ElementSelector) *UI
 	if selector == nil || root == nil {
 		return nil
 	}
+	if selector.Within != nil || selector.ChildIndex != nil {
+		nodes := a.FindAllElementsBySelector(root, selector)
+		if selector.Index < len(nodes) {
+			return nodes[selector.Index]
+		}
+		return nil
+	}
 
 	switch selector.Type {
 	case "text":
@@ -78,9 +85,11 @@ func (a *App) FindElementBySelector(root *UINode, selector *ElementSelector) *UI
 		}
 		return nil
 	case "bounds":
-		// Direct bounds match
-		node := &UINode{Bounds: selector.Value}
-		return node
+		nodes := a.findElementsByBounds(root, selector.Value)
+		if selector.Index < len(nodes) {
+			return nodes[selector.Index]
+		}
+		return nil
 	case "coordinates":
 		// Parse coordinates and find element at point
 		parts := strings.Split(selector.Value, ",")
@@ -104,6 +113,51 @@ func (a *App) FindAllElementsBySelector(root *UINode, selector *ElementSelector)
 		return nil
 	}
 
+	scopes := []*UINode{root}
+	if selector.Within != nil {
+		scope := a.FindElementBySelector(root, selector.Within)
+		if scope == nil {
+			return nil
+		}
+		scopes = scopes[:0]
+		for i := range scope.Nodes {
+			scopes = append(scopes, &scope.Nodes[i])
+		}
+	}
+	var nodes []*UINode
+	for _, scope := range scopes {
+		nodes = append(nodes, a.findAllByType(scope, selector)...)
+	}
+
+	if selector.ChildIndex != nil {
+		positions := childPositions(root)
+		kept := nodes[:0]
+		for _, n := range nodes {
+			if pos, ok := positions[n]; ok && pos == *selector.ChildIndex {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	}
+	return nodes
+}
+
+// childPositions maps every node below root to its index among its parent's children
+func childPositions(root *UINode) map[*UINode]int {
+	positions := make(map[*UINode]int)
+	var walk func(n *UINode)
+	walk = func(n *UINode) {
+		for i := range n.Nodes {
+			positions[&n.Nodes[i]] = i
+			walk(&n.Nodes[i])
+		}
+	}
+	walk(root)
+	return positions
+}
+
+// findAllByType matches the selector's own type and value, ignoring Within and ChildIndex
+func (a *App) findAllByType(root *UINode, selector *ElementSelector) []*UINode {
 	switch selector.Type {
 	case "text":
 		return a.collectMatchingNodes(root, func(n *UINode) bool {
@@ -132,6 +186,13 @@ func (a *App) FindAllElementsBySelector(root *UINode, selector *ElementSelector)
 			nodes[i] = r.Node
 		}
 		return nodes
+	case "bounds":
+		return a.findElementsByBounds(root, selector.Value)
+	case "coordinates":
+		if node := a.FindElementBySelector(root, &ElementSelector{Type: "coordinates", Value: selector.Value}); node != nil {
+			return []*UINode{node}
+		}
+		return nil
 	case "advanced":
 		return a.collectMatchingNodes(root, func(n *UINode) bool {
 			return a.matchAdvancedQuery(n, selector.Value)
@@ -193,116 +254,55 @@ func (a *App) findElementByContains(root *UINode, text string, index int) *UINod
 	return nil
 }
 
-// findElementByAdvanced finds element using advanced query syntax
-// Supports: "attr:value", "attr~value" (contains), "attr=value" (exact)
-// Boolean: "cond1 AND cond2", "cond1 OR cond2"
-func (a *App) findElementByAdvanced(root *UINode, query string, index int) *UINode {
-	nodes := a.collectMatchingNodes(root, func(n *UINode) bool {
-		return a.matchAdvancedQuery(n, query)
-	})
-	if index < len(nodes) {
-		return nodes[index]
+// findElementsByBounds returns the nodes whose bounds equal value exactly. Layouts can shift by
+// a pixel between dumps, so without an exact match it falls back to the smallest node
+// containing the center of value.
+func (a *App) findElementsByBounds(root *UINode, value string) []*UINode {
+	rect, err := ParseBounds(value)
+	if err != nil {
+		return nil
 	}
-	return nil
-}
-
-// matchAdvancedQuery evaluates an advanced query against a node
-func (a *App) matchAdvancedQuery(node *UINode, query string) bool {
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return false
+	exact := a.collectMatchingNodes(root, func(n *UINode) bool {
+		b, err := ParseBounds(n.Bounds)
+		return err == nil && *b == *rect
+	})
+	if len(exact) > 0 {
+		return exact
 	}
 
-	// Handle OR (lower precedence)
-	orParts := splitAdvancedQuery(query, " OR ")
-	if len(orParts) > 1 {
-		for _, part := range orParts {
-			if a.matchAdvancedQuery(node, part) {
-				return true
-			}
+	cx, cy := rect.Center()
+	var best *UINode
+	bestArea := 0
+	a.collectMatchingNodes(root, func(n *UINode) bool {
+		if b, err := ParseBounds(n.Bounds); err == nil && b.Contains(cx, cy) && (best == nil || b.Area() < bestArea) {
+			best, bestArea = n, b.Area()
 		}
 		return false
+	})
+	if best == nil {
+		return nil
 	}
-
-	// Handle AND (higher precedence)
-	andParts := splitAdvancedQuery(query, " AND ")
-	if len(andParts) > 1 {
-		for _, part := range andParts {
-			if !a.matchAdvancedQuery(node, part) {
-				return false
-			}
-		}
-		return true
-	}
-
-	// Single condition: "attr:value", "attr~value", "attr=value", "attr^value", "attr$value"
-	return a.evaluateAdvancedCondition(node, query)
+	return []*UINode{best}
 }
 
-// splitAdvancedQuery splits query by separator (case insensitive)
-func splitAdvancedQuery(query, sep string) []string {
-	// Case insensitive split
-	lowerQuery := strings.ToLower(query)
-	lowerSep := strings.ToLower(sep)
-
-	var parts []string
-	start := 0
-	for {
-		idx := strings.Index(lowerQuery[start:], lowerSep)
-		if idx == -1 {
-			parts = append(parts, strings.TrimSpace(query[start:]))
-			break
-		}
-		parts = append(parts, strings.TrimSpace(query[start:start+idx]))
-		start += idx + len(sep)
+// findElementByAdvanced finds element using advanced query syntax (see advanced_query.go)
+func (a *App) findElementByAdvanced(root *UINode, query string, index int) *UINode {
+	nodes := a.collectMatchingNodes(root, func(n *UINode) bool {
+		return a.matchAdvancedQuery(n, query)
+	})
+	if index < len(nodes) {
+		return nodes[index]
 	}
-	return parts
+	return nil
 }
 
-// evaluateAdvancedCondition evaluates a single condition
-func (a *App) evaluateAdvancedCondition(node *UINode, condition string) bool {
-	condition = strings.TrimSpace(condition)
-
-	// Find operator: ~, ^, $, =, :
-	operators := []string{"~", "^", "$", "=", ":"}
-	var attr, op, value string
-
-	for _, operator := range operators {
-		idx := strings.Index(condition, operator)
-		if idx != -1 {
-			attr = strings.TrimSpace(condition[:idx])
-			op = operator
-			value = strings.TrimSpace(condition[idx+1:])
-			break
-		}
-	}
-
-	// No operator found - treat as text contains search
-	if attr == "" {
-		lowerCond := strings.ToLower(condition)
-		return strings.Contains(strings.ToLower(node.Text), lowerCond) ||
-			strings.Contains(strings.ToLower(node.ContentDesc), lowerCond) ||
-			strings.Contains(strings.ToLower(node.ResourceID), lowerCond)
-	}
-
-	// Get attribute value from node
-	attrValue := a.getNodeAttribute(node, attr)
-	lowerAttrValue := strings.ToLower(attrValue)
-	lowerValue := strings.ToLower(value)
-
-	// Evaluate based on operator
-	switch op {
-	case "=":
-		return lowerAttrValue == lowerValue
-	case ":", "~":
-		return strings.Contains(lowerAttrValue, lowerValue)
-	case "^":
-		return strings.HasPrefix(lowerAttrValue, lowerValue)
-	case "$":
-		return strings.HasSuffix(lowerAttrValue, lowerValue)
-	default:
+// matchAdvancedQuery evaluates an advanced query against a node; invalid queries match nothing
+func (a *App) matchAdvancedQuery(node *UINode, query string) bool {
+	q, err := compileAdvancedQuery(query)
+	if err != nil {
 		return false
 	}
+	return q.match(a, node)
 }
 
 // Note: getNodeAttribute is defined in automation.go and reused here
@@ -384,7 +384,18 @@ func (a *App) GenerateSelectorSuggestions(node *UINode, root *UINode) []Selector
 		})
 	}
 
-	// 4. Class selector (lower priority)
+	// 4. Compound selector scoped to a uniquely named ancestor, when nothing above is unique
+	if compound := a.compoundSelector(node, root); compound != nil {
+		suggestions = append(suggestions, SelectorSuggestion{
+			Type:        compound.Type,
+			Value:       compound.Value,
+			Priority:    4,
+			Description: describeCompoundSelector(compound),
+			Selector:    compound,
+		})
+	}
+
+	// 5. Class selector (lower priority)
 	if node.Class != "" {
 		shortClass := node.Class
 		if parts := strings.Split(node.Class, "."); len(parts) > 0 {
@@ -398,7 +409,7 @@ func (a *App) GenerateSelectorSuggestions(node *UINode, root *UINode) []Selector
 		})
 	}
 
-	// 5. XPath selector (fallback, fragile)
+	// 6. XPath selector (fallback, fragile)
 	xpath := a.buildXPath(root, node)
 	if xpath != "" {
 		suggestions = append(suggestions, SelectorSuggestion{
@@ -409,7 +420,7 @@ func (a *App) GenerateSelectorSuggestions(node *UINode, root *UINode) []Selector
 		})
 	}
 
-	// 6. Bounds selector
+	// 7. Bounds selector
 	if node.Bounds != "" {
 		suggestions = append(suggestions, SelectorSuggestion{
 			Type:        "bounds",
@@ -433,7 +444,7 @@ func (a *App) GenerateSelectorSuggestions(node *UINode, root *UINode) []Selector
 
 // GetBestSelector returns the best selector for an element
 func (a *App) GetBestSelector(node *UINode, root *UINode) *ElementSelector {
-	// Priority: unique text > unique id > desc > xpath > bounds
+	// Priority: unique text > unique id > desc > within ancestor > xpath > bounds
 	if node.Text != "" && a.isSelectorUnique(root, "text", node.Text) && !isGenericText(node.Text) {
 		return &ElementSelector{Type: "text", Value: node.Text}
 	}
@@ -443,6 +454,10 @@ func (a *App) GetBestSelector(node *UINode, root *UINode) *ElementSelector {
 	if node.ContentDesc != "" && a.isSelectorUnique(root, "desc", node.ContentDesc) {
 		return &ElementSelector{Type: "desc", Value: node.ContentDesc}
 	}
+	// Scoped to a uniquely named ancestor
+	if compound := a.compoundSelector(node, root); compound != nil {
+		return compound
+	}
 	// Fallback to xpath
 	xpath := a.buildXPath(root, node)
 	if xpath != "" {
@@ -466,5 +481,213 @@ func (a *App) GetSelectorMatchCount(root *UINode, selector *ElementSelector) int
 	if selector == nil {
 		return 0
 	}
+	if selector.Type == "bounds" || selector.Within != nil || selector.ChildIndex != nil {
+		return len(a.FindAllElementsBySelector(root, selector))
+	}
 	return a.countMatchingNodes(root, selector.Type, selector.Value)
 }
+
+// maxCompoundDepth is how many levels up compoundSelector looks for a named ancestor
+const maxCompoundDepth = 3
+
+// compoundSelector scopes node under its nearest ancestor (up to maxCompoundDepth levels) that
+// has a unique text, id or description, or else holds a uniquely titled descendant, e.g. the
+// delete button inside the "Work profile" row. It returns nil when the node's own attributes
+// are already unique or no such ancestor exists.
+func (a *App) compoundSelector(node *UINode, root *UINode) *ElementSelector {
+	own := a.attributeSelectors(node)
+	for _, sel := range own {
+		if sel.Type != "class" && a.isSelectorUnique(root, sel.Type, sel.Value) {
+			return nil
+		}
+	}
+
+	ancestors := nodeAncestors(root, node)
+	for depth := 0; depth < len(ancestors) && depth < maxCompoundDepth; depth++ {
+		anc := ancestors[depth]
+		if isSyntheticRoot(anc) {
+			break
+		}
+		var scope *ElementSelector
+		for _, sel := range a.attributeSelectors(anc) {
+			if sel.Type != "class" && a.isSelectorUnique(root, sel.Type, sel.Value) {
+				scope = &sel
+				break
+			}
+		}
+		if scope == nil {
+			scope = a.containerScope(anc, node, root)
+		}
+		if scope == nil {
+			continue
+		}
+
+		// A unique match inside the scope, else the node's position among same-class matches
+		for _, sel := range own {
+			sel.Within = scope
+			matches := a.FindAllElementsBySelector(root, &sel)
+			for i, m := range matches {
+				if m != node || (len(matches) > 1 && sel.Type != "class") {
+					continue
+				}
+				sel.Index = i
+				return &sel
+			}
+		}
+	}
+	return nil
+}
+
+// containerScopeRegex matches the scopes containerScope builds, capturing the title literal
+var containerScopeRegex = regexp.MustCompile(`^\(//[^\[]+\[\.//\*\[@text=("[^"]*"|'[^']*')\]\]\)\[last\(\)\]$`)
+
+// containerScope selects anc by the first unique text below it outside node, for list rows
+// and cards that have no text or id of their own: the innermost element of anc's class holding
+// that text. It returns nil when no such text exists or the selector would find another element.
+func (a *App) containerScope(anc, node, root *UINode) *ElementSelector {
+	if anc.Class == "" {
+		return nil
+	}
+	title := ""
+	var walk func(n *UINode) bool
+	walk = func(n *UINode) bool {
+		if n == node {
+			return false
+		}
+		if n != anc && n.Text != "" && !isGenericText(n.Text) && a.isSelectorUnique(root, "text", n.Text) {
+			title = n.Text
+			return true
+		}
+		for i := range n.Nodes {
+			if walk(&n.Nodes[i]) {
+				return true
+			}
+		}
+		return false
+	}
+	if !walk(anc) {
+		return nil
+	}
+
+	// Outer containers of the same class hold the text too; the innermost comes last
+	scope := &ElementSelector{Type: "xpath", Value: fmt.Sprintf("(//%s[.//*[@text=%s]])[last()]", anc.Class, xpathLiteral(title))}
+	if a.FindElementBySelector(root, scope) != anc {
+		return nil
+	}
+	return scope
+}
+
+// attributeSelectors lists the single-attribute selectors that describe node, best first
+func (a *App) attributeSelectors(node *UINode) []ElementSelector {
+	var sels []ElementSelector
+	if node.Text != "" && !isGenericText(node.Text) {
+		sels = append(sels, ElementSelector{Type: "text", Value: node.Text})
+	}
+	if node.ResourceID != "" {
+		sels = append(sels, ElementSelector{Type: "id", Value: node.ResourceID})
+	}
+	if node.ContentDesc != "" {
+		sels = append(sels, ElementSelector{Type: "desc", Value: node.ContentDesc})
+	}
+	if node.Class != "" {
+		sels = append(sels, ElementSelector{Type: "class", Value: node.Class})
+	}
+	return sels
+}
+
+// GetNodePath returns the chain from root down to node, node included. node may be a copy of
+// an element of root (as sent by the frontend), in which case it is matched by class, bounds,
+// resource-id, text and description.
+func (a *App) GetNodePath(root, node *UINode) ([]NodePathStep, error) {
+	if root == nil || node == nil {
+		return nil, fmt.Errorf("no node specified")
+	}
+	target := node
+	if nodeAncestors(root, node) == nil && root != node {
+		matches := a.collectMatchingNodes(root, func(n *UINode) bool {
+			return n.Class == node.Class && n.Bounds == node.Bounds && n.ResourceID == node.ResourceID &&
+				n.Text == node.Text && n.ContentDesc == node.ContentDesc
+		})
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("node not found in the hierarchy")
+		}
+		target = matches[0]
+	}
+
+	chain := append(nodeAncestors(root, target), target)
+	// nodeAncestors is nearest first
+	for i, j := 0, len(chain)-2; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	steps := make([]NodePathStep, 0, len(chain))
+	for depth, n := range chain {
+		index := 0
+		if depth > 0 {
+			parent := chain[depth-1]
+			for i := range parent.Nodes {
+				if &parent.Nodes[i] == n {
+					index = i
+					break
+				}
+			}
+		}
+		steps = append(steps, NodePathStep{
+			Class:       n.Class,
+			Index:       index,
+			ResourceID:  n.ResourceID,
+			Text:        n.Text,
+			ContentDesc: n.ContentDesc,
+			Bounds:      n.Bounds,
+			Label:       uiNodeLabel(n),
+		})
+	}
+	return steps, nil
+}
+
+// nodeAncestors returns target's ancestors below root, nearest first, then root itself
+func nodeAncestors(root, target *UINode) []*UINode {
+	var path []*UINode
+	var walk func(n *UINode) bool
+	walk = func(n *UINode) bool {
+		if n == target {
+			return true
+		}
+		for i := range n.Nodes {
+			if walk(&n.Nodes[i]) {
+				path = append(path, n)
+				return true
+			}
+		}
+		return false
+	}
+	walk(root)
+	return path
+}
+
+// describeCompoundSelector renders a chain like "Within 'Work profile' → id:delete", or
+// "Within 'Work profile' row → id:delete" for a container scope
+func describeCompoundSelector(sel *ElementSelector) string {
+	var parts []string
+	for s := sel; s != nil; s = s.Within {
+		step := fmt.Sprintf("%s:%s", s.Type, shortResourceID(s.Value))
+		if s.Index > 0 {
+			step += fmt.Sprintf("[%d]", s.Index)
+		}
+		if s != sel {
+			step = fmt.Sprintf("Within '%s'", shortResourceID(s.Value))
+			if m := containerScopeRegex.FindStringSubmatch(s.Value); m != nil {
+				step = fmt.Sprintf("Within '%s' row", m[1][1:len(m[1])-1])
+			}
+		}
+		parts = append([]string{step}, parts...)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// shortResourceID trims the package from "com.app:id/delete"
+func shortResourceID(value string) string {
+	if i := strings.Index(value, ":id/"); i >= 0 {
+		return value[i+len(":id/"):]
+	}
+	return value
+}
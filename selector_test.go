@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestCompoundSelectorContainerScope(t *testing.T) {
+	root := loadUIDump(t, readTestdata(t, "ui_dump.xml"))
+	a := &App{}
+
+	results := a.SearchElementsXPath(root, `//*[@text='Green tea']/../android.widget.Button`)
+	if len(results) != 1 {
+		t.Fatalf("found %d remove buttons in the Green tea row", len(results))
+	}
+	button := results[0].Node
+
+	sel := a.GetBestSelector(button, root)
+	if sel == nil || sel.Within == nil {
+		t.Fatalf("got %+v, want a selector scoped to the row", sel)
+	}
+	if want := `(//android.widget.LinearLayout[.//*[@text="Green tea"]])[last()]`; sel.Within.Value != want {
+		t.Errorf("scope %s, want %s", sel.Within.Value, want)
+	}
+	if got := a.FindElementBySelector(root, sel); got != button {
+		t.Errorf("selector %+v finds %+v, not the button", sel, got)
+	}
+	if got, want := describeCompoundSelector(sel), "Within 'Green tea' row → text:Remove"; got != want {
+		t.Errorf("description %q, want %q", got, want)
+	}
+}
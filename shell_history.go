@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxShellHistory is how many commands are kept across all devices
+const maxShellHistory = 1000
+
+// shellStore is the on-disk layout of shell_history.json
+type shellStore struct {
+	History  []ShellHistoryEntry `json:"history"`
+	Snippets []ShellSnippet      `json:"snippets"`
+}
+
+var (
+	shellData       *shellStore
+	shellDataMu     sync.Mutex
+	shellDataLoaded bool
+)
+
+func (a *App) getShellStorePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "shell_history.json")
+}
+
+// loadShellStoreLocked reads shell_history.json once; callers must hold shellDataMu
+func (a *App) loadShellStoreLocked() *shellStore {
+	if shellDataLoaded {
+		return shellData
+	}
+	shellDataLoaded = true
+	shellData = &shellStore{}
+
+	data, err := os.ReadFile(a.getShellStorePath())
+	if err != nil {
+		return shellData
+	}
+	_ = json.Unmarshal(data, shellData)
+	return shellData
+}
+
+// saveShellStoreLocked writes shell_history.json; callers must hold shellDataMu
+func (a *App) saveShellStoreLocked() error {
+	data, err := json.MarshalIndent(shellData, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(a.getShellStorePath(), data, 0644)
+}
+
+// RunShellCommand runs a command typed into the shell view and records it in the history.
+// confirmToken is only needed for commands the dangerous-command policy blocks.
+func (a *App) RunShellCommand(deviceId, command, confirmToken string) (string, error) {
+	command = strings.TrimSpace(command)
+	start := time.Now()
+	output, err := a.RunAdbCommandConfirmed(deviceId, command, confirmToken)
+	if errors.Is(err, ErrConfirmationRequired) {
+		return output, err
+	}
+	if command != "" {
+		entry := ShellHistoryEntry{
+			DeviceID:   a.serialFor(deviceId),
+			Command:    command,
+			ExitCode:   shellCommandExitCode(err),
+			Timestamp:  start.Unix(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		go a.appendShellHistory(entry)
+	}
+	return output, err
+}
+
+// shellCommandExitCode maps a RunAdbCommand error to the process exit code, -1 when adb
+// didn't run at all
+func shellCommandExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// appendShellHistory adds a command to the history, folding it into the previous entry when
+// the same command was just run on the same device
+func (a *App) appendShellHistory(entry ShellHistoryEntry) {
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	for i := len(store.History) - 1; i >= 0; i-- {
+		prev := &store.History[i]
+		if prev.DeviceID != entry.DeviceID {
+			continue
+		}
+		if prev.Command == entry.Command {
+			entry.Count = prev.Count + 1
+			store.History = append(store.History[:i], store.History[i+1:]...)
+		}
+		break
+	}
+	if entry.Count == 0 {
+		entry.Count = 1
+	}
+	store.History = append(store.History, entry)
+	if len(store.History) > maxShellHistory {
+		store.History = store.History[len(store.History)-maxShellHistory:]
+	}
+	if err := a.saveShellStoreLocked(); err != nil {
+		a.Log("Failed to save shell history: %v", err)
+	}
+}
+
+// GetShellHistory returns the device's most recent commands, newest first. An empty device
+// returns history from all devices; limit <= 0 returns everything.
+func (a *App) GetShellHistory(deviceId string, limit int) []ShellHistoryEntry {
+	return a.SearchShellHistory(deviceId, "", limit)
+}
+
+// SearchShellHistory is GetShellHistory restricted to commands containing query, ignoring case
+func (a *App) SearchShellHistory(deviceId, query string, limit int) []ShellHistoryEntry {
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	serial := ""
+	if deviceId != "" {
+		serial = a.serialFor(deviceId)
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	result := []ShellHistoryEntry{}
+	for i := len(store.History) - 1; i >= 0; i-- {
+		entry := store.History[i]
+		if serial != "" && entry.DeviceID != serial {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(entry.Command), query) {
+			continue
+		}
+		result = append(result, entry)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// ClearShellHistory forgets the device's history, or all of it when deviceId is empty
+func (a *App) ClearShellHistory(deviceId string) error {
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	if deviceId == "" {
+		store.History = nil
+		return a.saveShellStoreLocked()
+	}
+	serial := a.serialFor(deviceId)
+	kept := store.History[:0]
+	for _, entry := range store.History {
+		if entry.DeviceID != serial {
+			kept = append(kept, entry)
+		}
+	}
+	store.History = kept
+	return a.saveShellStoreLocked()
+}
+
+// SaveShellSnippet stores a named command, replacing any snippet with the same name
+func (a *App) SaveShellSnippet(name, command string) error {
+	name = strings.TrimSpace(name)
+	command = strings.TrimSpace(command)
+	if name == "" {
+		return fmt.Errorf("snippet name is required")
+	}
+	if command == "" {
+		return fmt.Errorf("snippet command is required")
+	}
+
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	for i, s := range store.Snippets {
+		if strings.EqualFold(s.Name, name) {
+			store.Snippets[i].Name = name
+			store.Snippets[i].Command = command
+			return a.saveShellStoreLocked()
+		}
+	}
+	store.Snippets = append(store.Snippets, ShellSnippet{
+		Name:      name,
+		Command:   command,
+		CreatedAt: time.Now().Unix(),
+	})
+	return a.saveShellStoreLocked()
+}
+
+// ListShellSnippets returns the saved snippets sorted by name
+func (a *App) ListShellSnippets() []ShellSnippet {
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	result := append([]ShellSnippet{}, store.Snippets...)
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// DeleteShellSnippet removes a snippet by name
+func (a *App) DeleteShellSnippet(name string) error {
+	shellDataMu.Lock()
+	defer shellDataMu.Unlock()
+	store := a.loadShellStoreLocked()
+
+	for i, s := range store.Snippets {
+		if strings.EqualFold(s.Name, name) {
+			store.Snippets = append(store.Snippets[:i], store.Snippets[i+1:]...)
+			return a.saveShellStoreLocked()
+		}
+	}
+	return fmt.Errorf("snippet not found: %s", name)
+}
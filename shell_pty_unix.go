@@ -0,0 +1,30 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/creack/pty"
+)
+
+// ptyTerminal is the master side of a local pty; adb sees a real terminal and asks the device
+// for one, so window size changes travel with the shell protocol
+type ptyTerminal struct {
+	*os.File
+}
+
+func (t ptyTerminal) Resize(cols, rows int) error {
+	return pty.Setsize(t.File, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// startShellTerminal starts an interactive adb shell attached to a new pty
+func (a *App) startShellTerminal(deviceId string, cols, rows int) (*exec.Cmd, shellTerminal, error) {
+	cmd := a.newAdbCommand(nil, "-s", deviceId, "shell")
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+	if err != nil {
+		return nil, nil, err
+	}
+	return cmd, ptyTerminal{f}, nil
+}
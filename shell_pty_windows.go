@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"io"
+	"os/exec"
+)
+
+// pipeTerminal talks to adb over plain pipes. -tt makes the device allocate its pty anyway, so
+// interactive tools still work, but the window size stays whatever the device picked.
+type pipeTerminal struct {
+	io.Reader
+	io.WriteCloser
+}
+
+func (t pipeTerminal) Resize(cols, rows int) error {
+	return nil
+}
+
+// startShellTerminal starts an interactive adb shell with a device-side pty
+func (a *App) startShellTerminal(deviceId string, cols, rows int) (*exec.Cmd, shellTerminal, error) {
+	cmd := a.newAdbCommand(nil, "-s", deviceId, "shell", "-tt")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Output and errors share one stream, as they would on a terminal
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	go func() {
+		_ = cmd.Wait()
+		writer.Close()
+	}()
+	return cmd, pipeTerminal{Reader: reader, WriteCloser: stdin}, nil
+}
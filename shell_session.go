@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultShellCols = 80
+	defaultShellRows = 24
+)
+
+// shellTerminal is the local end of an interactive adb shell
+type shellTerminal interface {
+	io.ReadWriteCloser
+	Resize(cols, rows int) error
+}
+
+// shellSession is a persistent `adb shell` attached to a terminal; output is streamed to the
+// frontend as shell-output:<id> events until the shell exits or is closed
+type shellSession struct {
+	ID        string
+	DeviceID  string
+	cmd       *exec.Cmd
+	term      shellTerminal
+	closeOnce sync.Once
+
+	// line is what has been typed since the last Enter, as far as it can be followed;
+	// pending is a line held back by the dangerous-command policy
+	inputMu  sync.Mutex
+	line     []rune
+	lineLost bool
+	pending  string
+}
+
+var shellSeq int64
+
+// OpenShellSession starts an interactive shell on the device and returns its session ID.
+// A device can have any number of sessions open at once.
+func (a *App) OpenShellSession(deviceId string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+
+	cmd, term, err := a.startShellTerminal(deviceId, defaultShellCols, defaultShellRows)
+	if err != nil {
+		return "", fmt.Errorf("failed to start shell: %w", err)
+	}
+	a.trackProcess("shell", cmd, nil)
+
+	a.shellMu.Lock()
+	shellSeq++
+	session := &shellSession{
+		ID:       fmt.Sprintf("shell_%d_%d", time.Now().Unix(), shellSeq),
+		DeviceID: deviceId,
+		cmd:      cmd,
+		term:     term,
+	}
+	a.shellSessions[session.ID] = session
+	a.shellMu.Unlock()
+
+	go a.pumpShellOutput(session)
+	a.Log("Opened shell session %s on %s", session.ID, deviceId)
+	return session.ID, nil
+}
+
+// WriteToShell sends keystrokes or pasted text to the shell. When Enter would run a command
+// the dangerous-command policy blocks, the input stops there, a shell-blocked:<id> event names
+// the command and ConfirmShellCommand releases it.
+func (a *App) WriteToShell(sessionId, data string) error {
+	session, err := a.getShellSession(sessionId)
+	if err != nil {
+		return err
+	}
+
+	session.inputMu.Lock()
+	defer session.inputMu.Unlock()
+	forward, blocked := a.trackShellInput(session, data)
+	if forward != "" {
+		if _, err := io.WriteString(session.term, forward); err != nil {
+			return fmt.Errorf("failed to write to shell: %w", err)
+		}
+	}
+	if blocked != nil {
+		wailsRuntime.EventsEmit(a.ctx, "shell-blocked:"+session.ID, map[string]interface{}{
+			"deviceId":    session.DeviceID,
+			"command":     session.pending,
+			"ruleId":      blocked.ID,
+			"description": blocked.Description,
+		})
+		return fmt.Errorf("%w: command matches %q (%s)", ErrConfirmationRequired, blocked.ID, blocked.Description)
+	}
+	return nil
+}
+
+// ConfirmShellCommand runs the command held back by WriteToShell. confirmToken comes from
+// CheckDangerousCommand for that command.
+func (a *App) ConfirmShellCommand(sessionId, confirmToken string) error {
+	session, err := a.getShellSession(sessionId)
+	if err != nil {
+		return err
+	}
+
+	session.inputMu.Lock()
+	defer session.inputMu.Unlock()
+	if session.pending == "" {
+		return fmt.Errorf("no command is waiting for confirmation")
+	}
+	if err := a.guardDangerousCommand(session.DeviceID, session.pending, confirmToken); err != nil {
+		return err
+	}
+	session.pending = ""
+	session.line = session.line[:0]
+	if _, err := io.WriteString(session.term, "\r"); err != nil {
+		return fmt.Errorf("failed to write to shell: %w", err)
+	}
+	return nil
+}
+
+// trackShellInput follows the line being typed and returns the part of data that may be sent.
+// Tracking is best effort: once the cursor moves or the shell completes or recalls a line, the
+// text is no longer known and that line isn't checked.
+func (a *App) trackShellInput(session *shellSession, data string) (string, *DangerousCommandRule) {
+	// Further input means the warning was dismissed; the blocked text is still on the line, so
+	// another Enter is checked again
+	session.pending = ""
+	for i, r := range data {
+		switch {
+		case r == '\r' || r == '\n':
+			line := string(session.line)
+			lost := session.lineLost
+			session.line = session.line[:0]
+			session.lineLost = false
+			if lost {
+				continue
+			}
+			if rule := a.matchDangerousCommand(line); rule != nil {
+				session.pending = line
+				session.line = append(session.line, []rune(line)...)
+				return data[:i], rule
+			}
+		case r == 0x7f || r == '\b':
+			if n := len(session.line); n > 0 {
+				session.line = session.line[:n-1]
+			}
+		case r == 0x03 || r == 0x15:
+			// Ctrl-C and Ctrl-U discard the line
+			session.line = session.line[:0]
+			session.lineLost = false
+		case r == 0x1b || r == '\t' || r < 0x20:
+			session.lineLost = true
+		default:
+			session.line = append(session.line, r)
+		}
+	}
+	return data, nil
+}
+
+// ResizeShell tells the shell the frontend terminal's size in character cells
+func (a *App) ResizeShell(sessionId string, cols, rows int) error {
+	if cols <= 0 || rows <= 0 || cols > 65535 || rows > 65535 {
+		return fmt.Errorf("invalid terminal size %dx%d", cols, rows)
+	}
+	session, err := a.getShellSession(sessionId)
+	if err != nil {
+		return err
+	}
+	return session.term.Resize(cols, rows)
+}
+
+// CloseShellSession ends the shell; a shell-closed:<id> event follows once it has exited
+func (a *App) CloseShellSession(sessionId string) error {
+	session, err := a.getShellSession(sessionId)
+	if err != nil {
+		return err
+	}
+	a.closeShellSession(session)
+	return nil
+}
+
+func (a *App) getShellSession(sessionId string) (*shellSession, error) {
+	a.shellMu.Lock()
+	defer a.shellMu.Unlock()
+	session, ok := a.shellSessions[sessionId]
+	if !ok {
+		return nil, fmt.Errorf("shell session not found: %s", sessionId)
+	}
+	return session, nil
+}
+
+// closeShellSession closes the terminal and kills adb; the output pump does the rest
+func (a *App) closeShellSession(session *shellSession) {
+	session.closeOnce.Do(func() {
+		_ = session.term.Close()
+		if session.cmd.Process != nil {
+			_ = session.cmd.Process.Kill()
+		}
+	})
+}
+
+// closeAllShellSessions is called on shutdown
+func (a *App) closeAllShellSessions() {
+	a.shellMu.Lock()
+	sessions := make([]*shellSession, 0, len(a.shellSessions))
+	for _, s := range a.shellSessions {
+		sessions = append(sessions, s)
+	}
+	a.shellMu.Unlock()
+
+	for _, s := range sessions {
+		a.closeShellSession(s)
+	}
+}
+
+// pumpShellOutput forwards terminal output until the shell exits. Events carry strings, so a
+// multi-byte character split across reads is held back until the rest of it arrives.
+func (a *App) pumpShellOutput(session *shellSession) {
+	outputEvent := "shell-output:" + session.ID
+	buf := make([]byte, 4096)
+	var pending []byte
+	for {
+		n, err := session.term.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+			cut := utf8CompletePrefix(pending)
+			if cut > 0 {
+				wailsRuntime.EventsEmit(a.ctx, outputEvent, string(pending[:cut]))
+				pending = append(pending[:0], pending[cut:]...)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(pending) > 0 {
+		wailsRuntime.EventsEmit(a.ctx, outputEvent, string(pending))
+	}
+
+	a.closeShellSession(session)
+	_ = session.cmd.Wait()
+	a.untrackProcess(session.cmd)
+
+	a.shellMu.Lock()
+	delete(a.shellSessions, session.ID)
+	a.shellMu.Unlock()
+
+	exitCode := -1
+	if session.cmd.ProcessState != nil {
+		exitCode = session.cmd.ProcessState.ExitCode()
+	}
+	wailsRuntime.EventsEmit(a.ctx, "shell-closed:"+session.ID, map[string]interface{}{
+		"deviceId": session.DeviceID,
+		"exitCode": exitCode,
+	})
+	a.Log("Shell session %s on %s closed", session.ID, session.DeviceID)
+}
+
+// utf8CompletePrefix returns the length of b without a trailing incomplete UTF-8 sequence
+func utf8CompletePrefix(b []byte) int {
+	// A rune is at most 4 bytes, so only the last 3 can start an unfinished one
+	for i := len(b) - 1; i >= 0 && i >= len(b)-3; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if !utf8.FullRune(b[i:]) {
+			return i
+		}
+		break
+	}
+	return len(b)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrNotInSideload is returned by SideloadPackage when the device isn't waiting for a package
+var ErrNotInSideload = errors.New("the device isn't in sideload mode; use RebootToSideload or choose \"Apply update from ADB\" in recovery")
+
+var (
+	// sideloadProgressRegex matches adb's progress line: "serving: 'ota.zip'  (~47%)"
+	sideloadProgressRegex = regexp.MustCompile(`serving: '.*'\s+\(~(\d+)%\)`)
+
+	// sideloadChecksums caches package digests by path, size and mtime, so the checksum shown
+	// before starting isn't computed a second time by SideloadPackage
+	sideloadChecksums   = make(map[string]string)
+	sideloadChecksumsMu sync.Mutex
+)
+
+// InspectSideloadPackage checks that zipPath is a readable zip and returns its size and SHA-256
+// to show before sideloading it
+func (a *App) InspectSideloadPackage(zipPath string) (*SideloadPackageInfo, error) {
+	fi, err := os.Stat(zipPath)
+	if err != nil || fi.IsDir() {
+		return nil, fmt.Errorf("package %s not found", zipPath)
+	}
+	// The central directory sits at the end of the file, so a truncated download fails here
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid zip (incomplete download?): %w", zipPath, err)
+	}
+	info := &SideloadPackageInfo{Path: zipPath, Size: fi.Size()}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "payload.bin":
+			info.Kind = "ab_ota"
+		case "META-INF/com/google/android/update-binary":
+			if info.Kind == "" {
+				info.Kind = "update_zip"
+			}
+		}
+	}
+	zr.Close()
+
+	key := fmt.Sprintf("%s|%d|%d", zipPath, fi.Size(), fi.ModTime().UnixNano())
+	sideloadChecksumsMu.Lock()
+	sum, ok := sideloadChecksums[key]
+	sideloadChecksumsMu.Unlock()
+	if !ok {
+		if sum, err = localChecksum(zipPath, "sha256"); err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", zipPath, err)
+		}
+		sideloadChecksumsMu.Lock()
+		sideloadChecksums[key] = sum
+		sideloadChecksumsMu.Unlock()
+	}
+	info.SHA256 = sum
+	return info, nil
+}
+
+// adbDeviceState returns the state adb devices lists for deviceId ("device", "recovery",
+// "sideload", ...), or "" when it isn't listed
+func (a *App) adbDeviceState(deviceId string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := a.newAdbCommand(ctx, "devices").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == deviceId {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// RebootToSideload reboots into recovery's sideload mode; the device reboots by itself once a
+// package has been applied
+func (a *App) RebootToSideload(deviceId string) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "reboot", "sideload-auto-reboot").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to reboot to sideload: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	a.Log("Rebooted %s to sideload", deviceId)
+	return nil
+}
+
+// isBenignSideloadExit reports whether a non-zero adb sideload exit is the one many recoveries
+// produce by closing the connection once the whole package has been served
+func isBenignSideloadExit(lastPercent int, tail []string) bool {
+	for _, line := range tail {
+		if strings.HasPrefix(line, "Total xfer:") {
+			return true
+		}
+		if lastPercent >= 90 && strings.Contains(line, "failed to read command") {
+			return true
+		}
+	}
+	return false
+}
+
+// SideloadPackage sends an OTA or update zip to a device in sideload mode. The package's checksum
+// is sent as a sideload-checksum event before it starts, progress as sideload-progress, and the
+// outcome as sideload-finished.
+func (a *App) SideloadPackage(deviceId, zipPath string) (*SideloadResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	pkg, err := a.InspectSideloadPackage(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	wailsRuntime.EventsEmit(a.ctx, "sideload-checksum", map[string]interface{}{"deviceId": deviceId, "package": pkg})
+	if state := a.adbDeviceState(deviceId); state != "sideload" {
+		if state == "" {
+			state = "not connected"
+		}
+		return nil, fmt.Errorf("%w (state: %s)", ErrNotInSideload, state)
+	}
+
+	result := &SideloadResult{DeviceID: deviceId, Package: *pkg}
+	start := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := a.newAdbCommand(ctx, "-s", deviceId, "sideload", zipPath)
+	lastPercent := -1
+	tail, err := a.runMergedStreamed("sideload", cmd, cancel, func(line string) {
+		m := sideloadProgressRegex.FindStringSubmatch(line)
+		if m == nil {
+			return
+		}
+		if p, _ := strconv.Atoi(m[1]); p != lastPercent {
+			lastPercent = p
+			wailsRuntime.EventsEmit(a.ctx, "sideload-progress", map[string]interface{}{"deviceId": deviceId, "percent": p})
+		}
+	})
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		if isBenignSideloadExit(lastPercent, tail) {
+			result.Warning = "adb exited with an error after the package was sent; recoveries often close the connection this way"
+			err = nil
+		} else {
+			err = fmt.Errorf("sideload failed: %w, %s", err, strings.Join(tail, "\n"))
+		}
+	}
+	finished := map[string]interface{}{"deviceId": deviceId, "success": err == nil, "warning": result.Warning}
+	if err != nil {
+		finished["error"] = err.Error()
+	}
+	wailsRuntime.EventsEmit(a.ctx, "sideload-finished", finished)
+	if err != nil {
+		return nil, err
+	}
+	a.Log("Sideloaded %s (sha256 %s) to %s in %dms", zipPath, pkg.SHA256, deviceId, result.DurationMs)
+	return result, nil
+}
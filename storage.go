@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Storage Analyzer State
+// storageAnalysis is one AnalyzeRemoteStorage run; the pointer tells a run from the one that
+// replaced it
+type storageAnalysis struct {
+	cancel context.CancelFunc
+}
+
+var (
+	storageAnalyses  = make(map[string]*storageAnalysis)
+	storageAnalyzeMu sync.Mutex
+)
+
+// storageTopN is the number of largest directories returned alongside the tree
+const storageTopN = 20
+
+// AnalyzeRemoteStorage walks rootPath with du and returns a size tree suitable for a treemap
+func (a *App) AnalyzeRemoteStorage(deviceId, rootPath string, depth int) (*StorageAnalysis, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if depth <= 0 {
+		depth = 1
+	}
+	rootPath = path.Clean("/" + rootPath)
+
+	a.CancelStorageAnalysis(deviceId)
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &storageAnalysis{cancel: cancel}
+	storageAnalyzeMu.Lock()
+	storageAnalyses[deviceId] = run
+	storageAnalyzeMu.Unlock()
+	defer func() {
+		storageAnalyzeMu.Lock()
+		if storageAnalyses[deviceId] == run {
+			delete(storageAnalyses, deviceId)
+		}
+		storageAnalyzeMu.Unlock()
+		cancel()
+	}()
+
+	start := time.Now()
+	// Trailing slash follows a symlinked root such as /sdcard
+	script := fmt.Sprintf("du -d %d -k %s 2>&1", depth, shellQuote(rootPath+"/"))
+
+	nodes := map[string]*StorageNode{}
+	var denied []string
+	entries := 0
+	lastBeat := time.Now()
+
+	_, waitErr := a.runStreamedCommand(ctx, deviceId, []string{"shell", script}, func(stream, line string) {
+		if strings.HasPrefix(line, "du:") {
+			if strings.Contains(strings.ToLower(line), "permission denied") {
+				// Format: du: /path/to/dir: Permission denied
+				p := strings.TrimSpace(strings.TrimPrefix(line, "du:"))
+				if idx := strings.LastIndex(p, ":"); idx != -1 {
+					p = p[:idx]
+				}
+				denied = append(denied, path.Clean(p))
+			}
+			return
+		}
+
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			return
+		}
+		kb, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+		if err != nil {
+			return
+		}
+		p := path.Clean(fields[1])
+		nodes[p] = &StorageNode{Path: p, Name: path.Base(p), Size: kb * 1024}
+		entries++
+
+		if time.Since(lastBeat) >= 500*time.Millisecond {
+			lastBeat = time.Now()
+			wailsRuntime.EventsEmit(a.ctx, "storage-analyze-progress", map[string]interface{}{
+				"deviceId": deviceId,
+				"rootPath": rootPath,
+				"entries":  entries,
+				"current":  p,
+			})
+		}
+	})
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("storage analysis cancelled")
+	}
+
+	root, ok := nodes[rootPath]
+	if !ok {
+		if len(nodes) == 0 {
+			if len(denied) > 0 {
+				return nil, fmt.Errorf("%w: %s", ErrPermissionDenied, rootPath)
+			}
+			if waitErr != nil {
+				return nil, fmt.Errorf("failed to analyze storage: %w", waitErr)
+			}
+		}
+		root = &StorageNode{Path: rootPath, Name: path.Base(rootPath)}
+		nodes[rootPath] = root
+	}
+
+	// Link children to parents; du prints children before their parent so order is arbitrary here
+	var all []*StorageNode
+	for p, node := range nodes {
+		if p == rootPath {
+			continue
+		}
+		all = append(all, node)
+		parent := nodes[path.Dir(p)]
+		for parent == nil && path.Dir(p) != p {
+			p = path.Dir(p)
+			parent = nodes[path.Dir(p)]
+		}
+		if parent == nil {
+			parent = root
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	// Flag the deepest tree node that covers each unreadable path
+	for _, p := range denied {
+		for {
+			if node, ok := nodes[p]; ok {
+				node.PermissionDenied = true
+				break
+			}
+			if p == "/" || p == "." || !strings.HasPrefix(p, rootPath) {
+				root.PermissionDenied = true
+				break
+			}
+			p = path.Dir(p)
+		}
+	}
+
+	for _, node := range nodes {
+		sort.Slice(node.Children, func(i, j int) bool { return node.Children[i].Size > node.Children[j].Size })
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Size > all[j].Size })
+
+	var largest []StorageEntry
+	for _, node := range all {
+		if len(largest) >= storageTopN {
+			break
+		}
+		largest = append(largest, StorageEntry{Path: node.Path, Size: node.Size, PermissionDenied: node.PermissionDenied})
+	}
+
+	return &StorageAnalysis{
+		Root:       root,
+		Largest:    largest,
+		Entries:    entries,
+		Denied:     len(denied),
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// CancelStorageAnalysis stops a running AnalyzeRemoteStorage for the device
+func (a *App) CancelStorageAnalysis(deviceId string) {
+	storageAnalyzeMu.Lock()
+	defer storageAnalyzeMu.Unlock()
+
+	if run, ok := storageAnalyses[deviceId]; ok {
+		run.cancel()
+		delete(storageAnalyses, deviceId)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// syncEntry is a file seen on one side of a folder sync, keyed by its slash-separated relative path
+type syncEntry struct {
+	Size    int64
+	ModTime int64
+}
+
+// SyncFolderToDevice mirrors localDir onto remoteDir, pushing only new or changed files
+func (a *App) SyncFolderToDevice(deviceId, localDir, remoteDir string, deleteExtraneous bool) (*SyncSummary, error) {
+	return a.syncFolder(deviceId, localDir, remoteDir, "push", deleteExtraneous)
+}
+
+// SyncFolderFromDevice mirrors remoteDir into localDir, pulling only new or changed files
+func (a *App) SyncFolderFromDevice(deviceId, remoteDir, localDir string, deleteExtraneous bool) (*SyncSummary, error) {
+	return a.syncFolder(deviceId, localDir, remoteDir, "pull", deleteExtraneous)
+}
+
+func (a *App) syncFolder(deviceId, localDir, remoteDir, direction string, deleteExtraneous bool) (*SyncSummary, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	remoteDir = path.Clean("/" + remoteDir)
+
+	if direction == "push" {
+		if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("local folder not found: %s", localDir)
+		}
+	} else if err := os.MkdirAll(localDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create local folder: %w", err)
+	}
+
+	local, err := listLocalSyncEntries(localDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := a.listRemoteSyncEntries(deviceId, remoteDir)
+	if err != nil {
+		return nil, err
+	}
+
+	source, target := local, remote
+	if direction == "pull" {
+		source, target = remote, local
+	}
+
+	var changed []string
+	summary := &SyncSummary{Direction: direction}
+	for rel, src := range source {
+		dst, ok := target[rel]
+		// adb push keeps the source mtime and we restore it after pulls, so equal size+mtime means unchanged
+		if ok && dst.Size == src.Size && absInt64(dst.ModTime-src.ModTime) <= 1 {
+			summary.Skipped++
+			continue
+		}
+		changed = append(changed, rel)
+	}
+
+	var extraneous []string
+	if deleteExtraneous {
+		for rel := range target {
+			if _, ok := source[rel]; !ok {
+				extraneous = append(extraneous, rel)
+			}
+		}
+	}
+
+	syncId, ctx := beginTransfer()
+	defer endTransfer(syncId)
+	summary.SyncID = syncId
+
+	total := len(changed) + len(extraneous)
+	emit := func(index int, action, rel string) {
+		wailsRuntime.EventsEmit(a.ctx, "folder-sync-progress", map[string]interface{}{
+			"syncId":    syncId,
+			"deviceId":  deviceId,
+			"direction": direction,
+			"action":    action,
+			"file":      rel,
+			"index":     index,
+			"count":     total,
+		})
+	}
+
+	start := time.Now()
+	for i, rel := range changed {
+		if ctx.Err() != nil {
+			break
+		}
+		emit(i, "copy", rel)
+
+		f := transferFile{
+			Remote: path.Join(remoteDir, rel),
+			Local:  filepath.Join(localDir, filepath.FromSlash(rel)),
+			Size:   source[rel].Size,
+		}
+		if err := a.syncOneFile(ctx, syncId, deviceId, direction, f); err != nil {
+			a.Log("Sync %s failed for %s: %v", direction, rel, err)
+			summary.Failed = append(summary.Failed, rel)
+			continue
+		}
+		if direction == "pull" {
+			mtime := time.Unix(source[rel].ModTime, 0)
+			_ = os.Chtimes(f.Local, mtime, mtime)
+		}
+		summary.Copied++
+		summary.Bytes += f.Size
+	}
+
+	for i, rel := range extraneous {
+		if ctx.Err() != nil {
+			break
+		}
+		emit(len(changed)+i, "delete", rel)
+
+		var err error
+		if direction == "push" {
+			_, err = a.DeleteRemotePath(deviceId, path.Join(remoteDir, rel), false, false)
+		} else {
+			err = os.Remove(filepath.Join(localDir, filepath.FromSlash(rel)))
+		}
+		if err != nil {
+			summary.Failed = append(summary.Failed, rel)
+			continue
+		}
+		summary.Deleted++
+	}
+
+	summary.DurationMs = time.Since(start).Milliseconds()
+	summary.Cancelled = ctx.Err() != nil
+
+	if direction == "push" && summary.Copied > 0 {
+		var pushed []transferFile
+		for _, rel := range changed {
+			pushed = append(pushed, transferFile{Remote: path.Join(remoteDir, rel)})
+		}
+		a.scanMediaFiles(deviceId, pushed)
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "folder-sync-complete", summary)
+	return summary, nil
+}
+
+// syncOneFile transfers a single file through the shared transfer engine
+func (a *App) syncOneFile(ctx context.Context, syncId, deviceId, direction string, f transferFile) error {
+	var destSize func(transferFile) int64
+	if direction == "pull" {
+		destSize = func(f transferFile) int64 {
+			if info, err := os.Stat(f.Local); err == nil {
+				return info.Size()
+			}
+			return 0
+		}
+	} else {
+		destSize = func(transferFile) int64 { return 0 }
+	}
+	_, err := a.runTransfer(ctx, syncId, deviceId, direction, []transferFile{f}, destSize, nil)
+	return err
+}
+
+// listLocalSyncEntries indexes regular files under root
+func listLocalSyncEntries(root string) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+	err := filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || !fi.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		entries[filepath.ToSlash(rel)] = syncEntry{Size: fi.Size(), ModTime: fi.ModTime().Unix()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk local folder: %w", err)
+	}
+	return entries, nil
+}
+
+// listRemoteSyncEntries indexes regular files under root with a single batched stat call
+func (a *App) listRemoteSyncEntries(deviceId, root string) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+
+	script := "[ -d " + shellQuote(root) + " ] && find " + shellQuote(root+"/") + " -type f -exec stat -c '%s|%Y|%n' {} +"
+	output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if !strings.Contains(string(output), "|") {
+		// A missing remote folder simply means everything is new
+		if typed := classifyRemoteError(string(output), root); typed != nil && !errors.Is(typed, ErrPathNotFound) {
+			return nil, typed
+		}
+		return entries, nil
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(strings.TrimRight(line, "\r"), "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		size, err1 := strconv.ParseInt(parts[0], 10, 64)
+		mtime, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		rel := strings.TrimPrefix(path.Clean(parts[2]), root+"/")
+		entries[rel] = syncEntry{Size: size, ModTime: mtime}
+	}
+	return entries, nil
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultTraceDurationSec = 10
+	maxTraceDurationSec     = 600
+	defaultTraceBufferMB    = 64
+
+	perfettoTraceDir = "/data/misc/perfetto-traces"
+	perfettoUIHint   = "Open https://ui.perfetto.dev and drag the trace file onto the page"
+)
+
+// traceCategory is what a GUI category turns into: ftrace events for perfetto and atrace
+// categories for both backends
+type traceCategory struct {
+	ftrace []string
+	atrace []string
+}
+
+var traceCategories = map[string]traceCategory{
+	"sched": {
+		ftrace: []string{"sched/sched_switch", "sched/sched_wakeup", "sched/sched_waking", "sched/sched_process_exit", "sched/sched_process_free", "task/task_newtask", "task/task_rename"},
+		atrace: []string{"sched"},
+	},
+	"freq": {
+		ftrace: []string{"power/cpu_frequency", "power/cpu_idle", "power/suspend_resume"},
+		atrace: []string{"freq", "idle"},
+	},
+	"binder": {
+		ftrace: []string{"binder/binder_transaction", "binder/binder_transaction_received", "binder/binder_lock", "binder/binder_locked", "binder/binder_unlock"},
+		atrace: []string{"binder_driver", "aidl"},
+	},
+	"memory": {
+		ftrace: []string{"kmem/rss_stat", "mm_event/mm_event_record", "lowmemorykiller/lowmemory_kill", "oom/oom_score_adj_update"},
+		atrace: []string{"memreclaim"},
+	},
+	"disk": {
+		ftrace: []string{"block/block_rq_issue", "block/block_rq_complete"},
+		atrace: []string{"disk"},
+	},
+	"gfx":    {atrace: []string{"gfx"}},
+	"view":   {atrace: []string{"view"}},
+	"input":  {atrace: []string{"input"}},
+	"am":     {atrace: []string{"am"}},
+	"wm":     {atrace: []string{"wm"}},
+	"dalvik": {atrace: []string{"dalvik"}},
+	"res":    {atrace: []string{"res"}},
+	"hal":    {atrace: []string{"hal"}},
+	"audio":  {atrace: []string{"audio"}},
+	"video":  {atrace: []string{"video"}},
+	"camera": {atrace: []string{"camera"}},
+	"webview": {
+		atrace: []string{"webview"},
+	},
+}
+
+// defaultTraceCategories are used when TraceOptions lists none
+var defaultTraceCategories = []string{"sched", "freq", "gfx", "view", "input", "binder", "am", "wm"}
+
+// systemTrace is a capture in progress, or the last one for the device
+type systemTrace struct {
+	status SystemTraceStatus
+	stop   func() error
+	done   chan struct{}
+}
+
+var (
+	systemTraces   = make(map[string]*systemTrace)
+	systemTracesMu sync.Mutex
+)
+
+// StartSystemTrace records a system trace with perfetto, or with atrace on devices that
+// don't ship perfetto (Android 9 and earlier). It runs in the background until
+// StopSystemTrace or the configured duration; the trace is then pulled into the
+// recordings folder.
+func (a *App) StartSystemTrace(deviceId string, opts TraceOptions) (*SystemTraceStatus, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if opts.DurationSec <= 0 {
+		opts.DurationSec = defaultTraceDurationSec
+	}
+	if opts.DurationSec > maxTraceDurationSec {
+		return nil, fmt.Errorf("duration is limited to %d seconds", maxTraceDurationSec)
+	}
+	if opts.BufferSizeMB <= 0 {
+		opts.BufferSizeMB = defaultTraceBufferMB
+	}
+	if len(opts.Categories) == 0 {
+		opts.Categories = defaultTraceCategories
+	}
+	for _, c := range opts.Categories {
+		if _, ok := traceCategories[c]; !ok {
+			return nil, fmt.Errorf("unknown trace category %q", c)
+		}
+	}
+	if opts.HeapProfile && opts.AppPackage == "" {
+		return nil, fmt.Errorf("heap profiling needs an app package")
+	}
+
+	systemTracesMu.Lock()
+	if t, ok := systemTraces[deviceId]; ok && !t.status.Finished {
+		systemTracesMu.Unlock()
+		return nil, fmt.Errorf("a system trace is already running on %s", deviceId)
+	}
+	systemTracesMu.Unlock()
+
+	dir := opts.OutputDir
+	if dir == "" {
+		dir = a.GetRecordingsDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output folder: %w", err)
+	}
+	model, _ := a.RunAdbCommand(deviceId, "shell getprop ro.product.model")
+
+	backend := "atrace"
+	if a.getSDKInt(deviceId) >= 29 {
+		if out, _ := a.RunAdbCommand(deviceId, "shell command -v perfetto"); strings.Contains(out, "perfetto") {
+			backend = "perfetto"
+		}
+	}
+	if opts.HeapProfile && backend != "perfetto" {
+		return nil, fmt.Errorf("heap profiling needs perfetto (Android 10+)")
+	}
+
+	stamp := time.Now().UnixNano()
+	t := &systemTrace{
+		status: SystemTraceStatus{
+			DeviceID:    deviceId,
+			Backend:     backend,
+			State:       "running",
+			StartedAt:   time.Now().Unix(),
+			DurationSec: opts.DurationSec,
+			Categories:  opts.Categories,
+			AppPackage:  opts.AppPackage,
+		},
+		done: make(chan struct{}),
+	}
+
+	var run func() error
+	if backend == "perfetto" {
+		t.status.RemotePath = fmt.Sprintf("%s/gaze_%d.pftrace", perfettoTraceDir, stamp)
+		t.status.LocalPath = filepath.Join(dir, recordingFileName(model, "pftrace"))
+		run, t.stop = a.perfettoTrace(deviceId, t.status.RemotePath, buildPerfettoConfig(opts))
+	} else {
+		t.status.RemotePath = fmt.Sprintf("/data/local/tmp/gaze_%d.atrace", stamp)
+		t.status.LocalPath = filepath.Join(dir, recordingFileName(model, "atrace"))
+		var err error
+		run, t.stop, err = a.atraceTrace(deviceId, t.status.RemotePath, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	systemTracesMu.Lock()
+	systemTraces[deviceId] = t
+	systemTracesMu.Unlock()
+	wailsRuntime.EventsEmit(a.ctx, "system-trace-started", t.status)
+
+	go func() {
+		err := run()
+		a.finishSystemTrace(t, err)
+	}()
+
+	status := t.status
+	return &status, nil
+}
+
+// perfettoTrace returns a runner that feeds config to perfetto and blocks until the trace has
+// been written, and a stop function that ends it early. perfetto flushes on SIGINT.
+func (a *App) perfettoTrace(deviceId, remotePath, config string) (func() error, func() error) {
+	run := func() error {
+		cmd := a.newAdbCommand(nil, "-s", deviceId, "shell", "perfetto --txt -c - -o "+shellQuote(remotePath))
+		cmd.Stdin = strings.NewReader(config)
+		output, err := a.runTrackedCommand("perfetto", cmd)
+		if err != nil {
+			return fmt.Errorf("perfetto failed: %w, %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+		}
+		return nil
+	}
+	stop := func() error {
+		// The bracket keeps the pattern from matching the shell that runs pkill
+		pattern := "[p]erfetto.*" + remotePath
+		return a.newAdbCommand(nil, "-s", deviceId, "shell", "pkill -INT -f "+shellQuote(pattern)).Run()
+	}
+	return run, stop
+}
+
+// atraceTrace starts atrace asynchronously and returns a runner that waits out the duration
+// (or a stop request) and then collects the trace
+func (a *App) atraceTrace(deviceId, remotePath string, opts TraceOptions) (func() error, func() error, error) {
+	args := []string{"atrace", "--async_start", "-b", fmt.Sprintf("%d", opts.BufferSizeMB*1024)}
+	if opts.AppPackage != "" {
+		args = append(args, "-a", shellQuote(opts.AppPackage))
+	}
+	args = append(args, atraceCategories(opts.Categories)...)
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(args, " ")).CombinedOutput(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start atrace: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	stopCh := make(chan struct{})
+	var once sync.Once
+	run := func() error {
+		select {
+		case <-stopCh:
+		case <-time.After(time.Duration(opts.DurationSec) * time.Second):
+		}
+		out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "atrace --async_stop -o "+shellQuote(remotePath)).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to stop atrace: %w, %s", err, lastLines(strings.TrimSpace(string(out)), 3))
+		}
+		return nil
+	}
+	stop := func() error {
+		once.Do(func() { close(stopCh) })
+		return nil
+	}
+	return run, stop, nil
+}
+
+// buildPerfettoConfig renders opts as a perfetto text config
+func buildPerfettoConfig(opts TraceOptions) string {
+	ftraceSet := map[string]bool{}
+	atraceSet := map[string]bool{}
+	for _, c := range opts.Categories {
+		for _, e := range traceCategories[c].ftrace {
+			ftraceSet[e] = true
+		}
+		for _, e := range traceCategories[c].atrace {
+			atraceSet[e] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "buffers { size_kb: %d fill_policy: RING_BUFFER }\n", opts.BufferSizeMB*1024)
+	b.WriteString("data_sources { config { name: \"linux.ftrace\" ftrace_config {\n")
+	for _, e := range sortedKeys(ftraceSet) {
+		fmt.Fprintf(&b, "  ftrace_events: %q\n", e)
+	}
+	for _, c := range sortedKeys(atraceSet) {
+		fmt.Fprintf(&b, "  atrace_categories: %q\n", c)
+	}
+	if opts.AppPackage != "" {
+		fmt.Fprintf(&b, "  atrace_apps: %q\n", opts.AppPackage)
+	}
+	b.WriteString("} } }\n")
+	// Process and thread names, so the timeline isn't just PIDs
+	b.WriteString("data_sources { config { name: \"linux.process_stats\" process_stats_config { scan_all_processes_on_start: true } } }\n")
+	if opts.HeapProfile {
+		b.WriteString("data_sources { config { name: \"android.heapprofd\" heapprofd_config {\n")
+		b.WriteString("  sampling_interval_bytes: 4096\n  shmem_size_bytes: 8388608\n  block_client: true\n")
+		fmt.Fprintf(&b, "  process_cmdline: %q\n", opts.AppPackage)
+		b.WriteString("} } }\n")
+	}
+	fmt.Fprintf(&b, "duration_ms: %d\n", opts.DurationSec*1000)
+	return b.String()
+}
+
+// atraceCategories maps GUI categories to atrace's, without duplicates
+func atraceCategories(categories []string) []string {
+	set := map[string]bool{}
+	for _, c := range categories {
+		for _, e := range traceCategories[c].atrace {
+			set[e] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// finishSystemTrace pulls the trace, removes it from the device and publishes the result
+func (a *App) finishSystemTrace(t *systemTrace, runErr error) {
+	deviceId := t.status.DeviceID
+	systemTracesMu.Lock()
+	t.status.State = "pulling"
+	systemTracesMu.Unlock()
+
+	err := runErr
+	out, pullErr := a.newAdbCommand(nil, "-s", deviceId, "pull", t.status.RemotePath, t.status.LocalPath).CombinedOutput()
+	_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "rm -f "+shellQuote(t.status.RemotePath)).Run()
+	if pullErr != nil {
+		// A trace stopped early still gets written; only a missing file is a failure
+		err = fmt.Errorf("failed to pull trace: %w, %s", pullErr, strings.TrimSpace(string(out)))
+		if runErr != nil {
+			err = runErr
+		}
+	} else if fi, statErr := os.Stat(t.status.LocalPath); statErr != nil || fi.Size() == 0 {
+		_ = os.Remove(t.status.LocalPath)
+		err = fmt.Errorf("trace file is empty")
+	} else {
+		err = nil
+		if runErr != nil {
+			a.Log("System trace on %s ended with %v but produced a file", deviceId, runErr)
+		}
+	}
+
+	systemTracesMu.Lock()
+	t.status.Finished = true
+	t.status.FinishedAt = time.Now().Unix()
+	if err != nil {
+		t.status.State = "failed"
+		t.status.Error = err.Error()
+		t.status.LocalPath = ""
+	} else {
+		t.status.State = "done"
+		t.status.Hint = perfettoUIHint
+	}
+	status := t.status
+	systemTracesMu.Unlock()
+	close(t.done)
+
+	a.Log("System trace on %s %s", deviceId, status.State)
+	wailsRuntime.EventsEmit(a.ctx, "system-trace-finished", status)
+}
+
+// StopSystemTrace ends the device's trace early and waits for the file to be pulled
+func (a *App) StopSystemTrace(deviceId string) (*SystemTraceStatus, error) {
+	systemTracesMu.Lock()
+	t, ok := systemTraces[deviceId]
+	if ok && !t.status.Finished {
+		t.status.State = "stopping"
+	}
+	systemTracesMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no system trace on %s", deviceId)
+	}
+
+	if err := t.stop(); err != nil {
+		a.Log("Stopping system trace on %s: %v", deviceId, err)
+	}
+	select {
+	case <-t.done:
+	case <-time.After(2 * time.Minute):
+		return nil, fmt.Errorf("trace did not finish in time")
+	}
+
+	systemTracesMu.Lock()
+	status := t.status
+	systemTracesMu.Unlock()
+	if status.Error != "" {
+		return &status, fmt.Errorf("%s", status.Error)
+	}
+	return &status, nil
+}
+
+// GetSystemTraceStatus returns the device's running or most recent trace, or nil
+func (a *App) GetSystemTraceStatus(deviceId string) *SystemTraceStatus {
+	systemTracesMu.Lock()
+	defer systemTracesMu.Unlock()
+	t, ok := systemTraces[deviceId]
+	if !ok {
+		return nil
+	}
+	status := t.status
+	return &status
+}
+
+// stopAllSystemTraces asks running traces to stop on shutdown; there's no time to pull them
+func (a *App) stopAllSystemTraces() {
+	systemTracesMu.Lock()
+	var running []*systemTrace
+	for _, t := range systemTraces {
+		if !t.status.Finished {
+			running = append(running, t)
+		}
+	}
+	systemTracesMu.Unlock()
+	for _, t := range running {
+		_ = t.stop()
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// adbKeyboardIME is the ADBKeyBoard input method, which types whatever it's sent by broadcast
+const adbKeyboardIME = "com.android.adbkeyboard/.AdbIME"
+
+// maxClearChars caps ClearTextField so a bad count can't keep the device busy for minutes
+const maxClearChars = 1000
+
+// fallbackTextCommands types what `input text` and key events can represent: printable ASCII,
+// newlines and tabs. Everything else is returned as dropped, once per distinct character.
+func fallbackTextCommands(text string) (cmds []string, dropped []string) {
+	seen := map[rune]bool{}
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			// `input text` turns %s into a space; the whole argument is single-quoted for the shell
+			cmds = append(cmds, "input text "+shellQuote(strings.ReplaceAll(run.String(), " ", "%s")))
+			run.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r <= 0x7e:
+			run.WriteRune(r)
+		case r == '\n':
+			flush()
+			cmds = append(cmds, "input keyevent 66")
+		case r == '\t':
+			flush()
+			cmds = append(cmds, "input keyevent 61")
+		default:
+			if !seen[r] {
+				seen[r] = true
+				dropped = append(dropped, string(r))
+			}
+		}
+	}
+	flush()
+	return cmds, dropped
+}
+
+// TypeText types text into the focused field. Anything beyond ASCII goes through the
+// ADBKeyBoard IME when it's installed, switching to it for the duration if it isn't the active
+// keyboard. Without it, characters `input text` can't handle are dropped and reported.
+func (a *App) TypeText(deviceId, text string) (*TypeTextResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	result := &TypeTextResult{Method: "input_text", Dropped: []string{}}
+	if text == "" {
+		return result, nil
+	}
+
+	if cmds, ok := textInputCommands(text); ok {
+		if err := a.runTextCommands(deviceId, cmds); err != nil {
+			return nil, err
+		}
+		result.Typed = utf8.RuneCountInString(text)
+		return result, nil
+	}
+
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell",
+		"pm list packages com.android.adbkeyboard; echo \"ime=$(settings get secure default_input_method)\"").Output()
+	installed := strings.Contains(string(out), "package:com.android.adbkeyboard")
+	current := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "ime="); ok {
+			current = v
+		}
+	}
+
+	if installed {
+		if current != adbKeyboardIME {
+			if out, err := a.newAdbCommand(nil, "-s", deviceId, "shell",
+				"ime enable "+adbKeyboardIME+" && ime set "+adbKeyboardIME).CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("failed to switch to ADBKeyBoard: %w, %s", err, strings.TrimSpace(string(out)))
+			}
+			defer func() {
+				if current != "" && current != "null" {
+					_ = a.newAdbCommand(nil, "-s", deviceId, "shell", "ime set "+current).Run()
+				}
+			}()
+			// The keyboard has to bind to the focused field before it can type
+			time.Sleep(500 * time.Millisecond)
+		}
+		if err := a.runTextCommands(deviceId, []string{adbKeyboardTextCommand(text)}); err != nil {
+			return nil, err
+		}
+		result.Method = "ime"
+		result.Typed = utf8.RuneCountInString(text)
+		return result, nil
+	}
+
+	cmds, dropped := fallbackTextCommands(text)
+	if err := a.runTextCommands(deviceId, cmds); err != nil {
+		return nil, err
+	}
+	result.Dropped = dropped
+	droppedSet := strings.Join(dropped, "")
+	for _, r := range text {
+		if !strings.ContainsRune(droppedSet, r) {
+			result.Typed++
+		}
+	}
+	return result, nil
+}
+
+// runTextCommands runs typing commands in one adb call. They bypass the dangerous command
+// guard: the text is data for the focused field, not a command.
+func (a *App) runTextCommands(deviceId string, cmds []string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", strings.Join(cmds, " && ")).CombinedOutput()
+	// The field's text changed under any cached UI dump, even if a later command failed
+	a.InvalidateUIDump(deviceId)
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return fmt.Errorf("failed to type text: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ClearTextField deletes charCount characters before the cursor in the focused field, after
+// moving the cursor to the end of it
+func (a *App) ClearTextField(deviceId string, charCount int) error {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if charCount <= 0 {
+		return nil
+	}
+	if charCount > maxClearChars {
+		charCount = maxClearChars
+	}
+	// KEYCODE_MOVE_END, then KEYCODE_DEL; input keyevent takes several codes per call
+	cmds := []string{"input keyevent 123"}
+	for charCount > 0 {
+		n := charCount
+		if n > 50 {
+			n = 50
+		}
+		cmds = append(cmds, "input keyevent"+strings.Repeat(" 67", n))
+		charCount -= n
+	}
+	return a.runTextCommands(deviceId, cmds)
+}
+
+// typeText types a script or workflow text step, failing rather than silently leaving out
+// characters the device can't type
+func (a *App) typeText(deviceId, text string) error {
+	result, err := a.TypeText(deviceId, text)
+	if err != nil {
+		return err
+	}
+	if len(result.Dropped) > 0 {
+		return fmt.Errorf("could not type %q: install the ADBKeyBoard input method for non-ASCII text", strings.Join(result.Dropped, ""))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// maxThumbnailBackoff caps how far a slow or failing device's capture interval can stretch
+const maxThumbnailBackoff = time.Minute
+
+// Thumbnail Stream State
+var (
+	thumbnailCancels = make(map[string]context.CancelFunc)
+	thumbnailMu      sync.Mutex
+)
+
+// StartThumbnailStream periodically emits a small JPEG of the device screen as `device-thumbnail`
+func (a *App) StartThumbnailStream(deviceId string, intervalSec int, maxWidth int) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	if intervalSec <= 0 {
+		intervalSec = 5
+	}
+	if maxWidth <= 0 {
+		maxWidth = 160
+	}
+
+	a.StopThumbnailStream(deviceId)
+	ctx, cancel := context.WithCancel(context.Background())
+	thumbnailMu.Lock()
+	thumbnailCancels[deviceId] = cancel
+	thumbnailMu.Unlock()
+
+	go a.runThumbnailStream(ctx, deviceId, time.Duration(intervalSec)*time.Second, maxWidth)
+	return nil
+}
+
+// StopThumbnailStream stops the thumbnail stream for the device
+func (a *App) StopThumbnailStream(deviceId string) {
+	thumbnailMu.Lock()
+	defer thumbnailMu.Unlock()
+
+	if cancel, ok := thumbnailCancels[deviceId]; ok {
+		cancel()
+		delete(thumbnailCancels, deviceId)
+	}
+}
+
+// runThumbnailStream captures one frame at a time; when a capture outlasts the interval (or
+// fails) the delay doubles so slow devices never accumulate shell processes
+func (a *App) runThumbnailStream(ctx context.Context, deviceId string, interval time.Duration, maxWidth int) {
+	delay := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		start := time.Now()
+		err := a.emitThumbnail(ctx, deviceId, maxWidth)
+		if ctx.Err() != nil {
+			return
+		}
+		elapsed := time.Since(start)
+
+		switch {
+		case err != nil || elapsed > interval:
+			if err != nil {
+				a.Log("Thumbnail capture failed on %s: %v", deviceId, err)
+			}
+			if delay < interval {
+				delay = interval
+			}
+			delay *= 2
+			if delay > maxThumbnailBackoff {
+				delay = maxThumbnailBackoff
+			}
+		default:
+			delay = interval - elapsed
+		}
+	}
+}
+
+// emitThumbnail captures, downscales and publishes a single thumbnail; a dark screen is
+// reported without capturing since it would only produce a black frame
+func (a *App) emitThumbnail(ctx context.Context, deviceId string, maxWidth int) error {
+	on, err := a.isScreenOn(deviceId)
+	if err != nil {
+		return err
+	}
+	if !on {
+		wailsRuntime.EventsEmit(a.ctx, "device-thumbnail", map[string]interface{}{
+			"deviceId":  deviceId,
+			"screenOff": true,
+			"timestamp": time.Now().UnixMilli(),
+		})
+		return nil
+	}
+
+	data, err := a.captureScreenPNG(ctx, deviceId)
+	if err != nil {
+		return err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	thumb := scaleToWidth(img, maxWidth)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 70}); err != nil {
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	wailsRuntime.EventsEmit(a.ctx, "device-thumbnail", map[string]interface{}{
+		"deviceId":  deviceId,
+		"image":     "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+		"width":     thumb.Bounds().Dx(),
+		"height":    thumb.Bounds().Dy(),
+		"timestamp": time.Now().UnixMilli(),
+	})
+	return nil
+}
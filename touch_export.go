@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ExportTouchScript writes a saved script as "sh" (adb commands with sleeps), "python" (the
+// same through subprocess) or "json" (the native format, importable with ImportTouchScript).
+// With an empty destPath a save dialog is shown; the written path is returned, or "" if the
+// dialog was cancelled.
+func (a *App) ExportTouchScript(name, format, destPath string) (string, error) {
+	script, err := a.loadTouchScript(name)
+	if err != nil {
+		return "", err
+	}
+
+	var content []byte
+	var ext, filterName string
+	switch format {
+	case "sh":
+		content, ext, filterName = []byte(exportTouchScriptShell(script)), ".sh", "Shell Script (*.sh)"
+	case "python":
+		content, ext, filterName = []byte(exportTouchScriptPython(script)), ".py", "Python Script (*.py)"
+	case "json":
+		script.Version = touchScriptVersion
+		if content, err = json.MarshalIndent(script, "", "  "); err != nil {
+			return "", fmt.Errorf("failed to marshal script: %w", err)
+		}
+		ext, filterName = ".json", "Gaze Touch Script (*.json)"
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	if destPath == "" {
+		safeName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(script.Name, "_")
+		destPath, err = wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+			DefaultFilename: safeName + ext,
+			Title:           "Export Touch Script",
+			Filters:         []wailsRuntime.FileFilter{{DisplayName: filterName, Pattern: "*" + ext}},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to open save dialog: %w", err)
+		}
+		if destPath == "" {
+			return "", nil
+		}
+	}
+
+	mode := os.FileMode(0644)
+	if format != "json" {
+		mode = 0755
+	}
+	if err := os.WriteFile(destPath, content, mode); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+	return destPath, nil
+}
+
+// ImportTouchScript reads a script exported as JSON, validates it and saves it under its own
+// name, or with a numeric suffix if that name is taken
+func (a *App) ImportTouchScript(path string) (*TouchScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	var script TouchScript
+	if err := dec.Decode(&script); err != nil {
+		return nil, fmt.Errorf("not a touch script: %w", err)
+	}
+	if script.Version > touchScriptVersion {
+		return nil, fmt.Errorf("script version %d is newer than this app supports (%d)", script.Version, touchScriptVersion)
+	}
+	if len(script.Events) == 0 && (script.RawInput == nil || len(script.RawInput.Events) == 0) {
+		return nil, fmt.Errorf("script has no events")
+	}
+	migrateTouchScript(&script)
+	for i, event := range script.Events {
+		if err := validateTouchEvent(&script, event); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+
+	if script.Name == "" {
+		script.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	base := script.Name
+	for n := 2; ; n++ {
+		err := a.SaveTouchScript(script, false)
+		var exists *ScriptExistsError
+		if !errors.As(err, &exists) {
+			if err != nil {
+				return nil, err
+			}
+			return &script, nil
+		}
+		script.Name = fmt.Sprintf("%s (%d)", base, n)
+	}
+}
+
+// exportShellCommands turns a script into device shell commands with the pause, in seconds,
+// that precedes each. Coordinates are left as recorded. Steps that need Gaze itself (element
+// waits) become comments.
+func exportShellCommands(script *TouchScript) (cmds []string, pauses []float64, comments []string) {
+	var last int64
+	add := func(timestamp int64, comment string, device ...string) {
+		pause := float64(timestamp-last) / 1000
+		if pause < 0 {
+			pause = 0
+		}
+		last = timestamp
+		for i, cmd := range device {
+			cmds, comments = append(cmds, cmd), append(comments, comment)
+			if i == 0 {
+				pauses = append(pauses, pause)
+			} else {
+				pauses = append(pauses, 0)
+			}
+		}
+		if len(device) == 0 {
+			cmds, pauses, comments = append(cmds, ""), append(pauses, pause), append(comments, comment)
+		}
+	}
+
+	for _, event := range script.Events {
+		switch event.Type {
+		case "tap":
+			add(event.Timestamp, "", fmt.Sprintf("input tap %d %d", event.X, event.Y))
+		case "double_tap":
+			add(event.Timestamp, "", strings.TrimPrefix(doubleTapCommand(event.X, event.Y), "shell "))
+		case "long_press":
+			duration := event.Duration
+			if duration < 500 {
+				duration = 1000
+			}
+			add(event.Timestamp, "", fmt.Sprintf("input swipe %d %d %d %d %d", event.X, event.Y, event.X, event.Y, duration))
+		case "swipe":
+			duration := event.Duration
+			if duration <= 0 {
+				duration = 300
+			}
+			add(event.Timestamp, "", fmt.Sprintf("input swipe %d %d %d %d %d", event.X, event.Y, event.X2, event.Y2, duration))
+		case "pinch", "multitouch":
+			var parts []string
+			for _, f := range event.Fingers {
+				duration := f.Duration
+				if duration <= 0 {
+					duration = 300
+				}
+				parts = append(parts, fmt.Sprintf("(sleep %.3f; input swipe %d %d %d %d %d) &", float64(f.Delay)/1000, f.X, f.Y, f.X2, f.Y2, duration))
+			}
+			add(event.Timestamp, "", strings.Join(parts, " ")+" wait")
+		case "key":
+			add(event.Timestamp, "", strings.TrimPrefix(keyEventCommand(event), "shell "))
+		case "text":
+			if device, ok := textInputCommands(event.Text); ok {
+				add(event.Timestamp, "", device...)
+			} else {
+				add(event.Timestamp, "non-ASCII text, needs the ADBKeyBoard IME", adbKeyboardTextCommand(event.Text))
+			}
+		case "wait":
+			// Later timestamps already include the wait, so it only needs a marker
+			add(event.Timestamp, fmt.Sprintf("wait %dms", event.Duration))
+		case "waitForElement":
+			what := "appear"
+			if event.Gone {
+				what = "disappear"
+			}
+			selector := ""
+			if event.Selector != nil {
+				selector = event.Selector.Type + "=" + event.Selector.Value
+			}
+			add(event.Timestamp, fmt.Sprintf("not exported: wait for %s to %s", selector, what))
+		}
+	}
+	return cmds, pauses, comments
+}
+
+func exportHeader(script *TouchScript) []string {
+	device := script.DeviceModel
+	if device == "" {
+		device = script.DeviceID
+	}
+	recorded := "Recorded at " + script.Resolution
+	if device != "" {
+		recorded = "Recorded on " + device + " at " + script.Resolution
+	}
+	if script.Orientation != nil && *script.Orientation != 0 {
+		recorded += fmt.Sprintf(", rotation %d", *script.Orientation)
+	}
+	if script.CreatedAt != "" {
+		recorded += ", " + script.CreatedAt
+	}
+	return []string{
+		fmt.Sprintf("Touch script %q exported from Gaze", script.Name),
+		recorded,
+		"Coordinates are as recorded and are not rescaled for other screens",
+	}
+}
+
+// exportTouchScriptShell renders a POSIX sh script taking the device serial as its only argument
+func exportTouchScriptShell(script *TouchScript) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	for _, line := range exportHeader(script) {
+		b.WriteString("# " + line + "\n")
+	}
+	b.WriteString("#\n# Usage: sh " + regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(script.Name, "_") + ".sh [serial]\n")
+	b.WriteString("set -e\n")
+	// "$@" keeps a serial with unusual characters (e.g. host:port) as a single argument
+	b.WriteString("if [ -n \"${1:-}\" ]; then set -- -s \"$1\"; else set --; fi\n\n")
+
+	cmds, pauses, comments := exportShellCommands(script)
+	for i, cmd := range cmds {
+		if pauses[i] > 0 {
+			fmt.Fprintf(&b, "sleep %.3f\n", pauses[i])
+		}
+		if comments[i] != "" {
+			b.WriteString("# " + comments[i] + "\n")
+		}
+		if cmd != "" {
+			b.WriteString("adb \"$@\" shell " + shellQuote(cmd) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// exportTouchScriptPython renders a Python 3 script taking the device serial as its only argument
+func exportTouchScriptPython(script *TouchScript) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env python3\n")
+	for _, line := range exportHeader(script) {
+		b.WriteString("# " + line + "\n")
+	}
+	b.WriteString(`import subprocess
+import sys
+import time
+
+SERIAL = sys.argv[1] if len(sys.argv) > 1 else None
+
+
+def shell(cmd):
+    args = ["adb"] + (["-s", SERIAL] if SERIAL else []) + ["shell", cmd]
+    subprocess.run(args, check=True)
+
+
+`)
+
+	cmds, pauses, comments := exportShellCommands(script)
+	for i, cmd := range cmds {
+		if pauses[i] > 0 {
+			fmt.Fprintf(&b, "time.sleep(%.3f)\n", pauses[i])
+		}
+		if comments[i] != "" {
+			b.WriteString("# " + comments[i] + "\n")
+		}
+		if cmd != "" {
+			b.WriteString("shell(" + strconv.Quote(cmd) + ")\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// geteventMaxLine bounds a single getevent line. Label padding on some kernels overflows
+	// bufio.Scanner's 64KB default, which used to end a recording silently.
+	geteventMaxLine = 1024 * 1024
+	// geteventStartupTimeout is how long a new getevent gets to fail before it counts as running
+	geteventStartupTimeout = time.Second
+)
+
+var (
+	// `getevent -t` output: "[   1234.567890] 0003 0035 0000021c"
+	numericEventRegex    = regexp.MustCompile(`^(\[\s*[\d.]+\]\s*)([0-9a-fA-F]{4}) ([0-9a-fA-F]{4}) ([0-9a-fA-F]{8})\s*$`)
+	geteventUsageMarkers = []string{"Usage:", "usage:", "invalid option", "unknown option"}
+
+	// evdevLabels maps type -> code -> the label `getevent -l` would print
+	evdevLabels = buildEvdevLabels()
+)
+
+func buildEvdevLabels() map[uint16]map[uint16]string {
+	typeOf := map[string]string{"SYN_": "EV_SYN", "BTN_": "EV_KEY", "KEY_": "EV_KEY", "ABS_": "EV_ABS", "MSC_": "EV_MSC"}
+	labels := make(map[uint16]map[uint16]string)
+	add := func(label string, code uint16) {
+		evType, ok := typeOf[label[:4]]
+		if !ok {
+			return
+		}
+		t := evdevTypes[evType]
+		if labels[t] == nil {
+			labels[t] = make(map[uint16]string)
+		}
+		labels[t][code] = label
+	}
+	for label, code := range evdevCodes {
+		add(label, code)
+	}
+	for label, code := range linuxKeyCodes {
+		add(label, code)
+	}
+	return labels
+}
+
+// labelNumericEvent rewrites a `getevent -t` line into the `getevent -lt` form the recording
+// parser expects. Codes without a known label stay as hex, which the parser also accepts.
+func labelNumericEvent(line string) string {
+	m := numericEventRegex.FindStringSubmatch(line)
+	if m == nil {
+		return line
+	}
+	t, _ := strconv.ParseUint(m[2], 16, 16)
+	c, _ := strconv.ParseUint(m[3], 16, 16)
+
+	typeLabel := m[2]
+	for label, v := range evdevTypes {
+		if uint64(v) == t {
+			typeLabel = label
+		}
+	}
+	codeLabel := m[3]
+	if label, ok := evdevLabels[uint16(t)][uint16(c)]; ok {
+		codeLabel = label
+	}
+	return fmt.Sprintf("%s%s %s %s", m[1], typeLabel, codeLabel, m[4])
+}
+
+// geteventStream is a running getevent on one input device. Lines arrive on lines in the
+// labelled form whichever output mode the device supports; done closes once the process
+// has exited and every line has been delivered.
+type geteventStream struct {
+	cmd     *exec.Cmd
+	lines   chan string
+	done    chan struct{}
+	numeric bool
+
+	mu   sync.Mutex
+	head []string // first lines, kept for error messages
+	err  error    // read error, set before lines closes
+}
+
+// startGetevent starts `getevent -lt` on inputDevice and makes sure it is actually running.
+// Toolbox builds without -l print usage and exit; those get `getevent -t` instead.
+func (a *App) startGetevent(ctx context.Context, deviceId, inputDevice, kind string, cancel context.CancelFunc) (*geteventStream, error) {
+	stream, err := a.launchGetevent(ctx, deviceId, inputDevice, false, kind, cancel)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.checkStartup(inputDevice); err != nil {
+		if !stream.printedUsage() {
+			return nil, err
+		}
+		fmt.Printf("[Automation] getevent -lt not supported on %s, using numeric output\n", deviceId)
+		if stream, err = a.launchGetevent(ctx, deviceId, inputDevice, true, kind, cancel); err != nil {
+			return nil, err
+		}
+		if err := stream.checkStartup(inputDevice); err != nil {
+			return nil, err
+		}
+	}
+	return stream, nil
+}
+
+// launchGetevent starts getevent without checking on it
+func (a *App) launchGetevent(ctx context.Context, deviceId, inputDevice string, numeric bool, kind string, cancel context.CancelFunc) (*geteventStream, error) {
+	flag := "-lt"
+	if numeric {
+		flag = "-t"
+	}
+	cmd := exec.CommandContext(ctx, a.adbPath, "-s", deviceId, "shell", "getevent", flag, inputDevice)
+	// Usage errors go to stderr on some builds, so read both
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start getevent: %w", err)
+	}
+	a.trackProcess(kind, cmd, cancel)
+
+	s := &geteventStream{
+		cmd:     cmd,
+		lines:   make(chan string, 4096),
+		done:    make(chan struct{}),
+		numeric: numeric,
+	}
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), geteventMaxLine)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if numeric {
+				line = labelNumericEvent(line)
+			}
+			s.mu.Lock()
+			if len(s.head) < 20 {
+				s.head = append(s.head, line)
+			}
+			s.mu.Unlock()
+			s.lines <- line
+		}
+		s.err = scanner.Err()
+		close(s.lines)
+		// Keep draining so a failed read can't block the process on a full pipe
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	go func() {
+		_ = cmd.Wait()
+		pw.Close()
+		<-readerDone
+		a.untrackProcess(cmd)
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// checkStartup waits briefly for getevent to fail. Opening a single device prints nothing
+// until the first event, so a process still running after the timeout has the device open;
+// a missing device, denied access or an unsupported flag makes it exit before that.
+func (s *geteventStream) checkStartup(inputDevice string) error {
+	select {
+	case <-s.done:
+		return fmt.Errorf("getevent could not read %s: %s", inputDevice, s.headText())
+	case <-time.After(geteventStartupTimeout):
+		return nil
+	}
+}
+
+func (s *geteventStream) printedUsage() bool {
+	text := s.headText()
+	for _, marker := range geteventUsageMarkers {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *geteventStream) headText() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.head) == 0 {
+		return "no output"
+	}
+	return strings.Join(s.head, " | ")
+}
+
+// wait blocks until the process has exited and its output has been read
+func (s *geteventStream) wait() {
+	<-s.done
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// linuxKeyToAndroid maps the key labels `getevent -l` prints to Android KEYCODE_* values
+var linuxKeyToAndroid = map[string]int{
+	"KEY_HOME":       3,
+	"KEY_HOMEPAGE":   3,
+	"KEY_BACK":       4,
+	"KEY_VOLUMEUP":   24,
+	"KEY_VOLUMEDOWN": 25,
+	"KEY_POWER":      26,
+	"KEY_CAMERA":     27,
+	"KEY_MENU":       82,
+	"KEY_SEARCH":     84,
+	"KEY_MUTE":       164,
+	"KEY_APPSELECT":  187,
+	"KEY_ASSISTANT":  219,
+}
+
+// linuxKeyCodes are the evdev codes behind those labels, for `getevent -t` output
+var linuxKeyCodes = map[string]uint16{
+	"KEY_HOME":       102,
+	"KEY_HOMEPAGE":   172,
+	"KEY_BACK":       158,
+	"KEY_VOLUMEUP":   115,
+	"KEY_VOLUMEDOWN": 114,
+	"KEY_POWER":      116,
+	"KEY_CAMERA":     212,
+	"KEY_MENU":       139,
+	"KEY_SEARCH":     217,
+	"KEY_MUTE":       113,
+	"KEY_APPSELECT":  0x244,
+	"KEY_ASSISTANT":  0x247,
+}
+
+// keyDeviceMarkers identify input devices that carry hardware buttons
+var keyDeviceMarkers = []string{"KEY_VOLUMEUP", "KEY_VOLUMEDOWN", "KEY_POWER", "KEY_BACK", "KEY_HOMEPAGE", "KEY_HOME"}
+
+var (
+	// Recording state for the key device streams that run next to the touch stream
+	touchRecordKeyStreams = make(map[string][]*geteventStream)
+
+	rawEventTimeRegex   = regexp.MustCompile(`^\[\s*([\d.]+)\]`)
+	rawEventDeviceRegex = regexp.MustCompile(`^\[\s*[\d.]+\]\s*(/dev/input/\S+):`)
+)
+
+// getKeyInputDevices lists input devices other than exclude that report hardware keys
+// (gpio-keys, qpnp_pon and similar)
+func (a *App) getKeyInputDevices(deviceId, exclude string) []string {
+	output, err := a.RunAdbCommand(deviceId, "shell getevent -lp")
+	if err != nil {
+		return nil
+	}
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	var paths []string
+	for _, block := range strings.Split(output, "add device") {
+		firstLineEnd := strings.Index(block, "\n")
+		if firstLineEnd == -1 {
+			continue
+		}
+		pathIdx := strings.Index(block[:firstLineEnd], "/dev/input/")
+		if pathIdx == -1 {
+			continue
+		}
+		path := strings.TrimSpace(block[pathIdx:firstLineEnd])
+		if path == exclude {
+			continue
+		}
+		for _, marker := range keyDeviceMarkers {
+			if strings.Contains(block, marker) {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// startKeyRecording attaches a getevent stream to each key device, in numeric mode when the
+// touch stream had to fall back to it. Lines are tagged with their device path, the same form
+// `getevent -lt` uses when watching all devices, so parseRawEvents can tell them from touch
+// input. Callers must hold touchRecordMu.
+func (a *App) startKeyRecording(ctx context.Context, deviceId, touchDevice string, numeric bool) {
+	for _, keyDevice := range a.getKeyInputDevices(deviceId, touchDevice) {
+		stream, err := a.launchGetevent(ctx, deviceId, keyDevice, numeric, "touch-recording", nil)
+		if err != nil {
+			fmt.Printf("[Automation] Failed to watch key device %s: %v\n", keyDevice, err)
+			continue
+		}
+		touchRecordKeyStreams[deviceId] = append(touchRecordKeyStreams[deviceId], stream)
+		fmt.Printf("[Automation] Also listening for keys on: %s\n", keyDevice)
+
+		go func(keyDevice string) {
+			for line := range stream.lines {
+				if !strings.Contains(line, "EV_KEY") {
+					continue
+				}
+				if loc := rawEventTimeRegex.FindStringIndex(line); loc != nil {
+					line = line[:loc[1]] + " " + keyDevice + ":" + line[loc[1]:]
+				}
+
+				touchRecordMu.Lock()
+				session, ok := touchRecordData[deviceId]
+				recorded := ok && !session.IsPaused
+				if recorded {
+					session.RawEvents = append(session.RawEvents, line)
+				}
+				touchRecordMu.Unlock()
+
+				if recorded && (strings.Contains(line, " UP") || strings.HasSuffix(strings.TrimSpace(line), "00000000")) {
+					wailsRuntime.EventsEmit(a.ctx, "touch-action-recorded", map[string]interface{}{
+						"deviceId": deviceId,
+					})
+				}
+			}
+		}(keyDevice)
+	}
+}
+
+// stopKeyRecording reaps the key device streams once their context has been cancelled.
+// Callers must not hold touchRecordMu.
+func (a *App) stopKeyRecording(deviceId string) {
+	touchRecordMu.Lock()
+	streams := touchRecordKeyStreams[deviceId]
+	delete(touchRecordKeyStreams, deviceId)
+	touchRecordMu.Unlock()
+
+	for _, stream := range streams {
+		stream.wait()
+	}
+}
+
+// mergeEventStreams orders lines from several getevent streams by their kernel timestamps,
+// keeping arrival order for equal timestamps
+func mergeEventStreams(lines []string) []string {
+	timeOf := func(line string) float64 {
+		if m := rawEventTimeRegex.FindStringSubmatch(line); m != nil {
+			t, _ := strconv.ParseFloat(m[1], 64)
+			return t
+		}
+		return 0
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return timeOf(lines[i]) < timeOf(lines[j]) })
+	return lines
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// evdev type and code numbers for the labels `getevent -l` prints on touch devices
+var (
+	evdevTypes = map[string]uint16{
+		"EV_SYN": 0x00,
+		"EV_KEY": 0x01,
+		"EV_ABS": 0x03,
+		"EV_MSC": 0x04,
+	}
+	evdevCodes = map[string]uint16{
+		"SYN_REPORT":         0x00,
+		"SYN_MT_REPORT":      0x02,
+		"MSC_SCAN":           0x04,
+		"MSC_TIMESTAMP":      0x05,
+		"BTN_TOOL_FINGER":    0x145,
+		"BTN_TOUCH":          0x14a,
+		"ABS_X":              0x00,
+		"ABS_Y":              0x01,
+		"ABS_PRESSURE":       0x18,
+		"ABS_MT_SLOT":        0x2f,
+		"ABS_MT_TOUCH_MAJOR": 0x30,
+		"ABS_MT_TOUCH_MINOR": 0x31,
+		"ABS_MT_WIDTH_MAJOR": 0x32,
+		"ABS_MT_WIDTH_MINOR": 0x33,
+		"ABS_MT_ORIENTATION": 0x34,
+		"ABS_MT_POSITION_X":  0x35,
+		"ABS_MT_POSITION_Y":  0x36,
+		"ABS_MT_TOOL_TYPE":   0x37,
+		"ABS_MT_BLOB_ID":     0x38,
+		"ABS_MT_TRACKING_ID": 0x39,
+		"ABS_MT_PRESSURE":    0x3a,
+		"ABS_MT_DISTANCE":    0x3b,
+	}
+)
+
+// rawReplayMinSleepMs is the smallest gap worth a `sleep`; shorter gaps are absorbed by the
+// time each sendevent invocation takes anyway
+const rawReplayMinSleepMs = 10
+
+// newRawInputEvent converts one labelled getevent entry to numeric form. Labels getevent
+// could not name are printed as hex and parsed as such.
+func newRawInputEvent(timeMs int64, evType, evCode string, value int32) (RawInputEvent, bool) {
+	t, ok := lookupEvdev(evdevTypes, evType)
+	if !ok {
+		return RawInputEvent{}, false
+	}
+	c, ok := lookupEvdev(evdevCodes, evCode)
+	if !ok {
+		return RawInputEvent{}, false
+	}
+	return RawInputEvent{Time: timeMs, Type: t, Code: c, Value: value}, true
+}
+
+func lookupEvdev(table map[string]uint16, label string) (uint16, bool) {
+	if v, ok := table[label]; ok {
+		return v, true
+	}
+	v, err := strconv.ParseUint(label, 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(v), true
+}
+
+// touchPlaybackMode reports how a script will be replayed: "raw" when it asks for it and has
+// a captured event stream, "simple" (input tap/swipe) otherwise
+func touchPlaybackMode(script TouchScript) string {
+	if script.PlaybackMode == "raw" && script.RawInput != nil && len(script.RawInput.Events) > 0 {
+		return "raw"
+	}
+	return "simple"
+}
+
+// playRawTouchScript replays the recorded evdev stream on the target's touch device by piping
+// a generated sendevent script into a device shell. Positions are rescaled from the recorded
+// axis ranges to the target's; key presses, which come from other devices, are interleaved as
+// background `input keyevent` calls.
+func (a *App) playRawTouchScript(ctx context.Context, deviceId string, raw *RawTouchInput, keys []TouchEvent) error {
+	inputDevice, err := a.GetTouchInputDevice(deviceId)
+	if err != nil {
+		return fmt.Errorf("failed to find touch input device: %w", err)
+	}
+	minX, maxX, minY, maxY := a.getTouchAxisRange(deviceId, inputDevice)
+
+	rescale := func(v int32, srcMin, srcMax, dstMin, dstMax int) int32 {
+		if srcMax <= srcMin || dstMax <= dstMin {
+			return v
+		}
+		return int32(dstMin + int(float64(int(v)-srcMin)*float64(dstMax-dstMin)/float64(srcMax-srcMin)+0.5))
+	}
+
+	slots := map[int32]bool{0: true}
+	var b strings.Builder
+	var last int64
+	sleepUntil := func(t int64) {
+		if t-last >= rawReplayMinSleepMs {
+			fmt.Fprintf(&b, "sleep %.3f\n", float64(t-last)/1000)
+			last = t
+		}
+	}
+
+	nextKey := 0
+	for _, ev := range raw.Events {
+		for ; nextKey < len(keys) && keys[nextKey].Timestamp <= ev.Time; nextKey++ {
+			sleepUntil(keys[nextKey].Timestamp)
+			fmt.Fprintf(&b, "%s &\n", strings.TrimPrefix(keyEventCommand(keys[nextKey]), "shell "))
+		}
+		sleepUntil(ev.Time)
+
+		value := ev.Value
+		if ev.Type == evdevTypes["EV_ABS"] {
+			switch ev.Code {
+			case evdevCodes["ABS_MT_POSITION_X"], evdevCodes["ABS_X"]:
+				value = rescale(value, raw.MinX, raw.MaxX, minX, maxX)
+			case evdevCodes["ABS_MT_POSITION_Y"], evdevCodes["ABS_Y"]:
+				value = rescale(value, raw.MinY, raw.MaxY, minY, maxY)
+			case evdevCodes["ABS_MT_SLOT"]:
+				slots[value] = true
+			}
+		}
+		fmt.Fprintf(&b, "sendevent %s %d %d %d\n", inputDevice, ev.Type, ev.Code, value)
+	}
+	for ; nextKey < len(keys); nextKey++ {
+		sleepUntil(keys[nextKey].Timestamp)
+		fmt.Fprintf(&b, "%s &\n", strings.TrimPrefix(keyEventCommand(keys[nextKey]), "shell "))
+	}
+	// Let backgrounded keyevents finish before the shell exits
+	b.WriteString("wait\n")
+
+	fmt.Printf("[Automation] Raw replay: %d events on %s\n", len(raw.Events), inputDevice)
+	cmd := a.newAdbCommand(ctx, "-s", deviceId, "shell", "sh")
+	cmd.Stdin = strings.NewReader(b.String())
+	output, err := a.runTrackedCommand("touch-replay", cmd)
+	if ctx.Err() != nil {
+		// Stopping mid-gesture would leave fingers down; lift every slot that was used
+		a.releaseTouchSlots(deviceId, inputDevice, slots)
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("sendevent replay failed: %w, %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// releaseTouchSlots sends finger-up for the given slots followed by a sync report
+func (a *App) releaseTouchSlots(deviceId, inputDevice string, slots map[int32]bool) {
+	var b strings.Builder
+	for slot := range slots {
+		fmt.Fprintf(&b, "sendevent %s 3 %d %d\n", inputDevice, evdevCodes["ABS_MT_SLOT"], slot)
+		fmt.Fprintf(&b, "sendevent %s 3 %d -1\n", inputDevice, evdevCodes["ABS_MT_TRACKING_ID"])
+	}
+	fmt.Fprintf(&b, "sendevent %s 1 %d 0\n", inputDevice, evdevCodes["BTN_TOUCH"])
+	fmt.Fprintf(&b, "sendevent %s 0 0 0\n", inputDevice)
+
+	cmd := a.newAdbCommand(nil, "-s", deviceId, "shell", "sh")
+	cmd.Stdin = strings.NewReader(b.String())
+	_ = cmd.Run()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useTempScripts points the script library at an empty config dir for the test
+func useTempScripts(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("AppData", dir)
+	resetIndex := func() {
+		scriptIndexMu.Lock()
+		scriptIndexLoaded = false
+		scriptIndex = nil
+		scriptIndexMu.Unlock()
+	}
+	resetIndex()
+	t.Cleanup(resetIndex)
+}
+
+func TestScrollToEventRoundTrip(t *testing.T) {
+	useTempScripts(t)
+	a := &App{}
+
+	script := TouchScript{Name: "feed", Resolution: "1080x2340", Events: []TouchEvent{
+		{Type: "tap", X: 540, Y: 400},
+	}}
+	if err := a.SaveTouchScript(script, false); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	scroll := TouchEvent{
+		Type:      "scrollTo",
+		Timestamp: 500,
+		Selector:  &ElementSelector{Type: "text", Value: "Oat milk"},
+		Container: &ElementSelector{Type: "id", Value: "com.example.shop:id/list"},
+		Direction: "up",
+		MaxSwipes: 5,
+	}
+	if err := a.InsertTouchScriptEvent("feed", 1, scroll); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	saved, err := a.loadTouchScript("feed")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(saved.Events) != 2 || !reflect.DeepEqual(saved.Events[1], scroll) {
+		t.Fatalf("saved events %+v, want the scrollTo appended", saved.Events)
+	}
+
+	// Export as JSON and import it back as a copy
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "feed.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := a.ImportTouchScript(path)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.Name != "feed (2)" || !reflect.DeepEqual(imported.Events[1], scroll) {
+		t.Errorf("imported %q with %+v", imported.Name, imported.Events)
+	}
+
+	for _, bad := range []TouchEvent{
+		{Type: "scrollTo", Direction: "up"},
+		{Type: "scrollTo", Selector: scroll.Selector, Direction: "sideways"},
+		{Type: "scrollTo", Selector: scroll.Selector, MaxSwipes: -1},
+	} {
+		if err := a.InsertTouchScriptEvent("feed", 0, bad); err == nil {
+			t.Errorf("%+v was accepted", bad)
+		}
+	}
+}
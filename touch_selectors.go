@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ConvertScriptToSelectors replays a saved script and, before each tap, double tap and long
+// press, dumps the UI and attaches the best selector for the element under the recorded point.
+// Recorded coordinates are kept as the fallback. The rewritten script is saved and returned so
+// the suggestions can be reviewed. The run counts as the device's playback, so
+// StopTouchPlayback cancels it without saving anything.
+func (a *App) ConvertScriptToSelectors(deviceId, scriptName string) (*TouchScript, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	script, err := a.loadTouchScript(scriptName)
+	if err != nil {
+		return nil, err
+	}
+	transform := a.newTouchTransform(deviceId, script)
+	if err := transform.checkOrientation(script); err != nil {
+		return nil, err
+	}
+
+	touchPlaybackMu.Lock()
+	if _, exists := touchPlaybackCancel[deviceId]; exists {
+		touchPlaybackMu.Unlock()
+		return nil, fmt.Errorf("playback already in progress")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	touchPlaybackCancel[deviceId] = cancel
+	touchPlaybackMu.Unlock()
+	defer func() {
+		cancel()
+		touchPlaybackMu.Lock()
+		delete(touchPlaybackCancel, deviceId)
+		touchPlaybackMu.Unlock()
+	}()
+
+	converted := 0
+	startTime := time.Now()
+	for i := range script.Events {
+		event := &script.Events[i]
+
+		// Keep the recorded pacing so each tap sees the screen it was recorded on
+		if wait := time.Duration(event.Timestamp-time.Since(startTime).Milliseconds()) * time.Millisecond; wait > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("conversion cancelled after %d of %d events", i, len(script.Events))
+		}
+
+		switch event.Type {
+		case "wait":
+			// The pacing above has already waited it out
+			a.emitSelectorConversionProgress(deviceId, i+1, len(script.Events), converted)
+			continue
+		case "tap", "double_tap", "long_press":
+			if event.Selector == nil || event.Selector.Type == "coordinates" {
+				x, y := transform.apply(event.X, event.Y)
+				if selector := a.selectorAtPoint(deviceId, x, y); selector != nil {
+					event.Selector = selector
+					converted++
+				}
+			}
+		}
+
+		// Replay on the recorded coordinates; the new selectors are what's under review
+		replay := *event
+		replay.Selector = nil
+		if err := a.ExecuteSingleTouchEvent(deviceId, replay, script.Resolution); err != nil {
+			return nil, fmt.Errorf("event %d failed during conversion: %w", i+1, err)
+		}
+
+		a.emitSelectorConversionProgress(deviceId, i+1, len(script.Events), converted)
+	}
+
+	if err := a.SaveTouchScript(*script, true); err != nil {
+		return nil, err
+	}
+	a.Log("Converted %d of %d events in %q to selectors", converted, len(script.Events), scriptName)
+	return script, nil
+}
+
+// emitSelectorConversionProgress reports how far ConvertScriptToSelectors has got
+func (a *App) emitSelectorConversionProgress(deviceId string, current, total, converted int) {
+	wailsRuntime.EventsEmit(a.ctx, "selector-conversion-progress", map[string]interface{}{
+		"deviceId":  deviceId,
+		"current":   current,
+		"total":     total,
+		"converted": converted,
+	})
+}
+
+// selectorAtPoint suggests a selector for the element under (x, y) on the current screen.
+// Bounds-only suggestions are dropped since they are no sturdier than the coordinates.
+func (a *App) selectorAtPoint(deviceId string, x, y int) *ElementSelector {
+	hierarchy, err := a.GetUIHierarchy(deviceId, false)
+	if err != nil {
+		return nil
+	}
+	node := a.FindElementAtPoint(hierarchy.Root, x, y)
+	if node == nil || node == hierarchy.Root {
+		return nil
+	}
+	selector := a.GetBestSelector(node, hierarchy.Root)
+	if selector == nil || selector.Type == "bounds" {
+		return nil
+	}
+	return selector
+}
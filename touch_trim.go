@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+)
+
+// rejectStroke says why a recorded finger stroke should be dropped as accidental, or ""
+func (o RecordingOptions) rejectStroke(f TouchPointer) string {
+	for _, zone := range o.IgnoreZones {
+		if f.X >= zone.Left && f.X < zone.Right && f.Y >= zone.Top && f.Y < zone.Bottom {
+			return "starts in an ignore zone"
+		}
+	}
+	if o.MinStrokeMs > 0 && f.Duration < o.MinStrokeMs {
+		return fmt.Sprintf("shorter than %dms", o.MinStrokeMs)
+	}
+	if o.DiscardShortStrokes {
+		dx, dy := f.X2-f.X, f.Y2-f.Y
+		if f.Duration < o.ShortStrokeMs && dx*dx+dy*dy < o.ShortStrokePx*o.ShortStrokePx {
+			return fmt.Sprintf("graze under %dms and %dpx", o.ShortStrokeMs, o.ShortStrokePx)
+		}
+	}
+	return ""
+}
+
+// TrimTouchScript cuts a saved script down to the events between startMs and endMs (endMs <= 0
+// keeps everything after startMs) and shifts what is left to start at zero. The raw input
+// stream is cut on whole gestures so raw replay never starts or ends with a finger down.
+func (a *App) TrimTouchScript(name string, startMs, endMs int64) (*TouchScript, error) {
+	if startMs < 0 || (endMs > 0 && endMs <= startMs) {
+		return nil, fmt.Errorf("invalid trim range %d-%dms", startMs, endMs)
+	}
+	script, err := a.loadTouchScript(name)
+	if err != nil {
+		return nil, err
+	}
+	inRange := func(t int64) bool {
+		return t >= startMs && (endMs <= 0 || t <= endMs)
+	}
+
+	events := make([]TouchEvent, 0, len(script.Events))
+	for _, event := range script.Events {
+		if inRange(event.Timestamp) {
+			events = append(events, event)
+		}
+	}
+	var raw []RawInputEvent
+	if script.RawInput != nil {
+		raw = trimRawGestures(script.RawInput.Events, inRange)
+	}
+	if len(events) == 0 && len(raw) == 0 {
+		return nil, fmt.Errorf("no events between %dms and %dms", startMs, endMs)
+	}
+
+	// Gestures in the raw stream begin before the touch event stamped at their end
+	offset := int64(-1)
+	if len(events) > 0 {
+		offset = events[0].Timestamp
+	}
+	if len(raw) > 0 && (offset < 0 || raw[0].Time < offset) {
+		offset = raw[0].Time
+	}
+	for i := range events {
+		events[i].Timestamp -= offset
+	}
+	for i := range raw {
+		raw[i].Time -= offset
+	}
+
+	removed := len(script.Events) - len(events)
+	script.Events = events
+	if script.RawInput != nil {
+		if len(raw) == 0 {
+			script.RawInput = nil
+		} else {
+			script.RawInput.Events = raw
+		}
+	}
+	if err := a.SaveTouchScript(*script, true); err != nil {
+		return nil, err
+	}
+	a.Log("Trimmed %q: removed %d events", name, removed)
+	return script, nil
+}
+
+// trimRawGestures keeps the sync frames of every gesture (first finger down until the frame
+// where the last one lifts) whose final frame falls in range, plus idle frames in range
+func trimRawGestures(events []RawInputEvent, inRange func(int64) bool) []RawInputEvent {
+	var (
+		kept        []RawInputEvent
+		gesture     []RawInputEvent
+		frame       []RawInputEvent
+		slot        int32
+		activeSlots = make(map[int32]bool)
+		btnDown     bool
+	)
+	active := func() bool { return btnDown || len(activeSlots) > 0 }
+
+	for _, ev := range events {
+		wasActive := active()
+		frame = append(frame, ev)
+		switch {
+		case ev.Type == evdevTypes["EV_ABS"] && ev.Code == evdevCodes["ABS_MT_SLOT"]:
+			slot = ev.Value
+		case ev.Type == evdevTypes["EV_ABS"] && ev.Code == evdevCodes["ABS_MT_TRACKING_ID"]:
+			if ev.Value == -1 {
+				delete(activeSlots, slot)
+			} else {
+				activeSlots[slot] = true
+			}
+		case ev.Type == evdevTypes["EV_KEY"] && ev.Code == evdevCodes["BTN_TOUCH"]:
+			btnDown = ev.Value != 0
+		}
+		if wasActive != active() && len(gesture) == 0 && active() {
+			// First finger down: the frame so far opens a gesture
+			gesture, frame = frame, nil
+			continue
+		}
+		if ev.Type != evdevTypes["EV_SYN"] || ev.Code != evdevCodes["SYN_REPORT"] {
+			continue
+		}
+
+		// End of a sync frame
+		switch {
+		case len(gesture) == 0:
+			if inRange(ev.Time) {
+				kept = append(kept, frame...)
+			}
+		case active():
+			gesture = append(gesture, frame...)
+		default:
+			gesture = append(gesture, frame...)
+			if inRange(ev.Time) {
+				kept = append(kept, gesture...)
+			}
+			gesture = nil
+		}
+		frame = nil
+	}
+	// A gesture still open at the end of the recording is dropped with its trailing frame
+	if len(gesture) == 0 && len(frame) > 0 && inRange(frame[len(frame)-1].Time) {
+		kept = append(kept, frame...)
+	}
+	return kept
+}
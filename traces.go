@@ -0,0 +1,277 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	tombstoneProcessRegex = regexp.MustCompile(`>>> (.+?) <<<`)
+	tombstonePidRegex     = regexp.MustCompile(`pid: (\d+)`)
+	anrPidRegex           = regexp.MustCompile(`----- pid (\d+) at`)
+	anrCmdLineRegex       = regexp.MustCompile(`Cmd line: (\S+)`)
+)
+
+// ListAnrTraces lists the ANR trace files in /data/anr
+func (a *App) ListAnrTraces(deviceId string) ([]TraceFile, error) {
+	return a.listTraceDir(deviceId, "/data/anr", "anr")
+}
+
+// ListTombstones lists the native crash tombstones in /data/tombstones
+func (a *App) ListTombstones(deviceId string) ([]TraceFile, error) {
+	return a.listTraceDir(deviceId, "/data/tombstones", "tombstone")
+}
+
+// listTraceDir reads name/size/mtime and the first lines of every file in dir, using su when needed
+func (a *App) listTraceDir(deviceId, dir, kind string) ([]TraceFile, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	script := fmt.Sprintf(`for f in %s/*; do [ -f "$f" ] || continue; echo "==> $(stat -c '%%s|%%Y' "$f")|$f"; head -n 20 "$f" 2>/dev/null; done`, dir)
+
+	output, readable := a.runTraceScript(deviceId, dir, script, false)
+	if !readable {
+		if !a.hasRootShell(deviceId) {
+			return nil, fmt.Errorf("%w: %s is only readable as root on this device; use CollectTracesViaBugreport to extract it from an adb bugreport instead", ErrPermissionDenied, dir)
+		}
+		output, readable = a.runTraceScript(deviceId, dir, script, true)
+		if !readable {
+			return nil, fmt.Errorf("%w: %s could not be read even with su", ErrPermissionDenied, dir)
+		}
+	}
+
+	return parseTraceListing(output, kind), nil
+}
+
+// runTraceScript runs a listing script, optionally through su, and reports whether the directory was readable
+func (a *App) runTraceScript(deviceId, dir, script string, asRoot bool) (string, bool) {
+	if asRoot {
+		script = "su -c " + shellQuote(script)
+	}
+	output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	outStr := string(output)
+	if strings.Contains(outStr, "==> ") {
+		return outStr, true
+	}
+	// An empty directory lists nothing; only permission errors make it unreadable
+	lower := strings.ToLower(outStr)
+	denied := strings.Contains(lower, "permission denied") || strings.Contains(lower, "not permitted")
+	if !denied {
+		// The glob stays unexpanded when the directory cannot be listed at all
+		probe := "ls " + shellQuote(dir) + " >/dev/null 2>&1 && echo ok"
+		if asRoot {
+			probe = "su -c " + shellQuote(probe)
+		}
+		if out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", probe).Output(); !strings.Contains(string(out), "ok") {
+			denied = true
+		}
+	}
+	return outStr, !denied
+}
+
+// hasRootShell reports whether adbd runs as root or su is available
+func (a *App) hasRootShell(deviceId string) bool {
+	out, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "id -u").Output()
+	if strings.TrimSpace(string(out)) == "0" {
+		return true
+	}
+	out, _ = a.newAdbCommand(nil, "-s", deviceId, "shell", "su -c 'id -u'").Output()
+	return strings.TrimSpace(string(out)) == "0"
+}
+
+// parseTraceListing splits the "==> size|mtime|path" blocks produced by listTraceDir
+func parseTraceListing(output, kind string) []TraceFile {
+	var traces []TraceFile
+	var current *TraceFile
+	var head []string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Process, current.PID = parseTraceHeader(strings.Join(head, "\n"))
+		traces = append(traces, *current)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "==> ") {
+			flush()
+			current = nil
+			head = nil
+
+			parts := strings.SplitN(strings.TrimPrefix(line, "==> "), "|", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			size, _ := strconv.ParseInt(parts[0], 10, 64)
+			mtime, _ := strconv.ParseInt(parts[1], 10, 64)
+			current = &TraceFile{
+				Name:    path.Base(parts[2]),
+				Path:    parts[2],
+				Size:    size,
+				ModTime: mtime,
+				Kind:    kind,
+			}
+			continue
+		}
+		if current != nil {
+			head = append(head, line)
+		}
+	}
+	flush()
+
+	sort.Slice(traces, func(i, j int) bool { return traces[i].ModTime > traces[j].ModTime })
+	return traces
+}
+
+// parseTraceHeader extracts the crashing process from the first lines of a tombstone or ANR trace
+func parseTraceHeader(head string) (string, int) {
+	var process string
+	var pid int
+
+	if m := tombstoneProcessRegex.FindStringSubmatch(head); m != nil {
+		process = m[1]
+		if pm := tombstonePidRegex.FindStringSubmatch(head); pm != nil {
+			pid, _ = strconv.Atoi(pm[1])
+		}
+		return process, pid
+	}
+	if m := anrCmdLineRegex.FindStringSubmatch(head); m != nil {
+		process = m[1]
+	}
+	if m := anrPidRegex.FindStringSubmatch(head); m != nil {
+		pid, _ = strconv.Atoi(m[1])
+	}
+	return process, pid
+}
+
+// PullTrace saves a trace or tombstone locally, reading it through su when adb pull is refused
+func (a *App) PullTrace(deviceId, remotePath, destPath string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	remotePath = path.Clean("/" + remotePath)
+
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		destPath = filepath.Join(destPath, path.Base(remotePath))
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	output, err := a.newAdbCommand(nil, "-s", deviceId, "pull", remotePath, destPath).CombinedOutput()
+	if err == nil {
+		return destPath, nil
+	}
+	if !a.hasRootShell(deviceId) {
+		return "", fmt.Errorf("%w: cannot read %s without root (%s); use CollectTracesViaBugreport instead", ErrPermissionDenied, remotePath, strings.TrimSpace(string(output)))
+	}
+
+	data, err := a.newAdbCommand(nil, "-s", deviceId, "exec-out", "su -c "+shellQuote("cat "+shellQuote(remotePath))).Output()
+	if err != nil || len(data) == 0 {
+		return "", fmt.Errorf("failed to read %s as root: %v", remotePath, err)
+	}
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save trace: %w", err)
+	}
+	return destPath, nil
+}
+
+// CollectTracesViaBugreport captures an adb bugreport and extracts its ANR traces and tombstones
+// into destDir. This works on non-rooted devices but can take several minutes.
+func (a *App) CollectTracesViaBugreport(deviceId, destDir string) ([]TraceFile, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	zipPath := filepath.Join(os.TempDir(), fmt.Sprintf("gaze-bugreport-%s.zip", strings.NewReplacer(":", "_", ".", "_").Replace(deviceId)))
+	defer os.Remove(zipPath)
+
+	if output, err := a.newAdbCommand(nil, "-s", deviceId, "bugreport", zipPath).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("bugreport failed: %w, output: %s", err, string(output))
+	}
+
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bugreport: %w", err)
+	}
+	defer reader.Close()
+
+	var traces []TraceFile
+	for _, f := range reader.File {
+		var kind string
+		switch {
+		case strings.HasPrefix(f.Name, "FS/data/anr/"):
+			kind = "anr"
+		case strings.HasPrefix(f.Name, "FS/data/tombstones/"):
+			kind = "tombstone"
+		default:
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		localPath := filepath.Join(destDir, kind, path.Base(f.Name))
+		head, err := extractZipEntry(f, localPath)
+		if err != nil {
+			return nil, err
+		}
+		process, pid := parseTraceHeader(head)
+		traces = append(traces, TraceFile{
+			Name:      path.Base(f.Name),
+			Path:      "/" + strings.TrimPrefix(f.Name, "FS/"),
+			LocalPath: localPath,
+			Size:      int64(f.UncompressedSize64),
+			ModTime:   f.Modified.Unix(),
+			Kind:      kind,
+			Process:   process,
+			PID:       pid,
+		})
+	}
+
+	sort.Slice(traces, func(i, j int) bool { return traces[i].ModTime > traces[j].ModTime })
+	return traces, nil
+}
+
+// extractZipEntry writes a zip entry to disk and returns its first few KB for header parsing
+func extractZipEntry(f *zip.File, localPath string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return "", err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	head := make([]byte, 4096)
+	n, _ := io.ReadFull(rc, head)
+	if _, err := out.Write(head[:n]); err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		return "", err
+	}
+	return string(head[:n]), nil
+}
@@ -0,0 +1,770 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrChecksumMismatch is returned when a transferred file's size or hash differs from the source
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// File Transfer State
+var (
+	transferCancels = make(map[string]context.CancelFunc)
+	transferMu      sync.Mutex
+	transferSeq     int64
+)
+
+// transferFile is a single file inside a (possibly recursive) transfer
+type transferFile struct {
+	Remote string
+	Local  string
+	Size   int64
+}
+
+// beginTransfer registers a new cancellable transfer and returns its ID
+func beginTransfer() (string, context.Context) {
+	transferMu.Lock()
+	defer transferMu.Unlock()
+
+	transferSeq++
+	transferId := fmt.Sprintf("transfer_%d_%d", time.Now().Unix(), transferSeq)
+	ctx, cancel := context.WithCancel(context.Background())
+	transferCancels[transferId] = cancel
+	return transferId, ctx
+}
+
+// endTransfer releases the resources held by a transfer
+func endTransfer(transferId string) {
+	transferMu.Lock()
+	defer transferMu.Unlock()
+
+	if cancel, ok := transferCancels[transferId]; ok {
+		cancel()
+		delete(transferCancels, transferId)
+	}
+}
+
+// CancelTransfer aborts a running pull or push
+func (a *App) CancelTransfer(transferId string) error {
+	transferMu.Lock()
+	cancel, ok := transferCancels[transferId]
+	transferMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("transfer not found: %s", transferId)
+	}
+	cancel()
+	return nil
+}
+
+// PullFile copies a file or directory from the device, emitting file-transfer-progress events.
+// With verify set, each file's checksum is compared against the device copy.
+func (a *App) PullFile(deviceId, remotePath, localPath string, verify bool) (*TransferResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	remotePath = path.Clean("/" + remotePath)
+	isDir, size, err := a.statRemote(deviceId, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []transferFile
+	if isDir {
+		files, err = a.listRemoteTree(deviceId, remotePath, localPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(localPath, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create local directory: %w", err)
+		}
+	} else {
+		if info, statErr := os.Stat(localPath); statErr == nil && info.IsDir() {
+			localPath = filepath.Join(localPath, path.Base(remotePath))
+		}
+		files = []transferFile{{Remote: remotePath, Local: localPath, Size: size}}
+	}
+
+	transferId, ctx := beginTransfer()
+	defer endTransfer(transferId)
+
+	algo := ""
+	if verify {
+		algo = a.detectChecksumAlgo(deviceId)
+	}
+
+	verifyFile := func(f transferFile) error {
+		info, err := os.Stat(f.Local)
+		if err != nil {
+			return fmt.Errorf("failed to stat pulled file: %w", err)
+		}
+		if f.Size >= 0 && info.Size() != f.Size {
+			return fmt.Errorf("%w for %s: size differs, device %d bytes, local %d bytes", ErrChecksumMismatch, f.Remote, f.Size, info.Size())
+		}
+		if algo != "" {
+			return a.compareChecksums(deviceId, f, algo)
+		}
+		return nil
+	}
+	localSize := func(f transferFile) int64 {
+		if info, err := os.Stat(f.Local); err == nil {
+			return info.Size()
+		}
+		return 0
+	}
+
+	result, err := a.runTransfer(ctx, transferId, deviceId, "pull", files, localSize, verifyFile)
+	if err != nil {
+		return nil, err
+	}
+	result.Verified = algo != ""
+	result.ChecksumAlgo = algo
+	return result, nil
+}
+
+// runTransfer moves each file with adb pull/push, polling the destination size for progress.
+// adb only prints its own progress to a TTY, so it cannot be parsed from a pipe.
+func (a *App) runTransfer(ctx context.Context, transferId, deviceId, direction string, files []transferFile, destSize func(transferFile) int64, verify func(transferFile) error) (*TransferResult, error) {
+	return a.runTransferWithProgress(ctx, transferId, deviceId, direction, files, destSize, verify, nil)
+}
+
+// runTransferWithProgress is runTransfer that also passes the bytes moved so far to progress
+func (a *App) runTransferWithProgress(ctx context.Context, transferId, deviceId, direction string, files []transferFile, destSize func(transferFile) int64, verify func(transferFile) error, progress func(bytes int64)) (*TransferResult, error) {
+	var total int64
+	for _, f := range files {
+		if f.Size > 0 {
+			total += f.Size
+		}
+	}
+
+	start := time.Now()
+	var done int64
+	retries := 0
+
+	emit := func(status, current string, index int, bytes int64) {
+		elapsed := time.Since(start).Seconds()
+		var rate float64
+		if elapsed > 0 {
+			rate = float64(bytes) / elapsed
+		}
+		var percent float64
+		if total > 0 {
+			percent = float64(bytes) * 100 / float64(total)
+		} else if status == "completed" {
+			percent = 100
+		}
+		wailsRuntime.EventsEmit(a.ctx, "file-transfer-progress", map[string]interface{}{
+			"transferId": transferId,
+			"deviceId":   deviceId,
+			"direction":  direction,
+			"status":     status,
+			"file":       current,
+			"fileIndex":  index,
+			"fileCount":  len(files),
+			"bytes":      bytes,
+			"total":      total,
+			"percent":    percent,
+			"rate":       rate,
+		})
+		if progress != nil && status == "running" {
+			progress(bytes)
+		}
+	}
+
+	emit("started", "", 0, 0)
+
+	for i, f := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var args []string
+		if direction == "pull" {
+			if err := os.MkdirAll(filepath.Dir(f.Local), 0755); err != nil {
+				emit("failed", f.Remote, i, done)
+				return nil, fmt.Errorf("failed to create local directory: %w", err)
+			}
+			args = []string{"-s", deviceId, "pull", f.Remote, f.Local}
+		} else {
+			args = []string{"-s", deviceId, "push", f.Local, f.Remote}
+		}
+
+		for attempt := 1; ; attempt++ {
+			cmd := a.newAdbCommand(ctx, args...)
+			pollDone := make(chan struct{})
+			go func(f transferFile, index int, base int64) {
+				ticker := time.NewTicker(300 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-pollDone:
+						return
+					case <-ticker.C:
+						current := destSize(f)
+						if f.Size > 0 && current > f.Size {
+							current = f.Size
+						}
+						emit("running", f.Remote, index, base+current)
+					}
+				}
+			}(f, i, done)
+
+			output, err := a.runTrackedCommand("transfer", cmd)
+			close(pollDone)
+
+			if ctx.Err() != nil {
+				if direction == "pull" {
+					os.Remove(f.Local)
+				}
+				break
+			}
+			if err != nil {
+				emit("failed", f.Remote, i, done)
+				return nil, fmt.Errorf("failed to %s %s: %w, output: %s", direction, f.Remote, err, strings.TrimSpace(string(output)))
+			}
+			if verify == nil {
+				break
+			}
+			verifyErr := verify(f)
+			if verifyErr == nil {
+				break
+			}
+			if errors.Is(verifyErr, ErrChecksumMismatch) && attempt == 1 {
+				// Flaky wireless links occasionally corrupt data; one retry usually fixes it
+				a.Log("%v, retrying transfer", verifyErr)
+				retries++
+				continue
+			}
+			emit("failed", f.Remote, i, done)
+			return nil, verifyErr
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if f.Size > 0 {
+			done += f.Size
+		}
+		emit("running", f.Remote, i, done)
+	}
+
+	if ctx.Err() != nil {
+		emit("cancelled", "", len(files), done)
+		return nil, fmt.Errorf("transfer cancelled")
+	}
+
+	duration := time.Since(start)
+	result := &TransferResult{
+		TransferID: transferId,
+		Files:      len(files),
+		Bytes:      done,
+		DurationMs: duration.Milliseconds(),
+		Retries:    retries,
+	}
+	if duration > 0 {
+		result.Throughput = float64(done) / duration.Seconds()
+	}
+
+	emit("completed", "", len(files), done)
+	return result, nil
+}
+
+// statRemote reports whether a device path is a directory and its size in bytes
+func (a *App) statRemote(deviceId, remotePath string) (bool, int64, error) {
+	cmd := a.newAdbCommand(nil, "-s", deviceId, "shell", "stat -L -c '%F|%s' "+shellQuote(remotePath))
+	output, _ := cmd.CombinedOutput()
+	line := strings.TrimSpace(string(output))
+
+	if typed := classifyRemoteError(line, remotePath); typed != nil {
+		return false, 0, typed
+	}
+
+	parts := strings.SplitN(line, "|", 2)
+	if len(parts) != 2 {
+		return false, 0, fmt.Errorf("failed to stat %s: %s", remotePath, line)
+	}
+	size, _ := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+	return strings.Contains(parts[0], "directory"), size, nil
+}
+
+// listRemoteTree enumerates regular files below a device directory, mapped under localRoot
+func (a *App) listRemoteTree(deviceId, remoteRoot, localRoot string) ([]transferFile, error) {
+	var files []transferFile
+	script := "find " + shellQuote(remoteRoot+"/") + " -type f -exec stat -c '%s|%n' {} +"
+	_, err := a.runStreamedCommand(nil, deviceId, []string{"shell", script}, func(stream, line string) {
+		if stream != "stdout" {
+			return
+		}
+		parts := strings.SplitN(line, "|", 2)
+		if len(parts) != 2 {
+			return
+		}
+		size, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return
+		}
+		remote := path.Clean(parts[1])
+		rel := strings.TrimPrefix(remote, remoteRoot+"/")
+		files = append(files, transferFile{
+			Remote: remote,
+			Local:  filepath.Join(localRoot, filepath.FromSlash(rel)),
+			Size:   size,
+		})
+	})
+	if err != nil && len(files) == 0 {
+		return nil, fmt.Errorf("failed to list %s: %w", remoteRoot, err)
+	}
+	return files, nil
+}
+
+// FileConflictError is returned by PushFile when the target exists and overwrite is off
+type FileConflictError struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+}
+
+func (e *FileConflictError) Error() string {
+	return fmt.Sprintf("file exists: %s (size %d, modified %s)", e.Path, e.Size, time.Unix(e.ModTime, 0).Format("2006-01-02 15:04:05"))
+}
+
+// PushFile copies a local file or directory to the device, emitting file-transfer-progress events.
+// With verify set, each file's checksum is compared against the device copy.
+func (a *App) PushFile(deviceId, localPath, remotePath string, overwrite bool, verify bool) (*TransferResult, error) {
+	a.updateLastActive(deviceId)
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local path: %w", err)
+	}
+
+	remotePath = path.Clean("/" + remotePath)
+	if remoteIsDir, _, statErr := a.statRemote(deviceId, remotePath); statErr == nil && remoteIsDir {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	files, err := pushFileList(localPath, remotePath, info)
+	if err != nil {
+		return nil, err
+	}
+
+	if !overwrite {
+		if conflict := a.findPushConflict(deviceId, files); conflict != nil {
+			return nil, conflict
+		}
+	}
+
+	transferId, ctx := beginTransfer()
+	defer endTransfer(transferId)
+	return a.pushFiles(ctx, transferId, deviceId, files, verify, nil)
+}
+
+// pushFileList maps a local file, or every file below a local directory, to its target under
+// remotePath. Directories are pushed file by file, so an existing remote directory is merged
+// into rather than getting a copy nested inside it.
+func pushFileList(localPath, remotePath string, info os.FileInfo) ([]transferFile, error) {
+	if !info.IsDir() {
+		return []transferFile{{Remote: remotePath, Local: localPath, Size: info.Size()}}, nil
+	}
+	var files []transferFile
+	err := filepath.Walk(localPath, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, transferFile{
+			Remote: path.Join(remotePath, filepath.ToSlash(rel)),
+			Local:  p,
+			Size:   fi.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk local directory: %w", err)
+	}
+	return files, nil
+}
+
+// pushFiles runs a push transfer and has the pushed media indexed; progress, when set,
+// receives the bytes pushed so far
+func (a *App) pushFiles(ctx context.Context, transferId, deviceId string, files []transferFile, verify bool, progress func(bytes int64)) (*TransferResult, error) {
+	remoteSize := func(f transferFile) int64 {
+		output, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "stat -c %s "+shellQuote(f.Remote)).Output()
+		if err != nil {
+			return 0
+		}
+		size, _ := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+		return size
+	}
+	algo := ""
+	if verify {
+		algo = a.detectChecksumAlgo(deviceId)
+	}
+
+	verifyFile := func(f transferFile) error {
+		_, size, err := a.statRemote(deviceId, f.Remote)
+		if err != nil {
+			return err
+		}
+		if size != f.Size {
+			return fmt.Errorf("%w for %s: size differs, local %d bytes, device %d bytes", ErrChecksumMismatch, f.Remote, f.Size, size)
+		}
+		if algo != "" {
+			return a.compareChecksums(deviceId, f, algo)
+		}
+		return nil
+	}
+
+	result, err := a.runTransferWithProgress(ctx, transferId, deviceId, "push", files, remoteSize, verifyFile, progress)
+	if err != nil {
+		return nil, err
+	}
+	result.Verified = algo != ""
+	result.ChecksumAlgo = algo
+
+	a.scanMediaFiles(deviceId, files)
+	return result, nil
+}
+
+// maxShellScriptLen keeps one adb shell command under the 4 KB payload adbd accepted before
+// Android 7; longer commands are rejected or cut short there
+const maxShellScriptLen = 4000
+
+// shellBatches joins parts with sep into as few strings as stay within limit bytes. A part
+// longer than limit gets a batch of its own.
+func shellBatches(parts []string, sep string, limit int) []string {
+	var batches []string
+	var cur strings.Builder
+	for _, part := range parts {
+		if cur.Len() > 0 && cur.Len()+len(sep)+len(part) > limit {
+			batches = append(batches, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteString(sep)
+		}
+		cur.WriteString(part)
+	}
+	if cur.Len() > 0 {
+		batches = append(batches, cur.String())
+	}
+	return batches
+}
+
+// findPushConflict returns the first target that already exists on the device
+func (a *App) findPushConflict(deviceId string, files []transferFile) *FileConflictError {
+	if len(files) == 0 {
+		return nil
+	}
+
+	var quoted []string
+	for _, f := range files {
+		quoted = append(quoted, shellQuote(f.Remote))
+	}
+	const loop = `for f in %s; do [ -e "$f" ] && stat -c '%%s|%%Y|%%n' "$f" && break; done`
+	for _, batch := range shellBatches(quoted, " ", maxShellScriptLen-len(loop)) {
+		output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", fmt.Sprintf(loop, batch)).Output()
+
+		for _, line := range strings.Split(string(output), "\n") {
+			parts := strings.SplitN(strings.TrimRight(line, "\r"), "|", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			size, _ := strconv.ParseInt(parts[0], 10, 64)
+			mtime, _ := strconv.ParseInt(parts[1], 10, 64)
+			return &FileConflictError{Path: parts[2], Size: size, ModTime: mtime}
+		}
+	}
+	return nil
+}
+
+// scanMediaFiles asks the media scanner to index files pushed to, or removed from, shared
+// storage. The scanner walks directories since Android 10, so there a folder holding several of
+// the files is scanned with one broadcast; older scanners only take single files. The
+// broadcasts then share as few shell commands as the length limit allows.
+func (a *App) scanMediaFiles(deviceId string, files []transferFile) {
+	var dirs []string
+	byDir := make(map[string][]string)
+	for _, f := range files {
+		if !strings.HasPrefix(f.Remote, "/sdcard/") && !strings.HasPrefix(f.Remote, "/storage/") {
+			continue
+		}
+		dir := path.Dir(f.Remote)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], f.Remote)
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	walksDirs := false
+	for _, dir := range dirs {
+		if len(byDir[dir]) > 1 {
+			walksDirs = a.getSDKInt(deviceId) >= 29
+			break
+		}
+	}
+	var cmds []string
+	for _, dir := range dirs {
+		targets := byDir[dir]
+		if walksDirs && len(targets) > 1 {
+			targets = []string{dir}
+		}
+		for _, target := range targets {
+			cmds = append(cmds, "am broadcast -a android.intent.action.MEDIA_SCANNER_SCAN_FILE -d "+shellQuote("file://"+target)+" >/dev/null")
+		}
+	}
+
+	for _, script := range shellBatches(cmds, "; ", maxShellScriptLen) {
+		if output, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput(); err != nil {
+			a.Log("Media scan failed: %v, output: %s", err, string(output))
+		}
+	}
+}
+
+// PushDroppedFiles uploads files dropped onto the window into remoteDir in the background.
+// The returned batch ID reports aggregate file-batch-progress events and can be passed to CancelTransfer.
+func (a *App) PushDroppedFiles(deviceId string, localPaths []string, remoteDir string, overwrite bool) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	if len(localPaths) == 0 {
+		return "", fmt.Errorf("no files to upload")
+	}
+
+	remoteDir = path.Clean("/" + remoteDir)
+	existing := make(map[string]bool)
+	if entries, err := a.ListFiles(deviceId, remoteDir); err == nil {
+		for _, e := range entries {
+			existing[e.Name] = true
+		}
+	}
+
+	type droppedItem struct {
+		local string
+		files []transferFile
+		size  int64
+	}
+	var items []droppedItem
+	var total int64
+	for _, local := range localPaths {
+		info, err := os.Stat(local)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", local, err)
+		}
+
+		name := filepath.Base(local)
+		if !overwrite {
+			name = uniqueName(name, existing)
+		}
+		existing[name] = true
+
+		// The target is exact: with overwrite a dropped folder merges into the existing one
+		files, err := pushFileList(local, path.Join(remoteDir, name), info)
+		if err != nil {
+			return "", err
+		}
+		var size int64
+		for _, f := range files {
+			size += f.Size
+		}
+		total += size
+		items = append(items, droppedItem{local: local, files: files, size: size})
+	}
+
+	batchId, ctx := beginTransfer()
+
+	go func() {
+		defer endTransfer(batchId)
+
+		// done is updated from the transfer's progress poller as well
+		var mu sync.Mutex
+		var done int64
+		var failed []string
+		emit := func(status, current string, completed int) {
+			mu.Lock()
+			defer mu.Unlock()
+			var percent float64
+			if total > 0 {
+				percent = float64(done) * 100 / float64(total)
+			}
+			wailsRuntime.EventsEmit(a.ctx, "file-batch-progress", map[string]interface{}{
+				"batchId":   batchId,
+				"deviceId":  deviceId,
+				"status":    status,
+				"file":      current,
+				"completed": completed,
+				"count":     len(items),
+				"bytes":     done,
+				"total":     total,
+				"percent":   percent,
+				"failed":    append([]string(nil), failed...),
+			})
+		}
+
+		emit("started", "", 0)
+		var base int64
+		for i, item := range items {
+			if ctx.Err() != nil {
+				emit("cancelled", "", i)
+				return
+			}
+			emit("running", item.local, i)
+			_, err := a.pushFiles(ctx, batchId, deviceId, item.files, false, func(bytes int64) {
+				mu.Lock()
+				done = base + bytes
+				mu.Unlock()
+				emit("running", item.local, i)
+			})
+			if ctx.Err() != nil {
+				emit("cancelled", "", i)
+				return
+			}
+			mu.Lock()
+			if err != nil {
+				a.Log("Failed to push dropped file %s: %v", item.local, err)
+				failed = append(failed, item.local)
+			}
+			base += item.size
+			done = base
+			mu.Unlock()
+		}
+		emit("completed", "", len(items))
+	}()
+
+	return batchId, nil
+}
+
+// uniqueName appends " (n)" before the extension until name is not taken
+func uniqueName(name string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// checksumTools lists the hash command names to try on the device, per algorithm
+var checksumTools = map[string][]string{
+	"md5":    {"md5sum", "toybox md5sum", "busybox md5sum"},
+	"sha256": {"sha256sum", "toybox sha256sum", "busybox sha256sum"},
+}
+
+// ChecksumRemoteFile returns the hex digest of a device file using "md5" or "sha256"
+func (a *App) ChecksumRemoteFile(deviceId, pathStr, algo string) (string, error) {
+	if deviceId == "" {
+		return "", fmt.Errorf("no device specified")
+	}
+	algo = strings.ToLower(algo)
+	tools, ok := checksumTools[algo]
+	if !ok {
+		return "", fmt.Errorf("unsupported checksum algorithm: %s", algo)
+	}
+
+	pathStr = path.Clean("/" + pathStr)
+	for _, tool := range tools {
+		output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", tool+" "+shellQuote(pathStr)).CombinedOutput()
+		fields := strings.Fields(string(output))
+		if len(fields) > 0 && isHexDigest(fields[0], algo) {
+			return strings.ToLower(fields[0]), nil
+		}
+		if typed := classifyRemoteError(string(output), pathStr); typed != nil {
+			return "", typed
+		}
+	}
+	return "", fmt.Errorf("no %s tool available on device", algo)
+}
+
+// detectChecksumAlgo picks the strongest hash the device can compute, or "" if none
+func (a *App) detectChecksumAlgo(deviceId string) string {
+	for _, algo := range []string{"sha256", "md5"} {
+		for _, tool := range checksumTools[algo] {
+			output, _ := a.newAdbCommand(nil, "-s", deviceId, "shell", "echo -n | "+tool).CombinedOutput()
+			fields := strings.Fields(string(output))
+			if len(fields) > 0 && isHexDigest(fields[0], algo) {
+				return algo
+			}
+		}
+	}
+	return ""
+}
+
+// compareChecksums hashes both copies of a transferred file and reports a mismatch
+func (a *App) compareChecksums(deviceId string, f transferFile, algo string) error {
+	local, err := localChecksum(f.Local, algo)
+	if err != nil {
+		return fmt.Errorf("failed to hash local file: %w", err)
+	}
+	remote, err := a.ChecksumRemoteFile(deviceId, f.Remote, algo)
+	if err != nil {
+		return fmt.Errorf("failed to hash device file: %w", err)
+	}
+	if local != remote {
+		return fmt.Errorf("%w for %s: local %s, device %s", ErrChecksumMismatch, f.Remote, local, remote)
+	}
+	return nil
+}
+
+// localChecksum returns the hex digest of a local file
+func localChecksum(localPath, algo string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var h hash.Hash
+	if algo == "sha256" {
+		h = sha256.New()
+	} else {
+		h = md5.New()
+	}
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isHexDigest(s, algo string) bool {
+	want := 32
+	if algo == "sha256" {
+		want = 64
+	}
+	if len(s) != want {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
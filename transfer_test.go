@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShellBatches(t *testing.T) {
+	tests := []struct {
+		parts []string
+		limit int
+		want  []string
+	}{
+		{nil, 10, nil},
+		{[]string{"aa", "bb", "cc"}, 8, []string{"aa; bb", "cc"}},
+		{[]string{"aa", "bb", "cc"}, 10, []string{"aa; bb; cc"}},
+		{[]string{"aaaaaaaaaaaa", "bb"}, 8, []string{"aaaaaaaaaaaa", "bb"}},
+	}
+	for _, tt := range tests {
+		if got := shellBatches(tt.parts, "; ", tt.limit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("shellBatches(%q, %d) = %q, want %q", tt.parts, tt.limit, got, tt.want)
+		}
+	}
+
+	// A few thousand pushed files stay under adbd's limit and keep every path
+	var parts []string
+	for i := 0; i < 3000; i++ {
+		parts = append(parts, shellQuote("/sdcard/DCIM/Camera/IMG_20240101_"+strings.Repeat("0", 6)+".jpg"))
+	}
+	batches := shellBatches(parts, " ", maxShellScriptLen)
+	count := 0
+	for _, b := range batches {
+		if len(b) > maxShellScriptLen {
+			t.Fatalf("batch of %d bytes exceeds the limit", len(b))
+		}
+		count += len(strings.Split(b, " "))
+	}
+	if count != len(parts) {
+		t.Errorf("batches hold %d paths, want %d", count, len(parts))
+	}
+}
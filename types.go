@@ -6,7 +6,7 @@ import "time"
 type Device struct {
 	ID         string   `json:"id"`
 	Serial     string   `json:"serial"`
-	State      string   `json:"state"`
+	State      string   `json:"state"` // adb's state (device, offline, unauthorized, recovery, sideload) or "fastboot"
 	Model      string   `json:"model"`
 	Brand      string   `json:"brand"`
 	Type       string   `json:"type"` // "wired", "wireless", or "both"
@@ -45,12 +45,181 @@ type DeviceInfo struct {
 
 // FileInfo represents a file or directory on the device
 type FileInfo struct {
-	Name    string `json:"name"`
-	Size    int64  `json:"size"`
-	Mode    string `json:"mode"`
-	ModTime string `json:"modTime"`
-	IsDir   bool   `json:"isDir"`
-	Path    string `json:"path"`
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	Mode       string `json:"mode"`
+	Owner      string `json:"owner"`
+	Group      string `json:"group"`
+	ModTime    string `json:"modTime"`
+	IsDir      bool   `json:"isDir"`
+	IsSymlink  bool   `json:"isSymlink"`
+	LinkTarget string `json:"linkTarget,omitempty"`
+	Path       string `json:"path"`
+}
+
+// SyncSummary reports the outcome of a one-way folder sync
+type SyncSummary struct {
+	SyncID     string   `json:"syncId"`
+	Direction  string   `json:"direction"` // "push" or "pull"
+	Copied     int      `json:"copied"`
+	Skipped    int      `json:"skipped"`
+	Deleted    int      `json:"deleted"`
+	Failed     []string `json:"failed"`
+	Bytes      int64    `json:"bytes"`
+	DurationMs int64    `json:"durationMs"`
+	Cancelled  bool     `json:"cancelled"`
+}
+
+// PathBookmark is a saved file manager location
+type PathBookmark struct {
+	Path      string `json:"path"`
+	Label     string `json:"label"`
+	Global    bool   `json:"global"`
+	Exists    bool   `json:"exists"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+}
+
+// ShellHistoryEntry is a command run from the shell view
+type ShellHistoryEntry struct {
+	DeviceID   string `json:"deviceId"`
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exitCode"`
+	Timestamp  int64  `json:"timestamp"`
+	DurationMs int64  `json:"durationMs"`
+	Count      int    `json:"count"` // consecutive runs folded into this entry
+}
+
+// ShellSnippet is a saved, named shell command
+type ShellSnippet struct {
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// FilePreview is an in-memory preview of a remote file
+type FilePreview struct {
+	Path      string `json:"path"`
+	Kind      string `json:"kind"` // "image", "text" or "binary"
+	MimeType  string `json:"mimeType"`
+	Content   string `json:"content"` // data URI for images, UTF-8 text otherwise
+	Size      int64  `json:"size"`
+	Truncated bool   `json:"truncated"`
+}
+
+// StorageNode is a directory in the storage analyzer tree
+type StorageNode struct {
+	Path             string         `json:"path"`
+	Name             string         `json:"name"`
+	Size             int64          `json:"size"` // bytes
+	PermissionDenied bool           `json:"permissionDenied"`
+	Children         []*StorageNode `json:"children,omitempty"`
+}
+
+// StorageEntry is a flat path/size pair
+type StorageEntry struct {
+	Path             string `json:"path"`
+	Size             int64  `json:"size"`
+	PermissionDenied bool   `json:"permissionDenied"`
+}
+
+// StorageAnalysis is the result of AnalyzeRemoteStorage
+type StorageAnalysis struct {
+	Root       *StorageNode   `json:"root"`
+	Largest    []StorageEntry `json:"largest"`
+	Entries    int            `json:"entries"`
+	Denied     int            `json:"denied"`
+	DurationMs int64          `json:"durationMs"`
+}
+
+// DeleteSummary describes what a remote delete removed (or would remove in dry-run mode)
+type DeleteSummary struct {
+	Path      string `json:"path"`
+	Files     int    `json:"files"`
+	Dirs      int    `json:"dirs"`
+	TotalSize int64  `json:"totalSize"`
+	DryRun    bool   `json:"dryRun"`
+}
+
+// TransferResult summarizes a finished pull or push
+type TransferResult struct {
+	TransferID string  `json:"transferId"`
+	Files      int     `json:"files"`
+	Bytes      int64   `json:"bytes"`
+	DurationMs int64   `json:"durationMs"`
+	Throughput float64 `json:"throughput"` // bytes per second
+	Retries    int     `json:"retries"`
+
+	Verified     bool   `json:"verified"`
+	ChecksumAlgo string `json:"checksumAlgo,omitempty"` // "sha256", "md5" or empty when the device has neither
+}
+
+// LogcatRecord is a single parsed logcat line
+type LogcatRecord struct {
+	Timestamp string `json:"timestamp"`
+	PID       int    `json:"pid"`
+	TID       int    `json:"tid"`
+	Priority  string `json:"priority"` // V, D, I, W, E, F; empty for unparsed lines
+	Tag       string `json:"tag"`
+	Message   string `json:"message"`
+	Buffer    string `json:"buffer"`
+}
+
+// LogcatStatus is a package tracking notice for a logcat session
+type LogcatStatus struct {
+	SessionID string   `json:"sessionId"`
+	State     string   `json:"state"` // "waiting", "attached" or "restarted"
+	PIDs      []string `json:"pids"`
+	Message   string   `json:"message"`
+	Timestamp int64    `json:"timestamp"` // unix millis
+}
+
+// TagCount is the number of lines logged under a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// LogcatStats summarizes the volume of a logcat session
+type LogcatStats struct {
+	SessionID       string           `json:"sessionId"`
+	Total           int64            `json:"total"`
+	Unparsed        int64            `json:"unparsed"`
+	ByPriority      map[string]int64 `json:"byPriority"`
+	TopTags         []TagCount       `json:"topTags"`
+	TagCount        int              `json:"tagCount"`
+	ErrorsPerMinute int64            `json:"errorsPerMinute"` // E and F lines over the last 60s
+	Since           int64            `json:"since"`           // unix millis of the last reset
+}
+
+// LogBufferInfo is the size and usage of a log ring buffer
+type LogBufferInfo struct {
+	Buffer        string `json:"buffer"`
+	SizeBytes     int64  `json:"sizeBytes"`
+	ConsumedBytes int64  `json:"consumedBytes"`
+}
+
+// LogcatFilter is applied to records before they are emitted
+type LogcatFilter struct {
+	MinPriority  string   `json:"minPriority"`  // V, D, I, W, E or F
+	Tags         []string `json:"tags"`         // only these tags when non-empty
+	ExcludeTags  []string `json:"excludeTags"`  // never these tags
+	MessageRegex string   `json:"messageRegex"` // must match the message when set
+	Include      string   `json:"include"`      // must appear in "tag: message"
+	IncludeRegex bool     `json:"includeRegex"`
+	Exclude      string   `json:"exclude"` // must not appear in "tag: message"
+	ExcludeRegex bool     `json:"excludeRegex"`
+}
+
+// TraceFile is an ANR trace or native tombstone on the device
+type TraceFile struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	LocalPath string `json:"localPath,omitempty"` // set when extracted from a bugreport
+	Size      int64  `json:"size"`
+	ModTime   int64  `json:"modTime"`
+	Kind      string `json:"kind"` // "anr" or "tombstone"
+	Process   string `json:"process"`
+	PID       int    `json:"pid"`
 }
 
 // NetworkStats contains network usage statistics
@@ -108,12 +277,868 @@ type ScrcpyConfig struct {
 	NoClipboardSync    bool   `json:"noClipboardSync"`
 	ShowFps            bool   `json:"showFps"`
 	NoPowerOn          bool   `json:"noPowerOn"`
+	CameraFacing       string `json:"cameraFacing"`   // "front", "back" or "external"
+	NewDisplay         bool   `json:"newDisplay"`     // mirror a fresh virtual display
+	NewDisplaySize     string `json:"newDisplaySize"` // "WIDTHxHEIGHT", empty for the main display size
+	NewDisplayDpi      int    `json:"newDisplayDpi"`
+	StartApp           string `json:"startApp"`     // package to launch on start, e.g. into the new display
+	AudioSource        string `json:"audioSource"`  // "output" (default) or "mic"
+	AudioBitRate       int    `json:"audioBitRate"` // Kbps
+	AudioBuffer        int    `json:"audioBuffer"`  // ms
+	Crop               string `json:"crop"`         // "width:height:x:y"
+	Rotation           int    `json:"rotation"`     // 0, 90, 180 or 270
+	AllowMultiple      bool   `json:"allowMultiple"`
+}
+
+// BurstFrame is one screenshot of a burst with its measured timing
+type BurstFrame struct {
+	Index      int    `json:"index"`
+	Path       string `json:"path"`
+	OffsetMs   int64  `json:"offsetMs"`   // since the first frame was requested
+	IntervalMs int64  `json:"intervalMs"` // since the previous frame, 0 for the first
+}
+
+// BurstResult summarizes a screenshot burst
+type BurstResult struct {
+	DeviceID        string       `json:"deviceId"`
+	Dir             string       `json:"dir"`
+	Requested       int          `json:"requested"`
+	IntervalMs      int          `json:"intervalMs"`
+	Frames          []BurstFrame `json:"frames"`
+	AvgIntervalMs   int64        `json:"avgIntervalMs"`
+	PersistentShell bool         `json:"persistentShell"`
+	Cancelled       bool         `json:"cancelled"`
+	AnimationPath   string       `json:"animationPath,omitempty"`
+}
+
+// ManagedProcess is a child process Gaze started and will clean up on exit
+type ManagedProcess struct {
+	PID       int    `json:"pid"`
+	Kind      string `json:"kind"`
+	Command   string `json:"command"`
+	StartedAt int64  `json:"startedAt"`
+	AgeMs     int64  `json:"ageMs"`
+}
+
+// ScrcpyCamera is a camera reported by scrcpy --list-camera-sizes
+type ScrcpyCamera struct {
+	ID     string   `json:"id"`
+	Facing string   `json:"facing"`
+	Size   string   `json:"size"` // native sensor size
+	Fps    []int    `json:"fps"`
+	Sizes  []string `json:"sizes"`
+}
+
+// ScrcpyDisplay is a display reported by scrcpy --list-displays
+type ScrcpyDisplay struct {
+	ID   int    `json:"id"`
+	Size string `json:"size"`
+}
+
+// ScrcpyPreset is a named ScrcpyConfig; built-in presets cannot be deleted or overwritten
+type ScrcpyPreset struct {
+	Name    string       `json:"name"`
+	Config  ScrcpyConfig `json:"config"`
+	BuiltIn bool         `json:"builtIn"`
+}
+
+// ScrcpySession describes a running scrcpy mirror window
+type ScrcpySession struct {
+	SessionID string       `json:"sessionId"`
+	DeviceID  string       `json:"deviceId"`
+	PID       int          `json:"pid"`
+	StartTime int64        `json:"startTime"`
+	Config    ScrcpyConfig `json:"config"`
+	Warnings  []string     `json:"warnings,omitempty"`
+}
+
+// RecordOptions configures a screen recording
+type RecordOptions struct {
+	Format      string `json:"format"`      // "mp4" or "mkv"
+	MaxDuration int    `json:"maxDuration"` // seconds, 0 = unlimited
+	BitRate     int    `json:"bitRate"`     // Mbps
+	MaxSize     int    `json:"maxSize"`     // longest video edge in pixels
+	MaxFileSize int64  `json:"maxFileSize"` // bytes, 0 = unlimited
+	NoPlayback  bool   `json:"noPlayback"`  // record without opening a mirror window
+	NoAudio     bool   `json:"noAudio"`
+	VideoCodec  string `json:"videoCodec"`
+	Backend     string `json:"backend"` // "scrcpy", "screenrecord" or empty to pick automatically
+	Size        string `json:"size"`    // screenrecord only, "WIDTHxHEIGHT"
+	Concat      bool   `json:"concat"`  // screenrecord only, join segments with ffmpeg when available
+}
+
+// RecordingInfo describes a screen recording on the host
+type RecordingInfo struct {
+	Path       string   `json:"path"`
+	Name       string   `json:"name"`
+	Size       int64    `json:"size"`
+	DurationMs int64    `json:"durationMs"`
+	DeviceID   string   `json:"deviceId"`
+	Model      string   `json:"model"`
+	Backend    string   `json:"backend"` // "scrcpy" or "screenrecord" (video only)
+	Parts      []string `json:"parts,omitempty"`
+	CreatedAt  int64    `json:"createdAt"`
 }
 
 // AppSettings contains persistent application settings
 type AppSettings struct {
-	LastActive   map[string]int64 `json:"lastActive"`
-	PinnedSerial string           `json:"pinnedSerial"`
+	LastActive      map[string]int64  `json:"lastActive"`
+	PinnedSerial    string            `json:"pinnedSerial"`
+	PreviewSizeCap  int64             `json:"previewSizeCap,omitempty"`
+	RecordingsDir   string            `json:"recordingsDir,omitempty"`
+	TouchClassifier *ClassifierConfig `json:"touchClassifier,omitempty"`
+	WorkflowReports int               `json:"workflowReports,omitempty"` // Run reports kept
+
+	DangerousCommands *dangerousCommandSettings `json:"dangerousCommands,omitempty"`
+}
+
+// dangerousCommandSettings is the persisted part of the dangerous-command policy
+type dangerousCommandSettings struct {
+	Disabled         bool                   `json:"disabled,omitempty"`
+	DisabledBuiltins []string               `json:"disabledBuiltins,omitempty"`
+	Custom           []DangerousCommandRule `json:"custom,omitempty"`
+}
+
+// DangerousCommandRule is a pattern for commands that need confirmation before they run
+type DangerousCommandRule struct {
+	ID          string `json:"id"`
+	Pattern     string `json:"pattern"`
+	Description string `json:"description"`
+	Builtin     bool   `json:"builtin"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// DangerousCommandPolicy is the full rule set shown in settings
+type DangerousCommandPolicy struct {
+	Enabled bool                   `json:"enabled"`
+	Rules   []DangerousCommandRule `json:"rules"`
+}
+
+// DangerousCommandCheck is the result of CheckDangerousCommand
+type DangerousCommandCheck struct {
+	Dangerous   bool   `json:"dangerous"`
+	RuleID      string `json:"ruleId,omitempty"`
+	Description string `json:"description,omitempty"`
+	Token       string `json:"token,omitempty"` // Pass back to run the command once
+}
+
+// ResourceSample is one reading of a device's CPU and memory load
+type ResourceSample struct {
+	DeviceID     string         `json:"deviceId"`
+	Timestamp    int64          `json:"timestamp"`  // Unix milliseconds
+	CPUPercent   float64        `json:"cpuPercent"` // 0-100 across all cores
+	CoreCount    int            `json:"coreCount"`
+	CoreLoads    []float64      `json:"coreLoads"` // 0-100 per core; empty on the first sample
+	MemTotal     int64          `json:"memTotal"`  // Bytes
+	MemUsed      int64          `json:"memUsed"`
+	MemFree      int64          `json:"memFree"`
+	MemAvailable int64          `json:"memAvailable"`
+	TopByCPU     []ProcessUsage `json:"topByCpu"`
+	TopByMemory  []ProcessUsage `json:"topByMemory"`
+}
+
+// ProcessUsage is a process line from top
+type ProcessUsage struct {
+	PID  int     `json:"pid"`
+	Name string  `json:"name"`
+	CPU  float64 `json:"cpu"`
+	RSS  int64   `json:"rss"` // Bytes
+}
+
+// AppMemoryInfo is the parsed `dumpsys meminfo <package>` of an app's main process. Sizes
+// are in kilobytes, as dumpsys prints them.
+type AppMemoryInfo struct {
+	Package   string `json:"package"`
+	Process   string `json:"process"`
+	PID       int    `json:"pid"`
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+
+	// App Summary (Pss)
+	JavaHeap     int64 `json:"javaHeap"`
+	NativeHeap   int64 `json:"nativeHeap"`
+	Code         int64 `json:"code"`
+	Stack        int64 `json:"stack"`
+	Graphics     int64 `json:"graphics"`
+	PrivateOther int64 `json:"privateOther"`
+	System       int64 `json:"system"`
+
+	TotalPss          int64 `json:"totalPss"`
+	TotalRss          int64 `json:"totalRss"` // Zero before Android 10
+	TotalSwapPss      int64 `json:"totalSwapPss"`
+	TotalPrivateDirty int64 `json:"totalPrivateDirty"`
+	TotalPrivateClean int64 `json:"totalPrivateClean"`
+
+	Views        int64 `json:"views"`
+	ViewRootImpl int64 `json:"viewRootImpl"`
+	Activities   int64 `json:"activities"`
+	AppContexts  int64 `json:"appContexts"`
+
+	Rows       []MeminfoRow      `json:"rows"`       // The per-category table, TOTAL included
+	Summary    map[string]int64  `json:"summary"`    // App Summary Pss by label
+	SummaryRss map[string]int64  `json:"summaryRss"` // App Summary Rss by label
+	Objects    map[string]int64  `json:"objects"`
+	SQL        map[string]int64  `json:"sql"`
+	Extras     map[string]string `json:"extras"` // Unrecognised sections, verbatim
+}
+
+// MeminfoRow is one line of the meminfo table, keyed by column ("Pss Total", "Private Dirty", ...)
+type MeminfoRow struct {
+	Name   string           `json:"name"`
+	Values map[string]int64 `json:"values"`
+}
+
+// FrameStats is the parsed `dumpsys gfxinfo <package> framestats`
+type FrameStats struct {
+	Package   string `json:"package"`
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+
+	TotalFrames         int                    `json:"totalFrames"`
+	JankyFrames         int                    `json:"jankyFrames"`
+	JankyPercent        float64                `json:"jankyPercent"`
+	P50                 int                    `json:"p50"` // Milliseconds
+	P90                 int                    `json:"p90"`
+	P95                 int                    `json:"p95"`
+	P99                 int                    `json:"p99"`
+	MissedVsync         int                    `json:"missedVsync"`
+	HighInputLatency    int                    `json:"highInputLatency"`
+	SlowUIThread        int                    `json:"slowUiThread"`
+	SlowBitmapUploads   int                    `json:"slowBitmapUploads"`
+	SlowDrawCommands    int                    `json:"slowDrawCommands"`
+	FrameDeadlineMissed int                    `json:"frameDeadlineMissed"`
+	Histogram           []FrameHistogramBucket `json:"histogram"` // Non-empty buckets only
+
+	// Per-frame timings; false when the device or app doesn't report framestats
+	FramestatsAvailable bool          `json:"framestatsAvailable"`
+	Frames              []FrameTiming `json:"frames"`
+}
+
+// FrameHistogramBucket counts frames that took up to Ms milliseconds
+type FrameHistogramBucket struct {
+	Ms    int `json:"ms"`
+	Count int `json:"count"`
+}
+
+// FrameTiming is one frame from framestats, broken into its render phases
+type FrameTiming struct {
+	IntendedVsync int64   `json:"intendedVsync"` // Nanoseconds, device monotonic clock
+	DurationMs    float64 `json:"durationMs"`
+	Janky         bool    `json:"janky"`
+	InputMs       float64 `json:"inputMs"`
+	AnimationMs   float64 `json:"animationMs"`
+	LayoutMs      float64 `json:"layoutMs"`
+	DrawMs        float64 `json:"drawMs"`
+	SyncMs        float64 `json:"syncMs"`
+	GPUMs         float64 `json:"gpuMs"` // Issue draw commands until the frame completed
+}
+
+// AppNetworkStats is the traffic counted per UID since boot (qtaguid) or over the kept
+// netstats history
+type AppNetworkStats struct {
+	DeviceID  string            `json:"deviceId"`
+	Timestamp int64             `json:"timestamp"` // Unix milliseconds
+	Source    string            `json:"source"`    // "qtaguid" or "netstats"
+	RxMobile  int64             `json:"rxMobile"`  // Bytes
+	TxMobile  int64             `json:"txMobile"`
+	RxWifi    int64             `json:"rxWifi"`
+	TxWifi    int64             `json:"txWifi"`
+	Apps      []AppNetworkUsage `json:"apps"` // Busiest first
+}
+
+// AppNetworkUsage is one UID's traffic. Shared UIDs list all their packages.
+type AppNetworkUsage struct {
+	UID      int      `json:"uid"`
+	Label    string   `json:"label"`
+	Packages []string `json:"packages"`
+	RxMobile int64    `json:"rxMobile"`
+	TxMobile int64    `json:"txMobile"`
+	RxWifi   int64    `json:"rxWifi"`
+	TxWifi   int64    `json:"txWifi"`
+}
+
+// NetworkSample is the throughput between two polls of the per-app network monitor
+type NetworkSample struct {
+	DeviceID  string           `json:"deviceId"`
+	Timestamp int64            `json:"timestamp"`
+	RxTotal   float64          `json:"rxTotal"` // Bytes per second
+	TxTotal   float64          `json:"txTotal"`
+	Apps      []AppNetworkRate `json:"apps"` // Apps with traffic, busiest first
+}
+
+// AppNetworkRate is one UID's throughput in bytes per second
+type AppNetworkRate struct {
+	UID      int     `json:"uid"`
+	Label    string  `json:"label"`
+	RxMobile float64 `json:"rxMobile"`
+	TxMobile float64 `json:"txMobile"`
+	RxWifi   float64 `json:"rxWifi"`
+	TxWifi   float64 `json:"txWifi"`
+}
+
+// ProcessInfo is one row of the device's process list
+type ProcessInfo struct {
+	PID      int    `json:"pid"`
+	PPID     int    `json:"ppid"`
+	User     string `json:"user"`
+	Nice     int    `json:"nice"`
+	RSS      int64  `json:"rss"` // Bytes
+	Name     string `json:"name"`
+	Package  string `json:"package,omitempty"` // Set for app processes
+	Killable bool   `json:"killable"`          // Can be stopped without root
+}
+
+// KillResult reports how KillProcess stopped a process
+type KillResult struct {
+	PID     int    `json:"pid"`
+	Method  string `json:"method"` // "kill" or "force-stop"
+	Package string `json:"package,omitempty"`
+}
+
+// PortForward is an adb forward (host socket to device) or reverse (device socket to host)
+type PortForward struct {
+	Direction string `json:"direction"` // "forward" or "reverse"
+	Local     string `json:"local"`     // Host side, e.g. tcp:8080
+	Remote    string `json:"remote"`    // Device side, e.g. tcp:8080, localabstract:chrome_devtools_remote, jdwp:1234
+	Label     string `json:"label,omitempty"`
+	Favorite  bool   `json:"favorite"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+}
+
+// PortForwardResult is the outcome of re-applying one favorite
+type PortForwardResult struct {
+	Forward PortForward `json:"forward"`
+	Success bool        `json:"success"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// GlobalProxy is the device-wide HTTP proxy setting
+type GlobalProxy struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Raw     string `json:"raw"`               // The setting as stored, e.g. "null" or ":0"
+	Warning string `json:"warning,omitempty"` // Caveats for the device's Android version
+}
+
+// WifiInfo is the Wi-Fi state from dumpsys wifi
+type WifiInfo struct {
+	Enabled       bool   `json:"enabled"`
+	Connected     bool   `json:"connected"`
+	State         string `json:"state"` // Supplicant state, e.g. COMPLETED
+	SSID          string `json:"ssid"`
+	BSSID         string `json:"bssid"`
+	RSSI          int    `json:"rssi"` // dBm
+	LinkSpeedMbps int    `json:"linkSpeedMbps"`
+	FrequencyMHz  int    `json:"frequencyMhz"`
+	IP            string `json:"ip"`
+}
+
+// RadioToggleResult is the outcome of turning airplane mode, mobile data or Bluetooth on or off
+type RadioToggleResult struct {
+	Radio     string `json:"radio"`
+	Requested bool   `json:"requested"`
+	Enabled   bool   `json:"enabled"`  // State read back after the toggle
+	Verified  bool   `json:"verified"` // Enabled matched Requested before the timeout
+	Method    string `json:"method"`   // Mechanism used, which depends on the Android version
+	Warning   string `json:"warning,omitempty"`
+}
+
+// SettingEntry is one row of a settings table (system, secure or global)
+type SettingEntry struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Dangerous bool   `json:"dangerous"`
+	Note      string `json:"note,omitempty"` // Why a dangerous key is risky
+}
+
+// SettingsSnapshot is a stored copy of a device's settings tables
+type SettingsSnapshot struct {
+	ID        string                       `json:"id"`
+	Name      string                       `json:"name"`
+	DeviceID  string                       `json:"deviceId"`
+	Serial    string                       `json:"serial"`
+	CreatedAt int64                        `json:"createdAt"`
+	Count     int                          `json:"count"`
+	Values    map[string]map[string]string `json:"values,omitempty"` // namespace -> key -> value
+}
+
+// SettingChange is a key that differs between two settings snapshots
+type SettingChange struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Change    string `json:"change"` // added, removed, changed
+	Before    string `json:"before"`
+	After     string `json:"after"`
+	Dangerous bool   `json:"dangerous"`
+}
+
+// LocaleTimeInfo is the device's language, time zone and clock
+type LocaleTimeInfo struct {
+	Locale       string `json:"locale"` // BCP 47 tag, e.g. en-US
+	Timezone     string `json:"timezone"`
+	DeviceTime   int64  `json:"deviceTime"`   // Unix seconds
+	ClockSkewSec int64  `json:"clockSkewSec"` // Device clock minus this computer's
+	AutoTime     bool   `json:"autoTime"`
+	AutoTimezone bool   `json:"autoTimezone"`
+}
+
+// LocaleTimeChange is the outcome of changing the locale, time zone or clock
+type LocaleTimeChange struct {
+	Setting         string `json:"setting"`         // locale, timezone, time
+	Value           string `json:"value"`           // As read back from the device
+	Method          string `json:"method"`          // Mechanism used, which depends on Android version and root
+	RestartRequired string `json:"restartRequired"` // none, apps or reboot
+	Message         string `json:"message,omitempty"`
+}
+
+// SideloadPackageInfo describes a zip before it is sideloaded
+type SideloadPackageInfo struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	Kind   string `json:"kind,omitempty"` // "ab_ota" (payload.bin) or "update_zip"
+}
+
+// SideloadResult is a completed sideload
+type SideloadResult struct {
+	DeviceID   string              `json:"deviceId"`
+	Package    SideloadPackageInfo `json:"package"`
+	DurationMs int64               `json:"durationMs"`
+	Warning    string              `json:"warning,omitempty"`
+}
+
+// FastbootVars are the bootloader variables of a fastboot device
+type FastbootVars struct {
+	Serial            string            `json:"serial"`
+	Product           string            `json:"product"`
+	Unlocked          bool              `json:"unlocked"`
+	SecureBoot        bool              `json:"secureBoot"`
+	CurrentSlot       string            `json:"currentSlot,omitempty"` // A/B devices only
+	SlotCount         string            `json:"slotCount,omitempty"`
+	Userspace         bool              `json:"userspace"` // In fastbootd rather than the bootloader
+	BootloaderVersion string            `json:"bootloaderVersion,omitempty"`
+	BasebandVersion   string            `json:"basebandVersion,omitempty"`
+	Vars              map[string]string `json:"vars"`
+}
+
+// HeapDumpResult is a heap dump pulled from the device
+type HeapDumpResult struct {
+	Package   string `json:"package"`
+	LocalPath string `json:"localPath"`
+	Size      int64  `json:"size"`
+	Converted bool   `json:"converted"` // Converted to standard hprof with hprof-conv
+	Warning   string `json:"warning,omitempty"`
+}
+
+// ScheduledJob is a job registered with JobScheduler
+type ScheduledJob struct {
+	Package                string   `json:"package"`
+	UID                    string   `json:"uid"` // e.g. u0a123
+	JobID                  int      `json:"jobId"`
+	Service                string   `json:"service"`
+	RequiredConstraints    []string `json:"requiredConstraints"`
+	SatisfiedConstraints   []string `json:"satisfiedConstraints"`
+	UnsatisfiedConstraints []string `json:"unsatisfiedConstraints"`
+	StandbyBucket          string   `json:"standbyBucket,omitempty"`
+	Interval               string   `json:"interval,omitempty"` // Periodic jobs only
+	NextRun                string   `json:"nextRun,omitempty"`  // Earliest run, relative as dumped
+	NextRunAt              int64    `json:"nextRunAt"`          // Unix ms, 0 for none
+	Deadline               string   `json:"deadline,omitempty"`
+	DeadlineAt             int64    `json:"deadlineAt"`
+	LastRun                string   `json:"lastRun,omitempty"`
+	LastRunAt              int64    `json:"lastRunAt"`
+	Ready                  bool     `json:"ready"`
+	Raw                    string   `json:"raw"` // Lines of the entry not parsed above
+}
+
+// ScheduledAlarm is a pending AlarmManager alarm
+type ScheduledAlarm struct {
+	Package        string `json:"package"`
+	Type           string `json:"type"` // RTC_WAKEUP, RTC, ELAPSED_WAKEUP or ELAPSED
+	Tag            string `json:"tag"`
+	TriggerIn      string `json:"triggerIn"` // Relative as dumped
+	TriggerAt      int64  `json:"triggerAt"` // Unix ms
+	Window         string `json:"window,omitempty"`
+	RepeatInterval string `json:"repeatInterval,omitempty"`
+	Batch          string `json:"batch,omitempty"` // Before Android 12
+	Raw            string `json:"raw"`
+}
+
+// AlarmPackage groups a package's pending alarms
+type AlarmPackage struct {
+	Package string           `json:"package"`
+	Alarms  []ScheduledAlarm `json:"alarms"`
+}
+
+// DozeState is the device's idle state as reported by deviceidle
+type DozeState struct {
+	Deep            string   `json:"deep"`  // ACTIVE, INACTIVE, IDLE_PENDING, SENSING, LOCATING, IDLE, IDLE_MAINTENANCE
+	Light           string   `json:"light"` // ACTIVE, INACTIVE, IDLE, IDLE_MAINTENANCE, OVERRIDE
+	Forced          bool     `json:"forced"`
+	Charging        bool     `json:"charging"`
+	UserWhitelist   []string `json:"userWhitelist"`
+	SystemWhitelist []string `json:"systemWhitelist"`
+}
+
+// SecurityCheck is one item of a security report
+type SecurityCheck struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Value  string `json:"value"`
+	Status string `json:"status"` // pass, warn or info
+	Detail string `json:"detail,omitempty"`
+}
+
+// SecurityReport is a device's security posture
+type SecurityReport struct {
+	DeviceID              string          `json:"deviceId"`
+	Serial                string          `json:"serial"`
+	Model                 string          `json:"model"`
+	CollectedAt           int64           `json:"collectedAt"`
+	Checks                []SecurityCheck `json:"checks"`
+	Passed                int             `json:"passed"`
+	Warnings              int             `json:"warnings"`
+	AccessibilityServices []string        `json:"accessibilityServices"`
+	DeviceOwners          []string        `json:"deviceOwners"`
+	DeviceAdmins          []string        `json:"deviceAdmins"`
+	InstallSources        []string        `json:"installSources"` // Apps allowed to install other apps
+}
+
+// NotificationEntry is a posted notification. Title and text are empty when the build
+// redacts them.
+type NotificationEntry struct {
+	Key        string `json:"key"` // Pass to DismissNotification
+	Package    string `json:"package"`
+	ID         int    `json:"id"`
+	Tag        string `json:"tag,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+	Importance int    `json:"importance"`
+	When       int64  `json:"when,omitempty"` // Unix milliseconds
+	Title      string `json:"title,omitempty"`
+	Text       string `json:"text,omitempty"`
+	SubText    string `json:"subText,omitempty"`
+	Ongoing    bool   `json:"ongoing"`
+	Redacted   bool   `json:"redacted"`
+}
+
+// InstrumentationTarget is a test runner installed on the device
+type InstrumentationTarget struct {
+	Component     string `json:"component"` // Pass to InstrumentationOptions.Runner
+	TestPackage   string `json:"testPackage"`
+	Runner        string `json:"runner"`
+	TargetPackage string `json:"targetPackage"`
+}
+
+// InstrumentationOptions selects what am instrument runs
+type InstrumentationOptions struct {
+	Runner      string            `json:"runner"`  // testpkg/runner component
+	Class       string            `json:"class"`   // Test class, or class#method
+	Method      string            `json:"method"`  // Method within Class
+	Package     string            `json:"package"` // Java package to run all tests in
+	Args        map[string]string `json:"args"`    // Extra -e arguments for the runner
+	PullOutputs bool              `json:"pullOutputs"`
+	OutputDir   string            `json:"outputDir"` // Device folder to pull, default AndroidX Test Storage's
+	LocalDir    string            `json:"localDir"`  // Where to pull it, default under the recordings folder
+}
+
+// InstrumentationTestResult is the outcome of one test
+type InstrumentationTestResult struct {
+	Class      string `json:"class"`
+	Name       string `json:"name"`
+	Status     string `json:"status"` // passed, failed, error, ignored or assumption_failed
+	Stack      string `json:"stack,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// InstrumentationSummary is the state and results of a test run
+type InstrumentationSummary struct {
+	DeviceID    string                      `json:"deviceId"`
+	Runner      string                      `json:"runner"`
+	Command     string                      `json:"command"`
+	State       string                      `json:"state"` // running, passed, failed, cancelled or error
+	StartedAt   int64                       `json:"startedAt"`
+	DurationMs  int64                       `json:"durationMs"`
+	Total       int                         `json:"total"`
+	Passed      int                         `json:"passed"`
+	Failed      int                         `json:"failed"`
+	Ignored     int                         `json:"ignored"`
+	Tests       []InstrumentationTestResult `json:"tests"`
+	RunError    string                      `json:"runError,omitempty"` // e.g. the app under test crashed
+	OutputPath  string                      `json:"outputPath,omitempty"`
+	OutputError string                      `json:"outputError,omitempty"`
+}
+
+// MonkeyOptions configures a monkey stress test
+type MonkeyOptions struct {
+	Packages   []string `json:"packages"`
+	Count      int      `json:"count"`      // Events to inject, 0 = 1000
+	ThrottleMs int      `json:"throttleMs"` // Delay between events
+	Seed       int64    `json:"seed"`       // 0 = random; reuse a result's seed to repeat a run
+	// Percentages by event type: touch, motion, trackball, nav, majornav, syskeys, appswitch,
+	// anyevent. Types left out keep monkey's defaults.
+	Percentages    map[string]int `json:"percentages"`
+	IgnoreCrashes  bool           `json:"ignoreCrashes"`  // Keep going after a crash
+	IgnoreTimeouts bool           `json:"ignoreTimeouts"` // Keep going after an ANR
+}
+
+// MonkeyCrash is a crash or ANR monkey reported
+type MonkeyCrash struct {
+	Type     string `json:"type"` // crash or anr
+	Package  string `json:"package"`
+	PID      int    `json:"pid"`
+	ShortMsg string `json:"shortMsg"`
+	Details  string `json:"details"` // Stack trace or ANR report
+	AtEvent  int    `json:"atEvent"` // Events sent before it happened
+}
+
+// MonkeyResult is the state and outcome of a monkey test
+type MonkeyResult struct {
+	DeviceID        string        `json:"deviceId"`
+	Packages        []string      `json:"packages"`
+	Seed            int64         `json:"seed"`
+	Command         string        `json:"command"`
+	EventsRequested int           `json:"eventsRequested"`
+	EventsInjected  int           `json:"eventsInjected"`
+	State           string        `json:"state"` // running, completed, aborted, stopped or error
+	StartedAt       int64         `json:"startedAt"`
+	DurationMs      int64         `json:"durationMs"`
+	Crashes         []MonkeyCrash `json:"crashes"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// CurrentActivity is the activity in the foreground
+type CurrentActivity struct {
+	Component string `json:"component"` // As printed by the system, e.g. com.android.settings/.Settings
+	Package   string `json:"package"`
+	Activity  string `json:"activity"` // Fully qualified class name
+}
+
+// ActivityTask is a task with its activities, top first
+type ActivityTask struct {
+	ID         int      `json:"id"`
+	Affinity   string   `json:"affinity"`
+	Type       string   `json:"type,omitempty"` // standard, home, recents... (Android 10+)
+	Activities []string `json:"activities"`
+	Focused    bool     `json:"focused"` // Holds the resumed activity
+}
+
+// DeepLinkResult reports how the device handled a deep link
+type DeepLinkResult struct {
+	URL             string `json:"url"`
+	ExpectedPackage string `json:"expectedPackage"`
+	Package         string `json:"package"`  // Empty when nothing handled it or the chooser is showing
+	Activity        string `json:"activity"` // Foreground component after the launch
+	Chooser         bool   `json:"chooser"`  // The disambiguation dialog appeared
+	MatchesExpected bool   `json:"matchesExpected"`
+	ResolveMs       int64  `json:"resolveMs"`
+	Warning         string `json:"warning,omitempty"`
+	Error           string `json:"error,omitempty"` // e.g. no app can open the URL
+}
+
+// DeepLinkHandler is an app's app-link claim on a domain
+type DeepLinkHandler struct {
+	Package  string `json:"package"`
+	Domain   string `json:"domain"`
+	State    string `json:"state"` // verified, none, 1024, always, ask...
+	Verified bool   `json:"verified"`
+}
+
+// IntentExtra is a typed extra for an IntentSpec
+type IntentExtra struct {
+	Key    string   `json:"key"`
+	Type   string   `json:"type"` // string, int, bool, long, float or string_array
+	Value  string   `json:"value"`
+	Values []string `json:"values,omitempty"` // For string_array
+}
+
+// IntentSpec describes an intent for am start, broadcast and the service commands
+type IntentSpec struct {
+	Action     string        `json:"action"`
+	Data       string        `json:"data"` // URI
+	MimeType   string        `json:"mimeType"`
+	Component  string        `json:"component"` // package/.Class
+	Package    string        `json:"package"`   // Restricts resolution when no component is set
+	Categories []string      `json:"categories"`
+	Flags      []string      `json:"flags"` // FLAG_ names or hex/decimal values
+	Extras     []IntentExtra `json:"extras"`
+}
+
+// IntentResult is what am reported for an intent
+type IntentResult struct {
+	Command         string `json:"command"`
+	Output          string `json:"output"`
+	Status          string `json:"status,omitempty"`   // am start -W status, e.g. ok
+	Activity        string `json:"activity,omitempty"` // Resolved component
+	TotalTimeMs     int    `json:"totalTimeMs,omitempty"`
+	Warning         string `json:"warning,omitempty"` // e.g. "Activity not started, its current task has been brought to the front"
+	Error           string `json:"error,omitempty"`
+	BroadcastResult int    `json:"broadcastResult,omitempty"`
+	BroadcastData   string `json:"broadcastData,omitempty"`
+}
+
+// IntentPreset is a saved intent
+type IntentPreset struct {
+	Name   string     `json:"name"`
+	Kind   string     `json:"kind"` // activity, broadcast or service
+	Intent IntentSpec `json:"intent"`
+}
+
+// CameraRollOptions narrows and controls a PullCameraRoll run
+type CameraRollOptions struct {
+	Since       int64    `json:"since"`       // Unix seconds, 0 = no lower bound
+	Until       int64    `json:"until"`       // Unix seconds, 0 = no upper bound
+	DeleteAfter bool     `json:"deleteAfter"` // Remove device files once their checksum matches
+	Folders     []string `json:"folders"`     // Device folders to pull, empty = DCIM/Camera and screenshots
+}
+
+// CameraRollSummary reports what a PullCameraRoll run did
+type CameraRollSummary struct {
+	TransferID  string   `json:"transferId"`
+	Found       int      `json:"found"`
+	New         int      `json:"new"`
+	Skipped     int      `json:"skipped"` // Already pulled on an earlier run
+	Failed      int      `json:"failed"`
+	FailedFiles []string `json:"failedFiles"`
+	Bytes       int64    `json:"bytes"`
+	FreedBytes  int64    `json:"freedBytes"` // Device space released by DeleteAfter
+	DurationMs  int64    `json:"durationMs"`
+	Cancelled   bool     `json:"cancelled"`
+}
+
+// OrientationInfo is the display's rotation and the settings that control it
+type OrientationInfo struct {
+	Rotation     int    `json:"rotation"` // Quarter turns, 0-3
+	Name         string `json:"name"`     // portrait, landscape, reverse_portrait or reverse_landscape
+	AutoRotate   bool   `json:"autoRotate"`
+	UserRotation int    `json:"userRotation"` // Rotation used while auto-rotate is off
+}
+
+// GeoPoint is a position for mock locations and routes
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+	Alt float64 `json:"alt"` // Meters
+}
+
+// KeyCodeInfo is a named Android key code for the key palette
+type KeyCodeInfo struct {
+	Name  string `json:"name"` // KEYCODE_ suffix, e.g. BACK
+	Code  int    `json:"code"`
+	Group string `json:"group"` // navigation, dpad, editing, volume, media, system
+}
+
+// TypeTextResult reports how TypeText typed and what it couldn't
+type TypeTextResult struct {
+	Method  string   `json:"method"`  // "ime" (ADBKeyBoard) or "input_text"
+	Typed   int      `json:"typed"`   // Characters sent
+	Dropped []string `json:"dropped"` // Characters the device had no way to type
+}
+
+// DemoModeOptions configures the status bar shown in demo mode. Zero values give a clean
+// default: 10:00, full battery, full Wi-Fi and cellular signal, no notification icons.
+type DemoModeOptions struct {
+	Clock             string `json:"clock"`        // HH:MM
+	BatteryLevel      int    `json:"batteryLevel"` // 1-100
+	Charging          bool   `json:"charging"`
+	WifiLevel         int    `json:"wifiLevel"`      // 1-4, negative hides the icon
+	MobileLevel       int    `json:"mobileLevel"`    // 1-4, negative hides the icon
+	MobileDataType    string `json:"mobileDataType"` // e.g. lte, 5g; empty shows none
+	ShowNotifications bool   `json:"showNotifications"`
+}
+
+// DisplaySettings are the device's current dark mode, font scale, density and size
+type DisplaySettings struct {
+	DarkMode        string  `json:"darkMode"` // yes, no, auto or custom
+	FontScale       float64 `json:"fontScale"`
+	Density         int     `json:"density"` // Override dpi, 0 when not overridden
+	PhysicalDensity int     `json:"physicalDensity"`
+	Size            string  `json:"size"` // Override WIDTHxHEIGHT, "" when not overridden
+	PhysicalSize    string  `json:"physicalSize"`
+}
+
+// DisplayProfile is a named set of display settings applied in one go. Zero values leave a
+// setting as it is.
+type DisplayProfile struct {
+	Name      string  `json:"name"`
+	DarkMode  string  `json:"darkMode"`  // yes, no, auto
+	FontScale float64 `json:"fontScale"` // e.g. 1.3
+	Density   int     `json:"density"`   // dpi, -1 resets to the physical density
+	Size      string  `json:"size"`      // WIDTHxHEIGHT, "reset" restores the physical size
+	UpdatedAt int64   `json:"updatedAt,omitempty"`
+}
+
+// AnimationScales are the developer option animation speeds; 0 is off, 1 is normal
+type AnimationScales struct {
+	Window     float64 `json:"window"`
+	Transition float64 `json:"transition"`
+	Animator   float64 `json:"animator"`
+}
+
+// DeveloperToggle is the state of a developer option quick toggle
+type DeveloperToggle struct {
+	Name    string `json:"name"` // show_touches, pointer_location, layout_bounds, gpu_overdraw, dont_keep_activities
+	Label   string `json:"label"`
+	Enabled bool   `json:"enabled"`
+	Value   string `json:"value"` // Raw setting or property value
+}
+
+// PacketCaptureOptions configures StartPacketCapture
+type PacketCaptureOptions struct {
+	Interface string `json:"interface"` // Default "any"
+	Filter    string `json:"filter"`    // BPF expression, e.g. "tcp port 443"
+	SnapLen   int    `json:"snapLen"`   // Bytes kept per packet; 0 keeps whole packets
+	OutputDir string `json:"outputDir"` // Defaults to "Gaze Captures" in the recordings folder
+}
+
+// PacketCaptureStatus is the state of a device's running or most recent tcpdump capture
+type PacketCaptureStatus struct {
+	DeviceID   string `json:"deviceId"`
+	Interface  string `json:"interface"`
+	Filter     string `json:"filter"`
+	State      string `json:"state"` // running, stopping, pulling, done, failed
+	StartedAt  int64  `json:"startedAt"`
+	FinishedAt int64  `json:"finishedAt,omitempty"`
+	Bytes      int64  `json:"bytes"`
+	RemotePath string `json:"remotePath"`
+	LocalPath  string `json:"localPath,omitempty"`
+	Finished   bool   `json:"finished"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TraceOptions configures StartSystemTrace
+type TraceOptions struct {
+	DurationSec  int      `json:"durationSec"`  // Default 10, at most 600
+	BufferSizeMB int      `json:"bufferSizeMb"` // Default 64
+	Categories   []string `json:"categories"`   // sched, freq, gfx, binder, am, wm, ...
+	AppPackage   string   `json:"appPackage"`   // Enables the app's own trace markers
+	HeapProfile  bool     `json:"heapProfile"`  // heapprofd on AppPackage; perfetto only
+	OutputDir    string   `json:"outputDir"`    // Defaults to the recordings folder
+}
+
+// SystemTraceStatus is the state of a device's running or most recent system trace
+type SystemTraceStatus struct {
+	DeviceID    string   `json:"deviceId"`
+	Backend     string   `json:"backend"` // "perfetto" or "atrace"
+	State       string   `json:"state"`   // running, stopping, pulling, done, failed
+	StartedAt   int64    `json:"startedAt"`
+	FinishedAt  int64    `json:"finishedAt,omitempty"`
+	DurationSec int      `json:"durationSec"`
+	Categories  []string `json:"categories"`
+	AppPackage  string   `json:"appPackage,omitempty"`
+	RemotePath  string   `json:"remotePath"`
+	LocalPath   string   `json:"localPath,omitempty"`
+	Finished    bool     `json:"finished"`
+	Error       string   `json:"error,omitempty"`
+	Hint        string   `json:"hint,omitempty"` // How to open the trace
+}
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Timestamp int64  `json:"timestamp"`
+	Action    string `json:"action"`
+	DeviceID  string `json:"deviceId,omitempty"`
+	Detail    string `json:"detail"`
+	Rule      string `json:"rule,omitempty"`
 }
 
 // BatchOperation represents a batch operation to execute on multiple devices
@@ -146,33 +1171,223 @@ type BatchOperationResult struct {
 // TouchEvent represents a single touch event in an automation script
 type TouchEvent struct {
 	Timestamp int64            `json:"timestamp"` // Relative time in milliseconds from script start
-	Type      string           `json:"type"`      // "tap", "swipe", "wait"
+	Type      string           `json:"type"`      // "tap", "swipe", "long_press", "pinch", "key", "text", "wait", "waitForElement", "scrollTo"
 	X         int              `json:"x"`
 	Y         int              `json:"y"`
 	X2        int              `json:"x2,omitempty"`       // End X for swipe
 	Y2        int              `json:"y2,omitempty"`       // End Y for swipe
-	Duration  int              `json:"duration,omitempty"` // Duration in ms for swipe or wait
+	Duration  int              `json:"duration,omitempty"` // Duration in ms for swipe or wait, timeout for waitForElement
 	Selector  *ElementSelector `json:"selector,omitempty"` // Unified selector for smart tap
+	Fingers   []TouchPointer   `json:"fingers,omitempty"`  // Per-finger paths for multi-touch gestures
+	KeyCode   int              `json:"keyCode,omitempty"`  // Android keycode for "key" events
+	Text      string           `json:"text,omitempty"`     // Payload for "text" events
+	Gone      bool             `json:"gone,omitempty"`     // waitForElement: wait for the selector to disappear instead
+	// scrollTo: swipe Direction ("up" by default) inside Container until Selector shows up
+	Container *ElementSelector `json:"container,omitempty"`
+	Direction string           `json:"direction,omitempty"`
+	MaxSwipes int              `json:"maxSwipes,omitempty"`
+	// Text and resource ID of the element under a recorded touch, for display
+	ElementText string `json:"elementText,omitempty"`
+	ElementID   string `json:"elementId,omitempty"`
+	// Activity the recorded touch happened on, e.g. com.android.settings/.Settings
+	Activity string `json:"activity,omitempty"`
+}
+
+// ClassifierConfig holds the thresholds that turn recorded strokes into taps, long presses,
+// double taps and swipes
+type ClassifierConfig struct {
+	LongPressMs       int `json:"longPressMs"`       // Minimum hold for a long press
+	TapMaxDistance    int `json:"tapMaxDistance"`    // Max movement in px for a tap or long press
+	DoubleTapMs       int `json:"doubleTapMs"`       // Max gap between taps of a double tap, negative disables
+	DoubleTapDistance int `json:"doubleTapDistance"` // Max distance in px between the two taps
+}
+
+// RecordingOptions tunes a recording. The filters drop accidental strokes while classifying;
+// the raw sendevent stream keeps everything.
+type RecordingOptions struct {
+	// IgnoreZones drops strokes that start inside any of these rectangles, in the recording's
+	// natural (portrait) screen coordinates, e.g. the gesture bar along the bottom edge
+	IgnoreZones []ScreenRect `json:"ignoreZones"`
+	MinStrokeMs int          `json:"minStrokeMs"` // Drop strokes held for less than this, 0 = keep all
+	// DiscardShortStrokes drops grazes: strokes shorter than ShortStrokeMs that also moved
+	// less than ShortStrokePx
+	DiscardShortStrokes bool `json:"discardShortStrokes"`
+	ShortStrokeMs       int  `json:"shortStrokeMs"`
+	ShortStrokePx       int  `json:"shortStrokePx"`
+	// DisableSelectorCapture skips the background UI dumps that attach selectors to taps in
+	// fast mode, for captures where the extra device load matters
+	DisableSelectorCapture bool `json:"disableSelectorCapture"`
+	// ShowTouches turns on the device's "Show taps" while recording and restores it afterwards
+	ShowTouches bool `json:"showTouches"`
+}
+
+// ScreenRect is a rectangle in screen pixels; Right and Bottom are exclusive
+type ScreenRect struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Right  int `json:"right"`
+	Bottom int `json:"bottom"`
+}
+
+// TouchPointer is one finger's stroke within a multi-touch gesture
+type TouchPointer struct {
+	X        int `json:"x"`
+	Y        int `json:"y"`
+	X2       int `json:"x2"`
+	Y2       int `json:"y2"`
+	Delay    int `json:"delay,omitempty"` // Ms after the gesture's first finger went down
+	Duration int `json:"duration"`        // Ms this finger stayed down
 }
 
 // TouchScript represents a recorded touch automation script
 type TouchScript struct {
+	Version     int          `json:"version"` // Schema version, see touchScriptVersion
 	Name        string       `json:"name"`
 	DeviceID    string       `json:"deviceId"`
 	DeviceModel string       `json:"deviceModel,omitempty"` // Store device model name
 	Resolution  string       `json:"resolution"`            // e.g. "1080x2400"
+	Orientation *int         `json:"orientation,omitempty"` // Display rotation (0-3) while recording; nil when unknown, as in older scripts
 	CreatedAt   string       `json:"createdAt"`
 	Events      []TouchEvent `json:"events"`
+	// PlaybackMode selects "raw" to replay RawInput through sendevent; anything else uses Events
+	PlaybackMode string         `json:"playbackMode,omitempty"`
+	RawInput     *RawTouchInput `json:"rawInput,omitempty"`
+	// AbsoluteCoordinates replays coordinates as recorded, without scaling or rotation
+	AbsoluteCoordinates bool `json:"absoluteCoordinates,omitempty"`
+	// Tags and Folder organise the script library; Folder is a "/"-separated path, "" = top level
+	Tags   []string `json:"tags,omitempty"`
+	Folder string   `json:"folder,omitempty"`
+}
+
+// ScriptFilter selects and orders scripts for ListTouchScripts. Empty fields match everything.
+type ScriptFilter struct {
+	Tag        string `json:"tag"`
+	Folder     string `json:"folder"`     // Exact folder; subfolders are not included
+	Name       string `json:"name"`       // Case-insensitive substring
+	SortBy     string `json:"sortBy"`     // name (default), created or modified
+	Descending bool   `json:"descending"` // Newest or Z first
+}
+
+// TouchScriptSummary is a script's header without its events, for list views
+type TouchScriptSummary struct {
+	Name        string   `json:"name"`
+	DeviceModel string   `json:"deviceModel,omitempty"`
+	Resolution  string   `json:"resolution"`
+	CreatedAt   string   `json:"createdAt"`
+	ModifiedAt  int64    `json:"modifiedAt"` // File modification time, Unix ms
+	EventCount  int      `json:"eventCount"`
+	Tags        []string `json:"tags"`
+	Folder      string   `json:"folder"`
+}
+
+// PlaybackOptions controls how many times and for how long a touch script is replayed
+type PlaybackOptions struct {
+	Repeat            int     `json:"repeat"`            // Iterations to run; 0 or 1 = once, negative = until stopped
+	IntervalMs        int     `json:"intervalMs"`        // Pause between iterations
+	MaxDuration       int     `json:"maxDuration"`       // Total time cap in seconds, 0 = none
+	Speed             float64 `json:"speed"`             // Playback speed multiplier, 0 = 1x
+	StepMode          bool    `json:"stepMode"`          // Pause before every event until StepTouchPlayback
+	DisableAnimations bool    `json:"disableAnimations"` // Animation scales at 0 for the run, restored after
+	ForceOrientation  bool    `json:"forceOrientation"`  // Rotate to the script's recorded orientation for the run
+}
+
+// ScheduledPlayback is a touch script run on a cron schedule
+type ScheduledPlayback struct {
+	ID         string `json:"id"`
+	DeviceID   string `json:"deviceId"`
+	ScriptName string `json:"scriptName"`
+	Cron       string `json:"cron"`              // "minute hour day-of-month month day-of-week" or @hourly/@daily/@weekly
+	NextRun    int64  `json:"nextRun"`           // Unix seconds
+	LastRun    int64  `json:"lastRun,omitempty"` // Unix seconds
+	LastError  string `json:"lastError,omitempty"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// DevicePlaybackResult is one device's outcome within a multi-device playback
+type DevicePlaybackResult struct {
+	DeviceID   string `json:"deviceId"`
+	Success    bool   `json:"success"`
+	Reason     string `json:"reason"` // completed, stopped, timeout or error
+	Error      string `json:"error,omitempty"`
+	Iterations int    `json:"iterations"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// MultiPlaybackReport summarises a script played on several devices at once
+type MultiPlaybackReport struct {
+	BatchID    string                 `json:"batchId"`
+	ScriptName string                 `json:"scriptName"`
+	Results    []DevicePlaybackResult `json:"results"`
+	DurationMs int64                  `json:"durationMs"`
+}
+
+// PlaybackRun is the stored record of one pass through a touch script on one device
+type PlaybackRun struct {
+	ID         string                `json:"id"`
+	DeviceID   string                `json:"deviceId"`
+	ScriptName string                `json:"scriptName"`
+	StartTime  int64                 `json:"startTime"` // Unix ms
+	EndTime    int64                 `json:"endTime"`
+	Status     string                `json:"status"` // completed, aborted or failed
+	Error      string                `json:"error,omitempty"`
+	EventCount int                   `json:"eventCount"` // Events executed
+	MaxDriftMs int64                 `json:"maxDriftMs"`
+	AvgDriftMs int64                 `json:"avgDriftMs"`
+	Warnings   []string              `json:"warnings,omitempty"`
+	Events     []PlaybackEventTiming `json:"events,omitempty"` // Only filled by GetPlaybackRunDetail
+}
+
+// PlaybackEventTiming compares when an event was due with when it actually ran, both as
+// offsets from the start of the run. Drift is mostly adb latency piling up.
+type PlaybackEventTiming struct {
+	Index       int    `json:"index"`
+	Type        string `json:"type"`
+	ScheduledMs int64  `json:"scheduledMs"`
+	ActualMs    int64  `json:"actualMs"`
+	DriftMs     int64  `json:"driftMs"`
+	DurationMs  int64  `json:"durationMs"` // Time the event itself took to execute
+	Warning     string `json:"warning,omitempty"`
+}
+
+// RawTouchInput is the unsimplified getevent stream captured alongside a script
+type RawTouchInput struct {
+	Device string          `json:"device"` // Input device it was recorded from
+	MinX   int             `json:"minX"`
+	MaxX   int             `json:"maxX"`
+	MinY   int             `json:"minY"`
+	MaxY   int             `json:"maxY"`
+	Events []RawInputEvent `json:"events"`
+}
+
+// RawInputEvent is a single evdev event in sendevent's numeric form
+type RawInputEvent struct {
+	Time  int64  `json:"t"` // Relative time in milliseconds from script start
+	Type  uint16 `json:"type"`
+	Code  uint16 `json:"code"`
+	Value int32  `json:"value"`
 }
 
 // ElementInfo stores captured UI element information at touch point
 type ElementInfo struct {
-	X         int              `json:"x"`
-	Y         int              `json:"y"`
-	Class     string           `json:"class"`
-	Bounds    string           `json:"bounds"`
-	Selector  *ElementSelector `json:"selector,omitempty"` // Preferred selector
-	Timestamp int64            `json:"timestamp"`          // Unix timestamp when captured
+	X          int              `json:"x"`
+	Y          int              `json:"y"`
+	Class      string           `json:"class"`
+	Bounds     string           `json:"bounds"`
+	Selector   *ElementSelector `json:"selector,omitempty"` // Preferred selector
+	Timestamp  int64            `json:"timestamp"`          // Unix timestamp when captured
+	Text       string           `json:"text,omitempty"`
+	ResourceID string           `json:"resourceId,omitempty"`
+}
+
+// NodePathStep is one level of an element's ancestor chain, for the inspector breadcrumb
+type NodePathStep struct {
+	Class       string `json:"class"`
+	Index       int    `json:"index"` // Position among the parent's children
+	ResourceID  string `json:"resourceId,omitempty"`
+	Text        string `json:"text,omitempty"`
+	ContentDesc string `json:"contentDesc,omitempty"`
+	Bounds      string `json:"bounds"`
+	Label       string `json:"label"` // e.g. `LinearLayout #row` or `TextView "Wi-Fi"`
 }
 
 // SelectorSuggestion represents a suggested selector option for user to choose
@@ -181,6 +1396,8 @@ type SelectorSuggestion struct {
 	Value       string `json:"value"`       // The selector value
 	Priority    int    `json:"priority"`    // Higher is better (1-5)
 	Description string `json:"description"` // Human-readable description
+	// Selector is set for compound selectors that Type and Value alone can't express
+	Selector *ElementSelector `json:"selector,omitempty"`
 }
 
 // TouchRecordingSession represents an active recording session
@@ -196,8 +1413,20 @@ type TouchRecordingSession struct {
 	MinY               int
 	ElementInfos       []ElementInfo          // Captured element info during recording
 	RecordingMode      string                 // "fast" or "precise"
+	Orientation        *int                   // Display rotation (0-3) when recording started, nil if it couldn't be read
 	IsPaused           bool                   // True when waiting for user selector choice
 	PendingSelectorReq *SelectorChoiceRequest // Current pending selector choice
+	Options            RecordingOptions       // Accidental-touch filtering applied when parsing
+	snapshot           *recordingSnapshot     // Screen dumped for fast-mode selector capture
+	restoreShowTouches bool                   // Show taps was turned on for this recording
+	activityMarks      []activityMark         // Foreground changes, by position in RawEvents
+}
+
+// activityMark records that the focused activity became Component once RawIndex raw events
+// had been recorded
+type activityMark struct {
+	RawIndex  int
+	Component string
 }
 
 // SelectorChoiceRequest represents a request for user to choose a selector
@@ -234,6 +1463,10 @@ type ElementSelector struct {
 	Type  string `json:"type"` // "text", "id", "xpath", "advanced"
 	Value string `json:"value"`
 	Index int    `json:"index,omitempty"`
+	// Within limits the search to the descendants of the element it matches; chains nest
+	Within *ElementSelector `json:"within,omitempty"`
+	// ChildIndex keeps only matches at this position among their parent's children
+	ChildIndex *int `json:"childIndex,omitempty"`
 }
 
 type WorkflowStep struct {
@@ -244,12 +1477,21 @@ type WorkflowStep struct {
 	Value         string           `json:"value,omitempty"`
 	Timeout       int              `json:"timeout,omitempty"`
 	OnError       string           `json:"onError,omitempty"` // "stop", "continue"
+	Retries       int              `json:"retries,omitempty"` // extra attempts after a failure
 	Loop          int              `json:"loop,omitempty"`
 	PostDelay     int              `json:"postDelay,omitempty"`
 	PreWait       int              `json:"preWait,omitempty"`
 	SwipeDistance int              `json:"swipeDistance,omitempty"`
 	SwipeDuration int              `json:"swipeDuration,omitempty"`
-	ConditionType string           `json:"conditionType,omitempty"` // "exists", "not_exists", "text_equals", "text_contains", "variable_equals"
+	ConditionType string           `json:"conditionType,omitempty"` // "exists", "not_exists", "text_equals", "text_contains", "variable_equals", "shell_success", "activity_is"
+	Container     *ElementSelector `json:"container,omitempty"`     // scroll_to: list to swipe in, default the largest scrollable
+	MaxSwipes     int              `json:"maxSwipes,omitempty"`     // scroll_to: give up after this many swipes, default 10
+	// Nested steps (schema version 2)
+	Then          []WorkflowStep `json:"then,omitempty"`          // if: steps run when the condition holds
+	Else          []WorkflowStep `json:"else,omitempty"`          // if: steps run otherwise
+	Body          []WorkflowStep `json:"body,omitempty"`          // repeat: steps run each iteration
+	Times         int            `json:"times,omitempty"`         // repeat: fixed iteration count
+	MaxIterations int            `json:"maxIterations,omitempty"` // repeat: guard for while-condition loops, default 100
 	// Graph Flow Control
 	NextStepId  string `json:"nextStepId,omitempty"`  // Default next step
 	NextSource  string `json:"nextSource,omitempty"`  // Handle ID for next step
@@ -267,6 +1509,7 @@ type WorkflowStep struct {
 }
 
 type Workflow struct {
+	Version     int               `json:"version,omitempty"` // schema version, 0 or 1 for flat graph workflows
 	ID          string            `json:"id"`
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
@@ -274,4 +1517,127 @@ type Workflow struct {
 	Variables   map[string]string `json:"variables,omitempty"`
 	CreatedAt   string            `json:"createdAt"`
 	UpdatedAt   string            `json:"updatedAt"`
+
+	// DisableAnimations sets the device's animation scales to 0 while the workflow runs
+	DisableAnimations bool `json:"disableAnimations,omitempty"`
+}
+
+// WorkflowTrigger starts a saved workflow on its own: when a device connects, when an app is
+// installed on a connected device, or on a cron schedule for the connected devices. Serial
+// and Model narrow which devices it applies to.
+type WorkflowTrigger struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type"` // "device_connected", "app_installed" or "schedule"
+	WorkflowID string `json:"workflowId"`
+	Disabled   bool   `json:"disabled,omitempty"`
+	Serial     string `json:"serial,omitempty"`  // Only this device
+	Model      string `json:"model,omitempty"`   // Only devices whose model contains this
+	Package    string `json:"package,omitempty"` // app_installed: only this package
+	Cron       string `json:"cron,omitempty"`    // schedule: five-field cron expression
+	NextRun    int64  `json:"nextRun,omitempty"` // schedule: Unix seconds
+	LastFired  int64  `json:"lastFired,omitempty"`
+	LastError  string `json:"lastError,omitempty"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// WorkflowDatasetResult summarises a workflow run once per row of a CSV dataset
+type WorkflowDatasetResult struct {
+	Workflow  string               `json:"workflow"`
+	Rows      []WorkflowDatasetRow `json:"rows"`
+	Passed    int                  `json:"passed"`
+	Failed    int                  `json:"failed"`
+	Cancelled bool                 `json:"cancelled"` // stopped before every row ran
+	ElapsedMs int64                `json:"elapsedMs"`
+}
+
+// WorkflowDatasetRow is the outcome of one dataset row; Row counts from 1 after the header
+type WorkflowDatasetRow struct {
+	Row       int               `json:"row"`
+	Variables map[string]string `json:"variables"`
+	Passed    bool              `json:"passed"`
+	Error     string            `json:"error,omitempty"`
+	ElapsedMs int64             `json:"elapsedMs"`
+	RunID     string            `json:"runId,omitempty"` // report of the row's run
+}
+
+// WorkflowRunReport is the stored record of one workflow run. Each report lives in its own
+// directory next to the screenshots and UI dumps taken when steps failed.
+type WorkflowRunReport struct {
+	ID           string               `json:"id"`
+	WorkflowID   string               `json:"workflowId"`
+	WorkflowName string               `json:"workflowName"`
+	DeviceID     string               `json:"deviceId"`
+	Variables    map[string]string    `json:"variables,omitempty"`
+	Trigger      string               `json:"trigger,omitempty"` // ID of the trigger that started the run
+	StartTime    int64                `json:"startTime"`         // Unix ms
+	EndTime      int64                `json:"endTime"`
+	Status       string               `json:"status"` // running, completed, cancelled or failed
+	Error        string               `json:"error,omitempty"`
+	Passed       int                  `json:"passed"` // Steps that succeeded
+	Failed       int                  `json:"failed"`
+	Steps        []WorkflowStepReport `json:"steps,omitempty"` // Only filled by GetWorkflowRunReport
+}
+
+// WorkflowStepReport is the outcome of one executed step, in the order steps finished
+type WorkflowStepReport struct {
+	Path       string `json:"path"` // "3", or "3.1.2" inside if/repeat bodies and sub-workflows
+	StepID     string `json:"stepId"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type"`
+	Selector   string `json:"selector,omitempty"`
+	Status     string `json:"status"` // success, failed or cancelled
+	Attempts   int    `json:"attempts"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+	Screenshot string `json:"screenshot,omitempty"` // File in the report directory
+	Hierarchy  string `json:"hierarchy,omitempty"`  // File in the report directory
+}
+
+// UISnapshot is a saved UI hierarchy, for comparing screens across builds
+type UISnapshot struct {
+	Name        string  `json:"name"`
+	DeviceID    string  `json:"deviceId"`
+	DeviceModel string  `json:"deviceModel"`
+	CreatedAt   string  `json:"createdAt"`
+	Root        *UINode `json:"root"`
+	Screenshot  string  `json:"screenshot,omitempty"` // PNG data URL, filled in by GetUISnapshot
+}
+
+// UISnapshotSummary is a snapshot without its tree, for list views
+type UISnapshotSummary struct {
+	Name          string `json:"name"`
+	DeviceModel   string `json:"deviceModel"`
+	CreatedAt     string `json:"createdAt"`
+	NodeCount     int    `json:"nodeCount"`
+	HasScreenshot bool   `json:"hasScreenshot"`
+}
+
+// UISnapshotDiff compares two snapshots. Rows follow the merged tree in depth-first order so
+// they can be rendered side by side.
+type UISnapshotDiff struct {
+	A       string      `json:"a"`
+	B       string      `json:"b"`
+	Added   int         `json:"added"`
+	Removed int         `json:"removed"`
+	Changed int         `json:"changed"`
+	Rows    []UIDiffRow `json:"rows"`
+}
+
+// UIDiffRow is one node of the merged tree
+type UIDiffRow struct {
+	Depth    int              `json:"depth"`
+	Status   string           `json:"status"` // "same", "added", "removed", "changed"
+	Label    string           `json:"label"`
+	Selector *ElementSelector `json:"selector,omitempty"` // Best selector, for rows that differ
+	Before   *UINode          `json:"before,omitempty"`   // Without children; nil when added
+	After    *UINode          `json:"after,omitempty"`    // Without children; nil when removed
+	Changes  []UIDiffChange   `json:"changes,omitempty"`
+}
+
+// UIDiffChange is one attribute that differs between matched nodes
+type UIDiffChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultUIDumpTTL is how long a UI dump is reused before uiautomator runs again
+const defaultUIDumpTTL = 500 * time.Millisecond
+
+// uiDumpState is the cached dump of one device
+type uiDumpState struct {
+	mu         sync.Mutex // Held for the whole dump so uiautomator never runs twice at once
+	result     *UIHierarchyResult
+	doneAt     time.Time // When the dump finished; the TTL runs from here
+	focus      string    // Focused window when the dump was taken
+	hash       uint64    // Of the raw XML
+	generation int64
+	epoch      int64        // invalidations seen when the dump started
+	invalid    atomic.Int64 // bumped by InvalidateUIDump
+}
+
+// GetUIHierarchy returns the device's UI hierarchy. A dump younger than the cache TTL is reused
+// unless forceRefresh is set or the focused window has changed since it was taken.
+func (a *App) GetUIHierarchy(deviceId string, forceRefresh bool) (*UIHierarchyResult, error) {
+	state := a.uiDumpStateFor(deviceId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	ttl := a.uiDumpCacheTTL()
+	if !forceRefresh && state.result != nil && time.Since(state.doneAt) < ttl && state.epoch == state.invalid.Load() {
+		// Probing the focus is far cheaper than a dump and catches navigation to a new screen
+		if focus, err := a.focusedWindow(deviceId); err == nil && focus == state.focus {
+			cached := *state.result
+			cached.Cached = true
+			return &cached, nil
+		}
+	}
+
+	epoch := state.invalid.Load()
+	focus, _ := a.focusedWindow(deviceId)
+	takenAt := time.Now()
+	result, err := a.runUIDump(deviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(result.RawXML))
+	hash := h.Sum64()
+	if state.result == nil || hash != state.hash {
+		state.generation++
+	}
+	result.Generation = state.generation
+	result.DumpedAt = takenAt.UnixMilli()
+
+	state.result = result
+	// A dump takes seconds, longer than the TTL, so its age counts from when it finished; the
+	// epoch and focus from before it still catch changes made while it ran
+	state.doneAt = time.Now()
+	state.focus = focus
+	state.hash = hash
+	state.epoch = epoch
+	return result, nil
+}
+
+// InvalidateUIDump drops the cached UI dump of a device so the next lookup dumps again
+func (a *App) InvalidateUIDump(deviceId string) {
+	a.uiDumpStateFor(deviceId).invalid.Add(1)
+}
+
+// SetUIDumpCacheTTL sets how long UI dumps are reused, in ms; 0 turns the cache off
+func (a *App) SetUIDumpCacheTTL(ttlMs int) {
+	a.uiDumpMu.Lock()
+	defer a.uiDumpMu.Unlock()
+	a.uiDumpTTL = time.Duration(max(ttlMs, 0)) * time.Millisecond
+}
+
+func (a *App) uiDumpCacheTTL() time.Duration {
+	a.uiDumpMu.Lock()
+	defer a.uiDumpMu.Unlock()
+	return a.uiDumpTTL
+}
+
+func (a *App) uiDumpStateFor(deviceId string) *uiDumpState {
+	a.uiDumpMu.Lock()
+	defer a.uiDumpMu.Unlock()
+	state, ok := a.uiDumps[deviceId]
+	if !ok {
+		state = &uiDumpState{}
+		a.uiDumps[deviceId] = state
+	}
+	return state
+}
+
+func (a *App) runUIDump(deviceId string) (*UIHierarchyResult, error) {
+	if a.uiDumpRun != nil {
+		return a.uiDumpRun(deviceId)
+	}
+	return a.dumpUIHierarchy(deviceId)
+}
+
+// focusedWindow returns the mCurrentFocus line of dumpsys window, e.g.
+// "mCurrentFocus=Window{1a2b3c u0 com.android.settings/.Settings}"
+func (a *App) focusedWindow(deviceId string) (string, error) {
+	if a.uiFocusProbe != nil {
+		return a.uiFocusProbe(deviceId)
+	}
+	out, err := a.RunAdbCommand(deviceId, "shell dumpsys window | grep -m1 mCurrentFocus")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUIDumpCacheTTLCountsFromDumpEnd(t *testing.T) {
+	dumps := 0
+	focus := "mCurrentFocus=Window{1a2b3c u0 com.example.shop/.CartActivity}"
+	a := &App{
+		uiDumps:   make(map[string]*uiDumpState),
+		uiDumpTTL: defaultUIDumpTTL,
+		// Slower than the TTL, like uiautomator on a real device
+		uiDumpRun: func(deviceId string) (*UIHierarchyResult, error) {
+			dumps++
+			time.Sleep(defaultUIDumpTTL + 100*time.Millisecond)
+			return &UIHierarchyResult{Root: &UINode{Text: "Cart"}, RawXML: "<hierarchy/>"}, nil
+		},
+		uiFocusProbe: func(deviceId string) (string, error) { return focus, nil },
+	}
+
+	first, err := a.GetUIHierarchy("emulator-5554", false)
+	if err != nil || first.Cached {
+		t.Fatalf("first call: cached %v, err %v", first != nil && first.Cached, err)
+	}
+	second, err := a.GetUIHierarchy("emulator-5554", false)
+	if err != nil || !second.Cached || dumps != 1 {
+		t.Fatalf("second call within the TTL: cached %v, %d dumps, err %v", second != nil && second.Cached, dumps, err)
+	}
+
+	// Navigation and invalidation both force a fresh dump
+	focus = "mCurrentFocus=Window{4d5e6f u0 com.example.shop/.CheckoutActivity}"
+	if r, _ := a.GetUIHierarchy("emulator-5554", false); r.Cached || dumps != 2 {
+		t.Errorf("new focus: cached %v after %d dumps", r.Cached, dumps)
+	}
+	a.InvalidateUIDump("emulator-5554")
+	if r, _ := a.GetUIHierarchy("emulator-5554", false); r.Cached || dumps != 3 {
+		t.Errorf("invalidated: cached %v after %d dumps", r.Cached, dumps)
+	}
+}
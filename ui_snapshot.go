@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var unsafeSnapshotChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+func (a *App) getUISnapshotsPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	path := filepath.Join(configDir, "Gaze", "ui_snapshots")
+	_ = os.MkdirAll(path, 0755)
+	return path
+}
+
+// uiSnapshotFile maps a snapshot name to its file name without extension. Names that lose
+// characters to sanitizing get a hash suffix so they can't collide.
+func uiSnapshotFile(name string) string {
+	safe := unsafeSnapshotChars.ReplaceAllString(name, "_")
+	if safe == name {
+		return safe
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%s_%08x", safe, h.Sum32())
+}
+
+// SaveUISnapshot stores the current UI hierarchy and a screenshot under name, replacing any
+// snapshot of the same name
+func (a *App) SaveUISnapshot(deviceId, name string) (*UISnapshotSummary, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("snapshot name is required")
+	}
+
+	hierarchy, err := a.GetUIHierarchy(deviceId, true)
+	if err != nil {
+		return nil, err
+	}
+	model, _ := a.RunAdbCommand(deviceId, "shell getprop ro.product.model")
+	snapshot := UISnapshot{
+		Name:        name,
+		DeviceID:    deviceId,
+		DeviceModel: strings.TrimSpace(model),
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Root:        hierarchy.Root,
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Join(a.getUISnapshotsPath(), uiSnapshotFile(name))
+	if err := writeFileAtomic(base+".json", data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	_ = os.Remove(base + ".png")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	png, err := a.captureScreenPNG(ctx, deviceId)
+	if err == nil {
+		err = writeFileAtomic(base+".png", png, 0644)
+	}
+	if err != nil {
+		a.Log("UI snapshot %q saved without a screenshot: %v", name, err)
+	}
+
+	a.Log("Saved UI snapshot %q", name)
+	return &UISnapshotSummary{
+		Name:          name,
+		DeviceModel:   snapshot.DeviceModel,
+		CreatedAt:     snapshot.CreatedAt,
+		NodeCount:     countUINodes(snapshot.Root),
+		HasScreenshot: err == nil,
+	}, nil
+}
+
+// ListUISnapshots returns the saved snapshots, newest first
+func (a *App) ListUISnapshots() ([]UISnapshotSummary, error) {
+	dir := a.getUISnapshotsPath()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []UISnapshotSummary{}
+	for _, file := range files {
+		snapshot, err := readUISnapshot(file)
+		if err != nil {
+			continue
+		}
+		_, statErr := os.Stat(strings.TrimSuffix(file, ".json") + ".png")
+		summaries = append(summaries, UISnapshotSummary{
+			Name:          snapshot.Name,
+			DeviceModel:   snapshot.DeviceModel,
+			CreatedAt:     snapshot.CreatedAt,
+			NodeCount:     countUINodes(snapshot.Root),
+			HasScreenshot: statErr == nil,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CreatedAt > summaries[j].CreatedAt })
+	return summaries, nil
+}
+
+// GetUISnapshot loads a snapshot with its screenshot as a data URL
+func (a *App) GetUISnapshot(name string) (*UISnapshot, error) {
+	base := filepath.Join(a.getUISnapshotsPath(), uiSnapshotFile(name))
+	snapshot, err := readUISnapshot(base + ".json")
+	if err != nil {
+		return nil, err
+	}
+	if png, err := os.ReadFile(base + ".png"); err == nil {
+		snapshot.Screenshot = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	}
+	return snapshot, nil
+}
+
+// DeleteUISnapshot removes a snapshot and its screenshot
+func (a *App) DeleteUISnapshot(name string) error {
+	base := filepath.Join(a.getUISnapshotsPath(), uiSnapshotFile(name))
+	if err := os.Remove(base + ".json"); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot %q not found", name)
+		}
+		return err
+	}
+	_ = os.Remove(base + ".png")
+	return nil
+}
+
+func readUISnapshot(path string) (*UISnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q not found", strings.TrimSuffix(filepath.Base(path), ".json"))
+		}
+		return nil, err
+	}
+	var snapshot UISnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("corrupt snapshot %s: %w", filepath.Base(path), err)
+	}
+	if snapshot.Root == nil {
+		return nil, fmt.Errorf("snapshot %q has no hierarchy", snapshot.Name)
+	}
+	return &snapshot, nil
+}
+
+func countUINodes(node *UINode) int {
+	if node == nil {
+		return 0
+	}
+	n := 1
+	for i := range node.Nodes {
+		n += countUINodes(&node.Nodes[i])
+	}
+	return n
+}
+
+// DiffUISnapshots compares snapshot nameA (before) with nameB (after). Children are paired by
+// resource-id, then text, then content description, and only then by position, so elements
+// inserted above others don't show up as a cascade of changes.
+func (a *App) DiffUISnapshots(nameA, nameB string) (*UISnapshotDiff, error) {
+	dir := a.getUISnapshotsPath()
+	snapA, err := readUISnapshot(filepath.Join(dir, uiSnapshotFile(nameA)+".json"))
+	if err != nil {
+		return nil, err
+	}
+	snapB, err := readUISnapshot(filepath.Join(dir, uiSnapshotFile(nameB)+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	d := &uiDiffer{a: a, rootA: snapA.Root, rootB: snapB.Root, diff: &UISnapshotDiff{A: nameA, B: nameB, Rows: []UIDiffRow{}}}
+	d.pair(snapA.Root, snapB.Root, 0)
+	return d.diff, nil
+}
+
+type uiDiffer struct {
+	a            *App
+	rootA, rootB *UINode
+	diff         *UISnapshotDiff
+}
+
+// pair emits the row for two matched nodes and recurses into their children
+func (d *uiDiffer) pair(before, after *UINode, depth int) {
+	row := UIDiffRow{Depth: depth, Status: "same", Label: uiNodeLabel(after), Before: shallowUINode(before), After: shallowUINode(after)}
+	for _, field := range []struct {
+		name string
+		a, b string
+	}{
+		{"text", before.Text, after.Text},
+		{"contentDesc", before.ContentDesc, after.ContentDesc},
+		{"resourceId", before.ResourceID, after.ResourceID},
+		{"bounds", before.Bounds, after.Bounds},
+		{"enabled", before.Enabled, after.Enabled},
+		{"clickable", before.Clickable, after.Clickable},
+		{"checked", before.Checked, after.Checked},
+	} {
+		if field.a != field.b {
+			row.Changes = append(row.Changes, UIDiffChange{Field: field.name, Before: field.a, After: field.b})
+		}
+	}
+	if len(row.Changes) > 0 {
+		row.Status = "changed"
+		row.Selector = d.a.GetBestSelector(before, d.rootA)
+		d.diff.Changed++
+	}
+	d.diff.Rows = append(d.diff.Rows, row)
+
+	matchA, matchB := matchUIChildren(before.Nodes, after.Nodes)
+	// Walk the new children in order, slotting each removed child in before the first new
+	// child whose match comes after it
+	nextA := 0
+	flushRemoved := func(upTo int) {
+		for ; nextA < upTo; nextA++ {
+			if matchA[nextA] < 0 {
+				d.single(&before.Nodes[nextA], depth+1, "removed")
+			}
+		}
+	}
+	for j := range after.Nodes {
+		i := matchB[j]
+		if i < 0 {
+			d.single(&after.Nodes[j], depth+1, "added")
+			continue
+		}
+		flushRemoved(i)
+		d.pair(&before.Nodes[i], &after.Nodes[j], depth+1)
+	}
+	flushRemoved(len(before.Nodes))
+}
+
+// single emits an added or removed node and its whole subtree
+func (d *uiDiffer) single(node *UINode, depth int, status string) {
+	row := UIDiffRow{Depth: depth, Status: status, Label: uiNodeLabel(node)}
+	if status == "added" {
+		row.After = shallowUINode(node)
+		row.Selector = d.a.GetBestSelector(node, d.rootB)
+		d.diff.Added++
+	} else {
+		row.Before = shallowUINode(node)
+		row.Selector = d.a.GetBestSelector(node, d.rootA)
+		d.diff.Removed++
+	}
+	d.diff.Rows = append(d.diff.Rows, row)
+	for i := range node.Nodes {
+		d.single(&node.Nodes[i], depth+1, status)
+	}
+}
+
+// matchUIChildren pairs two sibling lists, returning for each side the index of its match on
+// the other side or -1
+func matchUIChildren(before, after []UINode) ([]int, []int) {
+	matchA := make([]int, len(before))
+	matchB := make([]int, len(after))
+	for i := range matchA {
+		matchA[i] = -1
+	}
+	for j := range matchB {
+		matchB[j] = -1
+	}
+
+	keys := []func(n *UINode) string{
+		func(n *UINode) string { return n.ResourceID },
+		func(n *UINode) string { return n.Text },
+		func(n *UINode) string { return n.ContentDesc },
+	}
+	for _, key := range keys {
+		for i := range before {
+			k := key(&before[i])
+			if matchA[i] >= 0 || k == "" {
+				continue
+			}
+			for j := range after {
+				if matchB[j] < 0 && after[j].Class == before[i].Class && key(&after[j]) == k {
+					matchA[i], matchB[j] = j, i
+					break
+				}
+			}
+		}
+	}
+
+	// Positional fallback: leftovers of the same class, in order, without crossing a match
+	lastB := -1
+	for i := range before {
+		if matchA[i] >= 0 {
+			lastB = max(lastB, matchA[i])
+			continue
+		}
+		for j := lastB + 1; j < len(after); j++ {
+			if matchB[j] >= 0 {
+				continue
+			}
+			if after[j].Class == before[i].Class {
+				matchA[i], matchB[j] = j, i
+				lastB = j
+			}
+			break
+		}
+	}
+	return matchA, matchB
+}
+
+func shallowUINode(node *UINode) *UINode {
+	c := *node
+	c.Nodes = nil
+	return &c
+}
+
+// uiNodeLabel is a short tree label such as `TextView "Work profile"`
+func uiNodeLabel(node *UINode) string {
+	class := node.Class
+	if i := strings.LastIndex(class, "."); i >= 0 {
+		class = class[i+1:]
+	}
+	switch {
+	case node.Text != "":
+		return fmt.Sprintf("%s %q", class, node.Text)
+	case node.ResourceID != "":
+		return fmt.Sprintf("%s #%s", class, shortResourceID(node.ResourceID))
+	case node.ContentDesc != "":
+		return fmt.Sprintf("%s [%s]", class, node.ContentDesc)
+	}
+	return class
+}
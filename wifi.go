@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrProxyNotApplied is returned when the device reads back a different http_proxy than was written
+var ErrProxyNotApplied = errors.New("proxy setting did not stick")
+
+// GetGlobalProxy reads the device-wide HTTP proxy (settings global http_proxy)
+func (a *App) GetGlobalProxy(deviceId string) (*GlobalProxy, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings get global http_proxy").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	return parseGlobalProxy(strings.TrimSpace(string(out))), nil
+}
+
+// parseGlobalProxy interprets an http_proxy value. "null", "" and ":0" all mean no proxy.
+func parseGlobalProxy(raw string) *GlobalProxy {
+	p := &GlobalProxy{Raw: raw}
+	if raw == "" || raw == "null" || raw == ":0" {
+		return p
+	}
+	host, portStr, err := net.SplitHostPort(raw)
+	if err != nil {
+		p.Host = raw
+		p.Enabled = true
+		return p
+	}
+	p.Host = host
+	p.Port, _ = strconv.Atoi(portStr)
+	p.Enabled = host != "" && p.Port > 0
+	return p
+}
+
+// SetGlobalProxy points the device's HTTP traffic at host:port (Charles, mitmproxy, or Gaze's
+// own proxy) and reads the value back to make sure the device accepted it
+func (a *App) SetGlobalProxy(deviceId, host string, port int) (*GlobalProxy, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	host = strings.TrimSpace(host)
+	if host == "" || strings.ContainsAny(host, " \t:/") {
+		return nil, fmt.Errorf("invalid proxy host %q", host)
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid proxy port %d", port)
+	}
+	value := fmt.Sprintf("%s:%d", host, port)
+	if out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings put global http_proxy "+value).CombinedOutput(); err != nil || strings.Contains(string(out), "Exception") {
+		return nil, fmt.Errorf("failed to set proxy: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+
+	p, err := a.GetGlobalProxy(deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if p.Raw != value {
+		return p, fmt.Errorf("%w: device reports %q", ErrProxyNotApplied, p.Raw)
+	}
+	if sdk := a.getSDKInt(deviceId); sdk > 0 && sdk < 21 {
+		p.Warning = fmt.Sprintf("API %d: the global proxy is only honoured by some apps before Android 5.0; set it on the Wi-Fi network instead", sdk)
+	}
+	a.Log("Set global proxy on %s to %s", deviceId, value)
+	return p, nil
+}
+
+// ClearGlobalProxy removes the device-wide proxy. Many builds keep using a deleted setting's
+// cached value, so ":0" is written instead.
+func (a *App) ClearGlobalProxy(deviceId string) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "settings put global http_proxy :0").CombinedOutput()
+	if err != nil || strings.Contains(string(out), "Exception") {
+		return fmt.Errorf("failed to clear proxy: %v, %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SetWifiEnabled turns Wi-Fi on or off. A device connected over Wi-Fi will drop off adb when
+// it's disabled.
+func (a *App) SetWifiEnabled(deviceId string, on bool) error {
+	if deviceId == "" {
+		return fmt.Errorf("no device specified")
+	}
+	state := "disable"
+	if on {
+		state = "enable"
+	}
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", "svc wifi "+state).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to %s Wi-Fi: %w, %s", state, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+var (
+	wifiSSIDRegex      = regexp.MustCompile(`SSID: ("[^"]*"|[^,]*)`)
+	wifiBSSIDRegex     = regexp.MustCompile(`BSSID: ([0-9a-fA-F:]{17})`)
+	wifiRSSIRegex      = regexp.MustCompile(`RSSI: (-?\d+)`)
+	wifiLinkSpeedRegex = regexp.MustCompile(`(?:^|, )Link speed: (\d+)`)
+	wifiFrequencyRegex = regexp.MustCompile(`Frequency: (\d+)`)
+	wifiStateRegex     = regexp.MustCompile(`Supplicant state: (\w+)`)
+	wifiIPRegex        = regexp.MustCompile(`IP: /?(\d+\.\d+\.\d+\.\d+)`)
+	inetAddrRegex      = regexp.MustCompile(`inet (\d+\.\d+\.\d+\.\d+)`)
+)
+
+// GetWifiInfo reports whether Wi-Fi is on and, if connected, the network and link quality
+func (a *App) GetWifiInfo(deviceId string) (*WifiInfo, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	// The first mWifiInfo line is the current connection; later ones are history
+	script := "dumpsys wifi | grep -E '^Wi-Fi is|mWifiInfo' | head -n 3; echo ---GAZE---; ip -f inet addr show wlan0"
+	out, err := a.newAdbCommand(nil, "-s", deviceId, "shell", script).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Wi-Fi state: %w, %s", err, strings.TrimSpace(string(out)))
+	}
+	dump, ipOut, _ := strings.Cut(string(out), "---GAZE---")
+	info := parseWifiInfo(dump)
+	if info.IP == "" {
+		if m := inetAddrRegex.FindStringSubmatch(ipOut); m != nil {
+			info.IP = m[1]
+		}
+	}
+	return info, nil
+}
+
+// parseWifiInfo reads the "Wi-Fi is ..." and first mWifiInfo lines of dumpsys wifi
+func parseWifiInfo(dump string) *WifiInfo {
+	info := &WifiInfo{}
+	var wifiInfo string
+	for _, line := range strings.Split(dump, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Wi-Fi is "):
+			info.Enabled = strings.HasPrefix(line, "Wi-Fi is enabled")
+		case wifiInfo == "" && strings.Contains(line, "mWifiInfo"):
+			wifiInfo = line[strings.Index(line, "mWifiInfo")+len("mWifiInfo"):]
+		}
+	}
+	if wifiInfo == "" {
+		return info
+	}
+
+	if m := wifiStateRegex.FindStringSubmatch(wifiInfo); m != nil {
+		info.State = m[1]
+		info.Connected = m[1] == "COMPLETED"
+	}
+	if m := wifiSSIDRegex.FindStringSubmatch(wifiInfo); m != nil {
+		ssid := strings.Trim(strings.TrimSpace(m[1]), `"`)
+		if ssid != "<unknown ssid>" && ssid != "<none>" {
+			info.SSID = ssid
+		}
+	}
+	if m := wifiBSSIDRegex.FindStringSubmatch(wifiInfo); m != nil && m[1] != "02:00:00:00:00:00" {
+		info.BSSID = m[1]
+	}
+	if m := wifiRSSIRegex.FindStringSubmatch(wifiInfo); m != nil {
+		info.RSSI, _ = strconv.Atoi(m[1])
+	}
+	if m := wifiLinkSpeedRegex.FindStringSubmatch(wifiInfo); m != nil {
+		info.LinkSpeedMbps, _ = strconv.Atoi(m[1])
+	}
+	if m := wifiFrequencyRegex.FindStringSubmatch(wifiInfo); m != nil {
+		info.FrequencyMHz, _ = strconv.Atoi(m[1])
+	}
+	if m := wifiIPRegex.FindStringSubmatch(wifiInfo); m != nil && m[1] != "0.0.0.0" {
+		info.IP = m[1]
+	}
+	return info
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,29 @@ import (
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+const (
+	// workflowSchemaVersion 2 added nested if/repeat steps; older files are flat graphs that
+	// load unchanged
+	workflowSchemaVersion = 2
+
+	// workflowRetryDelay is the pause before a failed step with retries runs again
+	workflowRetryDelay = 500 * time.Millisecond
+
+	defaultMaxRepeatIterations = 100
+)
+
+// decodeWorkflow parses a saved workflow, refusing files written by a newer schema
+func decodeWorkflow(data []byte) (Workflow, error) {
+	var workflow Workflow
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return workflow, err
+	}
+	if workflow.Version > workflowSchemaVersion {
+		return workflow, fmt.Errorf("workflow %q uses schema version %d, newer than supported %d", workflow.Name, workflow.Version, workflowSchemaVersion)
+	}
+	return workflow, nil
+}
+
 // getWorkflowsPath returns the path to the workflows directory
 func (a *App) getWorkflowsPath() string {
 	configDir, err := os.UserConfigDir()
@@ -37,6 +61,7 @@ func (a *App) SaveWorkflow(workflow Workflow) error {
 
 	filePath := filepath.Join(workflowsPath, safeName+".json")
 
+	workflow.Version = workflowSchemaVersion
 	data, err := json.MarshalIndent(workflow, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal workflow: %w", err)
@@ -73,8 +98,9 @@ func (a *App) LoadWorkflows() ([]Workflow, error) {
 			continue
 		}
 
-		var workflow Workflow
-		if err := json.Unmarshal(data, &workflow); err != nil {
+		workflow, err := decodeWorkflow(data)
+		if err != nil {
+			a.Log("Skipping workflow %s: %v", entry.Name(), err)
 			continue
 		}
 
@@ -123,7 +149,7 @@ func (a *App) ExecuteSingleWorkflowStep(deviceId string, step WorkflowStep) erro
 	}
 
 	// Execute the step
-	_, err := a.runWorkflowStep(ctx, deviceId, step, 1, 1, 0, vars)
+	_, _, err := a.runWorkflowStepWithRetries(ctx, deviceId, step, stepScope{path: "1", index: 1}, 0, vars)
 	if err != nil {
 		return err
 	}
@@ -136,9 +162,19 @@ func (a *App) ExecuteSingleWorkflowStep(deviceId string, step WorkflowStep) erro
 	return nil
 }
 
-// RunWorkflow executes a workflow on the specified device
-func (a *App) RunWorkflow(device Device, workflow Workflow) error {
+// RunWorkflow executes a workflow on the specified device. variables are bound on top of the
+// workflow's own defaults.
+func (a *App) RunWorkflow(device Device, workflow Workflow, variables map[string]string) error {
+	return a.startWorkflow(device, workflow, variables, "")
+}
+
+// startWorkflow runs a workflow in the background; trigger names the trigger that started it,
+// if any, for the run report
+func (a *App) startWorkflow(device Device, workflow Workflow, variables map[string]string, trigger string) error {
 	deviceId := device.ID
+	if err := validateWorkflowVariables(workflow, variables); err != nil {
+		return err
+	}
 
 	// Check if already running
 	touchPlaybackMu.Lock()
@@ -157,8 +193,11 @@ func (a *App) RunWorkflow(device Device, workflow Workflow) error {
 			delete(touchPlaybackCancel, deviceId)
 			touchPlaybackMu.Unlock()
 		}()
+		if workflow.DisableAnimations {
+			defer a.withAnimationsDisabled(deviceId)()
+		}
 
-		wailsRuntime.EventsEmit(a.ctx, "workflow-started", map[string]interface{}{
+		a.emitWorkflowEvent("workflow-started", map[string]interface{}{
 			"deviceId":     deviceId,
 			"workflowName": workflow.Name,
 			"workflowId":   workflow.ID,
@@ -167,33 +206,41 @@ func (a *App) RunWorkflow(device Device, workflow Workflow) error {
 
 		// Initialize variable context
 		vars := make(map[string]string)
-		if workflow.Variables != nil {
-			for k, v := range workflow.Variables {
-				vars[k] = v
-			}
+		for k, v := range workflow.Variables {
+			vars[k] = v
+		}
+		for k, v := range variables {
+			vars[k] = v
 		}
 
-		err := a.runWorkflowInternal(ctx, deviceId, workflow, 0, vars)
+		report := a.newWorkflowRunLog(deviceId, workflow, vars)
+		if report != nil {
+			report.report.Trigger = trigger
+		}
+		err := a.runWorkflowInternal(ctx, deviceId, workflow, stepScope{report: report}, 0, vars)
+		a.finishWorkflowRun(report, err)
 		if err != nil && err != context.Canceled {
-			wailsRuntime.EventsEmit(a.ctx, "workflow-error", map[string]interface{}{
+			a.emitWorkflowEvent("workflow-error", map[string]interface{}{
 				"deviceId":   deviceId,
 				"workflowId": workflow.ID,
 				"error":      err.Error(),
 			})
 		}
 
-		wailsRuntime.EventsEmit(a.ctx, "workflow-completed", map[string]interface{}{
+		a.emitWorkflowEvent("workflow-completed", map[string]interface{}{
 			"deviceId":     deviceId,
 			"workflowName": workflow.Name,
 			"workflowId":   workflow.ID,
+			"runId":        report.runID(),
 		})
 	}()
 
 	return nil
 }
 
-// runWorkflowInternal contains the core graph traversal logic
-func (a *App) runWorkflowInternal(ctx context.Context, deviceId string, workflow Workflow, depth int, vars map[string]string) error {
+// runWorkflowInternal contains the core graph traversal logic. Steps are numbered under
+// parent, whose path is empty at the top level and the run_workflow step for a sub-workflow.
+func (a *App) runWorkflowInternal(ctx context.Context, deviceId string, workflow Workflow, parent stepScope, depth int, vars map[string]string) error {
 	// Build ID->Step map for graph navigation
 	stepMap := make(map[string]*WorkflowStep)
 	var startStep *WorkflowStep
@@ -217,7 +264,7 @@ func (a *App) runWorkflowInternal(ctx context.Context, deviceId string, workflow
 	}
 
 	// Emit event for Start node to show it's running
-	wailsRuntime.EventsEmit(a.ctx, "workflow-step-running", map[string]interface{}{
+	a.emitWorkflowEvent("workflow-step-running", map[string]interface{}{
 		"deviceId": deviceId,
 		"stepId":   startStep.ID,
 		"stepName": startStep.Name,
@@ -246,65 +293,13 @@ func (a *App) runWorkflowInternal(ctx context.Context, deviceId string, workflow
 
 		executedCount++
 
-		wailsRuntime.EventsEmit(a.ctx, "workflow-step-running", map[string]interface{}{
-			"deviceId":   deviceId,
-			"workflowId": workflow.ID,
-			"stepIndex":  executedCount,
-			"stepId":     step.ID,
-			"stepName":   step.Name,
-			"stepType":   step.Type,
-		})
-
-		loopCount := step.Loop
-		if loopCount < 1 {
-			loopCount = 1
+		scope := stepScope{workflowID: workflow.ID, path: strconv.Itoa(executedCount), index: executedCount, report: parent.report}
+		if parent.path != "" {
+			scope.path = parent.path + "." + scope.path
 		}
-
-		var stepResult bool = true
-
-		for l := 0; l < loopCount; l++ {
-			// Pre-Wait
-			if step.PreWait > 0 {
-				wailsRuntime.EventsEmit(a.ctx, "workflow-step-waiting", map[string]interface{}{
-					"deviceId":   deviceId,
-					"workflowId": workflow.ID,
-					"stepId":     step.ID,
-					"duration":   step.PreWait,
-					"phase":      "pre",
-				})
-				time.Sleep(time.Duration(step.PreWait) * time.Millisecond)
-			}
-
-			var err error
-			stepResult, err = a.runWorkflowStep(ctx, deviceId, *step, l+1, loopCount, depth, vars)
-			if err != nil {
-				if err == context.Canceled {
-					return context.Canceled
-				}
-				if step.OnError == "continue" {
-					goto check_cancel
-				}
-				return err
-			}
-
-			// Post Delay (Wait After)
-			if step.PostDelay > 0 {
-				wailsRuntime.EventsEmit(a.ctx, "workflow-step-waiting", map[string]interface{}{
-					"deviceId":   deviceId,
-					"workflowId": workflow.ID,
-					"stepId":     step.ID,
-					"duration":   step.PostDelay,
-					"phase":      "post",
-				})
-				time.Sleep(time.Duration(step.PostDelay) * time.Millisecond)
-			}
-
-		check_cancel:
-			select {
-			case <-ctx.Done():
-				return context.Canceled
-			default:
-			}
+		stepResult, err := a.executeWorkflowStep(ctx, deviceId, step, scope, depth, vars)
+		if err != nil {
+			return err
 		}
 
 		// Determine Next Step
@@ -329,20 +324,330 @@ func (a *App) runWorkflowInternal(ctx context.Context, deviceId string, workflow
 	return nil
 }
 
-// processWorkflowVariables replaces placeholders like {{var}} with actual values
+// stepScope places a step for event reporting: its workflow and its position. Paths are
+// "3" for the third step run at the top level, "3.1.2" for the second step of its then
+// branch ("3.2.x" is the else branch) and "3.4.1" for the first body step in the fourth
+// iteration of a repeat.
+type stepScope struct {
+	workflowID string
+	path       string
+	index      int
+	report     *workflowRunLog // nil when the run isn't reported
+}
+
+func (sc stepScope) child(i int) stepScope {
+	return stepScope{workflowID: sc.workflowID, path: sc.path + "." + strconv.Itoa(i), index: sc.index, report: sc.report}
+}
+
+// executeWorkflowStep runs one step with its loops, waits and retries, reporting it through
+// the workflow-step-* events. The error is non-nil only when the workflow should stop.
+func (a *App) executeWorkflowStep(ctx context.Context, deviceId string, step *WorkflowStep, scope stepScope, depth int, vars map[string]string) (bool, error) {
+	stepEvent := func() map[string]interface{} {
+		return map[string]interface{}{
+			"deviceId":   deviceId,
+			"workflowId": scope.workflowID,
+			"stepIndex":  scope.index,
+			"stepPath":   scope.path,
+			"stepId":     step.ID,
+			"stepName":   step.Name,
+			"stepType":   step.Type,
+		}
+	}
+	a.emitWorkflowEvent("workflow-step-running", stepEvent())
+	a.emitWorkflowEvent("workflow-step-started", stepEvent())
+
+	// Repeat steps count their own iterations
+	loopCount := step.Loop
+	if loopCount < 1 || step.Type == "repeat" {
+		loopCount = 1
+	}
+
+	var stepResult bool = true
+	var stepErr error
+	attempts := 0
+	stepStart := time.Now()
+
+	for l := 0; l < loopCount && stepErr == nil; l++ {
+		// Pre-Wait
+		if step.PreWait > 0 {
+			a.emitWorkflowEvent("workflow-step-waiting", map[string]interface{}{
+				"deviceId":   deviceId,
+				"workflowId": scope.workflowID,
+				"stepId":     step.ID,
+				"duration":   step.PreWait,
+				"phase":      "pre",
+			})
+			time.Sleep(time.Duration(step.PreWait) * time.Millisecond)
+		}
+
+		var tries int
+		stepResult, tries, stepErr = a.runWorkflowStepWithRetries(ctx, deviceId, *step, scope, depth, vars)
+		attempts += tries
+		if stepErr != nil {
+			break
+		}
+
+		// Post Delay (Wait After)
+		if step.PostDelay > 0 {
+			a.emitWorkflowEvent("workflow-step-waiting", map[string]interface{}{
+				"deviceId":   deviceId,
+				"workflowId": scope.workflowID,
+				"stepId":     step.ID,
+				"duration":   step.PostDelay,
+				"phase":      "post",
+			})
+			time.Sleep(time.Duration(step.PostDelay) * time.Millisecond)
+		}
+
+		select {
+		case <-ctx.Done():
+			stepErr = context.Canceled
+		default:
+		}
+	}
+
+	status := "success"
+	finished := stepEvent()
+	finished["attempts"] = attempts
+	finished["durationMs"] = time.Since(stepStart).Milliseconds()
+	finished["result"] = stepResult
+	if errors.Is(stepErr, context.Canceled) {
+		status = "cancelled"
+	} else if stepErr != nil {
+		status = "failed"
+		finished["error"] = stepErr.Error()
+		finished["continued"] = step.OnError == "continue"
+	}
+	finished["status"] = status
+	a.emitWorkflowEvent("workflow-step-finished", finished)
+	a.recordWorkflowStep(scope, deviceId, step, status, attempts, time.Since(stepStart), stepErr)
+
+	if status == "cancelled" {
+		return false, context.Canceled
+	}
+	if stepErr != nil && step.OnError != "continue" {
+		return false, stepErr
+	}
+	return stepResult, nil
+}
+
+// emitWorkflowEvent reports workflow progress to the frontend
+func (a *App) emitWorkflowEvent(event string, data map[string]interface{}) {
+	if a.workflowEmit != nil {
+		a.workflowEmit(event, data)
+		return
+	}
+	wailsRuntime.EventsEmit(a.ctx, event, data)
+}
+
+// workflowHierarchy is the screen workflow conditions are checked against
+func (a *App) workflowHierarchy(deviceId string) (*UIHierarchyResult, error) {
+	if a.workflowUIDump != nil {
+		return a.workflowUIDump(deviceId)
+	}
+	return a.GetUIHierarchy(deviceId, false)
+}
+
+// runStepList runs the steps of an if or repeat body in order
+func (a *App) runStepList(ctx context.Context, deviceId string, steps []WorkflowStep, scope stepScope, depth int, vars map[string]string) error {
+	for i := range steps {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		default:
+		}
+		a.checkPause(deviceId)
+
+		if _, err := a.executeWorkflowStep(ctx, deviceId, &steps[i], scope.child(i+1), depth, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runRepeatStep runs the body Times times, or while the step's condition holds when it has
+// one, never more than MaxIterations
+func (a *App) runRepeatStep(ctx context.Context, deviceId string, step WorkflowStep, scope stepScope, depth int, vars map[string]string) error {
+	maxIterations := step.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultMaxRepeatIterations
+	}
+	iterations := step.Times
+	if step.ConditionType != "" || iterations <= 0 || iterations > maxIterations {
+		iterations = maxIterations
+	}
+
+	for i := 1; i <= iterations; i++ {
+		if step.ConditionType != "" && !a.evaluateWorkflowCondition(ctx, deviceId, step, vars) {
+			return nil
+		}
+		vars["_iteration"] = strconv.Itoa(i)
+		if err := a.runStepList(ctx, deviceId, step.Body, scope.child(i), depth+1, vars); err != nil {
+			return err
+		}
+	}
+	if step.ConditionType != "" && a.evaluateWorkflowCondition(ctx, deviceId, step, vars) {
+		return fmt.Errorf("repeat stopped after %d iterations with its condition still true", iterations)
+	}
+	return nil
+}
+
+// evaluateWorkflowCondition checks the condition of a branch, if or repeat step
+func (a *App) evaluateWorkflowCondition(ctx context.Context, deviceId string, step WorkflowStep, vars map[string]string) bool {
+	conditionType := step.ConditionType
+	if conditionType == "" {
+		conditionType = "exists"
+	}
+	value := a.processWorkflowVariables(step.Value, vars)
+	selectorValue := ""
+	if step.Selector != nil {
+		selectorValue = a.processWorkflowVariables(step.Selector.Value, vars)
+	}
+
+	// These don't need the screen
+	switch conditionType {
+	case "shell_success":
+		code, err := a.shellExitCode(ctx, deviceId, expandWorkflowVariables(step.Value, vars, shellQuote))
+		return err == nil && code == 0
+	case "variable_equals":
+		varValue, exists := vars[strings.TrimSpace(selectorValue)]
+		return exists && varValue == value
+	case "activity_is":
+		timeout := 2000
+		if step.Timeout > 0 {
+			timeout = step.Timeout
+		}
+		_, ok := a.waitForActivity(ctx, deviceId, value, time.Duration(timeout)*time.Millisecond)
+		return ok
+	}
+
+	timeout := 2000 // Default 2s check
+	if step.Timeout > 0 {
+		timeout = step.Timeout
+	}
+	return a.evaluateBranchCondition(deviceId, step, conditionType, selectorValue, value, timeout, vars)
+}
+
+// shellExitCode runs command in the device shell and returns its exit status
+func (a *App) shellExitCode(ctx context.Context, deviceId, command string) (int, error) {
+	if strings.TrimSpace(command) == "" {
+		return 0, fmt.Errorf("no command specified")
+	}
+	cmd := a.newAdbCommand(ctx, "-s", deviceId, "shell", command+"; echo __EXIT:$?")
+	output, err := cmd.CombinedOutput()
+	if idx := strings.LastIndex(string(output), "__EXIT:"); idx >= 0 {
+		return strconv.Atoi(strings.TrimSpace(string(output)[idx+len("__EXIT:"):]))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("shell command failed: %w", err)
+	}
+	return 0, fmt.Errorf("no exit status in shell output")
+}
+
+// runWorkflowStepWithRetries runs a step, trying again up to step.Retries times after a
+// failure. It returns the step result, the number of attempts made and the last error.
+func (a *App) runWorkflowStepWithRetries(ctx context.Context, deviceId string, step WorkflowStep, scope stepScope, depth int, vars map[string]string) (bool, int, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := a.runWorkflowStep(ctx, deviceId, step, scope, depth, vars)
+		if err == nil || attempt > step.Retries || ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return result, attempt, err
+		}
+		a.Log("Workflow step %q failed (attempt %d/%d): %v", step.Name, attempt, step.Retries+1, err)
+		select {
+		case <-ctx.Done():
+			return false, attempt, context.Canceled
+		case <-time.After(workflowRetryDelay):
+		}
+	}
+}
+
+// workflowVarRef matches variable references, ${name} or the older {{name}}
+var (
+	workflowVarRef         = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\{\{([^{}]+)\}\}`)
+	workflowVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// processWorkflowVariables replaces placeholders like ${var} or {{var}} with actual values
 func (a *App) processWorkflowVariables(text string, vars map[string]string) string {
+	return expandWorkflowVariables(text, vars, nil)
+}
+
+// expandWorkflowVariables substitutes the references in text, passing each value through
+// quote when it is set. Unknown references are left as written.
+func expandWorkflowVariables(text string, vars map[string]string, quote func(string) string) string {
 	if text == "" {
 		return ""
 	}
-	for k, v := range vars {
-		placeholder := "{{" + k + "}}"
-		text = strings.ReplaceAll(text, placeholder, v)
+	return workflowVarRef.ReplaceAllStringFunc(text, func(ref string) string {
+		value, ok := vars[workflowVarName(ref)]
+		if !ok {
+			return ref
+		}
+		if quote != nil {
+			value = quote(value)
+		}
+		return value
+	})
+}
+
+func workflowVarName(ref string) string {
+	if strings.HasPrefix(ref, "${") {
+		return ref[2 : len(ref)-1]
+	}
+	return ref[2 : len(ref)-2]
+}
+
+// validateWorkflowVariables fails when a step references a variable that is neither passed
+// in, declared by the workflow, nor assigned by one of its set_variable steps
+func validateWorkflowVariables(workflow Workflow, vars map[string]string) error {
+	defined := map[string]bool{"_iteration": true}
+	for name := range vars {
+		defined[name] = true
+	}
+	for name := range workflow.Variables {
+		defined[name] = true
+	}
+	var refs []string
+	var walk func(steps []WorkflowStep)
+	walk = func(steps []WorkflowStep) {
+		for _, step := range steps {
+			if step.Type == "set_variable" && step.Name != "" {
+				defined[step.Name] = true
+			}
+			texts := []string{step.Value}
+			if step.Selector != nil {
+				texts = append(texts, step.Selector.Value)
+			}
+			if step.Container != nil {
+				texts = append(texts, step.Container.Value)
+			}
+			for _, text := range texts {
+				for _, ref := range workflowVarRef.FindAllString(text, -1) {
+					refs = append(refs, workflowVarName(ref))
+				}
+			}
+			walk(step.Then)
+			walk(step.Else)
+			walk(step.Body)
+		}
+	}
+	walk(workflow.Steps)
+
+	var undefined []string
+	for _, name := range refs {
+		if !defined[name] {
+			undefined = append(undefined, name)
+			defined[name] = true // report once
+		}
+	}
+	if len(undefined) > 0 {
+		return fmt.Errorf("undefined variables: %s", strings.Join(undefined, ", "))
 	}
-	return text
+	return nil
 }
 
 // Updated signature to return (result, error)
-func (a *App) runWorkflowStep(ctx context.Context, deviceId string, step WorkflowStep, _, _, depth int, vars map[string]string) (bool, error) {
+func (a *App) runWorkflowStep(ctx context.Context, deviceId string, step WorkflowStep, scope stepScope, depth int, vars map[string]string) (bool, error) {
 	// Recursion guard
 	if depth > 10 {
 		return false, fmt.Errorf("maximum workflow nesting depth exceeded")
@@ -371,27 +676,21 @@ func (a *App) runWorkflowStep(ctx context.Context, deviceId string, step Workflo
 		time.Sleep(time.Duration(duration) * time.Millisecond)
 
 	case "adb":
-		// Raw ADB Command
-		if _, err := a.RunAdbCommand(deviceId, processedValue); err != nil {
+		// Raw ADB Command. Values bound for the device shell are quoted so they stay one word.
+		command := processedValue
+		if strings.HasPrefix(strings.TrimSpace(step.Value), "shell ") {
+			command = expandWorkflowVariables(step.Value, vars, shellQuote)
+		}
+		if _, err := a.RunAdbCommand(deviceId, command); err != nil {
 			return false, fmt.Errorf("adb command failed: %w", err)
 		}
 
 	case "run_workflow":
-		return true, a.loadAndRunSubWorkflow(ctx, deviceId, processedValue, depth, vars)
+		return true, a.loadAndRunSubWorkflow(ctx, deviceId, processedValue, scope, depth, vars)
 
 	case "branch":
 		// 1. Evaluate condition based on type
-		timeout := 2000 // Default 2s check
-		if step.Timeout > 0 {
-			timeout = step.Timeout
-		}
-
-		conditionType := step.ConditionType
-		if conditionType == "" {
-			conditionType = "exists" // Default to existence check
-		}
-
-		result := a.evaluateBranchCondition(deviceId, step, conditionType, processedSelectorValue, processedValue, timeout, vars)
+		result := a.evaluateWorkflowCondition(ctx, deviceId, step, vars)
 
 		// 2. Determine functionality based on configuration
 		isGraphMode := step.TrueStepId != "" || step.FalseStepId != ""
@@ -422,30 +721,26 @@ func (a *App) runWorkflowStep(ctx context.Context, deviceId string, step Workflo
 		}
 
 		// Run the selected workflow recursively
-		return true, a.loadAndRunSubWorkflow(ctx, deviceId, targetID, depth, vars)
+		return true, a.loadAndRunSubWorkflow(ctx, deviceId, targetID, scope, depth, vars)
+
+	case "if":
+		body, branch := step.Then, 1
+		if !a.evaluateWorkflowCondition(ctx, deviceId, step, vars) {
+			body, branch = step.Else, 2
+		}
+		return branch == 1, a.runStepList(ctx, deviceId, body, scope.child(branch), depth+1, vars)
+
+	case "repeat":
+		return true, a.runRepeatStep(ctx, deviceId, step, scope, depth, vars)
 
 	case "script":
 		// Run recorded script
-		scriptsPath := a.getScriptsPath()
-		scriptName := step.Value
-		safeName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(scriptName, "_")
-		filePath := filepath.Join(scriptsPath, safeName+".json")
-
-		data, err := os.ReadFile(filePath)
+		script, err := a.loadTouchScript(step.Value)
 		if err != nil {
-			filePath = filepath.Join(scriptsPath, scriptName+".json")
-			data, err = os.ReadFile(filePath)
-			if err != nil {
-				return false, fmt.Errorf("script not found: %s", scriptName)
-			}
-		}
-
-		var script TouchScript
-		if err := json.Unmarshal(data, &script); err != nil {
-			return false, fmt.Errorf("failed to parse script: %w", err)
+			return false, err
 		}
 
-		return true, a.playTouchScriptSync(ctx, deviceId, script, nil)
+		return true, a.playTouchScriptSync(ctx, deviceId, *script, nil)
 
 	case "launch_app":
 		_, err := a.StartApp(deviceId, step.Value)
@@ -463,12 +758,39 @@ func (a *App) runWorkflowStep(ctx context.Context, deviceId string, step Workflo
 		_, err := a.OpenSettings(deviceId, "android.settings.APPLICATION_DETAILS_SETTINGS", "package:"+step.Value)
 		return true, err
 
-	case "click_element", "long_click_element", "input_text", "assert_element", "wait_element", "wait_gone", "swipe_element":
+	case "wait_notification":
+		// Value is the package the notification must come from, empty for any app
+		timeout := 10000
+		if step.Timeout > 0 {
+			timeout = step.Timeout
+		}
+		_, err := a.waitForNotification(ctx, deviceId, strings.TrimSpace(processedValue), time.Duration(timeout)*time.Millisecond)
+		return err == nil, err
+
+	case "assert_activity":
+		// Value is a component, package or activity class name, e.g. ".SettingsActivity"
+		timeout := 5000
+		if step.Timeout > 0 {
+			timeout = step.Timeout
+		}
+		current, ok := a.waitForActivity(ctx, deviceId, processedValue, time.Duration(timeout)*time.Millisecond)
+		if !ok {
+			actual := "unknown"
+			if current != nil {
+				actual = current.Component
+			}
+			return false, fmt.Errorf("expected activity %s, but %s is in front", processedValue, actual)
+		}
+
+	case "click_element", "long_click_element", "input_text", "assert_element", "wait_element", "wait_gone", "swipe_element", "scroll_to":
 		// Create a copy of the step with processed values for the handler
 		processedStep := step
 		processedStep.Value = processedValue
 		if processedStep.Selector != nil {
-			processedStep.Selector.Value = processedSelectorValue
+			// Copy so the substitution doesn't stick to the step for later iterations
+			selector := *processedStep.Selector
+			selector.Value = processedSelectorValue
+			processedStep.Selector = &selector
 		}
 		return true, a.handleElementAction(ctx, deviceId, processedStep)
 
@@ -532,17 +854,55 @@ func (a *App) handleElementAction(ctx context.Context, deviceId string, step Wor
 	case "swipe_element":
 		return a.SwipeOnElement(ctx, deviceId, step.Selector, step.Value, step.SwipeDistance, step.SwipeDuration, config)
 
-	case "wait_element", "assert_element":
-		return a.WaitForElement(ctx, deviceId, step.Selector, config.Timeout)
+	case "wait_element":
+		_, err := a.waitForElement(ctx, deviceId, step.Selector, config.Timeout, config.RetryInterval)
+		return err
+
+	case "assert_element":
+		// With a value, the element must also show that text
+		if step.Value == "" {
+			_, err := a.waitForElement(ctx, deviceId, step.Selector, config.Timeout, config.RetryInterval)
+			return err
+		}
+		return a.assertElementText(ctx, deviceId, step.Selector, step.Value, config.Timeout, config.RetryInterval)
 
 	case "wait_gone":
-		return a.WaitElementGone(ctx, deviceId, step.Selector, config.Timeout)
+		return a.waitElementGone(ctx, deviceId, step.Selector, config.Timeout)
+
+	case "scroll_to":
+		_, err := a.scrollToElement(ctx, deviceId, step.Selector, step.Container, step.MaxSwipes, step.Value)
+		return err
 
 	default:
 		return fmt.Errorf("unknown element action: %s", step.Type)
 	}
 }
 
+// assertElementText waits until the element's text or description contains expected
+func (a *App) assertElementText(ctx context.Context, deviceId string, selector *ElementSelector, expected string, timeout, retryInterval int) error {
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+	lastText := ""
+	for {
+		remaining := int(time.Until(deadline).Milliseconds())
+		if remaining <= 0 {
+			return fmt.Errorf("assertion failed: expected text %q, found %q", expected, lastText)
+		}
+		node, err := a.waitForElement(ctx, deviceId, selector, remaining, retryInterval)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(node.Text, expected) || strings.Contains(node.ContentDesc, expected) {
+			return nil
+		}
+		lastText = node.Text
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(retryInterval) * time.Millisecond):
+		}
+	}
+}
+
 // findElementNode is a legacy wrapper that uses the unified selector service
 // Deprecated: Use FindElementBySelector with ElementSelector instead
 func (a *App) findElementNode(node *UINode, checkType, checkValue string) *UINode {
@@ -568,7 +928,7 @@ func (a *App) evaluateBranchCondition(deviceId string, step WorkflowStep, condit
 		}
 
 		// Get UI hierarchy
-		hierarchy, err := a.GetUIHierarchy(deviceId)
+		hierarchy, err := a.workflowHierarchy(deviceId)
 		if err != nil {
 			time.Sleep(500 * time.Millisecond)
 			continue
@@ -671,7 +1031,7 @@ func parseBoundsCenter(bounds string) (int, int, error) {
 	return x, y, nil
 }
 
-func (a *App) loadAndRunSubWorkflow(ctx context.Context, deviceId, workflowID string, depth int, vars map[string]string) error {
+func (a *App) loadAndRunSubWorkflow(ctx context.Context, deviceId, workflowID string, parent stepScope, depth int, vars map[string]string) error {
 	workflowsPath := a.getWorkflowsPath()
 
 	safeName := regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(workflowID, "_")
@@ -682,12 +1042,12 @@ func (a *App) loadAndRunSubWorkflow(ctx context.Context, deviceId, workflowID st
 		return fmt.Errorf("workflow not found: %s", workflowID)
 	}
 
-	var subWorkflow Workflow
-	if err := json.Unmarshal(data, &subWorkflow); err != nil {
+	subWorkflow, err := decodeWorkflow(data)
+	if err != nil {
 		return fmt.Errorf("failed to parse sub-workflow: %w", err)
 	}
 
-	wailsRuntime.EventsEmit(a.ctx, "workflow-started", map[string]interface{}{
+	a.emitWorkflowEvent("workflow-started", map[string]interface{}{
 		"deviceId":     deviceId,
 		"workflowName": subWorkflow.Name,
 		"workflowId":   subWorkflow.ID,
@@ -705,17 +1065,17 @@ func (a *App) loadAndRunSubWorkflow(ctx context.Context, deviceId, workflowID st
 		}
 	}
 
-	err = a.runWorkflowInternal(ctx, deviceId, subWorkflow, depth+1, subVars)
+	err = a.runWorkflowInternal(ctx, deviceId, subWorkflow, parent, depth+1, subVars)
 
 	if err != nil && err != context.Canceled {
-		wailsRuntime.EventsEmit(a.ctx, "workflow-error", map[string]interface{}{
+		a.emitWorkflowEvent("workflow-error", map[string]interface{}{
 			"deviceId":   deviceId,
 			"workflowId": subWorkflow.ID,
 			"error":      err.Error(),
 		})
 	}
 
-	wailsRuntime.EventsEmit(a.ctx, "workflow-completed", map[string]interface{}{
+	a.emitWorkflowEvent("workflow-completed", map[string]interface{}{
 		"deviceId":     deviceId,
 		"workflowName": subWorkflow.Name,
 		"workflowId":   subWorkflow.ID,
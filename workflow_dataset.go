@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// RunWorkflowWithDataset runs a saved workflow once per row of a CSV file. The header row
+// names the variables and every following row binds them for one run. A failing row doesn't
+// stop the rest; StopWorkflow does.
+func (a *App) RunWorkflowWithDataset(deviceId, workflowName, csvPath string) (*WorkflowDatasetResult, error) {
+	if deviceId == "" {
+		return nil, fmt.Errorf("no device specified")
+	}
+	workflow, err := a.findSavedWorkflow(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, err := readWorkflowDataset(csvPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every row binds the same names, so checking the header covers them all
+	bound := make(map[string]string, len(columns))
+	for _, column := range columns {
+		bound[column] = ""
+	}
+	if err := validateWorkflowVariables(workflow, bound); err != nil {
+		return nil, err
+	}
+
+	touchPlaybackMu.Lock()
+	if _, exists := touchPlaybackCancel[deviceId]; exists {
+		touchPlaybackMu.Unlock()
+		return nil, fmt.Errorf("workflow execution already in progress")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	touchPlaybackCancel[deviceId] = cancel
+	touchPlaybackMu.Unlock()
+	defer func() {
+		touchPlaybackMu.Lock()
+		delete(touchPlaybackCancel, deviceId)
+		touchPlaybackMu.Unlock()
+		cancel()
+	}()
+
+	startTime := time.Now()
+	result := &WorkflowDatasetResult{Workflow: workflow.Name, Rows: make([]WorkflowDatasetRow, 0, len(rows))}
+	for i, record := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		vars := make(map[string]string)
+		for k, v := range workflow.Variables {
+			vars[k] = v
+		}
+		row := WorkflowDatasetRow{Row: i + 1, Variables: make(map[string]string, len(columns))}
+		for c, column := range columns {
+			vars[column] = record[c]
+			row.Variables[column] = record[c]
+		}
+
+		wailsRuntime.EventsEmit(a.ctx, "workflow-started", map[string]interface{}{
+			"deviceId":     deviceId,
+			"workflowName": workflow.Name,
+			"workflowId":   workflow.ID,
+			"steps":        len(workflow.Steps),
+			"datasetRow":   row.Row,
+		})
+		rowStart := time.Now()
+		report := a.newWorkflowRunLog(deviceId, workflow, vars)
+		err := a.runWorkflowInternal(ctx, deviceId, workflow, stepScope{report: report}, 0, vars)
+		a.finishWorkflowRun(report, err)
+		row.RunID = report.runID()
+		row.ElapsedMs = time.Since(rowStart).Milliseconds()
+		if err == context.Canceled {
+			break
+		}
+		row.Passed = err == nil
+		if err != nil {
+			row.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Passed++
+		}
+		result.Rows = append(result.Rows, row)
+
+		wailsRuntime.EventsEmit(a.ctx, "workflow-completed", map[string]interface{}{
+			"deviceId":     deviceId,
+			"workflowName": workflow.Name,
+			"workflowId":   workflow.ID,
+			"datasetRow":   row.Row,
+			"runId":        row.RunID,
+		})
+		wailsRuntime.EventsEmit(a.ctx, "workflow-dataset-progress", map[string]interface{}{
+			"deviceId": deviceId,
+			"row":      row,
+			"total":    len(rows),
+		})
+	}
+	result.Cancelled = ctx.Err() != nil
+	result.ElapsedMs = time.Since(startTime).Milliseconds()
+	a.Log("Dataset run of %q: %d passed, %d failed of %d rows", workflow.Name, result.Passed, result.Failed, len(rows))
+	return result, nil
+}
+
+// SelectWorkflowDataset opens a file dialog to pick a CSV dataset
+func (a *App) SelectWorkflowDataset() (string, error) {
+	return wailsRuntime.OpenFileDialog(a.ctx, wailsRuntime.OpenDialogOptions{
+		Title: "Select Dataset",
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "CSV (*.csv)", Pattern: "*.csv"},
+		},
+	})
+}
+
+// findSavedWorkflow looks a saved workflow up by ID or, failing that, by name
+func (a *App) findSavedWorkflow(nameOrID string) (Workflow, error) {
+	workflows, err := a.LoadWorkflows()
+	if err != nil {
+		return Workflow{}, err
+	}
+	for _, wf := range workflows {
+		if wf.ID == nameOrID {
+			return wf, nil
+		}
+	}
+	for _, wf := range workflows {
+		if strings.EqualFold(wf.Name, nameOrID) {
+			return wf, nil
+		}
+	}
+	return Workflow{}, fmt.Errorf("workflow not found: %s", nameOrID)
+}
+
+// readWorkflowDataset returns the column names from the CSV header and the data rows. Blank
+// lines are skipped and every row must have a value for each column.
+func readWorkflowDataset(path string) ([]string, [][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("dataset is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read dataset header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !workflowVarNamePattern.MatchString(name) {
+			return nil, nil, fmt.Errorf("column %d: %q is not a valid variable name", i+1, name)
+		}
+		columns[i] = name
+	}
+
+	var rows [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read dataset: %w", err)
+		}
+		rows = append(rows, record)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("dataset has no rows")
+	}
+	return columns, rows, nil
+}
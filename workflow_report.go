@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultWorkflowReportLimit is how many run reports are kept unless configured otherwise
+const defaultWorkflowReportLimit = 50
+
+var workflowReportsMu sync.Mutex
+
+// workflowRunLog collects step outcomes while a workflow runs. A nil log records nothing.
+type workflowRunLog struct {
+	mu     sync.Mutex
+	dir    string
+	report WorkflowRunReport
+}
+
+func (l *workflowRunLog) runID() string {
+	if l == nil {
+		return ""
+	}
+	return l.report.ID
+}
+
+func (a *App) getWorkflowRunsPath() string {
+	path := filepath.Join(a.getWorkflowsPath(), "runs")
+	_ = os.MkdirAll(path, 0755)
+	return path
+}
+
+func (a *App) newWorkflowRunLog(deviceId string, workflow Workflow, vars map[string]string) *workflowRunLog {
+	now := time.Now()
+	// Zero-padded so directory names sort by start time
+	id := fmt.Sprintf("wfrun_%020d", now.UnixNano())
+	dir := filepath.Join(a.getWorkflowRunsPath(), id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		a.Log("Workflow report disabled: %v", err)
+		return nil
+	}
+	bound := make(map[string]string, len(vars))
+	for k, v := range vars {
+		bound[k] = v
+	}
+	return &workflowRunLog{
+		dir: dir,
+		report: WorkflowRunReport{
+			ID:           id,
+			WorkflowID:   workflow.ID,
+			WorkflowName: workflow.Name,
+			DeviceID:     deviceId,
+			Variables:    bound,
+			StartTime:    now.UnixMilli(),
+			Status:       "running",
+			Steps:        []WorkflowStepReport{},
+		},
+	}
+}
+
+// recordWorkflowStep adds a finished step to the run's report. A failed step also gets the
+// screen and UI dump as they are now, unless a step nested inside it already captured them.
+func (a *App) recordWorkflowStep(scope stepScope, deviceId string, step *WorkflowStep, status string, attempts int, duration time.Duration, stepErr error) {
+	l := scope.report
+	if l == nil {
+		return
+	}
+	entry := WorkflowStepReport{
+		Path:       scope.path,
+		StepID:     step.ID,
+		Name:       step.Name,
+		Type:       step.Type,
+		Status:     status,
+		Attempts:   attempts,
+		DurationMs: duration.Milliseconds(),
+	}
+	if step.Selector != nil {
+		entry.Selector = step.Selector.Type + ":" + step.Selector.Value
+	}
+	if stepErr != nil && status == "failed" {
+		entry.Error = stepErr.Error()
+		if !l.hasFailureUnder(scope.path) {
+			entry.Screenshot, entry.Hierarchy = a.captureFailureEvidence(l.dir, deviceId, scope.path)
+		}
+	}
+
+	l.mu.Lock()
+	l.report.Steps = append(l.report.Steps, entry)
+	l.mu.Unlock()
+}
+
+func (l *workflowRunLog) hasFailureUnder(path string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range l.report.Steps {
+		if s.Status == "failed" && strings.HasPrefix(s.Path, path+".") && s.Screenshot != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// captureFailureEvidence saves a screenshot and a fresh UI dump into dir, returning the file
+// names of whichever succeeded
+func (a *App) captureFailureEvidence(dir, deviceId, path string) (screenshot, hierarchy string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	base := "step_" + strings.ReplaceAll(path, ".", "_")
+	if png, err := a.captureScreenPNG(ctx, deviceId); err != nil {
+		a.Log("Workflow report: screenshot of step %s failed: %v", path, err)
+	} else if err := os.WriteFile(filepath.Join(dir, base+".png"), png, 0644); err == nil {
+		screenshot = base + ".png"
+	}
+	if dump, err := a.GetUIHierarchy(deviceId, true); err != nil {
+		a.Log("Workflow report: UI dump of step %s failed: %v", path, err)
+	} else if err := os.WriteFile(filepath.Join(dir, base+".xml"), []byte(dump.RawXML), 0644); err == nil {
+		hierarchy = base + ".xml"
+	}
+	return screenshot, hierarchy
+}
+
+// finishWorkflowRun fills in the outcome and totals, stores the report and prunes old ones
+func (a *App) finishWorkflowRun(l *workflowRunLog, runErr error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	report := l.report
+	report.Steps = append([]WorkflowStepReport(nil), l.report.Steps...)
+	l.mu.Unlock()
+
+	report.EndTime = time.Now().UnixMilli()
+	switch {
+	case runErr == nil:
+		report.Status = "completed"
+	case errors.Is(runErr, context.Canceled):
+		report.Status = "cancelled"
+	default:
+		report.Status = "failed"
+		report.Error = runErr.Error()
+	}
+	for _, s := range report.Steps {
+		switch s.Status {
+		case "success":
+			report.Passed++
+		case "failed":
+			report.Failed++
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(filepath.Join(l.dir, "report.json"), data, 0644)
+	}
+	if err != nil {
+		a.Log("Failed to save workflow report: %v", err)
+	}
+
+	a.workflowReportMu.Lock()
+	limit := a.workflowReportLimit
+	a.workflowReportMu.Unlock()
+
+	workflowReportsMu.Lock()
+	defer workflowReportsMu.Unlock()
+	runs := workflowRunDirs(a.getWorkflowRunsPath())
+	for len(runs) > limit {
+		_ = os.RemoveAll(filepath.Join(a.getWorkflowRunsPath(), runs[0]))
+		runs = runs[1:]
+	}
+}
+
+// workflowRunDirs lists stored run directories, oldest first
+func workflowRunDirs(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "wfrun_") {
+			runs = append(runs, entry.Name())
+		}
+	}
+	sort.Strings(runs)
+	return runs
+}
+
+func (a *App) readWorkflowRunReport(runId string) (*WorkflowRunReport, error) {
+	if runId == "" || filepath.Base(runId) != runId || !strings.HasPrefix(runId, "wfrun_") {
+		return nil, fmt.Errorf("invalid run id: %s", runId)
+	}
+	data, err := os.ReadFile(filepath.Join(a.getWorkflowRunsPath(), runId, "report.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("workflow run not found: %s", runId)
+		}
+		return nil, fmt.Errorf("failed to read workflow run: %w", err)
+	}
+	var report WorkflowRunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run: %w", err)
+	}
+	return &report, nil
+}
+
+// ListWorkflowRuns returns the stored runs of a workflow (matched by name or ID), newest
+// first; "" lists every workflow's runs. Steps are left out; use GetWorkflowRunReport.
+func (a *App) ListWorkflowRuns(workflowName string) ([]WorkflowRunReport, error) {
+	workflowReportsMu.Lock()
+	defer workflowReportsMu.Unlock()
+
+	runs := workflowRunDirs(a.getWorkflowRunsPath())
+	reports := make([]WorkflowRunReport, 0)
+	for i := len(runs) - 1; i >= 0; i-- {
+		report, err := a.readWorkflowRunReport(runs[i])
+		if err != nil {
+			continue
+		}
+		if workflowName != "" && report.WorkflowID != workflowName && !strings.EqualFold(report.WorkflowName, workflowName) {
+			continue
+		}
+		report.Steps = nil
+		reports = append(reports, *report)
+	}
+	return reports, nil
+}
+
+// GetWorkflowRunReport returns a stored run including its per-step results
+func (a *App) GetWorkflowRunReport(runId string) (*WorkflowRunReport, error) {
+	workflowReportsMu.Lock()
+	defer workflowReportsMu.Unlock()
+	return a.readWorkflowRunReport(runId)
+}
+
+// DeleteWorkflowRun removes a stored run and its captures
+func (a *App) DeleteWorkflowRun(runId string) error {
+	if _, err := a.GetWorkflowRunReport(runId); err != nil {
+		return err
+	}
+	workflowReportsMu.Lock()
+	defer workflowReportsMu.Unlock()
+	return os.RemoveAll(filepath.Join(a.getWorkflowRunsPath(), runId))
+}
+
+// GetWorkflowReportLimit returns how many run reports are kept
+func (a *App) GetWorkflowReportLimit() int {
+	a.workflowReportMu.Lock()
+	defer a.workflowReportMu.Unlock()
+	return a.workflowReportLimit
+}
+
+// SetWorkflowReportLimit changes how many run reports are kept; older ones go after the next run
+func (a *App) SetWorkflowReportLimit(count int) error {
+	if count <= 0 {
+		return fmt.Errorf("report limit must be positive")
+	}
+	a.workflowReportMu.Lock()
+	a.workflowReportLimit = count
+	a.workflowReportMu.Unlock()
+	go a.saveSettings()
+	return nil
+}
+
+// ExportWorkflowRunHTML writes a run's report as a single HTML file with the screenshots and
+// UI dumps inlined, for sharing. An empty destPath writes report.html into the run directory.
+func (a *App) ExportWorkflowRunHTML(runId, destPath string) (string, error) {
+	report, err := a.GetWorkflowRunReport(runId)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(a.getWorkflowRunsPath(), runId)
+	if destPath == "" {
+		destPath = filepath.Join(dir, "report.html")
+	}
+
+	type htmlStep struct {
+		WorkflowStepReport
+		Image template.URL
+		XML   string
+	}
+	steps := make([]htmlStep, 0, len(report.Steps))
+	for _, s := range report.Steps {
+		hs := htmlStep{WorkflowStepReport: s}
+		if s.Screenshot != "" {
+			if data, err := os.ReadFile(filepath.Join(dir, s.Screenshot)); err == nil {
+				hs.Image = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(data))
+			}
+		}
+		if s.Hierarchy != "" {
+			if data, err := os.ReadFile(filepath.Join(dir, s.Hierarchy)); err == nil {
+				hs.XML = string(data)
+			}
+		}
+		steps = append(steps, hs)
+	}
+
+	var out strings.Builder
+	err = workflowReportTemplate.Execute(&out, map[string]interface{}{
+		"Report":   report,
+		"Steps":    steps,
+		"Started":  time.UnixMilli(report.StartTime).Format("2006-01-02 15:04:05"),
+		"Duration": time.Duration(report.EndTime-report.StartTime) * time.Millisecond,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	if err := writeFileAtomic(destPath, []byte(out.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return destPath, nil
+}
+
+var workflowReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Report.WorkflowName}} - {{.Started}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 24px; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; font-size: 13px; }
+.success { color: #389e0d; } .failed { color: #cf1322; } .cancelled { color: #888; }
+.evidence { margin-top: 8px; }
+.evidence img { max-width: 360px; border: 1px solid #ddd; }
+pre { max-height: 400px; overflow: auto; background: #f6f6f6; padding: 8px; font-size: 11px; }
+</style>
+</head>
+<body>
+<h2>{{.Report.WorkflowName}}</h2>
+<p>Device {{.Report.DeviceID}} &middot; {{.Started}} &middot; {{.Duration}} &middot;
+<span class="{{.Report.Status}}">{{.Report.Status}}</span> &middot; {{.Report.Passed}} passed, {{.Report.Failed}} failed</p>
+{{if .Report.Error}}<p class="failed">{{.Report.Error}}</p>{{end}}
+<table>
+<tr><th>Step</th><th>Name</th><th>Type</th><th>Selector</th><th>Status</th><th>Attempts</th><th>Duration</th></tr>
+{{range .Steps}}
+<tr>
+<td>{{.Path}}</td><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Selector}}</td>
+<td class="{{.Status}}">{{.Status}}{{if .Error}}<br>{{.Error}}{{end}}
+{{if or .Image .XML}}<div class="evidence">
+{{if .Image}}<img src="{{.Image}}" alt="Screen at failure">{{end}}
+{{if .XML}}<details><summary>UI hierarchy</summary><pre>{{.XML}}</pre></details>{{end}}
+</div>{{end}}</td>
+<td>{{.Attempts}}</td><td>{{.DurationMs}} ms</td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// workflowHarness runs workflows against scripted screens, one per UI dump, and records the
+// events the engine emits
+type workflowHarness struct {
+	app     *App
+	screens [][]string // button texts on each successive dump; the last one repeats
+	dumps   int
+	events  []map[string]interface{}
+}
+
+func newWorkflowHarness(screens ...[]string) *workflowHarness {
+	h := &workflowHarness{screens: screens}
+	h.app = &App{
+		workflowUIDump: func(deviceId string) (*UIHierarchyResult, error) {
+			screen := h.screens[len(h.screens)-1]
+			if h.dumps < len(h.screens) {
+				screen = h.screens[h.dumps]
+			}
+			h.dumps++
+			root := &UINode{Class: "android.widget.FrameLayout", Bounds: "[0,0][1080,2340]"}
+			for _, text := range screen {
+				root.Nodes = append(root.Nodes, UINode{Class: "android.widget.Button", Text: text, Bounds: "[0,0][100,100]"})
+			}
+			return &UIHierarchyResult{Root: root}, nil
+		},
+		workflowEmit: func(event string, data map[string]interface{}) {
+			if event == "workflow-step-started" || event == "workflow-step-finished" {
+				data["event"] = event
+				h.events = append(h.events, data)
+			}
+		},
+	}
+	return h
+}
+
+func (h *workflowHarness) run(t *testing.T, steps ...WorkflowStep) (map[string]string, error) {
+	t.Helper()
+	workflow := Workflow{ID: "wf", Name: "test", Steps: []WorkflowStep{{ID: "start", Type: "start", NextStepId: steps[0].ID}}}
+	for i, step := range steps {
+		if i+1 < len(steps) {
+			step.NextStepId = steps[i+1].ID
+		}
+		workflow.Steps = append(workflow.Steps, step)
+	}
+	vars := map[string]string{}
+	return vars, h.app.runWorkflowInternal(context.Background(), "emulator-5554", workflow, stepScope{}, 0, vars)
+}
+
+// started lists "path id" for each step started, in order
+func (h *workflowHarness) started() []string {
+	var out []string
+	for _, e := range h.events {
+		if e["event"] == "workflow-step-started" {
+			out = append(out, e["stepPath"].(string)+" "+e["stepId"].(string))
+		}
+	}
+	return out
+}
+
+func (h *workflowHarness) finished(path string) map[string]interface{} {
+	for _, e := range h.events {
+		if e["event"] == "workflow-step-finished" && e["stepPath"] == path {
+			return e
+		}
+	}
+	return nil
+}
+
+func setVar(id, name, value string) WorkflowStep {
+	return WorkflowStep{ID: id, Type: "set_variable", Name: name, Value: value}
+}
+
+func onScreen(text string) *ElementSelector {
+	return &ElementSelector{Type: "text", Value: text}
+}
+
+func TestWorkflowIfInsideRepeat(t *testing.T) {
+	// The if sees Retry on the first and third iterations only, and the final if doesn't see it
+	h := newWorkflowHarness([]string{"Retry"}, []string{"OK"}, []string{"Retry"}, []string{"OK"})
+	vars, err := h.run(t,
+		setVar("a", "attempts", "0"),
+		setVar("b", "state", "begin"),
+		WorkflowStep{ID: "loop", Type: "repeat", Times: 3, Body: []WorkflowStep{
+			{ID: "check", Type: "if", ConditionType: "exists", Selector: onScreen("Retry"), Timeout: 1,
+				Then: []WorkflowStep{setVar("retry", "state", "retry_${_iteration}")},
+				Else: []WorkflowStep{setVar("ok", "state", "ok_${_iteration}")},
+			},
+			setVar("last", "attempts", "${_iteration}"),
+		}},
+		WorkflowStep{ID: "final", Type: "if", ConditionType: "exists", Selector: onScreen("Retry"), Timeout: 1,
+			Then: []WorkflowStep{setVar("again", "state", "again")},
+			Else: []WorkflowStep{setVar("done", "state", "done")},
+		},
+	)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	want := []string{
+		"1 a",
+		"2 b",
+		"3 loop",
+		"3.1.1 check",
+		"3.1.1.1.1 retry",
+		"3.1.2 last",
+		"3.2.1 check",
+		"3.2.1.2.1 ok",
+		"3.2.2 last",
+		"3.3.1 check",
+		"3.3.1.1.1 retry",
+		"3.3.2 last",
+		"4 final",
+		"4.2.1 done",
+	}
+	if got := h.started(); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps started:\n got  %q\n want %q", got, want)
+	}
+	if h.dumps != 4 {
+		t.Errorf("%d UI dumps, want one per condition check", h.dumps)
+	}
+	if vars["state"] != "done" || vars["attempts"] != "3" {
+		t.Errorf("vars %v", vars)
+	}
+
+	// Nested steps keep the index of the top-level step they run under
+	for path, result := range map[string]bool{"3.1.1": true, "3.2.1": false, "3.3.1": true, "4": false} {
+		e := h.finished(path)
+		if e == nil {
+			t.Errorf("no finished event for %s", path)
+			continue
+		}
+		if e["result"] != result || e["status"] != "success" {
+			t.Errorf("%s finished with result %v status %v, want %v success", path, e["result"], e["status"], result)
+		}
+		if want := int(path[0] - '0'); e["stepIndex"] != want {
+			t.Errorf("%s reported step index %v, want %d", path, e["stepIndex"], want)
+		}
+	}
+}
+
+func TestWorkflowRepeatWhileCondition(t *testing.T) {
+	// Each iteration dumps once for the repeat's condition and once for the inner if
+	more := []string{"Load more"}
+	h := newWorkflowHarness(more, more, more, more, []string{"End of list"})
+	vars, err := h.run(t,
+		WorkflowStep{ID: "loop", Type: "repeat", ConditionType: "exists", Selector: onScreen("Load more"), Timeout: 1, Body: []WorkflowStep{
+			setVar("page", "pages", "${_iteration}"),
+			{ID: "inner", Type: "if", ConditionType: "not_exists", Selector: onScreen("Error"),
+				Then: []WorkflowStep{setVar("fine", "ok", "${_iteration}")},
+			},
+		}},
+	)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := []string{"1 loop", "1.1.1 page", "1.1.2 inner", "1.1.2.1.1 fine", "1.2.1 page", "1.2.2 inner", "1.2.2.1.1 fine"}
+	if got := h.started(); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps started:\n got  %q\n want %q", got, want)
+	}
+	if vars["pages"] != "2" || vars["ok"] != "2" {
+		t.Errorf("vars %v", vars)
+	}
+}
+
+func TestWorkflowRepeatIterationLimit(t *testing.T) {
+	h := newWorkflowHarness([]string{"Load more"})
+	_, err := h.run(t,
+		WorkflowStep{ID: "loop", Type: "repeat", ConditionType: "exists", Selector: onScreen("Load more"), MaxIterations: 2, Body: []WorkflowStep{
+			setVar("page", "pages", "${_iteration}"),
+		}},
+	)
+	if err == nil || !strings.Contains(err.Error(), "after 2 iterations") {
+		t.Fatalf("got %v, want the iteration limit error", err)
+	}
+	if e := h.finished("1"); e == nil || e["status"] != "failed" {
+		t.Errorf("repeat step finished as %v, want failed", e)
+	}
+}
+
+func TestWorkflowEmptyElseBranch(t *testing.T) {
+	h := newWorkflowHarness([]string{"Cart"})
+	vars, err := h.run(t,
+		WorkflowStep{ID: "check", Type: "if", ConditionType: "not_exists", Selector: onScreen("Cart"), Timeout: 1,
+			Then: []WorkflowStep{setVar("empty", "cart", "missing")},
+		},
+		setVar("after", "cart", "${cart}seen"),
+	)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got, want := h.started(), []string{"1 check", "2 after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("steps started %q, want %q", got, want)
+	}
+	if vars["cart"] != "${cart}seen" {
+		t.Errorf("cart = %q, the then branch should not have run", vars["cart"])
+	}
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// workflowTriggerTick is how often schedules are checked; cron has minute resolution
+	workflowTriggerTick = 15 * time.Second
+
+	// appInstallPollInterval is how often connected devices are listed for app_installed triggers
+	appInstallPollInterval = 30 * time.Second
+
+	// connectFlapGrace is how long a device must have been gone before coming back counts as
+	// a new connection; a cable or adb hiccup within it doesn't fire connect triggers again
+	connectFlapGrace = time.Minute
+)
+
+var workflowTriggerTypes = map[string]bool{"device_connected": true, "app_installed": true, "schedule": true}
+
+// Workflow Trigger State
+var (
+	workflowTriggers       []WorkflowTrigger
+	workflowTriggersMu     sync.Mutex
+	workflowTriggersLoaded bool
+	workflowTriggerCancel  context.CancelFunc
+)
+
+// Device presence and installed packages as last seen by the triggers, keyed by serial. The
+// first device list after startup is a baseline: devices already connected don't fire.
+var (
+	triggerDevices      = make(map[string]*triggerDeviceState)
+	triggerDevicesReady bool
+	triggerStateMu      sync.Mutex
+)
+
+type triggerDeviceState struct {
+	device      Device
+	online      bool
+	wentOffline time.Time
+	packages    map[string]bool // nil until the first poll
+}
+
+// AddWorkflowTrigger registers a trigger for a saved workflow. WorkflowID may also be the
+// workflow's name. Triggers are persisted and resume when the app starts.
+func (a *App) AddWorkflowTrigger(trigger WorkflowTrigger) (*WorkflowTrigger, error) {
+	if !workflowTriggerTypes[trigger.Type] {
+		return nil, fmt.Errorf("unknown trigger type: %s", trigger.Type)
+	}
+	workflow, err := a.findSavedWorkflow(trigger.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if trigger.Type == "schedule" {
+		sched, err := parseCron(trigger.Cron)
+		if err != nil {
+			return nil, err
+		}
+		if sched.next(now).IsZero() {
+			return nil, fmt.Errorf("cron expression %q never matches", trigger.Cron)
+		}
+		trigger.Cron = strings.TrimSpace(trigger.Cron)
+		trigger.NextRun = sched.next(now).Unix()
+	}
+
+	trigger.ID = fmt.Sprintf("trigger_%d", now.UnixNano())
+	trigger.WorkflowID = workflow.ID
+	trigger.Serial = strings.TrimSpace(trigger.Serial)
+	trigger.Model = strings.TrimSpace(trigger.Model)
+	trigger.Package = strings.TrimSpace(trigger.Package)
+	trigger.LastFired = 0
+	trigger.LastError = ""
+	trigger.CreatedAt = now.Unix()
+	if trigger.Name == "" {
+		trigger.Name = workflow.Name
+	}
+
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	a.loadWorkflowTriggersLocked()
+	workflowTriggers = append(workflowTriggers, trigger)
+	if err := a.saveWorkflowTriggersLocked(); err != nil {
+		return nil, fmt.Errorf("failed to save trigger: %w", err)
+	}
+	return &trigger, nil
+}
+
+// ListWorkflowTriggers returns all triggers in the order they were added
+func (a *App) ListWorkflowTriggers() []WorkflowTrigger {
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	a.loadWorkflowTriggersLocked()
+	return append([]WorkflowTrigger{}, workflowTriggers...)
+}
+
+// RemoveWorkflowTrigger deletes a trigger; a run it already started keeps going
+func (a *App) RemoveWorkflowTrigger(id string) error {
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	a.loadWorkflowTriggersLocked()
+
+	for i, t := range workflowTriggers {
+		if t.ID == id {
+			workflowTriggers = append(workflowTriggers[:i], workflowTriggers[i+1:]...)
+			return a.saveWorkflowTriggersLocked()
+		}
+	}
+	return fmt.Errorf("trigger not found: %s", id)
+}
+
+// SetWorkflowTriggerEnabled pauses or resumes a trigger without deleting it. A resumed
+// schedule picks up from its next match rather than catching up.
+func (a *App) SetWorkflowTriggerEnabled(id string, enabled bool) error {
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	a.loadWorkflowTriggersLocked()
+
+	for i := range workflowTriggers {
+		t := &workflowTriggers[i]
+		if t.ID != id {
+			continue
+		}
+		t.Disabled = !enabled
+		if enabled && t.Type == "schedule" {
+			if sched, err := parseCron(t.Cron); err == nil {
+				t.NextRun = sched.next(time.Now()).Unix()
+			}
+		}
+		return a.saveWorkflowTriggersLocked()
+	}
+	return fmt.Errorf("trigger not found: %s", id)
+}
+
+// matches reports whether the trigger applies to device
+func (t WorkflowTrigger) matches(device Device) bool {
+	if t.Serial != "" && !strings.EqualFold(device.Serial, t.Serial) && !containsFold(device.IDs, t.Serial) {
+		return false
+	}
+	if t.Model != "" && !strings.Contains(strings.ToLower(device.Model), strings.ToLower(t.Model)) {
+		return false
+	}
+	return true
+}
+
+// enabledTriggers returns a copy of the enabled triggers of one type
+func (a *App) enabledTriggers(triggerType string) []WorkflowTrigger {
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	a.loadWorkflowTriggersLocked()
+
+	var result []WorkflowTrigger
+	for _, t := range workflowTriggers {
+		if t.Type == triggerType && !t.Disabled {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// startWorkflowTriggers loads persisted triggers and checks schedules and installed apps in
+// the background until shutdown; connect triggers are driven by the device monitor
+func (a *App) startWorkflowTriggers() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	workflowTriggersMu.Lock()
+	workflowTriggerCancel = cancel
+	a.loadWorkflowTriggersLocked()
+	workflowTriggersMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(workflowTriggerTick)
+		defer ticker.Stop()
+		var lastAppPoll time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				a.runDueWorkflowSchedules(now)
+				if now.Sub(lastAppPoll) >= appInstallPollInterval {
+					lastAppPoll = now
+					a.pollInstalledApps()
+				}
+			}
+		}
+	}()
+}
+
+func (a *App) stopWorkflowTriggers() {
+	workflowTriggersMu.Lock()
+	defer workflowTriggersMu.Unlock()
+	if workflowTriggerCancel != nil {
+		workflowTriggerCancel()
+		workflowTriggerCancel = nil
+	}
+}
+
+// evaluateConnectTriggers is called with each device list from the device monitor and fires
+// device_connected triggers for devices that came online. A device that went offline and
+// returned within connectFlapGrace is treated as never having left.
+func (a *App) evaluateConnectTriggers(devices []Device) {
+	now := time.Now()
+	var connected []Device
+
+	triggerStateMu.Lock()
+	online := make(map[string]bool)
+	for _, d := range devices {
+		if d.State != "device" {
+			continue
+		}
+		key := triggerDeviceKey(d)
+		online[key] = true
+		st := triggerDevices[key]
+		if st == nil {
+			st = &triggerDeviceState{}
+			triggerDevices[key] = st
+		}
+		if !st.online && triggerDevicesReady && (st.wentOffline.IsZero() || now.Sub(st.wentOffline) > connectFlapGrace) {
+			connected = append(connected, d)
+		}
+		st.device = d
+		st.online = true
+	}
+	for key, st := range triggerDevices {
+		if st.online && !online[key] {
+			st.online = false
+			st.wentOffline = now
+		}
+	}
+	triggerDevicesReady = true
+	triggerStateMu.Unlock()
+
+	if len(connected) == 0 {
+		return
+	}
+	for _, t := range a.enabledTriggers("device_connected") {
+		for _, d := range connected {
+			if t.matches(d) {
+				a.fireWorkflowTrigger(t, d, nil)
+			}
+		}
+	}
+}
+
+func triggerDeviceKey(d Device) string {
+	if d.Serial != "" {
+		return d.Serial
+	}
+	return d.ID
+}
+
+// pollInstalledApps lists the packages of online devices that an app_installed trigger
+// watches and fires for packages that weren't there on the previous poll
+func (a *App) pollInstalledApps() {
+	triggers := a.enabledTriggers("app_installed")
+	if len(triggers) == 0 {
+		return
+	}
+
+	triggerStateMu.Lock()
+	var watched []Device
+	for _, st := range triggerDevices {
+		if !st.online {
+			continue
+		}
+		for _, t := range triggers {
+			if t.matches(st.device) {
+				watched = append(watched, st.device)
+				break
+			}
+		}
+	}
+	triggerStateMu.Unlock()
+
+	for _, d := range watched {
+		out, err := a.RunAdbCommand(d.ID, "shell pm list packages")
+		if err != nil {
+			continue
+		}
+		current := make(map[string]bool)
+		for _, line := range strings.Split(out, "\n") {
+			if pkg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "package:")); pkg != "" {
+				current[pkg] = true
+			}
+		}
+
+		triggerStateMu.Lock()
+		st := triggerDevices[triggerDeviceKey(d)]
+		var installed []string
+		if st != nil {
+			if st.packages != nil {
+				for pkg := range current {
+					if !st.packages[pkg] {
+						installed = append(installed, pkg)
+					}
+				}
+			}
+			st.packages = current
+		}
+		triggerStateMu.Unlock()
+
+		for _, pkg := range installed {
+			for _, t := range triggers {
+				if t.matches(d) && (t.Package == "" || t.Package == pkg) {
+					a.fireWorkflowTrigger(t, d, map[string]string{"package": pkg})
+				}
+			}
+		}
+	}
+}
+
+// runDueWorkflowSchedules fires every schedule trigger whose next run has passed on each
+// connected device it matches, and advances it
+func (a *App) runDueWorkflowSchedules(now time.Time) {
+	workflowTriggersMu.Lock()
+	a.loadWorkflowTriggersLocked()
+	var due []WorkflowTrigger
+	for i := range workflowTriggers {
+		t := &workflowTriggers[i]
+		if t.Type != "schedule" || t.Disabled || t.NextRun > now.Unix() {
+			continue
+		}
+		due = append(due, *t)
+		if sched, err := parseCron(t.Cron); err == nil {
+			t.NextRun = sched.next(now).Unix()
+		}
+	}
+	if len(due) > 0 {
+		_ = a.saveWorkflowTriggersLocked()
+	}
+	workflowTriggersMu.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	devices, err := a.GetDevices(false)
+	if err != nil {
+		a.Log("Workflow triggers: failed to get devices: %v", err)
+		return
+	}
+	for _, t := range due {
+		fired := false
+		for _, d := range devices {
+			if d.State == "device" && t.matches(d) {
+				a.fireWorkflowTrigger(t, d, nil)
+				fired = true
+			}
+		}
+		if !fired {
+			a.recordTriggerFired(t, Device{}, fmt.Errorf("no matching device connected"))
+		}
+	}
+}
+
+// fireWorkflowTrigger starts the trigger's workflow on device. The run shows up in the run
+// reports with the trigger's ID.
+func (a *App) fireWorkflowTrigger(t WorkflowTrigger, device Device, extra map[string]string) {
+	vars := map[string]string{"serial": device.Serial, "model": device.Model}
+	for k, v := range extra {
+		vars[k] = v
+	}
+	workflow, err := a.findSavedWorkflow(t.WorkflowID)
+	if err == nil {
+		err = a.startWorkflow(device, workflow, vars, t.ID)
+	}
+	a.recordTriggerFired(t, device, err)
+}
+
+func (a *App) recordTriggerFired(t WorkflowTrigger, device Device, err error) {
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+		a.Log("Workflow trigger %s (%s) failed: %v", t.ID, t.Type, err)
+	} else {
+		a.Log("Workflow trigger %s (%s) started %q on %s", t.ID, t.Type, t.Name, device.ID)
+	}
+
+	workflowTriggersMu.Lock()
+	for i := range workflowTriggers {
+		if workflowTriggers[i].ID == t.ID {
+			workflowTriggers[i].LastFired = time.Now().Unix()
+			workflowTriggers[i].LastError = errMsg
+		}
+	}
+	_ = a.saveWorkflowTriggersLocked()
+	workflowTriggersMu.Unlock()
+
+	wailsRuntime.EventsEmit(a.ctx, "workflow-trigger-fired", map[string]interface{}{
+		"id":         t.ID,
+		"type":       t.Type,
+		"workflowId": t.WorkflowID,
+		"deviceId":   device.ID,
+		"error":      errMsg,
+	})
+}
+
+func (a *App) getWorkflowTriggersPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	gazeDir := filepath.Join(configDir, "Gaze")
+	_ = os.MkdirAll(gazeDir, 0755)
+	return filepath.Join(gazeDir, "workflow_triggers.json")
+}
+
+// loadWorkflowTriggersLocked reads workflow_triggers.json once and recomputes the next run of
+// schedules so runs missed while the app was closed are skipped; callers must hold
+// workflowTriggersMu
+func (a *App) loadWorkflowTriggersLocked() {
+	if workflowTriggersLoaded {
+		return
+	}
+	workflowTriggersLoaded = true
+	workflowTriggers = []WorkflowTrigger{}
+
+	data, err := os.ReadFile(a.getWorkflowTriggersPath())
+	if err != nil {
+		return
+	}
+	var stored []WorkflowTrigger
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return
+	}
+	now := time.Now()
+	for _, t := range stored {
+		if t.Type == "schedule" {
+			sched, err := parseCron(t.Cron)
+			if err != nil {
+				continue
+			}
+			t.NextRun = sched.next(now).Unix()
+		}
+		workflowTriggers = append(workflowTriggers, t)
+	}
+}
+
+// saveWorkflowTriggersLocked writes workflow_triggers.json; callers must hold workflowTriggersMu
+func (a *App) saveWorkflowTriggersLocked() error {
+	data, err := json.MarshalIndent(workflowTriggers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(a.getWorkflowTriggersPath(), data, 0644)
+}
@@ -0,0 +1,1057 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// XPath 1.0 over the UI hierarchy, enough for expressions copied from Appium Inspector:
+// absolute and // paths, class names or * as node tests, the common axes, attribute and
+// positional predicates, and/or/not, grouping like (//Button)[2] and unions with |.
+//
+// The document has a single "hierarchy" element holding the top-level nodes, as in
+// uiautomator dumps. Two differences from strict XPath: "node" as a name test matches any
+// element (uiautomator's raw XML names every element node), and an attribute only counts as
+// present when it is non-empty, since uiautomator writes every attribute on every node.
+
+// SearchElementsXPath returns the elements an XPath expression selects, in document order,
+// each with its absolute path. Invalid expressions select nothing.
+func (a *App) SearchElementsXPath(root *UINode, xpath string) []SearchResult {
+	results, err := a.evalXPath(root, xpath)
+	if err != nil {
+		fmt.Printf("[Automation] Invalid XPath %q: %v\n", xpath, err)
+		return []SearchResult{}
+	}
+	return results
+}
+
+// evalXPath is SearchElementsXPath with the parse error kept
+func (a *App) evalXPath(root *UINode, xpath string) ([]SearchResult, error) {
+	if root == nil {
+		return []SearchResult{}, nil
+	}
+	expr, err := compileXPath(xpath)
+	if err != nil {
+		return nil, err
+	}
+	doc := buildXTree(root)
+	v := expr.eval(a, xctx{node: doc, pos: 1, size: 1})
+	if v.kind != xvalNodes || v.attrs != nil {
+		return nil, fmt.Errorf("expression does not select elements")
+	}
+
+	results := make([]SearchResult, 0, len(v.nodes))
+	for _, n := range v.nodes {
+		if n.ui == nil {
+			continue
+		}
+		results = append(results, SearchResult{Node: n.ui, Path: n.path(), Depth: n.depth, Index: n.index})
+	}
+	return results, nil
+}
+
+// anchoredXPathOf is the XPath of target starting from its nearest element (target included)
+// whose resource-id is unique in the tree, e.g.
+// //androidx.recyclerview.widget.RecyclerView[@resource-id="com.app:id/list"]/android.widget.LinearLayout[2]/android.widget.Button.
+// Without such an element it is the absolute path.
+func anchoredXPathOf(root, target *UINode) string {
+	ids := make(map[string]int)
+	var found *xnode
+	var walk func(n *xnode)
+	walk = func(n *xnode) {
+		if n.ui != nil {
+			if n.ui.ResourceID != "" {
+				ids[n.ui.ResourceID]++
+			}
+			if n.ui == target && found == nil {
+				found = n
+			}
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(buildXTree(root))
+	if found == nil {
+		return ""
+	}
+
+	var segments []string
+	for c := found; c.ui != nil; c = c.parent {
+		if id := c.ui.ResourceID; id != "" && ids[id] == 1 {
+			anchor := fmt.Sprintf("//%s[@resource-id=%s]", c.stepName(), xpathLiteral(id))
+			for i := len(segments) - 1; i >= 0; i-- {
+				anchor += "/" + segments[i]
+			}
+			return anchor
+		}
+		segments = append(segments, c.step())
+	}
+	return found.path()
+}
+
+// xpathLiteral quotes s as an XPath string literal
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	parts := strings.Split(s, `"`)
+	for i, p := range parts {
+		parts[i] = `"` + p + `"`
+	}
+	return "concat(" + strings.Join(parts, `, '"', `) + ")"
+}
+
+// xnode is a UINode seen as an XPath node, with the parent links UINode lacks
+type xnode struct {
+	ui       *UINode // nil for the document and the hierarchy element
+	name     string  // "hierarchy" for the hierarchy element
+	parent   *xnode
+	children []*xnode
+	order    int // document order
+	index    int // position among the parent's children, as uiautomator's index attribute
+	depth    int
+}
+
+func buildXTree(root *UINode) *xnode {
+	doc := &xnode{}
+	hierarchy := &xnode{name: "hierarchy", parent: doc, order: 1, depth: -1}
+	doc.children = []*xnode{hierarchy}
+
+	order := 2
+	var add func(parent *xnode, ui *UINode, index, depth int)
+	add = func(parent *xnode, ui *UINode, index, depth int) {
+		n := &xnode{ui: ui, parent: parent, order: order, index: index, depth: depth}
+		order++
+		parent.children = append(parent.children, n)
+		for i := range ui.Nodes {
+			add(n, &ui.Nodes[i], i, depth+1)
+		}
+	}
+	if isSyntheticRoot(root) {
+		// Several windows were wrapped in a container that isn't on screen
+		for i := range root.Nodes {
+			add(hierarchy, &root.Nodes[i], i, 1)
+		}
+	} else {
+		add(hierarchy, root, 0, 0)
+	}
+	return doc
+}
+
+func (n *xnode) isElement() bool {
+	return n.ui != nil || n.name == "hierarchy"
+}
+
+// matchesName applies a name test
+func (n *xnode) matchesName(name string) bool {
+	if name == "*" {
+		return n.isElement()
+	}
+	if n.ui == nil {
+		return n.name != "" && n.name == name
+	}
+	if name == "node" || n.ui.Class == name {
+		return true
+	}
+	short := n.ui.Class
+	if i := strings.LastIndex(short, "."); i != -1 {
+		short = short[i+1:]
+	}
+	return short == name
+}
+
+func (n *xnode) stepName() string {
+	if n.ui == nil {
+		return n.name
+	}
+	if n.ui.Class == "" {
+		return "node"
+	}
+	return n.ui.Class
+}
+
+// path is the absolute XPath of n
+func (n *xnode) path() string {
+	var segments []string
+	for c := n; c.parent != nil; c = c.parent {
+		segments = append(segments, c.step())
+	}
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// step is n's location step under its parent, with a position only when a sibling shares the name
+func (n *xnode) step() string {
+	name := n.stepName()
+	same, pos := 0, 0
+	for _, sib := range n.parent.children {
+		if sib.matchesName(name) {
+			same++
+			if sib == n {
+				pos = same
+			}
+		}
+	}
+	if same > 1 {
+		name = fmt.Sprintf("%s[%d]", name, pos)
+	}
+	return name
+}
+
+func (n *xnode) attr(a *App, name string) string {
+	if n.ui == nil {
+		return ""
+	}
+	if name == "index" {
+		return strconv.Itoa(n.index)
+	}
+	return a.getNodeAttribute(n.ui, name)
+}
+
+func (n *xnode) stringValue() string {
+	if n.ui == nil {
+		return ""
+	}
+	return n.ui.Text
+}
+
+// Values
+
+type xvalKind int
+
+const (
+	xvalNodes xvalKind = iota
+	xvalString
+	xvalNumber
+	xvalBool
+)
+
+// xval is an XPath value. A node-set that ends in an attribute step holds the attribute
+// values in attrs instead of nodes.
+type xval struct {
+	kind  xvalKind
+	nodes []*xnode
+	attrs []string
+	str   string
+	num   float64
+	b     bool
+}
+
+func (v xval) items() []string {
+	if v.attrs != nil {
+		return v.attrs
+	}
+	items := make([]string, len(v.nodes))
+	for i, n := range v.nodes {
+		items[i] = n.stringValue()
+	}
+	return items
+}
+
+func (v xval) toString() string {
+	switch v.kind {
+	case xvalString:
+		return v.str
+	case xvalNumber:
+		if v.num == math.Trunc(v.num) && !math.IsInf(v.num, 0) {
+			return strconv.FormatFloat(v.num, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case xvalBool:
+		return strconv.FormatBool(v.b)
+	}
+	if items := v.items(); len(items) > 0 {
+		return items[0]
+	}
+	return ""
+}
+
+func (v xval) toNumber() float64 {
+	switch v.kind {
+	case xvalNumber:
+		return v.num
+	case xvalBool:
+		if v.b {
+			return 1
+		}
+		return 0
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v.toString()), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return f
+}
+
+func (v xval) toBool() bool {
+	switch v.kind {
+	case xvalString:
+		return v.str != ""
+	case xvalNumber:
+		return v.num != 0 && !math.IsNaN(v.num)
+	case xvalBool:
+		return v.b
+	}
+	if v.attrs != nil {
+		for _, s := range v.attrs {
+			if s != "" {
+				return true
+			}
+		}
+		return false
+	}
+	return len(v.nodes) > 0
+}
+
+func xstr(s string) xval     { return xval{kind: xvalString, str: s} }
+func xnum(f float64) xval    { return xval{kind: xvalNumber, num: f} }
+func xbool(b bool) xval      { return xval{kind: xvalBool, b: b} }
+func xnodes(n []*xnode) xval { return xval{kind: xvalNodes, nodes: n} }
+
+// compareXVal applies =, !=, <, <=, > or >= with XPath's conversion rules
+func compareXVal(op string, l, r xval) bool {
+	if l.kind == xvalNodes || r.kind == xvalNodes {
+		if l.kind == xvalNodes && r.kind == xvalNodes {
+			for _, a := range l.items() {
+				for _, b := range r.items() {
+					if compareXVal(op, xstr(a), xstr(b)) {
+						return true
+					}
+				}
+			}
+			return false
+		}
+		set, other, flipped := l, r, false
+		if r.kind == xvalNodes {
+			set, other, flipped = r, l, true
+		}
+		if other.kind == xvalBool {
+			return compareScalar(op, xbool(set.toBool()), other, flipped)
+		}
+		for _, item := range set.items() {
+			if compareScalar(op, xstr(item), other, flipped) {
+				return true
+			}
+		}
+		return false
+	}
+	return compareScalar(op, l, r, false)
+}
+
+func compareScalar(op string, l, r xval, flipped bool) bool {
+	if flipped {
+		l, r = r, l
+	}
+	if op == "=" || op == "!=" {
+		var eq bool
+		switch {
+		case l.kind == xvalBool || r.kind == xvalBool:
+			eq = l.toBool() == r.toBool()
+		case l.kind == xvalNumber || r.kind == xvalNumber:
+			eq = l.toNumber() == r.toNumber()
+		default:
+			eq = l.toString() == r.toString()
+		}
+		return eq == (op == "=")
+	}
+	a, b := l.toNumber(), r.toNumber()
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// Expressions
+
+type xctx struct {
+	node      *xnode
+	pos, size int
+}
+
+type xexpr interface {
+	eval(a *App, ctx xctx) xval
+}
+
+type xliteral struct{ v xval }
+
+func (e xliteral) eval(*App, xctx) xval { return e.v }
+
+type xbinary struct {
+	op   string
+	l, r xexpr
+}
+
+func (e xbinary) eval(a *App, ctx xctx) xval {
+	switch e.op {
+	case "or":
+		return xbool(e.l.eval(a, ctx).toBool() || e.r.eval(a, ctx).toBool())
+	case "and":
+		return xbool(e.l.eval(a, ctx).toBool() && e.r.eval(a, ctx).toBool())
+	case "|":
+		l, r := e.l.eval(a, ctx), e.r.eval(a, ctx)
+		if l.kind != xvalNodes || r.kind != xvalNodes || l.attrs != nil || r.attrs != nil {
+			return xnodes(nil)
+		}
+		return xnodes(docOrder(append(append([]*xnode{}, l.nodes...), r.nodes...)))
+	}
+	return xbool(compareXVal(e.op, e.l.eval(a, ctx), e.r.eval(a, ctx)))
+}
+
+type xnegate struct{ e xexpr }
+
+func (e xnegate) eval(a *App, ctx xctx) xval { return xnum(-e.e.eval(a, ctx).toNumber()) }
+
+type xcall struct {
+	name string
+	args []xexpr
+}
+
+func (e xcall) eval(a *App, ctx xctx) xval {
+	arg := func(i int) xval { return e.args[i].eval(a, ctx) }
+	str := func(i int) string {
+		if i < len(e.args) {
+			return arg(i).toString()
+		}
+		return ctx.node.stringValue()
+	}
+	switch e.name {
+	case "contains":
+		return xbool(strings.Contains(str(0), str(1)))
+	case "starts-with":
+		return xbool(strings.HasPrefix(str(0), str(1)))
+	case "ends-with":
+		return xbool(strings.HasSuffix(str(0), str(1)))
+	case "not":
+		return xbool(!arg(0).toBool())
+	case "true":
+		return xbool(true)
+	case "false":
+		return xbool(false)
+	case "last":
+		return xnum(float64(ctx.size))
+	case "position":
+		return xnum(float64(ctx.pos))
+	case "count":
+		v := arg(0)
+		if v.attrs != nil {
+			return xnum(float64(len(v.attrs)))
+		}
+		return xnum(float64(len(v.nodes)))
+	case "string", "text":
+		return xstr(str(0))
+	case "string-length":
+		return xnum(float64(len([]rune(str(0)))))
+	case "normalize-space":
+		return xstr(strings.Join(strings.Fields(str(0)), " "))
+	case "concat":
+		var b strings.Builder
+		for i := range e.args {
+			b.WriteString(str(i))
+		}
+		return xstr(b.String())
+	case "number":
+		if len(e.args) == 0 {
+			return xnum(xstr(ctx.node.stringValue()).toNumber())
+		}
+		return xnum(arg(0).toNumber())
+	case "boolean":
+		return xbool(arg(0).toBool())
+	}
+	return xval{}
+}
+
+// xstep is one location step; attr is set for a final @name step
+type xstep struct {
+	axis  string
+	test  string // name, "*" or "node()"
+	attr  string
+	preds []xexpr
+}
+
+type xpathExpr struct {
+	absolute bool
+	filter   xexpr   // grouped or primary expression the steps start from, or nil
+	preds    []xexpr // predicates on filter
+	steps    []xstep
+}
+
+func (e xpathExpr) eval(a *App, ctx xctx) xval {
+	var nodes []*xnode
+	switch {
+	case e.filter != nil:
+		v := e.filter.eval(a, ctx)
+		if len(e.preds) == 0 && len(e.steps) == 0 {
+			return v
+		}
+		if v.kind != xvalNodes || v.attrs != nil {
+			return xnodes(nil)
+		}
+		nodes = applyPredicates(a, v.nodes, e.preds)
+	case e.absolute:
+		root := ctx.node
+		for root.parent != nil {
+			root = root.parent
+		}
+		nodes = []*xnode{root}
+	default:
+		nodes = []*xnode{ctx.node}
+	}
+
+	for _, step := range e.steps {
+		if step.attr != "" {
+			attrs := make([]string, 0, len(nodes))
+			for _, n := range nodes {
+				if n.ui != nil {
+					attrs = append(attrs, n.attr(a, step.attr))
+				}
+			}
+			return xval{kind: xvalNodes, attrs: attrs}
+		}
+		seen := make(map[*xnode]bool)
+		var next []*xnode
+		for _, n := range nodes {
+			var candidates []*xnode
+			for _, c := range axisNodes(n, step.axis) {
+				if step.test == "node()" || c.matchesName(step.test) {
+					candidates = append(candidates, c)
+				}
+			}
+			for _, c := range applyPredicates(a, candidates, step.preds) {
+				if !seen[c] {
+					seen[c] = true
+					next = append(next, c)
+				}
+			}
+		}
+		nodes = docOrder(next)
+	}
+	return xnodes(nodes)
+}
+
+// applyPredicates filters nodes, which are in axis order, one predicate at a time; a number
+// keeps the node at that position
+func applyPredicates(a *App, nodes []*xnode, preds []xexpr) []*xnode {
+	for _, pred := range preds {
+		var kept []*xnode
+		for i, n := range nodes {
+			v := pred.eval(a, xctx{node: n, pos: i + 1, size: len(nodes)})
+			if v.kind == xvalNumber {
+				if v.num == float64(i+1) {
+					kept = append(kept, n)
+				}
+			} else if v.toBool() {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	}
+	return nodes
+}
+
+// axisNodes lists the nodes on an axis, nearest first for the reverse axes
+func axisNodes(n *xnode, axis string) []*xnode {
+	var out []*xnode
+	var descend func(*xnode)
+	descend = func(x *xnode) {
+		for _, c := range x.children {
+			out = append(out, c)
+			descend(c)
+		}
+	}
+	switch axis {
+	case "child":
+		return n.children
+	case "self":
+		return []*xnode{n}
+	case "parent":
+		if n.parent != nil {
+			return []*xnode{n.parent}
+		}
+	case "descendant":
+		descend(n)
+	case "descendant-or-self":
+		out = append(out, n)
+		descend(n)
+	case "ancestor", "ancestor-or-self":
+		if axis == "ancestor-or-self" {
+			out = append(out, n)
+		}
+		for p := n.parent; p != nil; p = p.parent {
+			out = append(out, p)
+		}
+	case "following-sibling", "preceding-sibling":
+		if n.parent == nil {
+			return nil
+		}
+		siblings := n.parent.children
+		at := 0
+		for i, s := range siblings {
+			if s == n {
+				at = i
+			}
+		}
+		if axis == "following-sibling" {
+			return siblings[at+1:]
+		}
+		for i := at - 1; i >= 0; i-- {
+			out = append(out, siblings[i])
+		}
+	}
+	return out
+}
+
+func docOrder(nodes []*xnode) []*xnode {
+	seen := make(map[*xnode]bool, len(nodes))
+	out := nodes[:0:0]
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].order < out[j].order })
+	return out
+}
+
+// Parsing
+
+var xpathAxes = map[string]bool{
+	"child": true, "descendant": true, "descendant-or-self": true, "self": true, "parent": true,
+	"ancestor": true, "ancestor-or-self": true, "following-sibling": true, "preceding-sibling": true,
+}
+
+// xpathFunctions maps each supported function to its minimum and maximum argument count
+var xpathFunctions = map[string][2]int{
+	"contains": {2, 2}, "starts-with": {2, 2}, "ends-with": {2, 2}, "not": {1, 1},
+	"true": {0, 0}, "false": {0, 0}, "last": {0, 0}, "position": {0, 0}, "count": {1, 1},
+	"string": {0, 1}, "text": {0, 0}, "string-length": {0, 1}, "normalize-space": {0, 1},
+	"concat": {2, 99}, "number": {0, 1}, "boolean": {1, 1},
+}
+
+type xtoken struct {
+	kind string // "name", "string", "number", "axis" or the operator itself
+	text string
+}
+
+func tokenizeXPath(s string) ([]xtoken, error) {
+	var tokens []xtoken
+	r := []rune(s)
+	isNameStart := func(c rune) bool { return unicode.IsLetter(c) || c == '_' }
+	isNameChar := func(c rune) bool {
+		return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.'
+	}
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '/':
+			if i+1 < len(r) && r[i+1] == '/' {
+				tokens = append(tokens, xtoken{kind: "//"})
+				i += 2
+			} else {
+				tokens = append(tokens, xtoken{kind: "/"})
+				i++
+			}
+		case c == '.' && i+1 < len(r) && r[i+1] == '.':
+			tokens = append(tokens, xtoken{kind: ".."})
+			i += 2
+		case c == '.' && (i+1 >= len(r) || !unicode.IsDigit(r[i+1])):
+			tokens = append(tokens, xtoken{kind: "."})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			if i+1 < len(r) && r[i+1] == '=' {
+				tokens = append(tokens, xtoken{kind: string(c) + "="})
+				i += 2
+			} else if c == '!' {
+				return nil, fmt.Errorf("unexpected '!' at %d", i)
+			} else {
+				tokens = append(tokens, xtoken{kind: string(c)})
+				i++
+			}
+		case strings.ContainsRune("[]()@,=|*-", c):
+			tokens = append(tokens, xtoken{kind: string(c)})
+			i++
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(r) && r[end] != c {
+				end++
+			}
+			if end >= len(r) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, xtoken{kind: "string", text: string(r[i+1 : end])})
+			i = end + 1
+		case unicode.IsDigit(c) || c == '.':
+			end := i
+			for end < len(r) && (unicode.IsDigit(r[end]) || r[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, xtoken{kind: "number", text: string(r[i:end])})
+			i = end
+		case isNameStart(c):
+			end := i
+			for end < len(r) && isNameChar(r[end]) {
+				end++
+			}
+			name := string(r[i:end])
+			if end+1 < len(r) && r[end] == ':' && r[end+1] == ':' {
+				tokens = append(tokens, xtoken{kind: "axis", text: name})
+				end += 2
+			} else {
+				tokens = append(tokens, xtoken{kind: "name", text: name})
+			}
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+type xparser struct {
+	tokens []xtoken
+	pos    int
+}
+
+// compileXPath parses an XPath expression
+func compileXPath(s string) (xexpr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	tokens, err := tokenizeXPath(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &xparser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.describe())
+	}
+	return expr, nil
+}
+
+func (p *xparser) peek() xtoken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return xtoken{kind: "end"}
+}
+
+func (p *xparser) peekAt(offset int) xtoken {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return xtoken{kind: "end"}
+}
+
+func (p *xparser) next() xtoken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *xparser) describe() string {
+	t := p.peek()
+	switch t.kind {
+	case "end":
+		return "end of expression"
+	case "name", "number", "axis":
+		return fmt.Sprintf("%q", t.text)
+	case "string":
+		return fmt.Sprintf("'%s'", t.text)
+	}
+	return fmt.Sprintf("%q", t.kind)
+}
+
+func (p *xparser) expect(kind string) error {
+	if p.peek().kind != kind {
+		return fmt.Errorf("expected %q, found %s", kind, p.describe())
+	}
+	p.pos++
+	return nil
+}
+
+// isOperatorName reports whether the next token is the operator word (and, or). Operators
+// are only looked for after a complete operand, so these never clash with name tests.
+func (p *xparser) isOperatorName(word string) bool {
+	t := p.peek()
+	return t.kind == "name" && t.text == word
+}
+
+func (p *xparser) parseOr() (xexpr, error) {
+	l, err := p.parseAnd()
+	for err == nil && p.isOperatorName("or") {
+		p.pos++
+		var r xexpr
+		if r, err = p.parseAnd(); err == nil {
+			l = xbinary{op: "or", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *xparser) parseAnd() (xexpr, error) {
+	l, err := p.parseEquality()
+	for err == nil && p.isOperatorName("and") {
+		p.pos++
+		var r xexpr
+		if r, err = p.parseEquality(); err == nil {
+			l = xbinary{op: "and", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *xparser) parseEquality() (xexpr, error) {
+	l, err := p.parseRelational()
+	for err == nil && (p.peek().kind == "=" || p.peek().kind == "!=") {
+		op := p.next().kind
+		var r xexpr
+		if r, err = p.parseRelational(); err == nil {
+			l = xbinary{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *xparser) parseRelational() (xexpr, error) {
+	l, err := p.parseUnary()
+	for err == nil {
+		op := p.peek().kind
+		if op != "<" && op != "<=" && op != ">" && op != ">=" {
+			break
+		}
+		p.pos++
+		var r xexpr
+		if r, err = p.parseUnary(); err == nil {
+			l = xbinary{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *xparser) parseUnary() (xexpr, error) {
+	if p.peek().kind == "-" {
+		p.pos++
+		e, err := p.parseUnary()
+		return xnegate{e: e}, err
+	}
+	return p.parseUnion()
+}
+
+func (p *xparser) parseUnion() (xexpr, error) {
+	l, err := p.parsePath()
+	for err == nil && p.peek().kind == "|" {
+		p.pos++
+		var r xexpr
+		if r, err = p.parsePath(); err == nil {
+			l = xbinary{op: "|", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *xparser) parsePath() (xexpr, error) {
+	t := p.peek()
+	var path xpathExpr
+
+	switch {
+	case t.kind == "string":
+		p.pos++
+		return xliteral{xstr(t.text)}, nil
+	case t.kind == "number":
+		p.pos++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", t.text)
+		}
+		return xliteral{xnum(f)}, nil
+	case t.kind == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		path.filter = inner
+	case t.kind == "name" && p.peekAt(1).kind == "(" && t.text != "node":
+		call, err := p.parseCall()
+		if err != nil {
+			return nil, err
+		}
+		path.filter = call
+	case t.kind == "/":
+		p.pos++
+		path.absolute = true
+		if !p.startsStep() {
+			// "/" alone selects the document
+			return path, nil
+		}
+	case t.kind == "//":
+		p.pos++
+		path.absolute = true
+		path.steps = append(path.steps, xstep{axis: "descendant-or-self", test: "node()"})
+	}
+
+	if path.filter != nil {
+		preds, err := p.parsePredicates()
+		if err != nil {
+			return nil, err
+		}
+		path.preds = preds
+		switch p.peek().kind {
+		case "/":
+			p.pos++
+		case "//":
+			p.pos++
+			path.steps = append(path.steps, xstep{axis: "descendant-or-self", test: "node()"})
+		default:
+			if len(preds) == 0 {
+				return path.filter, nil
+			}
+			return path, nil
+		}
+	}
+
+	for {
+		step, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		path.steps = append(path.steps, step)
+		if step.attr != "" {
+			return path, nil
+		}
+		switch p.peek().kind {
+		case "/":
+			p.pos++
+		case "//":
+			p.pos++
+			path.steps = append(path.steps, xstep{axis: "descendant-or-self", test: "node()"})
+		default:
+			return path, nil
+		}
+	}
+}
+
+func (p *xparser) startsStep() bool {
+	switch p.peek().kind {
+	case "name", "axis", "*", "@", ".", "..":
+		return true
+	}
+	return false
+}
+
+func (p *xparser) parseStep() (xstep, error) {
+	t := p.next()
+	switch t.kind {
+	case ".":
+		return xstep{axis: "self", test: "node()"}, nil
+	case "..":
+		return xstep{axis: "parent", test: "node()"}, nil
+	case "@":
+		name := p.next()
+		if name.kind != "name" {
+			return xstep{}, fmt.Errorf("expected attribute name after @")
+		}
+		return xstep{attr: name.text}, nil
+	}
+
+	step := xstep{axis: "child"}
+	if t.kind == "axis" {
+		if !xpathAxes[t.text] {
+			return xstep{}, fmt.Errorf("unsupported axis %q", t.text)
+		}
+		step.axis = t.text
+		t = p.next()
+	}
+	switch {
+	case t.kind == "*":
+		step.test = "*"
+	case t.kind == "name" && t.text == "node" && p.peek().kind == "(":
+		p.pos++
+		if err := p.expect(")"); err != nil {
+			return xstep{}, err
+		}
+		step.test = "node()"
+	case t.kind == "name":
+		step.test = t.text
+	default:
+		p.pos--
+		return xstep{}, fmt.Errorf("expected a node test, found %s", p.describe())
+	}
+
+	preds, err := p.parsePredicates()
+	step.preds = preds
+	return step, err
+}
+
+func (p *xparser) parsePredicates() ([]xexpr, error) {
+	var preds []xexpr
+	for p.peek().kind == "[" {
+		p.pos++
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	return preds, nil
+}
+
+func (p *xparser) parseCall() (xexpr, error) {
+	call := xcall{name: p.next().text}
+	p.pos++ // (
+	if p.peek().kind != ")" {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.peek().kind != "," {
+				break
+			}
+			p.pos++
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	want, ok := xpathFunctions[call.name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %s()", call.name)
+	}
+	if len(call.args) < want[0] || len(call.args) > want[1] {
+		return nil, fmt.Errorf("%s() takes %d argument(s), got %d", call.name, want[0], len(call.args))
+	}
+	return call, nil
+}
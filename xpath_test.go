@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadUIDump unmarshals a uiautomator dump and picks the root the way dumpUIHierarchy does
+func loadUIDump(t *testing.T, content string) *UINode {
+	t.Helper()
+	var h UIHierarchy
+	if err := xml.Unmarshal([]byte(content), &h); err != nil {
+		t.Fatalf("parsing UI dump: %v", err)
+	}
+	if len(h.Nodes) == 1 {
+		return &h.Nodes[0]
+	}
+	return &UINode{
+		Class:   "android.view.View",
+		Text:    syntheticRootText,
+		Package: h.Nodes[0].Package,
+		Bounds:  "[0,0][0,0]",
+		Nodes:   h.Nodes,
+	}
+}
+
+// resultLabel names a match by its text, else content-desc, else resource-id without the package
+func resultLabel(r SearchResult) string {
+	switch {
+	case r.Node.Text != "":
+		return r.Node.Text
+	case r.Node.ContentDesc != "":
+		return r.Node.ContentDesc
+	}
+	id := r.Node.ResourceID
+	if i := strings.Index(id, ":id/"); i != -1 {
+		id = id[i+len(":id/"):]
+	}
+	return id
+}
+
+const cartScreen = "/hierarchy/android.widget.FrameLayout/android.widget.LinearLayout/android.widget.FrameLayout/android.view.ViewGroup"
+
+func TestEvalXPath(t *testing.T) {
+	root := loadUIDump(t, readTestdata(t, "ui_dump.xml"))
+	a := &App{}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		// Node tests
+		{`//android.widget.Button`, []string{"Remove", "Remove", "Remove", "Checkout"}},
+		{`//Button`, []string{"Remove", "Remove", "Remove", "Checkout"}},
+		{`//node[@resource-id='com.example.shop:id/search']`, []string{"Search"}},
+		{`/hierarchy/android.widget.FrameLayout/android.widget.LinearLayout/android.widget.FrameLayout/android.view.ViewGroup/android.widget.TextView`, []string{"Cart"}},
+		{`/hierarchy/*/*/*/*/*[@scrollable='true']`, []string{"list"}},
+
+		// Attribute predicates
+		{`//*[@resource-id='com.example.shop:id/checkout']`, []string{"Checkout"}},
+		{`//android.widget.TextView[@text="Green tea"]`, []string{"Green tea"}},
+		{`//*[contains(@text,'tea')]`, []string{"Green tea"}},
+		{`//*[starts-with(@text,'$')]`, []string{"$12.00", "$8.50", "$3.20"}},
+		{`//*[@checkable='true']`, []string{"Gift wrap"}},
+		{`//*[@content-desc]`, []string{"Search"}},
+		{`//android.widget.EditText[@text]`, nil},
+		{`//android.widget.EditText[not(@text)]`, []string{"coupon"}},
+		{`//android.widget.Button[not(@enabled='false')]`, []string{"Remove", "Remove", "Checkout"}},
+		{`//*[@text='Checkout' or @text='Cart']`, []string{"Cart", "Checkout"}},
+		{`//*[@clickable='true' and @resource-id='com.example.shop:id/row']`, []string{"row", "row", "row"}},
+
+		// Indexes and positions
+		{`(//android.widget.Button)[2]`, []string{"Remove"}},
+		{`(//android.widget.Button)[last()]`, []string{"Checkout"}},
+		{`//android.widget.TextView[2]`, []string{"$12.00", "$8.50", "$3.20"}},
+		{`//android.widget.TextView[position()=1]`, []string{"Cart", "Coffee beans", "Green tea", "Oat milk"}},
+		{`//*[@resource-id='com.example.shop:id/row'][3]/*[2]`, []string{"$3.20"}},
+		{`//*[@index='2']`, []string{"list", "Remove", "Remove", "row", "Remove"}},
+		{`(//*[@resource-id='com.example.shop:id/item_name'])[position()>1]`, []string{"Green tea", "Oat milk"}},
+
+		// Nested paths, axes and unions
+		{`//androidx.recyclerview.widget.RecyclerView/android.widget.LinearLayout[2]/android.widget.TextView[1]`, []string{"Green tea"}},
+		{`//android.widget.LinearLayout[android.widget.TextView[@text='Oat milk']]/android.widget.Button`, []string{"Remove"}},
+		{`//*[@text='Oat milk']/following-sibling::android.widget.Button`, []string{"Remove"}},
+		{`//*[@text='$8.50']/preceding-sibling::*`, []string{"Green tea"}},
+		{`//*[@text='Gift wrap']/ancestor::*[@resource-id='com.example.shop:id/root']`, []string{"root"}},
+		{`//*[count(android.widget.TextView)=2]`, []string{"row", "row", "row"}},
+		{`//*[@resource-id='com.example.shop:id/list']//android.widget.Button[@enabled='false']/../*[1]`, []string{"Oat milk"}},
+		{`//*[@resource-id='com.example.shop:id/checkout'] | //*[@resource-id='com.example.shop:id/title']`, []string{"Cart", "Checkout"}},
+	}
+
+	for _, tt := range tests {
+		results, err := a.evalXPath(root, tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, resultLabel(r))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s\n got  %q\n want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvalXPathPaths(t *testing.T) {
+	root := loadUIDump(t, readTestdata(t, "ui_dump.xml"))
+	a := &App{}
+
+	tests := []struct {
+		expr  string
+		path  string
+		depth int
+		index int
+	}{
+		{`//*[@text='Cart']`, cartScreen + "/android.widget.TextView", 4, 0},
+		{`(//android.widget.Button)[2]`, cartScreen + "/androidx.recyclerview.widget.RecyclerView/android.widget.LinearLayout[2]/android.widget.Button", 6, 2},
+		{`//*[@text='Checkout']`, cartScreen + "/android.widget.Button", 4, 5},
+		{`//*[@text='$3.20']`, cartScreen + "/androidx.recyclerview.widget.RecyclerView/android.widget.LinearLayout[3]/android.widget.TextView[2]", 6, 1},
+	}
+	for _, tt := range tests {
+		results, err := a.evalXPath(root, tt.expr)
+		if err != nil || len(results) != 1 {
+			t.Errorf("%s: got %d results, err %v", tt.expr, len(results), err)
+			continue
+		}
+		r := results[0]
+		if r.Path != tt.path || r.Depth != tt.depth || r.Index != tt.index {
+			t.Errorf("%s: got %s depth %d index %d, want %s depth %d index %d", tt.expr, r.Path, r.Depth, r.Index, tt.path, tt.depth, tt.index)
+		}
+		// The computed path selects the same element again
+		again, err := a.evalXPath(root, r.Path)
+		if err != nil || len(again) != 1 || again[0].Node != r.Node {
+			t.Errorf("%s: path %s does not round-trip", tt.expr, r.Path)
+		}
+	}
+}
+
+func TestEvalXPathSeveralWindows(t *testing.T) {
+	dump := `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<hierarchy rotation="0">
+  <node index="0" text="" resource-id="" class="android.widget.FrameLayout" package="com.example.shop" content-desc="" bounds="[0,0][1080,2340]">
+    <node index="0" text="Cart" resource-id="com.example.shop:id/title" class="android.widget.TextView" package="com.example.shop" content-desc="" bounds="[48,120][600,210]" />
+  </node>
+  <node index="1" text="" resource-id="" class="android.widget.FrameLayout" package="com.google.android.inputmethod.latin" content-desc="" bounds="[0,1500][1080,2340]">
+    <node index="0" text="" resource-id="com.google.android.inputmethod.latin:id/key_pos_0_0" class="android.widget.FrameLayout" package="com.google.android.inputmethod.latin" content-desc="q" bounds="[0,1600][108,1750]" />
+  </node>
+</hierarchy>`
+	root := loadUIDump(t, dump)
+	if !isSyntheticRoot(root) {
+		t.Fatal("two windows should be wrapped in the synthetic root")
+	}
+
+	results, err := (&App{}).evalXPath(root, `//*[@content-desc='q']`)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("got %d results, err %v", len(results), err)
+	}
+	if want := "/hierarchy/android.widget.FrameLayout[2]/android.widget.FrameLayout"; results[0].Path != want {
+		t.Errorf("path %s, want %s", results[0].Path, want)
+	}
+	// The wrapper is not part of the document
+	if got, _ := (&App{}).evalXPath(root, `//android.view.View`); len(got) != 0 {
+		t.Errorf("synthetic root was selectable: %+v", got)
+	}
+	if got, _ := (&App{}).evalXPath(root, `/hierarchy/*`); len(got) != 2 {
+		t.Errorf("/hierarchy/* selected %d windows, want 2", len(got))
+	}
+}
+
+func TestEvalXPathErrors(t *testing.T) {
+	root := loadUIDump(t, readTestdata(t, "ui_dump.xml"))
+	for _, expr := range []string{
+		`//android.widget.Button[`,
+		`//*[@text='Cart'`,
+		`//*/@text`,
+		`count(//android.widget.Button)`,
+		``,
+	} {
+		if _, err := (&App{}).evalXPath(root, expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+		if got := (&App{}).SearchElementsXPath(root, expr); len(got) != 0 {
+			t.Errorf("%q: SearchElementsXPath returned %d results", expr, len(got))
+		}
+	}
+}